@@ -434,9 +434,10 @@ const (
 // tableToAvroSchema converts a column descriptor into its corresponding avro
 // record schema. The fields are kept in the same order as `tableDesc.Columns`.
 // If a name suffix is provided (as opposed to avroSchemaNoSuffix), it will be
-// appended to the end of the avro record's name.
+// appended to the end of the avro record's name. Columns not included in the
+// projection are left out.
 func tableToAvroSchema(
-	tableDesc catalog.TableDescriptor, nameSuffix string,
+	tableDesc catalog.TableDescriptor, nameSuffix string, columns columnProjection,
 ) (*avroDataRecord, error) {
 	name := SQLNameToAvroName(tableDesc.GetName())
 	if nameSuffix != avroSchemaNoSuffix {
@@ -452,6 +453,9 @@ func tableToAvroSchema(
 	}
 	for colIdx := range tableDesc.GetPublicColumns() {
 		col := tableDesc.GetColumnAtIdx(colIdx)
+		if !columns.includes(col.Name) {
+			continue
+		}
 		field, err := columnDescToAvroSchema(col)
 		if err != nil {
 			return nil, err
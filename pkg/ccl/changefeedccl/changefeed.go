@@ -62,10 +62,11 @@ func kvsToRows(
 	leaseMgr *lease.Manager,
 	hydratedTables *hydratedtables.Cache,
 	details jobspb.ChangefeedDetails,
+	filter *rowFilter,
 	inputFn func(context.Context) (kvfeed.Event, error),
 ) func(context.Context) ([]emitEntry, error) {
 	_, withDiff := details.Opts[changefeedbase.OptDiff]
-	rfCache := newRowFetcherCache(ctx, codec, settings, leaseMgr, hydratedTables, db)
+	rfCache := newRowFetcherCache(ctx, codec, settings, leaseMgr, hydratedTables, db, filter)
 
 	var kvs row.SpanKVFetcher
 	appendEmitEntryForKV := func(
@@ -90,7 +91,7 @@ func kvsToRows(
 			return nil, nil
 		}
 
-		rf, err := rfCache.RowFetcherForTableDesc(desc)
+		rf, err := rfCache.RowFetcherForTableDesc(ctx, desc)
 		if err != nil {
 			return nil, err
 		}
@@ -142,7 +143,7 @@ func kvsToRows(
 					return nil, err
 				}
 
-				prevRF, err = rfCache.RowFetcherForTableDesc(prevDesc)
+				prevRF, err = rfCache.RowFetcherForTableDesc(ctx, prevDesc)
 				if err != nil {
 					return nil, err
 				}
@@ -178,6 +179,28 @@ func kvsToRows(
 			}
 		}
 
+		if filter != nil {
+			// Emit the change if the row matches the filter now. With the diff
+			// option, also emit it if the row matched before the change, so that
+			// consumers can tell when a row stops matching.
+			matches, err := filter.matches(ctx, r.row.tableDesc, r.row.datums, r.row.deleted, &rfCache.a)
+			if err != nil {
+				return nil, err
+			}
+			if !matches && withDiff && !r.row.prevDeleted {
+				matches, err = filter.matches(ctx, r.row.prevTableDesc, r.row.prevDatums, false /* deleted */, &rfCache.a)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !matches {
+				if log.V(3) {
+					log.Infof(ctx, `skipping key not matching filter %s: %s`, desc.Name, kv.Key)
+				}
+				return nil, nil
+			}
+		}
+
 		output = append(output, r)
 		return output, nil
 	}
@@ -431,3 +454,9 @@ func initialScanFromOptions(opts map[string]string) bool {
 	_, noInitialScan := opts[changefeedbase.OptNoInitialScan]
 	return (cursor && initialScan) || (!cursor && !noInitialScan)
 }
+
+// initialScanOnlyFromOptions returns whether or not the options indicate that
+// the changefeed should stop once its initial scan is done.
+func initialScanOnlyFromOptions(opts map[string]string) bool {
+	return opts[changefeedbase.OptInitialScan] == changefeedbase.OptInitialScanOnly
+}
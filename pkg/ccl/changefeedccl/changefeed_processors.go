@@ -58,6 +58,8 @@ type changeAggregator struct {
 
 	// encoder is the Encoder to use for key and value serialization.
 	encoder Encoder
+	// filter, if non-nil, implements the `columns` and `filter` options.
+	filter *rowFilter
 	// sink is the Sink to write rows to. Resolved timestamps are never written
 	// by changeAggregator.
 	sink Sink
@@ -142,6 +144,9 @@ func newChangeAggregatorProcessor(
 	if ca.encoder, err = getEncoder(ca.spec.Feed.Opts); err != nil {
 		return nil, err
 	}
+	if ca.filter, err = makeRowFilter(ca.spec.Feed.Opts, flowCtx.NewEvalCtx()); err != nil {
+		return nil, err
+	}
 
 	return ca, nil
 }
@@ -159,7 +164,8 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	var err error
 	ca.sink, err = getSink(
 		ctx, ca.spec.Feed.SinkURI, ca.flowCtx.EvalCtx.NodeID.SQLInstanceID(), ca.spec.Feed.Opts, ca.spec.Feed.Targets,
-		ca.flowCtx.Cfg.Settings, timestampOracle, ca.flowCtx.Cfg.ExternalStorageFromURI, ca.spec.User(),
+		ca.flowCtx.Cfg.Settings, ca.flowCtx.Cfg.ExternalIODirConfig, timestampOracle,
+		ca.flowCtx.Cfg.ExternalStorageFromURI, ca.spec.User(),
 	)
 	if err != nil {
 		err = MarkRetryableError(err)
@@ -205,7 +211,7 @@ func (ca *changeAggregator) Start(ctx context.Context) context.Context {
 	kvfeedCfg := makeKVFeedCfg(ca.flowCtx.Cfg, leaseMgr, ca.kvFeedMemMon, ca.spec,
 		spans, withDiff, buf, metrics)
 	cfg := ca.flowCtx.Cfg
-	rowsFn := kvsToRows(ctx, cfg.Codec, cfg.Settings, cfg.DB, leaseMgr, cfg.HydratedTables, ca.spec.Feed, buf.Get)
+	rowsFn := kvsToRows(ctx, cfg.Codec, cfg.Settings, cfg.DB, leaseMgr, cfg.HydratedTables, ca.spec.Feed, ca.filter, buf.Get)
 	ca.tickFn = emitEntries(ca.flowCtx.Cfg.Settings, ca.spec.Feed,
 		kvfeedCfg.InitialHighWater, sf, ca.encoder, ca.sink, rowsFn, knobs, metrics)
 	ca.startKVFeed(ctx, kvfeedCfg)
@@ -258,12 +264,14 @@ func makeKVFeedCfg(
 		Gossip:             cfg.Gossip,
 		Spans:              spans,
 		Targets:            spec.Feed.Targets,
+		Opts:               spec.Feed.Opts,
 		LeaseMgr:           leaseMgr,
 		Metrics:            &metrics.KVFeedMetrics,
 		MM:                 mm,
 		InitialHighWater:   initialHighWater,
 		WithDiff:           withDiff,
 		NeedsInitialScan:   needsInitialScan,
+		InitialScanOnly:    initialScanOnlyFromOptions(spec.Feed.Opts),
 		SchemaChangeEvents: schemaChangeEvents,
 		SchemaChangePolicy: schemaChangePolicy,
 	}
@@ -541,7 +549,8 @@ func (cf *changeFrontier) Start(ctx context.Context) context.Context {
 	var err error
 	cf.sink, err = getSink(
 		ctx, cf.spec.Feed.SinkURI, cf.flowCtx.EvalCtx.NodeID.SQLInstanceID(), cf.spec.Feed.Opts, cf.spec.Feed.Targets,
-		cf.flowCtx.Cfg.Settings, nilOracle, cf.flowCtx.Cfg.ExternalStorageFromURI, cf.spec.User(),
+		cf.flowCtx.Cfg.Settings, cf.flowCtx.Cfg.ExternalIODirConfig, nilOracle,
+		cf.flowCtx.Cfg.ExternalStorageFromURI, cf.spec.User(),
 	)
 	if err != nil {
 		err = MarkRetryableError(err)
@@ -660,6 +669,12 @@ func (cf *changeFrontier) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetad
 				"schema change occurred at %v", cf.schemaChangeBoundary.Next().AsOfSystemTime()))
 			break
 		}
+		if cf.schemaChangeBoundaryReached() && initialScanOnlyFromOptions(cf.spec.Feed.Opts) {
+			// The only boundary of such a changefeed is the end of its initial
+			// scan, after which it's done.
+			cf.MoveToDraining(nil /* err */)
+			break
+		}
 
 		row, meta := cf.input.Next()
 		if meta != nil {
@@ -836,6 +851,8 @@ func (cf *changeFrontier) maybeReleaseProtectedTimestamp(
 // maybeProtectTimestamp creates a new protected timestamp when the
 // changeFrontier reaches a scanBoundary and the schemaChangePolicy indicates
 // that we should perform a backfill (see cf.shouldProtectBoundaries()).
+// Changefeeds with initial_scan='only' finish at the first boundary instead,
+// so they never protect it.
 func (cf *changeFrontier) maybeProtectTimestamp(
 	ctx context.Context,
 	progress *jobspb.ChangefeedProgress,
@@ -843,7 +860,8 @@ func (cf *changeFrontier) maybeProtectTimestamp(
 	txn *kv.Txn,
 	resolved hlc.Timestamp,
 ) error {
-	if cf.isSinkless() || !cf.schemaChangeBoundaryReached() || !cf.shouldProtectBoundaries() {
+	if cf.isSinkless() || !cf.schemaChangeBoundaryReached() || !cf.shouldProtectBoundaries() ||
+		initialScanOnlyFromOptions(cf.spec.Feed.Opts) {
 		return nil
 	}
 
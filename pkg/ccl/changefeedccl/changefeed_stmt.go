@@ -30,6 +30,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -43,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -74,8 +77,8 @@ func init() {
 func changefeedPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
 ) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
-	changefeedStmt, ok := stmt.(*tree.CreateChangefeed)
-	if !ok {
+	changefeedStmt := getChangefeedStatement(stmt)
+	if changefeedStmt == nil {
 		return nil, nil, nil, false, nil
 	}
 
@@ -150,7 +153,7 @@ func changefeedPlanHook(
 			return err
 		}
 
-		jobDescription, err := changefeedJobDescription(p, changefeedStmt, sinkURI, opts)
+		jobDescription, err := changefeedJobDescription(p, changefeedStmt.CreateChangefeed, sinkURI, opts)
 		if err != nil {
 			return err
 		}
@@ -197,6 +200,7 @@ func changefeedPlanHook(
 			}
 		}
 		targets := make(jobspb.ChangefeedTargets, len(targetDescs))
+		var tables []catalog.TableDescriptor
 		for _, desc := range targetDescs {
 			if table, isTable := desc.(catalog.TableDescriptor); isTable {
 				targets[table.GetID()] = jobspb.ChangefeedTarget{
@@ -205,8 +209,14 @@ func changefeedPlanHook(
 				if err := validateChangefeedTable(targets, table); err != nil {
 					return err
 				}
+				tables = append(tables, table)
 			}
 		}
+		if err := validateRowFilter(
+			ctx, &p.ExtendedEvalContext().EvalContext, opts, tables,
+		); err != nil {
+			return err
+		}
 
 		details := jobspb.ChangefeedDetails{
 			Targets:       targets,
@@ -229,14 +239,16 @@ func changefeedPlanHook(
 		//   and `format` if the user didn't specify them.
 		// - Then `getEncoder` is run to return any configuration errors.
 		// - Then the changefeed is opted in to `OptKeyInValue` for any cloud
-		//   storage sink. Kafka etc have a key and value field in each message but
-		//   cloud storage sinks don't have anywhere to put the key. So if the key
-		//   is not in the value, then for DELETEs there is no way to recover which
-		//   key was deleted. We could make the user explicitly pass this option for
+		//   storage sink, except with format=csv, which has no key. Kafka etc
+		//   have a key and value field in each message but cloud storage sinks
+		//   don't have anywhere to put the key. So if the key is not in the
+		//   value, then for DELETEs there is no way to recover which key was
+		//   deleted. We could make the user explicitly pass this option for
 		//   every cloud storage sink and error if they don't, but that seems
 		//   user-hostile for insufficient reason. We can't do this any earlier,
-		//   because we might return errors about `key_in_value` being incompatible
-		//   which is confusing when the user didn't type that option.
+		//   because we might return errors about `key_in_value` being
+		//   incompatible which is confusing when the user didn't type that
+		//   option.
 		// - Finally, we create a "canary" sink to test sink configuration and
 		//   connectivity. This has to go last because it is strange to return sink
 		//   connectivity errors before we've finished validating all the other
@@ -257,7 +269,12 @@ func changefeedPlanHook(
 		if _, err := getEncoder(details.Opts); err != nil {
 			return err
 		}
-		if isCloudStorageSink(parsedSink) {
+		isCSV := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatCSV
+		if isCSV && !isCloudStorageSink(parsedSink) {
+			return errors.Errorf(`%s=%s is only supported with cloud storage sinks`,
+				changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+		}
+		if (isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink)) && !isCSV {
 			details.Opts[changefeedbase.OptKeyInValue] = ``
 		}
 
@@ -303,7 +320,8 @@ func changefeedPlanHook(
 			var nilOracle timestampLowerBoundOracle
 			canarySink, err := getSink(
 				ctx, details.SinkURI, p.ExecCfg().NodeID.SQLInstanceID(), details.Opts, details.Targets,
-				settings, nilOracle, p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User(),
+				settings, p.ExecCfg().ExternalIODirConfig, nilOracle,
+				p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User(),
 			)
 			if err != nil {
 				return MaybeStripRetryableErrorMarker(err)
@@ -345,9 +363,34 @@ func changefeedPlanHook(
 					}
 					return sqlDescIDs
 				}(),
-				Details:  details,
-				Progress: *progress.GetChangefeed(),
+				Details:   details,
+				Progress:  *progress.GetChangefeed(),
+				CreatedBy: changefeedStmt.CreatedByInfo,
 			}
+
+			if changefeedStmt.CreatedByInfo != nil {
+				// The changefeed was started by a schedule. We simply create the job
+				// record, along with its protected timestamp, in the scheduler's
+				// transaction; the job is adopted and started once that transaction
+				// commits. We do not wait for the job to start.
+				txn := p.ExtendedEvalContext().Txn
+				aj, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, txn)
+				if err != nil {
+					return err
+				}
+				if protectedTimestampID != uuid.Nil {
+					ptr := jobsprotectedts.MakeRecord(protectedTimestampID, *aj.ID(),
+						statementTime, spansToProtect)
+					if err := p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, txn, ptr); err != nil {
+						return err
+					}
+				}
+				resultsCh <- tree.Datums{
+					tree.NewDInt(tree.DInt(*aj.ID())),
+				}
+				return nil
+			}
+
 			createJobAndProtectedTS := func(ctx context.Context, txn *kv.Txn) (err error) {
 				sj, err = p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(ctx, jr, txn, startedCh)
 				if err != nil {
@@ -404,6 +447,24 @@ func changefeedPlanHook(
 	return fn, header, nil, avoidBuffering, nil
 }
 
+// annotatedChangefeedStatement is a tree.CreateChangefeed, optionally
+// annotated with the scheduling information.
+type annotatedChangefeedStatement struct {
+	*tree.CreateChangefeed
+	*jobs.CreatedByInfo
+}
+
+func getChangefeedStatement(stmt tree.Statement) *annotatedChangefeedStatement {
+	switch changefeed := stmt.(type) {
+	case *annotatedChangefeedStatement:
+		return changefeed
+	case *tree.CreateChangefeed:
+		return &annotatedChangefeedStatement{CreateChangefeed: changefeed}
+	default:
+		return nil
+	}
+}
+
 func changefeedJobDescription(
 	p sql.PlanHookState, changefeed *tree.CreateChangefeed, sinkURI string, opts map[string]string,
 ) (string, error) {
@@ -472,13 +533,19 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		}
 	}
 	{
-		_, withInitialScan := details.Opts[changefeedbase.OptInitialScan]
+		initialScan, withInitialScan := details.Opts[changefeedbase.OptInitialScan]
 		_, noInitialScan := details.Opts[changefeedbase.OptNoInitialScan]
 		if withInitialScan && noInitialScan {
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`cannot specify both %s and %s`, changefeedbase.OptInitialScan,
 				changefeedbase.OptNoInitialScan)
 		}
+		switch initialScan {
+		case ``, changefeedbase.OptInitialScanOnly:
+		default:
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`unknown %s: %s`, changefeedbase.OptInitialScan, initialScan)
+		}
 	}
 	{
 		const opt = changefeedbase.OptEnvelope
@@ -487,7 +554,14 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeRow)
 		case changefeedbase.OptEnvelopeKeyOnly:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeKeyOnly)
-		case ``, changefeedbase.OptEnvelopeWrapped:
+		case ``:
+			// CSV has nowhere to put the wrapper, so it defaults to the bare row.
+			if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatCSV {
+				details.Opts[opt] = string(changefeedbase.OptEnvelopeRow)
+			} else {
+				details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
+			}
+		case changefeedbase.OptEnvelopeWrapped:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
@@ -499,7 +573,7 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.OptFormatProtobuf, changefeedbase.OptFormatCSV:
 			// No-op.
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
@@ -555,6 +629,37 @@ func validateChangefeedTable(
 	return nil
 }
 
+// validateRowFilter checks the `columns` and `filter` options against the
+// table watched by the changefeed. Both options are only supported on
+// changefeeds with a single target, since column names are table-specific.
+func validateRowFilter(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	opts map[string]string,
+	tables []catalog.TableDescriptor,
+) error {
+	_, hasColumns := opts[changefeedbase.OptColumns]
+	_, hasFilter := opts[changefeedbase.OptFilter]
+	if !hasColumns && !hasFilter {
+		return nil
+	}
+	if len(tables) != 1 {
+		return errors.Errorf(`%s and %s are only supported on CHANGEFEEDs with a single target table`,
+			changefeedbase.OptColumns, changefeedbase.OptFilter)
+	}
+	if err := changefeedbase.ValidateProjection(opts, tables[0]); err != nil {
+		return err
+	}
+	// Compile the filter to report type errors and disallowed functions now
+	// rather than when the first row arrives.
+	filter, err := makeRowFilter(opts, evalCtx)
+	if err != nil {
+		return err
+	}
+	_, err = filter.compile(ctx, tables[0])
+	return err
+}
+
 type changefeedResumer struct {
 	job *jobs.Job
 }
@@ -609,6 +714,18 @@ func (b *changefeedResumer) Resume(
 	var err error
 	for r := retry.StartWithCtx(ctx, opts); r.Next(); {
 		if err = distChangefeedFlow(ctx, jobExec, jobID, details, progress, startedCh); err == nil {
+			// The changefeed finished, which only happens with initial_scan='only'.
+			// Release the protected timestamp which guarded the initial scan, if it
+			// is still around. The record is read from the latest progress, which
+			// the changeFrontier may have updated since the job was loaded.
+			if reloadedJob, reloadErr := execCfg.JobRegistry.LoadJob(ctx, jobID); reloadErr != nil {
+				log.Warningf(ctx, `CHANGEFEED job %d could not reload job progress: %v`, jobID, reloadErr)
+			} else {
+				reloadedProgress := reloadedJob.Progress()
+				b.maybeCleanUpProtectedTimestamp(ctx, execCfg.DB, execCfg.ProtectedTimestampProvider,
+					reloadedProgress.GetChangefeed().ProtectedTimestampRecord)
+			}
+			b.maybeNotifyScheduledJobCompletion(ctx, jobs.StatusSucceeded, execCfg)
 			return nil
 		}
 		if !IsRetryableError(err) {
@@ -662,6 +779,7 @@ func (b *changefeedResumer) Resume(
 func (b *changefeedResumer) OnFailOrCancel(ctx context.Context, jobExec interface{}) error {
 	exec := jobExec.(sql.JobExecContext)
 	execCfg := exec.ExecCfg()
+	defer b.maybeNotifyScheduledJobCompletion(ctx, jobs.StatusFailed, execCfg)
 	progress := b.job.Progress()
 	b.maybeCleanUpProtectedTimestamp(ctx, execCfg.DB, execCfg.ProtectedTimestampProvider,
 		progress.GetChangefeed().ProtectedTimestampRecord)
@@ -678,6 +796,48 @@ func (b *changefeedResumer) OnFailOrCancel(ctx context.Context, jobExec interfac
 	return nil
 }
 
+// maybeNotifyScheduledJobCompletion notifies the schedule which created the
+// changefeed, if any, that the changefeed terminated. Only changefeeds with
+// initial_scan='only', which scheduled changefeeds always are, terminate
+// successfully.
+func (b *changefeedResumer) maybeNotifyScheduledJobCompletion(
+	ctx context.Context, jobStatus jobs.Status, exec *sql.ExecutorConfig,
+) {
+	env := schedulebase.JobSchedulerEnv(exec.DistSQLSrv.TestingKnobs.JobsTestingKnobs)
+
+	if err := exec.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		// Do not rely on b.job containing created_by_id.  Query it directly.
+		datums, err := exec.InternalExecutor.QueryRowEx(
+			ctx,
+			"lookup-schedule-info",
+			txn,
+			sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+			fmt.Sprintf(
+				"SELECT created_by_id FROM %s WHERE id=$1 AND created_by_type=$2",
+				env.SystemJobsTableName()),
+			*b.job.ID(), jobs.CreatedByScheduledJobs)
+
+		if err != nil {
+			return errors.Wrap(err, "schedule info lookup")
+		}
+		if datums == nil {
+			// Not a scheduled changefeed.
+			return nil
+		}
+
+		scheduleID := int64(tree.MustBeDInt(datums[0]))
+		if err := jobs.NotifyJobTermination(
+			ctx, env, *b.job.ID(), jobStatus, b.job.Details(), scheduleID, exec.InternalExecutor, txn); err != nil {
+			log.Warningf(ctx,
+				"failed to notify schedule %d of completion of job %d; err=%s",
+				scheduleID, *b.job.ID(), err)
+		}
+		return nil
+	}); err != nil {
+		log.Errorf(ctx, "maybeNotifySchedule error: %v", err)
+	}
+}
+
 // Try to clean up a protected timestamp created by the changefeed.
 func (b *changefeedResumer) maybeCleanUpProtectedTimestamp(
 	ctx context.Context, db *kv.DB, pts protectedts.Storage, ptsID uuid.UUID,
@@ -155,6 +155,124 @@ func TestChangefeedDiff(t *testing.T) {
 	t.Run(`cloudstorage`, cloudStorageTest(testFn))
 }
 
+func TestChangefeedColumnsAndFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'skipped', 0), (1, 'initial', 10)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH columns='a, b', filter='c > 5'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "initial"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'b', 1), (3, 'c', 100)`)
+		sqlDB.Exec(t, `UPDATE foo SET c = 20 WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+			`foo: [0]->{"after": {"a": 0, "b": "skipped"}}`,
+		})
+
+		// Deletions can't be evaluated against a filter on non-primary key
+		// columns, so they're always emitted.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": null}`,
+		})
+
+		// A filter on the primary key applies to deletions too.
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
+		bar := feed(t, f, `CREATE CHANGEFEED FOR bar WITH filter='a % 2 = 0'`)
+		defer closeFeed(t, bar)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1, 'a'), (2, 'b')`)
+		sqlDB.Exec(t, `DELETE FROM bar WHERE true`)
+		assertPayloads(t, bar, []string{
+			`bar: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`bar: [2]->{"after": null}`,
+		})
+
+		// Dropping a column the changefeed depends on is an error.
+		sqlDB.Exec(t, `ALTER TABLE foo DROP COLUMN c`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'd')`)
+		if _, err := foo.Next(); !testutils.IsError(err, `filter option of CHANGEFEED on foo: column "c" does not exist`) {
+			t.Errorf(`expected "column "c" does not exist" error got: %+v`, err)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+}
+
+func TestChangefeedInitialScanOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan='only'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+
+		// The job succeeds once the initial scan is done, without emitting any
+		// later changes.
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'c')`)
+		sqlDB.CheckQueryResultsRetry(t,
+			`SELECT status FROM [SHOW JOBS] WHERE job_type = 'CHANGEFEED'`,
+			[][]string{{string(jobs.StatusSucceeded)}},
+		)
+		// No protected timestamp is left behind to hold up GC.
+		sqlDB.CheckQueryResults(t,
+			`SELECT count(*) FROM system.protected_ts_records`, [][]string{{`0`}},
+		)
+
+		sqlDB.ExpectErr(t, `unknown initial_scan: sometimes`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH initial_scan='sometimes'`)
+		sqlDB.ExpectErr(t, `cannot specify both initial_scan and no_initial_scan`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH initial_scan='only', no_initial_scan`)
+	}
+
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`cloudstorage`, cloudStorageTest(testFn))
+}
+
+func TestChangefeedColumnsAndFilterErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+
+	for _, tc := range []struct {
+		opts string
+		err  string
+	}{
+		{`columns='a, nope'`, `columns option of CHANGEFEED on foo: column "nope" does not exist`},
+		{`columns='a.b'`, `expected a comma-separated list of column names`},
+		{`filter='nope = 1'`, `filter option of CHANGEFEED on foo: column "nope" does not exist`},
+		{`filter='b'`, `expected CHANGEFEED filter expression to have type bool`},
+		{`filter='a > random()'`, `volatile functions are not allowed in CHANGEFEED filter`},
+		{`filter='b = (SELECT b FROM foo LIMIT 1)'`, `subqueries are not allowed in CHANGEFEED filter`},
+	} {
+		sqlDB.ExpectErr(t, tc.err,
+			`EXPERIMENTAL CHANGEFEED FOR foo WITH `+tc.opts)
+	}
+	sqlDB.ExpectErr(t, `columns and filter are only supported on CHANGEFEEDs with a single target table`,
+		`EXPERIMENTAL CHANGEFEED FOR foo, bar WITH filter='a > 1'`)
+}
+
 func TestChangefeedEnvelope(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
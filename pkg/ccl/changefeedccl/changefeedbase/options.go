@@ -38,6 +38,9 @@ const (
 	OptSchemaChangeEvents       = `schema_change_events`
 	OptSchemaChangePolicy       = `schema_change_policy`
 	OptProtectDataFromGCOnPause = `protect_data_from_gc_on_pause`
+	OptColumns                  = `columns`
+	OptFilter                   = `filter`
+	OptCSVSkipDeletes           = `csv_skip_deletes`
 
 	// OptSchemaChangeEventClassColumnChange corresponds to all schema change
 	// events which add or remove any column.
@@ -61,8 +64,14 @@ const (
 	// OptInitialScan enables an initial scan. This is the default when no
 	// cursor is specified, leading to an initial scan at the statement time of
 	// the creation of the changeffed. If used in conjunction with a cursor,
-	// an initial scan will be performed at the cursor timestamp.
+	// an initial scan will be performed at the cursor timestamp. Its value can
+	// optionally be OptInitialScanOnly.
 	OptInitialScan = `initial_scan`
+	// OptInitialScanOnly is the value of OptInitialScan which makes the
+	// changefeed exit successfully once the initial scan is done, instead of
+	// going on to emit changes. It makes a changefeed usable as a one-off
+	// export of the targeted tables.
+	OptInitialScanOnly = `only`
 	// OptInitialScan enables an initial scan. This is the default when a
 	// cursor is specified. This option is useful to create a changefeed which
 	// subscribes only to new messages.
@@ -73,8 +82,10 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `experimental_avro`
+	OptFormatJSON     FormatType = `json`
+	OptFormatAvro     FormatType = `experimental_avro`
+	OptFormatProtobuf FormatType = `protobuf`
+	OptFormatCSV      FormatType = `csv`
 
 	SinkParamCACert           = `ca_cert`
 	SinkParamClientCert       = `client_cert`
@@ -91,6 +102,15 @@ const (
 	SinkParamSASLHandshake    = `sasl_handshake`
 	SinkParamSASLUser         = `sasl_user`
 	SinkParamSASLPassword     = `sasl_password`
+
+	SinkSchemeWebhookHTTPS = `webhook-https`
+	SinkParamBatchSize     = `batch_size`
+	SinkParamFlushInterval = `flush_interval`
+	SinkParamClientTimeout = `client_timeout`
+	SinkParamMaxRetries    = `max_retries`
+
+	SinkSchemeGCPubsub = `gcpubsub`
+	SinkParamEndpoint  = `endpoint`
 )
 
 // ChangefeedOptionExpectValues is used to parse changefeed options using
@@ -107,7 +127,10 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptCompression:              sql.KVStringOptRequireValue,
 	OptSchemaChangeEvents:       sql.KVStringOptRequireValue,
 	OptSchemaChangePolicy:       sql.KVStringOptRequireValue,
-	OptInitialScan:              sql.KVStringOptRequireNoValue,
+	OptInitialScan:              sql.KVStringOptAny,
 	OptNoInitialScan:            sql.KVStringOptRequireNoValue,
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
+	OptColumns:                  sql.KVStringOptRequireValue,
+	OptFilter:                   sql.KVStringOptRequireValue,
+	OptCSVSkipDeletes:           sql.KVStringOptRequireNoValue,
 }
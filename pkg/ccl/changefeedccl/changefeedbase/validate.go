@@ -9,10 +9,16 @@
 package changefeedbase
 
 import (
+	"strings"
+
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/errors"
 )
 
@@ -60,3 +66,65 @@ func ValidateTable(targets jobspb.ChangefeedTargets, tableDesc *tabledesc.Immuta
 
 	return nil
 }
+
+// ProjectedColumns returns the names of the columns listed in the `columns`
+// option, or nil if the option wasn't specified. Names are parsed like SQL
+// identifiers, so they are case-folded unless double quoted.
+func ProjectedColumns(opts map[string]string) ([]string, error) {
+	o, ok := opts[OptColumns]
+	if !ok {
+		return nil, nil
+	}
+	var names []string
+	for _, s := range strings.Split(o, `,`) {
+		expr, err := parser.ParseExpr(strings.TrimSpace(s))
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid %s='%s'`, OptColumns, o)
+		}
+		name, ok := expr.(*tree.UnresolvedName)
+		if !ok || name.NumParts != 1 || name.Star {
+			return nil, errors.Errorf(`invalid %s='%s': expected a comma-separated list of column names`,
+				OptColumns, o)
+		}
+		names = append(names, name.Parts[0])
+	}
+	return names, nil
+}
+
+// ValidateProjection validates that every column referenced by the `columns`
+// and `filter` options is a public column of the table. It is checked both when
+// the changefeed is created and for every later version of the table, since a
+// schema change may drop or rename a column the changefeed depends on.
+func ValidateProjection(opts map[string]string, tableDesc catalog.TableDescriptor) error {
+	names, err := ProjectedColumns(opts)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := tableDesc.FindActiveColumnByName(name); err != nil {
+			return errors.Wrapf(err, `%s option of CHANGEFEED on %s`, OptColumns, tableDesc.GetName())
+		}
+	}
+
+	filter, ok := opts[OptFilter]
+	if !ok {
+		return nil
+	}
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return errors.Wrapf(err, `invalid %s='%s'`, OptFilter, filter)
+	}
+	// ExtractColumnIDs also finds columns which are being added or dropped, so
+	// check that each of them is public.
+	colIDs, err := schemaexpr.ExtractColumnIDs(tableDesc, expr)
+	colIDs.ForEach(func(id descpb.ColumnID) {
+		if err != nil {
+			return
+		}
+		var col *descpb.ColumnDescriptor
+		if col, err = tableDesc.FindColumnByID(id); err == nil {
+			_, err = tableDesc.FindActiveColumnByName(col.Name)
+		}
+	})
+	return errors.Wrapf(err, `%s option of CHANGEFEED on %s`, OptFilter, tableDesc.GetName())
+}
@@ -0,0 +1,319 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+const scheduleChangefeedOp = "CREATE SCHEDULE FOR CHANGEFEED"
+
+var scheduledChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
+	schedulebase.OptFirstRun:          sql.KVStringOptRequireValue,
+	schedulebase.OptOnExecFailure:     sql.KVStringOptRequireValue,
+	schedulebase.OptOnPreviousRunning: sql.KVStringOptRequireValue,
+}
+
+// scheduledChangefeedEval is a representation of tree.ScheduledChangefeed,
+// prepared for evaluation.
+type scheduledChangefeedEval struct {
+	*tree.ScheduledChangefeed
+
+	// Schedule specific properties that get evaluated.
+	scheduleLabel func() (string, error)
+	recurrence    func() (string, error)
+	scheduleOpts  func() (map[string]string, error)
+
+	// Changefeed specific properties that get evaluated, so that we store the
+	// evaluated changefeed statement in the schedule.
+	sinkURI        func() (string, error)
+	changefeedOpts func() (map[string]string, error)
+}
+
+// scheduledChangefeedHeader is the header for "CREATE SCHEDULE FOR CHANGEFEED"
+// statement results.
+var scheduledChangefeedHeader = colinfo.ResultColumns{
+	{Name: "schedule_id", Typ: types.Int},
+	{Name: "label", Typ: types.String},
+	{Name: "status", Typ: types.String},
+	{Name: "first_run", Typ: types.TimestampTZ},
+	{Name: "schedule", Typ: types.String},
+	{Name: "changefeed_stmt", Typ: types.String},
+}
+
+func makeScheduledChangefeedEval(
+	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledChangefeed,
+) (*scheduledChangefeedEval, error) {
+	eval := &scheduledChangefeedEval{ScheduledChangefeed: schedule}
+	var err error
+
+	if schedule.ScheduleLabel != nil {
+		eval.scheduleLabel, err = p.TypeAsString(ctx, schedule.ScheduleLabel, scheduleChangefeedOp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if schedule.Recurrence == nil {
+		// Sanity check: recurrence must be specified.
+		return nil, errors.New("RECURRING clause required")
+	}
+
+	eval.recurrence, err = p.TypeAsString(ctx, schedule.Recurrence, scheduleChangefeedOp)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.scheduleOpts, err = p.TypeAsStringOpts(
+		ctx, schedule.ScheduleOptions, scheduledChangefeedOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.CreateChangefeed.SinkURI == nil {
+		return nil, errors.New("scheduled changefeeds require a sink")
+	}
+	eval.sinkURI, err = p.TypeAsString(ctx, schedule.CreateChangefeed.SinkURI, scheduleChangefeedOp)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.changefeedOpts, err = p.TypeAsStringOpts(
+		ctx, schedule.CreateChangefeed.Options, changefeedbase.ChangefeedOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	return eval, nil
+}
+
+// makeScheduledChangefeedNode returns the changefeed statement, with all of
+// its placeholders evaluated, which is run by the schedule, along with its
+// evaluated sink and options. Scheduled changefeeds only ever run an initial
+// scan.
+func makeScheduledChangefeedNode(
+	eval *scheduledChangefeedEval,
+) (*tree.CreateChangefeed, string, map[string]string, error) {
+	sinkURI, err := eval.sinkURI()
+	if err != nil {
+		return nil, "", nil, errors.Wrapf(err, "failed to evaluate changefeed sink")
+	}
+	opts, err := eval.changefeedOpts()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	for _, opt := range []string{changefeedbase.OptNoInitialScan, changefeedbase.OptCursor} {
+		if _, ok := opts[opt]; ok {
+			return nil, "", nil, errors.Errorf("%s is not supported by scheduled changefeeds", opt)
+		}
+	}
+	if v, ok := opts[changefeedbase.OptInitialScan]; ok && v != changefeedbase.OptInitialScanOnly {
+		return nil, "", nil, errors.Errorf("scheduled changefeeds require %s='%s'",
+			changefeedbase.OptInitialScan, changefeedbase.OptInitialScanOnly)
+	}
+	opts[changefeedbase.OptInitialScan] = changefeedbase.OptInitialScanOnly
+
+	node := &tree.CreateChangefeed{
+		Targets: eval.CreateChangefeed.Targets,
+		SinkURI: tree.NewDString(sinkURI),
+	}
+	for k, v := range opts {
+		opt := tree.KVOption{Key: tree.Name(k)}
+		if len(v) > 0 {
+			opt.Value = tree.NewDString(v)
+		}
+		node.Options = append(node.Options, opt)
+	}
+	sort.Slice(node.Options, func(i, j int) bool { return node.Options[i].Key < node.Options[j].Key })
+	return node, sinkURI, opts, nil
+}
+
+// doCreateChangefeedSchedule is a plan hook implementation responsible for the
+// creation of scheduled changefeeds.
+func doCreateChangefeedSchedule(
+	ctx context.Context,
+	p sql.PlanHookState,
+	eval *scheduledChangefeedEval,
+	resultsCh chan<- tree.Datums,
+) error {
+	if err := p.RequireAdminRole(ctx, scheduleChangefeedOp); err != nil {
+		return err
+	}
+	env := schedulebase.JobSchedulerEnv(p.ExecCfg().DistSQLSrv.TestingKnobs.JobsTestingKnobs)
+
+	recurrence, err := schedulebase.ComputeScheduleRecurrence(env.Now(), eval.recurrence)
+	if err != nil {
+		return err
+	}
+
+	changefeedNode, sinkURI, opts, err := makeScheduledChangefeedNode(eval)
+	if err != nil {
+		return err
+	}
+
+	// Run the changefeed planning in dry-run mode. This will do all of the
+	// sanity checks and validation we need to make in order to ensure the
+	// schedule is sane.
+	if err := dryRunChangefeed(ctx, p, changefeedNode); err != nil {
+		return errors.Wrapf(err, "failed to dry run changefeed")
+	}
+
+	var scheduleLabel string
+	if eval.scheduleLabel != nil {
+		scheduleLabel, err = eval.scheduleLabel()
+		if err != nil {
+			return err
+		}
+	} else {
+		scheduleLabel = fmt.Sprintf("CHANGEFEED %d", env.Now().Unix())
+	}
+
+	scheduleOptions, err := eval.scheduleOpts()
+	if err != nil {
+		return err
+	}
+
+	evalCtx := &p.ExtendedEvalContext().EvalContext
+	firstRun, err := schedulebase.ScheduleFirstRun(evalCtx, scheduleOptions)
+	if err != nil {
+		return err
+	}
+
+	details, err := schedulebase.MakeScheduleDetails(scheduleOptions)
+	if err != nil {
+		return err
+	}
+
+	sj, err := makeChangefeedSchedule(
+		env, p.User(), scheduleLabel, recurrence, details, changefeedNode, p.CurrentDatabase())
+	if err != nil {
+		return err
+	}
+	if firstRun != nil {
+		sj.SetNextRun(*firstRun)
+	}
+
+	if err := sj.Create(ctx, p.ExecCfg().InternalExecutor, p.ExtendedEvalContext().Txn); err != nil {
+		return err
+	}
+	telemetry.Count("scheduled-changefeed.create.success")
+
+	// Do not leak sink credentials in the returned statement.
+	description, err := changefeedJobDescription(p, changefeedNode, sinkURI, opts)
+	if err != nil {
+		return err
+	}
+	return schedulebase.EmitSchedule(sj, description, resultsCh)
+}
+
+func makeChangefeedSchedule(
+	env scheduledjobs.JobSchedulerEnv,
+	owner security.SQLUsername,
+	label string,
+	recurrence *schedulebase.ScheduleRecurrence,
+	details jobspb.ScheduleDetails,
+	changefeedNode *tree.CreateChangefeed,
+	database string,
+) (*jobs.ScheduledJob, error) {
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(label)
+	sj.SetOwner(owner)
+
+	if err := sj.SetSchedule(recurrence.Cron); err != nil {
+		return nil, err
+	}
+	sj.SetScheduleDetails(details)
+
+	// Serialize changefeed statement and set schedule executor and its args.
+	any, err := pbtypes.MarshalAny(&jobspb.SqlStatementExecutionArg{
+		Statement: tree.AsString(changefeedNode),
+		Database:  database,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledChangefeedExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: any},
+	)
+	return sj, nil
+}
+
+// dryRunChangefeed plans the changefeed under a transaction savepoint, and
+// then rolls back to that savepoint.
+func dryRunChangefeed(
+	ctx context.Context, p sql.PlanHookState, changefeedNode *tree.CreateChangefeed,
+) error {
+	sp, err := p.ExtendedEvalContext().Txn.CreateSavepoint(ctx)
+	if err != nil {
+		return err
+	}
+	err = dryRunInvokeChangefeed(ctx, p, changefeedNode)
+	if rollbackErr := p.ExtendedEvalContext().Txn.RollbackToSavepoint(ctx, sp); rollbackErr != nil {
+		return rollbackErr
+	}
+	return err
+}
+
+func dryRunInvokeChangefeed(
+	ctx context.Context, p sql.PlanHookState, changefeedNode *tree.CreateChangefeed,
+) error {
+	// Annotating the statement makes the changefeed plan hook create the job
+	// in our transaction, which gets rolled back, instead of starting it.
+	changefeedFn, err := planChangefeed(ctx, p, &annotatedChangefeedStatement{
+		CreateChangefeed: changefeedNode,
+		CreatedByInfo:    &jobs.CreatedByInfo{Name: jobs.CreatedByScheduledJobs},
+	})
+	if err != nil {
+		return err
+	}
+	return invokeChangefeed(ctx, changefeedFn)
+}
+
+func createChangefeedScheduleHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledChangefeed)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	eval, err := makeScheduledChangefeedEval(ctx, p, schedule)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		if err := doCreateChangefeedSchedule(ctx, p, eval, resultsCh); err != nil {
+			telemetry.Count("scheduled-changefeed.create.failed")
+			return err
+		}
+		return nil
+	}
+	return fn, scheduledChangefeedHeader, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook(createChangefeedScheduleHook)
+}
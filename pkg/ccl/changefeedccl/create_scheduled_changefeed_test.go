@@ -0,0 +1,116 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobstest"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledChangefeed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	env := jobstest.NewJobSchedulerTestEnv(jobstest.UseSystemTables, timeutil.Now())
+	var cfg *scheduledjobs.JobExecutionConfig
+	var executeSchedules func() error
+	var registry *jobs.Registry
+	knobs := &jobs.TestingKnobs{
+		JobSchedulerEnv: env,
+		TakeOverJobsScheduling: func(fn func(ctx context.Context, maxSchedules int64, txn *kv.Txn) error) {
+			executeSchedules = func() error {
+				defer registry.TestingNudgeAdoptionQueue()
+				return cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+					return fn(ctx, 0 /* allSchedules */, txn)
+				})
+			}
+		},
+		CaptureJobExecutionConfig: func(config *scheduledjobs.JobExecutionConfig) {
+			cfg = config
+		},
+	}
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		ExternalIODir: dir,
+		Knobs:         base.TestingKnobs{JobsTestingKnobs: knobs},
+	})
+	defer s.Stopper().Stop(ctx)
+	registry = s.JobRegistry().(*jobs.Registry)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'one'), (2, 'two')`)
+
+	var scheduleID int64
+	var unusedStr string
+	var unusedTS *time.Time
+	sqlDB.QueryRow(t,
+		`CREATE SCHEDULE 'my feed' FOR CHANGEFEED foo INTO $1 WITH format = 'csv' RECURRING '@hourly'`,
+		"experimental-nodelocal://0/feed",
+	).Scan(&scheduleID, &unusedStr, &unusedStr, &unusedTS, &unusedStr, &unusedStr)
+
+	sqlDB.CheckQueryResults(t,
+		`SELECT label, command FROM [SHOW SCHEDULES FOR CHANGEFEED]`,
+		[][]string{{"my feed",
+			`CREATE CHANGEFEED FOR TABLE foo INTO 'experimental-nodelocal://0/feed' WITH format = 'csv', initial_scan = 'only'`}},
+	)
+
+	sj, err := jobs.LoadScheduledJob(ctx, env, scheduleID, cfg.InternalExecutor, nil)
+	require.NoError(t, err)
+
+	// Force the schedule to execute, and wait for the changefeed it started to
+	// finish its initial scan.
+	env.SetTime(sj.NextRun().Add(time.Second))
+	require.NoError(t, executeSchedules())
+	testutils.SucceedsSoon(t, func() error {
+		registry.TestingNudgeAdoptionQueue()
+		var unused int64
+		return db.QueryRow(
+			`SELECT id FROM system.jobs WHERE status=$1 AND created_by_type=$2 AND created_by_id=$3`,
+			jobs.StatusSucceeded, jobs.CreatedByScheduledJobs, scheduleID).Scan(&unused)
+	})
+
+	// The schedule is notified once the changefeed completes.
+	ex, _, err := jobs.GetScheduledJobExecutor(tree.ScheduledChangefeedExecutor.InternalName())
+	require.NoError(t, err)
+	metrics := ex.Metrics().(*jobs.ExecutorMetrics)
+	testutils.SucceedsSoon(t, func() error {
+		if n := metrics.NumSucceeded.Count(); n != 1 {
+			return errors.Newf("expected 1 succeeded run, found %d", n)
+		}
+		return nil
+	})
+
+	sqlDB.ExpectErr(t, `scheduled changefeeds require initial_scan='only'`,
+		`CREATE SCHEDULE FOR CHANGEFEED foo INTO 'nodelocal://0/x' WITH initial_scan RECURRING '@daily'`)
+	sqlDB.ExpectErr(t, `no_initial_scan is not supported by scheduled changefeeds`,
+		`CREATE SCHEDULE FOR CHANGEFEED foo INTO 'nodelocal://0/x' WITH no_initial_scan RECURRING '@daily'`)
+	sqlDB.ExpectErr(t, `failed to dry run changefeed`,
+		`CREATE SCHEDULE FOR CHANGEFEED missing INTO 'nodelocal://0/x' RECURRING '@daily'`)
+}
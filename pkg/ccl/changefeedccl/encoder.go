@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -37,6 +38,7 @@ const (
 	confluentSubjectSuffixKey    = `-key`
 	confluentSubjectSuffixValue  = `-value`
 	confluentAvroWireFormatMagic = byte(0)
+	confluentSchemaTypeProtobuf  = `PROTOBUF`
 )
 
 // encodeRow holds all the pieces necessary to encode a row change into a key or
@@ -88,6 +90,10 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 		return makeJSONEncoder(opts)
 	case changefeedbase.OptFormatAvro:
 		return newConfluentAvroEncoder(opts)
+	case changefeedbase.OptFormatProtobuf:
+		return newConfluentProtobufEncoder(opts)
+	case changefeedbase.OptFormatCSV:
+		return makeCSVEncoder(opts)
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
@@ -95,10 +101,12 @@ func getEncoder(opts map[string]string) (Encoder, error) {
 
 // jsonEncoder encodes changefeed entries as JSON. Keys are the primary key
 // columns in a JSON array. Values are a JSON object mapping every column name
-// to its value. Updated timestamps in rows and resolved timestamp payloads are
-// stored in a sub-object under the `__crdb__` key in the top-level JSON object.
+// (or only those listed in the `columns` option) to its value. Updated
+// timestamps in rows and resolved timestamp payloads are stored in a sub-object
+// under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
 	updatedField, beforeField, wrapped, keyOnly, keyInValue bool
+	columns                                                 columnProjection
 
 	alloc rowenc.DatumAlloc
 	buf   bytes.Buffer
@@ -122,6 +130,10 @@ func makeJSONEncoder(opts map[string]string) (*jsonEncoder, error) {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptKeyInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
+	var err error
+	if e.columns, err = makeColumnProjection(opts); err != nil {
+		return nil, err
+	}
 	return e, nil
 }
 
@@ -176,6 +188,9 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 		after = make(map[string]interface{}, len(columns))
 		for i := range columns {
 			col := &columns[i]
+			if !e.columns.includes(col.Name) {
+				continue
+			}
 			datum := row.datums[i]
 			if err := datum.EnsureDecoded(col.Type, &e.alloc); err != nil {
 				return nil, err
@@ -194,6 +209,9 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 		before = make(map[string]interface{}, len(columns))
 		for i := range columns {
 			col := &columns[i]
+			if !e.columns.includes(col.Name) {
+				continue
+			}
 			datum := row.prevDatums[i]
 			if err := datum.EnsureDecoded(col.Type, &e.alloc); err != nil {
 				return nil, err
@@ -271,10 +289,11 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 
 // confluentAvroEncoder encodes changefeed entries as Avro's binary or textual
 // JSON format. Keys are the primary key columns in a record. Values are all
-// columns in a record.
+// columns (or only those listed in the `columns` option) in a record.
 type confluentAvroEncoder struct {
 	registryURL                        string
 	updatedField, beforeField, keyOnly bool
+	columns                            columnProjection
 
 	keyCache      map[tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache    map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
@@ -332,6 +351,11 @@ func newConfluentAvroEncoder(opts map[string]string) (*confluentAvroEncoder, err
 			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
 	}
 
+	var err error
+	if e.columns, err = makeColumnProjection(opts); err != nil {
+		return nil, err
+	}
+
 	e.keyCache = make(map[tableIDAndVersion]confluentRegisteredKeySchema)
 	e.valueCache = make(map[tableIDAndVersionPair]confluentRegisteredEnvelopeSchema)
 	e.resolvedCache = make(map[string]confluentRegisteredEnvelopeSchema)
@@ -385,13 +409,13 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 		var beforeDataSchema *avroDataRecord
 		if e.beforeField && row.prevTableDesc != nil {
 			var err error
-			beforeDataSchema, err = tableToAvroSchema(row.prevTableDesc, `before`)
+			beforeDataSchema, err = tableToAvroSchema(row.prevTableDesc, `before`, e.columns)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		afterDataSchema, err := tableToAvroSchema(row.tableDesc, avroSchemaNoSuffix)
+		afterDataSchema, err := tableToAvroSchema(row.tableDesc, avroSchemaNoSuffix, e.columns)
 		if err != nil {
 			return nil, err
 		}
@@ -474,26 +498,35 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
+) (int32, error) {
+	return registerConfluentSchema(ctx, e.registryURL, subject, ``, schema.codec.Schema())
+}
+
+// registerConfluentSchema registers a schema under the given subject with a
+// Confluent schema registry and returns its ID. An empty schemaType means an
+// avro schema, which is the registry's default.
+func registerConfluentSchema(
+	ctx context.Context, registryURL, subject, schemaType, schemaStr string,
 ) (int32, error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType,omitempty"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
 	}
 
-	url, err := url.Parse(e.registryURL)
+	url, err := url.Parse(registryURL)
 	if err != nil {
 		return 0, err
 	}
 	url.Path = filepath.Join(url.EscapedPath(), `subjects`, subject, `versions`)
 
-	schemaStr := schema.codec.Schema()
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", url, schemaStr)
+		log.Infof(ctx, "registering schema %s %s", url, schemaStr)
 	}
 
-	req := confluentSchemaVersionRequest{Schema: schemaStr}
+	req := confluentSchemaVersionRequest{Schema: schemaStr, SchemaType: schemaType}
 	var buf bytes.Buffer
 	if err := gojson.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -535,3 +568,356 @@ func (e *confluentAvroEncoder) register(
 
 	return id, nil
 }
+
+// confluentProtobufEncoder encodes changefeed entries as protobuf messages in
+// the Confluent wire format. Keys are the primary key columns in a message.
+// Values are all columns (or only those listed in the `columns` option) in a
+// message, wrapped in an envelope message. A schema is generated and
+// registered for every table version, just like confluentAvroEncoder does.
+type confluentProtobufEncoder struct {
+	registryURL                        string
+	updatedField, beforeField, keyOnly bool
+	columns                            columnProjection
+
+	keyCache      map[tableIDAndVersion]confluentRegisteredProtobufKeySchema
+	valueCache    map[tableIDAndVersionPair]confluentRegisteredProtobufEnvelopeSchema
+	resolvedCache map[string]confluentRegisteredProtobufEnvelopeSchema
+}
+
+type confluentRegisteredProtobufKeySchema struct {
+	schema     *protobufDataRecord
+	registryID int32
+}
+
+type confluentRegisteredProtobufEnvelopeSchema struct {
+	schema     *protobufEnvelopeRecord
+	registryID int32
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(opts map[string]string) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{registryURL: opts[changefeedbase.OptConfluentSchemaRegistry]}
+
+	switch opts[changefeedbase.OptEnvelope] {
+	case string(changefeedbase.OptEnvelopeKeyOnly):
+		e.keyOnly = true
+	case string(changefeedbase.OptEnvelopeWrapped):
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	if e.updatedField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptUpdatedTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+	_, e.beforeField = opts[changefeedbase.OptDiff]
+	if e.beforeField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+
+	if _, ok := opts[changefeedbase.OptKeyInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	if len(e.registryURL) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	var err error
+	if e.columns, err = makeColumnProjection(opts); err != nil {
+		return nil, err
+	}
+
+	e.keyCache = make(map[tableIDAndVersion]confluentRegisteredProtobufKeySchema)
+	e.valueCache = make(map[tableIDAndVersionPair]confluentRegisteredProtobufEnvelopeSchema)
+	e.resolvedCache = make(map[string]confluentRegisteredProtobufEnvelopeSchema)
+	return e, nil
+}
+
+// confluentProtobufHeader returns the Confluent wire format header of a
+// protobuf message. Every message we encode is the first one in its schema,
+// which is what the single 0 message index means.
+//
+// https://docs.confluent.io/platform/current/schema-registry/serdes-develop/index.html#wire-format
+func confluentProtobufHeader(registryID int32) []byte {
+	header := []byte{
+		confluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+		0, // Message indexes.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
+
+// supersededBy returns whether v is an older version of the same table as
+// newer.
+func (v tableIDAndVersion) supersededBy(newer tableIDAndVersion) bool {
+	return v>>32 == newer>>32 && v < newer
+}
+
+// evictKeySchemas bounds keyCache by removing the schemas of the versions of
+// the table that are older than the one about to be cached. Rows of an older
+// version can still show up while a schema change is in progress, in which
+// case its schema is cached again until a newer version comes along.
+// Registering the same schema again returns the same registry ID, so this only
+// costs a round trip to the registry.
+func (e *confluentProtobufEncoder) evictKeySchemas(newest tableIDAndVersion) {
+	for k := range e.keyCache {
+		if k.supersededBy(newest) {
+			delete(e.keyCache, k)
+		}
+	}
+}
+
+// evictEnvelopeSchemas is like evictKeySchemas for valueCache, which is keyed
+// by the version of the table after the change.
+func (e *confluentProtobufEncoder) evictEnvelopeSchemas(newest tableIDAndVersion) {
+	for k := range e.valueCache {
+		if k[1].supersededBy(newest) {
+			delete(e.valueCache, k)
+		}
+	}
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
+	cacheKey := makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
+	registered, ok := e.keyCache[cacheKey]
+	if !ok {
+		var err error
+		registered.schema, err = indexToProtobufMessage(row.tableDesc, row.tableDesc.GetPrimaryIndex().IndexDesc())
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.GetName()) + confluentSubjectSuffixKey
+		registered.registryID, err = registerConfluentSchema(ctx, e.registryURL, subject,
+			confluentSchemaTypeProtobuf, protobufSchemaText(registered.schema.file()))
+		if err != nil {
+			return nil, err
+		}
+		e.evictKeySchemas(cacheKey)
+		e.keyCache[cacheKey] = registered
+	}
+	return registered.schema.appendBinary(confluentProtobufHeader(registered.registryID), row.datums)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, row encodeRow,
+) ([]byte, error) {
+	if e.keyOnly {
+		return nil, nil
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField && row.prevTableDesc != nil {
+		cacheKey[0] = makeTableIDAndVersion(row.prevTableDesc.GetID(), row.prevTableDesc.GetVersion())
+	}
+	cacheKey[1] = makeTableIDAndVersion(row.tableDesc.GetID(), row.tableDesc.GetVersion())
+	registered, ok := e.valueCache[cacheKey]
+	if !ok {
+		var beforeDataSchema *protobufDataRecord
+		if e.beforeField && row.prevTableDesc != nil {
+			var err error
+			beforeDataSchema, err = tableToProtobufMessage(row.prevTableDesc, `before`, e.columns)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		afterDataSchema, err := tableToProtobufMessage(row.tableDesc, avroSchemaNoSuffix, e.columns)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := protobufEnvelopeOpts{
+			afterField:   true,
+			beforeField:  beforeDataSchema != nil,
+			updatedField: e.updatedField,
+		}
+		registered.schema = envelopeToProtobufSchema(row.tableDesc.GetName(), opts, beforeDataSchema, afterDataSchema)
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(row.tableDesc.GetName()) + confluentSubjectSuffixValue
+		registered.registryID, err = registerConfluentSchema(ctx, e.registryURL, subject,
+			confluentSchemaTypeProtobuf, protobufSchemaText(registered.schema.file))
+		if err != nil {
+			return nil, err
+		}
+		e.evictEnvelopeSchemas(cacheKey[1])
+		e.valueCache[cacheKey] = registered
+	}
+	var updated string
+	if e.updatedField {
+		updated = row.updated.AsOfSystemTime()
+	}
+	var beforeDatums, afterDatums rowenc.EncDatumRow
+	if row.prevDatums != nil && !row.prevDeleted {
+		beforeDatums = row.prevDatums
+	}
+	if !row.deleted {
+		afterDatums = row.datums
+	}
+	return registered.schema.BinaryFromRow(
+		confluentProtobufHeader(registered.registryID), updated, `` /* resolved */, beforeDatums, afterDatums)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registered, ok := e.resolvedCache[topic]
+	if !ok {
+		opts := protobufEnvelopeOpts{resolvedField: true}
+		registered.schema = envelopeToProtobufSchema(topic, opts, nil /* before */, nil /* after */)
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		var err error
+		registered.registryID, err = registerConfluentSchema(ctx, e.registryURL, subject,
+			confluentSchemaTypeProtobuf, protobufSchemaText(registered.schema.file))
+		if err != nil {
+			return nil, err
+		}
+		// There is one entry per topic, so this cache doesn't need a bound.
+		e.resolvedCache[topic] = registered
+	}
+	return registered.schema.BinaryFromRow(confluentProtobufHeader(registered.registryID),
+		`` /* updated */, resolved.AsOfSystemTime(), nil /* beforeRow */, nil /* afterRow */)
+}
+
+// csvEncoder encodes changefeed values as CSV records with one field for every
+// column (or only those listed in the `columns` option) in table order, in the
+// same text representation as EXPORT. NULLs are empty fields. It only supports
+// envelope=row: deletions have no value, so the cloud storage sink, which is
+// the only one the format can be used with, doesn't write them. Since that
+// silently loses changes, the user has to opt in with `csv_skip_deletes`
+// unless the changefeed only emits its initial scan, which has no deletions.
+// Keys are also CSV records with the primary key columns. Resolved timestamps
+// are JSON, like the ones of jsonEncoder with envelope=wrapped, because they're
+// written to their own files.
+type csvEncoder struct {
+	updatedField bool
+	columns      columnProjection
+
+	alloc  rowenc.DatumAlloc
+	buf    bytes.Buffer
+	writer *csv.Writer
+	record []string
+	fmtCtx *tree.FmtCtx
+}
+
+var _ Encoder = &csvEncoder{}
+
+func makeCSVEncoder(opts map[string]string) (*csvEncoder, error) {
+	if e := changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]); e != changefeedbase.OptEnvelopeRow {
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, e, changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+	}
+	for _, opt := range []string{changefeedbase.OptDiff, changefeedbase.OptKeyInValue} {
+		if _, ok := opts[opt]; ok {
+			return nil, errors.Errorf(`%s is not supported with %s=%s`,
+				opt, changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+		}
+	}
+	if _, ok := opts[changefeedbase.OptCSVSkipDeletes]; !ok && !initialScanOnlyFromOptions(opts) {
+		return nil, errors.WithHintf(
+			errors.Errorf(`%s=%s cannot represent deleted rows`,
+				changefeedbase.OptFormat, changefeedbase.OptFormatCSV),
+			`use WITH %s to create a changefeed which does not emit them, or %s='%s'`,
+			changefeedbase.OptCSVSkipDeletes, changefeedbase.OptInitialScan, changefeedbase.OptInitialScanOnly)
+	}
+	e := &csvEncoder{fmtCtx: tree.NewFmtCtx(tree.FmtExport)}
+	e.writer = csv.NewWriter(&e.buf)
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	var err error
+	if e.columns, err = makeColumnProjection(opts); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) appendField(datum rowenc.EncDatum, col *descpb.ColumnDescriptor) error {
+	if err := datum.EnsureDecoded(col.Type, &e.alloc); err != nil {
+		return err
+	}
+	if datum.Datum == tree.DNull {
+		e.record = append(e.record, ``)
+		return nil
+	}
+	e.fmtCtx.Reset()
+	datum.Datum.Format(e.fmtCtx)
+	e.record = append(e.record, e.fmtCtx.String())
+	return nil
+}
+
+func (e *csvEncoder) writeRecord() ([]byte, error) {
+	e.buf.Reset()
+	if err := e.writer.Write(e.record); err != nil {
+		return nil, err
+	}
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *csvEncoder) EncodeKey(_ context.Context, row encodeRow) ([]byte, error) {
+	e.record = e.record[:0]
+	colIdxByID := row.tableDesc.ColumnIdxMap()
+	primaryIndex := row.tableDesc.GetPrimaryIndex()
+	for i := 0; i < primaryIndex.NumColumns(); i++ {
+		colID := primaryIndex.GetColumnID(i)
+		idx, ok := colIdxByID.Get(colID)
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		if err := e.appendField(row.datums[idx], row.tableDesc.GetColumnAtIdx(idx)); err != nil {
+			return nil, err
+		}
+	}
+	return e.writeRecord()
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *csvEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
+	if row.deleted {
+		return nil, nil
+	}
+	e.record = e.record[:0]
+	columns := row.tableDesc.GetPublicColumns()
+	for i := range columns {
+		col := &columns[i]
+		if !e.columns.includes(col.Name) {
+			continue
+		}
+		if err := e.appendField(row.datums[i], col); err != nil {
+			return nil, err
+		}
+	}
+	if e.updatedField {
+		e.record = append(e.record, row.updated.AsOfSystemTime())
+	}
+	return e.writeRecord()
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *csvEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return gojson.Marshal(map[string]interface{}{
+		`resolved`: tree.TimestampToDecimalDatum(resolved).Decimal.String(),
+	})
+}
@@ -21,8 +21,10 @@ import (
 	"github.com/cockroachdb/cockroach-go/crdb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -32,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/workload/ledger"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
 	"github.com/cockroachdb/errors"
+	protodesc "github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
 	"github.com/linkedin/goavro/v2"
 	"github.com/stretchr/testify/require"
 )
@@ -457,3 +460,226 @@ func TestAvroLedger(t *testing.T) {
 	t.Run(`sinkless`, sinklessTest(testFn))
 	t.Run(`enterprise`, enterpriseTest(testFn))
 }
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	reg := makeTestSchemaRegistry()
+	defer reg.Close()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c BYTES, d FLOAT)`)
+	require.NoError(t, err)
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+		rowenc.EncDatum{Datum: tree.NewDBytes(`baz`)},
+		rowenc.EncDatum{Datum: tree.DNull},
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	e, err := getEncoder(map[string]string{
+		changefeedbase.OptFormat:                  string(changefeedbase.OptFormatProtobuf),
+		changefeedbase.OptEnvelope:                string(changefeedbase.OptEnvelopeWrapped),
+		changefeedbase.OptDiff:                    ``,
+		changefeedbase.OptUpdatedTimestamps:       ``,
+		changefeedbase.OptConfluentSchemaRegistry: reg.server.URL,
+	})
+	require.NoError(t, err)
+	pe := e.(*confluentProtobufEncoder)
+
+	// decode checks the Confluent wire format header and decodes the rest of
+	// the message with the schema that was registered under its id.
+	decode := func(
+		b []byte, file *protodesc.FileDescriptorProto, expectedID int32,
+	) map[string]interface{} {
+		require.True(t, len(b) >= 6)
+		require.Equal(t, confluentAvroWireFormatMagic, b[0])
+		require.Equal(t, expectedID, int32(binary.BigEndian.Uint32(b[1:5])))
+		require.Equal(t, byte(0), b[5])
+		reg.mu.Lock()
+		require.Equal(t, protobufSchemaText(file), reg.mu.schemas[expectedID])
+		reg.mu.Unlock()
+		native, err := protobufNativeFromBinary(file, file.MessageType[0], b[6:])
+		require.NoError(t, err)
+		return native
+	}
+
+	update := encodeRow{
+		datums:        row,
+		updated:       ts,
+		tableDesc:     tableDesc,
+		prevDatums:    row,
+		prevTableDesc: tableDesc,
+	}
+	key, err := e.EncodeKey(ctx, update)
+	require.NoError(t, err)
+	registeredKey := pe.keyCache[makeTableIDAndVersion(tableDesc.GetID(), tableDesc.GetVersion())]
+	require.Equal(t, map[string]interface{}{`a`: int64(1)},
+		decode(key, registeredKey.schema.file(), registeredKey.registryID))
+
+	value, err := e.EncodeValue(ctx, update)
+	require.NoError(t, err)
+	idVer := makeTableIDAndVersion(tableDesc.GetID(), tableDesc.GetVersion())
+	registeredValue := pe.valueCache[tableIDAndVersionPair{idVer, idVer}]
+	require.Equal(t, map[string]interface{}{
+		`before`:  map[string]interface{}{`a`: int64(1), `b`: `bar`, `c`: []byte(`baz`)},
+		`after`:   map[string]interface{}{`a`: int64(1), `b`: `bar`, `c`: []byte(`baz`)},
+		`updated`: `1.0000000002`,
+	}, decode(value, registeredValue.schema.file, registeredValue.registryID))
+
+	value, err = e.EncodeValue(ctx, encodeRow{
+		datums:        row,
+		deleted:       true,
+		updated:       ts,
+		tableDesc:     tableDesc,
+		prevDatums:    row,
+		prevTableDesc: tableDesc,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		`before`:  map[string]interface{}{`a`: int64(1), `b`: `bar`, `c`: []byte(`baz`)},
+		`updated`: `1.0000000002`,
+	}, decode(value, registeredValue.schema.file, registeredValue.registryID))
+
+	resolved, err := e.EncodeResolvedTimestamp(ctx, `foo`, ts)
+	require.NoError(t, err)
+	registeredResolved := pe.resolvedCache[`foo`]
+	require.Equal(t, map[string]interface{}{`resolved`: `1.0000000002`},
+		decode(resolved, registeredResolved.schema.file, registeredResolved.registryID))
+
+	// Caching the schemas of a newer version of the table evicts the ones of
+	// the older versions.
+	newTableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c BYTES, d FLOAT)`)
+	require.NoError(t, err)
+	newTableDesc.(*tabledesc.Mutable).Version = tableDesc.GetVersion() + 1
+	newIDVer := makeTableIDAndVersion(newTableDesc.GetID(), newTableDesc.GetVersion())
+	update = encodeRow{
+		datums:        row,
+		updated:       ts,
+		tableDesc:     newTableDesc,
+		prevDatums:    row,
+		prevTableDesc: tableDesc,
+	}
+	_, err = e.EncodeKey(ctx, update)
+	require.NoError(t, err)
+	_, err = e.EncodeValue(ctx, update)
+	require.NoError(t, err)
+	require.Len(t, pe.keyCache, 1)
+	require.Contains(t, pe.keyCache, newIDVer)
+	require.Len(t, pe.valueCache, 1)
+	require.Contains(t, pe.valueCache, tableIDAndVersionPair{idVer, newIDVer})
+
+	// The schemas of an older version are cached again if its rows show up
+	// after the newer version's, without evicting the newer version's.
+	_, err = e.EncodeKey(ctx, encodeRow{datums: row, updated: ts, tableDesc: tableDesc})
+	require.NoError(t, err)
+	require.Len(t, pe.keyCache, 2)
+	require.Contains(t, pe.keyCache, newIDVer)
+
+	_, err = getEncoder(map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatProtobuf),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	})
+	require.EqualError(t, err, `WITH option confluent_schema_registry is required for format=protobuf`)
+}
+
+func TestCSVEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tableDesc, err := parseTableDesc(
+		`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c DECIMAL, d STRING[])`)
+	require.NoError(t, err)
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar, "baz"`)},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: tree.NewDArray(types.String)},
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	for _, tc := range []struct {
+		opts          map[string]string
+		noSkipDeletes bool
+		key           string
+		value         string
+		resolved      string
+		err           string
+	}{
+		{
+			opts:     map[string]string{},
+			key:      "1\n",
+			value:    "1,\"bar, \"\"baz\"\"\",,ARRAY[]\n",
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts:     map[string]string{changefeedbase.OptUpdatedTimestamps: ``},
+			key:      "1\n",
+			value:    "1,\"bar, \"\"baz\"\"\",,ARRAY[],1.0000000002\n",
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts:     map[string]string{changefeedbase.OptColumns: `b,a`},
+			key:      "1\n",
+			value:    "1,\"bar, \"\"baz\"\"\"\n",
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts: map[string]string{changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped)},
+			err:  `envelope=wrapped is not supported with format=csv`,
+		},
+		{
+			opts: map[string]string{changefeedbase.OptDiff: ``},
+			err:  `diff is not supported with format=csv`,
+		},
+		{
+			opts:          map[string]string{},
+			noSkipDeletes: true,
+			err:           `format=csv cannot represent deleted rows`,
+		},
+		{
+			opts:          map[string]string{changefeedbase.OptInitialScan: changefeedbase.OptInitialScanOnly},
+			noSkipDeletes: true,
+			key:           "1\n",
+			value:         "1,\"bar, \"\"baz\"\"\",,ARRAY[]\n",
+			resolved:      `{"resolved":"1.0000000002"}`,
+		},
+	} {
+		opts := map[string]string{
+			changefeedbase.OptFormat:         string(changefeedbase.OptFormatCSV),
+			changefeedbase.OptEnvelope:       string(changefeedbase.OptEnvelopeRow),
+			changefeedbase.OptCSVSkipDeletes: ``,
+		}
+		if tc.noSkipDeletes {
+			delete(opts, changefeedbase.OptCSVSkipDeletes)
+		}
+		for k, v := range tc.opts {
+			opts[k] = v
+		}
+		e, err := getEncoder(opts)
+		if tc.err != `` {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+
+		insert := encodeRow{datums: row, updated: ts, tableDesc: tableDesc}
+		key, err := e.EncodeKey(ctx, insert)
+		require.NoError(t, err)
+		require.Equal(t, tc.key, string(key))
+		value, err := e.EncodeValue(ctx, insert)
+		require.NoError(t, err)
+		require.Equal(t, tc.value, string(value))
+
+		value, err = e.EncodeValue(ctx, encodeRow{datums: row, deleted: true, updated: ts, tableDesc: tableDesc})
+		require.NoError(t, err)
+		require.Nil(t, value)
+
+		resolved, err := e.EncodeResolvedTimestamp(ctx, `foo`, ts)
+		require.NoError(t, err)
+		require.Equal(t, tc.resolved, string(resolved))
+	}
+}
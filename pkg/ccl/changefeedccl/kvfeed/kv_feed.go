@@ -43,6 +43,7 @@ type Config struct {
 	Gossip             gossip.OptionalGossip
 	Spans              []roachpb.Span
 	Targets            jobspb.ChangefeedTargets
+	Opts               map[string]string
 	Sink               EventBufferWriter
 	LeaseMgr           *lease.Manager
 	Metrics            *Metrics
@@ -57,6 +58,11 @@ type Config struct {
 	// been seen.
 	NeedsInitialScan bool
 
+	// If true, the feed will stop once the initial scan is done, after
+	// resolving all of the spans at the scan timestamp as a boundary. The
+	// higher layers are expected to tear down the changefeed once they see it.
+	InitialScanOnly bool
+
 	// InitialHighWater is the timestamp from which new events are guaranteed to
 	// be produced.
 	InitialHighWater hlc.Timestamp
@@ -94,7 +100,7 @@ func Run(ctx context.Context, cfg Config) error {
 	f := newKVFeed(
 		cfg.Sink, cfg.Spans,
 		cfg.SchemaChangeEvents, cfg.SchemaChangePolicy,
-		cfg.NeedsInitialScan, cfg.WithDiff,
+		cfg.NeedsInitialScan, cfg.InitialScanOnly, cfg.WithDiff,
 		cfg.InitialHighWater,
 		cfg.Codec,
 		sf, sc, pff, bf)
@@ -108,10 +114,18 @@ func Run(ctx context.Context, cfg Config) error {
 		log.Infof(ctx, "stopping changefeed due to schema change at %v", scErr.ts)
 		<-ctx.Done()
 		err = nil
+	} else if errors.Is(err, errInitialScanDone) {
+		log.Infof(ctx, "stopping changefeed after its initial scan")
+		<-ctx.Done()
+		err = nil
 	}
 	return err
 }
 
+// errInitialScanDone is a sentinel error to indicate to Run() that the feed
+// is stopping because it only had to do an initial scan.
+var errInitialScanDone = errors.New("initial scan done")
+
 // schemaChangeDetectedError is a sentinel error to indicate to Run() that the
 // schema change is stopping due to a schema change. This is handy to trigger
 // the context group to stop; the error is handled entirely in this package.
@@ -132,6 +146,7 @@ type kvFeed struct {
 	spans               []roachpb.Span
 	withDiff            bool
 	withInitialBackfill bool
+	initialScanOnly     bool
 	initialHighWater    hlc.Timestamp
 	sink                EventBufferWriter
 	codec               keys.SQLCodec
@@ -151,7 +166,7 @@ func newKVFeed(
 	spans []roachpb.Span,
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass,
 	schemaChangePolicy changefeedbase.SchemaChangePolicy,
-	withInitialBackfill, withDiff bool,
+	withInitialBackfill, initialScanOnly, withDiff bool,
 	initialHighWater hlc.Timestamp,
 	codec keys.SQLCodec,
 	tf schemaFeed,
@@ -163,6 +178,7 @@ func newKVFeed(
 		sink:                sink,
 		spans:               spans,
 		withInitialBackfill: withInitialBackfill,
+		initialScanOnly:     initialScanOnly,
 		withDiff:            withDiff,
 		initialHighWater:    initialHighWater,
 		schemaChangeEvents:  schemaChangeEvents,
@@ -184,6 +200,16 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		if err = f.scanIfShould(ctx, initialScan, highWater); err != nil {
 			return err
 		}
+		if initialScan && f.initialScanOnly {
+			// Resolve all of the spans at the scan timestamp as a boundary so that
+			// everything scanned gets flushed before the feed is torn down.
+			for _, span := range f.spans {
+				if err := f.sink.AddResolved(ctx, span, highWater, true); err != nil {
+					return err
+				}
+			}
+			return errInitialScanDone
+		}
 		highWater, err = f.runUntilTableEvent(ctx, highWater)
 		if err != nil {
 			return err
@@ -415,6 +441,7 @@ func makeTablefeedConfig(cfg Config) schemafeed.Config {
 		Clock:              cfg.Clock,
 		Settings:           cfg.Settings,
 		Targets:            cfg.Targets,
+		Opts:               cfg.Opts,
 		LeaseManager:       cfg.LeaseMgr,
 		SchemaChangeEvents: cfg.SchemaChangeEvents,
 		InitialHighWater:   cfg.InitialHighWater,
@@ -19,6 +19,7 @@ import (
 var escapeRE = regexp.MustCompile(`_u[0-9a-fA-F]{2,8}_`)
 var kafkaDisallowedRE = regexp.MustCompile(`[^a-zA-Z0-9\._\-]`)
 var avroDisallowedRE = regexp.MustCompile(`[^A-Za-z0-9_]`)
+var pubsubDisallowedRE = regexp.MustCompile(`[^a-zA-Z0-9\._\-~+]`)
 
 func escapeRune(r rune) string {
 	if r <= 1<<16 {
@@ -55,6 +56,33 @@ func KafkaNameToSQLName(s string) string {
 	return unescapeSQLName(s)
 }
 
+// SQLNameToPubsubName escapes a sql table name into a valid Google Cloud
+// Pub/Sub topic name. This is reversible by PubsubNameToSQLName except when the
+// escaped string is longer than Pub/Sub's length limit.
+//
+// Pub/Sub allows names matching `[a-zA-Z][a-zA-Z0-9\._\-~+%]{2,254}` that
+// don't start with `goog`. `%` is escaped here like any other disallowed rune
+// so it can't be confused with URL encoding. The restrictions on the first
+// rune and on the length apply to the full topic name, including any prefix,
+// and are checked by the sink.
+//
+// Runes are escaped with _u<hex>_ in an attempt to look like U+0021. For
+// example `!` escapes to `_u0021_`.
+func SQLNameToPubsubName(s string) string {
+	s = escapeSQLName(s, pubsubDisallowedRE)
+	if len(s) > 255 {
+		// Not going to roundtrip, but not much we can do about that.
+		return s[:255]
+	}
+	return s
+}
+
+// PubsubNameToSQLName is the inverse of SQLNameToPubsubName except when
+// SQLNameToPubsubName had to truncate.
+func PubsubNameToSQLName(s string) string {
+	return unescapeSQLName(s)
+}
+
 // SQLNameToAvroName escapes a sql table name into a valid avro record or field
 // name. This is reversible by AvroNameToSQLName.
 //
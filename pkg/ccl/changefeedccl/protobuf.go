@@ -0,0 +1,448 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+	protodesc "github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// The file contains the mapping between our SQL schemas and protobuf messages
+// used by `format=protobuf`. Like avro.go, it's not intended to be a general
+// purpose protobuf utility.
+//
+// Each version of a table is mapped to a generated proto2 message with one
+// optional field per column. The field number is the column's ID, which never
+// changes and is never reused, so the messages generated for adjacent versions
+// of a table are always wire compatible with each other: adding a column adds a
+// field, dropping a column leaves a gap. A NULL is encoded by omitting the
+// field, which is why every field is optional, regardless of whether the column
+// allows NULLs.
+//
+// Columns of types with a natural protobuf counterpart (BOOL, INT, FLOAT,
+// STRING and BYTES) use it. Every other type is encoded as a string with the
+// same text representation as EXPORT.
+//
+// A generated message is kept as a FileDescriptorProto and registered with the
+// schema registry in the .proto text format it renders to.
+
+const (
+	protobufEnvelopeFieldBefore   = 1
+	protobufEnvelopeFieldAfter    = 2
+	protobufEnvelopeFieldUpdated  = 3
+	protobufEnvelopeFieldResolved = 4
+)
+
+// protobufDataRecord is a protobuf message that represents the schema of a SQL
+// table or index.
+type protobufDataRecord struct {
+	msg *protodesc.DescriptorProto
+
+	colIdxByFieldIdx map[int]int
+	typByFieldIdx    map[int]*types.T
+	alloc            rowenc.DatumAlloc
+	fmtCtx           *tree.FmtCtx
+}
+
+// protobufEnvelopeOpts controls which fields in protobufEnvelopeRecord are set.
+type protobufEnvelopeOpts struct {
+	beforeField, afterField     bool
+	updatedField, resolvedField bool
+}
+
+// protobufEnvelopeRecord is the schema of a changefeed value: a file with an
+// envelope message, which is always the first message of the file, followed by
+// the messages of the before and after versions of the row, if any.
+type protobufEnvelopeRecord struct {
+	file          *protodesc.FileDescriptorProto
+	opts          protobufEnvelopeOpts
+	before, after *protobufDataRecord
+}
+
+func protobufField(
+	name string, number int32, typ protodesc.FieldDescriptorProto_Type, typeName string,
+) *protodesc.FieldDescriptorProto {
+	f := &protodesc.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  protodesc.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   typ.Enum(),
+	}
+	if typeName != `` {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+func columnDescToProtobufField(
+	colDesc *descpb.ColumnDescriptor,
+) (*protodesc.FieldDescriptorProto, error) {
+	if colDesc.ID > descpb.ColumnID(math.MaxInt32>>3) {
+		return nil, errors.Errorf(`column %s has an id too large for a protobuf field: %d`,
+			colDesc.Name, colDesc.ID)
+	}
+	var typ protodesc.FieldDescriptorProto_Type
+	switch colDesc.Type.Family() {
+	case types.BoolFamily:
+		typ = protodesc.FieldDescriptorProto_TYPE_BOOL
+	case types.IntFamily:
+		typ = protodesc.FieldDescriptorProto_TYPE_INT64
+	case types.FloatFamily:
+		typ = protodesc.FieldDescriptorProto_TYPE_DOUBLE
+	case types.BytesFamily:
+		typ = protodesc.FieldDescriptorProto_TYPE_BYTES
+	default:
+		typ = protodesc.FieldDescriptorProto_TYPE_STRING
+	}
+	return protobufField(SQLNameToAvroName(colDesc.Name), int32(colDesc.ID), typ, ``), nil
+}
+
+func makeProtobufDataRecord(name string) *protobufDataRecord {
+	return &protobufDataRecord{
+		msg:              &protodesc.DescriptorProto{Name: proto.String(name)},
+		colIdxByFieldIdx: make(map[int]int),
+		typByFieldIdx:    make(map[int]*types.T),
+		fmtCtx:           tree.NewFmtCtx(tree.FmtExport),
+	}
+}
+
+func (r *protobufDataRecord) addColumn(colIdx int, col *descpb.ColumnDescriptor) error {
+	field, err := columnDescToProtobufField(col)
+	if err != nil {
+		return err
+	}
+	r.colIdxByFieldIdx[len(r.msg.Field)] = colIdx
+	r.typByFieldIdx[len(r.msg.Field)] = col.Type
+	r.msg.Field = append(r.msg.Field, field)
+	return nil
+}
+
+// indexToProtobufMessage converts an index descriptor into its corresponding
+// protobuf message. The fields are kept in the same order as columns in the
+// index.
+func indexToProtobufMessage(
+	tableDesc catalog.TableDescriptor, indexDesc *descpb.IndexDescriptor,
+) (*protobufDataRecord, error) {
+	r := makeProtobufDataRecord(SQLNameToAvroName(tableDesc.GetName()))
+	colIdxByID := tableDesc.ColumnIdxMap()
+	for _, colID := range indexDesc.ColumnIDs {
+		colIdx, ok := colIdxByID.Get(colID)
+		if !ok {
+			return nil, errors.Errorf(`unknown column id: %d`, colID)
+		}
+		if err := r.addColumn(colIdx, tableDesc.GetColumnAtIdx(colIdx)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// tableToProtobufMessage converts a table descriptor into its corresponding
+// protobuf message. The fields are kept in the same order as
+// `tableDesc.Columns`. If a name suffix is provided (as opposed to
+// avroSchemaNoSuffix), it will be appended to the end of the message's name.
+// Columns not included in the projection are left out.
+func tableToProtobufMessage(
+	tableDesc catalog.TableDescriptor, nameSuffix string, columns columnProjection,
+) (*protobufDataRecord, error) {
+	name := SQLNameToAvroName(tableDesc.GetName())
+	if nameSuffix != avroSchemaNoSuffix {
+		name = name + `_` + nameSuffix
+	}
+	r := makeProtobufDataRecord(name)
+	for colIdx := range tableDesc.GetPublicColumns() {
+		col := tableDesc.GetColumnAtIdx(colIdx)
+		if !columns.includes(col.Name) {
+			continue
+		}
+		if err := r.addColumn(colIdx, col); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// file returns a file with the message as its only message, which is the
+// schema of a changefeed key.
+func (r *protobufDataRecord) file() *protodesc.FileDescriptorProto {
+	return &protodesc.FileDescriptorProto{
+		Syntax:      proto.String(`proto2`),
+		MessageType: []*protodesc.DescriptorProto{r.msg},
+	}
+}
+
+// appendBinary encodes the given row data into protobuf's binary format and
+// appends it to buf.
+func (r *protobufDataRecord) appendBinary(buf []byte, row rowenc.EncDatumRow) ([]byte, error) {
+	b := proto.NewBuffer(buf)
+	for fieldIdx, field := range r.msg.Field {
+		encDatum := row[r.colIdxByFieldIdx[fieldIdx]]
+		if err := encDatum.EnsureDecoded(r.typByFieldIdx[fieldIdx], &r.alloc); err != nil {
+			return nil, err
+		}
+		if encDatum.Datum == tree.DNull {
+			continue
+		}
+		if err := r.encodeField(b, field, encDatum.Datum); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func (r *protobufDataRecord) encodeField(
+	b *proto.Buffer, field *protodesc.FieldDescriptorProto, d tree.Datum,
+) error {
+	key := uint64(field.GetNumber()) << 3
+	switch field.GetType() {
+	case protodesc.FieldDescriptorProto_TYPE_BOOL:
+		var v uint64
+		if *d.(*tree.DBool) {
+			v = 1
+		}
+		_ = b.EncodeVarint(key | proto.WireVarint)
+		return b.EncodeVarint(v)
+	case protodesc.FieldDescriptorProto_TYPE_INT64:
+		_ = b.EncodeVarint(key | proto.WireVarint)
+		return b.EncodeVarint(uint64(*d.(*tree.DInt)))
+	case protodesc.FieldDescriptorProto_TYPE_DOUBLE:
+		_ = b.EncodeVarint(key | proto.WireFixed64)
+		return b.EncodeFixed64(math.Float64bits(float64(*d.(*tree.DFloat))))
+	case protodesc.FieldDescriptorProto_TYPE_BYTES:
+		_ = b.EncodeVarint(key | proto.WireBytes)
+		return b.EncodeRawBytes([]byte(*d.(*tree.DBytes)))
+	case protodesc.FieldDescriptorProto_TYPE_STRING:
+		var s string
+		switch t := d.(type) {
+		case *tree.DString:
+			s = string(*t)
+		case *tree.DCollatedString:
+			s = t.Contents
+		default:
+			r.fmtCtx.Reset()
+			d.Format(r.fmtCtx)
+			s = r.fmtCtx.String()
+		}
+		_ = b.EncodeVarint(key | proto.WireBytes)
+		return b.EncodeStringBytes(s)
+	default:
+		return errors.AssertionFailedf(`unexpected protobuf field type %s`, field.GetType())
+	}
+}
+
+// envelopeToProtobufSchema creates a protobuf schema for an envelope
+// containing before and after versions of a row change and metadata about that
+// row change.
+func envelopeToProtobufSchema(
+	topic string, opts protobufEnvelopeOpts, before, after *protobufDataRecord,
+) *protobufEnvelopeRecord {
+	envelope := &protodesc.DescriptorProto{Name: proto.String(SQLNameToAvroName(topic) + `_envelope`)}
+	r := &protobufEnvelopeRecord{
+		file: &protodesc.FileDescriptorProto{
+			Syntax:      proto.String(`proto2`),
+			MessageType: []*protodesc.DescriptorProto{envelope},
+		},
+		opts: opts,
+	}
+	if opts.beforeField {
+		r.before = before
+		envelope.Field = append(envelope.Field, protobufField(`before`, protobufEnvelopeFieldBefore,
+			protodesc.FieldDescriptorProto_TYPE_MESSAGE, before.msg.GetName()))
+		r.file.MessageType = append(r.file.MessageType, before.msg)
+	}
+	if opts.afterField {
+		r.after = after
+		envelope.Field = append(envelope.Field, protobufField(`after`, protobufEnvelopeFieldAfter,
+			protodesc.FieldDescriptorProto_TYPE_MESSAGE, after.msg.GetName()))
+		r.file.MessageType = append(r.file.MessageType, after.msg)
+	}
+	if opts.updatedField {
+		envelope.Field = append(envelope.Field, protobufField(`updated`, protobufEnvelopeFieldUpdated,
+			protodesc.FieldDescriptorProto_TYPE_STRING, ``))
+	}
+	if opts.resolvedField {
+		envelope.Field = append(envelope.Field, protobufField(`resolved`, protobufEnvelopeFieldResolved,
+			protodesc.FieldDescriptorProto_TYPE_STRING, ``))
+	}
+	return r
+}
+
+// BinaryFromRow encodes the given metadata and row data into protobuf's binary
+// format. A nil row leaves the corresponding field unset, as does an empty
+// metadata string.
+func (r *protobufEnvelopeRecord) BinaryFromRow(
+	buf []byte, updated, resolved string, beforeRow, afterRow rowenc.EncDatumRow,
+) ([]byte, error) {
+	b := proto.NewBuffer(buf)
+	appendRow := func(fieldNum uint64, record *protobufDataRecord, row rowenc.EncDatumRow) error {
+		if row == nil {
+			return nil
+		}
+		msg, err := record.appendBinary(nil, row)
+		if err != nil {
+			return err
+		}
+		_ = b.EncodeVarint(fieldNum<<3 | proto.WireBytes)
+		return b.EncodeRawBytes(msg)
+	}
+	appendString := func(fieldNum uint64, s string) error {
+		if s == `` {
+			return nil
+		}
+		_ = b.EncodeVarint(fieldNum<<3 | proto.WireBytes)
+		return b.EncodeStringBytes(s)
+	}
+	if r.opts.beforeField {
+		if err := appendRow(protobufEnvelopeFieldBefore, r.before, beforeRow); err != nil {
+			return nil, err
+		}
+	}
+	if r.opts.afterField {
+		if err := appendRow(protobufEnvelopeFieldAfter, r.after, afterRow); err != nil {
+			return nil, err
+		}
+	}
+	if r.opts.updatedField {
+		if err := appendString(protobufEnvelopeFieldUpdated, updated); err != nil {
+			return nil, err
+		}
+	}
+	if r.opts.resolvedField {
+		if err := appendString(protobufEnvelopeFieldResolved, resolved); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// protobufSchemaText renders a file descriptor in the .proto text format, which
+// is what schema registries expect.
+func protobufSchemaText(file *protodesc.FileDescriptorProto) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "syntax = %q;\n", file.GetSyntax())
+	for _, msg := range file.MessageType {
+		fmt.Fprintf(&buf, "\nmessage %s {\n", msg.GetName())
+		for _, field := range msg.Field {
+			typ := field.GetTypeName()
+			if typ == `` {
+				typ = strings.ToLower(strings.TrimPrefix(field.GetType().String(), `TYPE_`))
+			}
+			label := strings.ToLower(strings.TrimPrefix(field.GetLabel().String(), `LABEL_`))
+			fmt.Fprintf(&buf, "  %s %s %s = %d;\n", label, typ, field.GetName(), field.GetNumber())
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+// protobufNativeFromBinary decodes a message of the given file into a map from
+// field name to value. Nested messages are decoded recursively. It exists to
+// make the output of the encoder readable in tests.
+func protobufNativeFromBinary(
+	file *protodesc.FileDescriptorProto, msg *protodesc.DescriptorProto, buf []byte,
+) (map[string]interface{}, error) {
+	fieldsByNumber := make(map[uint64]*protodesc.FieldDescriptorProto, len(msg.Field))
+	for _, field := range msg.Field {
+		fieldsByNumber[uint64(field.GetNumber())] = field
+	}
+	native := make(map[string]interface{}, len(msg.Field))
+	decodeVarint := func() (uint64, error) {
+		v, n := proto.DecodeVarint(buf)
+		if n == 0 {
+			return 0, errors.New(`malformed varint`)
+		}
+		buf = buf[n:]
+		return v, nil
+	}
+	decodeBytes := func() ([]byte, error) {
+		l, err := decodeVarint()
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(buf)) < l {
+			return nil, errors.New(`truncated length-delimited field`)
+		}
+		v := buf[:l]
+		buf = buf[l:]
+		return v, nil
+	}
+	for len(buf) > 0 {
+		key, err := decodeVarint()
+		if err != nil {
+			return nil, err
+		}
+		field, ok := fieldsByNumber[key>>3]
+		if !ok {
+			return nil, errors.Errorf(`unknown field number %d in message %s`, key>>3, msg.GetName())
+		}
+		switch field.GetType() {
+		case protodesc.FieldDescriptorProto_TYPE_BOOL:
+			v, err := decodeVarint()
+			if err != nil {
+				return nil, err
+			}
+			native[field.GetName()] = v != 0
+		case protodesc.FieldDescriptorProto_TYPE_INT64:
+			v, err := decodeVarint()
+			if err != nil {
+				return nil, err
+			}
+			native[field.GetName()] = int64(v)
+		case protodesc.FieldDescriptorProto_TYPE_DOUBLE:
+			if len(buf) < 8 {
+				return nil, errors.New(`truncated fixed64 field`)
+			}
+			native[field.GetName()] = math.Float64frombits(binary.LittleEndian.Uint64(buf))
+			buf = buf[8:]
+		case protodesc.FieldDescriptorProto_TYPE_BYTES:
+			v, err := decodeBytes()
+			if err != nil {
+				return nil, err
+			}
+			native[field.GetName()] = append([]byte(nil), v...)
+		case protodesc.FieldDescriptorProto_TYPE_STRING:
+			v, err := decodeBytes()
+			if err != nil {
+				return nil, err
+			}
+			native[field.GetName()] = string(v)
+		case protodesc.FieldDescriptorProto_TYPE_MESSAGE:
+			v, err := decodeBytes()
+			if err != nil {
+				return nil, err
+			}
+			var nested *protodesc.DescriptorProto
+			for _, m := range file.MessageType {
+				if m.GetName() == field.GetTypeName() {
+					nested = m
+				}
+			}
+			if nested == nil {
+				return nil, errors.Errorf(`unknown message %s`, field.GetTypeName())
+			}
+			if native[field.GetName()], err = protobufNativeFromBinary(file, nested, v); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, errors.AssertionFailedf(`unexpected protobuf field type %s`, field.GetType())
+		}
+	}
+	return native, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// columnProjection is the set of column names listed in the `columns` option.
+// A nil columnProjection includes every column.
+type columnProjection map[string]struct{}
+
+func makeColumnProjection(opts map[string]string) (columnProjection, error) {
+	names, err := changefeedbase.ProjectedColumns(opts)
+	if err != nil || names == nil {
+		return nil, err
+	}
+	p := make(columnProjection, len(names))
+	for _, name := range names {
+		p[name] = struct{}{}
+	}
+	return p, nil
+}
+
+// includes returns whether the named column is emitted.
+func (p columnProjection) includes(name string) bool {
+	if p == nil {
+		return true
+	}
+	_, ok := p[name]
+	return ok
+}
+
+// rowFilter implements the `columns` and `filter` changefeed options. It
+// decides which columns of a table need to be decoded and which changed rows
+// are emitted. The filter expression is compiled once per table descriptor
+// version, since the columns it refers to may move around between versions.
+type rowFilter struct {
+	columns columnProjection
+	filter  string
+	evalCtx *tree.EvalContext
+
+	compiled map[idVersion]*compiledRowFilter
+}
+
+type compiledRowFilter struct {
+	expr    tree.TypedExpr
+	refCols catalog.TableColSet
+	// pkOnly is true if the filter only references primary key columns, which
+	// are the only columns available for deletions.
+	pkOnly bool
+	ivars  schemaexpr.RowIndexedVarContainer
+}
+
+// makeRowFilter returns a rowFilter for the given changefeed options or nil if
+// neither `columns` nor `filter` was specified.
+func makeRowFilter(opts map[string]string, evalCtx *tree.EvalContext) (*rowFilter, error) {
+	columns, err := makeColumnProjection(opts)
+	if err != nil {
+		return nil, err
+	}
+	filter, hasFilter := opts[changefeedbase.OptFilter]
+	if columns == nil && !hasFilter {
+		return nil, nil
+	}
+	return &rowFilter{
+		columns:  columns,
+		filter:   filter,
+		evalCtx:  evalCtx,
+		compiled: make(map[idVersion]*compiledRowFilter),
+	}, nil
+}
+
+// compile returns the filter expression for the given table descriptor, or nil
+// if there is no filter.
+func (f *rowFilter) compile(
+	ctx context.Context, tableDesc catalog.TableDescriptor,
+) (*compiledRowFilter, error) {
+	if f.filter == `` {
+		return nil, nil
+	}
+	idVer := idVersion{id: tableDesc.GetID(), version: tableDesc.GetVersion()}
+	if c, ok := f.compiled[idVer]; ok {
+		return c, nil
+	}
+	cols := tableDesc.GetPublicColumns()
+	semaCtx := tree.MakeSemaContext()
+	expr, refCols, err := schemaexpr.MakeRowFilterExpr(
+		ctx, f.filter, `CHANGEFEED filter`, cols, tableDesc, f.evalCtx, &semaCtx)
+	if err != nil {
+		return nil, err
+	}
+	var pkCols catalog.TableColSet
+	primaryIndex := tableDesc.GetPrimaryIndex()
+	for i := 0; i < primaryIndex.NumColumns(); i++ {
+		pkCols.Add(primaryIndex.GetColumnID(i))
+	}
+	pkOnly := true
+	refCols.ForEach(func(id descpb.ColumnID) {
+		pkOnly = pkOnly && pkCols.Contains(id)
+	})
+	c := &compiledRowFilter{
+		expr:    expr,
+		refCols: refCols,
+		pkOnly:  pkOnly,
+		ivars: schemaexpr.RowIndexedVarContainer{
+			CurSourceRow: make(tree.Datums, len(cols)),
+			Cols:         cols,
+			Mapping:      tableDesc.ColumnIdxMap(),
+		},
+	}
+	// Bound the size of the cache by evicting the older versions of the table.
+	// Rows of an older version can still show up while a schema change is in
+	// progress, in which case the filter is compiled for it again and kept
+	// until a newer version comes along.
+	for cached := range f.compiled {
+		if cached.id == idVer.id && cached.version < idVer.version {
+			delete(f.compiled, cached)
+		}
+	}
+	f.compiled[idVer] = c
+	return c, nil
+}
+
+// neededColumns returns the ordinals of the columns of tableDesc that have to
+// be decoded: the primary key, the projected columns and the columns referenced
+// by the filter.
+func (f *rowFilter) neededColumns(
+	ctx context.Context, tableDesc catalog.TableDescriptor,
+) (util.FastIntSet, error) {
+	var needed util.FastIntSet
+	c, err := f.compile(ctx, tableDesc)
+	if err != nil {
+		return needed, err
+	}
+	colIdxMap := tableDesc.ColumnIdxMap()
+	primaryIndex := tableDesc.GetPrimaryIndex()
+	for i := 0; i < primaryIndex.NumColumns(); i++ {
+		if idx, ok := colIdxMap.Get(primaryIndex.GetColumnID(i)); ok {
+			needed.Add(idx)
+		}
+	}
+	for idx, col := range tableDesc.GetPublicColumns() {
+		if f.columns.includes(col.Name) || (c != nil && c.refCols.Contains(col.ID)) {
+			needed.Add(idx)
+		}
+	}
+	return needed, nil
+}
+
+// matches returns whether a version of a row passes the filter. Deletions only
+// have their primary key columns set, so they can only be filtered out if the
+// filter doesn't reference any other column; otherwise they're always emitted.
+func (f *rowFilter) matches(
+	ctx context.Context,
+	tableDesc catalog.TableDescriptor,
+	datums rowenc.EncDatumRow,
+	deleted bool,
+	alloc *rowenc.DatumAlloc,
+) (bool, error) {
+	c, err := f.compile(ctx, tableDesc)
+	if err != nil || c == nil {
+		return true, err
+	}
+	if deleted && !c.pkOnly {
+		return true, nil
+	}
+	var decodeErr error
+	c.refCols.ForEach(func(id descpb.ColumnID) {
+		idx, ok := c.ivars.Mapping.Get(id)
+		if !ok || decodeErr != nil {
+			return
+		}
+		if decodeErr = datums[idx].EnsureDecoded(c.ivars.Cols[idx].Type, alloc); decodeErr == nil {
+			c.ivars.CurSourceRow[idx] = datums[idx].Datum
+		}
+	})
+	if decodeErr != nil {
+		return false, decodeErr
+	}
+	f.evalCtx.PushIVarContainer(&c.ivars)
+	defer f.evalCtx.PopIVarContainer()
+	return schemaexpr.RunFilter(c.expr, f.evalCtx)
+}
@@ -41,6 +41,8 @@ type rowFetcherCache struct {
 
 	collection *descs.Collection
 	db         *kv.DB
+	// filter, if non-nil, restricts the columns that are decoded.
+	filter *rowFilter
 
 	a rowenc.DatumAlloc
 }
@@ -57,12 +59,14 @@ func newRowFetcherCache(
 	leaseMgr *lease.Manager,
 	hydratedTables *hydratedtables.Cache,
 	db *kv.DB,
+	filter *rowFilter,
 ) *rowFetcherCache {
 	return &rowFetcherCache{
 		codec:      codec,
 		leaseMgr:   leaseMgr,
 		collection: descs.NewCollection(settings, leaseMgr, hydratedTables),
 		db:         db,
+		filter:     filter,
 		fetchers:   make(map[idVersion]*row.Fetcher),
 	}
 }
@@ -140,7 +144,7 @@ func (c *rowFetcherCache) TableDescForKey(
 }
 
 func (c *rowFetcherCache) RowFetcherForTableDesc(
-	tableDesc *tabledesc.Immutable,
+	ctx context.Context, tableDesc *tabledesc.Immutable,
 ) (*row.Fetcher, error) {
 	idVer := idVersion{id: tableDesc.ID, version: tableDesc.Version}
 	// Ensure that all user defined types are up to date with the cached
@@ -152,17 +156,24 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 		tableDesc.UserDefinedTypeColsHaveSameVersion(rf.GetTables()[0].(*tabledesc.Immutable)) {
 		return rf, nil
 	}
-	// TODO(dan): Allow for decoding a subset of the columns.
 	var colIdxMap catalog.TableColMap
 	var valNeededForCol util.FastIntSet
 	for colIdx := range tableDesc.Columns {
 		colIdxMap.Set(tableDesc.Columns[colIdx].ID, colIdx)
 		valNeededForCol.Add(colIdx)
 	}
+	if c.filter != nil {
+		// Only decode the columns which are emitted or needed to evaluate the
+		// filter. The others are left NULL.
+		var err error
+		if valNeededForCol, err = c.filter.neededColumns(ctx, tableDesc); err != nil {
+			return nil, err
+		}
+	}
 
 	var rf row.Fetcher
 	if err := rf.Init(
-		context.TODO(),
+		ctx,
 		c.codec,
 		false, /* reverse */
 		descpb.ScanLockingStrength_FOR_NONE,
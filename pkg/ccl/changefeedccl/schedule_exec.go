@@ -0,0 +1,184 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+type scheduledChangefeedExecutor struct {
+	metrics jobs.ExecutorMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = &scheduledChangefeedExecutor{}
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledChangefeedExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	if err := e.executeChangefeed(ctx, cfg, sj, txn); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (e *scheduledChangefeedExecutor) executeChangefeed(
+	ctx context.Context, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	changefeedStmt, database, err := extractChangefeedStatement(sj)
+	if err != nil {
+		return err
+	}
+
+	// Sanity check: make sure the schedule is not paused (this shouldn't happen
+	// since job scheduler ignores paused schedules).
+	if sj.IsPaused() {
+		return errors.New("scheduled unexpectedly paused")
+	}
+
+	log.Infof(ctx, "Starting scheduled changefeed %d: %s",
+		sj.ScheduleID(), tree.AsString(changefeedStmt))
+
+	// Invoke changefeed plan hook.
+	hook, cleanup := cfg.PlanHookMaker("exec-changefeed", txn, sj.Owner())
+	defer cleanup()
+	p := hook.(sql.PlanHookState)
+	// Resolve the targets against the database in which the schedule was
+	// created.
+	if database != "" {
+		p.SessionData().Database = database
+	}
+	changefeedFn, err := planChangefeed(ctx, p, changefeedStmt)
+	if err != nil {
+		return err
+	}
+	return invokeChangefeed(ctx, changefeedFn)
+}
+
+func invokeChangefeed(ctx context.Context, changefeedFn sql.PlanHookRowFn) error {
+	resultCh := make(chan tree.Datums) // No need to close
+	g := ctxgroup.WithContext(ctx)
+
+	g.GoCtx(func(ctx context.Context) error {
+		select {
+		case <-resultCh:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	g.GoCtx(func(ctx context.Context) error {
+		return changefeedFn(ctx, nil, resultCh)
+	})
+
+	return g.Wait()
+}
+
+func planChangefeed(
+	ctx context.Context, p sql.PlanHookState, changefeedStmt *annotatedChangefeedStatement,
+) (sql.PlanHookRowFn, error) {
+	fn, _, _, _, err := changefeedPlanHook(ctx, changefeedStmt, p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "changefeed eval: %q", tree.AsString(changefeedStmt))
+	}
+	if fn == nil {
+		return nil, errors.Newf("changefeed eval: %q", tree.AsString(changefeedStmt))
+	}
+	return fn, nil
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledChangefeedExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID int64,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+		log.Infof(ctx, "changefeed job %d scheduled by %d succeeded", jobID, schedule.ScheduleID())
+		return nil
+	}
+
+	e.metrics.NumFailed.Inc(1)
+	err := errors.Errorf(
+		"changefeed job %d scheduled by %d failed with status %s",
+		jobID, schedule.ScheduleID(), jobStatus)
+	log.Errorf(ctx, "changefeed error: %v", err)
+	jobs.DefaultHandleFailedRun(schedule, "changefeed job %d failed with err=%v", jobID, err)
+	return nil
+}
+
+// Metrics implements ScheduledJobExecutor interface.
+func (e *scheduledChangefeedExecutor) Metrics() metric.Struct {
+	return &e.metrics
+}
+
+// extractChangefeedStatement returns tree.CreateChangefeed node encoded inside
+// scheduled job, along with the database in which the schedule was created.
+func extractChangefeedStatement(
+	sj *jobs.ScheduledJob,
+) (*annotatedChangefeedStatement, string, error) {
+	args := &jobspb.SqlStatementExecutionArg{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return nil, "", errors.Wrap(err, "un-marshaling args")
+	}
+
+	node, err := parser.ParseOne(args.Statement)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "parsing changefeed statement")
+	}
+
+	if changefeedStmt, ok := node.AST.(*tree.CreateChangefeed); ok {
+		return &annotatedChangefeedStatement{
+			CreateChangefeed: changefeedStmt,
+			CreatedByInfo: &jobs.CreatedByInfo{
+				Name: jobs.CreatedByScheduledJobs,
+				ID:   sj.ScheduleID(),
+			},
+		}, args.Database, nil
+	}
+
+	return nil, "", errors.Newf("unexpect node type %T", node)
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledChangefeedExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			return &scheduledChangefeedExecutor{
+				metrics: jobs.MakeExecutorMetrics(tree.ScheduledChangefeedExecutor.UserName()),
+			}, nil
+		})
+}
@@ -57,6 +57,11 @@ type Config struct {
 	Settings *cluster.Settings
 	Targets  jobspb.ChangefeedTargets
 
+	// Opts are the changefeed's options. Every new version of a target table is
+	// validated against the columns referenced by the `columns` and `filter`
+	// options.
+	Opts map[string]string
+
 	// SchemaChangeEvents controls the class of events which are emitted by this
 	// SchemaFeed.
 	SchemaChangeEvents changefeedbase.SchemaChangeEventClass
@@ -95,6 +100,7 @@ type SchemaFeed struct {
 	clock    *hlc.Clock
 	settings *cluster.Settings
 	targets  jobspb.ChangefeedTargets
+	opts     map[string]string
 	leaseMgr *lease.Manager
 	mu       struct {
 		syncutil.Mutex
@@ -205,6 +211,7 @@ func New(cfg Config) *SchemaFeed {
 		clock:    cfg.Clock,
 		settings: cfg.Settings,
 		targets:  cfg.Targets,
+		opts:     cfg.Opts,
 		leaseMgr: cfg.LeaseManager,
 	}
 	m.mu.previousTableVersion = make(map[descpb.ID]*tabledesc.Immutable)
@@ -499,6 +506,9 @@ func (tf *SchemaFeed) validateDescriptor(
 		if err := changefeedbase.ValidateTable(tf.targets, desc); err != nil {
 			return err
 		}
+		if err := changefeedbase.ValidateProjection(tf.opts, desc); err != nil {
+			return err
+		}
 		log.Infof(ctx, "validate %v", formatDesc(desc))
 		if lastVersion, ok := tf.mu.previousTableVersion[desc.ID]; ok {
 			// NB: Writes can occur to a table
@@ -36,11 +36,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
-	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
@@ -71,6 +71,7 @@ func getSink(
 	opts map[string]string,
 	targets jobspb.ChangefeedTargets,
 	settings *cluster.Settings,
+	ioConf base.ExternalIODirConfig,
 	timestampOracle timestampLowerBoundOracle,
 	makeExternalStorageFromURI cloud.ExternalStorageFromURIFactory,
 	user security.SQLUsername,
@@ -179,6 +180,28 @@ func getSink(
 		makeSink = func() (Sink, error) {
 			return makeKafkaSink(cfg, u.Host, targets)
 		}
+	case u.Scheme == changefeedbase.SinkSchemeGCPubsub:
+		cfg := defaultPubsubSinkConfig()
+		cfg.projectID = u.Host
+		cfg.topicPrefix = q.Get(changefeedbase.SinkParamTopicPrefix)
+		q.Del(changefeedbase.SinkParamTopicPrefix)
+		if endpoint := q.Get(changefeedbase.SinkParamEndpoint); endpoint != `` {
+			cfg.endpoint = strings.TrimSuffix(endpoint, `/`)
+		}
+		q.Del(changefeedbase.SinkParamEndpoint)
+		cfg.auth = q.Get(cloudimpl.AuthParam)
+		q.Del(cloudimpl.AuthParam)
+		cfg.credentials = q.Get(cloudimpl.CredentialsParam)
+		q.Del(cloudimpl.CredentialsParam)
+		if batchSize := q.Get(changefeedbase.SinkParamBatchSize); batchSize != `` {
+			if cfg.batchSize, err = strconv.Atoi(batchSize); err != nil {
+				return nil, errors.Errorf(`param %s must be an integer: %s`, changefeedbase.SinkParamBatchSize, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamBatchSize)
+		makeSink = func() (Sink, error) {
+			return makePubsubSink(ctx, cfg, targets, ioConf, settings)
+		}
 	case isCloudStorageSink(u):
 		fileSizeParam := q.Get(changefeedbase.SinkParamFileSize)
 		q.Del(changefeedbase.SinkParamFileSize)
@@ -199,6 +222,59 @@ func getSink(
 				opts, timestampOracle, makeExternalStorageFromURI, user,
 			)
 		}
+	case isWebhookSink(u):
+		cfg := defaultWebhookSinkConfig()
+		if tlsVerifyBool := q.Get(changefeedbase.SinkParamSkipTLSVerify); tlsVerifyBool != `` {
+			if cfg.tlsSkipVerify, err = strconv.ParseBool(tlsVerifyBool); err != nil {
+				return nil, errors.Errorf(`param %s must be a bool: %s`, changefeedbase.SinkParamSkipTLSVerify, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamSkipTLSVerify)
+		for param, dest := range map[string]*[]byte{
+			changefeedbase.SinkParamCACert:     &cfg.caCert,
+			changefeedbase.SinkParamClientCert: &cfg.clientCert,
+			changefeedbase.SinkParamClientKey:  &cfg.clientKey,
+		} {
+			if encoded := q.Get(param); encoded != `` {
+				if *dest, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+					return nil, errors.Errorf(`param %s must be base 64 encoded: %s`, param, err)
+				}
+			}
+			q.Del(param)
+		}
+		if batchSize := q.Get(changefeedbase.SinkParamBatchSize); batchSize != `` {
+			if cfg.batchSize, err = strconv.Atoi(batchSize); err != nil {
+				return nil, errors.Errorf(`param %s must be an integer: %s`, changefeedbase.SinkParamBatchSize, err)
+			}
+		}
+		q.Del(changefeedbase.SinkParamBatchSize)
+		if maxRetries := q.Get(changefeedbase.SinkParamMaxRetries); maxRetries != `` {
+			if cfg.retryOpts.MaxRetries, err = strconv.Atoi(maxRetries); err != nil {
+				return nil, errors.Errorf(`param %s must be an integer: %s`, changefeedbase.SinkParamMaxRetries, err)
+			}
+			if cfg.retryOpts.MaxRetries < 0 {
+				return nil, errors.Errorf(`param %s must not be negative`, changefeedbase.SinkParamMaxRetries)
+			}
+		}
+		q.Del(changefeedbase.SinkParamMaxRetries)
+		for param, dest := range map[string]*time.Duration{
+			changefeedbase.SinkParamFlushInterval: &cfg.flushInterval,
+			changefeedbase.SinkParamClientTimeout: &cfg.clientTimeout,
+		} {
+			if d := q.Get(param); d != `` {
+				if *dest, err = time.ParseDuration(d); err != nil {
+					return nil, errors.Errorf(`param %s must be a duration: %s`, param, err)
+				}
+			}
+			q.Del(param)
+		}
+		// Every query parameter is consumed by the sink (and unknown ones are
+		// rejected below), so none of them are sent to the endpoint.
+		webhookURL := *u
+		webhookURL.RawQuery = ``
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(ctx, &webhookURL, cfg, opts)
+		}
 	case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 		// Swap the changefeed prefix for the sql connection one that sqlSink
 		// expects.
@@ -312,7 +388,7 @@ type kafkaSink struct {
 	cfg      kafkaSinkConfig
 	client   sarama.Client
 	producer sarama.AsyncProducer
-	topics   map[string]struct{}
+	topics   *topicNamer
 
 	lastMetadataRefresh time.Time
 
@@ -321,22 +397,14 @@ type kafkaSink struct {
 	scratch      bufalloc.ByteAllocator
 
 	// Only synchronized between the client goroutine and the worker goroutine.
-	mu struct {
-		syncutil.Mutex
-		inflight int64
-		flushErr error
-		flushCh  chan struct{}
-	}
+	inflight inflightTracker
 }
 
 func makeKafkaSink(
 	cfg kafkaSinkConfig, bootstrapServers string, targets jobspb.ChangefeedTargets,
 ) (Sink, error) {
 	sink := &kafkaSink{cfg: cfg}
-	sink.topics = make(map[string]struct{})
-	for _, t := range targets {
-		sink.topics[cfg.kafkaTopicPrefix+SQLNameToKafkaName(t.StatementTimeName)] = struct{}{}
-	}
+	sink.topics = makeTopicNamer(targets, cfg.kafkaTopicPrefix, SQLNameToKafkaName)
 
 	config := sarama.NewConfig()
 	config.ClientID = `CockroachDB`
@@ -465,9 +533,9 @@ func (s *kafkaSink) Close() error {
 func (s *kafkaSink) EmitRow(
 	ctx context.Context, table catalog.TableDescriptor, key, value []byte, updated hlc.Timestamp,
 ) error {
-	topic := s.cfg.kafkaTopicPrefix + SQLNameToKafkaName(table.GetName())
-	if _, ok := s.topics[topic]; !ok {
-		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	topic, err := s.topics.topicForTable(table)
+	if err != nil {
+		return err
 	}
 
 	msg := &sarama.ProducerMessage{
@@ -491,17 +559,13 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 	// actively working on stability. At the same time, revisit this tuning.
 	const metadataRefreshMinDuration = time.Minute
 	if timeutil.Since(s.lastMetadataRefresh) > metadataRefreshMinDuration {
-		topics := make([]string, 0, len(s.topics))
-		for topic := range s.topics {
-			topics = append(topics, topic)
-		}
-		if err := s.client.RefreshMetadata(topics...); err != nil {
+		if err := s.client.RefreshMetadata(s.topics.names()...); err != nil {
 			return err
 		}
 		s.lastMetadataRefresh = timeutil.Now()
 	}
 
-	for topic := range s.topics {
+	for _, topic := range s.topics.names() {
 		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
 		if err != nil {
 			return err
@@ -533,42 +597,11 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 
 // Flush implements the Sink interface.
 func (s *kafkaSink) Flush(ctx context.Context) error {
-	flushCh := make(chan struct{}, 1)
-
-	s.mu.Lock()
-	inflight := s.mu.inflight
-	flushErr := s.mu.flushErr
-	s.mu.flushErr = nil
-	immediateFlush := inflight == 0 || flushErr != nil
-	if !immediateFlush {
-		s.mu.flushCh = flushCh
-	}
-	s.mu.Unlock()
-
-	if immediateFlush {
-		return flushErr
-	}
-
-	if log.V(1) {
-		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
-	}
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-flushCh:
-		s.mu.Lock()
-		flushErr := s.mu.flushErr
-		s.mu.flushErr = nil
-		s.mu.Unlock()
-		return flushErr
-	}
+	return s.inflight.flush(ctx)
 }
 
 func (s *kafkaSink) emitMessage(ctx context.Context, msg *sarama.ProducerMessage) error {
-	s.mu.Lock()
-	s.mu.inflight++
-	inflight := s.mu.inflight
-	s.mu.Unlock()
+	inflight := s.inflight.add()
 
 	select {
 	case <-ctx.Done():
@@ -590,21 +623,10 @@ func (s *kafkaSink) workerLoop() {
 		case <-s.stopWorkerCh:
 			return
 		case <-s.producer.Successes():
+			s.inflight.done(nil)
 		case err := <-s.producer.Errors():
-			s.mu.Lock()
-			if s.mu.flushErr == nil {
-				s.mu.flushErr = err
-			}
-			s.mu.Unlock()
-		}
-
-		s.mu.Lock()
-		s.mu.inflight--
-		if s.mu.inflight == 0 && s.mu.flushCh != nil {
-			s.mu.flushCh <- struct{}{}
-			s.mu.flushCh = nil
+			s.inflight.done(err)
 		}
-		s.mu.Unlock()
 	}
 }
 
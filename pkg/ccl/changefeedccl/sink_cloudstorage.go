@@ -162,9 +162,9 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // by a given `<sink_id>` and <session_id> is a unique identifying string for the job
 // session running the `changeAggregator` that owns this sink.
 //
-// `<ext>` implies the format of the file: currently the only option is
-// `ndjson`, which means a text file conforming to the "Newline Delimited JSON"
-// spec.
+// `<ext>` implies the format of the file: either `ndjson`, which means a text
+// file conforming to the "Newline Delimited JSON" spec, or `csv`, which means
+// RFC 4180 CSV without a header row.
 //
 // This naming convention of data files is carefully chosen in order to preserve
 // the external ordering guarantees of CDC. Naming output files in this fashion
@@ -274,6 +274,9 @@ type cloudStorageSink struct {
 
 	ext           string
 	recordDelimFn func(io.Writer) error
+	// skipEmptyValues is set for formats that have no way to represent a
+	// deletion, whose encoders return an empty value for them.
+	skipEmptyValues bool
 
 	compression string
 
@@ -339,22 +342,31 @@ func makeCloudStorageSink(
 			_, err := w.Write([]byte{'\n'})
 			return err
 		}
+		switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+		case changefeedbase.OptEnvelopeWrapped:
+		default:
+			return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+				changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
+		}
+		if _, ok := opts[changefeedbase.OptKeyInValue]; !ok {
+			return nil, errors.Errorf(`this sink requires the WITH %s option`, changefeedbase.OptKeyInValue)
+		}
+	case changefeedbase.OptFormatCSV:
+		// The csv encoder terminates each record itself.
+		s.ext = `.csv`
+		s.recordDelimFn = func(io.Writer) error { return nil }
+		s.skipEmptyValues = true
+		switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+		case changefeedbase.OptEnvelopeRow:
+		default:
+			return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+				changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
+		}
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
 
-	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
-	case changefeedbase.OptEnvelopeWrapped:
-	default:
-		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
-			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
-	}
-
-	if _, ok := opts[changefeedbase.OptKeyInValue]; !ok {
-		return nil, errors.Errorf(`this sink requires the WITH %s option`, changefeedbase.OptKeyInValue)
-	}
-
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
 		if strings.EqualFold(codec, "gzip") {
 			s.compression = sinkCompressionGzip
@@ -397,6 +409,9 @@ func (s *cloudStorageSink) EmitRow(
 	if s.files == nil {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
+	if s.skipEmptyValues && len(value) == 0 {
+		return nil
+	}
 
 	file := s.getOrCreateFile(table.GetName(), table.GetVersion())
 
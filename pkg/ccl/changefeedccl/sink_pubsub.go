@@ -0,0 +1,401 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	gojson "encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	pubsubDefaultEndpoint = `https://pubsub.googleapis.com`
+	pubsubScope           = `https://www.googleapis.com/auth/pubsub`
+
+	// pubsubMaxBatchSize is the most messages Pub/Sub accepts in a single
+	// publish request.
+	pubsubMaxBatchSize     = 1000
+	pubsubDefaultBatchSize = 100
+	pubsubClientTimeout    = 30 * time.Second
+)
+
+type pubsubSinkConfig struct {
+	projectID   string
+	topicPrefix string
+	// endpoint is the scheme and host requests are sent to. It's only
+	// overridden to point the sink at the Pub/Sub emulator.
+	endpoint    string
+	auth        string
+	credentials string
+	batchSize   int
+	retryOpts   retry.Options
+}
+
+func defaultPubsubSinkConfig() pubsubSinkConfig {
+	return pubsubSinkConfig{
+		endpoint:  pubsubDefaultEndpoint,
+		batchSize: pubsubDefaultBatchSize,
+		retryOpts: retry.Options{
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     10 * time.Second,
+			Multiplier:     2,
+			MaxRetries:     5,
+		},
+	}
+}
+
+// pubsubMessage is the JSON representation of a PubsubMessage in the Pub/Sub
+// REST API. Data is base64 encoded by encoding/json.
+type pubsubMessage struct {
+	Data        []byte `json:"data"`
+	OrderingKey string `json:"orderingKey,omitempty"`
+}
+
+type pubsubPublishRequest struct {
+	Messages []pubsubMessage `json:"messages"`
+}
+
+// pubsubWork is handed from the emitting goroutine to the worker goroutine.
+// Exactly one of msg and flushCh is set.
+type pubsubWork struct {
+	topic string
+	msg   pubsubMessage
+	// flushCh, if set, asks the worker to publish everything it has buffered
+	// and then close flushCh.
+	flushCh chan struct{}
+}
+
+// pubsubSink emits to Google Cloud Pub/Sub using its REST API. Rows are
+// published to the topic named after their table, as with kafkaSink, and each
+// resolved timestamp is published once to every topic.
+//
+// Messages are buffered per topic and published in batches by a single worker
+// goroutine, one request at a time, so every message is delivered in the order
+// it was emitted. The encoded key of each row is used as its ordering key, so
+// subscriptions with message ordering enabled observe the same per-key
+// ordering as a kafka consumer would.
+//
+// Like kafkaSink, it is not concurrency-safe; all calls to Emit and Flush
+// should be from the same goroutine.
+type pubsubSink struct {
+	cfg    pubsubSinkConfig
+	client *httputil.Client
+	topics *topicNamer
+
+	workCh    chan pubsubWork
+	stopCh    chan struct{}
+	closeOnce sync.Once
+	cancel    context.CancelFunc
+	worker    sync.WaitGroup
+	inflight  inflightTracker
+}
+
+var _ Sink = (*pubsubSink)(nil)
+
+var errPubsubSinkClosed = errors.New(`pubsub sink is closed`)
+
+func makePubsubSink(
+	ctx context.Context,
+	cfg pubsubSinkConfig,
+	targets jobspb.ChangefeedTargets,
+	ioConf base.ExternalIODirConfig,
+	settings *cluster.Settings,
+) (Sink, error) {
+	if cfg.projectID == `` {
+		return nil, errors.New(`the Pub/Sub project must be specified as the host of the sink URI`)
+	}
+	if cfg.batchSize <= 0 || cfg.batchSize > pubsubMaxBatchSize {
+		return nil, errors.Errorf(`%s must be between 1 and %d`,
+			changefeedbase.SinkParamBatchSize, pubsubMaxBatchSize)
+	}
+
+	topics := makeTopicNamer(targets, cfg.topicPrefix, SQLNameToPubsubName)
+	for _, topic := range topics.names() {
+		if err := validatePubsubTopicName(topic); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := makePubsubClient(ctx, cfg, ioConf, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &pubsubSink{
+		cfg:    cfg,
+		client: client,
+		topics: topics,
+		workCh: make(chan pubsubWork),
+		stopCh: make(chan struct{}),
+	}
+	var workerCtx context.Context
+	workerCtx, sink.cancel = context.WithCancel(context.Background())
+	workerCtx = logtags.WithTags(workerCtx, logtags.FromContext(ctx))
+	sink.worker.Add(1)
+	go sink.workerLoop(workerCtx)
+	return sink, nil
+}
+
+// validatePubsubTopicName checks the restrictions on Pub/Sub topic names which
+// SQLNameToPubsubName can't enforce on its own, since they depend on the
+// topic prefix.
+func validatePubsubTopicName(topic string) error {
+	switch {
+	case len(topic) < 3 || len(topic) > 255:
+		return errors.Errorf(`Pub/Sub topic %q must be between 3 and 255 characters long`, topic)
+	case !((topic[0] >= 'a' && topic[0] <= 'z') || (topic[0] >= 'A' && topic[0] <= 'Z')):
+		return errors.Errorf(`Pub/Sub topic %q must start with a letter, consider setting %s`,
+			topic, changefeedbase.SinkParamTopicPrefix)
+	case strings.HasPrefix(topic, `goog`):
+		return errors.Errorf(`Pub/Sub topic %q must not start with "goog"`, topic)
+	}
+	return nil
+}
+
+// makePubsubClient returns an HTTP client which authenticates its requests
+// according to the AUTH and CREDENTIALS sink parameters. These are interpreted
+// as they are for Google Cloud Storage URIs: an empty AUTH uses the key in the
+// cloudstorage.gs.default.key setting if there is one and the node's implicit
+// credentials otherwise, and anything other than AUTH=specified is rejected if
+// implicit credentials are disabled. The one exception is that if the endpoint
+// was overridden and no AUTH was given, requests are unauthenticated, which is
+// what the emulator expects.
+func makePubsubClient(
+	ctx context.Context,
+	cfg pubsubSinkConfig,
+	ioConf base.ExternalIODirConfig,
+	settings *cluster.Settings,
+) (*httputil.Client, error) {
+	client := httputil.NewClientWithTimeout(pubsubClientTimeout)
+	if cfg.auth == `` && cfg.endpoint != pubsubDefaultEndpoint {
+		return client, nil
+	}
+	if ioConf.DisableImplicitCredentials && cfg.auth != cloudimpl.AuthParamSpecified {
+		return nil, errors.New(
+			`implicit credentials disallowed for gcpubsub due to --external-io-disable-implicit-credentials flag`)
+	}
+
+	var ts oauth2.TokenSource
+	switch cfg.auth {
+	case ``, cloudimpl.AuthParamDefault:
+		var key string
+		if settings != nil {
+			key = cloudimpl.GcsDefault.Get(&settings.SV)
+		}
+		if key == `` {
+			if cfg.auth == cloudimpl.AuthParamDefault {
+				return nil, errors.Errorf(`expected settings value for %s`, cloudimpl.CloudstorageGSDefaultKey)
+			}
+			var err error
+			if ts, err = google.DefaultTokenSource(ctx, pubsubScope); err != nil {
+				return nil, errors.Wrap(err, `creating Pub/Sub oauth token source`)
+			}
+			break
+		}
+		jwtConfig, err := google.JWTConfigFromJSON([]byte(key), pubsubScope)
+		if err != nil {
+			return nil, errors.Wrap(err, `creating Pub/Sub oauth token source`)
+		}
+		// The token source outlives the statement that created the sink.
+		ts = jwtConfig.TokenSource(context.Background())
+	case cloudimpl.AuthParamImplicit:
+		var err error
+		if ts, err = google.DefaultTokenSource(ctx, pubsubScope); err != nil {
+			return nil, errors.Wrap(err, `creating Pub/Sub oauth token source`)
+		}
+	case cloudimpl.AuthParamSpecified:
+		if cfg.credentials == `` {
+			return nil, errors.Errorf(`%s is set to '%s', but %s is not set`,
+				cloudimpl.AuthParam, cloudimpl.AuthParamSpecified, cloudimpl.CredentialsParam)
+		}
+		decodedKey, err := base64.StdEncoding.DecodeString(cfg.credentials)
+		if err != nil {
+			return nil, errors.Wrapf(err, `decoding value of %s`, cloudimpl.CredentialsParam)
+		}
+		jwtConfig, err := google.JWTConfigFromJSON(decodedKey, pubsubScope)
+		if err != nil {
+			return nil, errors.Wrap(err, `creating Pub/Sub oauth token source from specified credentials`)
+		}
+		// The token source outlives the statement that created the sink.
+		ts = jwtConfig.TokenSource(context.Background())
+	default:
+		return nil, errors.Errorf(`unsupported value %s for %s`, cfg.auth, cloudimpl.AuthParam)
+	}
+	client.Transport = &oauth2.Transport{Source: ts, Base: client.Transport}
+	return client, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *pubsubSink) EmitRow(
+	ctx context.Context, table catalog.TableDescriptor, key, value []byte, _ hlc.Timestamp,
+) error {
+	topic, err := s.topics.topicForTable(table)
+	if err != nil {
+		return err
+	}
+	// The caller is free to reuse key and value once we return, so take
+	// copies.
+	msg := pubsubMessage{
+		Data:        append([]byte(nil), value...),
+		OrderingKey: string(key),
+	}
+	return s.emit(ctx, pubsubWork{topic: topic, msg: msg})
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *pubsubSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	for _, topic := range s.topics.names() {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		if err := s.emit(ctx, pubsubWork{topic: topic, msg: pubsubMessage{Data: payload}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *pubsubSink) Flush(ctx context.Context) error {
+	flushCh := make(chan struct{})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.stopCh:
+		return errPubsubSinkClosed
+	case s.workCh <- pubsubWork{flushCh: flushCh}:
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.stopCh:
+		return errPubsubSinkClosed
+	case <-flushCh:
+	}
+	return s.inflight.flush(ctx)
+}
+
+// Close implements the Sink interface. It is safe to call more than once.
+func (s *pubsubSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		s.cancel()
+		s.worker.Wait()
+		s.client.CloseIdleConnections()
+	})
+	return nil
+}
+
+func (s *pubsubSink) emit(ctx context.Context, work pubsubWork) error {
+	inflight := s.inflight.add()
+	select {
+	case <-ctx.Done():
+		s.inflight.done(ctx.Err())
+		return ctx.Err()
+	case <-s.stopCh:
+		s.inflight.done(errPubsubSinkClosed)
+		return errPubsubSinkClosed
+	case s.workCh <- work:
+	}
+	if log.V(2) {
+		log.Infof(ctx, "emitted %d inflight records to pubsub", inflight)
+	}
+	return nil
+}
+
+func (s *pubsubSink) workerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	// Topics are published in the order they first received a message since
+	// the last publish. Within a topic, messages are published in the order
+	// they were emitted.
+	var order []string
+	batches := make(map[string][]pubsubMessage)
+	publish := func(topic string) {
+		batch := batches[topic]
+		err := s.publish(ctx, topic, batch)
+		for range batch {
+			s.inflight.done(err)
+		}
+		delete(batches, topic)
+	}
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case work := <-s.workCh:
+			if work.flushCh != nil {
+				for _, topic := range order {
+					publish(topic)
+				}
+				order = order[:0]
+				close(work.flushCh)
+				continue
+			}
+			batch, ok := batches[work.topic]
+			if !ok {
+				order = append(order, work.topic)
+			}
+			batches[work.topic] = append(batch, work.msg)
+			if len(batches[work.topic]) >= s.cfg.batchSize {
+				publish(work.topic)
+				for i := range order {
+					if order[i] == work.topic {
+						order = append(order[:i], order[i+1:]...)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func (s *pubsubSink) publish(ctx context.Context, topic string, batch []pubsubMessage) error {
+	body, err := gojson.Marshal(pubsubPublishRequest{Messages: batch})
+	if err != nil {
+		return err
+	}
+	publishURL := fmt.Sprintf(`%s/v1/projects/%s/topics/%s:publish`,
+		s.cfg.endpoint, url.PathEscape(s.cfg.projectID), url.PathEscape(topic))
+	for r := retry.StartWithCtx(ctx, s.cfg.retryOpts); r.Next(); {
+		if err = postJSON(ctx, s.client, publishURL, body); err == nil || !isRetryableHTTPError(err) {
+			return errors.Wrapf(err, `publishing to Pub/Sub topic %s`, topic)
+		}
+		log.Warningf(ctx, `retrying publish to Pub/Sub topic %s: %v`, topic, err)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.Wrapf(err, `publishing to Pub/Sub topic %s failed after %d retries`,
+		topic, s.cfg.retryOpts.MaxRetries)
+}
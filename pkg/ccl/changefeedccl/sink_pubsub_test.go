@@ -0,0 +1,178 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePubsubEmulator implements the publish method of the Pub/Sub emulator's
+// REST API for a fixed set of topics.
+type fakePubsubEmulator struct {
+	syncutil.Mutex
+	project  string
+	messages map[string][]pubsubMessage
+	requests int
+}
+
+var pubsubPublishPathRE = regexp.MustCompile(`^/v1/projects/([^/]+)/topics/([^/]+):publish$`)
+
+func (e *fakePubsubEmulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m := pubsubPublishPathRE.FindStringSubmatch(r.URL.Path)
+	if r.Method != http.MethodPost || m == nil || m[1] != e.project {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var req pubsubPublishRequest
+	if err := gojson.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	e.Lock()
+	defer e.Unlock()
+	msgs, ok := e.messages[m[2]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	e.requests++
+	e.messages[m[2]] = append(msgs, req.Messages...)
+	_, _ = w.Write([]byte(`{"messageIds":[]}`))
+}
+
+func TestPubsubSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	foo := tabledesc.NewImmutable(descpb.TableDescriptor{ID: 52, Name: `foo`})
+	bar := tabledesc.NewImmutable(descpb.TableDescriptor{ID: 53, Name: `bar`})
+	targets := jobspb.ChangefeedTargets{
+		foo.GetID(): {StatementTimeName: `foo`},
+		bar.GetID(): {StatementTimeName: `bar`},
+	}
+
+	emulator := &fakePubsubEmulator{
+		project: `p`,
+		messages: map[string][]pubsubMessage{
+			`cdc_foo`: {},
+			`cdc_bar`: {},
+		},
+	}
+	ts := httptest.NewServer(emulator)
+	defer ts.Close()
+
+	makeSinkWithIOConf := func(params url.Values, ioConf base.ExternalIODirConfig) (Sink, error) {
+		params.Set(changefeedbase.SinkParamEndpoint, ts.URL)
+		u := url.URL{Scheme: changefeedbase.SinkSchemeGCPubsub, Host: `p`, RawQuery: params.Encode()}
+		return getSink(
+			ctx, u.String(), 0 /* srcID */, nil /* opts */, targets,
+			nil /* settings */, ioConf, nil, /* timestampOracle */
+			nil, /* makeExternalStorageFromURI */
+			security.RootUserName(),
+		)
+	}
+	makeSink := func(params url.Values) (Sink, error) {
+		return makeSinkWithIOConf(params, base.ExternalIODirConfig{})
+	}
+
+	sink, err := makeSink(url.Values{
+		changefeedbase.SinkParamTopicPrefix: {`cdc_`},
+		changefeedbase.SinkParamBatchSize:   {`2`},
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	// Nothing inflight.
+	require.NoError(t, sink.Flush(ctx))
+
+	require.NoError(t, sink.EmitRow(ctx, foo, []byte(`[1]`), []byte(`v1`), zeroTS))
+	require.NoError(t, sink.EmitRow(ctx, bar, []byte(`[2]`), []byte(`v2`), zeroTS))
+	require.NoError(t, sink.EmitRow(ctx, foo, []byte(`[1]`), []byte(`v3`), zeroTS))
+	require.NoError(t, sink.EmitRow(ctx, foo, []byte(`[3]`), []byte(`v4`), zeroTS))
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, testEncoder{}, hlc.Timestamp{WallTime: 1}))
+	require.NoError(t, sink.Flush(ctx))
+
+	resolved := hlc.Timestamp{WallTime: 1}.String()
+	emulator.Lock()
+	require.Equal(t, []pubsubMessage{
+		{Data: []byte(`v1`), OrderingKey: `[1]`},
+		{Data: []byte(`v3`), OrderingKey: `[1]`},
+		{Data: []byte(`v4`), OrderingKey: `[3]`},
+		{Data: []byte(resolved)},
+	}, emulator.messages[`cdc_foo`])
+	require.Equal(t, []pubsubMessage{
+		{Data: []byte(`v2`), OrderingKey: `[2]`},
+		{Data: []byte(resolved)},
+	}, emulator.messages[`cdc_bar`])
+	// Every batch filled up before Flush, so it had nothing left to publish.
+	require.Equal(t, 3, emulator.requests)
+	emulator.Unlock()
+
+	// Renamed tables are caught.
+	baz := tabledesc.NewImmutable(descpb.TableDescriptor{ID: 52, Name: `baz`})
+	require.Regexp(t, `cannot emit to undeclared topic: cdc_baz`,
+		sink.EmitRow(ctx, baz, nil, []byte(`v`), zeroTS))
+
+	// A topic which doesn't exist is reported on Flush.
+	noTopicSink, err := makeSink(url.Values{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, noTopicSink.Close()) }()
+	require.NoError(t, noTopicSink.EmitRow(ctx, foo, []byte(`[1]`), []byte(`v1`), zeroTS))
+	require.Regexp(t, `publishing to Pub/Sub topic foo: unexpected HTTP response: 404`,
+		noTopicSink.Flush(ctx))
+	// Close can be called again by the deferred cleanup.
+	require.NoError(t, noTopicSink.Close())
+
+	// A closed sink rejects anything further instead of blocking.
+	require.Regexp(t, `pubsub sink is closed`,
+		noTopicSink.EmitRow(ctx, foo, []byte(`[1]`), []byte(`v1`), zeroTS))
+	require.Regexp(t, `pubsub sink is closed`, noTopicSink.Flush(ctx))
+
+	// Only specified credentials may be used if implicit credentials are
+	// disabled.
+	disableImplicit := base.ExternalIODirConfig{DisableImplicitCredentials: true}
+	for _, auth := range []string{`implicit`, `default`} {
+		_, err := makeSinkWithIOConf(url.Values{`AUTH`: {auth}}, disableImplicit)
+		require.Regexp(t, `implicit credentials disallowed`, err)
+	}
+
+	for _, tc := range []struct {
+		params url.Values
+		err    string
+	}{
+		{url.Values{changefeedbase.SinkParamBatchSize: {`0`}}, `batch_size must be between 1 and 1000`},
+		{url.Values{changefeedbase.SinkParamTopicPrefix: {`1`}}, `Pub/Sub topic "1bar" must start with a letter`},
+		{url.Values{changefeedbase.SinkParamTopicPrefix: {`goog`}}, `must not start with "goog"`},
+		{url.Values{`AUTH`: {`specified`}}, `AUTH is set to 'specified', but CREDENTIALS is not set`},
+		{url.Values{`AUTH`: {`default`}}, `expected settings value for cloudstorage.gs.default.key`},
+		{url.Values{`AUTH`: {`nope`}}, `unsupported value nope for AUTH`},
+	} {
+		_, err := makeSink(tc.params)
+		require.Regexp(t, tc.err, err)
+	}
+}
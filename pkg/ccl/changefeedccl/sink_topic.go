@@ -0,0 +1,148 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// This file contains the pieces shared by the sinks which emit to a message
+// queue with named topics (kafkaSink and pubsubSink). Such a sink is made of a
+// topicNamer, which decides which topic each table's rows go to, and a
+// transport-specific producer that delivers messages asynchronously and
+// reports the outcome of each one to an inflightTracker, which is what
+// implements the Sink's Flush semantics.
+//
+// Both sinks preserve the ordering of messages with the same key that are
+// emitted to the same topic: kafka by hashing the key to a partition, pubsub
+// by publishing every message in order from a single goroutine.
+
+// topicNamer maps the tables watched by a changefeed to topic names. Every
+// topic is declared up front from the changefeed's targets, so that a table
+// which was renamed since the changefeed started is caught instead of silently
+// creating a new topic.
+type topicNamer struct {
+	prefix string
+	escape func(string) string
+	topics map[string]struct{}
+}
+
+func makeTopicNamer(
+	targets jobspb.ChangefeedTargets, prefix string, escape func(string) string,
+) *topicNamer {
+	n := &topicNamer{
+		prefix: prefix,
+		escape: escape,
+		topics: make(map[string]struct{}, len(targets)),
+	}
+	for _, t := range targets {
+		n.topics[n.topicName(t.StatementTimeName)] = struct{}{}
+	}
+	return n
+}
+
+func (n *topicNamer) topicName(tableName string) string {
+	return n.prefix + n.escape(tableName)
+}
+
+// topicForTable returns the topic that rows of the given table are emitted to.
+// It returns an error if the topic wasn't declared when the sink was created.
+func (n *topicNamer) topicForTable(table catalog.TableDescriptor) (string, error) {
+	topic := n.topicName(table.GetName())
+	if _, ok := n.topics[topic]; !ok {
+		return ``, errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	}
+	return topic, nil
+}
+
+// names returns every declared topic, in sorted order.
+func (n *topicNamer) names() []string {
+	names := make([]string, 0, len(n.topics))
+	for topic := range n.topics {
+		names = append(names, topic)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// inflightTracker counts the messages that have been handed to an asynchronous
+// producer but not yet acknowledged, and remembers the first error any of them
+// returned. It is safe for concurrent use by the goroutine emitting messages
+// and the one receiving acknowledgements.
+type inflightTracker struct {
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+// add records that one more message is in flight and returns the new count.
+func (t *inflightTracker) add() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mu.inflight++
+	return t.mu.inflight
+}
+
+// done records that a message was acknowledged, successfully if err is nil.
+func (t *inflightTracker) done(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil && t.mu.flushErr == nil {
+		t.mu.flushErr = err
+	}
+	t.mu.inflight--
+	if t.mu.inflight == 0 && t.mu.flushCh != nil {
+		t.mu.flushCh <- struct{}{}
+		t.mu.flushCh = nil
+	}
+}
+
+// flush blocks until every message passed to add has been passed to done and
+// returns the first error seen since the last flush, if any.
+func (t *inflightTracker) flush(ctx context.Context) error {
+	flushCh := make(chan struct{}, 1)
+
+	t.mu.Lock()
+	inflight := t.mu.inflight
+	flushErr := t.mu.flushErr
+	t.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		t.mu.flushCh = flushCh
+	}
+	t.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		t.mu.Lock()
+		flushErr := t.mu.flushErr
+		t.mu.flushErr = nil
+		t.mu.Unlock()
+		return flushErr
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/httputil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+const (
+	applicationTypeJSON = `application/json`
+
+	defaultWebhookBatchSize     = 100
+	defaultWebhookFlushInterval = time.Second
+	defaultWebhookClientTimeout = 3 * time.Second
+	defaultWebhookMaxRetries    = 3
+)
+
+func isWebhookSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeWebhookHTTPS
+}
+
+type webhookSinkConfig struct {
+	tlsSkipVerify bool
+	caCert        []byte
+	clientCert    []byte
+	clientKey     []byte
+
+	// batchSize is the maximum number of rows sent in a single request.
+	batchSize int
+	// flushInterval is how long a partial batch may sit in the sink before
+	// it is sent even though it isn't full.
+	flushInterval time.Duration
+	// clientTimeout bounds each individual request. Every retry of a request
+	// gets its own timeout.
+	clientTimeout time.Duration
+	// retryOpts configures the backoff used when a request fails with a
+	// retryable error.
+	retryOpts retry.Options
+}
+
+func defaultWebhookSinkConfig() webhookSinkConfig {
+	return webhookSinkConfig{
+		batchSize:     defaultWebhookBatchSize,
+		flushInterval: defaultWebhookFlushInterval,
+		clientTimeout: defaultWebhookClientTimeout,
+		retryOpts: retry.Options{
+			InitialBackoff: 500 * time.Millisecond,
+			MaxBackoff:     10 * time.Second,
+			Multiplier:     2,
+			MaxRetries:     defaultWebhookMaxRetries,
+		},
+	}
+}
+
+// webhookSinkPayload is the body of every request containing rows sent by the
+// webhook sink. Each element of Payload is one encoded row.
+type webhookSinkPayload struct {
+	Payload []gojson.RawMessage `json:"payload"`
+	Length  int                 `json:"length"`
+}
+
+// webhookMessage is either a row or a resolved timestamp handed from the
+// emitting goroutine to the worker goroutine.
+type webhookMessage struct {
+	row      []byte
+	resolved []byte
+	// flushCh, if set, indicates a request to send everything buffered and
+	// report the result of doing so.
+	flushCh chan error
+}
+
+// webhookSink emits to an HTTPS endpoint by POSTing JSON bodies. Rows are
+// batched up to a configured size or flush interval, whichever comes first.
+// Resolved timestamps are sent in their own request after every row emitted
+// before them has been sent.
+//
+// All requests are made by a single worker goroutine, in the order the
+// messages were emitted, so the ordering guarantees offered by the kafka sink
+// for rows with the same key hold here as well. Like kafkaSink, it is not
+// concurrency-safe; all calls to Emit and Flush should be from the same
+// goroutine.
+type webhookSink struct {
+	cfg    webhookSinkConfig
+	url    string
+	client *httputil.Client
+
+	inputCh   chan webhookMessage
+	stopCh    chan struct{}
+	closeOnce sync.Once
+	cancel    context.CancelFunc
+	worker    sync.WaitGroup
+
+	// Only synchronized between the client goroutine and the worker goroutine.
+	mu struct {
+		syncutil.Mutex
+		err error
+	}
+}
+
+var _ Sink = (*webhookSink)(nil)
+
+func makeWebhookSink(
+	ctx context.Context, u *url.URL, cfg webhookSinkConfig, opts map[string]string,
+) (Sink, error) {
+	// Every row is sent as an element of a JSON array, so only JSON values
+	// work here.
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+
+	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
+	case changefeedbase.OptEnvelopeWrapped:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
+	}
+
+	if cfg.batchSize <= 0 {
+		return nil, errors.Errorf(`%s must be a positive integer`, changefeedbase.SinkParamBatchSize)
+	}
+	if cfg.flushInterval <= 0 {
+		return nil, errors.Errorf(`%s must be a positive duration`, changefeedbase.SinkParamFlushInterval)
+	}
+	if cfg.clientTimeout <= 0 {
+		return nil, errors.Errorf(`%s must be a positive duration`, changefeedbase.SinkParamClientTimeout)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.tlsSkipVerify}
+	if cfg.caCert != nil {
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil || caCertPool == nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`failed to parse certificate data from %s`, changefeedbase.SinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil {
+		if cfg.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		}
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Errorf(`invalid client certificate data provided: %s`, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if cfg.clientKey != nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+
+	client := httputil.NewClientWithTimeout(cfg.clientTimeout)
+	client.Transport.(*http.Transport).TLSClientConfig = tlsConfig
+
+	// The scheme is only used to select this sink; the requests themselves go
+	// out over plain https.
+	sinkURL := *u
+	sinkURL.Scheme = strings.TrimPrefix(sinkURL.Scheme, `webhook-`)
+
+	sink := &webhookSink{
+		cfg:     cfg,
+		url:     sinkURL.String(),
+		client:  client,
+		inputCh: make(chan webhookMessage),
+		stopCh:  make(chan struct{}),
+	}
+	var workerCtx context.Context
+	workerCtx, sink.cancel = context.WithCancel(context.Background())
+	workerCtx = logtags.WithTags(workerCtx, logtags.FromContext(ctx))
+	sink.worker.Add(1)
+	go sink.workerLoop(workerCtx)
+	return sink, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, _ catalog.TableDescriptor, _, value []byte, _ hlc.Timestamp,
+) error {
+	if err := s.getErr(); err != nil {
+		return err
+	}
+	// The caller is free to reuse value once we return, so take a copy.
+	row := make([]byte, len(value))
+	copy(row, value)
+	return s.send(ctx, webhookMessage{row: row})
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	if err := s.getErr(); err != nil {
+		return err
+	}
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, ``, resolved)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, webhookMessage{resolved: payload})
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	flushCh := make(chan error, 1)
+	if err := s.send(ctx, webhookMessage{flushCh: flushCh}); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-flushCh:
+		return err
+	}
+}
+
+// Close implements the Sink interface. It is safe to call more than once.
+func (s *webhookSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		s.cancel()
+		s.worker.Wait()
+		s.client.CloseIdleConnections()
+	})
+	return nil
+}
+
+func (s *webhookSink) send(ctx context.Context, msg webhookMessage) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.stopCh:
+		return errors.New(`webhook sink is closed`)
+	case s.inputCh <- msg:
+		return nil
+	}
+}
+
+func (s *webhookSink) getErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.err
+}
+
+func (s *webhookSink) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.err == nil {
+		s.mu.err = err
+	}
+}
+
+func (s *webhookSink) workerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	var batch []gojson.RawMessage
+	flushBatch := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// Once a request has failed, everything after it is dropped: sending
+		// it would violate the ordering guarantees and the changefeed is going
+		// to be restarted from its last checkpoint anyway.
+		if s.getErr() == nil {
+			if err := s.sendBatch(ctx, batch); err != nil {
+				s.setErr(err)
+			}
+		}
+		batch = batch[:0]
+	}
+
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-timer.C:
+			timer.Read = true
+			flushBatch()
+		case msg := <-s.inputCh:
+			switch {
+			case msg.row != nil:
+				if len(batch) == 0 {
+					timer.Reset(s.cfg.flushInterval)
+				}
+				batch = append(batch, msg.row)
+				if len(batch) >= s.cfg.batchSize {
+					flushBatch()
+				}
+			case msg.resolved != nil:
+				flushBatch()
+				if s.getErr() == nil {
+					if err := s.sendWithRetries(ctx, msg.resolved); err != nil {
+						s.setErr(err)
+					}
+				}
+			case msg.flushCh != nil:
+				flushBatch()
+				msg.flushCh <- s.getErr()
+			}
+		}
+	}
+}
+
+func (s *webhookSink) sendBatch(ctx context.Context, batch []gojson.RawMessage) error {
+	body, err := gojson.Marshal(webhookSinkPayload{Payload: batch, Length: len(batch)})
+	if err != nil {
+		return err
+	}
+	if log.V(2) {
+		log.Infof(ctx, "sending %d rows to webhook sink", len(batch))
+	}
+	return s.sendWithRetries(ctx, body)
+}
+
+func (s *webhookSink) sendWithRetries(ctx context.Context, body []byte) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, s.cfg.retryOpts); r.Next(); {
+		if err = postJSON(ctx, s.client, s.url, body); err == nil || !isRetryableHTTPError(err) {
+			return errors.Wrap(err, `webhook sink`)
+		}
+		log.Warningf(ctx, `retrying webhook sink request: %v`, err)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return errors.Wrapf(err, `webhook sink request failed after %d retries`, s.cfg.retryOpts.MaxRetries)
+}
+
+// httpStatusError is returned for requests that were answered with a status
+// code other than 2xx.
+type httpStatusError struct {
+	statusCode int
+	body       string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf(`unexpected HTTP response: %d %s: %s`,
+		e.statusCode, http.StatusText(e.statusCode), e.body)
+}
+
+// isRetryableHTTPError returns true for errors which may go away on their own.
+// Connection failures and timeouts, as well as 5xx and 429 responses, are
+// retried; any other non-2xx response is a configuration problem and is not.
+func isRetryableHTTPError(err error) bool {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= http.StatusInternalServerError ||
+			statusErr.statusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// postJSON sends body to url, returning an *httpStatusError if the response
+// status code is not 2xx.
+func postJSON(ctx context.Context, client *httputil.Client, url string, body []byte) error {
+	resp, err := client.Post(ctx, url, applicationTypeJSON, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	const maxErrBodySize = 1 << 10
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrBodySize))
+	return &httpStatusError{statusCode: resp.StatusCode, body: string(respBody)}
+}
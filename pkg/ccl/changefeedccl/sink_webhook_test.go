@@ -0,0 +1,212 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/base64"
+	gojson "encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWebhookServer records the bodies of the requests it receives. The first
+// failures requests are answered with failStatus.
+type mockWebhookServer struct {
+	syncutil.Mutex
+	bodies     []string
+	failures   int
+	failStatus int
+}
+
+func (m *mockWebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	if m.failures > 0 {
+		m.failures--
+		w.WriteHeader(m.failStatus)
+		return
+	}
+	m.bodies = append(m.bodies, string(body))
+}
+
+func (m *mockWebhookServer) pop() []string {
+	m.Lock()
+	defer m.Unlock()
+	bodies := m.bodies
+	m.bodies = nil
+	return bodies
+}
+
+func webhookSinkURI(t *testing.T, ts *httptest.Server, params url.Values) string {
+	caCert := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: ts.Certificate().Raw})
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	u.Scheme = changefeedbase.SinkSchemeWebhookHTTPS
+	params.Set(changefeedbase.SinkParamCACert, base64.StdEncoding.EncodeToString(caCert))
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	table := tabledesc.NewImmutable(descpb.TableDescriptor{Name: `foo`})
+	targets := jobspb.ChangefeedTargets{table.GetID(): {StatementTimeName: `foo`}}
+	encoder, err := makeJSONEncoder(opts)
+	require.NoError(t, err)
+
+	mock := &mockWebhookServer{}
+	ts := httptest.NewTLSServer(mock)
+	defer ts.Close()
+
+	makeSink := func(params url.Values) Sink {
+		sink, err := getSink(
+			ctx, webhookSinkURI(t, ts, params), 0 /* srcID */, opts, targets,
+			nil /* settings */, base.ExternalIODirConfig{}, nil, /* timestampOracle */
+			nil, /* makeExternalStorageFromURI */
+			security.RootUserName(),
+		)
+		require.NoError(t, err)
+		return sink
+	}
+	row := func(i int) []byte {
+		return []byte(fmt.Sprintf(`{"after":{"a":%d}}`, i))
+	}
+
+	t.Run("batch size", func(t *testing.T) {
+		sink := makeSink(url.Values{
+			changefeedbase.SinkParamBatchSize:     {`2`},
+			changefeedbase.SinkParamFlushInterval: {`1h`},
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, sink.EmitRow(ctx, table, nil, row(i), zeroTS))
+		}
+		require.NoError(t, sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 1}))
+		require.NoError(t, sink.Flush(ctx))
+		require.Equal(t, []string{
+			`{"payload":[{"after":{"a":0}},{"after":{"a":1}}],"length":2}`,
+			`{"payload":[{"after":{"a":2}}],"length":1}`,
+			`{"resolved":"1.0000000000"}`,
+		}, mock.pop())
+
+		// Nothing buffered.
+		require.NoError(t, sink.Flush(ctx))
+		require.Empty(t, mock.pop())
+	})
+
+	t.Run("flush interval", func(t *testing.T) {
+		sink := makeSink(url.Values{
+			changefeedbase.SinkParamBatchSize:     {`100`},
+			changefeedbase.SinkParamFlushInterval: {`10ms`},
+		})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		require.NoError(t, sink.EmitRow(ctx, table, nil, row(0), zeroTS))
+		testutils.SucceedsSoon(t, func() error {
+			mock.Lock()
+			defer mock.Unlock()
+			if len(mock.bodies) != 1 {
+				return fmt.Errorf(`expected 1 request got %d`, len(mock.bodies))
+			}
+			return nil
+		})
+		var payload webhookSinkPayload
+		require.NoError(t, gojson.Unmarshal([]byte(mock.pop()[0]), &payload))
+		require.Equal(t, 1, payload.Length)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		sink := makeSink(url.Values{changefeedbase.SinkParamMaxRetries: {`3`}})
+		defer func() { require.NoError(t, sink.Close()) }()
+		sink.(*webhookSink).cfg.retryOpts.InitialBackoff = time.Millisecond
+
+		mock.Lock()
+		mock.failures, mock.failStatus = 2, http.StatusServiceUnavailable
+		mock.Unlock()
+		require.NoError(t, sink.EmitRow(ctx, table, nil, row(0), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Len(t, mock.pop(), 1)
+	})
+
+	t.Run("non-retryable error", func(t *testing.T) {
+		sink := makeSink(url.Values{})
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		mock.Lock()
+		mock.failures, mock.failStatus = 1, http.StatusBadRequest
+		mock.Unlock()
+		require.NoError(t, sink.EmitRow(ctx, table, nil, row(0), zeroTS))
+		require.Regexp(t, `webhook sink: unexpected HTTP response: 400 Bad Request`, sink.Flush(ctx))
+		// The error is sticky, since anything sent afterwards would be out of
+		// order.
+		require.Regexp(t, `webhook sink: unexpected HTTP response: 400 Bad Request`,
+			sink.EmitRow(ctx, table, nil, row(1), zeroTS))
+		require.Empty(t, mock.pop())
+	})
+
+	t.Run("close twice", func(t *testing.T) {
+		sink := makeSink(url.Values{})
+		require.NoError(t, sink.Close())
+		require.NoError(t, sink.Close())
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, tc := range []struct {
+			params url.Values
+			err    string
+		}{
+			{url.Values{changefeedbase.SinkParamBatchSize: {`0`}}, `batch_size must be a positive integer`},
+			{url.Values{changefeedbase.SinkParamBatchSize: {`x`}}, `param batch_size must be an integer`},
+			{url.Values{changefeedbase.SinkParamFlushInterval: {`x`}}, `param flush_interval must be a duration`},
+			{url.Values{changefeedbase.SinkParamClientKey: {`Zm9v`}}, `client_key requires client_cert to be set`},
+			{url.Values{`foo`: {`bar`}}, `unknown sink query parameter: foo`},
+		} {
+			_, err := getSink(
+				ctx, webhookSinkURI(t, ts, tc.params), 0 /* srcID */, opts, targets,
+				nil /* settings */, base.ExternalIODirConfig{}, nil, /* timestampOracle */
+				nil, /* makeExternalStorageFromURI */
+				security.RootUserName(),
+			)
+			require.Regexp(t, tc.err, err)
+		}
+	})
+}
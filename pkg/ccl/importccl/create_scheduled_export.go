@@ -0,0 +1,281 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+const scheduleExportOp = "CREATE SCHEDULE FOR EXPORT"
+
+var scheduledExportOptionExpectValues = map[string]sql.KVStringOptValidate{
+	schedulebase.OptFirstRun:          sql.KVStringOptRequireValue,
+	schedulebase.OptOnExecFailure:     sql.KVStringOptRequireValue,
+	schedulebase.OptOnPreviousRunning: sql.KVStringOptRequireValue,
+}
+
+// scheduledExportEval is a representation of tree.ScheduledExport, prepared
+// for evaluation.
+type scheduledExportEval struct {
+	*tree.ScheduledExport
+
+	// Schedule specific properties that get evaluated.
+	scheduleLabel func() (string, error)
+	recurrence    func() (string, error)
+	scheduleOpts  func() (map[string]string, error)
+
+	// Export specific properties that get evaluated. We evaluate anything in
+	// the tree.Export node that allows placeholders so that we store the
+	// evaluated export statement in the schedule.
+	file       func() (string, error)
+	exportOpts map[tree.Name]func() (string, error)
+}
+
+// scheduledExportHeader is the header for "CREATE SCHEDULE FOR EXPORT"
+// statement results.
+var scheduledExportHeader = colinfo.ResultColumns{
+	{Name: "schedule_id", Typ: types.Int},
+	{Name: "label", Typ: types.String},
+	{Name: "status", Typ: types.String},
+	{Name: "first_run", Typ: types.TimestampTZ},
+	{Name: "schedule", Typ: types.String},
+	{Name: "export_stmt", Typ: types.String},
+}
+
+func makeScheduledExportEval(
+	ctx context.Context, p sql.PlanHookState, schedule *tree.ScheduledExport,
+) (*scheduledExportEval, error) {
+	eval := &scheduledExportEval{ScheduledExport: schedule}
+	var err error
+
+	if schedule.ScheduleLabel != nil {
+		eval.scheduleLabel, err = p.TypeAsString(ctx, schedule.ScheduleLabel, scheduleExportOp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if schedule.Recurrence == nil {
+		// Sanity check: recurrence must be specified.
+		return nil, errors.New("RECURRING clause required")
+	}
+
+	eval.recurrence, err = p.TypeAsString(ctx, schedule.Recurrence, scheduleExportOp)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.scheduleOpts, err = p.TypeAsStringOpts(
+		ctx, schedule.ScheduleOptions, scheduledExportOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.file, err = p.TypeAsString(ctx, schedule.Export.File, scheduleExportOp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The set of valid export options is validated when the export statement
+	// is dry-run; here we only need to evaluate their values.
+	eval.exportOpts = make(map[tree.Name]func() (string, error), len(schedule.Export.Options))
+	for _, opt := range schedule.Export.Options {
+		if opt.Value == nil {
+			continue
+		}
+		eval.exportOpts[opt.Key], err = p.TypeAsString(ctx, opt.Value, scheduleExportOp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return eval, nil
+}
+
+// doCreateExportSchedule is a plan hook implementation responsible for the
+// creation of scheduled exports.
+func doCreateExportSchedule(
+	ctx context.Context, p sql.PlanHookState, eval *scheduledExportEval, resultsCh chan<- tree.Datums,
+) error {
+	if err := p.RequireAdminRole(ctx, scheduleExportOp); err != nil {
+		return err
+	}
+	// Older nodes do not know about the jobs which run scheduled exports.
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.ScheduledExportJobs) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create scheduled exports",
+			clusterversion.ScheduledExportJobs)
+	}
+	env := schedulebase.JobSchedulerEnv(p.ExecCfg().DistSQLSrv.TestingKnobs.JobsTestingKnobs)
+
+	recurrence, err := schedulebase.ComputeScheduleRecurrence(env.Now(), eval.recurrence)
+	if err != nil {
+		return err
+	}
+
+	// Prepare the export statement, with all of its placeholders evaluated.
+	file, err := eval.file()
+	if err != nil {
+		return errors.Wrapf(err, "failed to evaluate export destination")
+	}
+	exportNode := &tree.Export{
+		Query:      eval.Export.Query,
+		FileFormat: eval.Export.FileFormat,
+		File:       tree.NewDString(file),
+	}
+	for _, opt := range eval.Export.Options {
+		evalFn, ok := eval.exportOpts[opt.Key]
+		if !ok {
+			exportNode.Options = append(exportNode.Options, tree.KVOption{Key: opt.Key})
+			continue
+		}
+		v, err := evalFn()
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate export option %q", opt.Key)
+		}
+		exportNode.Options = append(exportNode.Options,
+			tree.KVOption{Key: opt.Key, Value: tree.NewDString(v)})
+	}
+
+	// Plan the export statement without running it. This does all of the
+	// sanity checks and validation we need in order to ensure the schedule
+	// is sane.
+	if err := dryRunExport(ctx, p, exportNode); err != nil {
+		return errors.Wrapf(err, "failed to dry run export")
+	}
+
+	var scheduleLabel string
+	if eval.scheduleLabel != nil {
+		scheduleLabel, err = eval.scheduleLabel()
+		if err != nil {
+			return err
+		}
+	} else {
+		scheduleLabel = fmt.Sprintf("EXPORT %d", env.Now().Unix())
+	}
+
+	scheduleOptions, err := eval.scheduleOpts()
+	if err != nil {
+		return err
+	}
+
+	evalCtx := &p.ExtendedEvalContext().EvalContext
+	firstRun, err := schedulebase.ScheduleFirstRun(evalCtx, scheduleOptions)
+	if err != nil {
+		return err
+	}
+
+	details, err := schedulebase.MakeScheduleDetails(scheduleOptions)
+	if err != nil {
+		return err
+	}
+
+	sj, err := makeExportSchedule(
+		env, p.User(), scheduleLabel, recurrence, details, exportNode, p.CurrentDatabase())
+	if err != nil {
+		return err
+	}
+	if firstRun != nil {
+		sj.SetNextRun(*firstRun)
+	}
+
+	if err := sj.Create(ctx, p.ExecCfg().InternalExecutor, p.ExtendedEvalContext().Txn); err != nil {
+		return err
+	}
+	telemetry.Count("scheduled-export.create.success")
+	return schedulebase.EmitSchedule(sj, tree.AsString(exportNode), resultsCh)
+}
+
+func makeExportSchedule(
+	env scheduledjobs.JobSchedulerEnv,
+	owner security.SQLUsername,
+	label string,
+	recurrence *schedulebase.ScheduleRecurrence,
+	details jobspb.ScheduleDetails,
+	exportNode *tree.Export,
+	database string,
+) (*jobs.ScheduledJob, error) {
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(label)
+	sj.SetOwner(owner)
+
+	if err := sj.SetSchedule(recurrence.Cron); err != nil {
+		return nil, err
+	}
+	sj.SetScheduleDetails(details)
+
+	// Serialize export statement and set schedule executor and its args.
+	any, err := pbtypes.MarshalAny(&jobspb.SqlStatementExecutionArg{
+		Statement: tree.AsString(exportNode),
+		Database:  database,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledExportExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: any},
+	)
+	return sj, nil
+}
+
+// dryRunExport plans, but does not execute, the export statement. It is
+// planned in the user's transaction with EXPLAIN, which builds the whole plan,
+// including the validation of the export options, but runs none of it.
+func dryRunExport(ctx context.Context, p sql.PlanHookState, exportNode *tree.Export) error {
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(ctx, "dry-run-export",
+		p.ExtendedEvalContext().Txn,
+		sessiondata.InternalExecutorOverride{User: p.User(), Database: p.CurrentDatabase()},
+		fmt.Sprintf("EXPLAIN %s", tree.AsString(exportNode)),
+	)
+	return err
+}
+
+func createExportScheduleHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	schedule, ok := stmt.(*tree.ScheduledExport)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	eval, err := makeScheduledExportEval(ctx, p, schedule)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		if err := doCreateExportSchedule(ctx, p, eval, resultsCh); err != nil {
+			telemetry.Count("scheduled-export.create.failed")
+			return err
+		}
+		return nil
+	}
+	return fn, scheduledExportHeader, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook(createExportScheduleHook)
+}
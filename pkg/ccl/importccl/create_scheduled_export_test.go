@@ -0,0 +1,134 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobstest"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledExport(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer jobs.TestingSetAdoptAndCancelIntervals(100*time.Millisecond, 100*time.Millisecond)()
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+
+	env := jobstest.NewJobSchedulerTestEnv(jobstest.UseSystemTables, timeutil.Now())
+	var cfg *scheduledjobs.JobExecutionConfig
+	var executeSchedules func() error
+	knobs := &jobs.TestingKnobs{
+		JobSchedulerEnv: env,
+		TakeOverJobsScheduling: func(fn func(ctx context.Context, maxSchedules int64, txn *kv.Txn) error) {
+			executeSchedules = func() error {
+				return cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+					return fn(ctx, 0 /* allSchedules */, txn)
+				})
+			}
+		},
+		CaptureJobExecutionConfig: func(config *scheduledjobs.JobExecutionConfig) {
+			cfg = config
+		},
+	}
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		ExternalIODir: dir,
+		Knobs:         base.TestingKnobs{JobsTestingKnobs: knobs},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'one'), (2, 'two')`)
+
+	var scheduleID int64
+	var unusedStr string
+	var unusedTS *time.Time
+	sqlDB.QueryRow(t,
+		`CREATE SCHEDULE 'my export' FOR EXPORT INTO CSV $1 FROM (SELECT * FROM t) RECURRING '@hourly'`,
+		"nodelocal://0/export",
+	).Scan(&scheduleID, &unusedStr, &unusedStr, &unusedTS, &unusedStr, &unusedStr)
+
+	sqlDB.CheckQueryResults(t,
+		`SELECT label, command FROM [SHOW SCHEDULES FOR EXPORT]`,
+		[][]string{{"my export",
+			`EXPORT INTO CSV 'nodelocal://0/export' FROM (SELECT * FROM t)`}},
+	)
+
+	sj, err := jobs.LoadScheduledJob(ctx, env, scheduleID, cfg.InternalExecutor, nil)
+	require.NoError(t, err)
+	require.Equal(t, tree.ScheduledExportExecutor.InternalName(), sj.ExecutorType())
+
+	// Force the schedule to execute. It creates an export job, which writes
+	// the file once it is adopted.
+	env.SetTime(sj.NextRun().Add(time.Second))
+	require.NoError(t, executeSchedules())
+
+	var jobID int64
+	sqlDB.QueryRow(t,
+		`SELECT id FROM system.jobs WHERE created_by_type = $1 AND created_by_id = $2`,
+		jobs.CreatedByScheduledJobs, scheduleID,
+	).Scan(&jobID)
+	jobutils.WaitForJob(t, sqlDB, jobID)
+
+	files, err := filepath.Glob(filepath.Join(dir, "export", "*.csv"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	content, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Equal(t, "1,one\n2,two\n", string(content))
+
+	// Pausing the schedule clears its next run.
+	sqlDB.Exec(t, `PAUSE SCHEDULE $1`, scheduleID)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW PAUSED SCHEDULES FOR EXPORT]`,
+		[][]string{{"1"}})
+	sqlDB.Exec(t, `RESUME SCHEDULE $1`, scheduleID)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW RUNNING SCHEDULES FOR EXPORT]`,
+		[][]string{{"1"}})
+
+	sqlDB.ExpectErr(t, "syntax error", `CREATE SCHEDULE FOR EXPORT INTO CSV 'nodelocal://0/x' FROM (SELECT * FROM t)`)
+	sqlDB.ExpectErr(t, `failed to dry run export`,
+		`CREATE SCHEDULE FOR EXPORT INTO CSV 'nodelocal://0/x' FROM (SELECT * FROM missing) RECURRING '@daily'`)
+
+	// Creating schedules requires the admin role.
+	sqlDB.Exec(t, `CREATE USER testuser`)
+	pgURL, cleanupFunc := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "TestScheduledExport-testuser", url.User("testuser"),
+	)
+	defer cleanupFunc()
+	testuser, err := gosql.Open("postgres", pgURL.String())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, testuser.Close())
+	}()
+	_, err = testuser.Exec(
+		`CREATE SCHEDULE FOR EXPORT INTO CSV 'nodelocal://0/x' FROM (SELECT 1) RECURRING '@daily'`)
+	require.Error(t, err)
+}
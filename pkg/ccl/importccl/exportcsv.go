@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
@@ -31,6 +32,41 @@ import (
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
 
+// exporter is implemented by the writers of each export format. It buffers
+// the file being exported in memory.
+type exporter interface {
+	// WriteRow appends a row to the file.
+	WriteRow(row rowenc.EncDatumRow) error
+	// Flush flushes any buffered rows.
+	Flush() error
+	// Close completes the file, appending any footers.
+	Close() error
+	// Release releases the resources held by the exporter once it is no
+	// longer used.
+	Release()
+	// ResetBuffer starts a new file.
+	ResetBuffer()
+	// Bytes returns the contents of the file.
+	Bytes() []byte
+	// Len returns the size of the file.
+	Len() int
+	// FileName returns the name of the file holding the specified part of the
+	// export.
+	FileName(spec execinfrapb.CSVWriterSpec, part string) string
+}
+
+// newExporter returns the exporter of the spec's format.
+func newExporter(sp execinfrapb.CSVWriterSpec, typs []*types.T) (exporter, error) {
+	switch sp.Format {
+	case roachpb.IOFileFormat_Unknown, roachpb.IOFileFormat_CSV:
+		return newCSVExporter(sp, typs), nil
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetExporter(sp, typs)
+	default:
+		return nil, errors.Errorf("unsupported export format %s", sp.Format)
+	}
+}
+
 // csvExporter data structure to augment the compression
 // and csv writer, encapsulating the internals to make
 // exporting oblivious for the consumers
@@ -38,13 +74,48 @@ type csvExporter struct {
 	compressor *gzip.Writer
 	buf        *bytes.Buffer
 	csvWriter  *csv.Writer
+
+	typs    []*types.T
+	nullsAs *string
+	alloc   rowenc.DatumAlloc
+	fmtCtx  *tree.FmtCtx
+	csvRow  []string
 }
 
+var _ exporter = &csvExporter{}
+
 // Write append record to csv file
 func (c *csvExporter) Write(record []string) error {
 	return c.csvWriter.Write(record)
 }
 
+// WriteRow formats the row and appends it to the csv file.
+func (c *csvExporter) WriteRow(row rowenc.EncDatumRow) error {
+	for i, ed := range row {
+		if ed.IsNull() {
+			if c.nullsAs != nil {
+				c.csvRow[i] = *c.nullsAs
+				continue
+			} else {
+				return errors.New("NULL value encountered during EXPORT, " +
+					"use `WITH nullas` to specify the string representation of NULL")
+			}
+		}
+		if err := ed.EnsureDecoded(c.typs[i], &c.alloc); err != nil {
+			return err
+		}
+		ed.Datum.Format(c.fmtCtx)
+		c.csvRow[i] = c.fmtCtx.String()
+		c.fmtCtx.Reset()
+	}
+	return c.Write(c.csvRow)
+}
+
+// Release implements the exporter interface.
+func (c *csvExporter) Release() {
+	c.fmtCtx.Close()
+}
+
 // Close closes the compressor writer which
 // appends archive footers
 func (c *csvExporter) Close() error {
@@ -97,7 +168,7 @@ func (c *csvExporter) FileName(spec execinfrapb.CSVWriterSpec, part string) stri
 	return fileName
 }
 
-func newCSVExporter(sp execinfrapb.CSVWriterSpec) *csvExporter {
+func newCSVExporter(sp execinfrapb.CSVWriterSpec, typs []*types.T) *csvExporter {
 	buf := bytes.NewBuffer([]byte{})
 	var exporter *csvExporter
 	switch sp.CompressionCodec {
@@ -121,6 +192,10 @@ func newCSVExporter(sp execinfrapb.CSVWriterSpec) *csvExporter {
 	if sp.Options.Comma != 0 {
 		exporter.csvWriter.Comma = sp.Options.Comma
 	}
+	exporter.typs = typs
+	exporter.nullsAs = sp.Options.NullEncoding
+	exporter.fmtCtx = tree.NewFmtCtx(tree.FmtExport)
+	exporter.csvRow = make([]string, len(typs))
 	return exporter
 }
 
@@ -173,18 +248,11 @@ func (sp *csvWriter) Run(ctx context.Context) {
 		sp.input.Start(ctx)
 		input := execinfra.MakeNoMetadataRowSource(sp.input, sp.output)
 
-		alloc := &rowenc.DatumAlloc{}
-
-		writer := newCSVExporter(sp.spec)
-
-		var nullsAs string
-		if sp.spec.Options.NullEncoding != nil {
-			nullsAs = *sp.spec.Options.NullEncoding
+		writer, err := newExporter(sp.spec, typs)
+		if err != nil {
+			return err
 		}
-		f := tree.NewFmtCtx(tree.FmtExport)
-		defer f.Close()
-
-		csvRow := make([]string, len(typs))
+		defer writer.Release()
 
 		chunk := 0
 		done := false
@@ -205,24 +273,7 @@ func (sp *csvWriter) Run(ctx context.Context) {
 				}
 				rows++
 
-				for i, ed := range row {
-					if ed.IsNull() {
-						if sp.spec.Options.NullEncoding != nil {
-							csvRow[i] = nullsAs
-							continue
-						} else {
-							return errors.New("NULL value encountered during EXPORT, " +
-								"use `WITH nullas` to specify the string representation of NULL")
-						}
-					}
-					if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
-						return err
-					}
-					ed.Datum.Format(f)
-					csvRow[i] = f.String()
-					f.Reset()
-				}
-				if err := writer.Write(csvRow); err != nil {
+				if err := writer.WriteRow(row); err != nil {
 					return err
 				}
 			}
@@ -230,7 +281,7 @@ func (sp *csvWriter) Run(ctx context.Context) {
 				break
 			}
 			if err := writer.Flush(); err != nil {
-				return errors.Wrap(err, "failed to flush csv writer")
+				return errors.Wrap(err, "failed to flush exporting writer")
 			}
 
 			conf, err := cloudimpl.ExternalStorageConfFromURI(sp.spec.Destination, sp.spec.User())
@@ -0,0 +1,376 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/geo"
+	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/parquet"
+	"github.com/cockroachdb/errors"
+)
+
+const exportParquetFilePatternDefault = exportFilePatternPart + ".parquet"
+
+// parquetEncodeFn converts a non-NULL datum to the value written to a Parquet
+// column.
+type parquetEncodeFn func(d tree.Datum) (interface{}, error)
+
+// parquetExporter writes the exported rows to a Parquet file. Unlike CSV
+// files, Parquet files are never compressed as a whole: the compression
+// codec is applied to the file's pages instead.
+type parquetExporter struct {
+	buf      *bytes.Buffer
+	writer   *parquet.Writer
+	typs     []*types.T
+	encoders []parquetEncodeFn
+	alloc    rowenc.DatumAlloc
+	row      []interface{}
+}
+
+var _ exporter = &parquetExporter{}
+
+func newParquetExporter(sp execinfrapb.CSVWriterSpec, typs []*types.T) (*parquetExporter, error) {
+	if len(sp.ColumnNames) != len(typs) {
+		return nil, errors.AssertionFailedf(
+			"expected %d column names, got %d", len(typs), len(sp.ColumnNames))
+	}
+	columns := make([]parquet.Column, len(typs))
+	encoders := make([]parquetEncodeFn, len(typs))
+	for i, typ := range typs {
+		var err error
+		columns[i], encoders[i], err = newParquetColumn(sp.ColumnNames[i], typ)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var opts parquet.WriterOptions
+	if sp.CompressionCodec == execinfrapb.FileCompression_Gzip {
+		opts.Compression = parquet.Gzip
+	}
+	buf := bytes.NewBuffer([]byte{})
+	writer, err := parquet.NewWriter(buf, columns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetExporter{
+		buf:      buf,
+		writer:   writer,
+		typs:     typs,
+		encoders: encoders,
+		row:      make([]interface{}, len(typs)),
+	}, nil
+}
+
+// WriteRow implements the exporter interface.
+func (c *parquetExporter) WriteRow(row rowenc.EncDatumRow) error {
+	for i, ed := range row {
+		if err := ed.EnsureDecoded(c.typs[i], &c.alloc); err != nil {
+			return err
+		}
+		if ed.Datum == tree.DNull {
+			c.row[i] = nil
+			continue
+		}
+		v, err := c.encoders[i](ed.Datum)
+		if err != nil {
+			return err
+		}
+		c.row[i] = v
+	}
+	return c.writer.AddRow(c.row)
+}
+
+// Flush implements the exporter interface. Rows are buffered until the file
+// is closed, so there is nothing to flush.
+func (c *parquetExporter) Flush() error {
+	return nil
+}
+
+// Release implements the exporter interface.
+func (c *parquetExporter) Release() {}
+
+// Close implements the exporter interface.
+func (c *parquetExporter) Close() error {
+	return c.writer.Close()
+}
+
+// ResetBuffer implements the exporter interface.
+func (c *parquetExporter) ResetBuffer() {
+	c.buf.Reset()
+	c.writer.Reset(c.buf)
+}
+
+// Bytes implements the exporter interface.
+func (c *parquetExporter) Bytes() []byte {
+	return c.buf.Bytes()
+}
+
+// Len implements the exporter interface.
+func (c *parquetExporter) Len() int {
+	return c.buf.Len()
+}
+
+// FileName implements the exporter interface.
+func (c *parquetExporter) FileName(spec execinfrapb.CSVWriterSpec, part string) string {
+	pattern := exportParquetFilePatternDefault
+	if spec.NamePattern != "" {
+		pattern = spec.NamePattern
+	}
+	return strings.Replace(pattern, exportFilePatternPart, part, -1)
+}
+
+// newParquetColumn returns the Parquet column to which values of the given
+// type are exported, along with the function that converts them. Types
+// without a Parquet counterpart are exported as strings.
+func newParquetColumn(name string, typ *types.T) (parquet.Column, parquetEncodeFn, error) {
+	col := parquet.Column{Name: name}
+	var encode parquetEncodeFn
+	switch typ.Family() {
+	case types.BoolFamily:
+		col.Type = parquet.Boolean
+		encode = func(d tree.Datum) (interface{}, error) {
+			return bool(*d.(*tree.DBool)), nil
+		}
+
+	case types.IntFamily:
+		switch typ.Width() {
+		case 16, 32:
+			col.Type = parquet.Int32
+			if typ.Width() == 16 {
+				col.Annotation = parquet.Int16
+			}
+			encode = func(d tree.Datum) (interface{}, error) {
+				return int32(*d.(*tree.DInt)), nil
+			}
+		default:
+			col.Type = parquet.Int64
+			encode = func(d tree.Datum) (interface{}, error) {
+				return int64(*d.(*tree.DInt)), nil
+			}
+		}
+
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			col.Type = parquet.Float
+			encode = func(d tree.Datum) (interface{}, error) {
+				return float32(*d.(*tree.DFloat)), nil
+			}
+		} else {
+			col.Type = parquet.Double
+			encode = func(d tree.Datum) (interface{}, error) {
+				return float64(*d.(*tree.DFloat)), nil
+			}
+		}
+
+	case types.DecimalFamily:
+		if typ.Precision() == 0 {
+			// Parquet decimals have a fixed scale, so unconstrained decimals are
+			// exported as strings to avoid losing digits.
+			return newParquetStringColumn(col)
+		}
+		col.Type = parquet.ByteArray
+		col.Annotation = parquet.Decimal
+		col.Precision, col.Scale = typ.Precision(), typ.Scale()
+		encode = func(d tree.Datum) (interface{}, error) {
+			return encodeParquetDecimal(&d.(*tree.DDecimal).Decimal, col.Scale)
+		}
+
+	case types.StringFamily:
+		col.Type = parquet.ByteArray
+		col.Annotation = parquet.String
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(*d.(*tree.DString)), nil
+		}
+
+	case types.CollatedStringFamily:
+		col.Type = parquet.ByteArray
+		col.Annotation = parquet.String
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DCollatedString).Contents), nil
+		}
+
+	case types.BytesFamily:
+		col.Type = parquet.ByteArray
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(*d.(*tree.DBytes)), nil
+		}
+
+	case types.DateFamily:
+		col.Type = parquet.Int32
+		col.Annotation = parquet.Date
+		encode = func(d tree.Datum) (interface{}, error) {
+			date := d.(*tree.DDate).Date
+			days := date.UnixEpochDays()
+			if !date.IsFinite() || days < math.MinInt32 || days > math.MaxInt32 {
+				return nil, errors.Errorf("cannot export date %s to Parquet", date)
+			}
+			return int32(days), nil
+		}
+
+	case types.TimestampFamily:
+		col.Type = parquet.Int64
+		col.Annotation = parquet.TimestampMicros
+		encode = func(d tree.Datum) (interface{}, error) {
+			t := d.(*tree.DTimestamp).Time
+			return t.Unix()*1e6 + int64(t.Nanosecond()/1e3), nil
+		}
+
+	case types.TimestampTZFamily:
+		col.Type = parquet.Int64
+		col.Annotation = parquet.TimestampMicrosUTC
+		encode = func(d tree.Datum) (interface{}, error) {
+			t := d.(*tree.DTimestampTZ).Time
+			return t.Unix()*1e6 + int64(t.Nanosecond()/1e3), nil
+		}
+
+	case types.TimeFamily:
+		col.Type = parquet.Int64
+		col.Annotation = parquet.TimeMicros
+		encode = func(d tree.Datum) (interface{}, error) {
+			return int64(*d.(*tree.DTime)), nil
+		}
+
+	case types.UuidFamily:
+		col.Type = parquet.FixedLenByteArray
+		col.TypeLength = 16
+		col.Annotation = parquet.UUID
+		encode = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DUuid).UUID.GetBytes(), nil
+		}
+
+	case types.JsonFamily:
+		col.Type = parquet.ByteArray
+		col.Annotation = parquet.JSON
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DJSON).JSON.String()), nil
+		}
+
+	case types.EnumFamily:
+		col.Type = parquet.ByteArray
+		col.Annotation = parquet.Enum
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DEnum).LogicalRep), nil
+		}
+
+	case types.GeometryFamily:
+		col.Type = parquet.ByteArray
+		encode = func(d tree.Datum) (interface{}, error) {
+			return spatialObjectToParquet(d.(*tree.DGeometry).SpatialObject())
+		}
+
+	case types.GeographyFamily:
+		col.Type = parquet.ByteArray
+		encode = func(d tree.Datum) (interface{}, error) {
+			return spatialObjectToParquet(d.(*tree.DGeography).SpatialObject())
+		}
+
+	case types.ArrayFamily:
+		elemCol, encodeElem, err := newParquetColumn(name, typ.ArrayContents())
+		if err != nil {
+			return parquet.Column{}, nil, err
+		}
+		if elemCol.List {
+			return parquet.Column{}, nil, errors.Errorf(
+				"cannot export nested array type %s to Parquet", typ.SQLString())
+		}
+		col = elemCol
+		col.List = true
+		encode = func(d tree.Datum) (interface{}, error) {
+			arr := d.(*tree.DArray).Array
+			list := make([]interface{}, len(arr))
+			for i, elem := range arr {
+				if elem == tree.DNull {
+					continue
+				}
+				v, err := encodeElem(elem)
+				if err != nil {
+					return nil, err
+				}
+				list[i] = v
+			}
+			return list, nil
+		}
+
+	default:
+		return newParquetStringColumn(col)
+	}
+	return col, encode, nil
+}
+
+// newParquetStringColumn returns a column to which values are exported as
+// strings, formatted as they are in CSV exports.
+func newParquetStringColumn(col parquet.Column) (parquet.Column, parquetEncodeFn, error) {
+	col.Type = parquet.ByteArray
+	col.Annotation = parquet.String
+	return col, func(d tree.Datum) (interface{}, error) {
+		return []byte(tree.AsStringWithFlags(d, tree.FmtExport)), nil
+	}, nil
+}
+
+// spatialObjectToParquet returns the little-endian WKB of a spatial object.
+// The Parquet writer only accepts plain []byte values for byte arrays, not
+// named types like geopb.WKB.
+func spatialObjectToParquet(so geopb.SpatialObject) (interface{}, error) {
+	wkb, err := geo.SpatialObjectToWKB(so, binary.LittleEndian)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(wkb), nil
+}
+
+// encodeParquetDecimal returns the unscaled value of the decimal at the
+// specified scale, as a big-endian two's complement integer.
+func encodeParquetDecimal(d *apd.Decimal, scale int32) ([]byte, error) {
+	if d.Form != apd.Finite {
+		return nil, errors.Errorf("cannot export decimal %s to Parquet", d)
+	}
+	var scaled apd.Decimal
+	cond, err := tree.HighPrecisionCtx.Quantize(&scaled, d, -scale)
+	if err != nil {
+		return nil, err
+	}
+	if cond.Inexact() || cond&apd.InvalidOperation != 0 {
+		return nil, errors.Errorf("cannot export decimal %s to Parquet with scale %d", d, scale)
+	}
+	unscaled := &scaled.Coeff
+	if scaled.Negative {
+		unscaled = new(big.Int).Neg(unscaled)
+	}
+	return bigIntToTwosComplement(unscaled), nil
+}
+
+// bigIntToTwosComplement returns the big-endian two's complement encoding of
+// x, using enough bytes to hold its sign bit.
+func bigIntToTwosComplement(x *big.Int) []byte {
+	n := x.BitLen()/8 + 1
+	v := x
+	if x.Sign() < 0 {
+		v = new(big.Int).Lsh(big.NewInt(1), uint(n*8))
+		v.Add(v, x)
+	}
+	b := v.Bytes()
+	if len(b) >= n {
+		return b
+	}
+	padded := make([]byte, n)
+	copy(padded[n-len(b):], b)
+	return padded
+}
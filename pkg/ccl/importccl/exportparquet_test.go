@@ -0,0 +1,165 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigIntToTwosComplement(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		x        int64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{1234, []byte{0x04, 0xd2}},
+		{-1, []byte{0xff}},
+		{-128, []byte{0xff, 0x80}},
+		{-1234, []byte{0xfb, 0x2e}},
+	} {
+		require.Equal(t, tc.expected, bigIntToTwosComplement(big.NewInt(tc.x)), "%d", tc.x)
+	}
+}
+
+func TestParquetColumnMapping(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		typ        *types.T
+		datum      string
+		physical   parquet.PhysicalType
+		annotation parquet.Annotation
+		expected   interface{}
+	}{
+		{types.Bool, "true", parquet.Boolean, parquet.NoAnnotation, true},
+		{types.Int2, "12", parquet.Int32, parquet.Int16, int32(12)},
+		{types.Int, "-12", parquet.Int64, parquet.NoAnnotation, int64(-12)},
+		{types.Float, "1.5", parquet.Double, parquet.NoAnnotation, 1.5},
+		{types.MakeDecimal(10, 2), "12.34", parquet.ByteArray, parquet.Decimal, []byte{0x04, 0xd2}},
+		{types.MakeDecimal(10, 2), "-12.3", parquet.ByteArray, parquet.Decimal, []byte{0xfb, 0x32}},
+		{types.Decimal, "12.345", parquet.ByteArray, parquet.String, []byte("12.345")},
+		{types.Decimal, "NaN", parquet.ByteArray, parquet.String, []byte("NaN")},
+		{types.String, "hello", parquet.ByteArray, parquet.String, []byte("hello")},
+		{types.Date, "1970-01-11", parquet.Int32, parquet.Date, int32(10)},
+		{types.Timestamp, "1970-01-01 00:00:01.5", parquet.Int64, parquet.TimestampMicros, int64(1500000)},
+		{types.TimestampTZ, "1970-01-01 00:00:01+00", parquet.Int64, parquet.TimestampMicrosUTC, int64(1000000)},
+		{types.Jsonb, `{"a": 1}`, parquet.ByteArray, parquet.JSON, []byte(`{"a": 1}`)},
+		{types.Interval, "1 day", parquet.ByteArray, parquet.String, []byte("1 day")},
+		{types.IntArray, "{1,NULL,3}", parquet.Int64, parquet.NoAnnotation,
+			[]interface{}{int64(1), nil, int64(3)}},
+		{types.Geometry, "POINT(1 2)", parquet.ByteArray, parquet.NoAnnotation, []byte{
+			0x01, 0x01, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+		}},
+		{types.Geography, "POINT(1 2)", parquet.ByteArray, parquet.NoAnnotation, []byte{
+			0x01, 0x01, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+		}},
+	} {
+		t.Run(tc.typ.SQLString(), func(t *testing.T) {
+			col, encode, err := newParquetColumn("c", tc.typ)
+			require.NoError(t, err)
+			require.NoError(t, col.Validate())
+			require.Equal(t, tc.physical, col.Type)
+			require.Equal(t, tc.annotation, col.Annotation)
+			require.Equal(t, tc.typ.Family() == types.ArrayFamily, col.List)
+
+			evalCtx := tree.NewTestingEvalContext(nil)
+			defer evalCtx.Stop(context.Background())
+			d, _, err := tree.ParseAndRequireString(tc.typ, tc.datum, evalCtx)
+			require.NoError(t, err)
+			v, err := encode(d)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v)
+		})
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TYPE mood AS ENUM ('happy', 'sad')`)
+	sqlDB.Exec(t, `CREATE TABLE foo (
+		i INT PRIMARY KEY, d DECIMAL(10, 2), ts TIMESTAMPTZ, j JSONB, a STRING[], m mood, g GEOMETRY
+	)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES
+		(1, 1.5, '2021-01-01 00:00:00+00', '{"a": 1}', ARRAY['x', NULL], 'happy', 'POINT(1 2)'),
+		(2, NULL, NULL, NULL, NULL, NULL, NULL),
+		(3, -2.25, now(), '[]', ARRAY[], 'sad', 'LINESTRING(0 0, 1 1)')`)
+
+	checkParquetFile := func(path string) {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "PAR1", string(content[:4]))
+		require.Equal(t, "PAR1", string(content[len(content)-4:]))
+	}
+
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/default' FROM SELECT * FROM foo`)
+	paths, err := filepath.Glob(filepath.Join(dir, "default", "export*-n1.0.parquet"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(paths))
+	checkParquetFile(paths[0])
+
+	rows := sqlDB.QueryStr(t, `EXPORT INTO PARQUET 'nodelocal://0/chunked'
+		WITH chunk_rows = '2', filename = 'foo' FROM SELECT * FROM foo`)
+	require.Equal(t, 2, len(rows))
+	for i, expected := range [][]string{{"foo-n1.0.parquet", "2"}, {"foo-n1.1.parquet", "1"}} {
+		require.Equal(t, expected, rows[i][:2])
+		checkParquetFile(filepath.Join(dir, "chunked", expected[0]))
+	}
+
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/compressed' WITH compression = gzip,
+		filename = 'part-%part%.pq' FROM SELECT * FROM foo`)
+	checkParquetFile(filepath.Join(dir, "compressed", "part-n1.0.pq"))
+
+	sqlDB.ExpectErr(t, `delimiter option is not supported for PARQUET exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/x' WITH delimiter = '|' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `nullas option is not supported for PARQUET exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/x' WITH nullas = '' FROM SELECT * FROM foo`)
+
+	// Unconstrained decimals, such as the results of aggregates, are exported
+	// as strings.
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/unconstrained' FROM SELECT sum(i), avg(d) FROM foo`)
+	paths, err = filepath.Glob(filepath.Join(dir, "unconstrained", "export*-n1.0.parquet"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(paths))
+	checkParquetFile(paths[0])
+
+	sqlDB.ExpectErr(t, `cannot export decimal NaN to Parquet`,
+		`EXPORT INTO PARQUET 'nodelocal://0/x' FROM SELECT 'NaN'::DECIMAL(10, 2)`)
+}
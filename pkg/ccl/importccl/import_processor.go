@@ -243,6 +243,14 @@ func makeInputConverter(
 		return newAvroInputReader(
 			kvCh, singleTable, spec.Format.Avro, spec.WalltimeNanos,
 			int(spec.ReaderParallelism), evalCtx)
+	case roachpb.IOFileFormat_Parquet:
+		return newParquetInputReader(
+			kvCh, singleTable, singleTableTargetCols, spec.Format.Parquet, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx), nil
+	case roachpb.IOFileFormat_NDJSON:
+		return newNDJSONInputReader(
+			kvCh, singleTable, singleTableTargetCols, spec.Format.Ndjson, spec.WalltimeNanos,
+			int(spec.ReaderParallelism), evalCtx), nil
 	default:
 		return nil, errors.Errorf(
 			"Requested IMPORT format (%d) not supported by this node", spec.Format.Format)
@@ -11,6 +11,7 @@ package importccl
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"net/url"
@@ -163,6 +164,8 @@ var mysqlOutAllowedOptions = makeStringSet(
 var mysqlDumpAllowedOptions = makeStringSet(importOptionSkipFKs, csvRowLimit)
 var pgCopyAllowedOptions = makeStringSet(pgCopyDelimiter, pgCopyNull, optMaxRowSize)
 var pgDumpAllowedOptions = makeStringSet(optMaxRowSize, importOptionSkipFKs, csvRowLimit)
+var parquetAllowedOptions = makeStringSet(avroStrict, csvRowLimit)
+var ndjsonAllowedOptions = makeStringSet(avroStrict, optMaxRowSize, csvRowLimit)
 
 // DROP is required because the target table needs to be take offline during
 // IMPORT INTO.
@@ -174,6 +177,14 @@ var allowedIntoFormats = map[string]struct{}{
 	"AVRO":      {},
 	"DELIMITED": {},
 	"PGCOPY":    {},
+	"PARQUET":   {},
+	"NDJSON":    {},
+}
+
+// File formats from which IMPORT TABLE can infer the schema of the table.
+var inferSchemaFormats = map[string]struct{}{
+	"PARQUET": {},
+	"NDJSON":  {},
 }
 
 // featureImportEnabled is used to enable and disable the IMPORT feature.
@@ -359,11 +370,18 @@ func importPlanHook(
 
 		// Typically the SQL grammar means it is only possible to specifying exactly
 		// one pgdump/mysqldump URI, but glob-expansion could have changed that.
-		if importStmt.Bundle && len(files) != 1 {
+		// Formats whose schema is inferred from the first file can import many.
+		_, infersSchema := inferSchemaFormats[importStmt.FileFormat]
+		if importStmt.Bundle && len(files) != 1 && !infersSchema {
 			return pgerror.New(pgcode.FeatureNotSupported, "SQL dump files must be imported individually")
 		}
 
 		table := importStmt.Table
+		if importStmt.Bundle && infersSchema && table == nil {
+			return pgerror.Newf(pgcode.Syntax,
+				"IMPORT %s requires a table name: use IMPORT TABLE <name> FROM %s",
+				importStmt.FileFormat, importStmt.FileFormat)
+		}
 		var parentID, parentSchemaID descpb.ID
 		if table != nil {
 			// TODO: As part of work for #34240, we should be operating on
@@ -626,6 +644,50 @@ func importPlanHook(
 			if err != nil {
 				return err
 			}
+		case "PARQUET":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, parquetAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_Parquet
+			_, format.Parquet.StrictMode = opts[avroStrict]
+			if override, ok := opts[csvRowLimit]; ok {
+				rowLimit, err := strconv.Atoi(override)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+				}
+				if rowLimit <= 0 {
+					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+				}
+				format.Parquet.RowLimit = int64(rowLimit)
+			}
+		case "NDJSON":
+			if err = validateFormatOptions(importStmt.FileFormat, opts, ndjsonAllowedOptions); err != nil {
+				return err
+			}
+			format.Format = roachpb.IOFileFormat_NDJSON
+			_, format.Ndjson.StrictMode = opts[avroStrict]
+			maxRowSize := int32(defaultScanBuffer)
+			if override, ok := opts[optMaxRowSize]; ok {
+				sz, err := humanizeutil.ParseBytes(override)
+				if err != nil {
+					return err
+				}
+				if sz < 1 || sz > math.MaxInt32 {
+					return errors.Errorf("%d out of range: %d", maxRowSize, sz)
+				}
+				maxRowSize = int32(sz)
+			}
+			format.Ndjson.MaxRowSize = maxRowSize
+			if override, ok := opts[csvRowLimit]; ok {
+				rowLimit, err := strconv.Atoi(override)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax, "invalid numeric %s value", csvRowLimit)
+				}
+				if rowLimit <= 0 {
+					return pgerror.Newf(pgcode.Syntax, "%s must be > 0", csvRowLimit)
+				}
+				format.Ndjson.RowLimit = int64(rowLimit)
+			}
 		default:
 			return unimplemented.Newf("import.format", "unsupported import format: %q", importStmt.FileFormat)
 		}
@@ -1262,16 +1324,21 @@ func parseAndCreateBundleTableDescs(
 	}
 	defer store.Close()
 
-	raw, err := store.ReadFile(ctx, "")
-	if err != nil {
-		return tableDescs, err
-	}
-	defer raw.Close()
-	reader, err := decompressingReader(raw, files[0], format.Compression)
-	if err != nil {
-		return tableDescs, err
+	var reader io.ReadCloser
+	// Parquet files are not read as streams, but at the offsets of their footer
+	// and row groups.
+	if format.Format != roachpb.IOFileFormat_Parquet {
+		raw, err := store.ReadFile(ctx, "")
+		if err != nil {
+			return tableDescs, err
+		}
+		defer raw.Close()
+		reader, err = decompressingReader(raw, files[0], format.Compression)
+		if err != nil {
+			return tableDescs, err
+		}
+		defer reader.Close()
 	}
-	defer reader.Close()
 
 	fks := fkHandler{skip: skipFKs, allowed: true, resolver: make(fkResolver)}
 	switch format.Format {
@@ -1281,6 +1348,11 @@ func parseAndCreateBundleTableDescs(
 	case roachpb.IOFileFormat_PgDump:
 		evalCtx := &p.ExtendedEvalContext().EvalContext
 		tableDescs, err = readPostgresCreateTable(ctx, reader, evalCtx, p, tableName, parentID, walltime, fks, int(format.PgDump.MaxRowSize), owner)
+	case roachpb.IOFileFormat_Parquet:
+		tableDescs, err = readParquetCreateTable(
+			ctx, store, files[0], format.Compression, p, tableName, parentID, walltime)
+	case roachpb.IOFileFormat_NDJSON:
+		tableDescs, err = readNDJSONCreateTable(ctx, reader, p, tableName, parentID, walltime, int(format.Ndjson.MaxRowSize))
 	default:
 		return tableDescs, errors.Errorf("non-bundle format %q does not support reading schemas", format.Format.String())
 	}
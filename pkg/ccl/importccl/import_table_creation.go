@@ -179,6 +179,34 @@ func MakeSimpleTableDescriptor(
 	return tableDesc, nil
 }
 
+// makeInferredTableDescriptor creates the descriptor of a table whose columns
+// were inferred from the contents of an input file. The columns are all
+// nullable, and the table is given a hidden rowid primary key.
+func makeInferredTableDescriptor(
+	ctx context.Context,
+	p sql.JobExecContext,
+	tableName string,
+	names []string,
+	typs []*types.T,
+	parentID descpb.ID,
+	walltime int64,
+) (*tabledesc.Mutable, error) {
+	// The name was formatted as a SQL identifier when the import was planned.
+	tn, err := parser.ParseQualifiedTableName(tableName)
+	if err != nil {
+		return nil, err
+	}
+	create := &tree.CreateTable{Table: tree.MakeUnqualifiedTableName(tn.ObjectName)}
+	for i := range names {
+		def := &tree.ColumnTableDef{Name: tree.Name(names[i]), Type: typs[i]}
+		def.Nullable.Nullability = tree.Null
+		create.Defs = append(create.Defs, def)
+	}
+	return MakeSimpleTableDescriptor(
+		ctx, p.SemaCtx(), p.ExecCfg().Settings, create, parentID, keys.PublicSchemaID,
+		defaultCSVTableID, NoFKs, walltime)
+}
+
 // fixDescriptorFKState repairs validity and table states set during descriptor
 // creation. sql.NewTableDesc and ResolveFK set the table to the ADD state
 // and mark references an validated. This function sets the table to PUBLIC
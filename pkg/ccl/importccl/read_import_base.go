@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
@@ -74,6 +75,10 @@ func runImport(
 	// TODO(adityamaru): Should we just plumb the flowCtx instead of this
 	// assignment.
 	evalCtx.DB = flowCtx.Cfg.DB
+	// Memory buffered by the input converters is accounted for by a monitor of
+	// the import, which is stopped once all of them have finished.
+	evalCtx.Mon = execinfra.NewMonitor(ctx, flowCtx.EvalCtx.Mon, "read-import-data-mem")
+	defer evalCtx.Mon.Stop(ctx)
 	conv, err := makeInputConverter(ctx, spec, evalCtx, kvCh, seqChunkProvider)
 	if err != nil {
 		return nil, err
@@ -357,7 +362,9 @@ func formatHasNamedColumns(format roachpb.IOFileFormat_FileFormat) bool {
 	switch format {
 	case roachpb.IOFileFormat_Avro,
 		roachpb.IOFileFormat_Mysqldump,
-		roachpb.IOFileFormat_PgDump:
+		roachpb.IOFileFormat_PgDump,
+		roachpb.IOFileFormat_Parquet,
+		roachpb.IOFileFormat_NDJSON:
 		return true
 	}
 	return false
@@ -422,6 +429,41 @@ type parallelImportContext struct {
 	seqChunkProvider *row.SeqChunkProvider // Used to reserve chunks of sequence values.
 }
 
+// namedColumnMapping matches the named fields of the records of an input file
+// to the columns targeted by the import.
+type namedColumnMapping struct {
+	names []string       // Names of the target columns, in DatumRowConverter order.
+	idx   map[string]int // Index of each target column, keyed by name.
+}
+
+func makeNamedColumnMapping(importCtx *parallelImportContext) namedColumnMapping {
+	var m namedColumnMapping
+	if len(importCtx.targetCols) > 0 {
+		for _, name := range importCtx.targetCols {
+			m.names = append(m.names, string(name))
+		}
+	} else {
+		for _, col := range importCtx.tableDesc.VisibleColumns() {
+			m.names = append(m.names, col.Name)
+		}
+	}
+	m.idx = make(map[string]int, len(m.names))
+	for i, name := range m.names {
+		m.idx[name] = i
+	}
+	return m
+}
+
+// lookup returns the index of the target column for the named field. The name
+// is first matched verbatim, and then normalized like a SQL identifier.
+func (m namedColumnMapping) lookup(field string) (int, bool) {
+	if idx, ok := m.idx[field]; ok {
+		return idx, true
+	}
+	idx, ok := m.idx[lexbase.NormalizeName(field)]
+	return idx, ok
+}
+
 // importFileContext describes state specific to a file being imported.
 type importFileContext struct {
 	source   int32       // Source is where the row data in the batch came from.
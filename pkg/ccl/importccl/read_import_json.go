@@ -0,0 +1,380 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bufio"
+	"context"
+	gojson "encoding/json"
+	"io"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// ndjsonInferenceRows is the number of lines of an NDJSON file which are
+// sampled to infer the schema of the imported table.
+const ndjsonInferenceRows = 1000
+
+type ndjsonInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.NDJSONOptions
+}
+
+var _ inputConverter = &ndjsonInputReader{}
+
+func newNDJSONInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *tabledesc.Immutable,
+	targetCols tree.NameList,
+	opts roachpb.NDJSONOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) *ndjsonInputReader {
+	return &ndjsonInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			targetCols: targetCols,
+			kvCh:       kvCh,
+		},
+		opts: opts,
+	}
+}
+
+func (n *ndjsonInputReader) start(group ctxgroup.Group) {}
+
+func (n *ndjsonInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, n.readFile, makeExternalStorage, user)
+}
+
+func (n *ndjsonInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	producer := &ndjsonRowStream{
+		input:   input,
+		scanner: newNDJSONScanner(input, int(n.opts.MaxRowSize)),
+	}
+	consumer := &ndjsonConsumer{
+		targets: makeNamedColumnMapping(n.importContext),
+		strict:  n.opts.StrictMode,
+	}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rejected: rejected,
+		rowLimit: n.opts.RowLimit,
+	}
+	return runParallelImport(ctx, n.importContext, fileCtx, producer, consumer)
+}
+
+func newNDJSONScanner(input io.Reader, maxRowSize int) *bufio.Scanner {
+	if maxRowSize <= 0 {
+		maxRowSize = defaultScanBuffer
+	}
+	s := bufio.NewScanner(input)
+	s.Split(bufio.ScanLines)
+	s.Buffer(nil, maxRowSize)
+	return s
+}
+
+// ndjsonRowStream produces the lines of an NDJSON file, skipping blank lines.
+type ndjsonRowStream struct {
+	input   *fileReader
+	scanner *bufio.Scanner
+	line    string
+	err     error
+}
+
+var _ importRowProducer = &ndjsonRowStream{}
+
+// Scan implements importRowProducer interface.
+func (n *ndjsonRowStream) Scan() bool {
+	for n.scanner.Scan() {
+		n.line = n.scanner.Text()
+		if strings.TrimSpace(n.line) != "" {
+			return true
+		}
+	}
+	if err := n.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = wrapWithLineTooLongHint(errors.New("line too long"))
+		}
+		n.err = err
+	}
+	return false
+}
+
+// Err implements importRowProducer interface.
+func (n *ndjsonRowStream) Err() error {
+	return n.err
+}
+
+// Row implements importRowProducer interface.
+func (n *ndjsonRowStream) Row() (interface{}, error) {
+	return n.line, nil
+}
+
+// Skip implements importRowProducer interface.
+func (n *ndjsonRowStream) Skip() error {
+	return nil
+}
+
+// Progress implements importRowProducer interface.
+func (n *ndjsonRowStream) Progress() float32 {
+	return n.input.ReadFraction()
+}
+
+// ndjsonConsumer converts the JSON objects of an NDJSON file to datums. The
+// keys of each object are matched to the target columns by name.
+type ndjsonConsumer struct {
+	targets namedColumnMapping
+	strict  bool
+}
+
+var _ importRowConsumer = &ndjsonConsumer{}
+
+// FillDatums implements importRowConsumer interface.
+func (n *ndjsonConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	line := native.(string)
+	j, err := json.ParseJSON(line)
+	if err != nil {
+		return newImportRowError(err, line, rowIndex)
+	}
+	if j.Type() != json.ObjectJSONType {
+		return newImportRowError(errors.New("expected a JSON object"), line, rowIndex)
+	}
+
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) {
+			conv.Datums[i] = nil
+		}
+	}
+	it, err := j.ObjectIter()
+	if err != nil {
+		return err
+	}
+	for it.Next() {
+		idx, ok := n.targets.lookup(it.Key())
+		if !ok {
+			if n.strict {
+				return newImportRowError(
+					errors.Errorf("could not find column for field %s", it.Key()), line, rowIndex)
+			}
+			continue
+		}
+		datum, err := jsonToDatum(it.Value(), conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return newImportRowError(errors.Wrapf(err, "field %s", it.Key()), line, rowIndex)
+		}
+		conv.Datums[idx] = datum
+	}
+
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) && conv.Datums[i] == nil {
+			if n.strict {
+				return newImportRowError(
+					errors.Errorf("field %s was not set in the NDJSON import", conv.VisibleCols[i].Name),
+					line, rowIndex)
+			}
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	return nil
+}
+
+// jsonToDatum converts a JSON value to a datum of the target type. Arrays are
+// converted element by element when imported into array columns. Otherwise,
+// scalars are parsed from their text, and objects and arrays from their JSON
+// representation.
+func jsonToDatum(j json.JSON, typ *types.T, evalCtx *tree.EvalContext) (tree.Datum, error) {
+	if j.Type() == json.NullJSONType {
+		return tree.DNull, nil
+	}
+	if typ.Family() == types.JsonFamily {
+		return tree.NewDJSON(j), nil
+	}
+	switch j.Type() {
+	case json.ArrayJSONType:
+		if typ.Family() != types.ArrayFamily {
+			break
+		}
+		arr := tree.NewDArray(typ.ArrayContents())
+		for i := 0; i < j.Len(); i++ {
+			elem, err := j.FetchValIdx(i)
+			if err != nil {
+				return nil, err
+			}
+			d, err := jsonToDatum(elem, typ.ArrayContents(), evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			if err := arr.Append(d); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+
+	case json.ObjectJSONType:
+		// Handled below.
+
+	default:
+		s, err := j.AsText()
+		if err != nil {
+			return nil, err
+		}
+		return rowenc.ParseDatumStringAs(typ, *s, evalCtx)
+	}
+	return rowenc.ParseDatumStringAs(typ, j.String(), evalCtx)
+}
+
+// readNDJSONCreateTable infers the schema of the table imported from an NDJSON
+// file by sampling its first lines. The columns of the table are the keys of
+// the sampled objects, in the order in which they first appear.
+func readNDJSONCreateTable(
+	ctx context.Context,
+	input io.Reader,
+	p sql.JobExecContext,
+	tableName string,
+	parentID descpb.ID,
+	walltime int64,
+	maxRowSize int,
+) ([]*tabledesc.Mutable, error) {
+	var names []string
+	var typs []*types.T
+	colIdx := make(map[string]int)
+
+	s := newNDJSONScanner(input, maxRowSize)
+	for rows := 0; rows < ndjsonInferenceRows && s.Scan(); {
+		line := s.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rows++
+		if err := inferNDJSONTypes(line, func(key string, typ *types.T) {
+			name := lexbase.NormalizeName(key)
+			idx, ok := colIdx[name]
+			if !ok {
+				colIdx[name] = len(names)
+				names = append(names, name)
+				typs = append(typs, typ)
+				return
+			}
+			typs[idx] = mergeInferredJSONTypes(typs[idx], typ)
+		}); err != nil {
+			return nil, errors.Wrapf(err, "row %d", rows)
+		}
+	}
+	if err := s.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = wrapWithLineTooLongHint(errors.New("line too long"))
+		}
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no columns found in NDJSON file")
+	}
+	for i := range typs {
+		if typs[i] == nil {
+			// The column only held nulls.
+			typs[i] = types.String
+		}
+	}
+
+	desc, err := makeInferredTableDescriptor(ctx, p, tableName, names, typs, parentID, walltime)
+	if err != nil {
+		return nil, err
+	}
+	return []*tabledesc.Mutable{desc}, nil
+}
+
+// inferNDJSONTypes calls fn with the key and the inferred type of each field
+// of the JSON object held by line, in order. The type of null values is nil.
+func inferNDJSONTypes(line string, fn func(key string, typ *types.T)) error {
+	dec := gojson.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != gojson.Delim('{') {
+		return errors.New("expected a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return errors.Errorf("unexpected JSON token %v", tok)
+		}
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		var typ *types.T
+		switch v := v.(type) {
+		case bool:
+			typ = types.Bool
+		case gojson.Number:
+			if _, err := v.Int64(); err == nil {
+				typ = types.Int
+			} else {
+				typ = types.Float
+			}
+		case string:
+			typ = types.String
+		case []interface{}, map[string]interface{}:
+			typ = types.Jsonb
+		}
+		fn(key, typ)
+	}
+	return nil
+}
+
+// mergeInferredJSONTypes returns the type of a column which holds values of
+// both types. Integers are widened to floats, and columns holding values of
+// otherwise conflicting types are imported as JSONB.
+func mergeInferredJSONTypes(a, b *types.T) *types.T {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || a.Identical(b):
+		return a
+	case a.Family() == types.IntFamily && b.Family() == types.FloatFamily,
+		a.Family() == types.FloatFamily && b.Family() == types.IntFamily:
+		return types.Float
+	}
+	return types.Jsonb
+}
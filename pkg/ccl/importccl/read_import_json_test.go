@@ -0,0 +1,144 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSONConsumer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	desc := descForTable(ctx, t,
+		`CREATE TABLE t (id INT8, name STRING, score FLOAT8, tags STRING[], attrs JSONB, day DATE)`,
+		10, 20, NoFKs).ImmutableCopy().(*tabledesc.Immutable)
+
+	readRows := func(
+		t *testing.T, input string, targetCols tree.NameList, strict bool,
+	) ([][]string, error) {
+		defer row.TestingSetDatumRowConverterBatchSize(10)()
+		importCtx := &parallelImportContext{tableDesc: desc, targetCols: targetCols}
+		producer := &ndjsonRowStream{
+			input:   &fileReader{Reader: strings.NewReader(input)},
+			scanner: newNDJSONScanner(strings.NewReader(input), 0 /* maxRowSize */),
+		}
+		consumer := &ndjsonConsumer{targets: makeNamedColumnMapping(importCtx), strict: strict}
+		conv, err := row.NewDatumRowConverter(ctx, desc, targetCols, evalCtx.Copy(), nil, nil /* seqChunkProvider */)
+		require.NoError(t, err)
+
+		var res [][]string
+		for i := int64(0); producer.Scan(); i++ {
+			native, err := producer.Row()
+			require.NoError(t, err)
+			if err := consumer.FillDatums(native, i, conv); err != nil {
+				return nil, err
+			}
+			var datums []string
+			for _, d := range conv.Datums[:len(conv.VisibleCols)] {
+				datums = append(datums, tree.AsStringWithFlags(d, tree.FmtBareStrings))
+			}
+			res = append(res, datums)
+		}
+		require.NoError(t, producer.Err())
+		return res, nil
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		res, err := readRows(t, `{"id": 1, "Name": "a", "score": 1.5, "tags": ["x", null], "attrs": {"k": [1]}, "day": "2021-03-04", "extra": true}
+
+{"id": 2, "name": null, "score": 2}
+`, nil, false /* strict */)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"1", "a", "1.5", "ARRAY[x,NULL]", `'{"k": [1]}'`, "2021-03-04"},
+			{"2", "NULL", "2.0", "NULL", "NULL", "NULL"},
+		}, res)
+	})
+
+	t.Run("target-columns", func(t *testing.T) {
+		res, err := readRows(t, `{"name": "b", "id": 3}`, tree.NameList{"name", "id"}, true /* strict */)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"b", "3"}}, res)
+	})
+
+	t.Run("strict-extra-field", func(t *testing.T) {
+		_, err := readRows(t, `{"name": "b", "id": 3, "extra": 1}`, tree.NameList{"name", "id"}, true /* strict */)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "could not find column for field extra")
+	})
+
+	t.Run("strict-missing-field", func(t *testing.T) {
+		_, err := readRows(t, `{"id": 3}`, tree.NameList{"name", "id"}, true /* strict */)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field name was not set in the NDJSON import")
+	})
+
+	t.Run("not-an-object", func(t *testing.T) {
+		_, err := readRows(t, `[1, 2]`, nil, false /* strict */)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expected a JSON object")
+	})
+}
+
+func TestInferNDJSONTypes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var names []string
+	typs := make(map[string]*types.T)
+	for _, line := range []string{
+		`{"b": 1, "a": "x", "c": null, "d": 1, "e": [1], "f": true, "g": 1}`,
+		`{"b": 2, "a": "y", "c": null, "d": 1.5, "e": {}, "f": false, "g": "1", "h": 2.5}`,
+	} {
+		require.NoError(t, inferNDJSONTypes(line, func(key string, typ *types.T) {
+			prev, ok := typs[key]
+			if !ok {
+				names = append(names, key)
+			}
+			typs[key] = mergeInferredJSONTypes(prev, typ)
+		}))
+	}
+
+	// Keys are reported in the order in which they appear.
+	require.Equal(t, []string{"b", "a", "c", "d", "e", "f", "g", "h"}, names)
+	expected := map[string]*types.T{
+		"a": types.String,
+		"b": types.Int,
+		"c": nil,
+		"d": types.Float,
+		"e": types.Jsonb,
+		"f": types.Bool,
+		"g": types.Jsonb,
+		"h": types.Float,
+	}
+	for key, typ := range expected {
+		if typ == nil {
+			require.Nil(t, typs[key], key)
+			continue
+		}
+		require.Equal(t, typ.SQLString(), typs[key].SQLString(), key)
+	}
+
+	require.Error(t, inferNDJSONTypes(`[1]`, func(string, *types.T) {}))
+	require.Error(t, inferNDJSONTypes(`{"a": }`, func(string, *types.T) {}))
+}
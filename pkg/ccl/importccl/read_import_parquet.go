@@ -0,0 +1,514 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// julianDayOfUnixEpoch is the Julian day number of 1970-01-01, used to decode
+// INT96 timestamps.
+const julianDayOfUnixEpoch = 2440588
+
+type parquetInputReader struct {
+	importContext *parallelImportContext
+	opts          roachpb.ParquetOptions
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	kvCh chan row.KVBatch,
+	tableDesc *tabledesc.Immutable,
+	targetCols tree.NameList,
+	opts roachpb.ParquetOptions,
+	walltime int64,
+	parallelism int,
+	evalCtx *tree.EvalContext,
+) *parquetInputReader {
+	return &parquetInputReader{
+		importContext: &parallelImportContext{
+			walltime:   walltime,
+			numWorkers: parallelism,
+			evalCtx:    evalCtx,
+			tableDesc:  tableDesc,
+			targetCols: targetCols,
+			kvCh:       kvCh,
+		},
+		opts: opts,
+	}
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	// The metadata of a Parquet file is stored at its end, so its files are not
+	// read as streams like those of the other formats: the footer and each row
+	// group are read separately, at their offsets.
+	for dataFileIndex, dataFile := range dataFiles {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := func() error {
+			conf, err := cloudimpl.ExternalStorageConfFromURI(dataFile, user)
+			if err != nil {
+				return err
+			}
+			es, err := makeExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+			return p.readFile(ctx, es, dataFile, format, dataFileIndex, resumePos[dataFileIndex])
+		}(); err != nil {
+			return errors.Wrapf(err, "%s", dataFile)
+		}
+	}
+	return nil
+}
+
+// readFile imports a Parquet file. Only the footer of the file and the row
+// group being imported are held in memory, and are accounted for by the
+// import's memory monitor.
+func (p *parquetInputReader) readFile(
+	ctx context.Context,
+	es cloud.ExternalStorage,
+	dataFile string,
+	format roachpb.IOFileFormat,
+	inputIdx int32,
+	resumePos int64,
+) error {
+	acc := p.importContext.evalCtx.Mon.MakeBoundAccount()
+	defer acc.Close(ctx)
+	reader, err := newParquetFileReader(ctx, es, dataFile, format.Compression, &acc)
+	if err != nil {
+		return err
+	}
+	consumer, err := newParquetConsumer(
+		reader.Columns(), makeNamedColumnMapping(p.importContext), p.opts.StrictMode)
+	if err != nil {
+		return err
+	}
+	producer := &parquetRowStream{reader: reader}
+
+	fileCtx := &importFileContext{
+		source:   inputIdx,
+		skip:     resumePos,
+		rowLimit: p.opts.RowLimit,
+	}
+	return runParallelImport(ctx, p.importContext, fileCtx, producer, consumer)
+}
+
+// newParquetFileReader returns a reader of the Parquet file held in es, whose
+// buffers are accounted for by acc.
+func newParquetFileReader(
+	ctx context.Context,
+	es cloud.ExternalStorage,
+	dataFile string,
+	compression roachpb.IOFileFormat_Compression,
+	acc *mon.BoundAccount,
+) (*parquet.Reader, error) {
+	if guessCompressionFromName(dataFile, compression) != roachpb.IOFileFormat_None {
+		return nil, errors.WithHint(
+			errors.New("compressed Parquet files cannot be imported"),
+			"Parquet files compress their pages themselves, so they do not need to be compressed")
+	}
+	size, err := es.Size(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return parquet.NewFileReader(&parquetFile{ctx: ctx, es: es}, size, parquet.ReaderOptions{
+		Reserve: func(bytes int64) error {
+			return acc.ResizeTo(ctx, bytes)
+		},
+	})
+}
+
+// parquetFile implements io.ReaderAt for a Parquet file held in external
+// storage, reading each part of the file with a separate request.
+type parquetFile struct {
+	ctx context.Context
+	es  cloud.ExternalStorage
+}
+
+var _ io.ReaderAt = &parquetFile{}
+
+// ReadAt implements the io.ReaderAt interface.
+func (f *parquetFile) ReadAt(p []byte, off int64) (int, error) {
+	r, _, err := f.es.ReadFileAt(f.ctx, "", off)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.ReadFull(r, p)
+}
+
+// parquetRowStream produces the rows of a Parquet file.
+type parquetRowStream struct {
+	reader *parquet.Reader
+	row    []interface{}
+	read   int64
+	err    error
+}
+
+var _ importRowProducer = &parquetRowStream{}
+
+// Scan implements importRowProducer interface.
+func (p *parquetRowStream) Scan() bool {
+	p.row, p.err = p.reader.Next()
+	if p.err == io.EOF {
+		p.err = nil
+		return false
+	}
+	if p.err != nil {
+		return false
+	}
+	p.read++
+	return true
+}
+
+// Err implements importRowProducer interface.
+func (p *parquetRowStream) Err() error {
+	return p.err
+}
+
+// Row implements importRowProducer interface.
+func (p *parquetRowStream) Row() (interface{}, error) {
+	return p.row, nil
+}
+
+// Skip implements importRowProducer interface.
+func (p *parquetRowStream) Skip() error {
+	return nil
+}
+
+// Progress implements importRowProducer interface.
+func (p *parquetRowStream) Progress() float32 {
+	if p.reader.NumRows() == 0 {
+		return 1
+	}
+	return float32(p.read) / float32(p.reader.NumRows())
+}
+
+// parquetConsumer converts the rows of a Parquet file to datums.
+type parquetConsumer struct {
+	columns []parquet.Column
+	// colIdx holds the index of the target column of each column of the file,
+	// or -1 if the column is not imported.
+	colIdx []int
+}
+
+var _ importRowConsumer = &parquetConsumer{}
+
+// newParquetConsumer matches the columns of a Parquet file to the target
+// columns. In strict mode, every column of the file must be matched, and every
+// target column must be present in the file.
+func newParquetConsumer(
+	columns []parquet.Column, targets namedColumnMapping, strict bool,
+) (*parquetConsumer, error) {
+	c := &parquetConsumer{
+		columns: columns,
+		colIdx:  make([]int, len(columns)),
+	}
+	found := make([]bool, len(targets.names))
+	for i := range columns {
+		idx, ok := targets.lookup(columns[i].Name)
+		if !ok {
+			if strict {
+				return nil, errors.Errorf("could not find column for Parquet column %s", columns[i].Name)
+			}
+			idx = -1
+		} else {
+			found[idx] = true
+		}
+		c.colIdx[i] = idx
+	}
+	if strict {
+		for i := range found {
+			if !found[i] {
+				return nil, errors.Errorf("column %s was not found in the Parquet file", targets.names[i])
+			}
+		}
+	}
+	return c, nil
+}
+
+// FillDatums implements importRowConsumer interface.
+func (c *parquetConsumer) FillDatums(
+	native interface{}, rowIndex int64, conv *row.DatumRowConverter,
+) error {
+	values := native.([]interface{})
+	for i := range conv.Datums {
+		if conv.TargetColOrds.Contains(i) {
+			conv.Datums[i] = tree.DNull
+		}
+	}
+	for i, v := range values {
+		idx := c.colIdx[i]
+		if idx < 0 {
+			continue
+		}
+		datum, err := parquetValueToDatum(v, &c.columns[i], conv.VisibleColTypes[idx], conv.EvalCtx)
+		if err != nil {
+			return newImportRowError(
+				errors.Wrapf(err, "column %s", c.columns[i].Name), fmt.Sprintf("%v", values), rowIndex)
+		}
+		conv.Datums[idx] = datum
+	}
+	return nil
+}
+
+// parquetValueToDatum converts a value read from a Parquet column to a datum
+// of the target type. Values which do not naturally map to the target type
+// are converted using their string representation.
+func parquetValueToDatum(
+	v interface{}, col *parquet.Column, typ *types.T, evalCtx *tree.EvalContext,
+) (tree.Datum, error) {
+	if v == nil {
+		return tree.DNull, nil
+	}
+	if col.List {
+		if typ.Family() != types.ArrayFamily {
+			return nil, errors.Errorf("cannot import a Parquet list into a column of type %s", typ.SQLString())
+		}
+		elemCol := *col
+		elemCol.List = false
+		arr := tree.NewDArray(typ.ArrayContents())
+		for _, elem := range v.([]interface{}) {
+			d, err := parquetValueToDatum(elem, &elemCol, typ.ArrayContents(), evalCtx)
+			if err != nil {
+				return nil, err
+			}
+			if err := arr.Append(d); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+
+	d, err := parquetScalarToDatum(v, col, typ)
+	if err != nil {
+		return nil, err
+	}
+	if d.ResolvedType().Equivalent(typ) {
+		return d, nil
+	}
+	return rowenc.ParseDatumStringAs(typ, tree.AsStringWithFlags(d, tree.FmtBareStrings), evalCtx)
+}
+
+// parquetScalarToDatum converts a non-NULL value read from a Parquet column to
+// the datum which best represents it. Byte arrays which are not annotated are
+// converted to strings, unless they are imported into a BYTES column.
+func parquetScalarToDatum(v interface{}, col *parquet.Column, typ *types.T) (tree.Datum, error) {
+	switch col.Annotation {
+	case parquet.String, parquet.Enum:
+		return tree.NewDString(string(v.([]byte))), nil
+	case parquet.JSON:
+		return tree.ParseDJSON(string(v.([]byte)))
+	case parquet.Decimal:
+		return decodeParquetDecimal(v, col.Scale), nil
+	case parquet.Date:
+		date, err := pgdate.MakeDateFromUnixEpoch(int64(v.(int32)))
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDDate(date), nil
+	case parquet.TimeMicros:
+		return tree.MakeDTime(timeofday.TimeOfDay(v.(int64))), nil
+	case parquet.TimestampMicros:
+		micros := v.(int64)
+		return tree.MakeDTimestamp(timeutil.Unix(micros/1e6, (micros%1e6)*1e3), time.Microsecond)
+	case parquet.TimestampMicrosUTC:
+		micros := v.(int64)
+		return tree.MakeDTimestampTZ(timeutil.Unix(micros/1e6, (micros%1e6)*1e3), time.Microsecond)
+	case parquet.TimestampMillis:
+		millis := v.(int64)
+		return tree.MakeDTimestamp(timeutil.Unix(millis/1e3, (millis%1e3)*1e6), time.Microsecond)
+	case parquet.TimestampMillisUTC:
+		millis := v.(int64)
+		return tree.MakeDTimestampTZ(timeutil.Unix(millis/1e3, (millis%1e3)*1e6), time.Microsecond)
+	case parquet.UUID:
+		u, err := uuid.FromBytes(v.([]byte))
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDUuid(tree.DUuid{UUID: u}), nil
+	}
+
+	switch v := v.(type) {
+	case bool:
+		return tree.MakeDBool(tree.DBool(v)), nil
+	case int32:
+		return tree.NewDInt(tree.DInt(v)), nil
+	case int64:
+		return tree.NewDInt(tree.DInt(v)), nil
+	case float32:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+	case float64:
+		return tree.NewDFloat(tree.DFloat(v)), nil
+	case []byte:
+		if col.Type == parquet.Int96 {
+			return decodeParquetInt96(v)
+		}
+		if typ.Family() == types.BytesFamily {
+			return tree.NewDBytes(tree.DBytes(v)), nil
+		}
+		return tree.NewDString(string(v)), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected Parquet value of type %T", v)
+}
+
+// decodeParquetDecimal returns the decimal whose unscaled value is stored in
+// v, which is either an integer or a big-endian two's complement integer.
+func decodeParquetDecimal(v interface{}, scale int32) tree.Datum {
+	d := &tree.DDecimal{}
+	switch v := v.(type) {
+	case int32:
+		d.SetFinite(int64(v), -scale)
+	case int64:
+		d.SetFinite(v, -scale)
+	case []byte:
+		unscaled := twosComplementToBigInt(v)
+		d.Coeff.Abs(unscaled)
+		d.Negative = unscaled.Sign() < 0
+		d.Exponent = -scale
+	}
+	return d
+}
+
+// twosComplementToBigInt decodes a big-endian two's complement integer. It is
+// the inverse of bigIntToTwosComplement.
+func twosComplementToBigInt(b []byte) *big.Int {
+	x := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return x
+}
+
+// decodeParquetInt96 decodes an INT96 timestamp, which holds the number of
+// nanoseconds since midnight followed by the Julian day number.
+func decodeParquetInt96(b []byte) (tree.Datum, error) {
+	nanos := int64(binary.LittleEndian.Uint64(b[:8]))
+	days := int64(binary.LittleEndian.Uint32(b[8:12])) - julianDayOfUnixEpoch
+	return tree.MakeDTimestampTZ(timeutil.Unix(days*24*60*60, nanos), time.Microsecond)
+}
+
+// parquetColumnType returns the SQL type used for a Parquet column when the
+// schema of the imported table is inferred from a Parquet file.
+func parquetColumnType(col parquet.Column) *types.T {
+	var typ *types.T
+	switch col.Annotation {
+	case parquet.String, parquet.Enum:
+		typ = types.String
+	case parquet.JSON:
+		typ = types.Jsonb
+	case parquet.Decimal:
+		typ = types.MakeDecimal(col.Precision, col.Scale)
+	case parquet.Date:
+		typ = types.Date
+	case parquet.TimeMicros:
+		typ = types.Time
+	case parquet.TimestampMicros, parquet.TimestampMillis:
+		typ = types.Timestamp
+	case parquet.TimestampMicrosUTC, parquet.TimestampMillisUTC:
+		typ = types.TimestampTZ
+	case parquet.Int16:
+		typ = types.Int2
+	case parquet.UUID:
+		typ = types.Uuid
+	default:
+		switch col.Type {
+		case parquet.Boolean:
+			typ = types.Bool
+		case parquet.Int32:
+			typ = types.Int4
+		case parquet.Int64:
+			typ = types.Int
+		case parquet.Int96:
+			typ = types.TimestampTZ
+		case parquet.Float:
+			typ = types.Float4
+		case parquet.Double:
+			typ = types.Float
+		default:
+			typ = types.Bytes
+		}
+	}
+	if col.List {
+		return types.MakeArray(typ)
+	}
+	return typ
+}
+
+// readParquetCreateTable infers the schema of the table imported from a
+// Parquet file. Every column of the file becomes a nullable column of the
+// table, which is given a hidden primary key.
+func readParquetCreateTable(
+	ctx context.Context,
+	es cloud.ExternalStorage,
+	dataFile string,
+	compression roachpb.IOFileFormat_Compression,
+	p sql.JobExecContext,
+	tableName string,
+	parentID descpb.ID,
+	walltime int64,
+) ([]*tabledesc.Mutable, error) {
+	acc := p.ExtendedEvalContext().Mon.MakeBoundAccount()
+	defer acc.Close(ctx)
+	reader, err := newParquetFileReader(ctx, es, dataFile, compression, &acc)
+	if err != nil {
+		return nil, err
+	}
+	columns := reader.Columns()
+	names := make([]string, len(columns))
+	typs := make([]*types.T, len(columns))
+	for i := range columns {
+		names[i] = lexbase.NormalizeName(columns[i].Name)
+		typs[i] = parquetColumnType(columns[i])
+	}
+	desc, err := makeInferredTableDescriptor(ctx, p, tableName, names, typs, parentID, walltime)
+	if err != nil {
+		return nil, err
+	}
+	return []*tabledesc.Mutable{desc}, nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwosComplementRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, s := range []string{
+		"0", "1", "-1", "127", "128", "-128", "-129", "255", "256",
+		"123456789012345678901234567890", "-123456789012345678901234567890",
+	} {
+		x, ok := new(big.Int).SetString(s, 10)
+		require.True(t, ok)
+		require.Equal(t, s, twosComplementToBigInt(bigIntToTwosComplement(x)).String())
+	}
+	// Values may be sign-extended to a fixed length.
+	require.Equal(t, "-2", twosComplementToBigInt([]byte{0xff, 0xff, 0xfe}).String())
+	require.Equal(t, "254", twosComplementToBigInt([]byte{0x00, 0x00, 0xfe}).String())
+}
+
+func TestParquetColumnType(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		col      parquet.Column
+		expected *types.T
+	}{
+		{parquet.Column{Type: parquet.Boolean}, types.Bool},
+		{parquet.Column{Type: parquet.Int32}, types.Int4},
+		{parquet.Column{Type: parquet.Int32, Annotation: parquet.Int16}, types.Int2},
+		{parquet.Column{Type: parquet.Int32, Annotation: parquet.Date}, types.Date},
+		{parquet.Column{Type: parquet.Int64}, types.Int},
+		{parquet.Column{Type: parquet.Int64, Annotation: parquet.TimestampMillis}, types.Timestamp},
+		{parquet.Column{Type: parquet.Int64, Annotation: parquet.TimestampMicrosUTC}, types.TimestampTZ},
+		{parquet.Column{Type: parquet.Int96}, types.TimestampTZ},
+		{parquet.Column{Type: parquet.Double}, types.Float},
+		{parquet.Column{Type: parquet.ByteArray}, types.Bytes},
+		{parquet.Column{Type: parquet.ByteArray, Annotation: parquet.String}, types.String},
+		{parquet.Column{Type: parquet.ByteArray, Annotation: parquet.JSON}, types.Jsonb},
+		{
+			parquet.Column{Type: parquet.FixedLenByteArray, Annotation: parquet.Decimal, Precision: 12, Scale: 3},
+			types.MakeDecimal(12, 3),
+		},
+		{parquet.Column{Type: parquet.Int64, List: true}, types.MakeArray(types.Int)},
+	} {
+		require.Equal(t, tc.expected.SQLString(), parquetColumnType(tc.col).SQLString())
+	}
+}
+
+// writeTestParquetFile returns a Parquet file holding the specified rows.
+func writeTestParquetFile(t *testing.T, columns []parquet.Column, rows ...[]interface{}) []byte {
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, columns, parquet.WriterOptions{})
+	require.NoError(t, err)
+	for _, r := range rows {
+		require.NoError(t, w.AddRow(r))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParquetConsumer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	desc := descForTable(ctx, t, `CREATE TABLE t (
+		id INT8, name STRING, price DECIMAL(10, 2), ts TIMESTAMPTZ, tags INT8[], raw BYTES, num INT8, unset STRING
+	)`, 10, 20, NoFKs).ImmutableCopy().(*tabledesc.Immutable)
+
+	columns := []parquet.Column{
+		{Name: "id", Type: parquet.Int64},
+		{Name: "Name", Type: parquet.ByteArray, Annotation: parquet.String},
+		{Name: "price", Type: parquet.ByteArray, Annotation: parquet.Decimal, Precision: 10, Scale: 2},
+		{Name: "ts", Type: parquet.Int64, Annotation: parquet.TimestampMicrosUTC},
+		{Name: "tags", Type: parquet.Int64, List: true},
+		{Name: "raw", Type: parquet.ByteArray},
+		{Name: "num", Type: parquet.ByteArray},
+		{Name: "extra", Type: parquet.Int32},
+	}
+	full := writeTestParquetFile(t, columns,
+		[]interface{}{
+			int64(1), []byte("a"), []byte{0xfe, 0x0c}, int64(1600000000123456),
+			[]interface{}{int64(1), nil, int64(3)}, []byte{0x00, 0x01}, []byte("42"), int32(7),
+		},
+		[]interface{}{int64(2), nil, nil, nil, nil, nil, nil, nil},
+	)
+
+	readRows := func(
+		t *testing.T, data []byte, targetCols tree.NameList, strict bool,
+	) ([][]string, error) {
+		defer row.TestingSetDatumRowConverterBatchSize(10)()
+		importCtx := &parallelImportContext{tableDesc: desc, targetCols: targetCols}
+		reader, err := parquet.NewReader(data)
+		require.NoError(t, err)
+		consumer, err := newParquetConsumer(reader.Columns(), makeNamedColumnMapping(importCtx), strict)
+		if err != nil {
+			return nil, err
+		}
+		producer := &parquetRowStream{reader: reader}
+		conv, err := row.NewDatumRowConverter(ctx, desc, targetCols, evalCtx.Copy(), nil, nil /* seqChunkProvider */)
+		require.NoError(t, err)
+
+		var res [][]string
+		for i := int64(0); producer.Scan(); i++ {
+			native, err := producer.Row()
+			require.NoError(t, err)
+			if err := consumer.FillDatums(native, i, conv); err != nil {
+				return nil, err
+			}
+			var datums []string
+			for _, d := range conv.Datums[:len(conv.VisibleCols)] {
+				datums = append(datums, tree.AsStringWithFlags(d, tree.FmtBareStrings))
+			}
+			res = append(res, datums)
+		}
+		require.NoError(t, producer.Err())
+		require.Equal(t, float32(1), producer.Progress())
+		return res, nil
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		res, err := readRows(t, full, nil, false /* strict */)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"1", "a", "-5.00", "2020-09-13 12:26:40.123456+00:00", "ARRAY[1,NULL,3]", `\x0001`, "42", "NULL"},
+			{"2", "NULL", "NULL", "NULL", "NULL", "NULL", "NULL", "NULL"},
+		}, res)
+	})
+
+	t.Run("target-columns", func(t *testing.T) {
+		res, err := readRows(t, full, tree.NameList{"num", "id"}, false /* strict */)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"42", "1"}, {"NULL", "2"}}, res)
+	})
+
+	idOnly := writeTestParquetFile(t, columns[:1], []interface{}{int64(3)})
+
+	t.Run("strict", func(t *testing.T) {
+		res, err := readRows(t, idOnly, tree.NameList{"id"}, true /* strict */)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"3"}}, res)
+	})
+
+	t.Run("strict-extra-column", func(t *testing.T) {
+		_, err := readRows(t, full, tree.NameList{"id", "name"}, true /* strict */)
+		require.EqualError(t, err, "could not find column for Parquet column price")
+	})
+
+	t.Run("strict-missing-column", func(t *testing.T) {
+		_, err := readRows(t, idOnly, tree.NameList{"id", "unset"}, true /* strict */)
+		require.EqualError(t, err, "column unset was not found in the Parquet file")
+	})
+}
+
+func TestImportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE src (i INT PRIMARY KEY, s STRING, a INT[])`)
+	sqlDB.Exec(t, `INSERT INTO src VALUES (1, 'one', ARRAY[1, NULL]), (2, NULL, NULL), (3, 'three', ARRAY[])`)
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/src' WITH filename = 'src' FROM SELECT * FROM src`)
+	const file = `nodelocal://0/src/src-n1.0.parquet`
+	expected := sqlDB.QueryStr(t, `SELECT * FROM src ORDER BY i`)
+
+	sqlDB.Exec(t, `CREATE TABLE dst (i INT PRIMARY KEY, s STRING, a INT[])`)
+	sqlDB.Exec(t, `IMPORT INTO dst PARQUET DATA ($1)`, file)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM dst ORDER BY i`, expected)
+
+	sqlDB.Exec(t, `IMPORT TABLE inferred FROM PARQUET ($1)`, file)
+	sqlDB.CheckQueryResults(t, `SELECT i, s, a FROM inferred ORDER BY i`, expected)
+
+	sqlDB.Exec(t, `CREATE TABLE compressed (i INT PRIMARY KEY, s STRING, a INT[])`)
+	sqlDB.ExpectErr(t, `compressed Parquet files cannot be imported`,
+		`IMPORT INTO compressed PARQUET DATA ($1) WITH decompress = 'gzip'`, file)
+}
@@ -0,0 +1,217 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs/schedulebase"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// scheduledExportExecutor executes scheduled exports. EXPORT does not create a
+// job of its own, so each run of the schedule creates an export job in the
+// scheduler's transaction, and the EXPORT statement is run by that job once
+// it is adopted. This keeps long exports from holding up the other schedules
+// and from being repeated if the scheduler's transaction is retried.
+type scheduledExportExecutor struct {
+	metrics jobs.ExecutorMetrics
+}
+
+var _ jobs.ScheduledJobExecutor = &scheduledExportExecutor{}
+
+// ExecuteJob implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledExportExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	if err := e.createExportJob(ctx, cfg, sj, txn); err != nil {
+		e.metrics.NumFailed.Inc(1)
+		return err
+	}
+	e.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+func (e *scheduledExportExecutor) createExportJob(
+	ctx context.Context, cfg *scheduledjobs.JobExecutionConfig, sj *jobs.ScheduledJob, txn *kv.Txn,
+) error {
+	args := &jobspb.SqlStatementExecutionArg{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+
+	// Sanity check: make sure the schedule is not paused (this shouldn't happen
+	// since job scheduler ignores paused schedules).
+	if sj.IsPaused() {
+		return errors.New("scheduled unexpectedly paused")
+	}
+
+	hook, cleanup := cfg.PlanHookMaker("create-export-job", txn, sj.Owner())
+	defer cleanup()
+	registry := hook.(sql.PlanHookState).ExecCfg().JobRegistry
+	record := jobs.Record{
+		Description: args.Statement,
+		Statement:   args.Statement,
+		Username:    sj.Owner(),
+		Details:     jobspb.ExportDetails{Statement: args.Statement, Database: args.Database},
+		Progress:    jobspb.ExportProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			Name: jobs.CreatedByScheduledJobs,
+			ID:   sj.ScheduleID(),
+		},
+	}
+	job, err := registry.CreateAdoptableJobWithTxn(ctx, record, txn)
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "Created export job %d for schedule %d: %s",
+		*job.ID(), sj.ScheduleID(), args.Statement)
+	return nil
+}
+
+// NotifyJobTermination implements jobs.ScheduledJobExecutor interface.
+func (e *scheduledExportExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID int64,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusSucceeded {
+		e.metrics.NumSucceeded.Inc(1)
+		log.Infof(ctx, "export job %d scheduled by %d succeeded", jobID, schedule.ScheduleID())
+		return nil
+	}
+
+	e.metrics.NumFailed.Inc(1)
+	err := errors.Errorf(
+		"export job %d scheduled by %d failed with status %s",
+		jobID, schedule.ScheduleID(), jobStatus)
+	log.Errorf(ctx, "export error: %v", err)
+	jobs.DefaultHandleFailedRun(schedule, "export job %d failed with err=%v", jobID, err)
+	return nil
+}
+
+// Metrics implements ScheduledJobExecutor interface.
+func (e *scheduledExportExecutor) Metrics() metric.Struct {
+	return &e.metrics
+}
+
+// exportResumer runs the EXPORT statement of a scheduled export.
+type exportResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &exportResumer{}
+
+// Resume implements the jobs.Resumer interface.
+func (r *exportResumer) Resume(
+	ctx context.Context, execCtx interface{}, _ chan<- tree.Datums,
+) error {
+	execCfg := execCtx.(sql.JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.ExportDetails)
+
+	// EXPORT cannot be used inside a transaction, so it runs in its own
+	// implicit one.
+	if _, err := execCfg.InternalExecutor.ExecEx(ctx, "exec-export", nil, /* txn */
+		sessiondata.InternalExecutorOverride{
+			User:     r.job.Payload().UsernameProto.Decode(),
+			Database: details.Database,
+		},
+		details.Statement,
+	); err != nil {
+		return err
+	}
+	r.maybeNotifyScheduledJobCompletion(ctx, jobs.StatusSucceeded, execCfg)
+	return nil
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface.
+func (r *exportResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	execCfg := execCtx.(sql.JobExecContext).ExecCfg()
+	r.maybeNotifyScheduledJobCompletion(ctx, jobs.StatusFailed, execCfg)
+	return nil
+}
+
+// maybeNotifyScheduledJobCompletion notifies the schedule which created the
+// export job, if any, that the job terminated.
+func (r *exportResumer) maybeNotifyScheduledJobCompletion(
+	ctx context.Context, jobStatus jobs.Status, exec *sql.ExecutorConfig,
+) {
+	env := schedulebase.JobSchedulerEnv(exec.DistSQLSrv.TestingKnobs.JobsTestingKnobs)
+
+	if err := exec.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		// Do not rely on r.job containing created_by_id.  Query it directly.
+		datums, err := exec.InternalExecutor.QueryRowEx(
+			ctx,
+			"lookup-schedule-info",
+			txn,
+			sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+			fmt.Sprintf(
+				"SELECT created_by_id FROM %s WHERE id=$1 AND created_by_type=$2",
+				env.SystemJobsTableName()),
+			*r.job.ID(), jobs.CreatedByScheduledJobs)
+
+		if err != nil {
+			return errors.Wrap(err, "schedule info lookup")
+		}
+		if datums == nil {
+			// Not a scheduled export.
+			return nil
+		}
+
+		scheduleID := int64(tree.MustBeDInt(datums[0]))
+		if err := jobs.NotifyJobTermination(
+			ctx, env, *r.job.ID(), jobStatus, r.job.Details(), scheduleID, exec.InternalExecutor, txn); err != nil {
+			log.Warningf(ctx,
+				"failed to notify schedule %d of completion of job %d; err=%s",
+				scheduleID, *r.job.ID(), err)
+		}
+		return nil
+	}); err != nil {
+		log.Errorf(ctx, "maybeNotifySchedule error: %v", err)
+	}
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeExport,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &exportResumer{job: job}
+		},
+	)
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledExportExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			return &scheduledExportExecutor{
+				metrics: jobs.MakeExecutorMetrics(tree.ScheduledExportExecutor.UserName()),
+			}, nil
+		})
+}
@@ -196,6 +196,10 @@ func createPartitioningImpl(
 				"declared partition columns (%s) do not match first %d columns in index being partitioned (%s)",
 				partitioningString(), n, strings.Join(indexDesc.ColumnNames[:n], ", "))
 		}
+		if indexDesc.ColumnNullsReversedAt(colOffset + i) {
+			return partDesc, unimplemented.NewWithIssuef(6224,
+				"partitioning by column %s with a non-default NULL ordering is not supported", col.Name)
+		}
 	}
 
 	for _, l := range partBy.List {
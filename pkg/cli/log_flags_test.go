@@ -36,11 +36,23 @@ func TestSetupLogging(t *testing.T) {
 
 	const defaultFluentConfig = `fluent-defaults: {` +
 		`buffered-writes: true, ` +
+		`max-buffer-size: 10MiB, ` +
 		`filter: INFO, ` +
 		`format: json-fluent-compact, ` +
 		`redactable: true, ` +
 		`exit-on-error: false` +
 		`}, `
+	const defaultHTTPConfig = `http-defaults: {` +
+		`timeout: 2s, ` +
+		`unsafe-tls: false, ` +
+		`disable-keep-alives: false, ` +
+		`buffered-writes: true, ` +
+		`max-buffer-size: 10MiB, ` +
+		`filter: INFO, ` +
+		`format: json-compact, ` +
+		`redactable: true, ` +
+		`exit-on-error: false` +
+		`}, `
 	stdFileDefaultsRe := regexp.MustCompile(
 		`file-defaults: \{dir: (?P<path>[^,]+), max-file-size: 10MiB, buffered-writes: true, filter: INFO, format: crdb-v1, redactable: true\}`)
 	fileDefaultsNoMaxSizeRe := regexp.MustCompile(
@@ -107,6 +119,7 @@ func TestSetupLogging(t *testing.T) {
 
 		// Shorten the configuration for legibility during reviews of test changes.
 		actual = strings.ReplaceAll(actual, defaultFluentConfig, "")
+		actual = strings.ReplaceAll(actual, defaultHTTPConfig, "")
 		actual = stdFileDefaultsRe.ReplaceAllString(actual, "<stdFileDefaults($path)>")
 		actual = fileDefaultsNoMaxSizeRe.ReplaceAllString(actual, "<fileDefaultsNoMaxSize($path)>")
 		actual = strings.ReplaceAll(actual, fileDefaultsNoDir, "<fileDefaultsNoDir>")
@@ -222,6 +222,34 @@ const (
 	// using the replicated legacy TruncatedState. It's also used in asserting
 	// that no replicated truncated state representation is found.
 	PostTruncatedAndRangeAppliedStateMigration
+	// UserDefinedFunctions is when user-defined functions, which are stored in
+	// function descriptors, are supported.
+	UserDefinedFunctions
+	// RowLevelTriggers is when row-level triggers can be added to tables.
+	RowLevelTriggers
+	// CompositeTypes is when composite user-defined types, which are stored in
+	// type descriptors of the COMPOSITE kind, are supported.
+	CompositeTypes
+	// ExclusionConstraints is when exclusion constraints can be added to tables.
+	ExclusionConstraints
+	// IndexNullsOrder is when index columns can be declared with a non-default
+	// NULL ordering (ASC NULLS LAST or DESC NULLS FIRST).
+	IndexNullsOrder
+	// RangeTypes is when columns of the range types (int4range, tstzrange, etc.)
+	// can be created.
+	RangeTypes
+	// TrigramInvertedIndexes is when inverted indexes can be created with the
+	// trigram operator classes.
+	TrigramInvertedIndexes
+	// ScheduledExportJobs is when scheduled exports can be created, which run
+	// their EXPORT statements in jobs of the EXPORT type.
+	ScheduledExportJobs
+	// DeferrableConstraints is when foreign key and unique without index
+	// constraints can be declared DEFERRABLE.
+	DeferrableConstraints
+	// SequenceCacheAndCycle is when sequences can be created or altered with
+	// CYCLE or with a CACHE greater than 1.
+	SequenceCacheAndCycle
 
 	// Step (1): Add new versions here.
 )
@@ -368,6 +396,46 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     PostTruncatedAndRangeAppliedStateMigration,
 		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 16},
 	},
+	{
+		Key:     UserDefinedFunctions,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 18},
+	},
+	{
+		Key:     RowLevelTriggers,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 20},
+	},
+	{
+		Key:     CompositeTypes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 22},
+	},
+	{
+		Key:     ExclusionConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 24},
+	},
+	{
+		Key:     IndexNullsOrder,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 26},
+	},
+	{
+		Key:     RangeTypes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 28},
+	},
+	{
+		Key:     TrigramInvertedIndexes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 30},
+	},
+	{
+		Key:     ScheduledExportJobs,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 32},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 34},
+	},
+	{
+		Key:     SequenceCacheAndCycle,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 36},
+	},
 
 	// Step (2): Add new versions here.
 })
@@ -29,6 +29,7 @@ var _ Details = ChangefeedDetails{}
 var _ Details = CreateStatsDetails{}
 var _ Details = SchemaChangeGCDetails{}
 var _ Details = StreamIngestionDetails{}
+var _ Details = ExportDetails{}
 
 // ProgressDetails is a marker interface for job progress details proto structs.
 type ProgressDetails interface{}
@@ -40,6 +41,7 @@ var _ ProgressDetails = ChangefeedProgress{}
 var _ ProgressDetails = CreateStatsProgress{}
 var _ ProgressDetails = SchemaChangeGCProgress{}
 var _ ProgressDetails = StreamIngestionProgress{}
+var _ ProgressDetails = ExportProgress{}
 
 // Type returns the payload's job type.
 func (p *Payload) Type() Type {
@@ -71,6 +73,8 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeTypeSchemaChange
 	case *Payload_StreamIngestion:
 		return TypeStreamIngestion
+	case *Payload_Export:
+		return TypeExport
 	default:
 		panic(errors.AssertionFailedf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -103,6 +107,8 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_TypeSchemaChange{TypeSchemaChange: &d}
 	case StreamIngestionProgress:
 		return &Progress_StreamIngest{StreamIngest: &d}
+	case ExportProgress:
+		return &Progress_Export{Export: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -130,6 +136,8 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.TypeSchemaChange
 	case *Payload_StreamIngestion:
 		return *d.StreamIngestion
+	case *Payload_Export:
+		return *d.Export
 	default:
 		return nil
 	}
@@ -157,6 +165,8 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.TypeSchemaChange
 	case *Progress_StreamIngest:
 		return *d.StreamIngest
+	case *Progress_Export:
+		return *d.Export
 	default:
 		return nil
 	}
@@ -197,6 +207,8 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_TypeSchemaChange{TypeSchemaChange: &d}
 	case StreamIngestionDetails:
 		return &Payload_StreamIngestion{StreamIngestion: &d}
+	case ExportDetails:
+		return &Payload_Export{Export: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -232,7 +244,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 11
+const NumJobTypes = 12
 
 func init() {
 	if len(Type_name) != NumJobTypes {
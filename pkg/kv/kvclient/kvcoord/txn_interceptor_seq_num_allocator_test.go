@@ -228,6 +228,55 @@ func TestSequenceNumberAllocationWithStep(t *testing.T) {
 	require.NotNil(t, br)
 }
 
+// TestSequenceNumberAllocationSetReadSeq tests that read-only requests can be
+// sent at a read seqnum established by an earlier step.
+func TestSequenceNumberAllocationSetReadSeq(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	s, mockSender := makeMockTxnSeqNumAllocator()
+
+	txn := makeTxnProto()
+	keyA := roachpb.Key("a")
+
+	// Setting the read seqnum requires stepping mode.
+	require.Error(t, s.setReadSeqLocked(0))
+	s.configureSteppingLocked(true /* enabled */)
+
+	var ba roachpb.BatchRequest
+	ba.Header = roachpb.Header{Txn: &txn}
+	ba.Add(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}})
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	_, pErr := s.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+
+	oldReadSeq := s.readSeq
+	require.NoError(t, s.stepLocked(ctx))
+	require.Equal(t, enginepb.TxnSeq(1), s.readSeq)
+
+	// Go back to the snapshot preceding the write.
+	require.NoError(t, s.setReadSeqLocked(oldReadSeq))
+	ba.Requests = nil
+	ba.Add(&roachpb.GetRequest{RequestHeader: roachpb.RequestHeader{Key: keyA}})
+	mockSender.MockSend(func(ba roachpb.BatchRequest) (*roachpb.BatchResponse, *roachpb.Error) {
+		require.Len(t, ba.Requests, 1)
+		require.Equal(t, oldReadSeq, ba.Requests[0].GetInner().Header().Sequence)
+
+		br := ba.CreateReply()
+		br.Txn = ba.Txn
+		return br, nil
+	})
+	_, pErr = s.SendLocked(ctx, ba)
+	require.Nil(t, pErr)
+
+	// The read seqnum cannot be set beyond the write seqnum.
+	require.Error(t, s.setReadSeqLocked(s.writeSeq+1))
+}
+
 // TestSequenceNumberAllocationTxnRequests tests sequence number allocation's
 // interaction with transaction state requests (HeartbeatTxn and EndTxn). Only
 // EndTxn requests should be assigned unique sequence numbers.
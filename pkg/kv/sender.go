@@ -290,6 +290,16 @@ type TxnSender interface {
 	// GetSteppingMode accompanies ConfigureStepping. It is provided
 	// for use in tests and assertion checks.
 	GetSteppingMode(ctx context.Context) (curMode SteppingMode)
+
+	// GetReadSeqNum returns the sequence number at which read-only
+	// operations are currently performed.
+	GetReadSeqNum() enginepb.TxnSeq
+
+	// SetReadSeqNum sets the sequence number at which subsequent
+	// read-only operations are performed. It can be used to go back to a
+	// snapshot previously established by Step() and obtained through
+	// GetReadSeqNum(). Stepping mode must be enabled.
+	SetReadSeqNum(seq enginepb.TxnSeq) error
 }
 
 // SteppingMode is the argument type to ConfigureStepping.
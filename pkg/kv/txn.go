@@ -1252,6 +1252,23 @@ func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevM
 	return txn.mu.sender.ConfigureStepping(ctx, mode)
 }
 
+// GetReadSeqNum returns the sequence number at which the transaction's
+// read-only operations are currently performed.
+func (txn *Txn) GetReadSeqNum() enginepb.TxnSeq {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.GetReadSeqNum()
+}
+
+// SetReadSeqNum sets the sequence number at which the transaction's
+// subsequent read-only operations are performed. Step-wise execution must be
+// already enabled.
+func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetReadSeqNum(seq)
+}
+
 // CreateSavepoint establishes a savepoint.
 // This method is only valid when called on RootTxns.
 func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
@@ -0,0 +1,173 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package schedulebase contains the pieces shared by the implementations of
+// the CREATE SCHEDULE FOR ... statements: parsing of the common schedule
+// options and of the RECURRING expression, and the rows these statements
+// return.
+package schedulebase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/gorhill/cronexpr"
+)
+
+// Schedule options understood by every CREATE SCHEDULE statement.
+const (
+	OptFirstRun          = "first_run"
+	OptOnExecFailure     = "on_execution_failure"
+	OptOnPreviousRunning = "on_previous_running"
+)
+
+// ParseOnError sets the error handling behavior of the schedule from the
+// value of the on_execution_failure option.
+func ParseOnError(onError string, details *jobspb.ScheduleDetails) error {
+	switch strings.ToLower(onError) {
+	case "retry":
+		details.OnError = jobspb.ScheduleDetails_RETRY_SOON
+	case "reschedule":
+		details.OnError = jobspb.ScheduleDetails_RETRY_SCHED
+	case "pause":
+		details.OnError = jobspb.ScheduleDetails_PAUSE_SCHED
+	default:
+		return errors.Newf(
+			"%q is not a valid on_execution_error; valid values are [retry|reschedule|pause]",
+			onError)
+	}
+	return nil
+}
+
+// ParseWaitBehavior sets the wait behavior of the schedule from the value of
+// the on_previous_running option.
+func ParseWaitBehavior(wait string, details *jobspb.ScheduleDetails) error {
+	switch strings.ToLower(wait) {
+	case "start":
+		details.Wait = jobspb.ScheduleDetails_NO_WAIT
+	case "skip":
+		details.Wait = jobspb.ScheduleDetails_SKIP
+	case "wait":
+		details.Wait = jobspb.ScheduleDetails_WAIT
+	default:
+		return errors.Newf(
+			"%q is not a valid on_previous_running; valid values are [start|skip|wait]",
+			wait)
+	}
+	return nil
+}
+
+// MakeScheduleDetails returns the schedule details specified by the schedule
+// options.
+func MakeScheduleDetails(opts map[string]string) (jobspb.ScheduleDetails, error) {
+	var details jobspb.ScheduleDetails
+	if v, ok := opts[OptOnExecFailure]; ok {
+		if err := ParseOnError(v, &details); err != nil {
+			return details, err
+		}
+	}
+
+	if v, ok := opts[OptOnPreviousRunning]; ok {
+		if err := ParseWaitBehavior(v, &details); err != nil {
+			return details, err
+		}
+	}
+	return details, nil
+}
+
+// ScheduleFirstRun returns the time specified by the first_run option, or nil
+// if the option is not set.
+func ScheduleFirstRun(evalCtx *tree.EvalContext, opts map[string]string) (*time.Time, error) {
+	if v, ok := opts[OptFirstRun]; ok {
+		firstRun, _, err := tree.ParseDTimestampTZ(evalCtx, v, time.Microsecond)
+		if err != nil {
+			return nil, err
+		}
+		return &firstRun.Time, nil
+	}
+	return nil, nil
+}
+
+// ScheduleRecurrence is an evaluated RECURRING expression.
+type ScheduleRecurrence struct {
+	Cron      string
+	Frequency time.Duration
+}
+
+// NeverRecurs is a sentinel value indicating the schedule never recurs.
+var NeverRecurs *ScheduleRecurrence
+
+// ComputeScheduleRecurrence evaluates a RECURRING expression. A nil evalFn
+// means the schedule never recurs.
+func ComputeScheduleRecurrence(
+	now time.Time, evalFn func() (string, error),
+) (*ScheduleRecurrence, error) {
+	if evalFn == nil {
+		return NeverRecurs, nil
+	}
+	cron, err := evalFn()
+	if err != nil {
+		return nil, err
+	}
+	expr, err := cronexpr.Parse(cron)
+	if err != nil {
+		return nil, errors.Newf(
+			`error parsing schedule expression: %q; it must be a valid cron expression`,
+			cron)
+	}
+	nextRun := expr.Next(now)
+	frequency := expr.Next(nextRun).Sub(nextRun)
+	return &ScheduleRecurrence{cron, frequency}, nil
+}
+
+// JobSchedulerEnv returns the environment new schedules should be created in,
+// which is overridden by the jobs testing knobs, if any.
+func JobSchedulerEnv(knobs base.ModuleTestingKnobs) scheduledjobs.JobSchedulerEnv {
+	if knobs, ok := knobs.(*jobs.TestingKnobs); ok && knobs.JobSchedulerEnv != nil {
+		return knobs.JobSchedulerEnv
+	}
+	return scheduledjobs.ProdJobSchedulerEnv
+}
+
+// EmitSchedule sends the row describing a newly created schedule, which runs
+// the given statement, to resultsCh.
+func EmitSchedule(sj *jobs.ScheduledJob, stmt string, resultsCh chan<- tree.Datums) error {
+	var nextRun tree.Datum
+	status := "ACTIVE"
+	if sj.IsPaused() {
+		nextRun = tree.DNull
+		status = "PAUSED"
+		if s := sj.ScheduleStatus(); s != "" {
+			status += ": " + s
+		}
+	} else {
+		next, err := tree.MakeDTimestampTZ(sj.NextRun(), time.Microsecond)
+		if err != nil {
+			return err
+		}
+		nextRun = next
+	}
+
+	resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(sj.ScheduleID())),
+		tree.NewDString(sj.ScheduleLabel()),
+		tree.NewDString(status),
+		nextRun,
+		tree.NewDString(sj.ScheduleExpr()),
+		tree.NewDString(stmt),
+	}
+	return nil
+}
@@ -76,6 +76,15 @@ func AlterColumnType(
 			)
 		}
 	}
+	for _, ref := range tableDesc.DependedOnByFunctions {
+		for _, colID := range ref.ColumnIDs {
+			if colID == col.ID {
+				return params.p.dependentFunctionErrorByID(
+					ctx, "column", col.Name, tableDesc.ParentID, ref.ID, "alter type of",
+				)
+			}
+		}
+	}
 
 	typ, err := tree.ResolveType(ctx, t.ToType, params.p.semaCtx.GetTypeResolver())
 	if err != nil {
@@ -208,6 +217,16 @@ func alterColumnTypeGeneral(
 		}
 	}
 
+	// Disallow ALTER COLUMN TYPE general for columns that have an exclusion
+	// constraint.
+	for _, ec := range tableDesc.AllActiveAndInactiveExclusionConstraints() {
+		for _, id := range ec.ColumnIDs {
+			if col.ID == id {
+				return colWithConstraintNotSupportedErr
+			}
+		}
+	}
+
 	// Disallow ALTER COLUMN TYPE general for columns that have a foreign key
 	// constraint.
 	for _, fk := range tableDesc.AllActiveAndInactiveForeignKeys() {
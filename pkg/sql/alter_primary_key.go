@@ -175,9 +175,15 @@ func (p *planner) AlterPrimaryKey(
 		telemetry.Inc(sqltelemetry.HashShardedIndexCounter)
 	}
 
+	if err := checkIndexOpClasses(alterPKNode.Columns, nil /* invertedColTyp */); err != nil {
+		return err
+	}
 	if err := newPrimaryIndexDesc.FillColumns(alterPKNode.Columns); err != nil {
 		return err
 	}
+	if err := checkIndexNullsOrder(ctx, p.ExecCfg().Settings.Version, newPrimaryIndexDesc); err != nil {
+		return err
+	}
 	if err := tableDesc.AddIndexMutation(newPrimaryIndexDesc, descpb.DescriptorMutation_ADD); err != nil {
 		return err
 	}
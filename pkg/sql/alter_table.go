@@ -182,6 +182,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 						"unique constraints without an index are not yet supported",
 					)
 				}
+				if d.Deferrability.Deferrable() {
+					return errDeferrableUniqueIndex
+				}
 				if d.PrimaryKey {
 					// We only support "adding" a primary key when we are using the
 					// default rowid primary index or if a DROP PRIMARY KEY statement
@@ -217,9 +220,15 @@ func (n *alterTableNode) startExec(params runParams) error {
 					Unique:           true,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
+				if err := checkIndexOpClasses(d.Columns, nil /* invertedColTyp */); err != nil {
+					return err
+				}
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
+				if err := checkIndexNullsOrder(params.ctx, params.ExecCfg().Settings.Version, &idx); err != nil {
+					return err
+				}
 				if d.PartitionByIndex.ContainsPartitions() {
 					var numImplicitColumns int
 					var err error
@@ -354,6 +363,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 				// 	return err
 				// }
 
+			case *tree.ExclusionConstraintTableDef:
+				if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.ExclusionConstraints) {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"version %v must be finalized to use exclusion constraints",
+						clusterversion.ExclusionConstraints)
+				}
+				if err := ResolveExclusionConstraint(
+					params.ctx, n.tableDesc, d, NonEmptyTable, t.ValidationBehavior,
+				); err != nil {
+					return err
+				}
+
 			default:
 				return errors.AssertionFailedf(
 					"unsupported constraint: %T", t.ConstraintDef)
@@ -443,6 +464,41 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 			}
 
+			// The same goes for the functions whose bodies refer to the column.
+			// Copy out the references, since dropping a function removes them.
+			fnRefs := append([]descpb.TableDescriptor_Reference(nil), n.tableDesc.DependedOnByFunctions...)
+			for _, ref := range fnRefs {
+				found := false
+				for _, colID := range ref.ColumnIDs {
+					if colID == colToDrop.ID {
+						found = true
+						break
+					}
+				}
+				if !found {
+					continue
+				}
+				if err := params.p.canRemoveDependentFunction(
+					params.ctx, "column", string(t.Column), n.tableDesc.ParentID, ref, t.DropBehavior,
+				); err != nil {
+					return err
+				}
+				fnDesc, err := params.p.getFunctionDescForCascade(
+					params.ctx, "column", string(t.Column), n.tableDesc.ParentID, ref.ID, t.DropBehavior,
+				)
+				if err != nil {
+					return err
+				}
+				if fnDesc.Dropped() {
+					continue
+				}
+				jobDesc := fmt.Sprintf("removing function %q dependent on column %q which is being dropped",
+					fnDesc.Name, colToDrop.ColName())
+				if err := params.p.dropFunctionImpl(params.ctx, fnDesc, jobDesc, tree.DropCascade); err != nil {
+					return err
+				}
+			}
+
 			// We cannot remove this column if there are computed columns that use it.
 			computedColValidator := schemaexpr.MakeComputedColumnValidator(
 				params.ctx,
@@ -561,6 +617,23 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			n.tableDesc.UniqueWithoutIndexConstraints = n.tableDesc.UniqueWithoutIndexConstraints[:sliceIdx]
 
+			// Drop exclusion constraints which reference the column.
+			validExclusions := n.tableDesc.ExclusionConstraints[:0]
+			for _, ec := range n.tableDesc.ExclusionConstraints {
+				if descpb.ColumnIDs(ec.ColumnIDs).Contains(colToDrop.ID) {
+					if ec.Validity == descpb.ConstraintValidity_Validating {
+						return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+							"referencing constraint %q in the middle of being added, try again later", ec.Name)
+					}
+					continue
+				}
+				validExclusions = append(validExclusions, ec)
+			}
+			if len(validExclusions) != len(n.tableDesc.ExclusionConstraints) {
+				n.tableDesc.ExclusionConstraints = validExclusions
+				descriptorChanged = true
+			}
+
 			// Drop check constraints which reference the column.
 			validChecks := n.tableDesc.Checks[:0]
 			for _, check := range n.tableDesc.AllActiveAndInactiveChecks() {
@@ -718,6 +791,28 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 				foundFk.Validity = descpb.ConstraintValidity_Validated
 
+			case descpb.ConstraintTypeExclusion:
+				var foundExclusion *descpb.ExclusionConstraint
+				for i := range n.tableDesc.ExclusionConstraints {
+					ec := &n.tableDesc.ExclusionConstraints[i]
+					// If the constraint is still being validated, don't allow
+					// VALIDATE CONSTRAINT to run.
+					if ec.Name == name && ec.Validity != descpb.ConstraintValidity_Validating {
+						foundExclusion = ec
+						break
+					}
+				}
+				if foundExclusion == nil {
+					return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+						"constraint %q in the middle of being added, try again later", t.Constraint)
+				}
+				if err := validateExclusionInTxn(
+					params.ctx, params.p.LeaseMgr(), params.EvalContext(), n.tableDesc, params.EvalContext().Txn, foundExclusion,
+				); err != nil {
+					return err
+				}
+				foundExclusion.Validity = descpb.ConstraintValidity_Validated
+
 			case descpb.ConstraintTypeUnique:
 				if constraint.Index == nil {
 					var foundUnique *descpb.UniqueWithoutIndexConstraint
@@ -746,8 +841,8 @@ func (n *alterTableNode) startExec(params runParams) error {
 
 			default:
 				return pgerror.Newf(pgcode.WrongObjectType,
-					"constraint %q of relation %q is not a foreign key, check, or unique without index"+
-						" constraint", tree.ErrString(&t.Constraint), tree.ErrString(n.n.Table))
+					"constraint %q of relation %q is not a foreign key, check, exclusion, or unique"+
+						" without index constraint", tree.ErrString(&t.Constraint), tree.ErrString(n.n.Table))
 			}
 			descriptorChanged = true
 
@@ -811,6 +906,21 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableEnableTrigger:
+			if t.All {
+				for i := range n.tableDesc.Triggers {
+					n.tableDesc.Triggers[i].Disabled = !t.Enable
+				}
+			} else {
+				idx := findTrigger(n.tableDesc, t.Trigger)
+				if idx == -1 {
+					return pgerror.Newf(pgcode.UndefinedObject,
+						"trigger %q for table %q does not exist", t.Trigger, n.tableDesc.Name)
+				}
+				n.tableDesc.Triggers[idx].Disabled = !t.Enable
+			}
+			descriptorChanged = true
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -83,6 +83,13 @@ func (p *planner) AlterTableSetSchema(
 			"set schema on",
 		)
 	}
+	// The same goes for the bodies of user-defined functions.
+	if len(tableDesc.DependedOnByFunctions) > 0 {
+		return nil, p.dependentFunctionErrorByID(
+			ctx, tableDesc.TypeName(), tableDesc.Name, tableDesc.ParentID,
+			tableDesc.DependedOnByFunctions[0].ID, "set schema on",
+		)
+	}
 
 	return &alterTableSetSchemaNode{
 		newSchema: string(n.Schema),
@@ -93,6 +93,10 @@ func (n *alterTypeNode) startExec(params runParams) error {
 		err = params.p.addEnumValue(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
 	case *tree.AlterTypeRenameValue:
 		err = params.p.renameTypeValue(params.ctx, n, string(t.OldVal), string(t.NewVal))
+	case *tree.AlterTypeAddAttribute:
+		err = params.p.addCompositeAttribute(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
+	case *tree.AlterTypeDropAttribute:
+		err = params.p.dropCompositeAttribute(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
 	case *tree.AlterTypeRename:
 		if err = params.p.renameType(params.ctx, n, string(t.NewName)); err != nil {
 			return err
@@ -165,6 +169,42 @@ func (p *planner) addEnumValue(
 	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
 }
 
+func (p *planner) addCompositeAttribute(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterTypeAddAttribute, jobDesc string,
+) error {
+	if desc.Kind != descpb.TypeDescriptor_COMPOSITE {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a composite type", desc.Name)
+	}
+	typ, err := p.resolveCompositeElementType(ctx, node.Type)
+	if err != nil {
+		return err
+	}
+	if err := desc.AddCompositeElement(string(node.Name), typ); err != nil {
+		return err
+	}
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (p *planner) dropCompositeAttribute(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterTypeDropAttribute, jobDesc string,
+) error {
+	if desc.Kind != descpb.TypeDescriptor_COMPOSITE {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a composite type", desc.Name)
+	}
+	if !desc.DropCompositeElement(string(node.Name)) {
+		if node.IfExists {
+			p.BufferClientNotice(
+				ctx,
+				pgnotice.Newf("column %q of relation %q does not exist, skipping", node.Name, desc.Name),
+			)
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedColumn,
+			"column %q of relation %q does not exist", node.Name, desc.Name)
+	}
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
 func (p *planner) renameType(ctx context.Context, n *alterTypeNode, newName string) error {
 	// See if there is a name collision with the new name.
 	exists, id, err := catalogkv.LookupObjectID(
@@ -264,6 +304,9 @@ func (p *planner) performRenameTypeDesc(
 func (p *planner) renameTypeValue(
 	ctx context.Context, n *alterTypeNode, oldVal string, newVal string,
 ) error {
+	if n.desc.Kind != descpb.TypeDescriptor_ENUM {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not an enum", n.desc.Name)
+	}
 	enumMemberIndex := -1
 
 	// Do one pass to verify that the oldVal exists and there isn't already
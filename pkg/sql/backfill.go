@@ -236,6 +236,11 @@ func (sc *SchemaChanger) runBackfill(ctx context.Context) error {
 					// NOT NULL constraints are always validated before they can be added
 					constraintsToAddBeforeValidation = append(constraintsToAddBeforeValidation, *t.Constraint)
 					constraintsToValidate = append(constraintsToValidate, *t.Constraint)
+				case descpb.ConstraintToUpdate_EXCLUSION:
+					if t.Constraint.ExclusionConstraint.Validity == descpb.ConstraintValidity_Validating {
+						constraintsToAddBeforeValidation = append(constraintsToAddBeforeValidation, *t.Constraint)
+						constraintsToValidate = append(constraintsToValidate, *t.Constraint)
+					}
 				}
 			case *descpb.DescriptorMutation_PrimaryKeySwap, *descpb.DescriptorMutation_ComputedColumnSwap:
 				// The backfiller doesn't need to do anything here.
@@ -419,6 +424,25 @@ func (sc *SchemaChanger) dropConstraints(
 						constraint,
 					)
 				}
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				found := false
+				for j := range scTable.ExclusionConstraints {
+					if scTable.ExclusionConstraints[j].Name == constraint.Name {
+						scTable.ExclusionConstraints = append(
+							scTable.ExclusionConstraints[:j], scTable.ExclusionConstraints[j+1:]...,
+						)
+						found = true
+						break
+					}
+				}
+				if !found {
+					log.VEventf(
+						ctx, 2,
+						"backfiller tried to drop constraint %+v but it was not found, "+
+							"presumably due to a retry or rollback",
+						constraint,
+					)
+				}
 			}
 		}
 		if err := descsCol.WriteDescToBatch(
@@ -562,6 +586,27 @@ func (sc *SchemaChanger) addConstraints(
 						}
 					}
 				}
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				found := false
+				for j := range scTable.ExclusionConstraints {
+					ec := &scTable.ExclusionConstraints[j]
+					if ec.Name == constraint.Name {
+						log.VEventf(
+							ctx, 2,
+							"backfiller tried to add constraint %+v but found existing constraint %+v, "+
+								"presumably due to a retry or rollback",
+							constraint, ec,
+						)
+						// Ensure the constraint on the descriptor is set to Validating, in
+						// case we're in the middle of rolling back DROP CONSTRAINT
+						ec.Validity = descpb.ConstraintValidity_Validating
+						found = true
+						break
+					}
+				}
+				if !found {
+					scTable.ExclusionConstraints = append(scTable.ExclusionConstraints, constraint.ExclusionConstraint)
+				}
 			}
 		}
 		if err := descsCol.WriteDescToBatch(
@@ -668,6 +713,10 @@ func (sc *SchemaChanger) validateConstraints(
 						// return a different error code in the former case
 						return errors.Wrap(err, "validation of NOT NULL constraint failed")
 					}
+				case descpb.ConstraintToUpdate_EXCLUSION:
+					if err := validateExclusionInTxn(ctx, sc.leaseMgr, &evalCtx.EvalContext, desc, txn, &c.ExclusionConstraint); err != nil {
+						return err
+					}
 				default:
 					return errors.Errorf("unsupported constraint type: %d", c.ConstraintType)
 				}
@@ -1867,6 +1916,15 @@ func runSchemaChangesInTxn(
 							break
 						}
 					}
+				case descpb.ConstraintToUpdate_EXCLUSION:
+					for i := range tableDesc.ExclusionConstraints {
+						if tableDesc.ExclusionConstraints[i].Name == t.Constraint.Name {
+							tableDesc.ExclusionConstraints = append(
+								tableDesc.ExclusionConstraints[:i], tableDesc.ExclusionConstraints[i+1:]...,
+							)
+							break
+						}
+					}
 				default:
 					return errors.AssertionFailedf(
 						"unsupported constraint type: %d", errors.Safe(t.Constraint.ConstraintType))
@@ -1950,6 +2008,15 @@ func runSchemaChangesInTxn(
 				}
 				constraint.Check.Validity = descpb.ConstraintValidity_Validated
 			}
+		case descpb.ConstraintToUpdate_EXCLUSION:
+			if constraint.ExclusionConstraint.Validity == descpb.ConstraintValidity_Validating {
+				if err := validateExclusionInTxn(
+					ctx, planner.Descriptors().LeaseManager(), planner.EvalContext(), tableDesc, planner.txn, &constraint.ExclusionConstraint,
+				); err != nil {
+					return err
+				}
+				constraint.ExclusionConstraint.Validity = descpb.ConstraintValidity_Validated
+			}
 		case descpb.ConstraintToUpdate_FOREIGN_KEY:
 			// We can't support adding a validated foreign key constraint in the same
 			// transaction as the CREATE TABLE statement. This would require adding
@@ -1978,6 +2045,8 @@ func runSchemaChangesInTxn(
 		switch constraint.ConstraintType {
 		case descpb.ConstraintToUpdate_CHECK, descpb.ConstraintToUpdate_NOT_NULL:
 			tableDesc.Checks = append(tableDesc.Checks, &constraint.Check)
+		case descpb.ConstraintToUpdate_EXCLUSION:
+			tableDesc.ExclusionConstraints = append(tableDesc.ExclusionConstraints, constraint.ExclusionConstraint)
 		case descpb.ConstraintToUpdate_FOREIGN_KEY:
 			fk := constraint.ForeignKey
 			var referencedTableDesc *tabledesc.Mutable
@@ -2101,6 +2170,38 @@ func validateFkInTxn(
 	return validateForeignKey(ctx, tableDesc, fk, ie, txn, evalCtx.Codec)
 }
 
+// validateExclusionInTxn validates exclusion constraints within the provided
+// transaction. If the provided table descriptor version is newer than the
+// cluster version, it will be used in the InternalExecutor that performs the
+// validation query.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionInTxn(
+	ctx context.Context,
+	leaseMgr *lease.Manager,
+	evalCtx *tree.EvalContext,
+	tableDesc *tabledesc.Mutable,
+	txn *kv.Txn,
+	ec *descpb.ExclusionConstraint,
+) error {
+	ie := evalCtx.InternalExecutor.(*InternalExecutor)
+	if tableDesc.Version > tableDesc.ClusterVersion.Version {
+		newTc := descs.NewCollection(evalCtx.Settings, leaseMgr, nil /* hydratedTables */)
+		// pretend that the schema has been modified.
+		if err := newTc.AddUncommittedDescriptor(tableDesc); err != nil {
+			return err
+		}
+
+		ie.tcModifier = newTc
+		defer func() {
+			ie.tcModifier = nil
+		}()
+	}
+
+	return validateExclusionConstraint(ctx, tableDesc, ec, ie, txn)
+}
+
 // columnBackfillInTxn backfills columns for all mutation columns in
 // the mutation list.
 //
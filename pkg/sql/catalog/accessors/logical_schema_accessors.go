@@ -176,6 +176,13 @@ func (l *LogicalSchemaAccessor) GetObjectDesc(
 		} else {
 			found, desc, err = l.tc.GetImmutableTableByName(ctx, txn, &tableName, flags)
 		}
+	case tree.FunctionObject:
+		funcName := tree.MakeTableNameWithSchema(tree.Name(db), tree.Name(schema), tree.Name(object))
+		if flags.RequireMutable {
+			found, desc, err = l.tc.GetMutableFunctionByName(ctx, txn, &funcName, flags)
+		} else {
+			found, desc, err = l.tc.GetImmutableFunctionByName(ctx, txn, &funcName, flags)
+		}
 	default:
 		return nil, errors.AssertionFailedf("unknown desired object kind %d", flags.DesiredObjectKind)
 	}
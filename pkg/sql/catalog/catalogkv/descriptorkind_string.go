@@ -12,12 +12,13 @@ func _() {
 	_ = x[SchemaDescriptorKind-1]
 	_ = x[TableDescriptorKind-2]
 	_ = x[TypeDescriptorKind-3]
-	_ = x[AnyDescriptorKind-4]
+	_ = x[FunctionDescriptorKind-4]
+	_ = x[AnyDescriptorKind-5]
 }
 
-const _DescriptorKind_name = "DatabaseDescriptorKindSchemaDescriptorKindTableDescriptorKindTypeDescriptorKindAnyDescriptorKind"
+const _DescriptorKind_name = "DatabaseDescriptorKindSchemaDescriptorKindTableDescriptorKindTypeDescriptorKindFunctionDescriptorKindAnyDescriptorKind"
 
-var _DescriptorKind_index = [...]uint8{0, 22, 42, 61, 79, 96}
+var _DescriptorKind_index = [...]uint8{0, 22, 42, 61, 79, 101, 118}
 
 func (i DescriptorKind) String() string {
 	if i < 0 || i >= DescriptorKind(len(_DescriptorKind_index)-1) {
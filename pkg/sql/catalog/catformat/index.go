@@ -58,6 +58,9 @@ func IndexForDisplay(
 	}
 	f.WriteString(" (")
 	index.ColNamesFormat(f)
+	if index.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+		f.WriteString(" gin_trgm_ops")
+	}
 	f.WriteByte(')')
 
 	if index.IsSharded() {
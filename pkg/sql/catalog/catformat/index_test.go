@@ -31,7 +31,7 @@ func TestIndexForDisplay(t *testing.T) {
 	colNames := []string{"a", "b"}
 	tableDesc := testTableDesc(
 		string(table),
-		[]testCol{{colNames[0], types.Int}, {colNames[1], types.Int}},
+		[]testCol{{colNames[0], types.Int}, {colNames[1], types.Int}, {"c", types.String}},
 		nil,
 	)
 
@@ -50,6 +50,11 @@ func TestIndexForDisplay(t *testing.T) {
 	invertedIndex.Type = descpb.IndexDescriptor_INVERTED
 	invertedIndex.ColumnNames = []string{"a"}
 
+	trigramIndex := baseIndex
+	trigramIndex.Type = descpb.IndexDescriptor_INVERTED
+	trigramIndex.ColumnNames = []string{"c"}
+	trigramIndex.InvertedColumnKind = descpb.IndexDescriptor_TRIGRAM
+
 	storingIndex := baseIndex
 	storingIndex.StoreColumnNames = []string{"c"}
 
@@ -65,6 +70,7 @@ func TestIndexForDisplay(t *testing.T) {
 		{baseIndex, tableName, "INDEX baz ON foo.public.bar (a ASC, b DESC)"},
 		{uniqueIndex, descpb.AnonymousTable, "UNIQUE INDEX baz (a ASC, b DESC)"},
 		{invertedIndex, descpb.AnonymousTable, "INVERTED INDEX baz (a)"},
+		{trigramIndex, descpb.AnonymousTable, "INVERTED INDEX baz (c gin_trgm_ops)"},
 		{storingIndex, descpb.AnonymousTable, "INDEX baz (a ASC, b DESC) STORING (c)"},
 		{partialIndex, descpb.AnonymousTable, "INDEX baz (a ASC, b DESC) WHERE a > 1:::INT8"},
 	}
@@ -95,12 +95,31 @@ func ValidateColumnDefType(t *types.T) error {
 		if err := types.CheckArrayElementType(t.ArrayContents()); err != nil {
 			return err
 		}
+		if t.ArrayContents().Family() == types.TupleFamily {
+			// Arrays of tuples do not have a value encoding.
+			return pgerror.Newf(pgcode.InvalidTableDefinition,
+				"value type %s cannot be used for table columns", t.String())
+		}
 		return ValidateColumnDefType(t.ArrayContents())
 
+	case types.TupleFamily:
+		// Of the tuple types, only composite types can be used for table
+		// columns.
+		if !t.UserDefined() {
+			return pgerror.Newf(pgcode.InvalidTableDefinition,
+				"value type %s cannot be used for table columns", t.String())
+		}
+		for _, elemTyp := range t.TupleContents() {
+			if err := ValidateColumnDefType(elemTyp); err != nil {
+				return err
+			}
+		}
+
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.TimeTZFamily,
-		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily:
+		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily,
+		types.RangeFamily:
 		// These types are OK.
 
 	default:
@@ -126,6 +145,12 @@ func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 		family == types.GeographyFamily || family == types.GeometryFamily
 }
 
+// ColumnTypeIsTrigramIndexable returns whether the type t is valid to be
+// indexed using a trigram inverted index.
+func ColumnTypeIsTrigramIndexable(t *types.T) bool {
+	return t.Family() == types.StringFamily
+}
+
 // MustBeValueEncoded returns true if columns of the given kind can only be value
 // encoded.
 func MustBeValueEncoded(semanticType *types.T) bool {
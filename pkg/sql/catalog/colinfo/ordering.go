@@ -22,6 +22,23 @@ import (
 type ColumnOrderInfo struct {
 	ColIdx    int
 	Direction encoding.Direction
+	// NullsReversed is true if NULLs are ordered opposite to the default for
+	// the direction: last if the column is ascending (NULLS LAST), or first if
+	// it is descending (NULLS FIRST).
+	NullsReversed bool
+}
+
+// CompareWithNulls adjusts cmp, the result of comparing two values of the
+// column in ascending order, to this column's direction and NULL ordering.
+// lhsNull and rhsNull indicate whether the respective values are NULL.
+func (c ColumnOrderInfo) CompareWithNulls(cmp int, lhsNull, rhsNull bool) int {
+	if c.NullsReversed && lhsNull != rhsNull {
+		cmp = -cmp
+	}
+	if c.Direction == encoding.Descending {
+		cmp = -cmp
+	}
+	return cmp
 }
 
 // ColumnOrdering is used to describe a desired column ordering. For example,
@@ -44,6 +61,13 @@ func (ordering ColumnOrdering) String(columns ResultColumns) string {
 
 		fmtCtx.FormatNameP(&columns[o.ColIdx].Name)
 		_, _ = fmtCtx.WriteTo(&buf)
+		if o.NullsReversed {
+			if o.Direction == encoding.Descending {
+				buf.WriteString(" nulls first")
+			} else {
+				buf.WriteString(" nulls last")
+			}
+		}
 	}
 	fmtCtx.Close()
 	return buf.String()
@@ -63,10 +87,7 @@ func CompareDatums(ordering ColumnOrdering, evalCtx *tree.EvalContext, lhs, rhs
 		// types for a column for different rows. Investigate how other RDBMs
 		// handle this.
 		if cmp := lhs[c.ColIdx].Compare(evalCtx, rhs[c.ColIdx]); cmp != 0 {
-			if c.Direction == encoding.Descending {
-				cmp = -cmp
-			}
-			return cmp
+			return c.CompareWithNulls(cmp, lhs[c.ColIdx] == tree.DNull, rhs[c.ColIdx] == tree.DNull)
 		}
 	}
 	return 0
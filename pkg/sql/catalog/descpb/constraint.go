@@ -95,6 +95,8 @@ const (
 	ConstraintTypeUnique ConstraintType = "UNIQUE"
 	// ConstraintTypeCheck identifies a CHECK constraint.
 	ConstraintTypeCheck ConstraintType = "CHECK"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // ConstraintDetail describes a constraint.
@@ -119,4 +121,22 @@ type ConstraintDetail struct {
 
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
+
+	// Only populated for Exclusion Constraints.
+	ExclusionConstraint *ExclusionConstraint
+}
+
+// ExclusionOperators are the comparison operators which can be used in
+// exclusion constraints. Only commutative operators can be used, since the
+// new rows are compared against the existing rows in either order.
+var ExclusionOperators = map[string]tree.ComparisonOperator{
+	tree.EQ.String():       tree.EQ,
+	tree.NE.String():       tree.NE,
+	tree.Overlaps.String(): tree.Overlaps,
+}
+
+// Operator returns the comparison operator of the ith column of the
+// exclusion constraint.
+func (c *ExclusionConstraint) Operator(i int) tree.ComparisonOperator {
+	return ExclusionOperators[c.Operators[i]]
 }
@@ -13,6 +13,8 @@ package descpb
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
@@ -90,15 +92,29 @@ func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtx) {
 		if desc.Type != IndexDescriptor_INVERTED {
 			ctx.WriteByte(' ')
 			ctx.WriteString(desc.ColumnDirections[i].String())
+			if desc.ColumnNullsReversedAt(i) {
+				if desc.ColumnDirections[i] == IndexDescriptor_DESC {
+					ctx.WriteString(" NULLS FIRST")
+				} else {
+					ctx.WriteString(" NULLS LAST")
+				}
+			}
 		}
 	}
 }
 
-// FillColumns sets the column names and directions in desc.
+// ColumnNullsReversedAt returns true if NULLs in the i-th column of the index
+// sort opposite to the default for the column's direction.
+func (desc *IndexDescriptor) ColumnNullsReversedAt(i int) bool {
+	return i < len(desc.ColumnNullsReversed) && desc.ColumnNullsReversed[i]
+}
+
+// FillColumns sets the column names, directions and NULLs orders in desc.
 func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
-	for _, c := range elems {
+	desc.ColumnNullsReversed = nil
+	for i, c := range elems {
 		if c.Expr != nil {
 			return unimplemented.NewWithIssuef(9682, "only simple columns are supported as index elements")
 		}
@@ -111,6 +127,16 @@ func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 		default:
 			return fmt.Errorf("invalid direction %s for column %s", c.Direction, c.Column)
 		}
+		if c.NullsOrder.IsReversed(c.Direction) {
+			if desc.Type == IndexDescriptor_INVERTED && i == len(elems)-1 {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s is not supported for the inverted column of an index", c.NullsOrder)
+			}
+			if desc.ColumnNullsReversed == nil {
+				desc.ColumnNullsReversed = make([]bool, len(elems))
+			}
+			desc.ColumnNullsReversed[i] = true
+		}
 	}
 	return nil
 }
@@ -236,6 +236,7 @@ type TableDescriptor interface {
 
 	ForeachDependedOnBy(f func(dep *descpb.TableDescriptor_Reference) error) error
 	GetDependsOn() []descpb.ID
+	GetDependedOnByFunctions() []descpb.TableDescriptor_Reference
 	GetConstraintInfoWithLookup(fn TableLookupFn) (map[string]descpb.ConstraintDetail, error)
 	ForeachOutboundFK(f func(fk *descpb.ForeignKeyConstraint) error) error
 	GetChecks() []*descpb.TableDescriptor_CheckConstraint
@@ -243,6 +244,8 @@ type TableDescriptor interface {
 	ActiveChecks() []descpb.TableDescriptor_CheckConstraint
 	GetUniqueWithoutIndexConstraints() []descpb.UniqueWithoutIndexConstraint
 	AllActiveAndInactiveUniqueWithoutIndexConstraints() []*descpb.UniqueWithoutIndexConstraint
+	GetExclusionConstraints() []descpb.ExclusionConstraint
+	AllActiveAndInactiveExclusionConstraints() []*descpb.ExclusionConstraint
 	ForeachInboundFK(f func(fk *descpb.ForeignKeyConstraint) error) error
 	FindActiveColumnByName(s string) (*descpb.ColumnDescriptor, error)
 	WritableColumns() []descpb.ColumnDescriptor
@@ -332,6 +335,7 @@ type Index interface {
 	GetColumnID(columnOrdinal int) descpb.ColumnID
 	GetColumnName(columnOrdinal int) string
 	GetColumnDirection(columnOrdinal int) descpb.IndexDescriptor_Direction
+	GetColumnNullsReversed(columnOrdinal int) bool
 
 	ForEachColumnID(func(id descpb.ColumnID) error) error
 	ContainsColumnID(colID descpb.ColumnID) bool
@@ -364,6 +368,14 @@ type TypeDescriptor interface {
 	Validate(ctx context.Context, dg DescGetter) error
 }
 
+// FunctionDescriptor will eventually be called funcdesc.Descriptor.
+// It is implemented by Immutable.
+type FunctionDescriptor interface {
+	Descriptor
+	FuncDesc() *descpb.FunctionDescriptor
+	Validate(ctx context.Context, dg DescGetter) error
+}
+
 // TypeDescriptorResolver is an interface used during hydration of type
 // metadata in types.T's. It is similar to tree.TypeReferenceResolver, except
 // that it has the power to return TypeDescriptor, rather than only a
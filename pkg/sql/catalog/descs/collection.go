@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/hydratedtables"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
@@ -647,6 +648,62 @@ func (tc *Collection) getTypeByName(
 	return true, typ, nil
 }
 
+// GetMutableFunctionByName returns a mutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is ignored.
+func (tc *Collection) GetMutableFunctionByName(
+	ctx context.Context, txn *kv.Txn, name tree.ObjectName, flags tree.ObjectLookupFlags,
+) (found bool, _ *funcdesc.Mutable, _ error) {
+	found, desc, err := tc.getFunctionByName(ctx, txn, name, flags, true /* mutable */)
+	if err != nil || !found {
+		return false, nil, err
+	}
+	return true, desc.(*funcdesc.Mutable), nil
+}
+
+// GetImmutableFunctionByName returns an immutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is ignored.
+func (tc *Collection) GetImmutableFunctionByName(
+	ctx context.Context, txn *kv.Txn, name tree.ObjectName, flags tree.ObjectLookupFlags,
+) (found bool, _ *funcdesc.Immutable, _ error) {
+	found, desc, err := tc.getFunctionByName(ctx, txn, name, flags, false /* mutable */)
+	if err != nil || !found {
+		return false, nil, err
+	}
+	return true, desc.(*funcdesc.Immutable), nil
+}
+
+// getFunctionByName returns a function descriptor with properties according
+// to the provided lookup flags.
+func (tc *Collection) getFunctionByName(
+	ctx context.Context,
+	txn *kv.Txn,
+	name tree.ObjectName,
+	flags tree.ObjectLookupFlags,
+	mutable bool,
+) (found bool, _ catalog.FunctionDescriptor, err error) {
+	found, desc, err := tc.getObjectByName(
+		ctx, txn, name.Catalog(), name.Schema(), name.Object(), flags, mutable)
+	if err != nil {
+		return false, nil, err
+	} else if !found {
+		if flags.Required {
+			return false, nil, sqlerrors.NewUndefinedFunctionError(tree.ErrString(name))
+		}
+		return false, nil, nil
+	}
+	fn, ok := desc.(catalog.FunctionDescriptor)
+	if !ok {
+		if flags.Required {
+			return false, nil, sqlerrors.NewUndefinedFunctionError(tree.ErrString(name))
+		}
+		return false, nil, nil
+	}
+	if dropped, err := filterDescriptorState(fn, flags.Required, flags.CommonLookupFlags); err != nil || dropped {
+		return false, nil, err
+	}
+	return true, fn, nil
+}
+
 // TODO (lucy): Should this just take a database name? We're separately
 // resolving the database name in lots of places where we (indirectly) call
 // this.
@@ -1457,6 +1514,53 @@ func (tc *Collection) getTypeByID(
 	return typ, nil
 }
 
+// GetMutableFunctionByID returns a mutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is ignored.
+// Required is ignored, and an error is always returned if no descriptor with
+// the ID exists.
+func (tc *Collection) GetMutableFunctionByID(
+	ctx context.Context, txn *kv.Txn, funcID descpb.ID, flags tree.ObjectLookupFlags,
+) (*funcdesc.Mutable, error) {
+	desc, err := tc.getFunctionByID(ctx, txn, funcID, flags, true /* mutable */)
+	if err != nil {
+		return nil, err
+	}
+	return desc.(*funcdesc.Mutable), nil
+}
+
+// GetImmutableFunctionByID returns an immutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is ignored.
+// Required is ignored, and an error is always returned if no descriptor with
+// the ID exists.
+func (tc *Collection) GetImmutableFunctionByID(
+	ctx context.Context, txn *kv.Txn, funcID descpb.ID, flags tree.ObjectLookupFlags,
+) (*funcdesc.Immutable, error) {
+	desc, err := tc.getFunctionByID(ctx, txn, funcID, flags, false /* mutable */)
+	if err != nil {
+		return nil, err
+	}
+	return desc.(*funcdesc.Immutable), nil
+}
+
+func (tc *Collection) getFunctionByID(
+	ctx context.Context, txn *kv.Txn, funcID descpb.ID, flags tree.ObjectLookupFlags, mutable bool,
+) (catalog.FunctionDescriptor, error) {
+	desc, err := tc.getDescriptorByID(ctx, txn, funcID, flags.CommonLookupFlags, mutable)
+	if err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil, pgerror.Newf(
+				pgcode.UndefinedFunction, "function with ID %d does not exist", funcID)
+		}
+		return nil, err
+	}
+	fn, ok := desc.(catalog.FunctionDescriptor)
+	if !ok {
+		return nil, pgerror.Newf(
+			pgcode.UndefinedFunction, "function with ID %d does not exist", funcID)
+	}
+	return fn, nil
+}
+
 // getUncommittedDescriptor returns a descriptor for the requested name
 // if the requested name is for a descriptor modified within the transaction
 // affiliated with the Collection.
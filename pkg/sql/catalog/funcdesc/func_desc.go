@@ -0,0 +1,437 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package funcdesc contains the concrete implementations of
+// catalog.FunctionDescriptor.
+package funcdesc
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+var _ catalog.FunctionDescriptor = (*Immutable)(nil)
+var _ catalog.FunctionDescriptor = (*Mutable)(nil)
+var _ catalog.MutableDescriptor = (*Mutable)(nil)
+
+// Immutable wraps a Function descriptor and provides methods on it.
+type Immutable struct {
+	descpb.FunctionDescriptor
+
+	// isUncommittedVersion is set to true if this descriptor was created from
+	// a copy of a Mutable with an uncommitted version.
+	isUncommittedVersion bool
+}
+
+// Mutable is a mutable reference to a FunctionDescriptor.
+type Mutable struct {
+	Immutable
+
+	// ClusterVersion represents the version of the function descriptor read
+	// from the store.
+	ClusterVersion *Immutable
+}
+
+var _ redact.SafeMessager = (*Immutable)(nil)
+
+// NewCreatedMutable returns a Mutable from the given function descriptor with
+// the cluster version being the zero function. This is for a function that is
+// created in the same transaction.
+func NewCreatedMutable(desc descpb.FunctionDescriptor) *Mutable {
+	return &Mutable{
+		Immutable: makeImmutable(desc),
+	}
+}
+
+// NewExistingMutable returns a Mutable from the given function descriptor with
+// the cluster version also set to the descriptor. This is for functions that
+// already exist.
+func NewExistingMutable(desc descpb.FunctionDescriptor) *Mutable {
+	return &Mutable{
+		Immutable:      makeImmutable(*protoutil.Clone(&desc).(*descpb.FunctionDescriptor)),
+		ClusterVersion: NewImmutable(desc),
+	}
+}
+
+// NewImmutable returns an Immutable from the given FunctionDescriptor.
+func NewImmutable(desc descpb.FunctionDescriptor) *Immutable {
+	m := makeImmutable(desc)
+	return &m
+}
+
+func makeImmutable(desc descpb.FunctionDescriptor) Immutable {
+	return Immutable{FunctionDescriptor: desc}
+}
+
+// SafeMessage makes Immutable a SafeMessager.
+func (desc *Immutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.Immutable", desc)
+}
+
+// SafeMessage makes Mutable a SafeMessager.
+func (desc *Mutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.Mutable", desc)
+}
+
+func formatSafeMessage(typeName string, desc catalog.FunctionDescriptor) string {
+	var buf redact.StringBuilder
+	buf.Printf(typeName + ": {")
+	catalog.FormatSafeDescriptorProperties(&buf, desc)
+	fd := desc.FuncDesc()
+	buf.Printf(", NumParams: %d", len(fd.Params))
+	buf.Printf(", Volatility: %s", fd.Volatility)
+	for i := range fd.ReferencingDescriptorIDs {
+		buf.Printf(", ")
+		if i == 0 {
+			buf.Printf("ReferencingDescriptorIDs: [")
+		}
+		buf.Printf("%d", fd.ReferencingDescriptorIDs[i])
+	}
+	if len(fd.ReferencingDescriptorIDs) > 0 {
+		buf.Printf("]")
+	}
+	buf.Printf("}")
+	return buf.String()
+}
+
+// NameResolutionResult implements the NameResolutionResult interface.
+func (desc *Immutable) NameResolutionResult() {}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *Immutable) IsUncommittedVersion() bool {
+	return desc.isUncommittedVersion
+}
+
+// GetAuditMode implements the DescriptorProto interface.
+func (desc *Immutable) GetAuditMode() descpb.TableDescriptor_AuditMode {
+	return descpb.TableDescriptor_DISABLED
+}
+
+// TypeName implements the DescriptorProto interface.
+func (desc *Immutable) TypeName() string {
+	return "function"
+}
+
+// FuncDesc implements the FunctionDescriptor interface.
+func (desc *Immutable) FuncDesc() *descpb.FunctionDescriptor {
+	return &desc.FunctionDescriptor
+}
+
+// Public implements the Descriptor interface.
+func (desc *Immutable) Public() bool {
+	return desc.State == descpb.DescriptorState_PUBLIC
+}
+
+// Adding implements the Descriptor interface.
+func (desc *Immutable) Adding() bool {
+	return false
+}
+
+// Offline implements the Descriptor interface.
+func (desc *Immutable) Offline() bool {
+	return desc.State == descpb.DescriptorState_OFFLINE
+}
+
+// Dropped implements the Descriptor interface.
+func (desc *Immutable) Dropped() bool {
+	return desc.State == descpb.DescriptorState_DROP
+}
+
+// DescriptorProto wraps a FunctionDescriptor in a Descriptor.
+func (desc *Immutable) DescriptorProto() *descpb.Descriptor {
+	return &descpb.Descriptor{
+		Union: &descpb.Descriptor_Function{
+			Function: &desc.FunctionDescriptor,
+		},
+	}
+}
+
+// TreeVolatility returns the volatility of the function as a tree.Volatility.
+func (desc *Immutable) TreeVolatility() tree.Volatility {
+	switch desc.Volatility {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		if desc.LeakProof {
+			return tree.VolatilityLeakProof
+		}
+		return tree.VolatilityImmutable
+	case descpb.FunctionDescriptor_STABLE:
+		return tree.VolatilityStable
+	default:
+		return tree.VolatilityVolatile
+	}
+}
+
+// IsStrict returns whether the function returns NULL without being evaluated
+// when any of its arguments is NULL.
+func (desc *Immutable) IsStrict() bool {
+	return desc.NullInputBehavior == descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+}
+
+// Validate performs validation on the FunctionDescriptor.
+func (desc *Immutable) Validate(ctx context.Context, dg catalog.DescGetter) error {
+	// Validate local properties of the descriptor.
+	if err := catalog.ValidateName(desc.Name, "function"); err != nil {
+		return err
+	}
+	if desc.ID == descpb.InvalidID {
+		return errors.AssertionFailedf("invalid ID %d", errors.Safe(desc.ID))
+	}
+	if desc.ParentID == descpb.InvalidID {
+		return errors.AssertionFailedf("invalid parentID %d", errors.Safe(desc.ParentID))
+	}
+	if desc.ReturnType == nil {
+		return errors.AssertionFailedf("missing return type")
+	}
+	names := make(map[string]struct{}, len(desc.Params))
+	for i := range desc.Params {
+		p := &desc.Params[i]
+		if p.Type == nil {
+			return errors.AssertionFailedf("missing type for parameter %d", errors.Safe(i+1))
+		}
+		if p.Name == "" {
+			continue
+		}
+		if _, ok := names[p.Name]; ok {
+			return errors.AssertionFailedf("duplicate parameter name %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+	}
+	if desc.FunctionBody == "" {
+		return errors.AssertionFailedf("empty function body")
+	}
+	if desc.LeakProof && desc.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		return errors.AssertionFailedf("leakproof function is not immutable")
+	}
+
+	// Validate the Privileges of the descriptor.
+	if err := desc.Privileges.Validate(desc.ID, privilege.Function); err != nil {
+		return err
+	}
+
+	// Don't validate cross-references for dropped descriptors.
+	if desc.Dropped() || dg == nil {
+		return nil
+	}
+
+	// Validate all cross references on the descriptor.
+
+	// Buffer all the requested requests and error checks together to run at once.
+	var checks []func(got catalog.Descriptor) error
+	var reqs []descpb.ID
+
+	// Validate the parentID.
+	reqs = append(reqs, desc.ParentID)
+	checks = append(checks, func(got catalog.Descriptor) error {
+		if _, isDB := got.(catalog.DatabaseDescriptor); !isDB {
+			return errors.AssertionFailedf("parentID %d does not exist", errors.Safe(desc.ParentID))
+		}
+		return nil
+	})
+
+	// Validate the parentSchemaID.
+	if desc.ParentSchemaID != keys.PublicSchemaID {
+		reqs = append(reqs, desc.ParentSchemaID)
+		checks = append(checks, func(got catalog.Descriptor) error {
+			if _, isSchema := got.(catalog.SchemaDescriptor); !isSchema {
+				return errors.AssertionFailedf("parentSchemaID %d does not exist", errors.Safe(desc.ParentSchemaID))
+			}
+			return nil
+		})
+	}
+
+	// Validate that all of the referencing descriptors exist.
+	tableExists := func(id descpb.ID) func(got catalog.Descriptor) error {
+		return func(got catalog.Descriptor) error {
+			if _, isTable := got.(catalog.TableDescriptor); !isTable {
+				return errors.AssertionFailedf("referencing descriptor %d does not exist", id)
+			}
+			return nil
+		}
+	}
+	for _, id := range desc.ReferencingDescriptorIDs {
+		reqs = append(reqs, id)
+		checks = append(checks, tableExists(id))
+	}
+
+	// Validate that the relations and types the body depends on exist, and
+	// that the relations have a back-reference to this function.
+	if !desc.Dropped() {
+		for _, id := range desc.DependsOn {
+			id := id
+			reqs = append(reqs, id)
+			checks = append(checks, func(got catalog.Descriptor) error {
+				tbl, isTable := got.(catalog.TableDescriptor)
+				if !isTable {
+					return errors.AssertionFailedf("depends-on relation %d does not exist", id)
+				}
+				if tbl.Dropped() {
+					return nil
+				}
+				for _, ref := range tbl.GetDependedOnByFunctions() {
+					if ref.ID == desc.ID {
+						return nil
+					}
+				}
+				return errors.AssertionFailedf(
+					"depends-on relation %q (%d) has no corresponding depended-on-by back reference",
+					tbl.GetName(), id)
+			})
+		}
+		for _, id := range desc.DependsOnTypes {
+			id := id
+			reqs = append(reqs, id)
+			checks = append(checks, func(got catalog.Descriptor) error {
+				if _, isType := got.(catalog.TypeDescriptor); !isType {
+					return errors.AssertionFailedf("depends-on type %d does not exist", id)
+				}
+				return nil
+			})
+		}
+	}
+
+	descs, err := dg.GetDescs(ctx, reqs)
+	if err != nil {
+		return err
+	}
+
+	// For each result in the batch, apply the corresponding check.
+	for i := range checks {
+		if err := checks[i](descs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *Mutable) IsUncommittedVersion() bool {
+	return desc.IsNew() || desc.ClusterVersion.GetVersion() != desc.GetVersion()
+}
+
+// SetDrainingNames implements the MutableDescriptor interface.
+func (desc *Mutable) SetDrainingNames(names []descpb.NameInfo) {
+	desc.DrainingNames = names
+}
+
+// AddDrainingName adds a draining name to the FunctionDescriptor's slice of
+// draining names.
+func (desc *Mutable) AddDrainingName(name descpb.NameInfo) {
+	desc.DrainingNames = append(desc.DrainingNames, name)
+}
+
+// MaybeIncrementVersion implements the MutableDescriptor interface.
+func (desc *Mutable) MaybeIncrementVersion() {
+	// Already incremented, no-op.
+	if desc.ClusterVersion == nil || desc.Version == desc.ClusterVersion.Version+1 {
+		return
+	}
+	desc.Version++
+	desc.ModificationTime = hlc.Timestamp{}
+}
+
+// OriginalName implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalName() string {
+	if desc.ClusterVersion == nil {
+		return ""
+	}
+	return desc.ClusterVersion.Name
+}
+
+// OriginalID implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalID() descpb.ID {
+	if desc.ClusterVersion == nil {
+		return descpb.InvalidID
+	}
+	return desc.ClusterVersion.ID
+}
+
+// OriginalVersion implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalVersion() descpb.DescriptorVersion {
+	if desc.ClusterVersion == nil {
+		return 0
+	}
+	return desc.ClusterVersion.Version
+}
+
+// ImmutableCopy implements the MutableDescriptor interface.
+func (desc *Mutable) ImmutableCopy() catalog.Descriptor {
+	imm := NewImmutable(*protoutil.Clone(desc.FuncDesc()).(*descpb.FunctionDescriptor))
+	imm.isUncommittedVersion = desc.IsUncommittedVersion()
+	return imm
+}
+
+// IsNew implements the MutableDescriptor interface.
+func (desc *Mutable) IsNew() bool {
+	return desc.ClusterVersion == nil
+}
+
+// SetPublic implements the MutableDescriptor interface.
+func (desc *Mutable) SetPublic() {
+	desc.State = descpb.DescriptorState_PUBLIC
+	desc.OfflineReason = ""
+}
+
+// SetDropped implements the MutableDescriptor interface.
+func (desc *Mutable) SetDropped() {
+	desc.State = descpb.DescriptorState_DROP
+	desc.OfflineReason = ""
+}
+
+// SetOffline implements the MutableDescriptor interface.
+func (desc *Mutable) SetOffline(reason string) {
+	desc.State = descpb.DescriptorState_OFFLINE
+	desc.OfflineReason = reason
+}
+
+// AddReferencingDescriptorID adds a new referencing descriptor ID to the
+// FunctionDescriptor. It ensures that duplicates are not added.
+func (desc *Mutable) AddReferencingDescriptorID(new descpb.ID) {
+	for _, id := range desc.ReferencingDescriptorIDs {
+		if new == id {
+			return
+		}
+	}
+	desc.ReferencingDescriptorIDs = append(desc.ReferencingDescriptorIDs, new)
+}
+
+// RemoveReferencingDescriptorID removes the desired referencing descriptor ID
+// from the FunctionDescriptor. It has no effect if the requested ID is not
+// present.
+func (desc *Mutable) RemoveReferencingDescriptorID(remove descpb.ID) {
+	for i, id := range desc.ReferencingDescriptorIDs {
+		if id == remove {
+			desc.ReferencingDescriptorIDs = append(desc.ReferencingDescriptorIDs[:i], desc.ReferencingDescriptorIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+// VolatilityFromTree converts a tree.Volatility into the representation
+// stored in the descriptor. LeakProof is represented separately in the
+// descriptor and maps to IMMUTABLE.
+func VolatilityFromTree(v tree.Volatility) descpb.FunctionDescriptor_Volatility {
+	switch v {
+	case tree.VolatilityLeakProof, tree.VolatilityImmutable:
+		return descpb.FunctionDescriptor_IMMUTABLE
+	case tree.VolatilityStable:
+		return descpb.FunctionDescriptor_STABLE
+	default:
+		return descpb.FunctionDescriptor_VOLATILE
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFunctionDesc(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	makeDesc := func() descpb.FunctionDescriptor {
+		return descpb.FunctionDescriptor{
+			Name:         "f",
+			ID:           52,
+			ParentID:     50,
+			Params:       []descpb.FunctionDescriptor_Param{{Name: "a", Type: types.Int}},
+			ReturnType:   types.Int,
+			Volatility:   descpb.FunctionDescriptor_IMMUTABLE,
+			Privileges:   descpb.NewDefaultPrivilegeDescriptor(security.RootUserName()),
+			FunctionBody: "SELECT a + 1",
+		}
+	}
+
+	testData := []struct {
+		err    string
+		mutate func(desc *descpb.FunctionDescriptor)
+	}{
+		{``, func(desc *descpb.FunctionDescriptor) {}},
+		{`empty function name`, func(desc *descpb.FunctionDescriptor) {
+			desc.Name = ""
+		}},
+		{`invalid ID 0`, func(desc *descpb.FunctionDescriptor) {
+			desc.ID = 0
+		}},
+		{`invalid parentID 0`, func(desc *descpb.FunctionDescriptor) {
+			desc.ParentID = 0
+		}},
+		{`missing return type`, func(desc *descpb.FunctionDescriptor) {
+			desc.ReturnType = nil
+		}},
+		{`missing type for parameter 1`, func(desc *descpb.FunctionDescriptor) {
+			desc.Params[0].Type = nil
+		}},
+		{`duplicate parameter name "a"`, func(desc *descpb.FunctionDescriptor) {
+			desc.Params = append(desc.Params, descpb.FunctionDescriptor_Param{Name: "a", Type: types.Int})
+		}},
+		{`empty function body`, func(desc *descpb.FunctionDescriptor) {
+			desc.FunctionBody = ""
+		}},
+		{`leakproof function is not immutable`, func(desc *descpb.FunctionDescriptor) {
+			desc.LeakProof = true
+			desc.Volatility = descpb.FunctionDescriptor_STABLE
+		}},
+	}
+	for _, test := range testData {
+		t.Run(test.err, func(t *testing.T) {
+			desc := makeDesc()
+			test.mutate(&desc)
+			err := funcdesc.NewImmutable(desc).Validate(context.Background(), nil /* dg */)
+			if test.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestReferencingDescriptorIDs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := funcdesc.NewCreatedMutable(descpb.FunctionDescriptor{ID: 52})
+	desc.AddReferencingDescriptorID(53)
+	desc.AddReferencingDescriptorID(54)
+	desc.AddReferencingDescriptorID(53)
+	require.Equal(t, []descpb.ID{53, 54}, desc.ReferencingDescriptorIDs)
+	desc.RemoveReferencingDescriptorID(53)
+	desc.RemoveReferencingDescriptorID(55)
+	require.Equal(t, []descpb.ID{54}, desc.ReferencingDescriptorIDs)
+}
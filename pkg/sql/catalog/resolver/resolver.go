@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -145,6 +146,23 @@ func ResolveMutableType(
 	return &tn, desc.(*typedesc.Mutable), nil
 }
 
+// ResolveMutableFunction resolves a function descriptor for mutable access.
+// It returns the resolved descriptor, as well as the resolved prefix of the
+// function name.
+func ResolveMutableFunction(
+	ctx context.Context, sc SchemaResolver, un *tree.UnresolvedObjectName, required bool,
+) (tree.ObjectNamePrefix, *funcdesc.Mutable, error) {
+	lookupFlags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: required, RequireMutable: true},
+		DesiredObjectKind: tree.FunctionObject,
+	}
+	desc, prefix, err := ResolveExistingObject(ctx, sc, un, lookupFlags)
+	if err != nil || desc == nil {
+		return prefix, nil, err
+	}
+	return prefix, desc.(*funcdesc.Mutable), nil
+}
+
 // ResolveExistingObject resolves an object with the given flags.
 func ResolveExistingObject(
 	ctx context.Context,
@@ -209,6 +227,14 @@ func ResolveExistingObject(
 		}
 
 		return descI.(*tabledesc.Immutable), prefix, nil
+	case tree.FunctionObject:
+		if _, isFunc := obj.(catalog.FunctionDescriptor); !isFunc {
+			return nil, prefix, sqlerrors.NewUndefinedFunctionError(tree.ErrString(&resolvedTn))
+		}
+		if lookupFlags.RequireMutable {
+			return obj.(*funcdesc.Mutable), prefix, nil
+		}
+		return obj.(*funcdesc.Immutable), prefix, nil
 	default:
 		return nil, prefix, errors.AssertionFailedf(
 			"unknown desired object kind %d", lookupFlags.DesiredObjectKind)
@@ -10,7 +10,73 @@
 
 package schemaexpr
 
-import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// MakeRowFilterExpr parses a boolean expression over the columns of a single
+// table and returns it type-checked and normalized, with its column references
+// replaced by IndexedVars referring to the ordinals of cols. The result can be
+// evaluated with RunFilter using a RowIndexedVarContainer with the same cols.
+// The set of column IDs referenced by the expression is also returned.
+//
+// The expression may not contain subqueries, aggregate, window or set
+// returning functions, or functions that are not immutable, since it is
+// evaluated outside of a SQL statement, possibly long after it was written.
+// The op string is used in error messages to describe the expression.
+func MakeRowFilterExpr(
+	ctx context.Context,
+	expr string,
+	op string,
+	cols []descpb.ColumnDescriptor,
+	tableDesc catalog.TableDescriptor,
+	evalCtx *tree.EvalContext,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, catalog.TableColSet, error) {
+	var refColIDs catalog.TableColSet
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, refColIDs, err
+	}
+	if refColIDs, err = ExtractColumnIDs(tableDesc, parsed); err != nil {
+		return nil, refColIDs, err
+	}
+
+	tn := tree.NewUnqualifiedTableName(tree.Name(tableDesc.GetName()))
+	nr := newNameResolver(evalCtx, tableDesc.GetID(), tn, columnDescriptorsToPtrs(cols))
+	nr.addIVarContainerToSemaCtx(semaCtx)
+	resolved, err := nr.resolveNames(parsed)
+	if err != nil {
+		return nil, refColIDs, err
+	}
+
+	defer semaCtx.Properties.Restore(semaCtx.Properties)
+	semaCtx.Properties.Require(op,
+		tree.RejectSpecial|tree.RejectSubqueries|tree.RejectStableOperators|tree.RejectVolatileFunctions)
+	typedExpr, err := tree.TypeCheck(ctx, resolved, semaCtx, types.Bool)
+	if err != nil {
+		return nil, refColIDs, err
+	}
+	if typ := typedExpr.ResolvedType(); !typ.Equivalent(types.Bool) && typedExpr != tree.DNull {
+		return nil, refColIDs, pgerror.Newf(pgcode.DatatypeMismatch,
+			"expected %s expression to have type %s, but '%s' has type %s", op, types.Bool, expr, typ)
+	}
+
+	var txCtx transform.ExprTransformContext
+	if typedExpr, err = txCtx.NormalizeExpr(evalCtx, typedExpr); err != nil {
+		return nil, refColIDs, err
+	}
+	return typedExpr, refColIDs, nil
+}
 
 // RunFilter runs a filter expression and returns whether the filter passes.
 func RunFilter(filter tree.TypedExpr, evalCtx *tree.EvalContext) (bool, error) {
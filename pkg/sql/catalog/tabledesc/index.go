@@ -267,6 +267,12 @@ func (w index) GetColumnDirection(columnOrdinal int) descpb.IndexDescriptor_Dire
 	return w.desc.ColumnDirections[columnOrdinal]
 }
 
+// GetColumnNullsReversed returns true if NULLs in the columnOrdinal-th column
+// sort opposite to the default for the column's direction.
+func (w index) GetColumnNullsReversed(columnOrdinal int) bool {
+	return w.desc.ColumnNullsReversedAt(columnOrdinal)
+}
+
 // ForEachColumnID applies its argument fn to each of the column IDs in the
 // index descriptor. If there is an error, that error is returned immediately.
 func (w index) ForEachColumnID(fn func(colID descpb.ColumnID) error) error {
@@ -156,7 +156,11 @@ func formatSafeIndex(
 		if i > 0 {
 			w.Printf(", ")
 		}
-		w.Printf("{ID: %d, Dir: %s}", idx.ColumnIDs[i], idx.ColumnDirections[i])
+		w.Printf("{ID: %d, Dir: %s", idx.ColumnIDs[i], idx.ColumnDirections[i])
+		if idx.ColumnNullsReversedAt(i) {
+			w.Printf(", NullsReversed: true")
+		}
+		w.Printf("}")
 	}
 	w.Printf("]")
 	if len(idx.ExtraColumnIDs) > 0 {
@@ -386,6 +386,31 @@ func (desc *wrapper) AllActiveAndInactiveUniqueWithoutIndexConstraints() []*desc
 	return ucs
 }
 
+// AllActiveAndInactiveExclusionConstraints returns all exclusion constraints,
+// including both "active" ones on the table descriptor which are being
+// enforced for all writes, and "inactive" ones queued in the mutations list.
+func (desc *wrapper) AllActiveAndInactiveExclusionConstraints() []*descpb.ExclusionConstraint {
+	ecs := make([]*descpb.ExclusionConstraint, 0, len(desc.ExclusionConstraints))
+	for i := range desc.ExclusionConstraints {
+		ec := &desc.ExclusionConstraints[i]
+		// While a constraint is being validated for existing rows or being dropped,
+		// the constraint is present both on the table descriptor and in the
+		// mutations list in the Validating or Dropping state, so those constraints
+		// are excluded here to avoid double-counting.
+		if ec.Validity != descpb.ConstraintValidity_Validating &&
+			ec.Validity != descpb.ConstraintValidity_Dropping {
+			ecs = append(ecs, ec)
+		}
+	}
+	for i := range desc.Mutations {
+		if c := desc.Mutations[i].GetConstraint(); c != nil &&
+			c.ConstraintType == descpb.ConstraintToUpdate_EXCLUSION {
+			ecs = append(ecs, &c.ExclusionConstraint)
+		}
+	}
+	return ecs
+}
+
 // AllActiveAndInactiveForeignKeys returns all foreign keys, including both
 // "active" ones on the index descriptor which are being enforced for all
 // writes, and "inactive" ones queued in the mutations list. An error is
@@ -1892,6 +1917,10 @@ func (desc *wrapper) ValidateTable(ctx context.Context) error {
 			return err
 		}
 
+		if err := desc.validateExclusionConstraints(columnIDs); err != nil {
+			return err
+		}
+
 		if err := desc.validateTableIndexes(columnNames); err != nil {
 			return err
 		}
@@ -2173,6 +2202,52 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateExclusionConstraints validates that exclusion constraints are well
+// formed. Checks include validating the column IDs and the operators.
+func (desc *wrapper) validateExclusionConstraints(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	for _, c := range desc.AllActiveAndInactiveExclusionConstraints() {
+		if err := catalog.ValidateName(c.Name, "exclusion constraint"); err != nil {
+			return err
+		}
+
+		// Verify that the table ID is valid.
+		if c.TableID != desc.ID {
+			return fmt.Errorf(
+				"TableID mismatch for exclusion constraint %q: \"%d\" doesn't match descriptor: \"%d\"",
+				c.Name, c.TableID, desc.ID,
+			)
+		}
+
+		if len(c.ColumnIDs) == 0 {
+			return fmt.Errorf("exclusion constraint %q has no columns", c.Name)
+		}
+		if len(c.Operators) != len(c.ColumnIDs) {
+			return fmt.Errorf(
+				"mismatched column ID size (%d) and operator size (%d) in exclusion constraint %q",
+				len(c.ColumnIDs), len(c.Operators), c.Name,
+			)
+		}
+
+		// Verify that the constraint's column IDs and operators are valid.
+		for i, colID := range c.ColumnIDs {
+			if _, ok := columnIDs[colID]; !ok {
+				return fmt.Errorf(
+					"exclusion constraint %q contains unknown column \"%d\"", c.Name, colID,
+				)
+			}
+			if _, ok := descpb.ExclusionOperators[c.Operators[i]]; !ok {
+				return fmt.Errorf(
+					"exclusion constraint %q contains invalid operator %q", c.Name, c.Operators[i],
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
@@ -2240,6 +2315,10 @@ func (desc *wrapper) validateTableIndexes(columnNames map[string]descpb.ColumnID
 			return fmt.Errorf("mismatched column IDs (%d) and directions (%d)",
 				len(index.ColumnIDs), len(index.ColumnDirections))
 		}
+		if len(index.ColumnNullsReversed) != 0 && len(index.ColumnIDs) != len(index.ColumnNullsReversed) {
+			return fmt.Errorf("mismatched column IDs (%d) and NULLs orders (%d)",
+				len(index.ColumnIDs), len(index.ColumnNullsReversed))
+		}
 		// In the old STORING encoding, stored columns are in ExtraColumnIDs;
 		// tolerate a longer list of column names.
 		if len(index.StoreColumnIDs) > len(index.StoreColumnNames) {
@@ -2586,7 +2665,8 @@ func checkColumnsValidForInvertedIndex(tableDesc *Mutable, indexColNames []strin
 		for _, col := range tableDesc.AllNonDropColumns() {
 			if col.Name == indexCol {
 				lastCol := len(indexColNames) - 1
-				if i == lastCol && !colinfo.ColumnTypeIsInvertedIndexable(col.Type) ||
+				if i == lastCol && !colinfo.ColumnTypeIsInvertedIndexable(col.Type) &&
+					!colinfo.ColumnTypeIsTrigramIndexable(col.Type) ||
 					i < lastCol && !colinfo.ColumnTypeIsIndexable(col.Type) {
 					invalidColumns = append(invalidColumns, col)
 				}
@@ -3119,6 +3199,35 @@ func (desc *Mutable) DropConstraint(
 		}
 		return errors.AssertionFailedf("constraint %q not found on table %q", name, desc.Name)
 
+	case descpb.ConstraintTypeExclusion:
+		if detail.ExclusionConstraint.Validity == descpb.ConstraintValidity_Validating {
+			return unimplemented.NewWithIssueDetailf(42844,
+				"drop-constraint-exclusion-validating",
+				"constraint %q in the middle of being added, try again later", name)
+		}
+		if detail.ExclusionConstraint.Validity == descpb.ConstraintValidity_Dropping {
+			return unimplemented.NewWithIssueDetailf(42844,
+				"drop-constraint-exclusion-mutation",
+				"constraint %q in the middle of being dropped", name)
+		}
+		for i := range desc.ExclusionConstraints {
+			ec := &desc.ExclusionConstraints[i]
+			if ec.Name == name {
+				// If the constraint is unvalidated, there's no assumption that it must
+				// hold for all rows, so it can be dropped immediately.
+				if detail.ExclusionConstraint.Validity == descpb.ConstraintValidity_Unvalidated {
+					desc.ExclusionConstraints = append(
+						desc.ExclusionConstraints[:i], desc.ExclusionConstraints[i+1:]...,
+					)
+					return nil
+				}
+				ec.Validity = descpb.ConstraintValidity_Dropping
+				desc.AddExclusionMutation(ec, descpb.DescriptorMutation_DROP)
+				return nil
+			}
+		}
+		return errors.AssertionFailedf("constraint %q not found on table %q", name, desc.Name)
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("drop-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(name))
@@ -3198,6 +3307,16 @@ func (desc *Mutable) RenameConstraint(
 		detail.CheckConstraint.Name = newName
 		return nil
 
+	case descpb.ConstraintTypeExclusion:
+		if detail.ExclusionConstraint.Validity == descpb.ConstraintValidity_Validating {
+			return unimplemented.NewWithIssueDetailf(42844,
+				"rename-constraint-exclusion-mutation",
+				"constraint %q in the middle of being added, try again later",
+				tree.ErrNameStringP(&detail.ExclusionConstraint.Name))
+		}
+		detail.ExclusionConstraint.Name = newName
+		return nil
+
 	default:
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%s", detail.Kind),
 			"constraint %q has unsupported type", tree.ErrNameString(oldName))
@@ -3345,6 +3464,25 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 					return err
 				}
 				col.Nullable = false
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				switch t.Constraint.ExclusionConstraint.Validity {
+				case descpb.ConstraintValidity_Validating:
+					// Constraint already added, just mark it as Validated
+					for i := range desc.ExclusionConstraints {
+						ec := &desc.ExclusionConstraints[i]
+						if ec.Name == t.Constraint.Name {
+							ec.Validity = descpb.ConstraintValidity_Validated
+							break
+						}
+					}
+				case descpb.ConstraintValidity_Unvalidated:
+					// add the constraint to the list of exclusion constraints on the
+					// table descriptor
+					desc.ExclusionConstraints = append(desc.ExclusionConstraints, t.Constraint.ExclusionConstraint)
+				default:
+					return errors.AssertionFailedf("invalid constraint validity state: %d",
+						t.Constraint.ExclusionConstraint.Validity)
+				}
 			default:
 				return errors.Errorf("unsupported constraint type: %d", t.Constraint.ConstraintType)
 			}
@@ -3448,6 +3586,13 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			// Remember the timestamp that the view query was run at, so that
+			// subsequent incremental refreshes know which changes to apply.
+			desc.RefreshAsOfTime = nil
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				asOf := t.MaterializedViewRefresh.AsOf
+				desc.RefreshAsOfTime = &asOf
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
@@ -3591,6 +3736,24 @@ func (desc *Mutable) AddForeignKeyMutation(
 	desc.addMutation(m)
 }
 
+// AddExclusionMutation adds an exclusion constraint mutation to
+// desc.Mutations.
+func (desc *Mutable) AddExclusionMutation(
+	ec *descpb.ExclusionConstraint, direction descpb.DescriptorMutation_Direction,
+) {
+	m := descpb.DescriptorMutation{
+		Descriptor_: &descpb.DescriptorMutation_Constraint{
+			Constraint: &descpb.ConstraintToUpdate{
+				ConstraintType:      descpb.ConstraintToUpdate_EXCLUSION,
+				Name:                ec.Name,
+				ExclusionConstraint: *ec,
+			},
+		},
+		Direction: direction,
+	}
+	desc.addMutation(m)
+}
+
 // MakeNotNullCheckConstraint creates a dummy check constraint equivalent to a
 // NOT NULL constraint on a column, so that NOT NULL constraints can be added
 // and dropped correctly in the schema changer. This function mutates inuseNames
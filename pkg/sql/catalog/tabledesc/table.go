@@ -269,6 +269,24 @@ func (desc *wrapper) collectConstraintInfo(
 		info[uc.Name] = detail
 	}
 
+	ecs := desc.AllActiveAndInactiveExclusionConstraints()
+	for _, ec := range ecs {
+		if _, ok := info[ec.Name]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate constraint name: %q", ec.Name)
+		}
+		detail := descpb.ConstraintDetail{Kind: descpb.ConstraintTypeExclusion}
+		// Constraints in the Validating state are considered Unvalidated for this purpose
+		detail.Unvalidated = ec.Validity != descpb.ConstraintValidity_Validated
+		var err error
+		detail.Columns, err = desc.NamesForColumnIDs(ec.ColumnIDs)
+		if err != nil {
+			return nil, err
+		}
+		detail.ExclusionConstraint = ec
+		info[ec.Name] = detail
+	}
+
 	fks := desc.AllActiveAndInactiveForeignKeys()
 	for _, fk := range fks {
 		if _, ok := info[fk.Name]; ok {
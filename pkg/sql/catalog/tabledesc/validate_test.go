@@ -94,6 +94,15 @@ var validationMap = []struct {
 			"DependedOnBy": {
 				status: todoIAmKnowinglyAddingTechDebt,
 				reason: "initial import: TODO(features): add validation"},
+			"DependsOnFunctions": {
+				status: todoIAmKnowinglyAddingTechDebt,
+				reason: "initial import: TODO(features): add validation"},
+			"DependedOnByFunctions": {
+				status: todoIAmKnowinglyAddingTechDebt,
+				reason: "the back-references are checked when validating the functions"},
+			"Triggers": {
+				status: todoIAmKnowinglyAddingTechDebt,
+				reason: "trigger statements refer to objects by name; dependencies are not tracked"},
 			"MutationJobs": {status: thisFieldReferencesNoObjects},
 			"SequenceOpts": {status: todoIAmKnowinglyAddingTechDebt,
 				reason: "initial import: TODO(features): add validation"},
@@ -109,20 +118,23 @@ var validationMap = []struct {
 			"OutboundFKs":                   {status: iSolemnlySwearThisFieldIsValidated},
 			"InboundFKs":                    {status: iSolemnlySwearThisFieldIsValidated},
 			"UniqueWithoutIndexConstraints": {status: iSolemnlySwearThisFieldIsValidated},
+			"ExclusionConstraints":          {status: iSolemnlySwearThisFieldIsValidated},
 			"Temporary":                     {status: thisFieldReferencesNoObjects},
 			"LocalityConfig":                {status: iSolemnlySwearThisFieldIsValidated},
 			"PartitionAllBy":                {status: iSolemnlySwearThisFieldIsValidated},
+			"RefreshAsOfTime":               {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
 		obj: descpb.IndexDescriptor{},
 		fieldMap: map[string]validationStatusInfo{
-			"Name":             {status: thisFieldReferencesNoObjects},
-			"ID":               {status: thisFieldReferencesNoObjects},
-			"Unique":           {status: thisFieldReferencesNoObjects},
-			"Version":          {status: thisFieldReferencesNoObjects},
-			"ColumnNames":      {status: iSolemnlySwearThisFieldIsValidated},
-			"ColumnDirections": {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":                {status: thisFieldReferencesNoObjects},
+			"ID":                  {status: thisFieldReferencesNoObjects},
+			"Unique":              {status: thisFieldReferencesNoObjects},
+			"Version":             {status: thisFieldReferencesNoObjects},
+			"ColumnNames":         {status: iSolemnlySwearThisFieldIsValidated},
+			"ColumnDirections":    {status: iSolemnlySwearThisFieldIsValidated},
+			"ColumnNullsReversed": {status: iSolemnlySwearThisFieldIsValidated},
 			"StoreColumnNames": {
 				status: todoIAmKnowinglyAddingTechDebt,
 				reason: "initial import: TODO(features): add validation"},
@@ -194,6 +206,8 @@ var validationMap = []struct {
 			"OnDelete":          {status: thisFieldReferencesNoObjects},
 			"OnUpdate":          {status: thisFieldReferencesNoObjects},
 			"Match":             {status: thisFieldReferencesNoObjects},
+			"Deferrable":        {status: thisFieldReferencesNoObjects},
+			"InitiallyDeferred": {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -215,6 +229,7 @@ var validationMap = []struct {
 			"Privileges":               {status: iSolemnlySwearThisFieldIsValidated},
 			"OfflineReason":            {status: thisFieldReferencesNoObjects},
 			"RegionConfig":             {status: iSolemnlySwearThisFieldIsValidated},
+			"Composite":                {status: thisFieldReferencesNoObjects},
 		},
 	},
 }
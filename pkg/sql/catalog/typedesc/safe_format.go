@@ -40,6 +40,9 @@ func formatSafeTypeProperties(w *redact.StringBuilder, desc catalog.TypeDescript
 	if len(td.EnumMembers) > 0 {
 		w.Printf(", NumEnumMembers: %d", len(td.EnumMembers))
 	}
+	if td.Composite != nil {
+		w.Printf(", NumCompositeElements: %d", len(td.Composite.Elements))
+	}
 	if td.Alias != nil {
 		w.Printf(", Alias: %d", td.Alias.Oid())
 	}
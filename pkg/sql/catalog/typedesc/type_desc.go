@@ -89,6 +89,12 @@ type Immutable struct {
 	physicalReps    [][]byte
 	readOnlyMembers []bool
 
+	// The fields below are used to fill user defined type metadata for
+	// composite types.
+	compositeContents []*types.T
+	compositeLabels   []string
+	compositeIDs      []uint32
+
 	// isUncommittedVersion is set to true if this descriptor was created from
 	// a copy of a Mutable with an uncommitted version.
 	isUncommittedVersion bool
@@ -135,6 +141,19 @@ func makeImmutable(desc descpb.TypeDescriptor) Immutable {
 			immutDesc.readOnlyMembers[i] =
 				member.Capability == descpb.TypeDescriptor_EnumMember_READ_ONLY
 		}
+	case descpb.TypeDescriptor_COMPOSITE:
+		if desc.Composite == nil {
+			break
+		}
+		elems := desc.Composite.Elements
+		immutDesc.compositeContents = make([]*types.T, len(elems))
+		immutDesc.compositeLabels = make([]string, len(elems))
+		immutDesc.compositeIDs = make([]uint32, len(elems))
+		for i := range elems {
+			immutDesc.compositeContents[i] = elems[i].Type
+			immutDesc.compositeLabels[i] = elems[i].Label
+			immutDesc.compositeIDs[i] = elems[i].ID
+		}
 	}
 
 	return immutDesc
@@ -355,6 +374,41 @@ func (desc *Mutable) AddEnumValue(node *tree.AlterTypeAddValue) error {
 	return nil
 }
 
+// AddCompositeElement adds an attribute with the given name and type to the
+// end of a composite type. AddCompositeElement assumes that the type is a
+// composite type.
+func (desc *Mutable) AddCompositeElement(name string, typ *types.T) error {
+	for i := range desc.Composite.Elements {
+		if desc.Composite.Elements[i].Label == name {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"column %q of relation %q already exists", name, desc.Name)
+		}
+	}
+	// Attributes get a new ID so that values written before an attribute with
+	// the same name was dropped are never decoded as the new attribute.
+	desc.Composite.Elements = append(desc.Composite.Elements, descpb.TypeDescriptor_Composite_Element{
+		Type:  typ,
+		Label: name,
+		ID:    desc.Composite.NextElementID,
+	})
+	desc.Composite.NextElementID++
+	return nil
+}
+
+// DropCompositeElement removes the attribute with the given name from a
+// composite type. It returns false if the type has no such attribute.
+// DropCompositeElement assumes that the type is a composite type.
+func (desc *Mutable) DropCompositeElement(name string) bool {
+	for i := range desc.Composite.Elements {
+		if desc.Composite.Elements[i].Label == name {
+			desc.Composite.Elements = append(
+				desc.Composite.Elements[:i], desc.Composite.Elements[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // AddReferencingDescriptorID adds a new referencing descriptor ID to the
 // TypeDescriptor. It ensures that duplicates are not added.
 func (desc *Mutable) AddReferencingDescriptorID(new descpb.ID) {
@@ -448,6 +502,35 @@ func (desc *Immutable) Validate(ctx context.Context, dg catalog.DescGetter) erro
 			members[desc.EnumMembers[i].LogicalRepresentation] = struct{}{}
 		}
 
+		// Validate the Privileges of the descriptor.
+		if err := desc.Privileges.Validate(desc.ID, privilege.Type); err != nil {
+			return err
+		}
+	case descpb.TypeDescriptor_COMPOSITE:
+		if desc.Composite == nil {
+			return errors.AssertionFailedf("COMPOSITE type desc has nil composite")
+		}
+		labels := make(map[string]struct{}, len(desc.Composite.Elements))
+		ids := make(map[uint32]struct{}, len(desc.Composite.Elements))
+		for i := range desc.Composite.Elements {
+			elem := &desc.Composite.Elements[i]
+			if elem.Type == nil {
+				return errors.AssertionFailedf("composite element %q has nil type", elem.Label)
+			}
+			if _, ok := labels[elem.Label]; ok {
+				return errors.AssertionFailedf("duplicate composite element %q", elem.Label)
+			}
+			labels[elem.Label] = struct{}{}
+			if elem.ID == 0 || elem.ID >= desc.Composite.NextElementID {
+				return errors.AssertionFailedf(
+					"composite element %q has invalid ID %d", elem.Label, elem.ID)
+			}
+			if _, ok := ids[elem.ID]; ok {
+				return errors.AssertionFailedf("duplicate composite element ID %d", elem.ID)
+			}
+			ids[elem.ID] = struct{}{}
+		}
+
 		// Validate the Privileges of the descriptor.
 		if err := desc.Privileges.Validate(desc.ID, privilege.Type); err != nil {
 			return err
@@ -460,6 +543,10 @@ func (desc *Immutable) Validate(ctx context.Context, dg catalog.DescGetter) erro
 		return errors.AssertionFailedf("invalid desc kind %s", desc.Kind.String())
 	}
 
+	if desc.Kind != descpb.TypeDescriptor_COMPOSITE && desc.Composite != nil {
+		return errors.AssertionFailedf("found composite on %s type desc", desc.Kind.String())
+	}
+
 	switch desc.Kind {
 	case descpb.TypeDescriptor_MULTIREGION_ENUM:
 		if desc.RegionConfig == nil {
@@ -568,7 +655,7 @@ func (desc *Immutable) Validate(ctx context.Context, dg catalog.DescGetter) erro
 	}
 
 	switch desc.Kind {
-	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
+	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM, descpb.TypeDescriptor_COMPOSITE:
 		// Ensure that the referenced array type exists.
 		reqs = append(reqs, desc.ArrayTypeID)
 		checks = append(checks, func(got catalog.Descriptor) error {
@@ -585,20 +672,22 @@ func (desc *Immutable) Validate(ctx context.Context, dg catalog.DescGetter) erro
 		return errors.New("unknown type descriptor type")
 	}
 
-	// Validate that all of the referencing descriptors exist.
-	tableExists := func(id descpb.ID) func(got catalog.Descriptor) error {
+	// Validate that all of the referencing descriptors exist. They are tables,
+	// or user-defined functions whose bodies refer to the type.
+	referenceExists := func(id descpb.ID) func(got catalog.Descriptor) error {
 		return func(got catalog.Descriptor) error {
-			if _, isTable := got.(catalog.TableDescriptor); !isTable {
-				return errors.AssertionFailedf("referencing descriptor %d does not exist", id)
+			switch got.(type) {
+			case catalog.TableDescriptor, catalog.FunctionDescriptor:
+				return nil
 			}
-			return nil
+			return errors.AssertionFailedf("referencing descriptor %d does not exist", id)
 		}
 	}
 	if !desc.Dropped() {
 
 		for _, id := range desc.ReferencingDescriptorIDs {
 			reqs = append(reqs, id)
-			checks = append(checks, tableExists(id))
+			checks = append(checks, referenceExists(id))
 		}
 	}
 
@@ -638,6 +727,13 @@ func (desc *Immutable) MakeTypesT(
 			return nil, err
 		}
 		return typ, nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		// The attributes of the type are filled in during hydration.
+		typ := types.MakeComposite(TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), nil, nil)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
 	case descpb.TypeDescriptor_ALIAS:
 		// Hydrate the alias and return it.
 		if err := desc.HydrateTypeInfoWithName(ctx, desc.Alias, name, res); err != nil {
@@ -715,6 +811,12 @@ func (desc *Immutable) HydrateTypeInfoWithName(
 			IsMemberReadOnly:        desc.readOnlyMembers,
 		}
 		return nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		if typ.Family() != types.TupleFamily {
+			return errors.New("cannot hydrate a non-tuple type with a composite type descriptor")
+		}
+		types.HydrateComposite(typ, desc.compositeContents, desc.compositeLabels, desc.compositeIDs)
+		return nil
 	case descpb.TypeDescriptor_ALIAS:
 		if typ.UserDefined() {
 			switch typ.Family() {
@@ -773,6 +875,30 @@ func (desc *Immutable) IsCompatibleWith(other *Immutable) error {
 			}
 		}
 		return nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		if other.Kind != desc.Kind {
+			return errors.Newf("%q of type %q is not compatible with type %q",
+				other.Name, other.Kind, desc.Kind)
+		}
+		// Every attribute in desc must be present in other with the same ID and
+		// type, otherwise values of desc cannot be decoded as values of other.
+		for _, thisElem := range desc.Composite.Elements {
+			found := false
+			for _, otherElem := range other.Composite.Elements {
+				if thisElem.Label == otherElem.Label {
+					if thisElem.ID != otherElem.ID || !thisElem.Type.Identical(otherElem.Type) {
+						return errors.Newf(
+							"%q has differing representation for attribute %q", other.Name, thisElem.Label)
+					}
+					found = true
+				}
+			}
+			if !found {
+				return errors.Newf(
+					"could not find attribute %q in %q", thisElem.Label, other.Name)
+			}
+		}
+		return nil
 	default:
 		return errors.Newf("compatibility comparison unsupported for type kind %s", desc.Kind.String())
 	}
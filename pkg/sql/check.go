@@ -305,6 +305,108 @@ func validateForeignKey(
 	return nil
 }
 
+// conflictingRowsQuery generates and returns a query for pairs of rows that
+// violate the specified exclusion constraint, i.e., distinct rows whose values
+// in the constrained columns all compare true under the corresponding
+// operators. Rows with null values in any constrained column never conflict.
+//
+// For example, an exclusion constraint EXCLUDE (a WITH =, b WITH &&) on a
+// table "tbl" with primary key (pk1, pk2) would require the following query:
+//
+// SELECT
+//   s.a, s.b, t.a, t.b
+// FROM
+//   [tbl AS s] JOIN [tbl AS t]
+//   ON s.a = t.a AND s.b && t.b AND (s.pk1 != t.pk1 OR s.pk2 != t.pk2)
+// LIMIT 1
+func conflictingRowsQuery(
+	tbl catalog.TableDescriptor, ec *descpb.ExclusionConstraint,
+) (sql string, colNames []string, _ error) {
+	colNames, err := tbl.NamesForColumnIDs(ec.ColumnIDs)
+	if err != nil {
+		return "", nil, err
+	}
+	nCols := len(colNames)
+	srcCols := make([]string, nCols)
+	targetCols := make([]string, nCols)
+	on := make([]string, nCols)
+	for i, n := range colNames {
+		// s and t are table aliases used in the query.
+		srcCols[i] = fmt.Sprintf("s.%s", tree.NameString(n))
+		targetCols[i] = fmt.Sprintf("t.%s", tree.NameString(n))
+		on[i] = fmt.Sprintf("%s %s %s", srcCols[i], ec.Operator(i), targetCols[i])
+	}
+
+	pkCols := make([]string, tbl.GetPrimaryIndex().NumColumns())
+	for i := range pkCols {
+		col, err := tbl.FindActiveColumnByID(tbl.GetPrimaryIndex().GetColumnID(i))
+		if err != nil {
+			return "", nil, err
+		}
+		name := tree.NameString(col.Name)
+		pkCols[i] = fmt.Sprintf("s.%s != t.%s", name, name)
+	}
+
+	return fmt.Sprintf(
+		`SELECT %[1]s, %[2]s FROM [%[3]d AS s] JOIN [%[3]d AS t] ON %[4]s AND (%[5]s) LIMIT 1`,
+		strings.Join(srcCols, ", "),    // 1
+		strings.Join(targetCols, ", "), // 2
+		tbl.GetID(),                    // 3
+		strings.Join(on, " AND "),      // 4
+		strings.Join(pkCols, " OR "),   // 5
+	), colNames, nil
+}
+
+// validateExclusionConstraint verifies that no two rows in the table conflict
+// under the given exclusion constraint.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing client.Txn safely.
+func validateExclusionConstraint(
+	ctx context.Context,
+	tbl *tabledesc.Mutable,
+	ec *descpb.ExclusionConstraint,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+) error {
+	query, colNames, err := conflictingRowsQuery(tbl, ec)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		ec.Name, tbl.Name, colNames, query,
+	)
+
+	values, err := ie.QueryRow(ctx, "validate exclusion constraint", txn, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		cols := strings.Join(colNames, ", ")
+		return errors.WithDetailf(
+			pgerror.WithConstraintName(pgerror.Newf(pgcode.ExclusionViolation,
+				"could not create exclusion constraint %q", ec.Name,
+			), ec.Name),
+			"Key (%s)=(%s) conflicts with key (%s)=(%s).",
+			cols, formatDatums(values[:len(colNames)]), cols, formatDatums(values[len(colNames):]),
+		)
+	}
+	return nil
+}
+
+// formatDatums formats the given datums as a comma-separated list.
+func formatDatums(values tree.Datums) string {
+	var buf bytes.Buffer
+	for i := range values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(values[i].String())
+	}
+	return buf.String()
+}
+
 func formatValues(colNames []string, values tree.Datums) string {
 	var pairs bytes.Buffer
 	for i := range values {
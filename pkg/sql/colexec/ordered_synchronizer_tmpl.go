@@ -275,8 +275,13 @@ func (o *OrderedSynchronizer) compareRow(batchIdx1 int, batchIdx2 int) int {
 	}
 	for i := range o.ordering {
 		info := o.ordering[i]
-		res := o.comparators[i].compare(batchIdx1, batchIdx2, valIdx1, valIdx2)
+		cmp := o.comparators[i]
+		res := cmp.compare(batchIdx1, batchIdx2, valIdx1, valIdx2)
 		if res != 0 {
+			if info.NullsReversed && cmp.isNull(batchIdx1, valIdx1) != cmp.isNull(batchIdx2, valIdx2) {
+				// Exactly one of the values is NULL, and NULLs sort at the other end.
+				res = -res
+			}
 			switch d := info.Direction; d {
 			case encoding.Ascending:
 				return res
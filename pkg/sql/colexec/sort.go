@@ -324,8 +324,11 @@ func (p *sortOp) sort(ctx context.Context) {
 	}
 
 	for i := range p.orderingCols {
-		inputVec := p.input.getValues(int(p.orderingCols[i].ColIdx))
-		p.sorters[i] = newSingleSorter(p.inputTypes[p.orderingCols[i].ColIdx], p.orderingCols[i].Direction, inputVec.MaybeHasNulls())
+		ord := &p.orderingCols[i]
+		inputVec := p.input.getValues(int(ord.ColIdx))
+		p.sorters[i] = newSingleSorter(
+			p.inputTypes[ord.ColIdx], ord.Direction, ord.NullsReversed, inputVec.MaybeHasNulls(),
+		)
 		p.sorters[i].init(inputVec, p.order)
 	}
 
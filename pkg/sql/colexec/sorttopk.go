@@ -243,8 +243,13 @@ func (t *topKSorter) emit() coldata.Batch {
 func (t *topKSorter) compareRow(vecIdx1, vecIdx2 int, rowIdx1, rowIdx2 int) int {
 	for i := range t.orderingCols {
 		info := t.orderingCols[i]
-		res := t.comparators[info.ColIdx].compare(vecIdx1, vecIdx2, rowIdx1, rowIdx2)
+		cmp := t.comparators[info.ColIdx]
+		res := cmp.compare(vecIdx1, vecIdx2, rowIdx1, rowIdx2)
 		if res != 0 {
+			if info.NullsReversed && cmp.isNull(vecIdx1, rowIdx1) != cmp.isNull(vecIdx2, rowIdx2) {
+				// Exactly one of the values is NULL, and NULLs sort at the other end.
+				res = -res
+			}
 			switch d := info.Direction; d {
 			case execinfrapb.Ordering_Column_ASC:
 				return res
@@ -67,6 +67,10 @@ type vecComparator interface {
 	// 0, or 1.
 	compare(vecIdx1, vecIdx2 int, valIdx1, valIdx2 int) int
 
+	// isNull returns whether the value at index valIdx of the vector at vecIdx
+	// is NULL.
+	isNull(vecIdx int, valIdx int) bool
+
 	// set sets the value of the vector at dstVecIdx at index dstValIdx to the value
 	// at the vector at srcVecIdx at index srcValIdx.
 	// NOTE: whenever set is used, the caller is responsible for updating the
@@ -101,6 +105,10 @@ func (c *_TYPEVecComparator) compare(vecIdx1, vecIdx2 int, valIdx1, valIdx2 int)
 	return cmp
 }
 
+func (c *_TYPEVecComparator) isNull(vecIdx int, valIdx int) bool {
+	return c.nulls[vecIdx].MaybeHasNulls() && c.nulls[vecIdx].NullAt(valIdx)
+}
+
 func (c *_TYPEVecComparator) setVec(idx int, vec coldata.Vec) {
 	c.vecs[idx] = vec._TYPE()
 	c.nulls[idx] = vec.Nulls()
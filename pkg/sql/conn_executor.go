@@ -810,6 +810,9 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		txnEv = txnRollback
 	}
 
+	// The cursors need to stop using the txn before it is rolled back.
+	ex.extraTxnState.sqlCursors.closeAll()
+
 	if closeType == normalClose {
 		// We'll cleanup the SQL txn by creating a non-retriable (commit:true) event.
 		// This event is guaranteed to be accepted in every state.
@@ -1003,6 +1006,19 @@ type connExecutor struct {
 		// connExecutor's closure.
 		prepStmtsNamespaceMemAcc mon.BoundAccount
 
+		// sqlCursors contains the list of SQL CURSORs the session currently has
+		// access to. Cursors are bound to the transaction that declared them.
+		sqlCursors cursorMap
+
+		// deferredConstraints contains the modes set by SET CONSTRAINTS and the
+		// checks of deferred constraints which need to run before the
+		// transaction commits.
+		deferredConstraints deferredConstraintSet
+
+		// localSessionVars contains the changes made by SET LOCAL, which are
+		// undone when the transaction ends.
+		localSessionVars localSessionVarStack
+
 		// onTxnFinish (if non-nil) will be called when txn is finished (either
 		// committed or aborted). It is set when txn is started but can remain
 		// unset when txn is executed within another higher-level txn.
@@ -1232,9 +1248,16 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
 
+	// Close all cursors.
+	ex.extraTxnState.sqlCursors.closeAll()
+
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
+		ex.extraTxnState.deferredConstraints.reset()
+		if err := ex.extraTxnState.localSessionVars.restore(ctx, ex.dataMutator, 0); err != nil {
+			return err
+		}
 		// After txn is finished, we need to call onTxnFinish (if it's non-nil).
 		if ex.extraTxnState.onTxnFinish != nil {
 			ex.extraTxnState.onTxnFinish(ev)
@@ -2190,6 +2213,9 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sessionDataMutator = ex.dataMutator
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.sqlCursors = &ex.extraTxnState.sqlCursors
+	p.deferredConstraints = &ex.extraTxnState.deferredConstraints
+	p.localSessionVars = &ex.extraTxnState.localSessionVars
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -380,6 +380,28 @@ func (ex *connExecutor) execStmtInOpenState(
 		stmt.ExpectedTypes = nil
 	}
 
+	if c, ok := ast.(*tree.CopyTo); ok {
+		copyRes, ok := res.(CopyOutResult)
+		if !ok {
+			return makeErrEvent(pgerror.New(pgcode.FeatureNotSupported,
+				"COPY TO STDOUT is not supported in this context"))
+		}
+		opts, err := p.makeCopyOutOptions(ctx, c.Options)
+		if err != nil {
+			return makeErrEvent(err)
+		}
+		// Strip off the copy node to execute the query; its results are streamed
+		// to the client by the result.
+		stmt.AST = c.Query()
+		ast = stmt.AST
+		stmt.ExpectedTypes = nil
+		if ast.StatementType() != tree.Rows {
+			return makeErrEvent(pgerror.New(pgcode.FeatureNotSupported,
+				"COPY query must have a RETURNING clause"))
+		}
+		copyRes.SetCopyOut(opts)
+	}
+
 	var needFinish bool
 	ctx, needFinish = ih.Setup(
 		ctx, ex.server.cfg, ex.appStats, p, ex.stmtDiagnosticsRecorder,
@@ -738,6 +760,22 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	// The queries of the cursors still use the txn, so they are stopped before
+	// it commits.
+	ex.extraTxnState.sqlCursors.closeAll()
+
+	if pending := ex.extraTxnState.deferredConstraints.takePending(); len(pending) > 0 {
+		ie := ex.planner.ExtendedEvalContext().InternalExecutor.(*InternalExecutor)
+		ie.tcModifier = &ex.extraTxnState.descCollection
+		err := checkDeferredConstraints(
+			ctx, ie, ex.state.mu.txn, &ex.extraTxnState.descCollection, pending,
+		)
+		ie.tcModifier = nil
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
 		return err
 	}
@@ -770,6 +808,7 @@ func validatePrimaryKeys(tc *descs.Collection) error {
 // rollbackSQLTransaction executes a ROLLBACK statement: the KV transaction is
 // rolled-back and an event is produced.
 func (ex *connExecutor) rollbackSQLTransaction(ctx context.Context) (fsm.Event, fsm.EventPayload) {
+	ex.extraTxnState.sqlCursors.closeAll()
 	if err := ex.state.mu.txn.Rollback(ctx); err != nil {
 		log.Warningf(ctx, "txn rollback failed: %s", err)
 	}
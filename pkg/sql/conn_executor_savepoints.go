@@ -105,6 +105,8 @@ func (ex *connExecutor) execSavepointInOpenState(
 		commitOnRelease: commitOnRelease,
 		kvToken:         token,
 		numDDL:          ex.extraTxnState.numDDL,
+		numLocalVars:    ex.extraTxnState.localSessionVars.len(),
+		numCursors:      ex.extraTxnState.sqlCursors.numDeclared,
 	}
 	savepoints.push(sp)
 
@@ -183,11 +185,22 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 		return ev, payload
 	}
 
+	// The cursors declared after the savepoint need to stop using the txn
+	// before it is rolled back.
+	ex.extraTxnState.sqlCursors.closeDeclaredAfter(entry.numCursors)
+
 	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, entry.kvToken); err != nil {
 		ev, payload := ex.makeErrEvent(err, s)
 		return ev, payload
 	}
 
+	if err := ex.extraTxnState.localSessionVars.restore(
+		ctx, ex.dataMutator, entry.numLocalVars,
+	); err != nil {
+		ev, payload := ex.makeErrEvent(err, s)
+		return ev, payload
+	}
+
 	ex.extraTxnState.savepoints.popToIdx(idx)
 
 	if entry.kvToken.Initial() {
@@ -264,10 +277,18 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 
 	ex.extraTxnState.savepoints.popToIdx(idx)
 
+	ex.extraTxnState.sqlCursors.closeDeclaredAfter(entry.numCursors)
+
 	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, entry.kvToken); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
 
+	if err := ex.extraTxnState.localSessionVars.restore(
+		ctx, ex.dataMutator, entry.numLocalVars,
+	); err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
 	}
@@ -312,6 +333,16 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// The number of changes made by SET LOCAL in the transaction at the time
+	// the savepoint was created. The changes made afterwards are undone when
+	// rolling back to the savepoint.
+	numLocalVars int
+
+	// The number of cursors declared in the session at the time the savepoint
+	// was created. The cursors declared afterwards are closed when rolling back
+	// to the savepoint.
+	numCursors int
 }
 
 type savepointStack []savepoint
@@ -799,6 +799,36 @@ type CopyInResult interface {
 	ResultBase
 }
 
+// CopyOutResult is implemented by results which can stream the rows of a
+// COPY ... TO STDOUT statement to the client using the CopyOut sub-protocol.
+type CopyOutResult interface {
+	RestrictedCommandResult
+
+	// SetCopyOut switches the result to CopyOut mode. It needs to be called
+	// before SetColumns. The subsequent SetColumns call starts the copy, every
+	// AddRow produces a CopyData message, and closing the result terminates
+	// the copy.
+	SetCopyOut(opts CopyFormatOptions)
+}
+
+// CopyFormatOptions describes the format in which the rows of a COPY statement
+// are exchanged with the client.
+type CopyFormatOptions struct {
+	Format tree.CopyFormat
+	// Delimiter separates the columns in the text and CSV formats.
+	Delimiter byte
+	// Null is the representation of NULL values in the text and CSV formats.
+	Null string
+	// Quote encloses quoted values in the CSV format.
+	Quote byte
+	// Escape precedes quote and escape characters inside quoted values in the
+	// CSV format. It is equal to Quote unless specified otherwise.
+	Escape byte
+	// Header, if set, makes the CSV format start with a line holding the column
+	// names.
+	Header bool
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
@@ -21,8 +21,12 @@ import (
 	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
@@ -30,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/errors"
@@ -56,8 +61,19 @@ type copyMachine struct {
 	table         tree.TableExpr
 	columns       tree.NameList
 	resultColumns colinfo.ResultColumns
-	format        tree.CopyFormat
-	binaryState   binaryState
+	// opts describes the format of the copied data.
+	opts        CopyFormatOptions
+	binaryState binaryState
+	// csvReader parses the records of the CSV format, which are fed to it
+	// through csvInput one at a time.
+	csvReader *csv.Reader
+	csvInput  bytes.Buffer
+	// headerSkipped is set once the header line of the CSV format has been
+	// skipped.
+	headerSkipped bool
+	// where, if set, is the filter of the COPY statement. Only the rows
+	// satisfying it are inserted.
+	where tree.Expr
 	// forceNotNull disables converting values matching the null string to
 	// NULL. The spec says this is only supported for CSV, and also must specify
 	// which columns it applies to.
@@ -112,7 +128,6 @@ func newCopyMachine(
 		//  but that dependency can be removed by refactoring it.
 		table:   &n.Table,
 		columns: n.Columns,
-		format:  n.Options.CopyFormat,
 		txnOpt:  txnOpt,
 		// The planner will be prepared before use.
 		p:              planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
@@ -127,6 +142,20 @@ func newCopyMachine(
 	}()
 	c.parsingEvalCtx = c.p.EvalContext()
 
+	opts, err := c.p.makeCopyFormatOptions(ctx, n.Options)
+	if err != nil {
+		return nil, err
+	}
+	c.opts = opts
+	if opts.Format == tree.CopyFormatCSV {
+		c.csvReader = csv.NewReader(&c.csvInput)
+		c.csvReader.Comma = rune(opts.Delimiter)
+		c.csvReader.Quote = rune(opts.Quote)
+		c.csvReader.Escape = rune(opts.Escape)
+		c.csvReader.FieldsPerRecord = -1
+		c.csvReader.ReuseRecord = true
+	}
+
 	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc)
 	tableDesc, err := resolver.ResolveExistingTableObject(ctx, &c.p, &n.Table, flags)
 	if err != nil {
@@ -149,12 +178,174 @@ func newCopyMachine(
 			PGAttributeNum: cols[i].GetPGAttributeNum(),
 		}
 	}
+	if n.Where != nil {
+		if c.where, err = c.makeCopyWhere(ctx, tableDesc, n, cols); err != nil {
+			return nil, err
+		}
+	}
 	c.rowsMemAcc = c.p.extendedEvalCtx.Mon.MakeBoundAccount()
 	c.bufMemAcc = c.p.extendedEvalCtx.Mon.MakeBoundAccount()
 	c.processRows = c.insertRows
 	return c, nil
 }
 
+// makeCopyWhere validates the WHERE clause of a COPY statement and returns
+// the filter to apply to the copied rows. The filter is evaluated over the
+// copied values, so it can only reference the columns being copied.
+func (c *copyMachine) makeCopyWhere(
+	ctx context.Context,
+	tableDesc catalog.TableDescriptor,
+	n *tree.CopyFrom,
+	cols []descpb.ColumnDescriptor,
+) (tree.Expr, error) {
+	const op = "COPY FROM WHERE"
+	if _, err := tree.SimpleVisit(n.Where.Expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if _, ok := expr.(*tree.Subquery); ok {
+			return false, nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"subqueries are not allowed in %s", op)
+		}
+		return true, expr, nil
+	}); err != nil {
+		return nil, err
+	}
+	expr, colIDs, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx, tableDesc, n.Where.Expr, types.Bool, op, c.p.SemaCtx(), tree.VolatilityVolatile, &n.Table,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var copied catalog.TableColSet
+	for i := range cols {
+		copied.Add(cols[i].ID)
+	}
+	for _, id := range colIDs.Ordered() {
+		if !copied.Contains(id) {
+			col, err := tableDesc.FindColumnByID(id)
+			if err != nil {
+				return nil, err
+			}
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s cannot reference column %q, which is not copied", op, col.Name)
+		}
+	}
+	return parser.ParseExpr(expr)
+}
+
+// makeCopyFormatOptions evaluates the options describing the format of the
+// data of a COPY statement.
+func (p *planner) makeCopyFormatOptions(
+	ctx context.Context, opts tree.CopyOptions,
+) (CopyFormatOptions, error) {
+	res := CopyFormatOptions{Format: opts.CopyFormat, Header: opts.Header}
+	isCSV := opts.CopyFormat == tree.CopyFormatCSV
+	if opts.Header && !isCSV {
+		return res, pgerror.New(pgcode.FeatureNotSupported, "COPY HEADER available only in CSV mode")
+	}
+	if opts.Quote != nil && !isCSV {
+		return res, pgerror.New(pgcode.FeatureNotSupported, "COPY quote available only in CSV mode")
+	}
+	if opts.Escape != nil && !isCSV {
+		return res, pgerror.New(pgcode.FeatureNotSupported, "COPY escape available only in CSV mode")
+	}
+	if opts.CopyFormat == tree.CopyFormatBinary {
+		if opts.Delimiter != nil {
+			return res, pgerror.New(pgcode.Syntax, "cannot specify DELIMITER in BINARY mode")
+		}
+		if opts.Null != nil {
+			return res, pgerror.New(pgcode.Syntax, "cannot specify NULL in BINARY mode")
+		}
+		return res, nil
+	}
+
+	res.Delimiter, res.Null = '\t', nullString
+	if isCSV {
+		res.Delimiter, res.Null, res.Quote = ',', "", '"'
+	}
+	if opts.Delimiter != nil {
+		delim, err := p.evalCopyOption(ctx, opts.Delimiter)
+		if err != nil {
+			return res, err
+		}
+		if len(delim) != 1 {
+			return res, pgerror.New(pgcode.FeatureNotSupported,
+				"COPY delimiter must be a single one-byte character")
+		}
+		if delim[0] == '\n' || delim[0] == '\r' {
+			return res, pgerror.New(pgcode.InvalidParameterValue,
+				"COPY delimiter cannot be newline or carriage return")
+		}
+		// In the text format, these characters can follow a backslash, so they
+		// cannot be told apart from an escaped delimiter.
+		if !isCSV && strings.Contains(`\.abcdefghijklmnopqrstuvwxyz0123456789`, delim) {
+			return res, pgerror.Newf(pgcode.InvalidParameterValue,
+				"COPY delimiter cannot be \"%s\"", delim)
+		}
+		res.Delimiter = delim[0]
+	}
+	if opts.Null != nil {
+		null, err := p.evalCopyOption(ctx, opts.Null)
+		if err != nil {
+			return res, err
+		}
+		if strings.ContainsAny(null, "\r\n") {
+			return res, pgerror.New(pgcode.InvalidParameterValue,
+				"COPY null representation cannot use newline or carriage return")
+		}
+		res.Null = null
+	}
+	if opts.Quote != nil {
+		quote, err := p.evalCopyOption(ctx, opts.Quote)
+		if err != nil {
+			return res, err
+		}
+		if len(quote) != 1 {
+			return res, pgerror.New(pgcode.FeatureNotSupported,
+				"COPY quote must be a single one-byte character")
+		}
+		res.Quote = quote[0]
+	}
+	res.Escape = res.Quote
+	if opts.Escape != nil {
+		escape, err := p.evalCopyOption(ctx, opts.Escape)
+		if err != nil {
+			return res, err
+		}
+		if len(escape) != 1 {
+			return res, pgerror.New(pgcode.FeatureNotSupported,
+				"COPY escape must be a single one-byte character")
+		}
+		res.Escape = escape[0]
+	}
+	if strings.IndexByte(res.Null, res.Delimiter) != -1 {
+		return res, pgerror.New(pgcode.InvalidParameterValue,
+			"COPY delimiter must not appear in the NULL specification")
+	}
+	if isCSV {
+		if res.Delimiter == res.Quote {
+			return res, pgerror.New(pgcode.InvalidParameterValue,
+				"COPY delimiter and quote must be different")
+		}
+		if res.Quote == '\n' || res.Quote == '\r' || res.Escape == '\n' || res.Escape == '\r' {
+			return res, pgerror.New(pgcode.InvalidParameterValue,
+				"COPY quote and escape cannot be newline or carriage return")
+		}
+		if strings.IndexByte(res.Null, res.Quote) != -1 {
+			return res, pgerror.New(pgcode.InvalidParameterValue,
+				"CSV quote character must not appear in the NULL specification")
+		}
+	}
+	return res, nil
+}
+
+// evalCopyOption evaluates the string value of a COPY option.
+func (p *planner) evalCopyOption(ctx context.Context, expr tree.Expr) (string, error) {
+	fn, err := p.TypeAsString(ctx, expr, "COPY")
+	if err != nil {
+		return "", err
+	}
+	return fn()
+}
+
 // copyTxnOpt contains information about the transaction in which the copying
 // should take place. Can be empty, in which case the copyMachine is responsible
 // for managing its own transactions.
@@ -180,7 +371,11 @@ func (c *copyMachine) run(ctx context.Context) error {
 	defer c.bufMemAcc.Close(ctx)
 
 	// Send the message describing the columns to the client.
-	if err := c.conn.BeginCopyIn(ctx, c.resultColumns); err != nil {
+	format := pgwirebase.FormatText
+	if c.opts.Format == tree.CopyFormatBinary {
+		format = pgwirebase.FormatBinary
+	}
+	if err := c.conn.BeginCopyIn(ctx, c.resultColumns, format); err != nil {
 		return err
 	}
 
@@ -239,10 +434,6 @@ const (
 	lineDelim  = '\n'
 )
 
-var (
-	fieldDelim = []byte{'\t'}
-)
-
 // processCopyData buffers incoming data and, once the buffer fills up, inserts
 // the accumulated rows.
 //
@@ -269,11 +460,13 @@ func (c *copyMachine) processCopyData(ctx context.Context, data string, final bo
 	}
 	c.buf.WriteString(data)
 	var readFn func(ctx context.Context, final bool) (brk bool, err error)
-	switch c.format {
+	switch c.opts.Format {
 	case tree.CopyFormatText:
 		readFn = c.readTextData
 	case tree.CopyFormatBinary:
 		readFn = c.readBinaryData
+	case tree.CopyFormatCSV:
+		readFn = c.readCSVData
 	default:
 		panic("unknown copy format")
 	}
@@ -318,13 +511,75 @@ func (c *copyMachine) readTextData(ctx context.Context, final bool) (brk bool, e
 	return false, err
 }
 
+// readCSVData reads a single record of the CSV format. A record can span
+// multiple lines if it has quoted fields containing line breaks.
+func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, err error) {
+	n := csvRecordLen(c.buf.Bytes(), c.opts.Quote, c.opts.Escape)
+	if n < 0 {
+		if !final {
+			// Leave the incomplete record in the buffer, to be processed next time.
+			return true, nil
+		}
+		n = c.buf.Len()
+	}
+	record := c.buf.Next(n)
+	line := bytes.TrimSuffix(bytes.TrimSuffix(record, []byte{lineDelim}), []byte{'\r'})
+	if c.buf.Len() == 0 && bytes.Equal(line, []byte(`\.`)) {
+		return true, nil
+	}
+	if c.opts.Header && !c.headerSkipped {
+		c.headerSkipped = true
+		return false, nil
+	}
+	if len(line) == 0 {
+		// The CSV reader skips empty lines, but for COPY they are records with
+		// a single empty field.
+		return false, c.readCSVTuple(ctx, []string{""}, nil /* quoted */)
+	}
+	c.csvInput.Write(record)
+	fields, err := c.csvReader.Read()
+	if err != nil {
+		return false, pgerror.Wrap(err, pgcode.BadCopyFileFormat, "read CSV record")
+	}
+	return false, c.readCSVTuple(ctx, fields, c.csvReader.FieldQuoted)
+}
+
+// csvRecordLen returns the length of the first CSV record in b, including its
+// line terminator, or -1 if b does not hold a complete record. Line
+// terminators inside quoted fields are part of the record.
+func csvRecordLen(b []byte, quote, escape byte) int {
+	inQuotes := false
+	for i := 0; i < len(b); i++ {
+		switch {
+		case inQuotes && b[i] == escape && escape != quote:
+			// The escaped character, if any, can neither end the quoted field
+			// nor the record.
+			i++
+		case b[i] == quote:
+			inQuotes = !inQuotes
+		case b[i] == lineDelim && !inQuotes:
+			return i + 1
+		}
+	}
+	return -1
+}
+
 func (c *copyMachine) readBinaryData(ctx context.Context, final bool) (brk bool, err error) {
 	switch c.binaryState {
 	case binaryStateNeedSignature:
+		if c.buf.Len() < len(binarySignature) && !final {
+			// Wait for the rest of the signature.
+			return true, nil
+		}
 		if err := c.readBinarySignature(); err != nil {
 			return false, err
 		}
 	case binaryStateRead:
+		if !final && !binaryTupleComplete(c.buf.Bytes()) {
+			// Leave the incomplete tuple in the buffer, to be processed next
+			// time. Clients are free to split tuples across messages.
+			return true, nil
+		}
 		if err := c.readBinaryTuple(ctx); err != nil {
 			return false, errors.Wrapf(err, "read binary tuple")
 		}
@@ -357,6 +612,10 @@ func (c *copyMachine) readBinaryTuple(ctx context.Context) error {
 		return pgerror.Newf(pgcode.BadCopyFileFormat,
 			"unexpected field count: %d", fieldCount)
 	}
+	if int(fieldCount) != len(c.resultColumns) {
+		return pgerror.Newf(pgcode.BadCopyFileFormat,
+			"expected %d values, got %d", len(c.resultColumns), fieldCount)
+	}
 	exprs := make(tree.Exprs, fieldCount)
 	var byteCount int32
 	for i := range exprs {
@@ -394,12 +653,37 @@ func (c *copyMachine) readBinaryTuple(ctx context.Context) error {
 	return nil
 }
 
+// binaryTupleComplete returns whether b starts with a complete tuple of the
+// binary format, or with the trailer.
+func binaryTupleComplete(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	fieldCount := int16(binary.BigEndian.Uint16(b))
+	b = b[2:]
+	for i := int16(0); i < fieldCount; i++ {
+		if len(b) < 4 {
+			return false
+		}
+		byteCount := int32(binary.BigEndian.Uint32(b))
+		b = b[4:]
+		if byteCount > 0 {
+			if len(b) < int(byteCount) {
+				return false
+			}
+			b = b[byteCount:]
+		}
+	}
+	return true
+}
+
+// binarySignature is the standard 11-byte binary signature with the flags and
+// header 32-bit integers appended since we only support the zero value of
+// them.
+const binarySignature = "PGCOPY\n\377\r\n\000" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00"
+
 func (c *copyMachine) readBinarySignature() error {
-	// This is the standard 11-byte binary signature with the flags and
-	// header 32-bit integers appended since we only support the zero value
-	// of them.
-	const binarySignature = "PGCOPY\n\377\r\n\000" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00"
-	var sig [11 + 8]byte
+	var sig [len(binarySignature)]byte
 	if _, err := io.ReadFull(&c.buf, sig[:]); err != nil {
 		return err
 	}
@@ -474,7 +758,10 @@ func (c *copyMachine) insertRows(ctx context.Context) (retErr error) {
 		retErr = cleanup(ctx, retErr)
 	}()
 
-	vc := &tree.ValuesClause{Rows: c.rows}
+	var rows tree.SelectStatement = &tree.ValuesClause{Rows: c.rows}
+	if c.where != nil {
+		rows = c.filterRows(rows)
+	}
 	numRows := len(c.rows)
 	// Reuse the same backing array once the Insert is complete.
 	c.rows = c.rows[:0]
@@ -485,7 +772,7 @@ func (c *copyMachine) insertRows(ctx context.Context) (retErr error) {
 		Table:   c.table,
 		Columns: c.columns,
 		Rows: &tree.Select{
-			Select: vc,
+			Select: rows,
 		},
 		Returning: tree.AbsentReturningClause,
 	}
@@ -502,17 +789,37 @@ func (c *copyMachine) insertRows(ctx context.Context) (retErr error) {
 		return err
 	}
 
-	if rows := res.RowsAffected(); rows != numRows {
+	rowsAffected := res.RowsAffected()
+	if c.where == nil && rowsAffected != numRows {
 		log.Fatalf(ctx, "didn't insert all buffered rows and yet no error was reported. "+
-			"Inserted %d out of %d rows.", rows, numRows)
+			"Inserted %d out of %d rows.", rowsAffected, numRows)
 	}
-	c.insertedRows += numRows
+	c.insertedRows += rowsAffected
 
 	return nil
 }
 
+// filterRows returns a query producing the given rows which satisfy the WHERE
+// clause of the COPY statement, i.e.
+//
+//	SELECT * FROM (VALUES ...) AS copy_from (cols) WHERE filter
+func (c *copyMachine) filterRows(rows tree.SelectStatement) tree.SelectStatement {
+	cols := make(tree.NameList, len(c.resultColumns))
+	for i := range c.resultColumns {
+		cols[i] = tree.Name(c.resultColumns[i].Name)
+	}
+	return &tree.SelectClause{
+		Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+		From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+			Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{Select: rows}}},
+			As:   tree.AliasClause{Alias: "copy_from", Cols: cols},
+		}}},
+		Where: tree.NewWhere(tree.AstWhere, c.where),
+	}
+}
+
 func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
-	parts := bytes.Split(line, fieldDelim)
+	parts := splitCopyTextLine(line, c.opts.Delimiter)
 	if len(parts) != len(c.resultColumns) {
 		return pgerror.Newf(pgcode.BadCopyFileFormat,
 			"expected %d values, got %d", len(c.resultColumns), len(parts))
@@ -522,7 +829,7 @@ func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
 		s := string(part)
 		// Although the spec says this is only supported for CSV, we need it here to
 		// disable NULL conversion during file uploads.
-		if !c.forceNotNull && s == nullString {
+		if !c.forceNotNull && s == c.opts.Null {
 			exprs[i] = tree.DNull
 			continue
 		}
@@ -557,6 +864,59 @@ func (c *copyMachine) readTextTuple(ctx context.Context, line []byte) error {
 	return nil
 }
 
+// splitCopyTextLine splits a line of the text format into its fields. A
+// backslash escapes the following character, so an escaped delimiter is part
+// of a field; it is unescaped by decodeCopy.
+func splitCopyTextLine(line []byte, delim byte) [][]byte {
+	var parts [][]byte
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case delim:
+			parts = append(parts, line[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, line[start:])
+}
+
+func (c *copyMachine) readCSVTuple(
+	ctx context.Context, record []string, quoted func(i int) bool,
+) error {
+	if len(record) != len(c.resultColumns) {
+		return pgerror.Newf(pgcode.BadCopyFileFormat,
+			"expected %d values, got %d", len(c.resultColumns), len(record))
+	}
+	exprs := make(tree.Exprs, len(record))
+	for i, s := range record {
+		// Only unquoted values matching the null string are NULL, so that an
+		// empty string can be copied as "" with the default null string.
+		if s == c.opts.Null && (quoted == nil || !quoted(i)) {
+			exprs[i] = tree.DNull
+			continue
+		}
+		d, err := rowenc.ParseDatumStringAsWithRawBytes(c.resultColumns[i].Typ, s, c.parsingEvalCtx)
+		if err != nil {
+			return err
+		}
+
+		sz := d.Size()
+		if err := c.rowsMemAcc.Grow(ctx, int64(sz)); err != nil {
+			return err
+		}
+
+		exprs[i] = d
+	}
+	if err := c.rowsMemAcc.Grow(ctx, int64(unsafe.Sizeof(exprs))); err != nil {
+		return err
+	}
+
+	c.rows = append(c.rows, exprs)
+	return nil
+}
+
 // decodeCopy unescapes a single COPY field.
 //
 // See: https://www.postgresql.org/docs/9.5/static/sql-copy.html#AEN74432
@@ -87,8 +87,12 @@ func newFileUploadMachine(
 	if len(n.Columns) != 0 {
 		return nil, errors.New("expected 0 columns specified for file uploads")
 	}
+	if n.Where != nil {
+		return nil, errors.New("WHERE is not supported for file uploads")
+	}
 	c := &copyMachine{
 		conn: conn,
+		opts: CopyFormatOptions{Delimiter: '\t', Null: nullString},
 		// The planner will be prepared before use.
 		p: planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
 	}
@@ -11,7 +11,9 @@
 package sql_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
@@ -20,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -353,6 +356,55 @@ func TestCopyBinary(t *testing.T) {
 	sqlDB.CheckQueryResults(t, "SELECT * FROM t ORDER BY id", expect)
 }
 
+// TestCopyBinaryWhere checks that binary COPY filters the rows with the WHERE
+// clause, and that tuples can be split across CopyData messages.
+func TestCopyBinaryWhere(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB.Exec(t, `CREATE TABLE t (i INT8 PRIMARY KEY, s STRING)`)
+
+	pgURL, cleanupGoDB := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "StartServer" /* prefix */, url.User(security.RootUser))
+	defer cleanupGoDB()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	var data bytes.Buffer
+	data.WriteString("PGCOPY\n\377\r\n\000")
+	// Flags and header extension length.
+	data.Write(make([]byte, 8))
+	for i := 1; i <= 10; i++ {
+		val := strconv.Itoa(i * i)
+		_ = binary.Write(&data, binary.BigEndian, int16(2))
+		_ = binary.Write(&data, binary.BigEndian, int32(8))
+		_ = binary.Write(&data, binary.BigEndian, int64(i))
+		_ = binary.Write(&data, binary.BigEndian, int32(len(val)))
+		data.WriteString(val)
+	}
+	// Trailer.
+	_ = binary.Write(&data, binary.BigEndian, int16(-1))
+
+	// The data is sent one byte per CopyData message.
+	tag, err := conn.PgConn().CopyFrom(
+		ctx, iotest.OneByteReader(&data), `COPY t FROM STDIN WITH (FORMAT binary) WHERE i % 3 = 0`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), tag.RowsAffected())
+	sqlDB.CheckQueryResults(t, "SELECT * FROM t ORDER BY i", [][]string{
+		{"3", "9"}, {"6", "36"}, {"9", "81"},
+	})
+}
+
 func TestCopyError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
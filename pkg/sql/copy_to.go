@@ -0,0 +1,30 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// makeCopyOutOptions evaluates the options of a COPY ... TO STDOUT statement.
+func (p *planner) makeCopyOutOptions(
+	ctx context.Context, opts tree.CopyOptions,
+) (CopyFormatOptions, error) {
+	if opts.Destination != nil {
+		return CopyFormatOptions{}, pgerror.New(pgcode.Syntax,
+			"COPY TO does not support the destination option")
+	}
+	return p.makeCopyFormatOptions(ctx, opts)
+}
@@ -1733,6 +1733,32 @@ CREATE TABLE crdb_internal.create_type_statements (
 				); err != nil {
 					return err
 				}
+			case descpb.TypeDescriptor_COMPOSITE:
+				elems := make([]tree.CompositeTypeElem, len(typeDesc.Composite.Elements))
+				for i := range typeDesc.Composite.Elements {
+					elem := &typeDesc.Composite.Elements[i]
+					elems[i] = tree.CompositeTypeElem{Label: tree.Name(elem.Label), Type: elem.Type}
+				}
+				name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+				if err != nil {
+					return err
+				}
+				node := &tree.CreateType{
+					Variety:           tree.Composite,
+					TypeName:          name,
+					CompositeTypeList: elems,
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+					tree.NewDString(db.GetName()),             // database_name
+					tree.NewDString(sc),                       // schema_name
+					tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+					tree.NewDString(typeDesc.GetName()),       // descriptor_name
+					tree.NewDString(tree.AsString(node)),      // create_statement
+					tree.DNull,                                // enum_members
+				); err != nil {
+					return err
+				}
 			case descpb.TypeDescriptor_MULTIREGION_ENUM:
 				// Multi-region enums are created implicitly, so we don't have create
 				// statements for them.
@@ -0,0 +1,285 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createFunctionNode struct {
+	n *tree.CreateFunction
+	// funcName is the fully qualified name of the new function.
+	funcName *tree.TableName
+	// body contains the function body, with all table names fully qualified
+	// and parameter references replaced by placeholders.
+	body   string
+	dbDesc *dbdesc.Immutable
+	// planDeps tracks the tables, views and sequences which the function body
+	// refers to.
+	planDeps planDependencies
+	// typeDeps contains the user-defined types which the function body refers
+	// to.
+	typeDeps []*types.T
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if n.n.Replace {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("or_replace_function"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+	}
+
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.UserDefinedFunctions) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use user-defined functions",
+			clusterversion.UserDefinedFunctions)
+	}
+	if n.dbDesc.GetID() == keys.SystemDatabaseID {
+		return errors.New("cannot create a function in the system database")
+	}
+
+	opts, err := n.n.Options.Values()
+	if err != nil {
+		return err
+	}
+	funcParams, returnType, err := resolveFunctionSignature(params.ctx, params.p, n.n.Params, n.n.ReturnType)
+	if err != nil {
+		return err
+	}
+
+	dbID := n.dbDesc.GetID()
+	schemaID, err := params.p.getSchemaIDForCreate(params.ctx, params.ExecCfg().Codec, dbID, n.funcName.Schema())
+	if err != nil {
+		return err
+	}
+	if err := params.p.canCreateOnSchema(
+		params.ctx, schemaID, dbID, params.p.User(), skipCheckPublicSchema); err != nil {
+		return err
+	}
+	if schemaID != keys.PublicSchemaID {
+		sqltelemetry.IncrementUserDefinedSchemaCounter(sqltelemetry.UserDefinedSchemaUsedByObject)
+	}
+
+	funcKey := catalogkv.MakeObjectNameKey(
+		params.ctx, params.ExecCfg().Settings, dbID, schemaID, n.funcName.Object(),
+	)
+	exists, collided, err := catalogkv.LookupObjectID(
+		params.ctx, params.p.txn, params.ExecCfg().Codec, dbID, schemaID, n.funcName.Object())
+	if err != nil {
+		return err
+	}
+	if exists {
+		desc, err := catalogkv.GetAnyDescriptorByID(
+			params.ctx, params.p.txn, params.ExecCfg().Codec, collided, catalogkv.Immutable)
+		if err != nil {
+			return sqlerrors.WrapErrorWhileConstructingObjectAlreadyExistsErr(err)
+		}
+		if desc.DescriptorProto().GetFunction() == nil || !n.n.Replace {
+			return sqlerrors.MakeObjectAlreadyExistsError(desc.DescriptorProto(), n.funcName.String())
+		}
+		return n.replaceFunction(params, collided, funcParams, returnType, opts)
+	}
+
+	id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+
+	// Function privileges do not overlap with database or schema privileges,
+	// so there is nothing to inherit. As in Postgres, everyone may execute a
+	// new function until the privilege is revoked.
+	privs := descpb.NewDefaultPrivilegeDescriptor(params.p.User())
+	privs.Grant(params.p.User(), privilege.List{privilege.ALL})
+	privs.Grant(security.PublicRoleName(), privilege.List{privilege.EXECUTE})
+
+	funcDesc := funcdesc.NewCreatedMutable(descpb.FunctionDescriptor{
+		Name:              n.funcName.Object(),
+		ID:                id,
+		ParentID:          dbID,
+		ParentSchemaID:    schemaID,
+		Version:           1,
+		Privileges:        privs,
+		Params:            funcParams,
+		ReturnType:        returnType,
+		Volatility:        funcdesc.VolatilityFromTree(opts.TreeVolatility()),
+		LeakProof:         opts.LeakProof,
+		NullInputBehavior: nullInputBehaviorFromOptions(opts),
+		FunctionBody:      n.body,
+	})
+	if err := params.p.addFunctionDependencies(
+		params.ctx, funcDesc, n.planDeps, n.typeDepIDs(),
+	); err != nil {
+		return err
+	}
+
+	return params.p.createDescriptorWithID(
+		params.ctx,
+		funcKey.Key(params.ExecCfg().Codec),
+		id,
+		funcDesc,
+		params.EvalContext().Settings,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// replaceFunction implements CREATE OR REPLACE FUNCTION for an existing
+// function. The signature of the function cannot change, since views may
+// depend on it.
+func (n *createFunctionNode) replaceFunction(
+	params runParams,
+	id descpb.ID,
+	funcParams []descpb.FunctionDescriptor_Param,
+	returnType *types.T,
+	opts tree.FunctionOptionValues,
+) error {
+	desc, err := params.p.Descriptors().GetMutableFunctionByID(
+		params.ctx, params.p.txn, id, tree.ObjectLookupFlags{})
+	if err != nil {
+		return err
+	}
+	if err := params.p.canModifyFunction(params.ctx, desc); err != nil {
+		return err
+	}
+	if !desc.ReturnType.Identical(returnType) {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"cannot change return type of existing function")
+	}
+	if len(desc.Params) != len(funcParams) {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists with different parameters", desc.Name),
+			"function overloading is not supported.")
+	}
+	for i := range funcParams {
+		if !desc.Params[i].Type.Identical(funcParams[i].Type) {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.DuplicateFunction,
+					"function %q already exists with different parameters", desc.Name),
+				"function overloading is not supported.")
+		}
+		if desc.Params[i].Name != funcParams[i].Name {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change name of input parameter %q", desc.Params[i].Name)
+		}
+	}
+
+	desc.Volatility = funcdesc.VolatilityFromTree(opts.TreeVolatility())
+	desc.LeakProof = opts.LeakProof
+	desc.NullInputBehavior = nullInputBehaviorFromOptions(opts)
+	desc.FunctionBody = n.body
+	// Replace the dependencies of the old body with those of the new one.
+	if err := params.p.removeFunctionDependencies(params.ctx, desc); err != nil {
+		return err
+	}
+	if err := params.p.addFunctionDependencies(
+		params.ctx, desc, n.planDeps, n.typeDepIDs(),
+	); err != nil {
+		return err
+	}
+	return params.p.writeFunctionDescChange(params.ctx, desc,
+		fmt.Sprintf("CREATE OR REPLACE FUNCTION %s", n.funcName.FQString()))
+}
+
+// typeDepIDs returns the IDs of the user-defined types which the function
+// body refers to, along with the IDs of their array types.
+func (n *createFunctionNode) typeDepIDs() descpb.IDs {
+	idSet := make(map[descpb.ID]struct{})
+	for _, typ := range n.typeDeps {
+		for id := range typedesc.GetTypeDescriptorClosure(typ) {
+			idSet[id] = struct{}{}
+		}
+	}
+	ids := make(descpb.IDs, 0, len(idSet))
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+	sort.Sort(ids)
+	return ids
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
+
+// resolveFunctionSignature resolves the types of the parameters and the
+// return type of a function.
+func resolveFunctionSignature(
+	ctx context.Context, p *planner, params tree.FuncParams, ret tree.ResolvableTypeReference,
+) ([]descpb.FunctionDescriptor_Param, *types.T, error) {
+	resolve := func(ref tree.ResolvableTypeReference) (*types.T, error) {
+		typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, err
+		}
+		if typ.UserDefined() {
+			return nil, unimplemented.NewWithIssue(17511,
+				"user-defined types in function signatures are not supported")
+		}
+		return typ, nil
+	}
+	res := make([]descpb.FunctionDescriptor_Param, len(params))
+	for i := range params {
+		typ, err := resolve(params[i].Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		name := string(params[i].Name)
+		if name != "" {
+			for j := 0; j < i; j++ {
+				if res[j].Name == name {
+					return nil, nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+						"parameter name %q used more than once", name)
+				}
+			}
+		}
+		res[i] = descpb.FunctionDescriptor_Param{Name: name, Type: typ}
+	}
+	returnType, err := resolve(ret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, returnType, nil
+}
+
+func nullInputBehaviorFromOptions(
+	opts tree.FunctionOptionValues,
+) descpb.FunctionDescriptor_NullInputBehavior {
+	if opts.IsStrict() {
+		return descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+	}
+	return descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+}
@@ -207,8 +207,20 @@ func MakeIndexDescriptor(
 		case types.GeographyFamily:
 			indexDesc.GeoConfig = *geoindex.DefaultGeographyIndexConfig()
 			telemetry.Inc(sqltelemetry.GeographyInvertedIndexCounter)
+		case types.StringFamily:
+			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
+		}
+		if err := checkIndexOpClasses(n.Columns, columnDesc.Type); err != nil {
+			return nil, err
+		}
+		if err := setInvertedColumnKind(
+			params.ctx, params.ExecCfg().Settings.Version, &indexDesc, n.Columns,
+		); err != nil {
+			return nil, err
 		}
 		telemetry.Inc(sqltelemetry.InvertedIndexCounter)
+	} else if err := checkIndexOpClasses(n.Columns, nil /* invertedColTyp */); err != nil {
+		return nil, err
 	}
 
 	if n.Sharded != nil {
@@ -253,6 +265,9 @@ func MakeIndexDescriptor(
 	if err := indexDesc.FillColumns(n.Columns); err != nil {
 		return nil, err
 	}
+	if err := checkIndexNullsOrder(params.ctx, params.ExecCfg().Settings.Version, &indexDesc); err != nil {
+		return nil, err
+	}
 
 	if err := paramparse.ApplyStorageParameters(
 		params.ctx,
@@ -284,6 +299,81 @@ func validateIndexColumnsExist(desc *tabledesc.Mutable, columns tree.IndexElemLi
 	return nil
 }
 
+// checkIndexNullsOrder returns an error if a column of the index has a
+// non-default NULL ordering and not all nodes in the cluster know how to
+// encode the keys of such an index.
+func checkIndexNullsOrder(
+	ctx context.Context, version clusterversion.Handle, idx *descpb.IndexDescriptor,
+) error {
+	if len(idx.ColumnNullsReversed) == 0 || version.IsActive(ctx, clusterversion.IndexNullsOrder) {
+		return nil
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"version %v must be finalized to use NULLS FIRST or NULLS LAST in an index",
+		clusterversion.IndexNullsOrder)
+}
+
+// checkIndexOpClasses validates the operator classes of the index elements.
+// invertedColTyp is the type of the inverted column if the index is inverted,
+// or nil if it is a forward index. Only the trigram operator classes are
+// supported, and they must be specified for (and only for) an inverted
+// STRING column.
+func checkIndexOpClasses(elems tree.IndexElemList, invertedColTyp *types.T) error {
+	for i := range elems {
+		opClass := string(elems[i].OpClass)
+		if invertedColTyp == nil {
+			if opClass != "" {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"operator class %q does not exist for access method \"btree\"", opClass)
+			}
+			continue
+		}
+		if i < len(elems)-1 {
+			if opClass != "" {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"operator classes are only supported for the last column of an inverted index")
+			}
+			continue
+		}
+		isTrigram := colinfo.ColumnTypeIsTrigramIndexable(invertedColTyp)
+		if opClass == "" && isTrigram {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.UndefinedObject,
+					"data type %s has no default operator class for access method \"gin\"",
+					invertedColTyp.SQLString()),
+				"You must specify an operator class for the index, such as gin_trgm_ops.",
+			)
+		}
+		if opClass != "" && !isTrigram {
+			return pgerror.Newf(pgcode.DatatypeMismatch,
+				"operator class %q does not accept data type %s", opClass, invertedColTyp.SQLString())
+		}
+	}
+	return nil
+}
+
+// setInvertedColumnKind sets the kind of the inverted column of the inverted
+// index idx from the operator class of its last element, which must have been
+// validated by checkIndexOpClasses. Trigram indexes can only be created once
+// all nodes in the cluster know how to encode their keys.
+func setInvertedColumnKind(
+	ctx context.Context,
+	version clusterversion.Handle,
+	idx *descpb.IndexDescriptor,
+	elems tree.IndexElemList,
+) error {
+	if elems[len(elems)-1].OpClass == "" {
+		return nil
+	}
+	if !version.IsActive(ctx, clusterversion.TrigramInvertedIndexes) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use trigram inverted indexes",
+			clusterversion.TrigramInvertedIndexes)
+	}
+	idx.InvertedColumnKind = descpb.IndexDescriptor_TRIGRAM
+	return nil
+}
+
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
 // This is because CREATE INDEX performs multiple KV operations on descriptors
 // and expects to see its own writes.
@@ -87,6 +87,7 @@ var minimumTypeUsageVersions = map[types.Family]clusterversion.Key{
 	types.GeographyFamily: clusterversion.GeospatialType,
 	types.GeometryFamily:  clusterversion.GeospatialType,
 	types.Box2DFamily:     clusterversion.Box2DType,
+	types.RangeFamily:     clusterversion.RangeTypes,
 }
 
 // isTypeSupportedInVersion returns whether a given type is supported in the given version.
@@ -599,6 +600,15 @@ func (p *planner) MaybeUpgradeDependentOldForeignKeyVersionTables(
 	return nil
 }
 
+// errDeferrableUniqueIndex is returned for deferrable unique constraints which
+// would be enforced by a unique index. The uniqueness of the keys of an index
+// is enforced when they are written, so it cannot be deferred.
+var errDeferrableUniqueIndex = errors.WithHint(
+	pgerror.New(pgcode.FeatureNotSupported,
+		"deferrable unique constraints are only supported without an index"),
+	"use UNIQUE WITHOUT INDEX",
+)
+
 // ResolveUniqueWithoutIndexConstraint looks up the columns mentioned in a
 // UNIQUE WITHOUT INDEX constraint and adds metadata representing that
 // constraint to the descriptor.
@@ -613,6 +623,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	colNames []string,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
+	deferrability tree.ConstraintDeferrability,
 ) error {
 	var colSet catalog.TableColSet
 	cols := make([]*descpb.ColumnDescriptor, len(colNames))
@@ -664,10 +675,12 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Validity:  validity,
+		Name:              constraintName,
+		TableID:           tbl.ID,
+		ColumnIDs:         columnIDs,
+		Validity:          validity,
+		Deferrable:        deferrability.Deferrable(),
+		InitiallyDeferred: deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -683,6 +696,101 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// ResolveExclusionConstraint looks up the columns mentioned in an EXCLUDE
+// constraint, checks that their operators are usable for exclusion, and adds
+// metadata representing that constraint to the descriptor.
+//
+// The passed validationBehavior is used to determine whether or not preexisting
+// entries in the table need to be validated against the exclusion constraint
+// being added. This only applies for existing tables, not new tables.
+func ResolveExclusionConstraint(
+	ctx context.Context,
+	tbl *tabledesc.Mutable,
+	d *tree.ExclusionConstraintTableDef,
+	ts TableState,
+	validationBehavior tree.ValidationBehavior,
+) error {
+	var colSet catalog.TableColSet
+	columnIDs := make(descpb.ColumnIDs, len(d.Elems))
+	operators := make([]string, len(d.Elems))
+	colNames := make([]string, len(d.Elems))
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		col, err := tbl.FindActiveOrNewColumnByName(elem.Column)
+		if err != nil {
+			return err
+		}
+		// Ensure that the columns don't have duplicates.
+		if colSet.Contains(col.ID) {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"column %q appears twice in exclusion constraint", col.Name)
+		}
+		colSet.Add(col.ID)
+
+		if _, ok := descpb.ExclusionOperators[elem.Operator.String()]; !ok {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "operator %s is not commutative", elem.Operator),
+				"Only commutative operators can be used in exclusion constraints.",
+			)
+		}
+		// Ensure that the operator is defined for the column's type.
+		op, _, _, _, _ := tree.FoldComparisonExpr(elem.Operator, nil, nil)
+		if _, ok := tree.CmpOps[op].LookupImpl(col.Type, col.Type); !ok {
+			return pgerror.Newf(pgcode.UndefinedFunction,
+				"unsupported comparison operator: <%s> %s <%s>", col.Type, elem.Operator, col.Type)
+		}
+
+		columnIDs[i] = col.ID
+		operators[i] = elem.Operator.String()
+		colNames[i] = col.Name
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	constraintInfo, err := tbl.GetConstraintInfo(ctx, nil)
+	if err != nil {
+		return err
+	}
+	constraintName := string(d.Name)
+	if constraintName == "" {
+		constraintName = tabledesc.GenerateUniqueConstraintName(
+			fmt.Sprintf("excl_%s", strings.Join(colNames, "_")),
+			func(p string) bool {
+				_, ok := constraintInfo[p]
+				return ok
+			},
+		)
+	} else {
+		if _, ok := constraintInfo[constraintName]; ok {
+			return pgerror.Newf(pgcode.DuplicateObject, "duplicate constraint name: %q", constraintName)
+		}
+	}
+
+	validity := descpb.ConstraintValidity_Validated
+	if ts != NewTable {
+		if validationBehavior == tree.ValidationSkip {
+			validity = descpb.ConstraintValidity_Unvalidated
+		} else {
+			validity = descpb.ConstraintValidity_Validating
+		}
+	}
+
+	ec := descpb.ExclusionConstraint{
+		Name:      constraintName,
+		TableID:   tbl.ID,
+		ColumnIDs: columnIDs,
+		Operators: operators,
+		Validity:  validity,
+	}
+
+	if ts == NewTable {
+		tbl.ExclusionConstraints = append(tbl.ExclusionConstraints, ec)
+	} else {
+		tbl.AddExclusionMutation(&ec, descpb.DescriptorMutation_ADD)
+	}
+
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -719,6 +827,13 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if d.Deferrability.Deferrable() &&
+		!evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use DEFERRABLE constraints",
+			clusterversion.DeferrableConstraints)
+	}
+
 	var originColSet catalog.TableColSet
 	originCols := make([]*descpb.ColumnDescriptor, len(d.FromCols))
 	for i, col := range d.FromCols {
@@ -934,6 +1049,8 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrable:          d.Deferrability.Deferrable(),
+		InitiallyDeferred:   d.Deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -1074,7 +1191,8 @@ func addInterleave(
 				strings.Join(index.ColumnNames, ", "),
 			)
 		}
-		if !col.Type.Identical(targetCol.Type) || index.ColumnDirections[i] != parentIndex.GetColumnDirection(i) {
+		if !col.Type.Identical(targetCol.Type) || index.ColumnDirections[i] != parentIndex.GetColumnDirection(i) ||
+			index.ColumnNullsReversedAt(i) != parentIndex.GetColumnNullsReversed(i) {
 			return pgerror.Newf(
 				pgcode.InvalidSchemaDefinition,
 				"declared interleaved columns (%s) must match type and sort direction of the parent's primary index (%s)",
@@ -1705,6 +1823,9 @@ func NewTableDesc(
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return nil, err
 			}
+			if err := checkIndexNullsOrder(ctx, st.Version, &idx); err != nil {
+				return nil, err
+			}
 			if d.Inverted {
 				columnDesc, _, err := desc.FindColumnByName(tree.Name(idx.InvertedColumnName()))
 				if err != nil {
@@ -1720,6 +1841,14 @@ func NewTableDesc(
 				case types.GeographyFamily:
 					idx.GeoConfig = *geoindex.DefaultGeographyIndexConfig()
 				}
+				if err := checkIndexOpClasses(d.Columns, columnDesc.Type); err != nil {
+					return nil, err
+				}
+				if err := setInvertedColumnKind(ctx, st.Version, &idx, d.Columns); err != nil {
+					return nil, err
+				}
+			} else if err := checkIndexOpClasses(d.Columns, nil /* invertedColTyp */); err != nil {
+				return nil, err
 			}
 			if d.PartitionByIndex.ContainsPartitions() || partitionByAll != nil {
 				partitionBy := partitionByAll
@@ -1785,6 +1914,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrability.Deferrable() {
+				return nil, errDeferrableUniqueIndex
+			}
 			idx := descpb.IndexDescriptor{
 				Name:             string(d.Name),
 				Unique:           true,
@@ -1799,9 +1931,15 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
+			if err := checkIndexOpClasses(d.Columns, nil /* invertedColTyp */); err != nil {
+				return nil, err
+			}
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return nil, err
 			}
+			if err := checkIndexNullsOrder(ctx, st.Version, &idx); err != nil {
+				return nil, err
+			}
 			if d.PartitionByIndex.ContainsPartitions() || partitionByAll != nil {
 				partitionBy := partitionByAll
 				if partitionByAll == nil {
@@ -1864,7 +2002,8 @@ func NewTableDesc(
 			if d.Interleave != nil {
 				return nil, unimplemented.NewWithIssue(9148, "use CREATE INDEX to make interleaved indexes")
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -2023,7 +2162,7 @@ func NewTableDesc(
 				// Add a unique constraint.
 				if err := ResolveUniqueWithoutIndexConstraint(
 					ctx, &desc, string(d.Unique.ConstraintName), []string{string(d.Name)}, NewTable,
-					tree.ValidationDefault,
+					tree.ValidationDefault, tree.NotDeferrableConstraint,
 				); err != nil {
 					return nil, err
 				}
@@ -2056,6 +2195,12 @@ func NewTableDesc(
 						"partitioned unique constraints without an index are not supported",
 					)
 				}
+				if d.Deferrability.Deferrable() &&
+					!evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+					return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+						"version %v must be finalized to use DEFERRABLE constraints",
+						clusterversion.DeferrableConstraints)
+				}
 				if d.Predicate != nil {
 					// TODO(rytaft): It may be necessary to support predicates so that partial
 					// unique indexes will work correctly in multi-region deployments.
@@ -2070,6 +2215,7 @@ func NewTableDesc(
 				}
 				if err := ResolveUniqueWithoutIndexConstraint(
 					ctx, &desc, string(d.Name), colNames, NewTable, tree.ValidationDefault,
+					d.Deferrability,
 				); err != nil {
 					return nil, err
 				}
@@ -2092,6 +2238,18 @@ func NewTableDesc(
 				return nil, err
 			}
 
+		case *tree.ExclusionConstraintTableDef:
+			if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.ExclusionConstraints) {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use exclusion constraints",
+					clusterversion.ExclusionConstraints)
+			}
+			if err := ResolveExclusionConstraint(
+				ctx, &desc, d, NewTable, tree.ValidationDefault,
+			); err != nil {
+				return nil, err
+			}
+
 		default:
 			return nil, errors.Errorf("unsupported table def: %T", def)
 		}
@@ -2145,6 +2303,9 @@ func NewTableDesc(
 					telemetry.Inc(sqltelemetry.GeometryInvertedIndexCounter)
 				}
 			}
+			if idx.IndexDesc().InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
+			}
 		}
 		return nil
 	}); err != nil {
@@ -2413,6 +2574,16 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					if idx.GetColumnDirection(j) == descpb.IndexDescriptor_DESC {
 						elem.Direction = tree.Descending
 					}
+					if idx.GetColumnNullsReversed(j) {
+						elem.NullsOrder = tree.NullsLast
+						if elem.Direction == tree.Descending {
+							elem.NullsOrder = tree.NullsFirst
+						}
+					}
+					if j == numColumns-1 &&
+						idx.IndexDesc().InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+						elem.OpClass = "gin_trgm_ops"
+					}
 					indexDef.Columns = append(indexDef.Columns, elem)
 				}
 				for j := 0; j < idx.NumStoredColumns(); j++ {
@@ -2634,6 +2805,11 @@ func detectImplicitPartitionColumns(
 		indexDesc.ColumnNames = append(implicitColumns, indexDesc.ColumnNames...)
 		indexDesc.ColumnIDs = append(implicitColumnIDs, indexDesc.ColumnIDs...)
 		indexDesc.ColumnDirections = append(implicitColumnDirections, indexDesc.ColumnDirections...)
+		if len(indexDesc.ColumnNullsReversed) > 0 {
+			indexDesc.ColumnNullsReversed = append(
+				make([]bool, len(implicitColumns)), indexDesc.ColumnNullsReversed...,
+			)
+		}
 	}
 	return indexDesc, len(implicitColumns), nil
 }
@@ -0,0 +1,89 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type createTriggerNode struct {
+	n       *tree.CreateTrigger
+	tableID descpb.ID
+	// when is the serialized WHEN condition of the trigger, or empty.
+	when string
+	// body contains the statement run by the trigger, with all table names
+	// fully qualified.
+	body string
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.RowLevelTriggers) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use triggers",
+			clusterversion.RowLevelTriggers)
+	}
+
+	tableDesc, err := params.p.Descriptors().GetMutableTableVersionByID(
+		params.ctx, n.tableID, params.p.txn,
+	)
+	if err != nil {
+		return err
+	}
+	name := string(n.n.Name)
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == name {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", name, tableDesc.Name)
+		}
+	}
+
+	trigger := descpb.TableDescriptor_Trigger{
+		Name:       name,
+		ActionTime: descpb.TableDescriptor_Trigger_ActionTime(n.n.ActionTime),
+		WhenExpr:   n.when,
+		Body:       n.body,
+	}
+	for _, event := range n.n.Events {
+		trigger.Events = append(trigger.Events, descpb.TableDescriptor_Trigger_Event(event))
+	}
+	// The triggers are kept sorted by name, which is the order in which they
+	// fire.
+	idx := sort.Search(len(tableDesc.Triggers), func(i int) bool {
+		return tableDesc.Triggers[i].Name > name
+	})
+	tableDesc.Triggers = append(tableDesc.Triggers, descpb.TableDescriptor_Trigger{})
+	copy(tableDesc.Triggers[idx+1:], tableDesc.Triggers[idx:])
+	tableDesc.Triggers[idx] = trigger
+
+	return params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
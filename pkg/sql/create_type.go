@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/enum"
@@ -98,6 +99,8 @@ func (n *createTypeNode) startExec(params runParams) error {
 	switch n.n.Variety {
 	case tree.Enum:
 		return params.p.createUserDefinedEnum(params, n)
+	case tree.Composite:
+		return params.p.createUserDefinedComposite(params, n)
 	default:
 		return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 	}
@@ -243,6 +246,16 @@ func (p *planner) createArrayType(
 	switch t := typDesc.Kind; t {
 	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
 		elemTyp = types.MakeEnum(typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id))
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents := make([]*types.T, len(typDesc.Composite.Elements))
+		labels := make([]string, len(typDesc.Composite.Elements))
+		for i := range typDesc.Composite.Elements {
+			contents[i] = typDesc.Composite.Elements[i].Type
+			labels[i] = typDesc.Composite.Elements[i].Label
+		}
+		elemTyp = types.MakeComposite(
+			typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), contents, labels,
+		)
 	default:
 		return 0, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -405,6 +418,120 @@ func (p *planner) createEnumWithID(
 		})
 }
 
+func (p *planner) createUserDefinedComposite(params runParams, n *createTypeNode) error {
+	// Make sure that all nodes in the cluster are able to decode composite type
+	// descriptors and the values of composite types.
+	if !p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.CompositeTypes) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create composite types",
+			clusterversion.CompositeTypes)
+	}
+
+	// Resolve and validate the types of the attributes.
+	elems := make([]descpb.TypeDescriptor_Composite_Element, len(n.n.CompositeTypeList))
+	seenLabels := make(map[tree.Name]struct{})
+	for i := range n.n.CompositeTypeList {
+		elem := &n.n.CompositeTypeList[i]
+		if _, ok := seenLabels[elem.Label]; ok {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"column %q specified more than once", elem.Label)
+		}
+		seenLabels[elem.Label] = struct{}{}
+		typ, err := p.resolveCompositeElementType(params.ctx, elem.Type)
+		if err != nil {
+			return err
+		}
+		elems[i] = descpb.TypeDescriptor_Composite_Element{
+			Type:  typ,
+			Label: string(elem.Label),
+			ID:    uint32(i + 1),
+		}
+	}
+
+	// Generate a stable ID and a key in the namespace table for the new type.
+	id, err := catalogkv.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+	typeKey, schemaID, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+
+	// As for enums, having USAGE on the parent schema of the type gives USAGE
+	// privilege on the type.
+	privs := descpb.NewDefaultPrivilegeDescriptor(params.p.User())
+	resolvedSchema, err := p.Descriptors().GetImmutableSchemaByID(
+		params.ctx, p.Txn(), schemaID, tree.SchemaLookupFlags{})
+	if err != nil {
+		return err
+	}
+	inheritUsagePrivilegeFromSchema(resolvedSchema, privs)
+	privs.Grant(params.p.User(), privilege.List{privilege.ALL})
+
+	typeDesc := typedesc.NewCreatedMutable(
+		descpb.TypeDescriptor{
+			Name:           n.typeName.Type(),
+			ID:             id,
+			ParentID:       n.dbDesc.GetID(),
+			ParentSchemaID: schemaID,
+			Kind:           descpb.TypeDescriptor_COMPOSITE,
+			Composite: &descpb.TypeDescriptor_Composite{
+				Elements:      elems,
+				NextElementID: uint32(len(elems) + 1),
+			},
+			Version:    1,
+			Privileges: privs,
+		})
+
+	// Create the implicit array type for this type before finishing the type.
+	arrayTypeID, err := p.createArrayType(params, n.typeName, typeDesc, n.dbDesc, schemaID)
+	if err != nil {
+		return err
+	}
+	typeDesc.ArrayTypeID = arrayTypeID
+
+	if err := p.createDescriptorWithID(
+		params.ctx,
+		typeKey.Key(params.ExecCfg().Codec),
+		id,
+		typeDesc,
+		params.EvalContext().Settings,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	return p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
+// resolveCompositeElementType resolves the type of an attribute of a composite
+// type and checks that it can be used as one.
+func (p *planner) resolveCompositeElementType(
+	ctx context.Context, ref tree.ResolvableTypeReference,
+) (*types.T, error) {
+	typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	// Values of a composite type are stored using its descriptor, which does
+	// not track references to other user defined types.
+	if typ.UserDefined() {
+		return nil, unimplemented.NewWithIssueDetailf(27792, "udt attribute",
+			"user defined types cannot be used as composite type attributes")
+	}
+	if err := colinfo.ValidateColumnDefType(typ); err != nil {
+		return nil, err
+	}
+	return typ, nil
+}
+
 func (n *createTypeNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *createTypeNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *createTypeNode) Close(ctx context.Context)           {}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -52,6 +53,19 @@ type createViewNode struct {
 	// depends on. This is collected during the construction of
 	// the view query's logical plan.
 	planDeps planDependencies
+
+	// funcDeps contains the user-defined functions called by the view query.
+	funcDeps []cat.Function
+}
+
+// funcDepIDs returns the IDs of the user-defined functions that the view
+// depends on.
+func (n *createViewNode) funcDepIDs() []descpb.ID {
+	ids := make([]descpb.ID, len(n.funcDeps))
+	for i, fn := range n.funcDeps {
+		ids[i] = fn.(*optFunction).desc.ID
+	}
+	return ids
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -194,6 +208,8 @@ func (n *createViewNode) startExec(params runParams) error {
 			desc.IsMaterializedView = true
 			desc.State = descpb.DescriptorState_ADD
 			desc.CreateAsOfTime = params.p.Txn().ReadTimestamp()
+			refreshAsOf := desc.CreateAsOfTime
+			desc.RefreshAsOfTime = &refreshAsOf
 			if err := desc.AllocateIDs(params.ctx); err != nil {
 				return err
 			}
@@ -203,6 +219,7 @@ func (n *createViewNode) startExec(params runParams) error {
 		for backrefID := range n.planDeps {
 			desc.DependsOn = append(desc.DependsOn, backrefID)
 		}
+		desc.DependsOnFunctions = n.funcDepIDs()
 
 		// TODO (lucy): I think this needs a NodeFormatter implementation. For now,
 		// do some basic string formatting (not accurate in the general case).
@@ -251,6 +268,14 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
+	// Install back references to functions called by this view.
+	for _, id := range newDesc.DependsOnFunctions {
+		jobDesc := fmt.Sprintf("updating function back reference %d for view %d", id, newDesc.ID)
+		if err := params.p.addFunctionBackReference(params.ctx, id, newDesc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+
 	dg := catalogkv.NewOneLevelUncachedDescGetter(params.p.txn, params.ExecCfg().Codec)
 	if err := newDesc.Validate(params.ctx, dg); err != nil {
 		return err
@@ -366,12 +391,29 @@ func (p *planner) replaceViewDesc(
 		}
 	}
 
+	// Remove the back reference from all functions that the new view
+	// definition no longer calls.
+	funcDepIDs := n.funcDepIDs()
+	for _, id := range toReplace.DependsOnFunctions {
+		stillUsed := false
+		for _, newID := range funcDepIDs {
+			stillUsed = stillUsed || newID == id
+		}
+		if !stillUsed {
+			jobDesc := fmt.Sprintf("removing function back reference %d for view %d", id, toReplace.ID)
+			if err := p.removeFunctionBackReference(ctx, id, toReplace.ID, jobDesc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Since the view query has been replaced, the dependencies that this
 	// table descriptor had are gone.
 	toReplace.DependsOn = make([]descpb.ID, 0, len(n.planDeps))
 	for backrefID := range n.planDeps {
 		toReplace.DependsOn = append(toReplace.DependsOn, backrefID)
 	}
+	toReplace.DependsOnFunctions = funcDepIDs
 
 	// Since we are replacing an existing view here, we need to write the new
 	// descriptor into place.
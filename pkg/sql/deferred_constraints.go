@@ -0,0 +1,368 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/errors"
+)
+
+// SetConstraints implements the SET CONSTRAINTS statement.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html for
+// details.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	return &setConstraintsNode{n: n}, nil
+}
+
+type setConstraintsNode struct {
+	n *tree.SetConstraints
+}
+
+func (n *setConstraintsNode) startExec(params runParams) error {
+	// The queries must see the descriptors modified by the transaction.
+	ie := params.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+	ie.tcModifier = params.p.Descriptors()
+	defer func() {
+		ie.tcModifier = nil
+	}()
+
+	// Verify that the named constraints exist and are deferrable. SET
+	// CONSTRAINTS refers to constraints by name only, so all the constraints
+	// with the given name are affected.
+	for _, name := range n.n.Names {
+		row, err := ie.QueryRowEx(
+			params.ctx, "set-constraints", params.p.txn, sessiondata.InternalExecutorOverride{},
+			`SELECT bool_and(condeferrable) FROM pg_catalog.pg_constraint WHERE conname = $1`,
+			string(name),
+		)
+		if err != nil {
+			return err
+		}
+		if row[0] == tree.DNull {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", string(name))
+		}
+		if !bool(tree.MustBeDBool(row[0])) {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"constraint %q is not deferrable", string(name))
+		}
+	}
+
+	immediate := params.p.deferredConstraints.setMode(n.n.Names, n.n.Deferred)
+	return checkDeferredConstraints(params.ctx, ie, params.p.txn, params.p.Descriptors(), immediate)
+}
+
+func (n *setConstraintsNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *setConstraintsNode) Values() tree.Datums                 { return nil }
+func (n *setConstraintsNode) Close(ctx context.Context)           {}
+
+// deferredConstraints gives a planner access to the state of the deferrable
+// constraints in the current transaction.
+type deferredConstraints interface {
+	// isDeferred returns whether the checking of the given constraint is
+	// currently deferred until the end of the transaction.
+	isDeferred(check *exec.DeferrableCheck) bool
+	// addViolation records a possible violation of a deferred constraint, to be
+	// checked again at the end of the transaction.
+	addViolation(check *exec.DeferrableCheck, keyVals tree.Datums)
+	// setMode sets the mode of the named constraints (or of all the
+	// constraints, if names is nil) for the rest of the transaction. If the
+	// constraints become immediate, their pending checks are returned.
+	setMode(names tree.NameList, deferred bool) []pendingConstraintCheck
+}
+
+// pendingConstraintCheck is a possible violation of a deferred constraint.
+type pendingConstraintCheck struct {
+	check   *exec.DeferrableCheck
+	keyVals tree.Datums
+	// key identifies the constraint and the key values.
+	key string
+}
+
+// constraintMode is the mode of a deferrable constraint, set by SET
+// CONSTRAINTS.
+type constraintMode int
+
+const (
+	// constraintModeDefault is the mode given by INITIALLY DEFERRED or
+	// INITIALLY IMMEDIATE.
+	constraintModeDefault constraintMode = iota
+	constraintModeDeferred
+	constraintModeImmediate
+)
+
+// deferredConstraintSet is the deferredConstraints implementation of a
+// connExecutor. Its state is scoped to the current transaction.
+type deferredConstraintSet struct {
+	// all is the mode set by SET CONSTRAINTS ALL.
+	all constraintMode
+	// modes contains the modes set for specific constraints after the last
+	// SET CONSTRAINTS ALL, keyed by constraint name.
+	modes map[string]constraintMode
+
+	// pending contains the possible violations of deferred constraints, in the
+	// order in which they were found; seen is used to deduplicate them.
+	pending []pendingConstraintCheck
+	seen    map[string]struct{}
+}
+
+var _ deferredConstraints = &deferredConstraintSet{}
+
+// reset clears the state at the end of a transaction.
+func (d *deferredConstraintSet) reset() {
+	*d = deferredConstraintSet{}
+}
+
+// takePending removes and returns all the pending checks.
+func (d *deferredConstraintSet) takePending() []pendingConstraintCheck {
+	pending := d.pending
+	d.pending = nil
+	d.seen = nil
+	return pending
+}
+
+func (d *deferredConstraintSet) isDeferred(check *exec.DeferrableCheck) bool {
+	mode := d.modes[check.Name]
+	if mode == constraintModeDefault {
+		mode = d.all
+	}
+	switch mode {
+	case constraintModeDeferred:
+		return true
+	case constraintModeImmediate:
+		return false
+	default:
+		return check.InitiallyDeferred
+	}
+}
+
+func (d *deferredConstraintSet) addViolation(check *exec.DeferrableCheck, keyVals tree.Datums) {
+	key := fmt.Sprintf("%d/%s/%s", check.TableID, check.Name, &tree.DTuple{D: keyVals})
+	if _, ok := d.seen[key]; ok {
+		return
+	}
+	if d.seen == nil {
+		d.seen = make(map[string]struct{})
+	}
+	d.seen[key] = struct{}{}
+	d.pending = append(d.pending, pendingConstraintCheck{check: check, keyVals: keyVals, key: key})
+}
+
+func (d *deferredConstraintSet) setMode(
+	names tree.NameList, deferred bool,
+) []pendingConstraintCheck {
+	mode := constraintModeImmediate
+	if deferred {
+		mode = constraintModeDeferred
+	}
+	if names == nil {
+		d.all = mode
+		d.modes = nil
+		if deferred {
+			return nil
+		}
+		return d.takePending()
+	}
+
+	if d.modes == nil {
+		d.modes = make(map[string]constraintMode)
+	}
+	for _, name := range names {
+		d.modes[string(name)] = mode
+	}
+	if deferred {
+		return nil
+	}
+	// Split the pending checks of the constraints that became immediate from
+	// the others.
+	var immediate []pendingConstraintCheck
+	remaining := d.pending[:0]
+	for _, c := range d.pending {
+		if d.isDeferred(c.check) {
+			remaining = append(remaining, c)
+		} else {
+			immediate = append(immediate, c)
+			delete(d.seen, c.key)
+		}
+	}
+	d.pending = remaining
+	return immediate
+}
+
+// emptyDeferredConstraints is the deferredConstraints implementation of
+// planners that are not bound to a session. Their constraints are never
+// deferred.
+type emptyDeferredConstraints struct{}
+
+var _ deferredConstraints = emptyDeferredConstraints{}
+
+func (emptyDeferredConstraints) isDeferred(*exec.DeferrableCheck) bool { return false }
+
+func (emptyDeferredConstraints) addViolation(*exec.DeferrableCheck, tree.Datums) {}
+
+func (emptyDeferredConstraints) setMode(tree.NameList, bool) []pendingConstraintCheck {
+	return nil
+}
+
+// checkDeferredConstraints checks again the possible violations of deferred
+// constraints, and returns an error for the first one which is still a
+// violation. Constraints which have been dropped in the meantime are ignored.
+// The internal executor must see the descriptors of the given collection.
+func checkDeferredConstraints(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	tc *descs.Collection,
+	checks []pendingConstraintCheck,
+) error {
+	for i := range checks {
+		c := &checks[i]
+		query, err := deferredCheckQuery(ctx, txn, tc, c)
+		if err != nil {
+			return err
+		}
+		if query == "" {
+			continue
+		}
+		args := make([]interface{}, len(c.keyVals))
+		for j := range c.keyVals {
+			args[j] = c.keyVals[j]
+		}
+		row, err := ie.QueryRowEx(
+			ctx, "deferred-constraint-check", txn,
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			query, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if row[0] == tree.DBoolTrue {
+			return c.check.MkErr(c.keyVals)
+		}
+	}
+	return nil
+}
+
+// deferredCheckQuery returns a query which returns true if the given check is
+// a violation of its constraint, or an empty string if the constraint does not
+// exist anymore. The key values are passed as placeholders.
+func deferredCheckQuery(
+	ctx context.Context, txn *kv.Txn, tc *descs.Collection, c *pendingConstraintCheck,
+) (string, error) {
+	desc, err := tc.GetImmutableTableByID(
+		ctx, txn, descpb.ID(c.check.TableID),
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{AvoidCached: true}},
+	)
+	if err != nil {
+		if sqlerrors.IsUndefinedRelationError(err) || errors.Is(err, catalog.ErrDescriptorDropped) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	// filter returns a conjunction of comparisons between the given columns and
+	// the placeholders.
+	filter := func(
+		desc catalog.TableDescriptor, alias string, colIDs []descpb.ColumnID, cmp string,
+	) (string, error) {
+		names, err := desc.NamesForColumnIDs(colIDs)
+		if err != nil {
+			return "", err
+		}
+		var buf strings.Builder
+		for i, name := range names {
+			if i > 0 {
+				buf.WriteString(" AND ")
+			}
+			fmt.Fprintf(&buf, "%s.%s %s $%d", alias, tree.NameString(name), cmp, i+1)
+		}
+		return buf.String(), nil
+	}
+
+	if !c.check.ForeignKey {
+		for _, uc := range desc.GetUniqueWithoutIndexConstraints() {
+			if uc.Name != c.check.Name {
+				continue
+			}
+			where, err := filter(desc, "t", uc.ColumnIDs, "=")
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf(
+				`SELECT count(*) > 1 FROM [%d AS t] WHERE %s`, desc.GetID(), where,
+			), nil
+		}
+		return "", nil
+	}
+
+	var fk *descpb.ForeignKeyConstraint
+	if err := desc.ForeachOutboundFK(func(c2 *descpb.ForeignKeyConstraint) error {
+		if c2.Name == c.check.Name {
+			fk = c2
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	if fk == nil {
+		return "", nil
+	}
+	hasNull := false
+	for _, d := range c.keyVals {
+		if d == tree.DNull {
+			hasNull = true
+		}
+	}
+	if hasNull {
+		// A MATCH FULL violation, which can only be fixed by removing or
+		// updating the referencing row.
+		where, err := filter(desc, "o", fk.OriginColumnIDs, "IS NOT DISTINCT FROM")
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(
+			`SELECT EXISTS(SELECT 1 FROM [%d AS o] WHERE %s)`, desc.GetID(), where,
+		), nil
+	}
+	refDesc, err := tc.GetImmutableTableByID(
+		ctx, txn, fk.ReferencedTableID,
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{AvoidCached: true}},
+	)
+	if err != nil {
+		return "", err
+	}
+	originWhere, err := filter(desc, "o", fk.OriginColumnIDs, "=")
+	if err != nil {
+		return "", err
+	}
+	refWhere, err := filter(refDesc, "r", fk.ReferencedColumnIDs, "=")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		`SELECT EXISTS(SELECT 1 FROM [%d AS o] WHERE %s) AND NOT EXISTS(SELECT 1 FROM [%d AS r] WHERE %s)`,
+		desc.GetID(), originWhere, refDesc.GetID(), refWhere,
+	), nil
+}
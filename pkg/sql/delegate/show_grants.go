@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 // delegateShowGrants implements SHOW GRANTS which returns grant details for the
@@ -111,6 +112,8 @@ FROM "".information_schema.type_privileges`
 				strings.Join(params, ","),
 			)
 		}
+	} else if n.Targets != nil && n.Targets.Functions != nil {
+		return nil, unimplemented.NewWithIssue(17511, "SHOW GRANTS ON FUNCTION")
 	} else if n.Targets != nil && len(n.Targets.Types) > 0 {
 		for _, typName := range n.Targets.Types {
 			t, err := d.catalog.ResolveType(d.ctx, typName)
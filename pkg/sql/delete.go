@@ -51,9 +51,15 @@ type deleteRun struct {
 
 	// partialIndexDelValsOffset is the offset of partial index delete
 	// indicators in the source values. It is equal to the number of fetched
-	// columns.
+	// columns plus the number of passthrough columns.
 	partialIndexDelValsOffset int
 
+	// numPassthrough is the number of columns that are passed through from the
+	// source to the result rows. These are the columns of the USING tables that
+	// are referenced in the RETURNING clause. They follow the fetched columns in
+	// the source values.
+	numPassthrough int
+
 	// rowIdxToRetIdx is the mapping from the columns returned by the deleter
 	// to the columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the column at that index is not part of the resultRowBuffer
@@ -168,6 +174,12 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 		sourceVals = sourceVals[:d.run.partialIndexDelValsOffset]
 	}
 
+	// Separate the passthrough values from the fetched values, which are the
+	// only ones passed to the deleter.
+	numFetchCols := len(sourceVals) - d.run.numPassthrough
+	passthroughValues := sourceVals[numFetchCols:]
+	sourceVals = sourceVals[:numFetchCols]
+
 	// Queue the deletion in the KV batch.
 	if err := d.run.td.row(params.ctx, sourceVals, pm, d.run.traceKV); err != nil {
 		return err
@@ -189,6 +201,11 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 			}
 		}
 
+		// At this point we've extracted all the RETURNING values that are part
+		// of the target table. We must now extract the columns in the RETURNING
+		// clause that refer to other tables (from the USING clause of the delete).
+		copy(resultValues[len(resultValues)-d.run.numPassthrough:], passthroughValues)
+
 		if _, err := d.run.td.rows.AddRow(params.ctx, resultValues); err != nil {
 			return err
 		}
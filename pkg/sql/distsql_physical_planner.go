@@ -1127,6 +1127,7 @@ func (dsp *DistSQLPlanner) convertOrdering(
 			dir = execinfrapb.Ordering_Column_DESC
 		}
 		result.Columns[i].Direction = dir
+		result.Columns[i].NullsReversed = o.NullsReversed
 	}
 	return result
 }
@@ -1790,6 +1791,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 			} else {
 				ordCols[i].Direction = execinfrapb.Ordering_Column_ASC
 			}
+			ordCols[i].NullsReversed = o.NullsReversed
 		}
 
 		localAggsSpec := execinfrapb.AggregatorSpec{
@@ -3548,6 +3550,11 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 	if err != nil {
 		return nil, err
 	}
+	sourceCols := planColumns(n.source)
+	colNames := make([]string, len(sourceCols))
+	for i := range sourceCols {
+		colNames[i] = sourceCols[i].Name
+	}
 	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
 		Destination:      n.destination,
 		NamePattern:      n.fileNamePattern,
@@ -3555,6 +3562,8 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		ChunkRows:        int64(n.chunkRows),
 		CompressionCodec: n.fileCompression,
 		UserProto:        planCtx.planner.User().EncodeProto(),
+		Format:           n.fileFormat,
+		ColumnNames:      colNames,
 	}}
 
 	resTypes := make([]*types.T, len(colinfo.ExportColumns))
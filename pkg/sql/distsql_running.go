@@ -1024,10 +1024,7 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 		}
 		cp := cascadePlan.(*planComponents)
 		plan.cascades[i].plan = cp.main
-		if len(cp.subqueryPlans) > 0 {
-			recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
-			return false
-		}
+		plan.cascades[i].subqueryPlans = cp.subqueryPlans
 
 		// Queue any new cascades.
 		if len(cp.cascades) > 0 {
@@ -1049,13 +1046,7 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			return false
 		}
 
-		if err := dsp.planAndRunPostquery(
-			ctx,
-			cp.main,
-			planner,
-			evalCtx,
-			recv,
-		); err != nil {
+		if err := dsp.planAndRunCascade(ctx, cp, planner, evalCtx, evalCtxFactory, recv); err != nil {
 			recv.SetError(err)
 			return false
 		}
@@ -1093,6 +1084,41 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	return true
 }
 
+// planAndRunCascade runs a cascade query, along with its subqueries. FK
+// cascades don't have subqueries, but the statements run by triggers can have
+// them (including the CTEs hoisted by BEFORE triggers).
+func (dsp *DistSQLPlanner) planAndRunCascade(
+	ctx context.Context,
+	cascade *planComponents,
+	planner *planner,
+	evalCtx *extendedEvalContext,
+	evalCtxFactory func() *extendedEvalContext,
+	recv *DistSQLReceiver,
+) error {
+	if len(cascade.subqueryPlans) == 0 {
+		return dsp.planAndRunPostquery(ctx, cascade.main, planner, evalCtx, recv)
+	}
+
+	// The subqueries are evaluated using the subquery plans of the current plan,
+	// so they are temporarily replaced with those of the cascade.
+	defer func(prevSubqueryPlans []subquery) {
+		planner.curPlan.subqueryPlans = prevSubqueryPlans
+	}(planner.curPlan.subqueryPlans)
+	planner.curPlan.subqueryPlans = cascade.subqueryPlans
+
+	subqueryRecv := recv.clone()
+	subqueryRecv.resultWriter = &errOnlyResultWriter{}
+	if !dsp.PlanAndRunSubqueries(
+		ctx, planner, evalCtxFactory, cascade.subqueryPlans, subqueryRecv,
+	) {
+		if subqueryRecv.commErr != nil {
+			return subqueryRecv.commErr
+		}
+		return subqueryRecv.resultWriter.Err()
+	}
+	return dsp.planAndRunPostquery(ctx, cascade.main, planner, evalCtx, recv)
+}
+
 // planAndRunPostquery runs a cascade or check query.
 func (dsp *DistSQLPlanner) planAndRunPostquery(
 	ctx context.Context,
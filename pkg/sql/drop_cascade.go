@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -34,6 +35,7 @@ type dropCascadeState struct {
 	td                      []toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []*funcdesc.Mutable
 
 	droppedNames []string
 }
@@ -132,6 +134,13 @@ func (d *dropCascadeState) resolveCollectedObjects(
 					return err
 				}
 			}
+			for _, ref := range tbDesc.DependedOnByFunctions {
+				if err := p.canRemoveDependentFunction(
+					ctx, tbDesc.TypeName(), tbDesc.Name, tbDesc.ParentID, ref, tree.DropCascade,
+				); err != nil {
+					return err
+				}
+			}
 			d.td = append(d.td, toDelete{objName, tbDesc})
 		} else {
 			// If we couldn't resolve objName as a table, try a type.
@@ -152,8 +161,49 @@ func (d *dropCascadeState) resolveCollectedObjects(
 			if err != nil {
 				return err
 			}
-			// If we couldn't find the object at all, then continue.
 			if !found {
+				// If we couldn't resolve objName as a type, try a function.
+				found, desc, err = p.LookupObject(
+					ctx,
+					tree.ObjectLookupFlags{
+						CommonLookupFlags: tree.CommonLookupFlags{
+							RequireMutable: true,
+							IncludeOffline: true,
+						},
+						DesiredObjectKind: tree.FunctionObject,
+					},
+					objName.Catalog(),
+					objName.Schema(),
+					objName.Object(),
+				)
+				if err != nil {
+					return err
+				}
+				// If we couldn't find the object at all, then continue.
+				if !found {
+					continue
+				}
+				fnDesc, ok := desc.(*funcdesc.Mutable)
+				if !ok {
+					return errors.AssertionFailedf(
+						"descriptor for %q is not Mutable",
+						objName.Object(),
+					)
+				}
+				if err := p.canModifyFunction(ctx, fnDesc); err != nil {
+					return err
+				}
+				// Check permissions on all dependent views, since some may be in
+				// different schemas or databases.
+				for _, id := range fnDesc.ReferencingDescriptorIDs {
+					if err := p.canRemoveDependentViewGeneric(
+						ctx, "function", fnDesc.Name, fnDesc.ParentID,
+						descpb.TableDescriptor_Reference{ID: id}, tree.DropCascade,
+					); err != nil {
+						return err
+					}
+				}
+				d.functionsToDelete = append(d.functionsToDelete, fnDesc)
 				continue
 			}
 			typDesc, ok := desc.(*typedesc.Mutable)
@@ -201,7 +251,20 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		d.droppedNames = append(d.droppedNames, toDel.tn.FQString())
 	}
 
-	// Now delete all of the types.
+	// Now drop the functions, along with any views in other schemas or
+	// databases which call them. The functions are dropped before the types,
+	// so that they can remove their references to the types. Functions whose
+	// bodies refer to the dropped tables have been dropped already.
+	for _, fn := range d.functionsToDelete {
+		if fn.Dropped() {
+			continue
+		}
+		if err := p.dropFunctionImpl(ctx, fn, "", tree.DropCascade); err != nil {
+			return err
+		}
+	}
+
+	// Finally, delete all of the types.
 	for _, typ := range d.typesToDelete {
 		// Drop the types. Note that we set queueJob to be false because the types
 		// will be dropped in bulk as part of the DROP DATABASE job.
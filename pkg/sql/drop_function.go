@@ -0,0 +1,179 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n  *tree.DropFunction
+	fd []*funcdesc.Mutable
+}
+
+// DropFunction drops user-defined functions.
+// Privileges: ownership of the function.
+//   Notes: postgres requires ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropFunctionNode{n: n}
+	seen := make(map[descpb.ID]struct{}, len(n.Functions))
+	for i := range n.Functions {
+		fo := &n.Functions[i]
+		fn, desc, err := p.ResolveMutableFunctionDescriptor(ctx, fo.FuncName, !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			// IfExists specified and the function did not exist.
+			continue
+		}
+		if fo.Params != nil {
+			matches, err := p.functionParamsMatch(ctx, desc, fo.Params)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				if n.IfExists {
+					continue
+				}
+				return nil, pgerror.Newf(pgcode.UndefinedFunction,
+					"function %s does not exist", tree.AsString(fo))
+			}
+		}
+		if _, ok := seen[desc.ID]; ok {
+			continue
+		}
+		seen[desc.ID] = struct{}{}
+
+		if err := p.canModifyFunction(ctx, desc); err != nil {
+			return nil, err
+		}
+		// Check that dependent views can be dropped before making any changes.
+		for _, id := range desc.ReferencingDescriptorIDs {
+			if err := p.canRemoveDependentViewGeneric(
+				ctx, "function", fn.Object(), desc.ParentID,
+				descpb.TableDescriptor_Reference{ID: id}, n.DropBehavior,
+			); err != nil {
+				return nil, err
+			}
+		}
+		node.fd = append(node.fd, desc)
+	}
+	if len(node.fd) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+// functionParamsMatch returns whether the types of the parameters of the
+// function match the given parameter list. The parameter names are ignored,
+// as in Postgres.
+func (p *planner) functionParamsMatch(
+	ctx context.Context, desc *funcdesc.Mutable, params tree.FuncParams,
+) (bool, error) {
+	if len(desc.Params) != len(params) {
+		return false, nil
+	}
+	for i := range params {
+		typ, err := tree.ResolveType(ctx, params[i].Type, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return false, err
+		}
+		if !desc.Params[i].Type.Identical(typ) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropFunctionNode) ReadingOwnWrites() {}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+
+	for _, desc := range n.fd {
+		if err := params.p.dropFunctionImpl(
+			params.ctx, desc, tree.AsStringWithFQNames(n.n, params.Ann()), n.n.DropBehavior,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
+
+// dropFunctionImpl does the work of dropping a function and, if the drop
+// behavior is CASCADE, the views which call it.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string, behavior tree.DropBehavior,
+) error {
+	if desc.Dropped() {
+		return errors.Errorf("function %q is already being dropped", desc.Name)
+	}
+
+	// Remove the back-references from the relations and types which the body
+	// of the function refers to.
+	if err := p.removeFunctionDependencies(ctx, desc); err != nil {
+		return err
+	}
+
+	// Mark the function as dropped before dropping the dependent views, so
+	// that they leave the function's back-references alone.
+	desc.SetDropped()
+	desc.AddDrainingName(descpb.NameInfo{
+		ParentID:       desc.ParentID,
+		ParentSchemaID: desc.ParentSchemaID,
+		Name:           desc.Name,
+	})
+	if err := p.writeFunctionDescChange(ctx, desc, jobDesc); err != nil {
+		return err
+	}
+
+	dependents := append([]descpb.ID(nil), desc.ReferencingDescriptorIDs...)
+	for _, id := range dependents {
+		viewDesc, err := p.getViewDescForCascade(ctx, "function", desc.Name, desc.ParentID, id, behavior)
+		if err != nil {
+			return err
+		}
+		if viewDesc.Dropped() {
+			continue
+		}
+		if _, err := p.dropViewImpl(
+			ctx, viewDesc, true /* queueJob */, "dropping dependent view", behavior,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -56,6 +56,13 @@ func (p *planner) DropSequence(ctx context.Context, n *tree.DropSequence) (planN
 		if depErr := p.sequenceDependencyError(ctx, droppedDesc); depErr != nil {
 			return nil, depErr
 		}
+		for _, ref := range droppedDesc.DependedOnByFunctions {
+			if err := p.canRemoveDependentFunction(
+				ctx, droppedDesc.TypeName(), droppedDesc.Name, droppedDesc.ParentID, ref, n.DropBehavior,
+			); err != nil {
+				return nil, err
+			}
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
@@ -115,6 +122,9 @@ func (p *planner) dropSequenceImpl(
 	if err := removeSequenceOwnerIfExists(ctx, p, seqDesc.ID, seqDesc.GetSequenceOpts()); err != nil {
 		return err
 	}
+	if err := p.dropDependentFunctions(ctx, seqDesc, behavior); err != nil {
+		return err
+	}
 	return p.initiateDropTable(ctx, seqDesc, queueJob, jobDesc, true /* drainName */)
 }
 
@@ -101,6 +101,13 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 				}
 			}
 		}
+		for _, ref := range droppedDesc.DependedOnByFunctions {
+			if err := p.canRemoveDependentFunction(
+				ctx, droppedDesc.TypeName(), droppedDesc.Name, droppedDesc.ParentID, ref, n.DropBehavior,
+			); err != nil {
+				return nil, err
+			}
+		}
 		if err := p.canRemoveAllTableOwnedSequences(ctx, droppedDesc, n.DropBehavior); err != nil {
 			return nil, err
 		}
@@ -352,6 +359,12 @@ func (p *planner) dropTableImpl(
 		droppedViews = append(droppedViews, viewDesc.Name)
 	}
 
+	// Drop all functions whose bodies refer to this table, again assuming that
+	// `cascade` was enabled.
+	if err := p.dropDependentFunctions(ctx, tableDesc, tree.DropCascade); err != nil {
+		return droppedViews, err
+	}
+
 	err := p.removeTableComments(ctx, tableDesc)
 	if err != nil {
 		return droppedViews, err
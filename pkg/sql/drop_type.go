@@ -135,13 +135,15 @@ func (p *planner) canDropTypeDesc(
 	if len(desc.ReferencingDescriptorIDs) > 0 && behavior != tree.DropCascade {
 		var dependentNames []string
 		for _, id := range desc.ReferencingDescriptorIDs {
-			desc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+			// The referencing descriptors are tables, or functions whose bodies
+			// refer to the type.
+			desc, err := p.Descriptors().GetMutableDescriptorByID(ctx, id, p.txn)
 			if err != nil {
 				return errors.Wrapf(err, "type has dependent objects")
 			}
 			fqName, err := p.getQualifiedTableName(ctx, desc)
 			if err != nil {
-				return errors.Wrapf(err, "type %q has dependent objects", desc.Name)
+				return errors.Wrapf(err, "type %q has dependent objects", desc.GetName())
 			}
 			dependentNames = append(dependentNames, fqName.FQString())
 		}
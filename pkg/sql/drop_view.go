@@ -77,6 +77,13 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 				return nil, err
 			}
 		}
+		for _, ref := range droppedDesc.DependedOnByFunctions {
+			if err := p.canRemoveDependentFunction(
+				ctx, droppedDesc.TypeName(), droppedDesc.Name, droppedDesc.ParentID, ref, n.DropBehavior,
+			); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if len(td) == 0 {
@@ -157,12 +164,20 @@ func (p *planner) canRemoveDependentViewGeneric(
 	if err := p.CheckPrivilege(ctx, viewDesc, privilege.DROP); err != nil {
 		return err
 	}
-	// If this view is depended on by other views, we have to check them as well.
+	// If this view is depended on by other views or functions, we have to
+	// check them as well.
 	for _, ref := range viewDesc.DependedOnBy {
 		if err := p.canRemoveDependentView(ctx, viewDesc, ref, behavior); err != nil {
 			return err
 		}
 	}
+	for _, ref := range viewDesc.DependedOnByFunctions {
+		if err := p.canRemoveDependentFunction(
+			ctx, viewDesc.TypeName(), viewDesc.Name, viewDesc.ParentID, ref, behavior,
+		); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -215,6 +230,17 @@ func (p *planner) dropViewImpl(
 	}
 	viewDesc.DependsOn = nil
 
+	// Remove back-references from the functions this view calls.
+	for _, funcID := range viewDesc.DependsOnFunctions {
+		if err := p.removeFunctionBackReference(
+			ctx, funcID, viewDesc.ID,
+			fmt.Sprintf("removing references for view %s from function %d", viewDesc.Name, funcID),
+		); err != nil {
+			return cascadeDroppedViews, err
+		}
+	}
+	viewDesc.DependsOnFunctions = nil
+
 	if behavior == tree.DropCascade {
 		dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), viewDesc.DependedOnBy...)
 		for _, ref := range dependedOnBy {
@@ -232,6 +258,9 @@ func (p *planner) dropViewImpl(
 			cascadeDroppedViews = append(cascadeDroppedViews, dependentDesc.Name)
 		}
 	}
+	if err := p.dropDependentFunctions(ctx, viewDesc, behavior); err != nil {
+		return cascadeDroppedViews, err
+	}
 
 	// Remove any references to types that this view has.
 	if err := p.removeBackRefsFromAllTypesInTable(ctx, viewDesc); err != nil {
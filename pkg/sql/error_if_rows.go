@@ -26,6 +26,11 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// deferrable is set if the node checks a deferrable constraint. If the
+	// constraint is deferred, the rows are recorded as possible violations
+	// instead of causing an error.
+	deferrable *exec.DeferrableCheck
+
 	nexted bool
 }
 
@@ -39,6 +44,18 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
+	if n.deferrable != nil && params.p.deferredConstraints.isDeferred(n.deferrable) {
+		for {
+			ok, err := n.plan.Next(params)
+			if err != nil || !ok {
+				return false, err
+			}
+			params.p.deferredConstraints.addViolation(
+				n.deferrable, n.deferrable.KeyVals(n.plan.Values()),
+			)
+		}
+	}
+
 	ok, err := n.plan.Next(params)
 	if err != nil {
 		return false, err
@@ -135,6 +135,10 @@ type ServerConfig struct {
 	ExternalStorage        cloud.ExternalStorageFactory
 	ExternalStorageFromURI cloud.ExternalStorageFromURIFactory
 
+	// ExternalIODirConfig restricts the external storage and sinks that
+	// processors may use.
+	ExternalIODirConfig base.ExternalIODirConfig
+
 	// ProtectedTimestampProvider maintains the state of the protected timestamp
 	// subsystem. It is queried during the GC process and in the handling of
 	// AdminVerifyProtectedTimestampRequest.
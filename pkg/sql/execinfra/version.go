@@ -39,7 +39,7 @@ import "github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 //
 // ATTENTION: When updating these fields, add a brief description of what
 // changed to the version history below.
-const Version execinfrapb.DistSQLVersion = 44
+const Version execinfrapb.DistSQLVersion = 45
 
 // MinAcceptedVersion is the oldest version that the server is compatible with.
 // A server will not accept flows with older versions.
@@ -51,6 +51,11 @@ const MinAcceptedVersion execinfrapb.DistSQLVersion = 44
 
 Please add new entries at the top.
 
+- Version: 45 (MinAcceptedVersion: 44)
+  - Format and ColumnNames fields were added to CSVWriterSpec to support
+    exporting to Parquet. The change is backwards compatible (mixed versions
+    will prevent parallelization).
+
 - Version: 44 (MinAcceptedVersion: 44)
   - Changes to the component statistics proto.
 
@@ -34,6 +34,7 @@ func ConvertToColumnOrdering(specOrdering Ordering) colinfo.ColumnOrdering {
 		} else {
 			ordering[i].Direction = encoding.Descending
 		}
+		ordering[i].NullsReversed = c.NullsReversed
 	}
 	return ordering
 }
@@ -66,6 +67,7 @@ func ConvertToMappedSpecOrdering(
 		} else {
 			specOrdering.Columns[i].Direction = Ordering_Column_DESC
 		}
+		specOrdering.Columns[i].NullsReversed = c.NullsReversed
 	}
 	return specOrdering
 }
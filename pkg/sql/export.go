@@ -40,6 +40,8 @@ type exportNode struct {
 	// fileNamePattern represents the file naming pattern for the
 	// export, typically to be appended to the destination URI
 	fileNamePattern string
+	// fileFormat is the format of the exported files.
+	fileFormat      roachpb.IOFileFormat_FileFormat
 	csvOpts         roachpb.CSVOptions
 	chunkRows       int
 	fileCompression execinfrapb.FileCompression
@@ -77,11 +79,20 @@ var exportOptionExpectValues = map[string]KVStringOptValidate{
 	exportOptionCompression: KVStringOptRequireValue,
 }
 
+// exportCSVOnlyOptions are the options which only apply to CSV exports.
+var exportCSVOnlyOptions = []string{exportOptionDelimiter, exportOptionNullAs}
+
 const exportChunkRowsDefault = 100000
 const exportFilePatternPart = "%part%"
-const exportFilePatternDefault = exportFilePatternPart + ".csv"
 const exportCompressionCodec = "gzip"
 
+// exportFileExtensions are the extensions of the files written by each
+// supported export format.
+var exportFileExtensions = map[string]string{
+	"CSV":     ".csv",
+	"PARQUET": ".parquet",
+}
+
 // featureExportEnabled is used to enable and disable the EXPORT feature.
 var featureExportEnabled = settings.RegisterBoolSetting(
 	"feature.export.enabled",
@@ -109,13 +120,20 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
-	if !ef.planner.ExtendedEvalContext().TxnImplicit {
+	// EXPLAIN does not run the export, so it is allowed in a transaction. This
+	// lets scheduled exports be validated in the transaction creating them.
+	if !ef.planner.ExtendedEvalContext().TxnImplicit && !ef.isExplain {
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	fileExtension, ok := exportFileExtensions[fileFormat]
+	if !ok {
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
+	format := roachpb.IOFileFormat_CSV
+	if fileFormat == "PARQUET" {
+		format = roachpb.IOFileFormat_Parquet
+	}
 
 	destinationDatum, err := fileName.Eval(ef.planner.EvalContext())
 	if err != nil {
@@ -147,6 +165,15 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
+	if format != roachpb.IOFileFormat_CSV {
+		for _, opt := range exportCSVOnlyOptions {
+			if _, ok := optVals[opt]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s option is not supported for %s exports", opt, fileFormat)
+			}
+		}
+	}
+
 	csvOpts := roachpb.CSVOptions{}
 
 	if override, ok := optVals[exportOptionDelimiter]; ok {
@@ -183,13 +210,28 @@ func (ef *execFactory) ConstructExport(
 		}
 	}
 
-	exportID := ef.planner.stmt.QueryID.String()
-	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePatternDefault)
+	// The filename option names the exported files, which are otherwise named
+	// after the query. Unless it says where to put the part of the export
+	// that each file holds, the part is appended to it.
+	var namePattern string
+	if name, ok := optVals[exportOptionFileName]; ok {
+		if name == "" || strings.Contains(name, "/") {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue, "invalid filename %q", name)
+		}
+		namePattern = name
+		if !strings.Contains(name, exportFilePatternPart) {
+			namePattern = fmt.Sprintf("%s-%s%s", name, exportFilePatternPart, fileExtension)
+		}
+	} else {
+		exportID := ef.planner.stmt.QueryID.String()
+		namePattern = fmt.Sprintf("export%s-%s%s", exportID, exportFilePatternPart, fileExtension)
+	}
 
 	return &exportNode{
 		source:          input.(planNode),
 		destination:     string(*destination),
 		fileNamePattern: namePattern,
+		fileFormat:      format,
 		csvOpts:         csvOpts,
 		chunkRows:       chunkRows,
 		fileCompression: codec,
@@ -214,7 +214,7 @@ func (ep *DummySessionAccessor) GetSessionVar(
 }
 
 // SetSessionVar is part of the tree.EvalSessionAccessor interface.
-func (ep *DummySessionAccessor) SetSessionVar(_ context.Context, _, _ string) error {
+func (ep *DummySessionAccessor) SetSessionVar(_ context.Context, _, _ string, _ bool) error {
 	return errors.WithStack(errEvalSessionVar)
 }
 
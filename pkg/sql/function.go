@@ -0,0 +1,334 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+func (p *planner) writeFunctionDesc(ctx context.Context, desc *funcdesc.Mutable) error {
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// writeFunctionDescChange writes the function descriptor and queues a job
+// which waits for the new version to propagate, drains any old names and
+// deletes the descriptor if it was dropped.
+func (p *planner) writeFunctionDescChange(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	job, jobExists := p.extendedEvalCtx.SchemaChangeJobCache[desc.ID]
+	if jobExists {
+		// Update it.
+		if err := job.WithTxn(p.txn).SetDescription(ctx,
+			func(ctx context.Context, desc string) (string, error) {
+				return desc + "; " + jobDesc, nil
+			},
+		); err != nil {
+			return err
+		}
+		log.Infof(ctx, "job %d: updated with change on function %d", *job.ID(), desc.ID)
+	} else {
+		// Or, create a new job.
+		jobRecord := jobs.Record{
+			Description:   jobDesc,
+			Username:      p.User(),
+			DescriptorIDs: descpb.IDs{desc.ID},
+			Details: jobspb.SchemaChangeDetails{
+				DescID: desc.ID,
+				// The version distinction for database jobs doesn't matter for
+				// function jobs.
+				FormatVersion: jobspb.DatabaseJobFormatVersion,
+			},
+			Progress: jobspb.SchemaChangeProgress{},
+		}
+		newJob, err := p.extendedEvalCtx.QueueJob(jobRecord)
+		if err != nil {
+			return err
+		}
+		p.extendedEvalCtx.SchemaChangeJobCache[desc.ID] = newJob
+		log.Infof(ctx, "queued new schema change job %d for function %d", *newJob.ID(), desc.ID)
+	}
+
+	return p.writeFunctionDesc(ctx, desc)
+}
+
+// addFunctionBackReference records that the descriptor ref depends on the
+// function funcID. The user must have the EXECUTE privilege on the function.
+func (p *planner) addFunctionBackReference(
+	ctx context.Context, funcID, ref descpb.ID, jobDesc string,
+) error {
+	mutDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, funcID, tree.ObjectLookupFlags{})
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(ctx, mutDesc, privilege.EXECUTE); err != nil {
+		return err
+	}
+	mutDesc.AddReferencingDescriptorID(ref)
+	return p.writeFunctionDescChange(ctx, mutDesc, jobDesc)
+}
+
+// removeFunctionBackReference removes the record that the descriptor ref
+// depends on the function funcID. Functions which are being dropped are left
+// untouched.
+func (p *planner) removeFunctionBackReference(
+	ctx context.Context, funcID, ref descpb.ID, jobDesc string,
+) error {
+	mutDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, funcID,
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{IncludeDropped: true}})
+	if err != nil {
+		return err
+	}
+	if mutDesc.Dropped() {
+		return nil
+	}
+	mutDesc.RemoveReferencingDescriptorID(ref)
+	return p.writeFunctionDescChange(ctx, mutDesc, jobDesc)
+}
+
+// canModifyFunction returns an error if the current user may not replace or
+// drop the function. Only the owner of a function and admins may do so.
+func (p *planner) canModifyFunction(ctx context.Context, desc *funcdesc.Mutable) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of function %s", tree.Name(desc.GetName()))
+	}
+	return nil
+}
+
+// addFunctionDependencies records the relations and the types which the body
+// of the function depends on, and installs back-references to the function
+// in their descriptors.
+func (p *planner) addFunctionDependencies(
+	ctx context.Context, desc *funcdesc.Mutable, planDeps planDependencies, typeIDs descpb.IDs,
+) error {
+	desc.DependsOn = make([]descpb.ID, 0, len(planDeps))
+	for id, updated := range planDeps {
+		backRefMutable, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		if backRefMutable.Temporary {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"function %q cannot refer to temporary %s %q",
+				desc.Name, backRefMutable.TypeName(), backRefMutable.Name)
+		}
+		backRefMutable.DependedOnByFunctions = removeMatchingReferences(
+			backRefMutable.DependedOnByFunctions, desc.ID,
+		)
+		for _, dep := range updated.deps {
+			// The ID of the function was not known when the dependencies were
+			// collected.
+			dep.ID = desc.ID
+			backRefMutable.DependedOnByFunctions = append(backRefMutable.DependedOnByFunctions, dep)
+		}
+		if err := p.writeSchemaChange(
+			ctx, backRefMutable, descpb.InvalidMutationID,
+			fmt.Sprintf("updating function reference %q in table %s(%d)",
+				desc.Name, backRefMutable.Name, backRefMutable.ID),
+		); err != nil {
+			return err
+		}
+		desc.DependsOn = append(desc.DependsOn, id)
+	}
+	sort.Sort(descpb.IDs(desc.DependsOn))
+
+	desc.DependsOnTypes = typeIDs
+	for _, id := range typeIDs {
+		jobDesc := fmt.Sprintf("updating type back reference %d for function %d", id, desc.ID)
+		if err := p.addTypeBackReference(ctx, id, desc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFunctionDependencies removes the back-references to the function from
+// the relations and the types which its body depends on. Relations which are
+// being dropped are left untouched.
+func (p *planner) removeFunctionDependencies(ctx context.Context, desc *funcdesc.Mutable) error {
+	for _, id := range desc.DependsOn {
+		backRefMutable, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return errors.Wrapf(err, "error resolving dependency relation ID %d", id)
+		}
+		if backRefMutable.Dropped() {
+			continue
+		}
+		backRefMutable.DependedOnByFunctions = removeMatchingReferences(
+			backRefMutable.DependedOnByFunctions, desc.ID,
+		)
+		if err := p.writeSchemaChange(
+			ctx, backRefMutable, descpb.InvalidMutationID,
+			fmt.Sprintf("removing references for function %s from table %s(%d)",
+				desc.Name, backRefMutable.Name, backRefMutable.ID),
+		); err != nil {
+			return err
+		}
+	}
+	desc.DependsOn = nil
+
+	for _, id := range desc.DependsOnTypes {
+		jobDesc := fmt.Sprintf("removing type back reference %d for function %d", id, desc.ID)
+		if err := p.removeTypeBackReference(ctx, id, desc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+	desc.DependsOnTypes = nil
+	return nil
+}
+
+// canRemoveDependentFunction checks that the function whose body refers to the
+// object being dropped can be dropped along with it.
+func (p *planner) canRemoveDependentFunction(
+	ctx context.Context,
+	typeName string,
+	objName string,
+	parentID descpb.ID,
+	ref descpb.TableDescriptor_Reference,
+	behavior tree.DropBehavior,
+) error {
+	fnDesc, err := p.getFunctionDescForCascade(ctx, typeName, objName, parentID, ref.ID, behavior)
+	if err != nil {
+		return err
+	}
+	if err := p.canModifyFunction(ctx, fnDesc); err != nil {
+		return err
+	}
+	// The views which call the function are dropped along with it.
+	for _, id := range fnDesc.ReferencingDescriptorIDs {
+		if err := p.canRemoveDependentViewGeneric(
+			ctx, "function", fnDesc.Name, fnDesc.ParentID,
+			descpb.TableDescriptor_Reference{ID: id}, behavior,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropDependentFunctions drops the functions whose bodies refer to the table,
+// view or sequence which is being dropped, along with the views which call
+// them. It returns an error if there are such functions and the drop behavior
+// is not CASCADE.
+func (p *planner) dropDependentFunctions(
+	ctx context.Context, tableDesc *tabledesc.Mutable, behavior tree.DropBehavior,
+) error {
+	// Copy out the references, since dropping a function removes them.
+	dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), tableDesc.DependedOnByFunctions...)
+	for _, ref := range dependedOnBy {
+		fnDesc, err := p.getFunctionDescForCascade(
+			ctx, tableDesc.TypeName(), tableDesc.Name, tableDesc.ParentID, ref.ID, behavior,
+		)
+		if err != nil {
+			return err
+		}
+		if fnDesc.Dropped() {
+			continue
+		}
+		if err := p.dropFunctionImpl(ctx, fnDesc, "dropping dependent function", tree.DropCascade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getFunctionDescForCascade returns the descriptor of the function funcID,
+// whose body refers to the object being dropped. It returns an error if the
+// drop behavior is not CASCADE.
+func (p *planner) getFunctionDescForCascade(
+	ctx context.Context,
+	typeName string,
+	objName string,
+	parentID, funcID descpb.ID,
+	behavior tree.DropBehavior,
+) (*funcdesc.Mutable, error) {
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, funcID,
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{IncludeDropped: true}})
+	if err != nil {
+		log.Warningf(ctx, "unable to retrieve descriptor for function %d: %v", funcID, err)
+		return nil, errors.Wrapf(err, "error resolving dependent function ID %d", funcID)
+	}
+	if behavior != tree.DropCascade {
+		return nil, p.dependentFunctionError(ctx, typeName, objName, parentID, fnDesc, "drop")
+	}
+	return fnDesc, nil
+}
+
+// dependentFunctionError returns the error for an operation which cannot be
+// performed on an object because the body of the function fnDesc refers to
+// it. Since function bodies are stored as strings which name the objects they
+// refer to, such objects cannot be renamed either.
+func (p *planner) dependentFunctionError(
+	ctx context.Context, typeName, objName string, parentID descpb.ID, fnDesc *funcdesc.Mutable, op string,
+) error {
+	fnName := fnDesc.Name
+	if fnDesc.ParentID != parentID {
+		fnFQName, err := p.getQualifiedTableName(ctx, fnDesc)
+		if err != nil {
+			log.Warningf(ctx, "unable to retrieve name of function %d: %v", fnDesc.ID, err)
+			return sqlerrors.NewDependentObjectErrorf(
+				"cannot %s %s %q because a function depends on it",
+				op, typeName, objName)
+		}
+		fnName = fnFQName.FQString()
+	}
+	return errors.WithHintf(
+		sqlerrors.NewDependentObjectErrorf("cannot %s %s %q because function %q depends on it",
+			op, typeName, objName, fnName),
+		"you can drop %s instead.", fnName)
+}
+
+// dependentFunctionErrorByID is like dependentFunctionError, for the function
+// with the given ID.
+func (p *planner) dependentFunctionErrorByID(
+	ctx context.Context, typeName, objName string, parentID, funcID descpb.ID, op string,
+) error {
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, funcID,
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{IncludeDropped: true}})
+	if err != nil {
+		return err
+	}
+	return p.dependentFunctionError(ctx, typeName, objName, parentID, fnDesc, op)
+}
@@ -1272,7 +1272,7 @@ CREATE TABLE information_schema.sequences (
 					tree.NewDString(strconv.FormatInt(table.GetSequenceOpts().MinValue, 10)),  // min value
 					tree.NewDString(strconv.FormatInt(table.GetSequenceOpts().MaxValue, 10)),  // max value
 					tree.NewDString(strconv.FormatInt(table.GetSequenceOpts().Increment, 10)), // increment
-					noString, // cycle
+					yesOrNoDatum(table.GetSequenceOpts().Cycle),                               // cycle
 				)
 			})
 	},
@@ -1429,6 +1429,17 @@ CREATE TABLE information_schema.table_constraints (
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					// Exclusion constraints are not part of the SQL standard, so Postgres
+					// does not list them here either.
+					if c.Kind == descpb.ConstraintTypeExclusion {
+						continue
+					}
+					var deferrable, initiallyDeferred bool
+					if c.FK != nil {
+						deferrable, initiallyDeferred = c.FK.Deferrable, c.FK.InitiallyDeferred
+					} else if uc := c.UniqueWithoutIndexConstraint; uc != nil {
+						deferrable, initiallyDeferred = uc.Deferrable, uc.InitiallyDeferred
+					}
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1437,8 +1448,8 @@ CREATE TABLE information_schema.table_constraints (
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(deferrable),        // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
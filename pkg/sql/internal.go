@@ -111,10 +111,14 @@ func (ie *InternalExecutor) SetSessionData(sessionData *sessiondata.SessionData)
 // If txn is not nil, the statement will be executed in the respective txn.
 //
 // sd will constitute the executor's session state.
+//
+// If stream is not nil, the rows of the statement results are handed over to
+// it instead of being buffered.
 func (ie *InternalExecutor) initConnEx(
 	ctx context.Context,
 	txn *kv.Txn,
 	sd *sessiondata.SessionData,
+	stream *ieRowStream,
 	syncCallback func([]resWithPos),
 	errCallback func(error),
 ) (*StmtBuf, *sync.WaitGroup, error) {
@@ -122,6 +126,7 @@ func (ie *InternalExecutor) initConnEx(
 		sync: syncCallback,
 		// init lastDelivered below the position of the first result (0).
 		lastDelivered: -1,
+		stream:        stream,
 	}
 
 	// When the connEx is serving an internal executor, it can inherit the
@@ -232,6 +237,64 @@ func (ie *InternalExecutor) QueryWithCols(
 	return ie.queryInternal(ctx, opName, txn, session, stmt, qargs...)
 }
 
+// QueryIteratorEx executes the supplied SQL statement and returns an iterator
+// over the resulting rows. Unlike QueryEx, the rows are not buffered: the
+// statement's execution only progresses as rows are requested through the
+// iterator. The returned iterator must be closed.
+//
+// If txn is not nil, the statement will be executed in the respective txn. The
+// txn must not be used by the caller while the iterator is advanced.
+func (ie *InternalExecutor) QueryIteratorEx(
+	ctx context.Context,
+	opName string,
+	txn *kv.Txn,
+	session sessiondata.InternalExecutorOverride,
+	stmt string,
+	qargs ...interface{},
+) (_ *rowsIterator, retErr error) {
+	ctx = logtags.AddTag(ctx, "intExec", opName)
+	defer func() {
+		if retErr != nil && !errIsRetriable(retErr) {
+			retErr = errors.Wrapf(retErr, "%s", opName)
+		}
+	}()
+
+	resPos := internalResultPos(qargs)
+	stream := newIERowStream()
+	syncCallback := func(results []resWithPos) {
+		for _, res := range results {
+			if res.pos == resPos || res.err != nil {
+				stream.finish(res.Err())
+				return
+			}
+		}
+		stream.finish(errors.AssertionFailedf("missing result for pos: %d and no previous error", resPos))
+	}
+	errCallback := func(err error) {
+		stream.finish(err)
+	}
+	stmtBuf, wg, err := ie.startInternal(
+		ctx, opName, txn, session, stream, syncCallback, errCallback, stmt, qargs...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	it := &rowsIterator{stream: stream, stmtBuf: stmtBuf, wg: wg}
+
+	// Wait for the statement to be planned. The first item carries either the
+	// result columns or the error that prevented the execution.
+	item := <-stream.dataCh
+	if item.done {
+		it.finish()
+		if item.err != nil {
+			return nil, item.err
+		}
+		return it, nil
+	}
+	it.cols = item.cols
+	return it, nil
+}
+
 func (ie *InternalExecutor) queryInternal(
 	ctx context.Context,
 	opName string,
@@ -378,22 +441,6 @@ func (ie *InternalExecutor) execInternal(
 ) (retRes result, retErr error) {
 	ctx = logtags.AddTag(ctx, "intExec", opName)
 
-	var sd *sessiondata.SessionData
-	if ie.sessionData != nil {
-		// TODO(andrei): Properly clone (deep copy) ie.sessionData.
-		sdCopy := *ie.sessionData
-		sd = &sdCopy
-	} else {
-		sd = ie.s.newSessionData(SessionArgs{})
-	}
-	applyOverrides(sessionDataOverride, sd)
-	if sd.User().Undefined() {
-		return result{}, errors.AssertionFailedf("no user specified for internal query")
-	}
-	if sd.ApplicationName == "" {
-		sd.ApplicationName = catconstants.InternalAppNamePrefix + "-" + opName
-	}
-
 	defer func() {
 		// We wrap errors with the opName, but not if they're retriable - in that
 		// case we need to leave the error intact so that it can be retried at a
@@ -412,17 +459,9 @@ func (ie *InternalExecutor) execInternal(
 	ctx, sp := tracing.EnsureChildSpan(ctx, ie.s.cfg.AmbientCtx.Tracer, opName)
 	defer sp.Finish()
 
-	timeReceived := timeutil.Now()
-	parseStart := timeReceived
-	parsed, err := parser.ParseOne(stmt)
-	if err != nil {
-		return result{}, err
-	}
-	parseEnd := timeutil.Now()
-
-	// resPos will be set to the position of the command that represents the
-	// statement we care about before that command is sent for execution.
-	var resPos CmdPos
+	// resPos is the position of the command that represents the statement we
+	// care about.
+	resPos := internalResultPos(qargs)
 
 	resCh := make(chan result)
 	var resultsReceived bool
@@ -448,16 +487,85 @@ func (ie *InternalExecutor) execInternal(
 		}
 		resCh <- result{err: err}
 	}
-	stmtBuf, wg, err := ie.initConnEx(ctx, txn, sd, syncCallback, errCallback)
+	stmtBuf, wg, err := ie.startInternal(
+		ctx, opName, txn, sessionDataOverride, nil /* stream */, syncCallback, errCallback, stmt, qargs...,
+	)
 	if err != nil {
 		return result{}, err
 	}
 
+	res := <-resCh
+	stmtBuf.Close()
+	wg.Wait()
+	return res, nil
+}
+
+// internalResultPos returns the position of the command whose result is
+// returned when executing a statement with the given arguments: statements
+// without arguments are executed directly, while the others are prepared,
+// bound and then executed.
+func internalResultPos(qargs []interface{}) CmdPos {
+	if len(qargs) == 0 {
+		return 0
+	}
+	return 2
+}
+
+// startInternal starts the execution of a statement on a new connExecutor. The
+// results are delivered to syncCallback, or errCallback if the connExecutor
+// fails. The returned StmtBuf must be closed and the WaitGroup waited on once
+// the results have been received.
+func (ie *InternalExecutor) startInternal(
+	ctx context.Context,
+	opName string,
+	txn *kv.Txn,
+	sessionDataOverride sessiondata.InternalExecutorOverride,
+	stream *ieRowStream,
+	syncCallback func([]resWithPos),
+	errCallback func(error),
+	stmt string,
+	qargs ...interface{},
+) (*StmtBuf, *sync.WaitGroup, error) {
+	var sd *sessiondata.SessionData
+	if ie.sessionData != nil {
+		// TODO(andrei): Properly clone (deep copy) ie.sessionData.
+		sdCopy := *ie.sessionData
+		sd = &sdCopy
+	} else {
+		sd = ie.s.newSessionData(SessionArgs{})
+	}
+	applyOverrides(sessionDataOverride, sd)
+	if sd.User().Undefined() {
+		return nil, nil, errors.AssertionFailedf("no user specified for internal query")
+	}
+	if sd.ApplicationName == "" {
+		sd.ApplicationName = catconstants.InternalAppNamePrefix + "-" + opName
+	}
+	if stream != nil {
+		// The rows of a streamed statement are produced while the caller keeps
+		// using the txn in between, so the statement must not spread out to
+		// leaf txns on other nodes.
+		sd.DistSQLMode = sessiondata.DistSQLOff
+	}
+
+	timeReceived := timeutil.Now()
+	parseStart := timeReceived
+	parsed, err := parser.ParseOne(stmt)
+	if err != nil {
+		return nil, nil, err
+	}
+	parseEnd := timeutil.Now()
+
+	stmtBuf, wg, err := ie.initConnEx(ctx, txn, sd, stream, syncCallback, errCallback)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Transforms the args to datums. The datum types will be passed as type hints
 	// to the PrepareStmt command.
 	datums, err := golangFillQueryArguments(qargs...)
 	if err != nil {
-		return result{}, err
+		return nil, nil, err
 	}
 	typeHints := make(tree.PlaceholderTypes, len(datums))
 	for i, d := range datums {
@@ -465,7 +573,6 @@ func (ie *InternalExecutor) execInternal(
 		typeHints[tree.PlaceholderIdx(i)] = d.ResolvedType()
 	}
 	if len(qargs) == 0 {
-		resPos = 0
 		if err := stmtBuf.Push(
 			ctx,
 			ExecStmt{
@@ -474,10 +581,9 @@ func (ie *InternalExecutor) execInternal(
 				ParseStart:   parseStart,
 				ParseEnd:     parseEnd,
 			}); err != nil {
-			return result{}, err
+			return nil, nil, err
 		}
 	} else {
-		resPos = 2
 		if err := stmtBuf.Push(
 			ctx,
 			PrepareStmt{
@@ -487,25 +593,21 @@ func (ie *InternalExecutor) execInternal(
 				TypeHints:  typeHints,
 			},
 		); err != nil {
-			return result{}, err
+			return nil, nil, err
 		}
 
 		if err := stmtBuf.Push(ctx, BindStmt{internalArgs: datums}); err != nil {
-			return result{}, err
+			return nil, nil, err
 		}
 
 		if err := stmtBuf.Push(ctx, ExecPortal{TimeReceived: timeReceived}); err != nil {
-			return result{}, err
+			return nil, nil, err
 		}
 	}
 	if err := stmtBuf.Push(ctx, Sync{}); err != nil {
-		return result{}, err
+		return nil, nil, err
 	}
-
-	res := <-resCh
-	stmtBuf.Close()
-	wg.Wait()
-	return res, nil
+	return stmtBuf, wg, nil
 }
 
 // internalClientComm is an implementation of ClientComm used by the
@@ -520,6 +622,10 @@ type internalClientComm struct {
 	// sync, if set, is called whenever a Sync is executed. It returns all the
 	// results since the previous Sync.
 	sync func([]resWithPos)
+
+	// stream, if set, receives the columns and rows of statement results
+	// instead of them being buffered.
+	stream *ieRowStream
 }
 
 var _ ClientComm = &internalClientComm{}
@@ -541,7 +647,13 @@ func (icc *internalClientComm) CreateStatementResult(
 	_ string,
 	_ bool,
 ) CommandResult {
-	return icc.createRes(pos, nil /* onClose */)
+	res := icc.createRes(pos, nil /* onClose */)
+	if icc.stream != nil {
+		return &streamingCommandResult{
+			bufferedCommandResult: res, icc: icc, pos: pos, stream: icc.stream,
+		}
+	}
+	return res
 }
 
 // createRes creates a result. onClose, if not nil, is called when the result is
@@ -652,3 +764,178 @@ func (ncl *noopClientLock) RTrim(_ context.Context, pos CmdPos) {
 	}
 	ncl.results = ncl.results[:i]
 }
+
+// errIEIteratorClosed is returned to the connExecutor serving a rowsIterator
+// when the iterator is closed before all the rows have been consumed.
+var errIEIteratorClosed = errors.New("internal executor iterator closed")
+
+// ieStreamItem is an item handed from the connExecutor serving a rowsIterator
+// to the iterator.
+type ieStreamItem struct {
+	cols colinfo.ResultColumns
+	row  tree.Datums
+	// done is set on the last item, which carries the error of the statement,
+	// if any.
+	done bool
+	err  error
+}
+
+// ieRowStream hands the results of a statement executed by the
+// InternalExecutor over to a rowsIterator, one item at a time. The hand-off is
+// synchronous: after producing an item, the connExecutor's goroutine blocks
+// until the iterator asks for the next one. This ensures that the execution
+// does not progress, and in particular does not use its transaction, while
+// the consumer is not waiting for it.
+type ieRowStream struct {
+	// dataCh carries the items from the connExecutor to the iterator.
+	dataCh chan ieStreamItem
+	// waitCh is used by the iterator to request the next item.
+	waitCh chan struct{}
+	// doneCh is closed when the iterator is closed.
+	doneCh chan struct{}
+	// finished is set once the last item has been sent. It is only accessed
+	// by the connExecutor's goroutine.
+	finished bool
+}
+
+func newIERowStream() *ieRowStream {
+	return &ieRowStream{
+		dataCh: make(chan ieStreamItem),
+		waitCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// send hands an item over to the iterator and, unless it's the last item,
+// waits for the iterator to request the next one. errIEIteratorClosed is
+// returned if the iterator is closed in the meantime.
+func (s *ieRowStream) send(item ieStreamItem) error {
+	select {
+	case s.dataCh <- item:
+	case <-s.doneCh:
+		return errIEIteratorClosed
+	}
+	if item.done {
+		return nil
+	}
+	select {
+	case <-s.waitCh:
+		return nil
+	case <-s.doneCh:
+		return errIEIteratorClosed
+	}
+}
+
+// finish sends the last item, carrying the statement's error, if it hasn't
+// been sent already.
+func (s *ieRowStream) finish(err error) {
+	if s.finished {
+		return
+	}
+	s.finished = true
+	_ = s.send(ieStreamItem{done: true, err: err})
+}
+
+// streamingCommandResult is a CommandResult which hands the result columns and
+// rows over to an ieRowStream instead of buffering them.
+type streamingCommandResult struct {
+	*bufferedCommandResult
+	icc    *internalClientComm
+	pos    CmdPos
+	stream *ieRowStream
+}
+
+var _ CommandResult = &streamingCommandResult{}
+
+// SetColumns is part of the RestrictedCommandResult interface.
+func (r *streamingCommandResult) SetColumns(_ context.Context, cols colinfo.ResultColumns) {
+	r.cols = cols
+	// If the iterator has been closed, the error is returned by the next
+	// AddRow() call.
+	_ = r.stream.send(ieStreamItem{cols: cols})
+}
+
+// AddRow is part of the RestrictedCommandResult interface.
+func (r *streamingCommandResult) AddRow(_ context.Context, row tree.Datums) error {
+	// The rows handed over cannot be taken back, so the statement must not be
+	// retried automatically anymore.
+	r.icc.lastDelivered = r.pos
+	rowCopy := make(tree.Datums, len(row))
+	copy(rowCopy, row)
+	return r.stream.send(ieStreamItem{row: rowCopy})
+}
+
+// rowsIterator iterates over the rows of a statement executed by
+// InternalExecutor.QueryIteratorEx.
+type rowsIterator struct {
+	stream  *ieRowStream
+	stmtBuf *StmtBuf
+	wg      *sync.WaitGroup
+
+	cols colinfo.ResultColumns
+	row  tree.Datums
+	// done is set once the last item of the stream has been received, or the
+	// iterator has been closed.
+	done bool
+}
+
+// Next advances the iterator to the next row, returning false if there are no
+// more rows or an error occurred.
+func (it *rowsIterator) Next(ctx context.Context) (bool, error) {
+	if it.done {
+		return false, nil
+	}
+	var item ieStreamItem
+	select {
+	case it.stream.waitCh <- struct{}{}:
+	case <-ctx.Done():
+		it.Close()
+		return false, ctx.Err()
+	}
+	select {
+	case item = <-it.stream.dataCh:
+	case <-ctx.Done():
+		// The iterator cannot be used anymore since the item being produced
+		// would be lost.
+		it.Close()
+		return false, ctx.Err()
+	}
+	if item.done {
+		it.finish()
+		return false, item.err
+	}
+	it.row = item.row
+	return true, nil
+}
+
+// Cur returns the current row. It is only valid after Next() returned true.
+func (it *rowsIterator) Cur() tree.Datums {
+	return it.row
+}
+
+// Types returns the result columns of the statement.
+func (it *rowsIterator) Types() colinfo.ResultColumns {
+	return it.cols
+}
+
+// Close releases the resources of the iterator, interrupting the execution of
+// the statement if it hasn't finished yet.
+func (it *rowsIterator) Close() {
+	if !it.done {
+		close(it.stream.doneCh)
+	}
+	it.finish()
+}
+
+// finish shuts down the connExecutor serving the iterator and waits for it to
+// exit.
+func (it *rowsIterator) finish() {
+	if it.stmtBuf == nil {
+		return
+	}
+	it.done = true
+	it.row = nil
+	it.stmtBuf.Close()
+	it.wg.Wait()
+	it.stmtBuf = nil
+}
@@ -165,6 +165,86 @@ func TestInternalFullTableScan(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestQueryIteratorEx(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, _, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	ie := s.InternalExecutor().(*sql.InternalExecutor)
+	root := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+
+	t.Run("all rows", func(t *testing.T) {
+		it, err := ie.QueryIteratorEx(ctx, "test", nil /* txn */, root,
+			"SELECT g FROM generate_series(1, 10) AS g")
+		require.NoError(t, err)
+		defer it.Close()
+		require.Len(t, it.Types(), 1)
+		require.Equal(t, "g", it.Types()[0].Name)
+		var n int
+		for {
+			ok, err := it.Next(ctx)
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			n++
+			require.Equal(t, tree.DInt(n), tree.MustBeDInt(it.Cur()[0]))
+		}
+		require.Equal(t, 10, n)
+	})
+
+	t.Run("early close", func(t *testing.T) {
+		it, err := ie.QueryIteratorEx(ctx, "test", nil /* txn */, root,
+			"SELECT g FROM generate_series(1, 1000000) AS g")
+		require.NoError(t, err)
+		ok, err := it.Next(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		it.Close()
+		ok, err = it.Next(ctx)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("planning error", func(t *testing.T) {
+		_, err := ie.QueryIteratorEx(ctx, "test", nil /* txn */, root,
+			"SELECT * FROM nonexistent")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `relation "nonexistent" does not exist`)
+	})
+
+	// The txn can be used in between rows, as the statement only runs while
+	// rows are requested.
+	t.Run("txn", func(t *testing.T) {
+		require.NoError(t, kvDB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			it, err := ie.QueryIteratorEx(ctx, "test", txn, root,
+				"SELECT g FROM generate_series(1, 3) AS g")
+			if err != nil {
+				return err
+			}
+			defer it.Close()
+			for i := 1; i <= 3; i++ {
+				ok, err := it.Next(ctx)
+				if err != nil {
+					return err
+				}
+				require.True(t, ok)
+				require.Equal(t, tree.DInt(i), tree.MustBeDInt(it.Cur()[0]))
+				if _, err := ie.QueryRowEx(ctx, "test", txn, root, "SELECT 1"); err != nil {
+					return err
+				}
+			}
+			ok, err := it.Next(ctx)
+			require.False(t, ok)
+			return err
+		}))
+	})
+}
+
 func TestQueryIsAdminWithNoTxn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
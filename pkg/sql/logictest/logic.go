@@ -39,6 +39,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -506,6 +507,15 @@ var logicTestConfigs = []testClusterConfig{
 		binaryVersion:       roachpb.Version{Major: 20, Minor: 2},
 		disableUpgrade:      true,
 	},
+	{
+		name:                "local-mixed-20.2-21.1",
+		numNodes:            1,
+		overrideDistSQLMode: "off",
+		overrideAutoStats:   "false",
+		bootstrapVersion:    roachpb.Version{Major: 20, Minor: 2},
+		binaryVersion:       clusterversion.TestingBinaryVersion,
+		disableUpgrade:      true,
+	},
 	{
 		name:                                "local-spec-planning",
 		numNodes:                            1,
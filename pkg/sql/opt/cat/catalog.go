@@ -125,6 +125,19 @@ type Catalog interface {
 		ctx context.Context, name *tree.UnresolvedObjectName,
 	) (*types.T, error)
 
+	// ResolveFunction locates a user-defined function with the given name and
+	// returns it along with the name of the schema that contains it. Names
+	// without an explicit schema are looked up using the search path.
+	//
+	// If no such function exists, then ResolveFunction returns an error with
+	// code pgcode.UndefinedFunction.
+	//
+	// NOTE: The returned function must be immutable after construction, and so
+	// can be safely copied or used across goroutines.
+	ResolveFunction(
+		ctx context.Context, flags Flags, name *tree.UnresolvedObjectName,
+	) (Function, SchemaName, error)
+
 	// CheckPrivilege verifies that the current user has the given privilege on
 	// the given catalog object. If not, then CheckPrivilege returns an error.
 	CheckPrivilege(ctx context.Context, o Object, priv privilege.Kind) error
@@ -0,0 +1,50 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// Function is an interface to a user-defined function stored in the catalog.
+// Its body is a SQL query which returns a single value.
+type Function interface {
+	Object
+
+	// Name returns the unqualified name of the function.
+	Name() tree.Name
+
+	// ParamCount returns the number of parameters of the function.
+	ParamCount() int
+
+	// ParamName returns the name of the i-th parameter, or the empty name if
+	// the parameter is unnamed. Unnamed parameters can only be referenced as
+	// $1, $2, etc. in the function body.
+	ParamName(i int) tree.Name
+
+	// ParamType returns the type of the i-th parameter.
+	ParamType(i int) *types.T
+
+	// ReturnType returns the type of the value returned by the function.
+	ReturnType() *types.T
+
+	// Volatility returns the volatility that was declared for the function.
+	// It is checked against the function body when the function is created.
+	Volatility() tree.Volatility
+
+	// IsStrict returns true if the function returns NULL, without being
+	// evaluated, when any of its arguments is NULL.
+	IsStrict() bool
+
+	// Body returns the SQL text of the function body.
+	Body() string
+}
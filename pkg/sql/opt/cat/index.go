@@ -249,6 +249,11 @@ type IndexColumn struct {
 	// Descending is true if the index is ordered from greatest to least on
 	// this column, rather than least to greatest.
 	Descending bool
+
+	// NullsReversed is true if NULLs sort opposite to the default for the
+	// column's direction: after all other values (NULLS LAST) if the column is
+	// ascending, or before them (NULLS FIRST) if it is descending.
+	NullsReversed bool
 }
 
 // IsMutationIndex is a convenience function that returns true if the index at
@@ -131,6 +131,22 @@ type Table interface {
 	// Unique returns the ith unique constraint defined on this table, where
 	// i < UniqueCount.
 	Unique(i int) UniqueConstraint
+
+	// ExclusionCount returns the number of exclusion constraints defined on this
+	// table.
+	ExclusionCount() int
+
+	// Exclusion returns the ith exclusion constraint defined on this table,
+	// where i < ExclusionCount.
+	Exclusion(i int) ExclusionConstraint
+
+	// TriggerCount returns the number of enabled row-level triggers defined on
+	// this table.
+	TriggerCount() int
+
+	// Trigger returns the ith enabled trigger, where i < TriggerCount. Triggers
+	// are ordered by name, which is the order in which they fire.
+	Trigger(i int) Trigger
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -145,6 +161,34 @@ type CheckConstraint struct {
 	Validated  bool
 }
 
+// Trigger contains the definition of a row-level trigger on a table. The
+// trigger runs a SQL statement for each row modified by the statements which
+// fire it. For example, this trigger records the rows deleted from a table:
+//
+//   CREATE TRIGGER audit AFTER DELETE ON a FOR EACH ROW
+//     AS 'INSERT INTO a_audit VALUES (old.a, now())'
+//
+type Trigger struct {
+	Name       string
+	ActionTime tree.TriggerActionTime
+	Events     tree.TriggerEvents
+	// When is the SQL text of the condition under which the trigger fires, or
+	// the empty string if it fires for every row.
+	When string
+	// Body is the SQL text of the statement run by the trigger.
+	Body string
+}
+
+// FiresOn returns true if the trigger fires on the given event.
+func (t *Trigger) FiresOn(event tree.TriggerEvent) bool {
+	for _, e := range t.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
 // TableStatistic is an interface to a table statistic. Each statistic is
 // associated with a set of columns.
 type TableStatistic interface {
@@ -237,6 +281,15 @@ type ForeignKeyConstraint interface {
 	// MatchMethod returns the method used for comparing composite foreign keys.
 	MatchMethod() tree.CompositeKeyMatchMethod
 
+	// Deferrable is true if the checking of the constraint can be deferred until
+	// the end of the transaction. A deferrable constraint can be temporarily
+	// violated, so the optimizer cannot make any assumptions about the data.
+	Deferrable() bool
+
+	// InitiallyDeferred is true if the checking of the constraint is deferred
+	// until the end of the transaction, unless SET CONSTRAINTS is used.
+	InitiallyDeferred() bool
+
 	// DeleteReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by a delete.
 	DeleteReferenceAction() tree.ReferenceAction
@@ -246,6 +299,13 @@ type ForeignKeyConstraint interface {
 	UpdateReferenceAction() tree.ReferenceAction
 }
 
+// UniqueOrdinal identifies a unique constraint (in the context of a Table).
+type UniqueOrdinal = int
+
+// UniqueOrdinals identifies a list of unique constraints (in the context of
+// a Table).
+type UniqueOrdinals = []UniqueOrdinal
+
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
 // or may not be enforced with a unique index. For example, the following
 // statement creates a unique constraint on column a without a unique index:
@@ -277,4 +337,47 @@ type UniqueConstraint interface {
 	// cannot make any assumptions about the data. An unvalidated constraint still
 	// needs to be enforced on new mutations.
 	Validated() bool
+
+	// Deferrable is true if the checking of the constraint can be deferred until
+	// the end of the transaction. A deferrable constraint can be temporarily
+	// violated, so the optimizer cannot make any assumptions about the data.
+	Deferrable() bool
+
+	// InitiallyDeferred is true if the checking of the constraint is deferred
+	// until the end of the transaction, unless SET CONSTRAINTS is used.
+	InitiallyDeferred() bool
+}
+
+// ExclusionConstraint represents an exclusion constraint. An exclusion
+// constraint guarantees that no two rows in the table compare true under all
+// of the constraint's operators on the constrained columns. For example, the
+// following constraint disallows overlapping arrays for rows with the same
+// value in column a:
+//   ALTER TABLE t ADD CONSTRAINT e EXCLUDE USING gist (a WITH =, b WITH &&);
+// Exclusion constraints are not enforced by an index, so the optimizer must
+// add an exclusion check as a postquery to any query that inserts into or
+// updates the constrained columns.
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// TableID returns the stable identifier of the table on which this
+	// exclusion constraint is defined.
+	TableID() StableID
+
+	// ColumnCount returns the number of columns in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the ith column in this
+	// constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the comparison operator used for the ith column in this
+	// constraint.
+	Operator(i int) tree.ComparisonOperator
+
+	// Validated is true if the constraint is validated (i.e. we know that the
+	// existing data satisfies the constraint). An unvalidated constraint still
+	// needs to be enforced on new mutations.
+	Validated() bool
 }
@@ -173,6 +173,23 @@ func FormatTable(cat Catalog, tab Table, tp treeprinter.Node) {
 		)
 	}
 
+	for i := 0; i < tab.ExclusionCount(); i++ {
+		e := tab.Exclusion(i)
+		var buf bytes.Buffer
+		for j := 0; j < e.ColumnCount(); j++ {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s WITH %s", tab.Column(e.ColumnOrdinal(tab, j)).ColName(), e.Operator(j))
+		}
+		child.Childf("EXCLUDE (%s)", buf.String())
+	}
+
+	for i := 0; i < tab.TriggerCount(); i++ {
+		t := tab.Trigger(i)
+		child.Childf("TRIGGER %s %s %s", t.Name, t.ActionTime, tree.AsString(&t.Events))
+	}
+
 	// TODO(radu): show stats.
 }
 
@@ -205,6 +222,13 @@ func formatCatalogIndex(tab Table, ord int, tp treeprinter.Node) {
 		if idxCol.Descending {
 			fmt.Fprintf(&buf, " desc")
 		}
+		if idxCol.NullsReversed {
+			if idxCol.Descending {
+				fmt.Fprintf(&buf, " nulls first")
+			} else {
+				fmt.Fprintf(&buf, " nulls last")
+			}
+		}
 
 		if i >= idx.LaxKeyColumnCount() {
 			fmt.Fprintf(&buf, " (storing)")
@@ -100,6 +100,7 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		input.root,
 		tab,
 		ins.Arbiters,
+		ins.ArbiterConstraints,
 		insertOrds,
 		returnOrds,
 		checkOrds,
@@ -123,6 +124,12 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	// Inserts don't have FK cascades, but they can have AFTER triggers, which
+	// are run as cascades.
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -156,10 +163,15 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	//  - there are no AFTER triggers, which are run as cascades.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
-	//  - there are no self-referencing foreign keys;
+	//  - there are no self-referencing or deferrable foreign keys;
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	fkChecks := make([]exec.InsertFastPathFKCheck, len(ins.FKChecks))
 	for i := range ins.FKChecks {
@@ -169,6 +181,9 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrable() {
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -418,6 +433,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		input.root,
 		tab,
 		ups.Arbiters,
+		ups.ArbiterConstraints,
 		canaryCol,
 		insertColOrds,
 		fetchColOrds,
@@ -464,8 +480,14 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PassthroughCols)+len(del.PartialIndexDelCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
@@ -478,11 +500,22 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols colinfo.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, colinfo.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
 	)
 	if err != nil {
@@ -794,14 +827,34 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			return err
 		}
 		// Wrap the query in an error node.
-		mkErr := func(row tree.Datums) error {
+		keyVals := func(row tree.Datums) tree.Datums {
 			keyVals := make(tree.Datums, len(c.KeyCols))
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
-			return mkUniqueCheckErr(md, c, keyVals)
+			return keyVals
+		}
+		mkErr := func(row tree.Datums) error {
+			return mkUniqueCheckErr(md, c, keyVals(row))
+		}
+		var deferrable *exec.DeferrableCheck
+		if c.Exclusion {
+			// Exclusion constraints cannot be deferred.
+			mkErr = func(row tree.Datums) error {
+				return mkExclusionCheckErr(md, c, keyVals(row))
+			}
+		} else if uc := md.TableMeta(c.Table).Table.Unique(c.CheckOrdinal); uc.Deferrable() {
+			deferrable = &exec.DeferrableCheck{
+				TableID:           uc.TableID(),
+				Name:              uc.Name(),
+				InitiallyDeferred: uc.InitiallyDeferred(),
+				KeyVals:           keyVals,
+				MkErr: func(keyVals tree.Datums) error {
+					return mkUniqueCheckErr(md, c, keyVals)
+				},
+			}
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -820,14 +873,33 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			return err
 		}
 		// Wrap the query in an error node.
-		mkErr := func(row tree.Datums) error {
+		keyVals := func(row tree.Datums) tree.Datums {
 			keyVals := make(tree.Datums, len(c.KeyCols))
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
-			return mkFKCheckErr(md, c, keyVals)
+			return keyVals
+		}
+		mkErr := func(row tree.Datums) error {
+			return mkFKCheckErr(md, c, keyVals(row))
+		}
+		var deferrable *exec.DeferrableCheck
+		if fk := fkCheckConstraint(md, c); fk.Deferrable() && (c.FKOutbound ||
+			(fk.DeleteReferenceAction() != tree.Restrict &&
+				fk.UpdateReferenceAction() != tree.Restrict)) {
+			// The checks of ON DELETE or ON UPDATE RESTRICT are never deferred.
+			deferrable = &exec.DeferrableCheck{
+				TableID:           fk.OriginTableID(),
+				Name:              fk.Name(),
+				ForeignKey:        true,
+				InitiallyDeferred: fk.InitiallyDeferred(),
+				KeyVals:           keyVals,
+				MkErr: func(keyVals tree.Datums) error {
+					return mkFKCheckErr(md, c, keyVals)
+				},
+			}
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -836,6 +908,15 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// fkCheckConstraint returns the foreign key constraint enforced by the given
+// check.
+func fkCheckConstraint(md *opt.Metadata, c *memo.FKChecksItem) cat.ForeignKeyConstraint {
+	if c.FKOutbound {
+		return md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal)
+	}
+	return md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -878,6 +959,48 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 	)
 }
 
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values that correspond to the
+// cat.ExclusionConstraint columns.
+func mkExclusionCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.Exclusion(c.CheckOrdinal)
+	constraintName := ec.Name()
+	var msg, details bytes.Buffer
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (k)=(2) conflicts with an existing key.
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, constraintName)
+
+	details.WriteString("Key (")
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(ec.ColumnOrdinal(tabMeta.Table, i))
+		details.WriteString(string(col.ColName()))
+	}
+	details.WriteString(")=(")
+	for i, d := range keyVals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+
+	details.WriteString(") conflicts with an existing key.")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			constraintName,
+		),
+		details.String(),
+	)
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
@@ -278,7 +278,12 @@ func (b *Builder) buildFunction(
 			return nil, err
 		}
 	}
-	funcRef := tree.WrapFunction(fn.Name)
+	var funcRef tree.ResolvableFunctionReference
+	if fn.Properties.UserDefined {
+		funcRef = tree.WrapUserDefinedFunction(fn.Name, fn.Properties)
+	} else {
+		funcRef = tree.WrapFunction(fn.Name)
+	}
 	return tree.NewTypedFuncExpr(
 		funcRef,
 		0, /* aggQualifier */
@@ -336,11 +341,12 @@ func (b *Builder) buildCast(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Ty
 	if err != nil {
 		return nil, err
 	}
-	if cast.Typ.Family() == types.TupleFamily {
-		// TODO(radu): casts to Tuple are not supported (they can't be serialized
-		// for distsql). This should only happen when the input is always NULL so
-		// the expression should still be valid without the cast (though there could
-		// be cornercases where the type does matter).
+	if cast.Typ.Family() == types.TupleFamily && !cast.Typ.UserDefined() {
+		// TODO(radu): casts to anonymous Tuples are not supported (they can't be
+		// serialized for distsql). This should only happen when the input is
+		// always NULL so the expression should still be valid without the cast
+		// (though there could be cornercases where the type does matter). Casts
+		// to composite types are serialized by reference to the type's OID.
 		return input, nil
 	}
 	return tree.NewTypedCastExpr(input, cast.Typ), nil
@@ -160,6 +160,7 @@ func omitTrivialProjections(n *Node) (*Node, colinfo.ResultColumns, colinfo.Colu
 	for i, o := range inputOrdering {
 		ordering[i].ColIdx = inverse[o.ColIdx]
 		ordering[i].Direction = o.Direction
+		ordering[i].NullsReversed = o.NullsReversed
 	}
 	return input, n.Columns(), ordering
 }
@@ -256,6 +257,8 @@ var nodeNames = [...]string{
 	createStatisticsOp:     "create statistics",
 	createTableOp:          "create table",
 	createTableAsOp:        "create table as",
+	createFunctionOp:       "create function",
+	createTriggerOp:        "create trigger",
 	createViewOp:           "create view",
 	deleteOp:               "delete",
 	deleteRangeOp:          "delete range",
@@ -601,6 +604,16 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			}
 			ob.Attr("arbiter indexes", sb.String())
 		}
+		if len(a.ArbiterConstraints) > 0 {
+			var sb strings.Builder
+			for i, uc := range a.ArbiterConstraints {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(a.Table.Unique(uc).Name())
+			}
+			ob.Attr("arbiter constraints", sb.String())
+		}
 
 	case insertFastPathOp:
 		a := n.args.(*insertFastPathArgs)
@@ -640,6 +653,16 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			}
 			ob.Attr("arbiter indexes", sb.String())
 		}
+		if len(a.ArbiterConstraints) > 0 {
+			var sb strings.Builder
+			for i, uc := range a.ArbiterConstraints {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(a.Table.Unique(uc).Name())
+			}
+			ob.Attr("arbiter constraints", sb.String())
+		}
 
 	case updateOp:
 		a := n.args.(*updateArgs)
@@ -680,6 +703,8 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		createTableOp,
 		createTableAsOp,
 		createViewOp,
+		createFunctionOp,
+		createTriggerOp,
 		sequenceSelectOp,
 		saveTableOp,
 		errorIfRowsOp,
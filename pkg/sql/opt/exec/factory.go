@@ -259,6 +259,35 @@ type InsertFastPathFKCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableCheck describes a foreign key or uniqueness check query for a
+// deferrable constraint. When the constraint is deferred, the violations found
+// by the query are recorded and checked again at the end of the transaction.
+type DeferrableCheck struct {
+	// TableID is the ID of the table the constraint belongs to. For foreign
+	// keys, this is the origin (referencing) table.
+	TableID cat.StableID
+
+	// Name is the name of the constraint.
+	Name string
+
+	// ForeignKey is true if the constraint is a foreign key constraint, and
+	// false if it is a UNIQUE WITHOUT INDEX constraint.
+	ForeignKey bool
+
+	// InitiallyDeferred is true if the constraint is deferred unless SET
+	// CONSTRAINTS is used.
+	InitiallyDeferred bool
+
+	// KeyVals returns the values of the constraint columns for a row returned
+	// by the check query. For foreign keys, the values are the same for the
+	// origin and the referenced columns.
+	KeyVals func(row tree.Datums) tree.Datums
+
+	// MkErr generates the error for a violation, given the values returned by
+	// KeyVals.
+	MkErr MkErrFn
+}
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
@@ -1320,6 +1320,10 @@ func (ic *Instance) Init(
 		ic.constraint = *constraints[0]
 	} else {
 		ic.tight = ic.makeSpansForExpr(0 /* offset */, &ic.allFilters, &ic.constraint)
+		if !ic.respectsNullsOrder(&ic.constraint) {
+			ic.unconstrained(0 /* offset */, &ic.constraint)
+			ic.tight = false
+		}
 	}
 	// Note: If consolidate is true, we only consolidate spans at the
 	// end; consolidating partial results can lead to worse spans, for example:
@@ -1341,6 +1345,11 @@ func (ic *Instance) Init(
 	if consolidate {
 		ic.consolidatedConstraint = ic.constraint
 		ic.consolidatedConstraint.ConsolidateSpans(evalCtx)
+		if !ic.isInverted && !ic.respectsNullsOrder(&ic.consolidatedConstraint) {
+			ic.unconstrained(0 /* offset */, &ic.constraint)
+			ic.unconstrained(0 /* offset */, &ic.consolidatedConstraint)
+			ic.tight = false
+		}
 		ic.consolidated = true
 	}
 	ic.initialized = true
@@ -1405,6 +1414,12 @@ type indexConstraintCtx struct {
 
 	columns []opt.OrderingColumn
 
+	// nullsReversed contains the ordinals of the index columns that store NULLs
+	// opposite to the default for their direction (e.g. ASC NULLS LAST). The
+	// constraints are always built as if NULLs were ordered first; see
+	// respectsNullsOrder.
+	nullsReversed util.FastIntSet
+
 	notNullCols opt.ColSet
 
 	computedCols map[opt.ColumnID]opt.ScalarExpr
@@ -1444,10 +1459,70 @@ func (c *indexConstraintCtx) init(
 		factory:      factory,
 		keyCtx:       make([]constraint.KeyContext, len(columns)),
 	}
+	for i := range columns {
+		if columns[i].NullsReversed() {
+			// Strip the NULL ordering from the columns of the constraints.
+			if c.nullsReversed.Empty() {
+				c.columns = append([]opt.OrderingColumn(nil), columns...)
+			}
+			c.nullsReversed.Add(i)
+			c.columns[i] = opt.MakeOrderingColumn(columns[i].ID(), columns[i].Descending())
+		}
+	}
 	for i := range columns {
 		c.keyCtx[i].EvalCtx = evalCtx
-		c.keyCtx[i].Columns.Init(columns[i:])
+		c.keyCtx[i].Columns.Init(c.columns[i:])
+	}
+}
+
+// respectsNullsOrder returns true if the spans of the given constraint (which
+// are built as if all index columns ordered NULLs first) describe the same rows
+// and order when NULLs are stored at the other end of the index columns in
+// nullsReversed. This is the case if, for every span and every such column,
+// either:
+//  - the column is part of the prefix that the start and end keys have in
+//    common,
+//  - the column is not part of either key, or
+//  - the column is the first one that differs between the keys, and neither
+//    key has a NULL (or missing) value for it.
+// In addition, NULL values are only allowed in a constraint with a single
+// span, since the order of the spans would not match the order of the index
+// otherwise.
+func (c *indexConstraintCtx) respectsNullsOrder(cons *constraint.Constraint) bool {
+	if c.nullsReversed.Empty() {
+		return true
 	}
+	keyCtx := &c.keyCtx[0]
+	for i, n := 0, cons.Spans.Count(); i < n; i++ {
+		sp := cons.Spans.Get(i)
+		start, end := sp.StartKey(), sp.EndKey()
+		// Find the length of the common prefix of the start and end keys.
+		prefix := 0
+		for prefix < start.Length() && prefix < end.Length() &&
+			keyCtx.Compare(prefix, start.Value(prefix), end.Value(prefix)) == 0 {
+			prefix++
+		}
+		for col, ok := c.nullsReversed.Next(0); ok; col, ok = c.nullsReversed.Next(col + 1) {
+			switch {
+			case col < prefix:
+				if start.Value(col) == tree.DNull && n > 1 {
+					return false
+				}
+			case start.Length() <= col && end.Length() <= col:
+				// The column is not constrained by this span.
+			case col == prefix:
+				if start.Length() <= col || end.Length() <= col ||
+					start.Value(col) == tree.DNull || end.Value(col) == tree.DNull {
+					return false
+				}
+			default:
+				if start.Length() > col || end.Length() > col {
+					return false
+				}
+			}
+		}
+	}
+	return true
 }
 
 // isIndexColumn returns true if e is an expression that corresponds to index
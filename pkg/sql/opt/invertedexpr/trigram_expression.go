@@ -0,0 +1,42 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedexpr
+
+import "github.com/cockroachdb/cockroach/pkg/util/encoding"
+
+// TrigramsToSpanExpr converts a set of trigrams to a SpanExpression over a
+// trigram inverted index. If union is true, the expression matches the rows
+// that contain any of the trigrams; otherwise it matches the rows that contain
+// all of them. The expression is never tight, since the trigrams of a string
+// are only an approximation of the string. Returns nil if there are no
+// trigrams, since the index cannot be used to constrain anything in that case.
+func TrigramsToSpanExpr(trigrams []string, union bool) *SpanExpression {
+	var invExpr InvertedExpression
+	for _, t := range trigrams {
+		// The keys must be encoded the same way as in
+		// rowenc.EncodeInvertedIndexTableKeys.
+		spanExpr := ExprForInvertedSpan(
+			MakeSingleInvertedValSpan(encoding.EncodeStringAscending(nil, t)), false, /* tight */
+		)
+		switch {
+		case invExpr == nil:
+			invExpr = spanExpr
+		case union:
+			invExpr = Or(invExpr, spanExpr)
+		default:
+			invExpr = And(invExpr, spanExpr)
+		}
+	}
+	if spanExpr, ok := invExpr.(*SpanExpression); ok {
+		return spanExpr
+	}
+	return nil
+}
@@ -108,12 +108,19 @@ func TryFilterInvertedIndex(
 		}
 		typ = types.Geometry
 	} else {
-		filterPlanner = &jsonOrArrayFilterPlanner{
-			tabID: tabID,
-			index: index,
-		}
 		col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
 		typ = factory.Metadata().Table(tabID).Column(col).DatumType()
+		if typ.Family() == types.StringFamily {
+			filterPlanner = &trigramFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		} else {
+			filterPlanner = &jsonOrArrayFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		}
 	}
 
 	var invertedExpr invertedexpr.InvertedExpression
@@ -188,6 +195,11 @@ func TryJoinInvertedIndex(
 			getSpanExpr: getSpanExprForGeometryIndex,
 		}
 	} else {
+		col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
+		if factory.Metadata().Table(tabID).Column(col).DatumType().Family() == types.StringFamily {
+			// Inverted joins are not supported for trigram indexes.
+			return nil
+		}
 		joinPlanner = &jsonOrArrayJoinPlanner{
 			tabID:     tabID,
 			index:     index,
@@ -382,7 +394,7 @@ func constrainPrefixColumns(
 	for i := range prefixColumns {
 		col := index.Column(i)
 		colID := tabID.ColumnID(col.Ordinal())
-		prefixColumns[i] = opt.MakeOrderingColumn(colID, col.Descending)
+		prefixColumns[i] = opt.MakeOrderingColumnWithNulls(colID, col.Descending, col.NullsReversed)
 		if !col.IsNullable() {
 			notNullCols.Add(colID)
 		}
@@ -0,0 +1,165 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"regexp/syntax"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+)
+
+type trigramFilterPlanner struct {
+	tabID opt.TableID
+	index cat.Index
+}
+
+var _ invertedFilterPlanner = &trigramFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf is part of the invertedFilterPlanner
+// interface.
+func (t *trigramFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	evalCtx *tree.EvalContext, expr opt.ScalarExpr,
+) (
+	invertedExpr invertedexpr.InvertedExpression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	var left, right opt.ScalarExpr
+	var getTrigrams func(s string) (trigrams []string, union bool)
+	switch e := expr.(type) {
+	case *memo.LikeExpr:
+		left, right, getTrigrams = e.Left, e.Right, likeTrigrams
+	case *memo.ILikeExpr:
+		// The trigrams are case-insensitive, so ILIKE is handled like LIKE.
+		left, right, getTrigrams = e.Left, e.Right, likeTrigrams
+	case *memo.RegMatchExpr:
+		left, right, getTrigrams = e.Left, e.Right, regexTrigrams
+	case *memo.RegIMatchExpr:
+		left, right, getTrigrams = e.Left, e.Right, regexTrigrams
+	case *memo.EqExpr:
+		left, right, getTrigrams = e.Left, e.Right, equalityTrigrams
+		if !t.isIndexColumn(left) {
+			left, right = right, left
+		}
+	case *memo.ModExpr:
+		// The string % operator is the trigram similarity operator.
+		left, right, getTrigrams = e.Left, e.Right, similarityTrigrams
+		if !t.isIndexColumn(left) {
+			left, right = right, left
+		}
+	default:
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+
+	if !t.isIndexColumn(left) || !memo.CanExtractConstDatum(right) {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	d, ok := tree.AsDString(memo.ExtractConstDatum(right))
+	if !ok {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	spanExpr := invertedexpr.TrigramsToSpanExpr(getTrigrams(string(d)))
+	if spanExpr == nil {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	// The trigram span expressions are never tight, so the original expression
+	// must always be applied as a remaining filter.
+	return spanExpr, expr, nil
+}
+
+// isIndexColumn returns true if the given expression is a variable that
+// refers to the source column of the inverted index.
+func (t *trigramFilterPlanner) isIndexColumn(expr opt.ScalarExpr) bool {
+	variable, ok := expr.(*memo.VariableExpr)
+	if !ok || variable.Typ.Family() != types.StringFamily {
+		return false
+	}
+	return variable.Col == t.tabID.ColumnID(
+		t.index.VirtualInvertedColumn().InvertedSourceColumnOrdinal(),
+	)
+}
+
+// likeTrigrams returns the trigrams that every string matching the given LIKE
+// pattern must contain. These are the unpadded trigrams of the literal
+// fragments of the pattern, since a fragment can match in the middle of a
+// word.
+func likeTrigrams(pattern string) (trigrams []string, union bool) {
+	var fragments []string
+	var b strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%' || r == '_':
+			fragments = append(fragments, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	fragments = append(fragments, b.String())
+	return fragmentTrigrams(fragments), false /* union */
+}
+
+// regexTrigrams returns the trigrams that every string matching the given
+// regular expression must contain. Only the literal runs of a top-level
+// concatenation are considered; more complex expressions produce no trigrams.
+func regexTrigrams(pattern string) (trigrams []string, union bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, false /* union */
+	}
+	re = re.Simplify()
+	var fragments []string
+	switch re.Op {
+	case syntax.OpLiteral:
+		fragments = append(fragments, string(re.Rune))
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpLiteral {
+				fragments = append(fragments, string(sub.Rune))
+			}
+		}
+	}
+	return fragmentTrigrams(fragments), false /* union */
+}
+
+// equalityTrigrams returns all of the trigrams of the given string, which must
+// be contained by any string equal to it.
+func equalityTrigrams(s string) (trigrams []string, union bool) {
+	return trigram.MakeTrigrams(s, true /* pad */), false /* union */
+}
+
+// similarityTrigrams returns the trigrams of the given string, of which any
+// similar string must contain at least one.
+func similarityTrigrams(s string) (trigrams []string, union bool) {
+	return trigram.MakeTrigrams(s, true /* pad */), true /* union */
+}
+
+// fragmentTrigrams returns the unpadded trigrams of all the given fragments.
+func fragmentTrigrams(fragments []string) []string {
+	var trigrams []string
+	for _, f := range fragments {
+		trigrams = append(trigrams, trigram.MakeTrigrams(f, false /* pad */)...)
+	}
+	return trigrams
+}
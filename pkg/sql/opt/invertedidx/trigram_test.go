@@ -0,0 +1,94 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+func TestTryFilterTrigramIndex(t *testing.T) {
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := tree.NewTestingEvalContext(nil /* st */)
+
+	tc := testcat.New()
+	if _, err := tc.ExecuteDDL(
+		"CREATE TABLE t (s STRING, INVERTED INDEX (s gin_trgm_ops))",
+	); err != nil {
+		t.Fatal(err)
+	}
+	var f norm.Factory
+	f.Init(evalCtx, tc)
+	md := f.Metadata()
+	tn := tree.NewUnqualifiedTableName("t")
+	tab := md.AddTable(tc.Table(tn), tn)
+	trigramOrd := 1
+
+	testCases := []struct {
+		filters string
+		ok      bool
+		// numSpans is the number of spans read from the index.
+		numSpans int
+	}{
+		// The trigram span expressions are never tight, so the original filters
+		// must always remain.
+		{filters: "s LIKE '%foo%'", ok: true, numSpans: 1},
+		{filters: "s ILIKE '%FOO%'", ok: true, numSpans: 1},
+		{filters: "s LIKE 'foo%bar'", ok: true, numSpans: 2},
+		{filters: "s LIKE '%foobar%'", ok: true, numSpans: 4},
+		{filters: "s LIKE '%fo_bar%'", ok: true, numSpans: 1},
+		{filters: "s ~ 'foo.*bar'", ok: true, numSpans: 2},
+		{filters: "s ~* 'foo'", ok: true, numSpans: 1},
+		{filters: "s = 'foo'", ok: true, numSpans: 4},
+		{filters: "'foo' = s", ok: true, numSpans: 4},
+		{filters: "s % 'foo'", ok: true, numSpans: 4},
+		{filters: "s LIKE '%foo%' OR s LIKE '%bar%'", ok: true, numSpans: 2},
+		{filters: "s LIKE '%foo%' AND s LIKE '%bar%'", ok: true, numSpans: 2},
+		// Fragments shorter than three characters have no unpadded trigrams.
+		{filters: "s LIKE '%fo%'", ok: false},
+		{filters: "s LIKE '%f_o%'", ok: false},
+		{filters: "s ~ 'foo|bar'", ok: false},
+		{filters: "s = ''", ok: false},
+		{filters: "s LIKE '%foo%' OR s LIKE '%fo%'", ok: false},
+		{filters: "s NOT LIKE '%foo%'", ok: false},
+		{filters: "s > 'foo'", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("test case: %v", tc)
+		filters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.filters)
+
+		spanExpr, _, remainingFilters, _, ok := invertedidx.TryFilterInvertedIndex(
+			evalCtx, &f, filters, nil /* optionalFilters */, tab, md.Table(tab).Index(trigramOrd),
+		)
+		if tc.ok != ok {
+			t.Fatalf("expected %v, got %v", tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+
+		if spanExpr.Tight {
+			t.Fatalf("expected tight=false, but got true")
+		}
+		if len(spanExpr.SpansToRead) != tc.numSpans {
+			t.Fatalf("expected %d spans, got %d", tc.numSpans, len(spanExpr.SpansToRead))
+		}
+		if remainingFilters.String() != filters.String() {
+			t.Errorf("expected remainingFilters=%v, got %v", filters, remainingFilters)
+		}
+	}
+}
@@ -207,7 +207,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
-		*CancelSessionsExpr, *CreateViewExpr, *ExportExpr:
+		*CancelSessionsExpr, *CreateViewExpr, *CreateFunctionExpr, *CreateTriggerExpr, *ExportExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -509,7 +509,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			if len(colList) == 0 {
 				tp.Child("columns: <none>")
 			}
-			f.formatArbiters(tp, t.Arbiters, t.Table)
+			f.formatArbiters(tp, t.Arbiters, t.ArbiterConstraints, t.Table)
 			f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
 			f.formatOptionalColList(e, tp, "check columns:", t.CheckCols)
 			f.formatOptionalColList(e, tp, "partial index put columns:", t.PartialIndexPutCols)
@@ -535,7 +535,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 				tp.Child("columns: <none>")
 			}
 			if t.CanaryCol != 0 {
-				f.formatArbiters(tp, t.Arbiters, t.Table)
+				f.formatArbiters(tp, t.Arbiters, t.ArbiterConstraints, t.Table)
 				f.formatColList(e, tp, "canary column:", opt.ColList{t.CanaryCol})
 				f.formatOptionalColList(e, tp, "fetch columns:", t.FetchCols)
 				f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
@@ -585,6 +585,15 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 	case *CreateTableExpr:
 		tp.Child(t.Syntax.String())
 
+	case *CreateFunctionExpr:
+		tp.Child(t.Body)
+
+	case *CreateTriggerExpr:
+		if t.When != "" {
+			tp.Childf("when: %s", t.When)
+		}
+		tp.Child(t.Body)
+
 	case *CreateViewExpr:
 		tp.Child(t.ViewQuery)
 
@@ -1014,8 +1023,17 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
-		constraint := tab.Table.Unique(t.CheckOrdinal)
-		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
+		var constraint interface {
+			ColumnCount() int
+			ColumnOrdinal(tab cat.Table, i int) int
+		}
+		if t.Exclusion {
+			constraint = tab.Table.Exclusion(t.CheckOrdinal)
+			fmt.Fprintf(f.Buffer, ": exclude %s(", tab.Alias.ObjectName)
+		} else {
+			constraint = tab.Table.Unique(t.CheckOrdinal)
+			fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
+		}
 		for i := 0; i < constraint.ColumnCount(); i++ {
 			if i > 0 {
 				f.Buffer.WriteByte(',')
@@ -1099,24 +1117,38 @@ func (f *ExprFmtCtx) formatIndex(tabID opt.TableID, idxOrd cat.IndexOrdinal, rev
 	}
 }
 
-// formatArbiters constructs a new treeprinter child containing the
-// specified list of arbiter indexes.
+// formatArbiters constructs new treeprinter children containing the
+// specified lists of arbiter indexes and arbiter constraints.
 func (f *ExprFmtCtx) formatArbiters(
-	tp treeprinter.Node, arbiters cat.IndexOrdinals, tabID opt.TableID,
+	tp treeprinter.Node,
+	arbiterIndexes cat.IndexOrdinals,
+	arbiterConstraints cat.UniqueOrdinals,
+	tabID opt.TableID,
 ) {
 	md := f.Memo.Metadata()
 	tab := md.Table(tabID)
 
-	if len(arbiters) > 0 {
+	if len(arbiterIndexes) > 0 {
 		f.Buffer.Reset()
 		f.Buffer.WriteString("arbiter indexes:")
-		for _, idx := range arbiters {
+		for _, idx := range arbiterIndexes {
 			name := string(tab.Index(idx).Name())
 			f.space()
 			f.Buffer.WriteString(name)
 		}
 		tp.Child(f.Buffer.String())
 	}
+
+	if len(arbiterConstraints) > 0 {
+		f.Buffer.Reset()
+		f.Buffer.WriteString("arbiter constraints:")
+		for _, uc := range arbiterConstraints {
+			name := tab.Unique(uc).Name()
+			f.space()
+			f.Buffer.WriteString(name)
+		}
+		tp.Child(f.Buffer.String())
+	}
 }
 
 func (f *ExprFmtCtx) formatColumns(
@@ -1407,6 +1439,13 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 		schema := f.Memo.Metadata().Schema(t.Schema)
 		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.ViewName)
 
+	case *CreateFunctionPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.FuncName)
+
+	case *CreateTriggerPrivate:
+		tab := f.Memo.Metadata().Table(t.Table)
+		fmt.Fprintf(f.Buffer, " %s ON %s", t.Syntax.Name, tab.Name())
+
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
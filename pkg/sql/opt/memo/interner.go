@@ -468,6 +468,7 @@ func (h *hasher) HashOrderingChoice(val physical.OrderingChoice) {
 		choice := &val.Columns[i]
 		h.HashColSet(choice.Group)
 		h.HashBool(choice.Descending)
+		h.HashBool(choice.NullsReversed)
 	}
 }
 
@@ -553,6 +554,15 @@ func (h *hasher) HashIndexOrdinals(val cat.IndexOrdinals) {
 	h.hash = hash
 }
 
+func (h *hasher) HashUniqueOrdinals(val cat.UniqueOrdinals) {
+	hash := h.hash
+	for _, ord := range val {
+		hash ^= internHash(ord)
+		hash *= prime64
+	}
+	h.hash = hash
+}
+
 func (h *hasher) HashViewDeps(val opt.ViewDeps) {
 	// Hash the length and address of the first element.
 	h.HashInt(len(val))
@@ -944,6 +954,18 @@ func (h *hasher) IsIndexOrdinalsEqual(l, r cat.IndexOrdinals) bool {
 	return true
 }
 
+func (h *hasher) IsUniqueOrdinalsEqual(l, r cat.UniqueOrdinals) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		if l[i] != r[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (h *hasher) IsViewDepsEqual(l, r opt.ViewDeps) bool {
 	if len(l) != len(r) {
 		return false
@@ -397,8 +397,10 @@ func checkForeignKeyCase(
 		leftBaseTable := md.Table(leftTableID)
 		for i, cnt := 0, leftBaseTable.OutboundForeignKeyCount(); i < cnt; i++ {
 			fk := leftBaseTable.OutboundForeignKey(i)
-			if !fk.Validated() {
-				// The data is not guaranteed to follow the foreign key constraint.
+			if !fk.Validated() || fk.Deferrable() {
+				// The data is not guaranteed to follow the foreign key constraint. A
+				// deferrable constraint can be violated until the end of the
+				// transaction.
 				continue
 			}
 			if rightTableIDs == nil {
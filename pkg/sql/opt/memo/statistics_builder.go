@@ -527,6 +527,11 @@ func (sb *statisticsBuilder) makeTableStatistics(tabID opt.TableID) *props.Stati
 		// The inverted column of an inverted index is virtual.
 		col := index.VirtualInvertedColumn()
 		srcOrd := col.InvertedSourceColumnOrdinal()
+		if tab.Column(srcOrd).DatumType().Family() == types.StringFamily {
+			// The histograms of string columns describe the strings themselves,
+			// not the entries of trigram indexes.
+			continue
+		}
 		invIndexVirtualCols[srcOrd] = append(invIndexVirtualCols[srcOrd], col.Ordinal())
 	}
 
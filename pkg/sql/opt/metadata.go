@@ -107,6 +107,11 @@ type Metadata struct {
 	// we want to verify the resolution of both names.
 	deps []mdDep
 
+	// funcDeps stores information about all user-defined functions called by
+	// the query. As with deps, the same function can appear multiple times if
+	// different names were used to call it.
+	funcDeps []mdFuncDep
+
 	// views stores the list of referenced views. This information is only
 	// needed for EXPLAIN (opt, env).
 	views []cat.View
@@ -147,6 +152,13 @@ func (n *MDDepName) equals(other *MDDepName) bool {
 	return n.byID == other.byID && n.byName.Equals(&other.byName)
 }
 
+type mdFuncDep struct {
+	fn cat.Function
+
+	// name is the name that was used to resolve the function.
+	name tree.UnresolvedObjectName
+}
+
 // Init prepares the metadata for use (or reuse).
 func (md *Metadata) Init() {
 	// Clear the metadata objects to release memory (this clearing pattern is
@@ -173,6 +185,10 @@ func (md *Metadata) Init() {
 		md.deps[i] = mdDep{}
 	}
 
+	for i := range md.funcDeps {
+		md.funcDeps[i] = mdFuncDep{}
+	}
+
 	for i := range md.views {
 		md.views[i] = nil
 	}
@@ -185,6 +201,7 @@ func (md *Metadata) Init() {
 		tables:    md.tables[:0],
 		sequences: md.sequences[:0],
 		deps:      md.deps[:0],
+		funcDeps:  md.funcDeps[:0],
 		views:     md.views[:0],
 	}
 }
@@ -196,8 +213,8 @@ func (md *Metadata) Init() {
 // the copy.
 func (md *Metadata) CopyFrom(from *Metadata) {
 	if len(md.schemas) != 0 || len(md.cols) != 0 || len(md.tables) != 0 ||
-		len(md.sequences) != 0 || len(md.deps) != 0 || len(md.views) != 0 ||
-		len(md.userDefinedTypes) != 0 || len(md.userDefinedTypesSlice) != 0 {
+		len(md.sequences) != 0 || len(md.deps) != 0 || len(md.funcDeps) != 0 ||
+		len(md.views) != 0 || len(md.userDefinedTypes) != 0 || len(md.userDefinedTypesSlice) != 0 {
 		panic(errors.AssertionFailedf("CopyFrom requires empty destination"))
 	}
 	md.schemas = append(md.schemas, from.schemas...)
@@ -223,6 +240,7 @@ func (md *Metadata) CopyFrom(from *Metadata) {
 
 	md.sequences = append(md.sequences, from.sequences...)
 	md.deps = append(md.deps, from.deps...)
+	md.funcDeps = append(md.funcDeps, from.funcDeps...)
 	md.views = append(md.views, from.views...)
 	md.currUniqueID = from.currUniqueID
 
@@ -261,6 +279,30 @@ func (md *Metadata) AddDependency(name MDDepName, ds cat.DataSource, priv privil
 	})
 }
 
+// AddFunctionDependency tracks a user-defined function called by the query,
+// along with the name that was used to resolve it. If the Memo using this
+// metadata is cached, then a call to CheckDependencies can detect if the name
+// resolves to a different function now, or if the function was changed, or if
+// the user no longer has the EXECUTE privilege on it.
+func (md *Metadata) AddFunctionDependency(name *tree.UnresolvedObjectName, fn cat.Function) {
+	for i := range md.funcDeps {
+		if md.funcDeps[i].fn == fn && md.funcDeps[i].name == *name {
+			return
+		}
+	}
+	md.funcDeps = append(md.funcDeps, mdFuncDep{fn: fn, name: *name})
+}
+
+// AllFunctions returns the user-defined functions called by the query. A
+// function can appear more than once if it was called using different names.
+func (md *Metadata) AllFunctions() []cat.Function {
+	fns := make([]cat.Function, len(md.funcDeps))
+	for i := range md.funcDeps {
+		fns[i] = md.funcDeps[i].fn
+	}
+	return fns
+}
+
 // CheckDependencies resolves (again) each data source on which this metadata
 // depends, in order to check that all data source names resolve to the same
 // objects, and that the user still has sufficient privileges to access the
@@ -309,6 +351,25 @@ func (md *Metadata) CheckDependencies(
 			privs &= ^(1 << priv)
 		}
 	}
+	// Check that all user-defined functions resolve to the same, unchanged
+	// functions, and that they can still be executed.
+	for i := range md.funcDeps {
+		toCheck, _, err := catalog.ResolveFunction(ctx, cat.Flags{}, &md.funcDeps[i].name)
+		if err != nil {
+			// A dropped function makes the query stale, so that it is built
+			// again and reports the error the same way as an unknown function.
+			if pgerror.GetPGCode(err) == pgcode.UndefinedFunction {
+				return false, nil
+			}
+			return false, err
+		}
+		if !toCheck.Equals(md.funcDeps[i].fn) {
+			return false, nil
+		}
+		if err := catalog.CheckPrivilege(ctx, toCheck, privilege.EXECUTE); err != nil {
+			return false, err
+		}
+	}
 	// Check that all of the user defined types present have not changed.
 	for _, typ := range md.AllUserDefinedTypes() {
 		toCheck, err := catalog.ResolveTypeByOID(ctx, typ.Oid())
@@ -31,6 +31,7 @@ func TestMetadata(t *testing.T) {
 	tabID := md.AddTable(&testcat.Table{}, &tree.TableName{})
 	seqID := md.AddSequence(&testcat.Sequence{})
 	md.AddView(&testcat.View{})
+	md.AddFunctionDependency(&tree.UnresolvedObjectName{}, &testcat.Function{})
 	md.AddUserDefinedType(types.MakeEnum(152100, 154180))
 
 	// Call Init and add objects from catalog, verifying that IDs have been reset.
@@ -57,6 +58,12 @@ func TestMetadata(t *testing.T) {
 		t.Fatalf("unexpected views")
 	}
 
+	fn := &testcat.Function{FuncID: 102}
+	md.AddFunctionDependency(&tree.UnresolvedObjectName{}, fn)
+	if len(md.AllFunctions()) != 1 {
+		t.Fatalf("unexpected functions")
+	}
+
 	md.AddUserDefinedType(types.MakeEnum(151500, 152510))
 	if len(md.AllUserDefinedTypes()) != 1 {
 		fmt.Println(md)
@@ -93,6 +100,10 @@ func TestMetadata(t *testing.T) {
 		t.Fatalf("unexpected view")
 	}
 
+	if fns := mdNew.AllFunctions(); len(fns) != 1 || fns[0] != fn {
+		t.Fatalf("unexpected function")
+	}
+
 	if ts := mdNew.AllUserDefinedTypes(); len(ts) != 1 && ts[151500].Equal(types.MakeEnum(151500, 152510)) {
 		t.Fatalf("unexpected type")
 	}
@@ -553,6 +553,12 @@ func (c *CustomFuncs) FoldFunction(
 		return nil
 	}
 
+	// User-defined functions which were not inlined are evaluated by running
+	// their body as a separate query, which should not happen during planning.
+	if private.Properties.UserDefined {
+		return nil
+	}
+
 	if !c.CanFoldOperator(private.Overload.Volatility) {
 		return nil
 	}
@@ -103,6 +103,17 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 	var cols opt.ColSet
 	tabMeta := c.mem.Metadata().TableMeta(private.Table)
 
+	// Triggers refer to the old values of all the columns of the modified rows,
+	// so none of the FetchCols can be pruned.
+	if tabMeta.Table.TriggerCount() > 0 {
+		for ord, col := range private.FetchCols {
+			if col != 0 {
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+		return cols
+	}
+
 	// familyCols returns the columns in the given family.
 	familyCols := func(fam cat.Family) opt.ColSet {
 		var colSet opt.ColSet
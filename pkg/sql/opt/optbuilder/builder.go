@@ -127,6 +127,14 @@ type Builder struct {
 	// isCorrelated is set to true if we already reported to telemetry that the
 	// query contains a correlated subquery.
 	isCorrelated bool
+
+	// triggerRows is set while the statement run by a trigger is being built.
+	// It is the data source which produces the rows that fire the trigger.
+	triggerRows *triggerRowsSource
+
+	// triggerDepth is the nesting depth of the BEFORE triggers which are being
+	// built.
+	triggerDepth int
 }
 
 // New creates a new Builder structure initialized with the given
@@ -252,6 +260,7 @@ func (b *Builder) buildStmt(
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
 		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+			*tree.CreateFunction, *tree.CreateTrigger,
 			*tree.Split, *tree.Unsplit, *tree.Relocate,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
 			panic(pgerror.Newf(
@@ -288,6 +297,12 @@ func (b *Builder) buildStmt(
 	case *tree.CreateView:
 		return b.buildCreateView(stmt, inScope)
 
+	case *tree.CreateFunction:
+		return b.buildCreateFunction(stmt, inScope)
+
+	case *tree.CreateTrigger:
+		return b.buildCreateTrigger(stmt, inScope)
+
 	case *tree.Explain:
 		return b.buildExplain(stmt, inScope)
 
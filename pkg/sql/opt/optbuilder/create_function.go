@@ -0,0 +1,189 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+func (b *Builder) buildCreateFunction(cf *tree.CreateFunction, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := cf.FuncName.ToTableName()
+	sch, resName := b.resolveSchemaForCreate(&tn)
+	schID := b.factory.Metadata().AddSchema(sch)
+	funcName := tree.MakeTableNameFromPrefix(resName, tree.Name(tn.Object()))
+
+	// Built-in functions are resolved before user-defined functions, so a
+	// function with the same name as a built-in could never be called.
+	if _, ok := tree.FunDefs[funcName.Object()]; ok {
+		panic(pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q conflicts with a built-in function", funcName.Object()))
+	}
+
+	opts, err := cf.Options.Values()
+	if err != nil {
+		panic(err)
+	}
+	paramTypes := make([]*types.T, len(cf.Params))
+	paramIdx := make(map[string]int, len(cf.Params))
+	for i := range cf.Params {
+		paramTypes[i] = b.resolveFunctionType(cf.Params[i].Type)
+		if name := string(cf.Params[i].Name); name != "" {
+			paramIdx[name] = i
+		}
+	}
+	returnType := b.resolveFunctionType(cf.ReturnType)
+
+	stmt, err := parser.ParseOne(opts.Body)
+	if err != nil {
+		panic(pgerror.Wrap(err, pgcode.InvalidFunctionDefinition, "invalid function body"))
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"function body must be a SELECT statement, not %s", stmt.AST.StatementTag()))
+	}
+
+	// Replace the references to the parameters with placeholders, cast to the
+	// type of the parameter. The casts make the body independent of the types
+	// of the arguments it is later called with.
+	replaceInSelect(sel, func(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+		switch t := expr.(type) {
+		case *tree.UnresolvedName:
+			if t.NumParts == 1 && !t.Star {
+				if idx, ok := paramIdx[t.Parts[0]]; ok {
+					return false, castParam(tree.PlaceholderIdx(idx), paramTypes[idx])
+				}
+			}
+		case *tree.CastExpr:
+			// Keep the casts added by a previous CREATE FUNCTION, so that the
+			// output of SHOW CREATE FUNCTION can be executed as is.
+			if p, ok := t.Expr.(*tree.Placeholder); ok && int(p.Idx) < len(paramTypes) {
+				if typ, ok := tree.GetStaticallyKnownType(t.Type); ok && typ.Identical(paramTypes[p.Idx]) {
+					return false, expr
+				}
+			}
+		case *tree.Placeholder:
+			if int(t.Idx) >= len(paramTypes) {
+				panic(pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter %s", t))
+			}
+			return false, castParam(t.Idx, paramTypes[t.Idx])
+		}
+		return true, expr
+	})
+
+	// We build the body to:
+	//  - check the statement semantically,
+	//  - get the fully resolved names into the AST,
+	//  - collect the relations the body depends on in b.viewDeps, and
+	//  - check that the declared volatility is not weaker than that of the
+	//    body.
+	// The result is not otherwise used.
+	defer func(prevPlaceholders tree.PlaceholderInfo, prevKeepPlaceholders bool) {
+		b.semaCtx.Placeholders = prevPlaceholders
+		b.KeepPlaceholders = prevKeepPlaceholders
+		b.insideViewDef = false
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.qualifyDataSourceNamesInAST = false
+	}(b.semaCtx.Placeholders, b.KeepPlaceholders)
+	b.semaCtx.Placeholders = tree.PlaceholderInfo{}
+	if err := b.semaCtx.Placeholders.Init(len(paramTypes), paramTypes); err != nil {
+		panic(err)
+	}
+	b.KeepPlaceholders = true
+	b.insideViewDef = true
+	b.trackViewDeps = true
+	b.qualifyDataSourceNamesInAST = true
+
+	// Stable functions in the body must not be folded into constants, since
+	// that would hide their volatility.
+	var defScope *scope
+	b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
+		b.pushWithFrame()
+		defScope = b.buildStmtAtRoot(sel, []*types.T{returnType}, b.allocScope())
+		b.popWithFrame(defScope)
+	})
+
+	cols := defScope.makePhysicalProps().Presentation
+	if len(cols) != 1 {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s", returnType.SQLString()))
+	}
+	if colType := b.factory.Metadata().ColumnMeta(cols[0].ID).Type; !colType.Equivalent(returnType) &&
+		colType.Family() != types.UnknownFamily {
+		panic(errors.WithDetailf(
+			pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function declared to return %s", returnType.SQLString()),
+			"Actual return type is %s.", colType.SQLString()))
+	}
+
+	vs := defScope.expr.Relational().VolatilitySet
+	switch opts.Volatility {
+	case tree.FunctionImmutable:
+		if vs.HasStable() || vs.HasVolatile() {
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"function declared IMMUTABLE has a %s body", vs))
+		}
+	case tree.FunctionStable:
+		if vs.HasVolatile() {
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"function declared STABLE has a %s body", vs))
+		}
+	}
+
+	// Type checking annotated the placeholders with their types; replace them
+	// with unannotated placeholders, since they are already cast.
+	replaceInSelect(sel, func(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+		if p, ok := expr.(*tree.Placeholder); ok {
+			return false, &tree.Placeholder{Idx: p.Idx}
+		}
+		return true, expr
+	})
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema:   schID,
+			FuncName: &funcName,
+			Syntax:   cf,
+			Body:     tree.AsStringWithFlags(sel, tree.FmtParsable),
+			Deps:     b.viewDeps,
+		},
+	)
+	return outScope
+}
+
+// resolveFunctionType resolves the type of a parameter or of the return value
+// of a user-defined function.
+func (b *Builder) resolveFunctionType(ref tree.ResolvableTypeReference) *types.T {
+	typ, err := tree.ResolveType(b.ctx, ref, b.semaCtx.GetTypeResolver())
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// castParam returns the expression which is used in the body of a
+// user-defined function to refer to the given parameter.
+func castParam(idx tree.PlaceholderIdx, typ *types.T) tree.Expr {
+	return &tree.CastExpr{
+		Expr:       &tree.Placeholder{Idx: idx},
+		Type:       typ,
+		SyntaxMode: tree.CastShort,
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+func (b *Builder) buildCreateTrigger(ct *tree.CreateTrigger, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := ct.Table.ToTableName()
+	tab, resName := b.resolveTable(&tn, privilege.CREATE)
+	if tab.IsVirtualTable() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot create a trigger on virtual table %q", tree.ErrString(&resName)))
+	}
+	md := b.factory.Metadata()
+	tabID := md.AddTable(tab, &resName)
+
+	trigger := cat.Trigger{
+		Name:       string(ct.Name),
+		ActionTime: ct.ActionTime,
+		Events:     ct.Events,
+		Body:       ct.Body,
+	}
+	if ct.When != nil {
+		trigger.When = tree.Serialize(ct.When)
+	}
+
+	// We build the statement for each event which fires the trigger, over an
+	// empty set of rows, to:
+	//  - check the statement and the WHEN condition semantically, and
+	//  - get the fully resolved names into the AST.
+	// The result is not otherwise used.
+	defer func() {
+		b.qualifyDataSourceNamesInAST = false
+	}()
+	b.qualifyDataSourceNamesInAST = true
+
+	tabCols := make(opt.OptionalColList, tab.ColumnCount())
+	for i := range tabCols {
+		tabCols[i] = tabID.ColumnID(i)
+	}
+	var stmt tree.Statement
+	for _, event := range ct.Events {
+		rows := triggerRows{event: event}
+		if event != tree.TriggerInsert {
+			rows.oldCols = tabCols
+		}
+		if event != tree.TriggerDelete {
+			rows.newCols = tabCols
+		}
+		b.pushWithFrame()
+		stmtScope, eventStmt := b.buildTriggerStmt(tab, &trigger, &rows, 0 /* withID */)
+		b.popWithFrame(stmtScope)
+		if stmt == nil {
+			stmt = eventStmt
+		}
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateTrigger(
+		&memo.CreateTriggerPrivate{
+			Table:  tabID,
+			Syntax: ct,
+			When:   trigger.When,
+			Body:   tree.AsStringWithFlags(stmt, tree.FmtParsable),
+		},
+	)
+	return outScope
+}
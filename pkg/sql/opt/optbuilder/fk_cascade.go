@@ -894,5 +894,8 @@ func buildCascadeHelper(
 		}
 	}()
 
-	return fn(b), nil
+	// Statements run by triggers can hoist CTEs.
+	b.pushWithFrame()
+	expr := fn(b)
+	return b.flushCTEs(expr), nil
 }
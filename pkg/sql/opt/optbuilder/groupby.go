@@ -45,6 +45,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,8 +102,68 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping sets of a GROUP BY clause with ROLLUP,
+	// CUBE or GROUPING SETS items, as sets of ordinals of the grouping columns.
+	// It is nil for other GROUP BY clauses. For example:
+	//
+	//   SELECT a, b, count(*) FROM t GROUP BY ROLLUP (a, b)
+	//
+	//   grouping columns: a, b
+	//   grouping sets:    (0,1), (0), ()
+	//
+	groupingSets []util.FastIntSet
+
+	// groupingOutCols contains the columns in the aggOutScope corresponding to
+	// the grouping columns, when there are multiple grouping sets. In that case
+	// the output columns are different from the grouping columns, because they
+	// are NULL in the rows of the grouping sets which don't include them.
+	groupingOutCols opt.ColList
+
+	// groupingOps contains information about the GROUPING operations
+	// encountered.
+	groupingOps []groupingOpInfo
+}
+
+// groupingOpInfo stores information about a GROUPING operation.
+type groupingOpInfo struct {
+	// ords contains the ordinals of the grouping columns corresponding to the
+	// arguments of the operation.
+	ords []int
+
+	// col is the output column of the operation, in the aggOutScope.
+	col opt.ColumnID
 }
 
+// value returns the result of the GROUPING operation for the rows of the given
+// grouping set: a bit mask which has a bit set for each argument which is not
+// part of the grouping set, where the last argument corresponds to the least
+// significant bit.
+func (op *groupingOpInfo) value(groupingSet util.FastIntSet) int64 {
+	var v int64
+	for _, ord := range op.ords {
+		v <<= 1
+		if !groupingSet.Contains(ord) {
+			v |= 1
+		}
+	}
+	return v
+}
+
+const (
+	// maxGroupingArgs is the maximum number of arguments of a GROUPING
+	// operation; this is the same limit as in Postgres.
+	maxGroupingArgs = 31
+
+	// maxCubeElements is the maximum number of elements of a CUBE; this is the
+	// same limit as in Postgres.
+	maxCubeElements = 12
+
+	// maxGroupingSets is the maximum number of grouping sets of a GROUP BY
+	// clause; this is the same limit as in Postgres.
+	maxGroupingSets = 4096
+)
+
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
 // grouping column in an aggOutScope scope that projects that expression. It
 // is used to enforce scoping rules, since any non-aggregate, variable
@@ -154,6 +216,30 @@ func (g *groupby) hasAggregates() bool {
 	return len(g.aggs) > 0
 }
 
+// hasMultipleGroupingSets returns true if the GROUP BY clause has more than
+// one grouping set.
+func (g *groupby) hasMultipleGroupingSets() bool {
+	return len(g.groupingSets) > 1
+}
+
+// groupingColOrdinal returns the ordinal of the grouping column with the given
+// ID; the ID can be either the ID of the grouping column or the ID of the
+// corresponding output column. It returns -1 if there is no such column.
+func (g *groupby) groupingColOrdinal(id opt.ColumnID) int {
+	groupingCols := g.groupingCols()
+	for i := range groupingCols {
+		if groupingCols[i].id == id {
+			return i
+		}
+	}
+	for i, outCol := range g.groupingOutCols {
+		if outCol == id {
+			return i
+		}
+	}
+	return -1
+}
+
 // findAggregate finds the given aggregate among the bound variables
 // in this scope. Returns nil if the aggregate is not found.
 func (g *groupby) findAggregate(agg aggregateInfo) *scopeColumn {
@@ -279,6 +365,40 @@ func (a *aggregateInfo) ResolvedType() *types.T {
 var _ tree.Expr = &aggregateInfo{}
 var _ tree.TypedExpr = &aggregateInfo{}
 
+// groupingInfo replaces a GROUPING operation while the SELECT list, HAVING and
+// ORDER BY expressions are analyzed. See scope.replaceGrouping.
+type groupingInfo struct {
+	*tree.GroupingExpr
+
+	// args contains the type checked arguments of the operation.
+	args []tree.TypedExpr
+}
+
+// Walk is part of the tree.Expr interface.
+func (g *groupingInfo) Walk(v tree.Visitor) tree.Expr {
+	return g
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (g *groupingInfo) TypeCheck(
+	ctx context.Context, semaCtx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	return g, nil
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (g *groupingInfo) Eval(_ *tree.EvalContext) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("groupingInfo must be replaced before evaluation"))
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (g *groupingInfo) ResolvedType() *types.T {
+	return types.Int
+}
+
+var _ tree.Expr = &groupingInfo{}
+var _ tree.TypedExpr = &groupingInfo{}
+
 func (b *Builder) needsAggregation(sel *tree.SelectClause, scope *scope) bool {
 	// We have an aggregation if:
 	//  - we have a GROUP BY, or
@@ -322,6 +442,204 @@ func (b *Builder) constructGroupBy(
 	return b.factory.ConstructGroupBy(input, aggs, &private)
 }
 
+// constructGroupingSets constructs the aggregation for a GROUP BY clause with
+// multiple grouping sets. The input of the aggregation is buffered, and each
+// grouping set is aggregated separately from a scan of the buffer; the results
+// are combined with UNION ALL. For example:
+//
+//   SELECT a, b, count(*), GROUPING (a, b) FROM t GROUP BY ROLLUP (a, b)
+//   =>
+//   WITH buf AS MATERIALIZED (SELECT a, b FROM t)
+//     SELECT a, b, count(*), 0 FROM buf GROUP BY a, b
+//     UNION ALL SELECT a, NULL, count(*), 1 FROM buf GROUP BY a
+//     UNION ALL SELECT NULL, NULL, count(*), 3 FROM buf
+//
+// The output columns are the aggregate columns, the grouping output columns
+// (see groupby.groupingOutCols) and the columns of the GROUPING operations.
+func (b *Builder) constructGroupingSets(
+	g *groupby, aggCols []scopeColumn, ordering opt.Ordering,
+) memo.RelExpr {
+	md := b.factory.Metadata()
+	input := g.aggInScope.expr.(memo.RelExpr)
+	if !input.Relational().OuterCols.Empty() {
+		panic(unimplemented.NewWithIssue(46280,
+			"grouping sets in correlated subqueries are not supported"))
+	}
+
+	// Buffer the input, so that it is computed only once.
+	withID := b.factory.Memo().NextWithID()
+	md.AddWithBinding(withID, input)
+	b.cteStack[len(b.cteStack)-1] = append(b.cteStack[len(b.cteStack)-1], cteSource{
+		id:   withID,
+		expr: input,
+		mtr:  tree.MaterializeClause{Set: true, Materialize: true},
+	})
+
+	// Deduplicate the aggregate columns, like constructGroupBy.
+	var aggColSet opt.ColSet
+	var aggOrds []int
+	outCols := make(opt.ColList, 0, len(aggCols)+len(g.groupingOutCols)+len(g.groupingOps))
+	for i := range aggCols {
+		if id := aggCols[i].id; !aggColSet.Contains(id) {
+			outCols = append(outCols, id)
+			aggColSet.Add(id)
+			aggOrds = append(aggOrds, i)
+		}
+	}
+	outCols = append(outCols, g.groupingOutCols...)
+	for i := range g.groupingOps {
+		outCols = append(outCols, g.groupingOps[i].col)
+	}
+
+	inCols := input.Relational().OutputCols.ToList()
+	groupingCols := g.groupingCols()
+	var result memo.RelExpr
+	var resultCols opt.ColList
+	for setIdx, groupingSet := range g.groupingSets {
+		// Scan the buffer with new columns.
+		scanCols := make(opt.ColList, len(inCols))
+		var colMap opt.ColMap
+		for i, col := range inCols {
+			colMeta := md.ColumnMeta(col)
+			scanCols[i] = md.AddColumn(colMeta.Alias, colMeta.Type)
+			colMap.Set(int(col), int(scanCols[i]))
+		}
+		var branch memo.RelExpr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+			With:    withID,
+			InCols:  inCols,
+			OutCols: scanCols,
+			ID:      md.NextUniqueID(),
+		})
+		remap := func(col opt.ColumnID) opt.ColumnID {
+			to, _ := colMap.Get(int(col))
+			return opt.ColumnID(to)
+		}
+
+		branchCols := make(opt.ColList, 0, len(outCols))
+		aggs := make(memo.AggregationsExpr, len(aggOrds))
+		for i, ord := range aggOrds {
+			col := &aggCols[ord]
+			id := md.AddColumn(string(col.name), col.typ)
+			aggs[i] = b.factory.ConstructAggregationsItem(
+				b.factory.CustomFuncs().RemapCols(col.scalar, colMap), id,
+			)
+			branchCols = append(branchCols, id)
+		}
+
+		// The grouping columns which are not part of the grouping set are NULL.
+		var groupingColSet opt.ColSet
+		var projections memo.ProjectionsExpr
+		for i := range groupingCols {
+			if groupingSet.Contains(i) {
+				col := remap(groupingCols[i].id)
+				groupingColSet.Add(col)
+				branchCols = append(branchCols, col)
+				continue
+			}
+			id := md.AddColumn(string(groupingCols[i].name), groupingCols[i].typ)
+			projections = append(projections, b.factory.ConstructProjectionsItem(
+				b.factory.ConstructNull(groupingCols[i].typ), id,
+			))
+			branchCols = append(branchCols, id)
+		}
+		for i := range g.groupingOps {
+			id := md.AddColumn("grouping", types.Int)
+			value := tree.NewDInt(tree.DInt(g.groupingOps[i].value(groupingSet)))
+			projections = append(projections, b.factory.ConstructProjectionsItem(
+				b.factory.ConstructConstVal(value, types.Int), id,
+			))
+			branchCols = append(branchCols, id)
+		}
+
+		private := memo.GroupingPrivate{GroupingCols: groupingColSet}
+		var branchOrdering opt.Ordering
+		for _, col := range ordering {
+			branchOrdering = append(branchOrdering, opt.MakeOrderingColumnWithNulls(
+				remap(col.ID()), col.Descending(), col.NullsReversed(),
+			))
+		}
+		private.Ordering.FromOrderingWithOptCols(branchOrdering, groupingColSet)
+		if groupingColSet.Empty() {
+			branch = b.factory.ConstructScalarGroupBy(branch, aggs, &private)
+		} else {
+			branch = b.factory.ConstructGroupBy(branch, aggs, &private)
+		}
+		if len(projections) > 0 {
+			branch = b.factory.ConstructProject(branch, projections, branch.Relational().OutputCols)
+		}
+
+		if result == nil {
+			result, resultCols = branch, branchCols
+			continue
+		}
+		unionCols := outCols
+		if setIdx < len(g.groupingSets)-1 {
+			unionCols = make(opt.ColList, len(outCols))
+			for i, col := range outCols {
+				colMeta := md.ColumnMeta(col)
+				unionCols[i] = md.AddColumn(colMeta.Alias, colMeta.Type)
+			}
+		}
+		result = b.factory.ConstructUnionAll(result, branch, &memo.SetPrivate{
+			LeftCols:  resultCols,
+			RightCols: branchCols,
+			OutCols:   unionCols,
+		})
+		resultCols = unionCols
+	}
+	return result
+}
+
+// buildGroupingOp builds the output column of a GROUPING operation, and
+// returns it.
+func (b *Builder) buildGroupingOp(op *groupingInfo, inScope *scope) *scopeColumn {
+	if !inScope.inGroupingContext() {
+		panic(errGroupingArgs)
+	}
+	g := inScope.groupby
+	if g.buildingGroupingCols {
+		panic(pgerror.New(pgcode.Grouping, "GROUPING is not allowed in GROUP BY"))
+	}
+	if inScope.inAgg {
+		panic(pgerror.New(pgcode.Grouping, "aggregate function calls cannot contain GROUPING"))
+	}
+
+	ords := make([]int, len(op.args))
+	for i, arg := range op.args {
+		col, ok := g.groupStrs[symbolicExprStr(arg)]
+		if !ok {
+			panic(errGroupingArgs)
+		}
+		ords[i] = g.groupingColOrdinal(col.id)
+	}
+
+	// Reuse the column of an identical operation, if there is one.
+	for i := range g.groupingOps {
+		if intsEqual(g.groupingOps[i].ords, ords) {
+			return g.aggOutScope.getColumn(g.groupingOps[i].col)
+		}
+	}
+	col := b.synthesizeColumn(g.aggOutScope, "grouping", types.Int, op, nil /* scalar */)
+	g.groupingOps = append(g.groupingOps, groupingOpInfo{ords: ords, col: col.id})
+	return col
+}
+
+var errGroupingArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level")
+
+// intsEqual returns true if the given slices are equal.
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // buildGroupingColumns builds the grouping columns and adds them to the
 // groupby scopes that will be used to build the aggregation expression.
 // Returns the slice of grouping columns.
@@ -334,8 +652,29 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 	// The "from" columns are visible to any grouping expressions.
 	b.buildGroupingList(sel.GroupBy, sel.Exprs, projectionsScope, fromScope)
 
-	// Copy the grouping columns to the aggOutScope.
-	g.aggOutScope.appendColumns(g.groupingCols())
+	// Add the grouping columns to the aggOutScope. The grouping expressions now
+	// refer to the output columns.
+	groupingCols := g.groupingCols()
+	outCols := make(map[opt.ColumnID]*scopeColumn, len(groupingCols))
+	for i := range groupingCols {
+		outCols[groupingCols[i].id] = b.addGroupingOutCol(g, &groupingCols[i])
+	}
+	for str, col := range g.groupStrs {
+		g.groupStrs[str] = outCols[col.id]
+	}
+}
+
+// addGroupingOutCol adds the column corresponding to the given grouping column
+// to the aggOutScope, and returns it. This is the grouping column itself unless
+// there are multiple grouping sets; see groupby.groupingOutCols.
+func (b *Builder) addGroupingOutCol(g *groupby, col *scopeColumn) *scopeColumn {
+	g.aggOutScope.appendColumn(col)
+	outCol := &g.aggOutScope.cols[len(g.aggOutScope.cols)-1]
+	if g.hasMultipleGroupingSets() {
+		outCol.id = b.factory.Metadata().AddColumn(string(col.name), col.typ)
+		g.groupingOutCols = append(g.groupingOutCols, outCol.id)
+	}
+	return outCol
 }
 
 // buildAggregation builds the aggregation operators and constructs the
@@ -354,6 +693,10 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil || len(g.groupingOps) > 0 {
+			panic(unimplemented.NewWithIssue(46280,
+				"ordered aggregates with grouping sets are not supported"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -430,12 +773,30 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+	if g.hasMultipleGroupingSets() {
+		g.aggOutScope.expr = b.constructGroupingSets(g, aggCols, g.aggInScope.ordering)
+	} else {
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+
+		// With a single grouping set, all the GROUPING operations return 0.
+		if len(g.groupingOps) > 0 {
+			projections := make(memo.ProjectionsExpr, len(g.groupingOps))
+			for i := range g.groupingOps {
+				projections[i] = b.factory.ConstructProjectionsItem(
+					b.factory.ConstructConstVal(tree.NewDInt(0), types.Int), g.groupingOps[i].col,
+				)
+			}
+			input := g.aggOutScope.expr.(memo.RelExpr)
+			g.aggOutScope.expr = b.factory.ConstructProject(
+				input, projections, input.Relational().OutputCols,
+			)
+		}
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -507,15 +868,101 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
+	hasGroupingSets := false
 	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		if _, ok := e.(*tree.GroupingSet); ok {
+			hasGroupingSets = true
+			break
+		}
+	}
+	if !hasGroupingSets {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
+	} else {
+		// The grouping sets of the GROUP BY clause are the cross product of the
+		// grouping sets of its items. For example:
+		//   GROUP BY a, ROLLUP (b, c)
+		// has the grouping sets:
+		//   (a, b, c), (a, b), (a)
+		g.groupingSets = []util.FastIntSet{{}}
+		for _, e := range groupBy {
+			itemSets := b.buildGroupingSets(e, selects, projectionsScope, fromScope)
+			if len(g.groupingSets)*len(itemSets) > maxGroupingSets {
+				panic(pgerror.Newf(pgcode.StatementTooComplex,
+					"too many grouping sets present (maximum %d)", maxGroupingSets))
+			}
+			sets := make([]util.FastIntSet, 0, len(g.groupingSets)*len(itemSets))
+			for _, set := range g.groupingSets {
+				for _, itemSet := range itemSets {
+					sets = append(sets, set.Union(itemSet))
+				}
+			}
+			g.groupingSets = sets
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
+// buildGroupingSets builds the grouping columns for the given GROUP BY item
+// and returns its grouping sets, as sets of ordinals of the grouping columns.
+// An item which isn't a ROLLUP, CUBE or GROUPING SETS has a single grouping
+// set.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) []util.FastIntSet {
+	groupingSet, ok := groupBy.(*tree.GroupingSet)
+	if !ok {
+		return []util.FastIntSet{
+			b.buildGrouping(groupBy, selects, projectionsScope, fromScope, fromScope.groupby.aggInScope),
+		}
+	}
+
+	if groupingSet.Type == tree.ExplicitGroupingSet {
+		var sets []util.FastIntSet
+		for _, e := range groupingSet.Exprs {
+			sets = append(sets, b.buildGroupingSets(e, selects, projectionsScope, fromScope)...)
+		}
+		return sets
+	}
+
+	elems := make([]util.FastIntSet, len(groupingSet.Exprs))
+	for i, e := range groupingSet.Exprs {
+		elems[i] = b.buildGrouping(e, selects, projectionsScope, fromScope, fromScope.groupby.aggInScope)
+	}
+	var sets []util.FastIntSet
+	switch groupingSet.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b, c) has the grouping sets (a, b, c), (a, b), (a), ().
+		sets = make([]util.FastIntSet, len(elems)+1)
+		for i := len(elems) - 1; i >= 0; i-- {
+			sets[i] = sets[i+1].Union(elems[len(elems)-1-i])
+		}
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) has the grouping sets (a, b), (b), (a), ().
+		if len(elems) > maxCubeElements {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		sets = make([]util.FastIntSet, 0, 1<<len(elems))
+		for mask := 1<<len(elems) - 1; mask >= 0; mask-- {
+			var set util.FastIntSet
+			for i := range elems {
+				if mask&(1<<i) != 0 {
+					set.UnionWith(elems[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the ordinals of the grouping columns
+// for the expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +976,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (ords util.FastIntSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -627,7 +1074,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			ords.Add(fromScope.groupby.groupingColOrdinal(col.id))
 			continue
 		}
 
@@ -637,7 +1085,9 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(alias, e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		ords.Add(len(fromScope.groupby.groupStrs) - 1)
 	}
+	return ords
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -903,6 +1353,11 @@ func newGroupingError(name *tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The column would be NULL in the grouping sets which don't include the
+		// PK columns.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
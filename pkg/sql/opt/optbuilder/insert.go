@@ -287,11 +287,11 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 
 	// Case 2: INSERT..ON CONFLICT DO NOTHING.
 	case ins.OnConflict.DoNothing:
-		// Wrap the input in one ANTI JOIN per UNIQUE index, and filter out rows
-		// that have conflicts. See the buildInputForDoNothing comment for more
+		// Wrap the input in one ANTI JOIN per arbiter, and filter out rows that
+		// have conflicts. See the buildInputForDoNothing comment for more
 		// details.
-		conflictOrds := mb.mapPublicColumnNamesToOrdinals(ins.OnConflict.Columns)
-		mb.buildInputForDoNothing(inScope, conflictOrds, ins.OnConflict.ArbiterPredicate)
+		arbiterIndexes, arbiterConstraints := mb.onConflictArbiters(ins.OnConflict)
+		mb.buildInputForDoNothing(inScope, arbiterIndexes, arbiterConstraints)
 
 		// Since buildInputForDoNothing filters out rows with conflicts, always
 		// insert rows that are not filtered.
@@ -309,7 +309,10 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 			// Left-join each input row to the target table, using conflict columns
 			// derived from the primary index as the join condition.
 			primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
-			mb.buildInputForUpsert(inScope, primaryOrds, nil /* arbiterPredicate */, nil /* whereClause */)
+			arbiterIndexes := mb.arbiterIndexes(primaryOrds, nil /* arbiterPredicate */)
+			mb.buildInputForUpsert(
+				inScope, arbiterIndexes, util.FastIntSet{} /* arbiterConstraints */, nil, /* whereClause */
+			)
 
 			// Add additional columns for computed expressions that may depend on any
 			// updated columns, as well as mutation columns with default values.
@@ -323,8 +326,8 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	default:
 		// Left-join each input row to the target table, using the conflict columns
 		// as the join condition.
-		conflictOrds := mb.mapPublicColumnNamesToOrdinals(ins.OnConflict.Columns)
-		mb.buildInputForUpsert(inScope, conflictOrds, ins.OnConflict.ArbiterPredicate, ins.OnConflict.Where)
+		arbiterIndexes, arbiterConstraints := mb.onConflictArbiters(ins.OnConflict)
+		mb.buildInputForUpsert(inScope, arbiterIndexes, arbiterConstraints, ins.OnConflict.Where)
 
 		// Derive the columns that will be updated from the SET expressions.
 		mb.addTargetColsForUpdate(ins.OnConflict.Exprs)
@@ -350,6 +353,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //      values specified for them.
 //   3. Each update value is the same as the corresponding insert value.
 //   4. There are no inbound foreign keys containing non-key columns.
+//   5. There are no triggers, which need the old values of updated rows.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -361,6 +365,11 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	// #5: Triggers need the old values.
+	if mb.tab.TriggerCount() > 0 {
+		return true
+	}
+
 	// Key columns are never updated and are assumed to be the same as the insert
 	// values.
 	// TODO(andyk): This is not true in the case of composite key encodings. See
@@ -645,6 +654,8 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
 
+	mb.buildBeforeTriggers(mb.insertTriggerRows())
+
 	// Keep a reference to the scope before the check constraint columns are
 	// projected. We use this scope when projecting the partial index put
 	// columns because the check columns are not in-scope for those expressions.
@@ -657,9 +668,12 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 	mb.projectPartialIndexPutCols(preCheckScope)
 
 	mb.buildUniqueChecksForInsert()
+	mb.buildExclusionChecksForInsert()
 
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(mb.insertTriggerRows())
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -669,14 +683,13 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 }
 
 // buildInputForDoNothing wraps the input expression in ANTI JOIN expressions,
-// one for each UNIQUE index on the target table. See the comment header for
-// Builder.buildInsert for an example.
+// one for each arbiter index and arbiter constraint on the target table. See
+// the comment header for Builder.buildInsert for an example.
 func (mb *mutationBuilder) buildInputForDoNothing(
-	inScope *scope, conflictOrds util.FastIntSet, arbiterPredicate tree.Expr,
+	inScope *scope, arbiterIndexes, arbiterConstraints util.FastIntSet,
 ) {
-	// Determine the set of arbiter indexes to use to check for conflicts.
-	arbiterIndexes := mb.arbiterIndexes(conflictOrds, arbiterPredicate)
 	mb.arbiters = arbiterIndexes.Ordered()
+	mb.arbiterConstraints = arbiterConstraints.Ordered()
 
 	insertColScope := mb.outScope.replace()
 	insertColScope.appendColumnsFromScope(mb.outScope)
@@ -765,6 +778,41 @@ func (mb *mutationBuilder) buildInputForDoNothing(
 		)
 	}
 
+	// Loop over each arbiter constraint, creating an anti-join for each one.
+	// These are built just like the anti-joins for arbiter indexes, except that
+	// UNIQUE WITHOUT INDEX constraints are never partial.
+	for uc, ok := arbiterConstraints.Next(0); ok; uc, ok = arbiterConstraints.Next(uc + 1) {
+		fetchScope := mb.b.buildScan(
+			mb.b.addTable(mb.tab, &mb.alias),
+			tableOrdinals(mb.tab, columnKinds{
+				includeMutations:       false,
+				includeSystem:          false,
+				includeVirtualInverted: false,
+				includeVirtualComputed: false,
+			}),
+			nil, /* indexFlags */
+			noRowLocking,
+			inScope,
+		)
+
+		conflictOrds := getUniqueConstraintOrdinals(mb.tab, mb.tab.Unique(uc))
+		var on memo.FiltersExpr
+		for ord, ok := conflictOrds.Next(0); ok; ord, ok = conflictOrds.Next(ord + 1) {
+			condition := mb.b.factory.ConstructEq(
+				mb.b.factory.ConstructVariable(mb.insertColIDs[ord]),
+				mb.b.factory.ConstructVariable(fetchScope.cols[ord].id),
+			)
+			on = append(on, mb.b.factory.ConstructFiltersItem(condition))
+		}
+
+		mb.outScope.expr = mb.b.factory.ConstructAntiJoin(
+			mb.outScope.expr,
+			fetchScope.expr,
+			on,
+			memo.EmptyJoinPrivate,
+		)
+	}
+
 	// Loop over each arbiter index, creating an upsert-distinct-on for each one.
 	// This must happen after all conflicting rows are removed with the anti-joins
 	// created above, to avoid removing valid rows (see #59125).
@@ -812,6 +860,17 @@ func (mb *mutationBuilder) buildInputForDoNothing(
 		}
 	}
 
+	// Similarly, add an UpsertDistinctOn operator for each arbiter constraint.
+	for uc, ok := arbiterConstraints.Next(0); ok; uc, ok = arbiterConstraints.Next(uc + 1) {
+		conflictOrds := getUniqueConstraintOrdinals(mb.tab, mb.tab.Unique(uc))
+		var conflictCols opt.ColSet
+		for ord, ok := conflictOrds.Next(0); ok; ord, ok = conflictOrds.Next(ord + 1) {
+			conflictCols.Add(mb.insertColIDs[ord])
+		}
+		mb.outScope = mb.b.buildDistinctOn(
+			conflictCols, mb.outScope, true /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
+
 	mb.targetColList = make(opt.ColList, 0, mb.tab.ColumnCount())
 	mb.targetColSet = opt.ColSet{}
 }
@@ -823,15 +882,14 @@ func (mb *mutationBuilder) buildInputForDoNothing(
 // given insert row conflicts with an existing row in the table. If it is null,
 // then there is no conflict.
 func (mb *mutationBuilder) buildInputForUpsert(
-	inScope *scope, conflictOrds util.FastIntSet, arbiterPredicate tree.Expr, whereClause *tree.Where,
+	inScope *scope, arbiterIndexes, arbiterConstraints util.FastIntSet, whereClause *tree.Where,
 ) {
-	// Determine the set of arbiter indexes to use to check for conflicts.
-	arbiterIndexes := mb.arbiterIndexes(conflictOrds, arbiterPredicate)
 	mb.arbiters = arbiterIndexes.Ordered()
+	mb.arbiterConstraints = arbiterConstraints.Ordered()
 
 	// TODO(mgartner): Add support for multiple arbiter indexes, similar to
 	// buildInputForDoNothing.
-	if arbiterIndexes.Len() > 1 {
+	if arbiterIndexes.Len()+arbiterConstraints.Len() > 1 {
 		panic(unimplemented.NewWithIssue(53170,
 			"there are multiple unique or exclusion constraints matching the ON CONFLICT specification"))
 	}
@@ -842,22 +900,34 @@ func (mb *mutationBuilder) buildInputForUpsert(
 	// Ignore any ordering requested by the input.
 	mb.outScope.ordering = nil
 
-	idx, _ := arbiterIndexes.Next(0)
-	index := mb.tab.Index(idx)
-
-	_, isPartial := index.Predicate()
+	// Determine the conflict columns and the canary column from the arbiter.
+	var conflictOrds util.FastIntSet
+	var canaryOrd int
+	var isPartial bool
 	var predExpr tree.Expr
-	if isPartial {
-		predExpr = mb.parsePartialIndexPredicateExpr(idx)
-	}
-
-	// If the index is a partial index, project a new column that allows the
-	// UpsertDistinctOn to only de-duplicate insert rows that satisfy the
-	// partial index predicate. See projectPartialIndexDistinctColumn for more
-	// details.
 	var partialIndexDistinctCol *scopeColumn
-	if isPartial {
-		partialIndexDistinctCol = mb.projectPartialIndexDistinctColumn(insertColScope, idx)
+	if idx, ok := arbiterIndexes.Next(0); ok {
+		index := mb.tab.Index(idx)
+		conflictOrds = getIndexLaxKeyOrdinals(index)
+		canaryOrd = findNotNullIndexCol(index)
+
+		_, isPartial = index.Predicate()
+		if isPartial {
+			predExpr = mb.parsePartialIndexPredicateExpr(idx)
+
+			// If the index is a partial index, project a new column that allows
+			// the UpsertDistinctOn to only de-duplicate insert rows that satisfy
+			// the partial index predicate. See projectPartialIndexDistinctColumn
+			// for more details.
+			partialIndexDistinctCol = mb.projectPartialIndexDistinctColumn(insertColScope, idx)
+		}
+	} else {
+		uc, _ := arbiterConstraints.Next(0)
+		conflictOrds = getUniqueConstraintOrdinals(mb.tab, mb.tab.Unique(uc))
+
+		// A UNIQUE WITHOUT INDEX constraint has no index of its own, so use a
+		// not-null column of the primary index as the canary.
+		canaryOrd = findNotNullIndexCol(mb.tab.Index(cat.PrimaryIndex))
 	}
 
 	// Ensure that input is distinct on the conflict columns. Otherwise, the
@@ -928,7 +998,7 @@ func (mb *mutationBuilder) buildInputForUpsert(
 	// Record a not-null "canary" column. After the left-join, this will be null
 	// if no conflict has been detected, or not null otherwise. At least one not-
 	// null column must exist, since primary key columns are not-null.
-	canaryScopeCol := &mb.fetchScope.cols[findNotNullIndexCol(index)]
+	canaryScopeCol := &mb.fetchScope.cols[canaryOrd]
 	mb.canaryColID = canaryScopeCol.id
 
 	// Set fetchColIDs to reference the columns created for the fetch values.
@@ -1053,6 +1123,8 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
 
+	mb.buildBeforeTriggers(mb.insertTriggerRows(), mb.updateTriggerRows())
+
 	// Keep a reference to the scope before the check constraint columns are
 	// projected. We use this scope when projecting the partial index put
 	// columns because the check columns are not in-scope for those expressions.
@@ -1080,9 +1152,12 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 	}
 
 	mb.buildUniqueChecksForUpsert()
+	mb.buildExclusionChecksForUpsert()
 
 	mb.buildFKChecksForUpsert()
 
+	mb.buildAfterTriggers(mb.insertTriggerRows(), mb.updateTriggerRows())
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -1175,6 +1250,60 @@ func (mb *mutationBuilder) projectUpsertColumns() {
 	mb.outScope = projectionsScope
 }
 
+// onConflictArbiters returns the sets of index ordinals and UNIQUE WITHOUT
+// INDEX constraint ordinals to be used as arbiters for the given ON CONFLICT
+// clause. See arbiterIndexes and constraintArbiters for more details.
+func (mb *mutationBuilder) onConflictArbiters(
+	onConflict *tree.OnConflict,
+) (arbiterIndexes, arbiterConstraints util.FastIntSet) {
+	if onConflict.Constraint != "" {
+		return mb.constraintArbiters(onConflict.Constraint)
+	}
+	conflictOrds := mb.mapPublicColumnNamesToOrdinals(onConflict.Columns)
+	return mb.arbiterIndexes(conflictOrds, onConflict.ArbiterPredicate), util.FastIntSet{}
+}
+
+// constraintArbiters returns the arbiter for an ON CONFLICT ON CONSTRAINT
+// clause. The named constraint is either a unique index, in which case it is
+// returned in arbiterIndexes, or a UNIQUE WITHOUT INDEX constraint, in which
+// case it is returned in arbiterConstraints. The primary index can be named as
+// well. This function panics if no such constraint exists.
+//
+// As in Postgres, deferrable constraints cannot be arbiters, because they
+// could be violated by existing rows. For the same reason, unvalidated
+// constraints cannot be arbiters either.
+func (mb *mutationBuilder) constraintArbiters(
+	name tree.Name,
+) (arbiterIndexes, arbiterConstraints util.FastIntSet) {
+	for idx, idxCount := 0, mb.tab.IndexCount(); idx < idxCount; idx++ {
+		index := mb.tab.Index(idx)
+		if index.IsUnique() && index.Name() == name {
+			arbiterIndexes.Add(idx)
+			return arbiterIndexes, arbiterConstraints
+		}
+	}
+
+	for uc, ucCount := 0, mb.tab.UniqueCount(); uc < ucCount; uc++ {
+		u := mb.tab.Unique(uc)
+		if !u.WithoutIndex() || u.Name() != string(name) {
+			continue
+		}
+		if u.Deferrable() {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"ON CONFLICT does not support deferrable unique constraints/exclusion constraints as arbiters"))
+		}
+		if !u.Validated() {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"ON CONFLICT does not support unvalidated unique constraints as arbiters"))
+		}
+		arbiterConstraints.Add(uc)
+		return arbiterIndexes, arbiterConstraints
+	}
+
+	panic(pgerror.Newf(pgcode.UndefinedObject,
+		"constraint %q for table %q does not exist", string(name), string(mb.tab.Name())))
+}
+
 // arbiterIndexes returns the set of index ordinals to be used as arbiter
 // indexes for an INSERT ON CONFLICT statement. This function panics if no
 // arbiter indexes are found.
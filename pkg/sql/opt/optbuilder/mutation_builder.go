@@ -136,6 +136,11 @@ type mutationBuilder struct {
 	// for UPSERT and INSERT ON CONFLICT statements.
 	arbiters cat.IndexOrdinals
 
+	// arbiterConstraints stores the ordinals of UNIQUE WITHOUT INDEX
+	// constraints that are used to detect conflicts for INSERT ON CONFLICT ON
+	// CONSTRAINT statements.
+	arbiterConstraints cat.UniqueOrdinals
+
 	// roundedDecimalCols is the set of columns that have already been rounded.
 	// Keeping this set avoids rounding the same column multiple times.
 	roundedDecimalCols opt.ColSet
@@ -323,25 +328,33 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Build a distinct on to ensure there is at most one row in the joined output
 	// for every row in the table.
 	if fromClausePresent {
-		var pkCols opt.ColSet
-
-		// We need to ensure that the join has a maximum of one row for every row
-		// in the table and we ensure this by constructing a distinct on the primary
-		// key columns.
-		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
-		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
-			// If the primary key column is hidden, then we don't need to use it
-			// for the distinct on.
-			if col := primaryIndex.Column(i); !col.IsHidden() {
-				pkCols.Add(mb.fetchColIDs[col.Ordinal()])
-			}
-		}
+		mb.buildDistinctOnPrimaryKey()
+	}
+}
 
-		if !pkCols.Empty() {
-			mb.outScope = mb.b.buildDistinctOn(
-				pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+// buildDistinctOnPrimaryKey wraps the input expression of an UPDATE ... FROM or
+// DELETE ... USING statement in a distinct on the primary key columns of the
+// target table. This ensures that the join has a maximum of one row for every
+// row in the table.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		// If the primary key column is hidden, then we don't need to use it
+		// for the distinct on.
+		if col := primaryIndex.Column(i); !col.IsHidden() {
+			pkCols.Add(mb.fetchColIDs[col.Ordinal()])
 		}
 	}
+
+	if !pkCols.Empty() {
+		// Any ORDER BY has already been used to apply the LIMIT, and mutations
+		// make no guarantees about the order in which rows are processed, so the
+		// ordering does not need to be compatible with the distinct on.
+		mb.outScope.ordering = nil
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
 }
 
 // buildInputForDelete constructs a Select expression from the fields in
@@ -354,9 +367,17 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+// If a USING clause is defined, we build out each of the table
+// expressions required and JOIN them together with the table being
+// deleted from, as for the FROM clause of an UPDATE.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -387,6 +408,35 @@ func (mb *mutationBuilder) buildInputForDelete(
 	)
 	mb.outScope = mb.fetchScope
 
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		usingScope := mb.b.buildFromTables(using, noRowLocking, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.fetchScope, usingScope)
+
+		// The USING table columns can be accessed by the RETURNING clause of the
+		// query and so we have to make them accessible.
+		mb.extraAccessibleCols = usingScope.cols
+
+		// Add the columns in the USING scope. Unlike UPDATE ... FROM, a new
+		// scope is used so that fetchScope only contains columns of the target
+		// table. It is used later to build partial index predicates, which must
+		// not be ambiguous with columns in the USING tables.
+		mb.outScope = mb.fetchScope.replace()
+		mb.outScope.appendColumnsFromScope(mb.fetchScope)
+		mb.outScope.appendColumnsFromScope(usingScope)
+
+		left := mb.fetchScope.expr.(memo.RelExpr)
+		right := usingScope.expr.(memo.RelExpr)
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+	}
+
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
 
@@ -404,8 +454,11 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
+	// Build a distinct on to ensure there is at most one row in the joined output
+	// for every row in the table.
+	if usingClausePresent {
+		mb.buildDistinctOnPrimaryKey()
+	}
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -995,6 +1048,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		UpdateCols:          checkEmptyList(mb.updateColIDs),
 		CanaryCol:           mb.canaryColID,
 		Arbiters:            mb.arbiters,
+		ArbiterConstraints:  mb.arbiterConstraints,
 		CheckCols:           checkEmptyList(mb.checkColIDs),
 		PartialIndexPutCols: checkEmptyList(mb.partialIndexPutColIDs),
 		PartialIndexDelCols: checkEmptyList(mb.partialIndexDelColIDs),
@@ -1196,6 +1250,16 @@ func getIndexLaxKeyOrdinals(index cat.Index) util.FastIntSet {
 	return keyOrds
 }
 
+// getUniqueConstraintOrdinals returns the ordinals of all columns in the given
+// unique constraint. Ordinals are relative to the owner table.
+func getUniqueConstraintOrdinals(tab cat.Table, uc cat.UniqueConstraint) util.FastIntSet {
+	var ucOrds util.FastIntSet
+	for i, n := 0, uc.ColumnCount(); i < n; i++ {
+		ucOrds.Add(uc.ColumnOrdinal(tab, i))
+	}
+	return ucOrds
+}
+
 // findNotNullIndexCol finds the first not-null column in the given index and
 // returns its ordinal position in the owner table. There must always be such a
 // column, even if it turns out to be an implicit primary key column.
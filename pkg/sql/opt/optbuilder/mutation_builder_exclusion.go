@@ -0,0 +1,230 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// buildExclusionChecksForInsert builds exclusion check queries for an insert.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForInsert() {
+	if mb.tab.ExclusionCount() == 0 {
+		return
+	}
+
+	mb.ensureWithID()
+	var h exclusionCheckHelper
+
+	for i, n := 0, mb.tab.ExclusionCount(); i < n; i++ {
+		if h.init(mb, i) {
+			mb.uniqueChecks = append(mb.uniqueChecks, h.buildInsertionCheck())
+		}
+	}
+}
+
+// buildExclusionChecksForUpdate builds exclusion check queries for an update.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpdate() {
+	if mb.tab.ExclusionCount() == 0 {
+		return
+	}
+
+	mb.ensureWithID()
+	var h exclusionCheckHelper
+
+	for i, n := 0, mb.tab.ExclusionCount(); i < n; i++ {
+		// If this constraint doesn't include the updated columns we don't need to
+		// plan a check.
+		if mb.exclusionColsUpdated(i) && h.init(mb, i) {
+			// As with unique checks, the insertion check works for updates too since
+			// it simply checks that the newly inserted or updated rows do not
+			// conflict with any existing rows.
+			mb.uniqueChecks = append(mb.uniqueChecks, h.buildInsertionCheck())
+		}
+	}
+}
+
+// buildExclusionChecksForUpsert builds exclusion check queries for an upsert.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpsert() {
+	// Upserts check newly inserted and updated rows in the same way as inserts.
+	mb.buildExclusionChecksForInsert()
+}
+
+// exclusionColsUpdated returns true if any of the columns for an exclusion
+// constraint are being updated (according to updateColIDs).
+func (mb *mutationBuilder) exclusionColsUpdated(exclusionOrdinal int) bool {
+	ec := mb.tab.Exclusion(exclusionOrdinal)
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if ord := ec.ColumnOrdinal(mb.tab, i); mb.updateColIDs[ord] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// exclusionCheckHelper is a type associated with a single exclusion constraint
+// and is used to build the semi-join that finds conflicting rows.
+type exclusionCheckHelper struct {
+	mb *mutationBuilder
+
+	exclusion        cat.ExclusionConstraint
+	exclusionOrdinal int
+
+	// exclusionOrdinals are the table ordinals of the constrained columns in
+	// the table that is being mutated. They correspond 1-to-1 to the columns in
+	// the ExclusionConstraint.
+	exclusionOrdinals []int
+
+	// exclusionAndPrimaryKeyOrdinals includes all the ordinals from
+	// exclusionOrdinals, plus the ordinals from any primary key columns that are
+	// not already included in exclusionOrdinals.
+	exclusionAndPrimaryKeyOrdinals []int
+
+	// primaryKeyPositions are the positions of the primary key columns within
+	// exclusionAndPrimaryKeyOrdinals.
+	primaryKeyPositions []int
+}
+
+// init initializes the helper with an exclusion constraint.
+//
+// Returns false if the constraint should be ignored (e.g. because the new
+// values for one of the constrained columns are known to be always NULL).
+func (h *exclusionCheckHelper) init(mb *mutationBuilder, exclusionOrdinal int) bool {
+	*h = exclusionCheckHelper{
+		mb:               mb,
+		exclusion:        mb.tab.Exclusion(exclusionOrdinal),
+		exclusionOrdinal: exclusionOrdinal,
+	}
+
+	exclusionCount := h.exclusion.ColumnCount()
+
+	var exclusionOrds util.FastIntSet
+	h.exclusionOrdinals = make([]int, exclusionCount)
+	for i := 0; i < exclusionCount; i++ {
+		h.exclusionOrdinals[i] = h.exclusion.ColumnOrdinal(mb.tab, i)
+		exclusionOrds.Add(h.exclusionOrdinals[i])
+	}
+
+	// Unlike unique checks, the primary key columns are always needed to prevent
+	// rows from conflicting with themselves, since the constraint's operators
+	// need not be equalities. Primary key columns that are also constrained are
+	// not scanned twice.
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	h.exclusionAndPrimaryKeyOrdinals = append(h.exclusionOrdinals, primaryOrds.Difference(exclusionOrds).Ordered()...)
+	h.exclusionOrdinals = h.exclusionAndPrimaryKeyOrdinals[:exclusionCount]
+	for i, ord := range h.exclusionAndPrimaryKeyOrdinals {
+		if primaryOrds.Contains(ord) {
+			h.primaryKeyPositions = append(h.primaryKeyPositions, i)
+		}
+	}
+
+	// If at least one constrained column is getting a NULL value, no comparison
+	// can be true, so the exclusion check is not needed.
+	for _, tabOrd := range h.exclusionOrdinals {
+		colID := mb.mapToReturnColID(tabOrd)
+		if memo.OutputColumnIsAlwaysNull(mb.outScope.expr, colID) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildInsertionCheck creates an exclusion check for rows which are added to a
+// table. The input to the insertion check will be produced from the input to
+// the mutation operator.
+func (h *exclusionCheckHelper) buildInsertionCheck() memo.UniqueChecksItem {
+	checkInput, withScanCols, _ := h.mb.makeCheckInputScan(
+		checkInputScanNewVals, h.exclusionAndPrimaryKeyOrdinals,
+	)
+
+	f := h.mb.b.factory
+
+	// Build a self semi-join, with the new values on the left and the
+	// existing values on the right.
+	tabMeta := h.mb.b.addTable(h.mb.tab, tree.NewUnqualifiedTableName(h.mb.tab.Name()))
+	scanScope := h.mb.b.buildScan(
+		tabMeta,
+		h.exclusionAndPrimaryKeyOrdinals,
+		nil, /* indexFlags */
+		noRowLocking,
+		h.mb.b.allocScope(),
+	)
+
+	// Build the join filters:
+	//   (new_a op_a existing_a) AND (new_b op_b existing_b) AND ...
+	semiJoinFilters := make(memo.FiltersExpr, 0, len(h.exclusionOrdinals)+1)
+	for i := 0; i < len(h.exclusionOrdinals); i++ {
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(
+			h.constructExclusionComparison(
+				h.exclusion.Operator(i),
+				f.ConstructVariable(withScanCols[i]),
+				f.ConstructVariable(scanScope.cols[i].id),
+			),
+		))
+	}
+
+	// Prevent rows from conflicting with themselves in the semi join:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	var pkFilter opt.ScalarExpr
+	for _, i := range h.primaryKeyPositions {
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(withScanCols[i]),
+			f.ConstructVariable(scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(pkFilter))
+
+	semiJoin := f.ConstructSemiJoin(checkInput, scanScope.expr, semiJoinFilters, memo.EmptyJoinPrivate)
+
+	return f.ConstructUniqueChecksItem(semiJoin, &memo.UniqueChecksItemPrivate{
+		Table:        h.mb.tabID,
+		CheckOrdinal: h.exclusionOrdinal,
+		Exclusion:    true,
+		KeyCols:      withScanCols[:len(h.exclusionOrdinals)],
+		OpName:       h.mb.opName,
+	})
+}
+
+// constructExclusionComparison builds the scalar expression for one of the
+// operators allowed in an exclusion constraint.
+func (h *exclusionCheckHelper) constructExclusionComparison(
+	op tree.ComparisonOperator, left, right opt.ScalarExpr,
+) opt.ScalarExpr {
+	f := h.mb.b.factory
+	switch op {
+	case tree.EQ:
+		return f.ConstructEq(left, right)
+	case tree.NE:
+		return f.ConstructNe(left, right)
+	case tree.Overlaps:
+		leftFam, rightFam := left.DataType().Family(), right.DataType().Family()
+		if (leftFam == types.GeometryFamily || leftFam == types.Box2DFamily) &&
+			(rightFam == types.GeometryFamily || rightFam == types.Box2DFamily) {
+			return f.ConstructBBoxIntersects(left, right)
+		}
+		return f.ConstructOverlaps(left, right)
+	}
+	panic(errors.AssertionFailedf("unhandled exclusion operator: %s", log.Safe(op)))
+}
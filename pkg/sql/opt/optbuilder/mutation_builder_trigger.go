@@ -0,0 +1,593 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// This file contains the methods which build the statements run by row-level
+// triggers.
+//
+// A trigger runs a statement for each row which is inserted, updated or
+// deleted by a statement that fires it. The statement refers to the values of
+// the row as new.<column> and old.<column>. It is built on top of a data source,
+// the "trigger rows", which has a row for each modified row and a column for
+// each of these values (see buildTriggerRows). The statement is rewritten so
+// that it runs once for all of the trigger rows (see rewriteTriggerStmt). For
+// example, with trigger rows tr:
+//
+//   INSERT INTO log VALUES (new.k, new.v)
+//     => INSERT INTO log SELECT new.k, new.v FROM tr
+//
+//   UPDATE counts SET n = n + 1 WHERE counts.k = new.k
+//     => UPDATE counts SET n = n + 1 FROM tr WHERE counts.k = new.k
+//
+//   DELETE FROM counts WHERE counts.k = old.k
+//     => DELETE FROM counts WHERE EXISTS (SELECT 1 FROM tr WHERE counts.k = old.k)
+//
+// BEFORE triggers are built together with the statement which fires them. The
+// input of the mutation is buffered in a CTE, and the trigger statements are
+// hoisted into CTEs which run before the mutation:
+//
+//   with &1 (mutation input)
+//    ├── <mutation input>
+//    └── with &2
+//         ├── <trigger statement, reading the trigger rows from &1>
+//         └── insert t
+//              └── with-scan &1
+//
+// AFTER triggers are planned as cascades, which run after the mutation (and
+// after its foreign key cascades) in the same way as foreign key actions (see
+// triggerCascadeBuilder). Cycles of AFTER triggers are therefore bounded by the
+// optimizer_foreign_key_cascades_limit setting.
+
+// maxBeforeTriggerDepth is the maximum nesting depth of BEFORE triggers, which
+// are built together with the statement that fires them.
+const maxBeforeTriggerDepth = 16
+
+// triggerRowsSource is the data source which produces the trigger rows while
+// the statement run by a trigger is being built.
+type triggerRowsSource struct {
+	// name is the table name which refers to the trigger rows in the rewritten
+	// statement. It is matched by pointer, so it cannot clash with the names in
+	// the original statement.
+	name  *tree.TableName
+	build func(inScope *scope) *scope
+}
+
+// triggerRows describes the rows which fire a trigger.
+type triggerRows struct {
+	event tree.TriggerEvent
+
+	// oldCols and newCols are the columns of the mutation input which hold the
+	// old and new values of each table column. Either can be nil.
+	oldCols, newCols opt.OptionalColList
+
+	// canaryCol is the canary column of an upsert (see
+	// mutationBuilder.canaryColID), or 0. If set, only the inserted rows (for
+	// the INSERT event) or the updated rows (for the UPDATE event) fire the
+	// trigger.
+	canaryCol opt.ColumnID
+}
+
+// buildBeforeTriggers builds the statements run by the BEFORE triggers of the
+// target table that fire on the given rows, and hoists them into CTEs which run
+// before the mutation. It must be called after the mutation input has been
+// fully built, and before any of the check, partial index, unique and foreign
+// key columns are added to it.
+func (mb *mutationBuilder) buildBeforeTriggers(rows ...triggerRows) {
+	var triggers []int
+	var triggerRowsIdx []int
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime != tree.TriggerBefore {
+			continue
+		}
+		for j := range rows {
+			if trigger.FiresOn(rows[j].event) {
+				triggers = append(triggers, i)
+				triggerRowsIdx = append(triggerRowsIdx, j)
+			}
+		}
+	}
+	if len(triggers) == 0 {
+		return
+	}
+
+	b := mb.b
+	if b.triggerDepth >= maxBeforeTriggerDepth {
+		panic(pgerror.Newf(pgcode.TriggeredActionException,
+			"BEFORE triggers nested too deeply (limit %d)", maxBeforeTriggerDepth))
+	}
+	b.triggerDepth++
+	defer func() { b.triggerDepth-- }()
+
+	// Buffer the mutation input.
+	input := mb.outScope.expr
+	withID := b.factory.Memo().NextWithID()
+	mb.md.AddWithBinding(withID, input)
+	b.cteStack[len(b.cteStack)-1] = append(b.cteStack[len(b.cteStack)-1], cteSource{
+		id:   withID,
+		expr: input,
+		mtr:  tree.MaterializeClause{Set: true, Materialize: true},
+	})
+
+	// Build the trigger statements, in the order in which they fire.
+	for i, ord := range triggers {
+		trigger := mb.tab.Trigger(ord)
+		stmtScope, stmt := b.buildTriggerStmt(mb.tab, &trigger, &rows[triggerRowsIdx[i]], withID)
+		id := b.factory.Memo().NextWithID()
+		mb.md.AddWithBinding(id, stmtScope.expr)
+		b.cteStack[len(b.cteStack)-1] = append(b.cteStack[len(b.cteStack)-1], cteSource{
+			id:           id,
+			name:         tree.AliasClause{Alias: tree.Name(trigger.Name)},
+			originalExpr: stmt,
+			expr:         stmtScope.expr,
+		})
+	}
+
+	// Replace the mutation input with a scan of the buffer. The columns of the
+	// scan are new, so all the references to the input columns are remapped.
+	inCols := input.Relational().OutputCols.ToList()
+	outCols := make(opt.ColList, len(inCols))
+	var colMap opt.ColMap
+	for i, col := range inCols {
+		colMeta := mb.md.ColumnMeta(col)
+		outCols[i] = mb.md.AddColumn(colMeta.Alias, colMeta.Type)
+		colMap.Set(int(col), int(outCols[i]))
+	}
+	mb.outScope.expr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    withID,
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      mb.md.NextUniqueID(),
+	})
+
+	remapCol := func(col opt.ColumnID) opt.ColumnID {
+		if to, ok := colMap.Get(int(col)); ok {
+			return opt.ColumnID(to)
+		}
+		return col
+	}
+	remapCols := func(cols opt.OptionalColList) {
+		for i := range cols {
+			cols[i] = remapCol(cols[i])
+		}
+	}
+	remapScopeCols := func(cols []scopeColumn) {
+		for i := range cols {
+			cols[i].id = remapCol(cols[i].id)
+			cols[i].scalar = nil
+		}
+	}
+	remapCols(mb.insertColIDs)
+	remapCols(mb.fetchColIDs)
+	remapCols(mb.updateColIDs)
+	remapCols(mb.upsertColIDs)
+	mb.canaryColID = remapCol(mb.canaryColID)
+	var roundedDecimalCols opt.ColSet
+	mb.roundedDecimalCols.ForEach(func(col opt.ColumnID) {
+		roundedDecimalCols.Add(remapCol(col))
+	})
+	mb.roundedDecimalCols = roundedDecimalCols
+	remapScopeCols(mb.outScope.cols)
+	remapScopeCols(mb.extraAccessibleCols)
+	if mb.fetchScope != nil {
+		remapScopeCols(mb.fetchScope.cols)
+	}
+	for i := range mb.outScope.ordering {
+		col := mb.outScope.ordering[i]
+		mb.outScope.ordering[i] = opt.MakeOrderingColumnWithNulls(
+			remapCol(col.ID()), col.Descending(), col.NullsReversed(),
+		)
+	}
+}
+
+// buildAfterTriggers adds a cascade for each AFTER trigger of the target table
+// which fires on the given rows. The cascades run the trigger statements after
+// the mutation.
+func (mb *mutationBuilder) buildAfterTriggers(rows ...triggerRows) {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime != tree.TriggerAfter {
+			continue
+		}
+		for j := range rows {
+			if !trigger.FiresOn(rows[j].event) {
+				continue
+			}
+			mb.ensureWithID()
+			cb := &triggerCascadeBuilder{table: mb.tab, trigger: i, event: rows[j].event}
+			var oldValues, newValues opt.ColList
+			for ord, col := range rows[j].oldCols {
+				if col != 0 {
+					cb.oldOrds = append(cb.oldOrds, ord)
+					oldValues = append(oldValues, col)
+				}
+			}
+			for ord, col := range rows[j].newCols {
+				if col != 0 {
+					cb.newOrds = append(cb.newOrds, ord)
+					newValues = append(newValues, col)
+				}
+			}
+			if rows[j].canaryCol != 0 {
+				cb.hasCanary = true
+				newValues = append(newValues, rows[j].canaryCol)
+			}
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				FKName:    trigger.Name,
+				Builder:   cb,
+				WithID:    mb.withID,
+				OldValues: oldValues,
+				NewValues: newValues,
+			})
+		}
+	}
+}
+
+// insertTriggerRows returns the rows which fire the INSERT triggers of the
+// target table.
+func (mb *mutationBuilder) insertTriggerRows() triggerRows {
+	return triggerRows{
+		event:     tree.TriggerInsert,
+		newCols:   mb.insertColIDs,
+		canaryCol: mb.canaryColID,
+	}
+}
+
+// updateTriggerRows returns the rows which fire the UPDATE triggers of the
+// target table. The new value of each column is its update column if it is
+// updated, and its fetch column otherwise.
+func (mb *mutationBuilder) updateTriggerRows() triggerRows {
+	if mb.tab.TriggerCount() == 0 {
+		// Avoid allocating the new columns when they are not used.
+		return triggerRows{event: tree.TriggerUpdate}
+	}
+	newCols := make(opt.OptionalColList, len(mb.fetchColIDs))
+	for i := range newCols {
+		newCols[i] = mb.updateColIDs[i]
+		if newCols[i] == 0 {
+			newCols[i] = mb.fetchColIDs[i]
+		}
+	}
+	return triggerRows{
+		event:     tree.TriggerUpdate,
+		oldCols:   mb.fetchColIDs,
+		newCols:   newCols,
+		canaryCol: mb.canaryColID,
+	}
+}
+
+// deleteTriggerRows returns the rows which fire the DELETE triggers of the
+// target table.
+func (mb *mutationBuilder) deleteTriggerRows() triggerRows {
+	return triggerRows{event: tree.TriggerDelete, oldCols: mb.fetchColIDs}
+}
+
+// triggerCascadeBuilder is a memo.CascadeBuilder implementation for AFTER
+// triggers. It builds the statement run by the trigger on top of the buffered
+// mutation input.
+//
+// The old and new values passed to Build hold the columns of the table with
+// the ordinals in oldOrds and newOrds respectively. If hasCanary is set, the
+// new values are followed by the canary column of an upsert.
+type triggerCascadeBuilder struct {
+	table   cat.Table
+	trigger int
+	event   tree.TriggerEvent
+
+	oldOrds, newOrds []int
+	hasCanary        bool
+}
+
+var _ memo.CascadeBuilder = &triggerCascadeBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (cb *triggerCascadeBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		md := b.factory.Metadata()
+		rows := triggerRows{event: cb.event}
+		numNewValues := len(cb.newOrds)
+		if cb.hasCanary {
+			numNewValues++
+		}
+		if len(oldValues) != len(cb.oldOrds) || len(newValues) != numNewValues {
+			panic(errors.AssertionFailedf(
+				"expected %d old and %d new values, got %d and %d",
+				len(cb.oldOrds), numNewValues, len(oldValues), len(newValues),
+			))
+		}
+		if len(cb.oldOrds) > 0 {
+			rows.oldCols = make(opt.OptionalColList, cb.table.ColumnCount())
+			for i, ord := range cb.oldOrds {
+				rows.oldCols[ord] = oldValues[i]
+			}
+		}
+		if len(cb.newOrds) > 0 {
+			rows.newCols = make(opt.OptionalColList, cb.table.ColumnCount())
+			for i, ord := range cb.newOrds {
+				rows.newCols[ord] = newValues[i]
+			}
+		}
+		if cb.hasCanary {
+			rows.canaryCol = newValues[len(newValues)-1]
+		}
+
+		// Construct a dummy operator as the binding.
+		md.AddWithBinding(binding, b.factory.ConstructFakeRel(&memo.FakeRelPrivate{
+			Props: bindingProps,
+		}))
+		trigger := cb.table.Trigger(cb.trigger)
+		outScope, _ := b.buildTriggerStmt(cb.table, &trigger, &rows, binding)
+		return outScope.expr
+	})
+}
+
+// buildTriggerStmt builds the statement run by the given trigger, for the rows
+// buffered by the given With binding. If withID is 0, the statement is built
+// for an empty set of rows; this is used to validate the statement. It returns
+// the built statement, along with the parsed statement.
+func (b *Builder) buildTriggerStmt(
+	tab cat.Table, trigger *cat.Trigger, rows *triggerRows, withID opt.WithID,
+) (outScope *scope, stmt tree.Statement) {
+	parsed, err := parser.ParseOne(trigger.Body)
+	if err != nil {
+		panic(err)
+	}
+	stmt = parsed.AST
+	if err := checkTriggerStmt(stmt); err != nil {
+		panic(err)
+	}
+	if parsed.NumPlaceholders > 0 {
+		panic(pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"trigger statement cannot contain placeholders"))
+	}
+
+	defer func(prevAnnotations tree.Annotations, prevRows *triggerRowsSource) {
+		b.semaCtx.Annotations = prevAnnotations
+		b.triggerRows = prevRows
+	}(b.semaCtx.Annotations, b.triggerRows)
+	b.semaCtx.Annotations = tree.MakeAnnotations(parsed.NumAnnotations)
+
+	name := tree.MakeUnqualifiedTableName("trigger_rows")
+	b.triggerRows = &triggerRowsSource{
+		name: &name,
+		build: func(inScope *scope) *scope {
+			return b.buildTriggerRows(tab, trigger, rows, withID, inScope)
+		},
+	}
+	return b.buildStmt(rewriteTriggerStmt(stmt, &name), nil /* desiredTypes */, b.allocScope()), stmt
+}
+
+// checkTriggerStmt returns an error if the given statement cannot be run by a
+// trigger.
+func checkTriggerStmt(stmt tree.Statement) error {
+	unsupported := func(what string) error {
+		return pgerror.Newf(pgcode.FeatureNotSupported, "%s is not supported in trigger statements", what)
+	}
+	switch t := stmt.(type) {
+	case *tree.Insert:
+		if t.With != nil {
+			return unsupported("WITH")
+		}
+		if t.DefaultValues() {
+			return unsupported("DEFAULT VALUES")
+		}
+		if _, ok := t.Returning.(*tree.NoReturningClause); !ok {
+			return unsupported("RETURNING")
+		}
+	case *tree.Update:
+		if t.With != nil {
+			return unsupported("WITH")
+		}
+		if t.OrderBy != nil || t.Limit != nil {
+			return unsupported("ORDER BY or LIMIT")
+		}
+		if _, ok := t.Returning.(*tree.NoReturningClause); !ok {
+			return unsupported("RETURNING")
+		}
+	case *tree.Delete:
+		if t.With != nil {
+			return unsupported("WITH")
+		}
+		if t.OrderBy != nil || t.Limit != nil {
+			return unsupported("ORDER BY or LIMIT")
+		}
+		if _, ok := t.Returning.(*tree.NoReturningClause); !ok {
+			return unsupported("RETURNING")
+		}
+	default:
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"trigger statement must be an INSERT, UPSERT, UPDATE or DELETE statement, not %s",
+			stmt.StatementTag())
+	}
+	return nil
+}
+
+// rewriteTriggerStmt rewrites the given trigger statement so that it runs once
+// for all of the trigger rows, which are referred to by the given name. See the
+// comment at the top of the file for examples.
+//
+// The original statement is not modified, but it shares its sub-expressions
+// with the rewritten statement; in particular, the data source names it
+// contains are qualified when the rewritten statement is built with
+// qualifyDataSourceNamesInAST.
+func rewriteTriggerStmt(stmt tree.Statement, rows *tree.TableName) tree.Statement {
+	switch t := stmt.(type) {
+	case *tree.Insert:
+		ins := *t
+		if values, ok := t.Rows.Select.(*tree.ValuesClause); ok && t.Rows.OrderBy == nil && t.Rows.Limit == nil {
+			// Build each tuple as a SELECT, so that its expressions are typed
+			// using the types of the target columns, as in a VALUES clause.
+			var sel tree.SelectStatement
+			for _, tuple := range values.Rows {
+				exprs := make(tree.SelectExprs, len(tuple))
+				for i := range tuple {
+					exprs[i].Expr = tuple[i]
+				}
+				var next tree.SelectStatement = &tree.SelectClause{
+					Exprs: exprs,
+					From:  tree.From{Tables: tree.TableExprs{rows}},
+				}
+				if sel != nil {
+					next = &tree.UnionClause{
+						Type:  tree.UnionOp,
+						Left:  &tree.Select{Select: sel},
+						Right: &tree.Select{Select: next},
+						All:   true,
+					}
+				}
+				sel = next
+			}
+			ins.Rows = &tree.Select{Select: sel}
+		} else {
+			alias, err := tree.NewUnresolvedObjectName(
+				1 /* numParts */, [3]string{"trigger_stmt"}, tree.NoAnnotation,
+			)
+			if err != nil {
+				panic(errors.NewAssertionErrorWithWrappedErrf(err, "invalid alias"))
+			}
+			ins.Rows = &tree.Select{Select: &tree.SelectClause{
+				Exprs: tree.SelectExprs{{Expr: &tree.AllColumnsSelector{TableName: alias}}},
+				From: tree.From{Tables: tree.TableExprs{
+					rows,
+					&tree.AliasedTableExpr{
+						Expr:    &tree.Subquery{Select: &tree.ParenSelect{Select: t.Rows}},
+						Lateral: true,
+						As:      tree.AliasClause{Alias: "trigger_stmt"},
+					},
+				}},
+			}}
+		}
+		return &ins
+
+	case *tree.Update:
+		upd := *t
+		upd.From = append(t.From[:len(t.From):len(t.From)], rows)
+		return &upd
+
+	case *tree.Delete:
+		del := *t
+		del.Where = &tree.Where{Type: tree.AstWhere, Expr: &tree.Subquery{
+			Select: &tree.ParenSelect{Select: &tree.Select{Select: &tree.SelectClause{
+				Exprs: tree.SelectExprs{{Expr: tree.NewDInt(1)}},
+				From:  tree.From{Tables: tree.TableExprs{rows}},
+				Where: t.Where,
+			}}},
+			Exists: true,
+		}}
+		return &del
+	}
+	panic(errors.AssertionFailedf("unexpected trigger statement %T", stmt))
+}
+
+// buildTriggerRows builds the trigger rows for the rows buffered by the given
+// With binding, or an empty set of rows if withID is 0. The trigger rows have
+// a column for each old and new value of the public table columns, named after
+// the table column and qualified by "old" or "new". Only the rows which satisfy
+// the WHEN condition of the trigger are kept.
+func (b *Builder) buildTriggerRows(
+	tab cat.Table, trigger *cat.Trigger, rows *triggerRows, withID opt.WithID, inScope *scope,
+) (outScope *scope) {
+	md := b.factory.Metadata()
+	outScope = inScope.push()
+
+	var inCols, outCols opt.ColList
+	addCols := func(cols opt.OptionalColList, tableName tree.Name) {
+		tn := tree.MakeUnqualifiedTableName(tableName)
+		for ord, col := range cols {
+			tabCol := tab.Column(ord)
+			if col == 0 || tabCol.Kind() != cat.Ordinary {
+				continue
+			}
+			scopeCol := b.synthesizeColumn(
+				outScope, string(tabCol.ColName()), tabCol.DatumType(), nil /* expr */, nil, /* scalar */
+			)
+			scopeCol.table = tn
+			scopeCol.hidden = tabCol.IsHidden()
+			inCols = append(inCols, col)
+			outCols = append(outCols, scopeCol.id)
+		}
+	}
+	addCols(rows.oldCols, "old")
+	addCols(rows.newCols, "new")
+
+	var canaryCol opt.ColumnID
+	if rows.canaryCol != 0 {
+		canaryCol = md.AddColumn("canary", md.ColumnMeta(rows.canaryCol).Type)
+		inCols = append(inCols, rows.canaryCol)
+		outCols = append(outCols, canaryCol)
+	}
+
+	if withID == 0 {
+		outScope.expr = b.factory.ConstructValues(memo.ScalarListExpr{}, &memo.ValuesPrivate{
+			Cols: outCols,
+			ID:   md.NextUniqueID(),
+		})
+	} else {
+		outScope.expr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+			With:    withID,
+			InCols:  inCols,
+			OutCols: outCols,
+			ID:      md.NextUniqueID(),
+		})
+	}
+
+	if canaryCol != 0 {
+		// Only keep the inserted rows for the INSERT event, and the updated rows
+		// for the UPDATE event.
+		var filter opt.ScalarExpr
+		canary := b.factory.ConstructVariable(canaryCol)
+		if rows.event == tree.TriggerInsert {
+			filter = b.factory.ConstructIs(canary, memo.NullSingleton)
+		} else {
+			filter = b.factory.ConstructIsNot(canary, memo.NullSingleton)
+		}
+		outScope.expr = b.factory.ConstructProject(
+			b.factory.ConstructSelect(
+				outScope.expr,
+				memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)},
+			),
+			memo.EmptyProjectionsExpr,
+			outScope.colSet(),
+		)
+	}
+
+	if trigger.When != "" {
+		when, err := parser.ParseExpr(trigger.When)
+		if err != nil {
+			panic(err)
+		}
+		b.buildWhere(&tree.Where{Type: tree.AstWhere, Expr: when}, outScope)
+	}
+	return outScope
+}
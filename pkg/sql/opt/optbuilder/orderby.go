@@ -148,6 +148,9 @@ func (b *Builder) analyzeOrderByIndex(
 		expr := inScope.resolveType(colItem, types.Any)
 		outCol := orderByScope.addColumn("" /* alias */, expr)
 		outCol.descending = desc
+		// Reversing the order of the index also reverses the position of NULLs, so
+		// the NULL ordering relative to the direction is unchanged.
+		outCol.nullsReversed = col.NullsReversed
 	}
 }
 
@@ -168,6 +171,7 @@ func (b *Builder) analyzeOrderByArg(
 	for i := start; i < len(orderByScope.cols); i++ {
 		col := &orderByScope.cols[i]
 		col.descending = order.Direction == tree.Descending
+		col.nullsReversed = order.NullsOrder.IsReversed(order.Direction)
 	}
 }
 
@@ -182,7 +186,9 @@ func (b *Builder) buildOrderByArg(
 
 	// Add the new column to the ordering.
 	orderByScope.ordering = append(orderByScope.ordering,
-		opt.MakeOrderingColumn(orderByCol.id, orderByCol.descending),
+		opt.MakeOrderingColumnWithNulls(
+			orderByCol.id, orderByCol.descending, orderByCol.nullsReversed,
+		),
 	)
 }
 
@@ -93,9 +93,12 @@ func (b *Builder) buildScalar(
 			// valid operator.
 			aggInCol := g.aggInScope.addColumn("" /* alias */, t)
 			b.finishBuildScalarRef(t, inScope, g.aggInScope, aggInCol, nil)
-			g.groupStrs[symbolicExprStr(t)] = aggInCol
+			g.groupStrs[symbolicExprStr(t)] = b.addGroupingOutCol(g, aggInCol)
 
-			g.aggOutScope.appendColumn(aggInCol)
+			// The new grouping column is part of all the grouping sets.
+			for i := range g.groupingSets {
+				g.groupingSets[i].Add(len(g.groupStrs) - 1)
+			}
 
 			return b.finishBuildScalarRef(t, g.aggOutScope, outScope, outCol, colRefs)
 		}
@@ -112,6 +115,10 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *groupingInfo:
+		col := b.buildGroupingOp(t, inScope)
+		return b.finishBuildScalarRef(col, inScope.groupby.aggOutScope, outScope, outCol, colRefs)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(tree.ReType(t.TypedLeft(), types.Bool), inScope, nil, nil, colRefs)
 		right := b.buildScalar(tree.ReType(t.TypedRight(), types.Bool), inScope, nil, nil, colRefs)
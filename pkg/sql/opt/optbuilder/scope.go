@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -932,7 +933,10 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 	case *tree.FuncExpr:
 		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
 		if err != nil {
-			panic(err)
+			// The function may be a user-defined function, which is replaced
+			// by either its inlined body or a call to a synthesized built-in.
+			expr = s.replaceUDF(t, err)
+			break
 		}
 
 		if isGenerator(def) && s.replaceSRFs {
@@ -955,6 +959,9 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+	case *tree.GroupingExpr:
+		expr = s.replaceGrouping(t)
+
 	case *tree.ArrayFlatten:
 		if sub, ok := t.Subquery.(*tree.Subquery); ok {
 			// Copy the ArrayFlatten expression so that the tree isn't mutated.
@@ -1180,6 +1187,32 @@ func (s *scope) constructWindowDef(def tree.WindowDef) tree.WindowDef {
 	}
 }
 
+// replaceGrouping returns a groupingInfo which replaces the given GROUPING
+// operation. The arguments are type checked here, but they can only be matched
+// against the grouping expressions once those are built; see
+// Builder.buildGroupingOp.
+func (s *scope) replaceGrouping(t *tree.GroupingExpr) tree.Expr {
+	if len(t.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1))
+	}
+
+	// We need to save and restore the previous value of the field in
+	// semaCtx in case we are recursively called within a subquery
+	// context.
+	defer s.builder.semaCtx.Properties.Restore(s.builder.semaCtx.Properties)
+	s.builder.semaCtx.Properties.Require("GROUPING", tree.RejectSpecial)
+
+	info := &groupingInfo{
+		GroupingExpr: t,
+		args:         make([]tree.TypedExpr, len(t.Exprs)),
+	}
+	for i, e := range t.Exprs {
+		info.args[i] = s.resolveType(e, types.Any)
+	}
+	return info
+}
+
 func (s *scope) replaceWindowFn(f *tree.FuncExpr, def *tree.FunctionDefinition) tree.Expr {
 	f, def = s.replaceCount(f, def)
 
@@ -1339,6 +1372,10 @@ func analyzeWindowFrame(s *scope, windowDef *tree.WindowDef) error {
 				return pgerror.Newf(pgcode.Windowing,
 					"RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column")
 			}
+			if o := windowDef.OrderBy[0]; o.NullsOrder.IsReversed(o.Direction) {
+				return unimplemented.NewWithIssuef(6224,
+					"RANGE with offset PRECEDING/FOLLOWING is not supported with %s", o.NullsOrder)
+			}
 			requiredType = windowDef.OrderBy[0].Expr.(tree.TypedExpr).ResolvedType()
 			if !types.IsAdditiveType(requiredType) {
 				return pgerror.Newf(pgcode.Windowing,
@@ -73,6 +73,12 @@ func (b *Builder) buildDataSource(
 	case *tree.TableName:
 		tn := source
 
+		// The trigger rows are only referred to by the statements rewritten by
+		// rewriteTriggerStmt.
+		if b.triggerRows != nil && tn == b.triggerRows.name {
+			return b.triggerRows.build(inScope)
+		}
+
 		// CTEs take precedence over other data sources.
 		if cte := inScope.resolveCTE(tn); cte != nil {
 			locking.ignoreLockingForCTE()
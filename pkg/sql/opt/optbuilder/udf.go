@@ -0,0 +1,438 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// maxUDFInlineDepth is the maximum nesting depth of user-defined functions
+// that are inlined into the calling query. Calls which are nested more deeply
+// (e.g. in recursive functions) are evaluated using the internal executor.
+const maxUDFInlineDepth = 8
+
+// maxUDFCallDepth is the maximum nesting depth of user-defined functions that
+// are evaluated using the internal executor.
+const maxUDFCallDepth = 32
+
+// udfCallDepthKey is the context key under which the nesting depth of
+// user-defined function evaluations is stored.
+type udfCallDepthKey struct{}
+
+// replaceUDF returns the expression which replaces a call to a user-defined
+// function. resolveErr is the error which was returned when trying to resolve
+// the name of the function among the built-in functions; it is raised if the
+// name does not refer to a user-defined function either.
+//
+// If the function is simple enough, its body is inlined into the query.
+// Otherwise, the call is replaced by a call to a synthesized built-in whose
+// only overload evaluates the body using the internal executor.
+func (s *scope) replaceUDF(f *tree.FuncExpr, resolveErr error) tree.Expr {
+	return s.builder.replaceUDF(f, resolveErr, 0 /* depth */)
+}
+
+func (b *Builder) replaceUDF(f *tree.FuncExpr, resolveErr error, depth int) tree.Expr {
+	fn, name := b.resolveUDF(f, resolveErr)
+
+	switch {
+	case f.Type == tree.DistinctFuncType:
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"DISTINCT specified, but %s is not an aggregate function", name))
+	case f.Type == tree.AllFuncType:
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"ALL specified, but %s is not an aggregate function", name))
+	case f.Filter != nil:
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"FILTER specified, but %s is not an aggregate function", name))
+	case len(f.OrderBy) > 0:
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"ORDER BY specified, but %s is not an aggregate function", name))
+	case f.WindowDef != nil:
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"OVER specified, but %s is not a window function nor an aggregate function", name))
+	}
+	if len(f.Exprs) != fn.ParamCount() {
+		panic(pgerror.Newf(pgcode.UndefinedFunction,
+			"function %s expects %d arguments, but %d were given", name, fn.ParamCount(), len(f.Exprs)))
+	}
+
+	if depth < maxUDFInlineDepth {
+		if inlined := b.tryInlineUDF(fn, f.Exprs, depth); inlined != nil {
+			return inlined
+		}
+	}
+
+	argTypes := make(tree.ArgTypes, fn.ParamCount())
+	for i := range argTypes {
+		argTypes[i].Name = string(fn.ParamName(i))
+		if argTypes[i].Name == "" {
+			argTypes[i].Name = fmt.Sprintf("$%d", i+1)
+		}
+		argTypes[i].Typ = fn.ParamType(i)
+	}
+	body := fn.Body()
+	def := tree.NewUserDefinedFunctionDefinition(
+		name,
+		&tree.FunctionProperties{
+			Class:            tree.NormalClass,
+			NullableArgs:     !fn.IsStrict(),
+			DistsqlBlocklist: true,
+		},
+		&tree.Overload{
+			Types:      argTypes,
+			ReturnType: tree.FixedReturnType(fn.ReturnType()),
+			Volatility: fn.Volatility(),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return evalUDF(evalCtx, body, args)
+			},
+		},
+	)
+
+	// Copy the function expression so that the tree isn't mutated; a prepared
+	// statement must resolve the function again each time it is planned.
+	copy := *f
+	copy.Func = tree.ResolvableFunctionReference{FunctionReference: def}
+	return &copy
+}
+
+// resolveUDF resolves the name of a called function in the catalog, and
+// returns the function along with its fully qualified name. If the name does
+// not refer to a user-defined function, resolveErr is raised.
+func (b *Builder) resolveUDF(f *tree.FuncExpr, resolveErr error) (cat.Function, string) {
+	n, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok || pgerror.GetPGCode(resolveErr) != pgcode.UndefinedFunction || n.NumParts > 3 {
+		panic(resolveErr)
+	}
+	un, err := tree.NewUnresolvedObjectName(
+		n.NumParts, [3]string{n.Parts[0], n.Parts[1], n.Parts[2]}, tree.NoAnnotation,
+	)
+	if err != nil {
+		panic(resolveErr)
+	}
+
+	flags := cat.Flags{AvoidDescriptorCaches: b.insideViewDef}
+	fn, resName, err := b.catalog.ResolveFunction(b.ctx, flags, un)
+	if err != nil {
+		if pgerror.GetPGCode(err) == pgcode.UndefinedFunction {
+			panic(resolveErr)
+		}
+		panic(err)
+	}
+	// As for the tables used by a view, the privileges on the functions called
+	// by a view are not checked for the user of the view.
+	if !b.skipSelectPrivilegeChecks {
+		if err := b.catalog.CheckPrivilege(b.ctx, fn, privilege.EXECUTE); err != nil {
+			panic(err)
+		}
+	}
+	b.factory.Metadata().AddFunctionDependency(un, fn)
+
+	fnName := tree.MakeTableNameFromPrefix(resName, fn.Name())
+	if b.qualifyDataSourceNamesInAST {
+		f.Func.FunctionReference = &tree.UnresolvedName{
+			NumParts: 3,
+			Parts:    tree.NameParts{string(fn.Name()), fnName.Schema(), fnName.Catalog()},
+		}
+	}
+	return fn, fnName.FQString()
+}
+
+// tryInlineUDF returns the body of the function with the arguments
+// substituted for the parameters, or nil if the function cannot be inlined.
+//
+// A function is inlined if it is not volatile and its body is a single
+// expression without subqueries or aggregates. Each argument which is not
+// trivial to evaluate must be used exactly once, so that inlining neither
+// duplicates nor drops its evaluation. All the arguments of strict functions
+// must be trivial, since they are also used to check for NULL inputs.
+func (b *Builder) tryInlineUDF(fn cat.Function, args tree.Exprs, depth int) tree.Expr {
+	if fn.Volatility() == tree.VolatilityVolatile {
+		return nil
+	}
+	stmt, err := parser.ParseOne(fn.Body())
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.Syntax,
+			"failed to parse the body of function %q", fn.Name()))
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok || sel.With != nil || len(sel.OrderBy) > 0 || sel.Limit != nil || len(sel.Locking) > 0 {
+		return nil
+	}
+	sc, ok := sel.Select.(*tree.SelectClause)
+	if !ok || len(sc.From.Tables) > 0 || sc.Where != nil || sc.Having != nil ||
+		len(sc.GroupBy) > 0 || len(sc.Window) > 0 || sc.Distinct || len(sc.DistinctOn) > 0 ||
+		len(sc.Exprs) != 1 {
+		return nil
+	}
+
+	uses := make([]int, len(args))
+	canInline := true
+	body, err := tree.SimpleVisit(sc.Exprs[0].Expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch t := expr.(type) {
+		case *tree.Subquery:
+			canInline = false
+		case *tree.Placeholder:
+			if int(t.Idx) < len(uses) {
+				uses[t.Idx]++
+			}
+		case *tree.FuncExpr:
+			def, err := t.Func.Resolve(b.semaCtx.SearchPath)
+			if err != nil {
+				// Nested calls to user-defined functions are replaced right
+				// away, in order to limit the inlining depth.
+				return true, b.replaceUDF(t, err, depth+1), nil
+			}
+			if def.Class != tree.NormalClass || t.WindowDef != nil {
+				canInline = false
+			}
+		}
+		return canInline, expr, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	if !canInline {
+		return nil
+	}
+	for i := range args {
+		if (uses[i] != 1 || fn.IsStrict()) && !isTrivialUDFArg(args[i]) {
+			return nil
+		}
+	}
+
+	body, err = tree.SimpleVisit(body, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if p, ok := expr.(*tree.Placeholder); ok {
+			return false, &tree.ParenExpr{Expr: args[p.Idx]}, nil
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	var res tree.Expr = &tree.CastExpr{
+		Expr:       &tree.ParenExpr{Expr: body},
+		Type:       fn.ReturnType(),
+		SyntaxMode: tree.CastShort,
+	}
+	if fn.IsStrict() && len(args) > 0 {
+		var anyNull tree.Expr
+		for i := range args {
+			var isNull tree.Expr = &tree.IsNullExpr{Expr: args[i]}
+			if anyNull == nil {
+				anyNull = isNull
+			} else {
+				anyNull = &tree.OrExpr{Left: anyNull, Right: isNull}
+			}
+		}
+		res = &tree.CaseExpr{
+			Whens: []*tree.When{{Cond: anyNull, Val: &tree.CastExpr{
+				Expr: tree.DNull, Type: fn.ReturnType(), SyntaxMode: tree.CastShort,
+			}}},
+			Else: res,
+		}
+	}
+	return res
+}
+
+// isTrivialUDFArg returns true if the given argument of a user-defined
+// function is cheap to evaluate and has no side effects, so that it can be
+// evaluated any number of times.
+func isTrivialUDFArg(arg tree.Expr) bool {
+	switch t := arg.(type) {
+	case tree.Datum, tree.Constant, *tree.Placeholder, *tree.ColumnItem:
+		return true
+	case *tree.UnresolvedName:
+		return !t.Star
+	case *tree.ParenExpr:
+		return isTrivialUDFArg(t.Expr)
+	}
+	return false
+}
+
+// evalUDF evaluates the body of a user-defined function using the internal
+// executor, and returns the first column of the first row of the result, or
+// NULL if there are no rows.
+func evalUDF(evalCtx *tree.EvalContext, body string, args tree.Datums) (tree.Datum, error) {
+	ctx := evalCtx.Ctx()
+	depth, _ := ctx.Value(udfCallDepthKey{}).(int)
+	if depth >= maxUDFCallDepth {
+		return nil, errors.WithHintf(
+			pgerror.New(pgcode.StatementTooComplex, "stack depth limit exceeded"),
+			"user-defined functions can be nested at most %d levels deep.", maxUDFCallDepth)
+	}
+	ctx = context.WithValue(ctx, udfCallDepthKey{}, depth+1)
+
+	qargs := make([]interface{}, len(args))
+	for i := range args {
+		qargs[i] = args[i]
+	}
+	rows, err := evalCtx.InternalExecutor.Query(ctx, udfOpName, evalCtx.Txn, body, qargs...)
+	if err != nil {
+		return nil, stripUDFOpName(err)
+	}
+	if len(rows) == 0 {
+		return tree.DNull, nil
+	}
+	return rows[0][0], nil
+}
+
+// udfOpName is the name of the internal executor operation which evaluates
+// the body of a user-defined function.
+const udfOpName = "udf"
+
+// stripUDFOpName removes the prefix which the internal executor adds to the
+// errors of the statements it runs, so that the errors raised by nested calls
+// do not accumulate prefixes.
+func stripUDFOpName(err error) error {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, udfOpName+": ") {
+		return err
+	}
+	msg = msg[len(udfOpName)+2:]
+	for e := errors.UnwrapOnce(err); e != nil; e = errors.UnwrapOnce(e) {
+		if e.Error() == msg {
+			return e
+		}
+	}
+	return err
+}
+
+// replaceInSelect replaces, in place, the expressions of a SELECT statement
+// which is the body of a user-defined function. Unlike the statement walker
+// of the tree package, it also visits the expressions in the FROM clause and
+// in common table expressions. The fn function is called like
+// Visitor.VisitPre.
+func replaceInSelect(sel *tree.Select, fn func(expr tree.Expr) (bool, tree.Expr)) {
+	r := selectReplacer{fn: fn}
+	r.replaceSelect(sel)
+}
+
+type selectReplacer struct {
+	fn func(expr tree.Expr) (bool, tree.Expr)
+}
+
+var _ tree.Visitor = &selectReplacer{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (r *selectReplacer) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if sub, ok := expr.(*tree.Subquery); ok {
+		r.replaceSelectStmt(sub.Select)
+		return false, expr
+	}
+	return r.fn(expr)
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (r *selectReplacer) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+func (r *selectReplacer) replace(expr *tree.Expr) {
+	if *expr != nil {
+		*expr, _ = tree.WalkExpr(r, *expr)
+	}
+}
+
+func (r *selectReplacer) replaceSelect(sel *tree.Select) {
+	if sel.With != nil {
+		for _, cte := range sel.With.CTEList {
+			if s, ok := cte.Stmt.(tree.SelectStatement); ok {
+				r.replaceSelectStmt(s)
+			}
+		}
+	}
+	r.replaceSelectStmt(sel.Select)
+	for _, o := range sel.OrderBy {
+		r.replace(&o.Expr)
+	}
+	if sel.Limit != nil {
+		r.replace(&sel.Limit.Count)
+		r.replace(&sel.Limit.Offset)
+	}
+}
+
+func (r *selectReplacer) replaceSelectStmt(stmt tree.Statement) {
+	switch t := stmt.(type) {
+	case *tree.Select:
+		r.replaceSelect(t)
+	case *tree.ParenSelect:
+		r.replaceSelect(t.Select)
+	case *tree.UnionClause:
+		r.replaceSelect(t.Left)
+		r.replaceSelect(t.Right)
+	case *tree.ValuesClause:
+		for _, row := range t.Rows {
+			for i := range row {
+				r.replace(&row[i])
+			}
+		}
+	case *tree.SelectClause:
+		for i := range t.DistinctOn {
+			r.replace(&t.DistinctOn[i])
+		}
+		for i := range t.Exprs {
+			r.replace(&t.Exprs[i].Expr)
+		}
+		for _, table := range t.From.Tables {
+			r.replaceTableExpr(table)
+		}
+		if t.Where != nil {
+			r.replace(&t.Where.Expr)
+		}
+		for i := range t.GroupBy {
+			r.replace(&t.GroupBy[i])
+		}
+		if t.Having != nil {
+			r.replace(&t.Having.Expr)
+		}
+		for _, w := range t.Window {
+			r.replaceWindowDef(w)
+		}
+	}
+}
+
+func (r *selectReplacer) replaceTableExpr(table tree.TableExpr) {
+	switch t := table.(type) {
+	case *tree.AliasedTableExpr:
+		r.replaceTableExpr(t.Expr)
+	case *tree.ParenTableExpr:
+		r.replaceTableExpr(t.Expr)
+	case *tree.JoinTableExpr:
+		r.replaceTableExpr(t.Left)
+		r.replaceTableExpr(t.Right)
+		if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+			r.replace(&on.Expr)
+		}
+	case *tree.Subquery:
+		r.replaceSelectStmt(t.Select)
+	case *tree.RowsFromExpr:
+		for i := range t.Items {
+			r.replace(&t.Items[i])
+		}
+	}
+}
+
+func (r *selectReplacer) replaceWindowDef(w *tree.WindowDef) {
+	for i := range w.Partitions {
+		r.replace(&w.Partitions[i])
+	}
+	for _, o := range w.OrderBy {
+		r.replace(&o.Expr)
+	}
+}
@@ -329,6 +329,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	// check constraint, refer to the correct columns.
 	mb.disambiguateColumns()
 
+	mb.buildBeforeTriggers(mb.updateTriggerRows())
+
 	// Keep a reference to the scope before the check constraint columns are
 	// projected. We use this scope when projecting the partial index put
 	// columns because the check columns are not in-scope for those expressions.
@@ -345,9 +347,12 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 	mb.projectPartialIndexPutAndDelCols(preCheckScope, mb.fetchScope)
 
 	mb.buildUniqueChecksForUpdate()
+	mb.buildExclusionChecksForUpdate()
 
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(mb.updateTriggerRows())
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -218,8 +218,8 @@ func (b *Builder) populateSynthesizedColumn(col *scopeColumn, scalar opt.ScalarE
 // - We don't copy hidden, because projecting a column makes it visible.
 //   dst already has hidden=false, so keep it as-is.
 // - We don't copy table, since the table becomes anonymous in the new scope.
-// - We don't copy descending, since we don't want to overwrite dst.descending
-//   if dst is an ORDER BY column.
+// - We don't copy descending or nullsReversed, since we don't want to
+//   overwrite them in dst if dst is an ORDER BY column.
 // - expr, exprStr and typ in dst already correspond to the expression and type
 //   of the src column.
 func (b *Builder) projectColumn(dst *scopeColumn, src *scopeColumn) {
@@ -219,6 +219,8 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"Subquery":          {fullName: "tree.Subquery", isPointer: true, usePointerIntern: true},
 		"CreateTable":       {fullName: "tree.CreateTable", isPointer: true, usePointerIntern: true},
 		"CreateStats":       {fullName: "tree.CreateStats", isPointer: true, usePointerIntern: true},
+		"CreateFunction":    {fullName: "tree.CreateFunction", isPointer: true, usePointerIntern: true},
+		"CreateTrigger":     {fullName: "tree.CreateTrigger", isPointer: true, usePointerIntern: true},
 		"TableName":         {fullName: "tree.TableName", isPointer: true, usePointerIntern: true},
 		"Constraint":        {fullName: "constraint.Constraint", isPointer: true, usePointerIntern: true},
 		"FuncProps":         {fullName: "tree.FunctionProperties", isPointer: true, usePointerIntern: true},
@@ -235,6 +237,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"ScheduleCommand":   {fullName: "tree.ScheduleCommand", passByVal: true},
 		"IndexOrdinal":      {fullName: "cat.IndexOrdinal", passByVal: true},
 		"IndexOrdinals":     {fullName: "cat.IndexOrdinals", passByVal: true},
+		"UniqueOrdinals":    {fullName: "cat.UniqueOrdinals", passByVal: true},
 		"ViewDeps":          {fullName: "opt.ViewDeps", passByVal: true},
 		"LockingItem":       {fullName: "tree.LockingItem", isPointer: true},
 		"MaterializeClause": {fullName: "tree.MaterializeClause", passByVal: true},
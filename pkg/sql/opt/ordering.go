@@ -19,9 +19,14 @@ import (
 
 // OrderingColumn is the ColumnID for a column that is part of an ordering,
 // except that it can be negated to indicate a descending ordering on that
-// column.
+// column. The nullsReversedFlag bit is set in the absolute value if NULLs are
+// ordered opposite to the default for the direction (see NullsReversed).
 type OrderingColumn int32
 
+// nullsReversedFlag is the bit of the absolute value of an OrderingColumn that
+// is set when NULLs are ordered opposite to the default for the direction.
+const nullsReversedFlag = 1 << 30
+
 // MakeOrderingColumn initializes an ordering column with a ColumnID and a flag
 // indicating whether the direction is descending.
 func MakeOrderingColumn(id ColumnID, descending bool) OrderingColumn {
@@ -31,12 +36,40 @@ func MakeOrderingColumn(id ColumnID, descending bool) OrderingColumn {
 	return OrderingColumn(id)
 }
 
+// MakeOrderingColumnWithNulls is like MakeOrderingColumn, but also takes a
+// flag indicating whether NULLs are ordered opposite to the default for the
+// direction.
+func MakeOrderingColumnWithNulls(
+	id ColumnID, descending bool, nullsReversed bool,
+) OrderingColumn {
+	if nullsReversed {
+		id |= nullsReversedFlag
+	}
+	return MakeOrderingColumn(id, descending)
+}
+
 // ID returns the ColumnID for this OrderingColumn.
 func (c OrderingColumn) ID() ColumnID {
 	if c < 0 {
-		return ColumnID(-c)
+		c = -c
+	}
+	return ColumnID(c &^ nullsReversedFlag)
+}
+
+// NullsReversed returns true if NULLs are ordered opposite to the default for
+// the direction of this column. By default, NULLs are ordered before all other
+// values in an ascending ordering (NULLS FIRST) and after all other values in
+// a descending ordering (NULLS LAST).
+func (c OrderingColumn) NullsReversed() bool {
+	if c < 0 {
+		c = -c
 	}
-	return ColumnID(c)
+	return c&nullsReversedFlag != 0
+}
+
+// NullsLast returns true if NULLs are ordered after all other values.
+func (c OrderingColumn) NullsLast() bool {
+	return c.Descending() != c.NullsReversed()
 }
 
 // Ascending returns true if the ordering on this column is ascending.
@@ -54,7 +87,7 @@ func (c OrderingColumn) Descending() bool {
 func (c OrderingColumn) RemapColumn(from, to TableID) OrderingColumn {
 	ord := from.ColumnOrdinal(c.ID())
 	newColID := to.ColumnID(ord)
-	return MakeOrderingColumn(newColID, c.Descending())
+	return MakeOrderingColumnWithNulls(newColID, c.Descending(), c.NullsReversed())
 }
 
 func (c OrderingColumn) String() string {
@@ -71,6 +104,13 @@ func (c OrderingColumn) Format(buf *bytes.Buffer) {
 		buf.WriteByte('+')
 	}
 	fmt.Fprintf(buf, "%d", c.ID())
+	if c.NullsReversed() {
+		if c.NullsLast() {
+			buf.WriteString(":nulls-last")
+		} else {
+			buf.WriteString(":nulls-first")
+		}
+	}
 }
 
 // Ordering defines the order of rows provided or required by an operator. A
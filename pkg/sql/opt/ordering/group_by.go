@@ -115,7 +115,9 @@ func StreamingGroupingColOrdering(
 			// The rest of the ordering is not useful.
 			return ordering[:i]
 		}
-		ordering[i] = opt.MakeOrderingColumn(colID, inputOrdering.Columns[i].Descending)
+		ordering[i] = opt.MakeOrderingColumnWithNulls(
+			colID, inputOrdering.Columns[i].Descending, inputOrdering.Columns[i].NullsReversed,
+		)
 	}
 	return ordering
 }
@@ -300,8 +300,8 @@ func remapProvided(provided opt.Ordering, fds *props.FuncDepSet, outCols opt.Col
 				result = make(opt.Ordering, i, len(provided))
 				copy(result, provided)
 			}
-			result = append(result, opt.MakeOrderingColumn(
-				remappedCol, provided[i].Descending(),
+			result = append(result, opt.MakeOrderingColumnWithNulls(
+				remappedCol, provided[i].Descending(), provided[i].NullsReversed(),
 			))
 		}
 		closure.Add(col)
@@ -94,6 +94,11 @@ func ScanPrivateCanProvide(
 		if !reqCol.Group.Contains(indexColID) {
 			return false, false
 		}
+		// A reverse scan flips both the direction and the position of NULLs, so
+		// the NULL ordering relative to the direction is the same either way.
+		if indexCol.NullsReversed != reqCol.NullsReversed {
+			return false, false
+		}
 		// The directions of the index column and the required column impose either
 		// a forward or a reverse scan.
 		required := fwd
@@ -140,7 +145,9 @@ func scanBuildProvided(expr memo.RelExpr, required *physical.OrderingChoice) opt
 			continue
 		}
 		direction := (indexCol.Descending != reverse) // != is bool XOR
-		provided = append(provided, opt.MakeOrderingColumn(colID, direction))
+		provided = append(provided, opt.MakeOrderingColumnWithNulls(
+			colID, direction, indexCol.NullsReversed,
+		))
 	}
 
 	return trimProvided(provided, required, fds)
@@ -34,6 +34,7 @@ import (
 //   +(1|2)              ORDER BY a        | ORDER BY b
 //   +(1|2),+3           ORDER BY a,c      | ORDER BY b, c
 //   -(3|4),+5 opt(1,2)  ORDER BY c DESC,e | ORDER BY a,d DESC,b DESC,e | ...
+//   +1:nulls-last       ORDER BY a NULLS LAST
 //
 // Each column in the ordering sequence forms the corresponding column of the
 // sort key, from most significant to least significant. Each column has a sort
@@ -86,11 +87,17 @@ type OrderingColumnChoice struct {
 	// Descending is true if the sort key column is ordered from highest to
 	// lowest. Otherwise, it's ordered from lowest to highest.
 	Descending bool
+
+	// NullsReversed is true if NULLs are ordered opposite to the default for
+	// the direction of the sort key column; that is, after all other values in
+	// an ascending column (NULLS LAST), or before all other values in a
+	// descending column (NULLS FIRST).
+	NullsReversed bool
 }
 
 const (
 	colChoiceRegexStr = `(?:\((\d+(?:\|\d+)*)\))`
-	ordColRegexStr    = `^(?:(?:\+|\-)(?:(\d+)|` + colChoiceRegexStr + `))$`
+	ordColRegexStr    = `^(?:(?:\+|\-)(?:(\d+)|` + colChoiceRegexStr + `)(?::nulls-(?:first|last))?)$`
 	colListRegexStr   = `(\d+(?:,\d+)*)`
 	optRegexStr       = `^\s*([\S]+)?\s*(?:opt\(` + colListRegexStr + `\))?\s*$`
 )
@@ -104,6 +111,7 @@ var optRegex, ordColRegex *regexp.Regexp
 //   +1
 //   -(1|2),+3
 //   +(1|2),+3 opt(5,6)
+//   +1:nulls-last,-2:nulls-first
 //
 // The input string is expected to be valid; ParseOrderingChoice will panic if
 // it is not.
@@ -148,6 +156,13 @@ func ParseOrderingChoice(s string) OrderingChoice {
 		var colChoice OrderingColumnChoice
 		colChoice.Descending = strings.HasPrefix(ordColStr, "-")
 
+		// An optional suffix overrides the default NULL ordering.
+		if colChoice.Descending {
+			colChoice.NullsReversed = strings.HasSuffix(ordColStr, ":nulls-first")
+		} else {
+			colChoice.NullsReversed = strings.HasSuffix(ordColStr, ":nulls-last")
+		}
+
 		if len(ordColMatches[1]) != 0 {
 			// Single column in equivalence group.
 			id, _ := strconv.Atoi(ordColMatches[1])
@@ -209,6 +224,7 @@ func (oc *OrderingChoice) FromOrdering(ord opt.Ordering) {
 	for i := range ord {
 		oc.Columns[i].Group.Add(ord[i].ID())
 		oc.Columns[i].Descending = ord[i].Descending()
+		oc.Columns[i].NullsReversed = ord[i].NullsReversed()
 	}
 }
 
@@ -221,8 +237,9 @@ func (oc *OrderingChoice) FromOrderingWithOptCols(ord opt.Ordering, optCols opt.
 	for i := range ord {
 		if !oc.Optional.Contains(ord[i].ID()) {
 			oc.Columns = append(oc.Columns, OrderingColumnChoice{
-				Group:      opt.MakeColSet(ord[i].ID()),
-				Descending: ord[i].Descending(),
+				Group:         opt.MakeColSet(ord[i].ID()),
+				Descending:    ord[i].Descending(),
+				NullsReversed: ord[i].NullsReversed(),
 			})
 		}
 	}
@@ -235,7 +252,7 @@ func (oc *OrderingChoice) ToOrdering() opt.Ordering {
 	ordering := make(opt.Ordering, len(oc.Columns))
 	for i := range oc.Columns {
 		col := &oc.Columns[i]
-		ordering[i] = opt.MakeOrderingColumn(col.AnyID(), col.Descending)
+		ordering[i] = opt.MakeOrderingColumnWithNulls(col.AnyID(), col.Descending, col.NullsReversed)
 	}
 	return ordering
 }
@@ -274,6 +291,7 @@ func (oc *OrderingChoice) ColSet() opt.ColSet {
 //
 //   <empty>           !implies +1
 //   +1                !implies -1            (direction mismatch)
+//   +1                !implies +1:nulls-last (NULL ordering mismatch)
 //   +1                !implies +1,-2         (prefix matching not commutative)
 //   +1 opt(2)         !implies +1            (extra optional cols not allowed)
 //   +1 opt(2)         !implies +1 opt(3)
@@ -296,7 +314,7 @@ func (oc *OrderingChoice) Implies(other *OrderingChoice) bool {
 		leftCol, rightCol := &oc.Columns[left], &other.Columns[right]
 
 		switch {
-		case leftCol.Descending == rightCol.Descending && leftCol.Group.SubsetOf(rightCol.Group):
+		case leftCol.sameDirection(rightCol) && leftCol.Group.SubsetOf(rightCol.Group):
 			// The columns match.
 			left, right = left+1, right+1
 
@@ -317,7 +335,7 @@ func (oc *OrderingChoice) Intersects(other *OrderingChoice) bool {
 	for left, right := 0, 0; left < len(oc.Columns) && right < len(other.Columns); {
 		leftCol, rightCol := &oc.Columns[left], &other.Columns[right]
 		switch {
-		case leftCol.Descending == rightCol.Descending && leftCol.Group.Intersects(rightCol.Group):
+		case leftCol.sameDirection(rightCol) && leftCol.Group.Intersects(rightCol.Group):
 			// The columns match.
 			left, right = left+1, right+1
 
@@ -371,27 +389,30 @@ func (oc *OrderingChoice) Intersection(other *OrderingChoice) OrderingChoice {
 		leftCol, rightCol := &oc.Columns[left], &other.Columns[right]
 
 		switch {
-		case leftCol.Descending == rightCol.Descending && leftCol.Group.Intersects(rightCol.Group):
+		case leftCol.sameDirection(rightCol) && leftCol.Group.Intersects(rightCol.Group):
 			// The columns match.
 			result = append(result, OrderingColumnChoice{
-				Group:      leftCol.Group.Intersection(rightCol.Group),
-				Descending: leftCol.Descending,
+				Group:         leftCol.Group.Intersection(rightCol.Group),
+				Descending:    leftCol.Descending,
+				NullsReversed: leftCol.NullsReversed,
 			})
 			left, right = left+1, right+1
 
 		case leftCol.Group.Intersects(other.Optional):
 			// Left column is optional in the right set.
 			result = append(result, OrderingColumnChoice{
-				Group:      leftCol.Group.Intersection(other.Optional),
-				Descending: leftCol.Descending,
+				Group:         leftCol.Group.Intersection(other.Optional),
+				Descending:    leftCol.Descending,
+				NullsReversed: leftCol.NullsReversed,
 			})
 			left++
 
 		case rightCol.Group.Intersects(oc.Optional):
 			// Right column is optional in the left set.
 			result = append(result, OrderingColumnChoice{
-				Group:      rightCol.Group.Intersection(oc.Optional),
-				Descending: rightCol.Descending,
+				Group:         rightCol.Group.Intersection(oc.Optional),
+				Descending:    rightCol.Descending,
+				NullsReversed: rightCol.NullsReversed,
 			})
 			right++
 
@@ -451,13 +472,13 @@ func (oc *OrderingChoice) CanProjectCols(cs opt.ColSet) bool {
 
 // MatchesAt returns true if the ordering column at the given index in this
 // instance matches the given column. The column matches if its id is part of
-// the equivalence group and if it has the same direction.
+// the equivalence group and if it has the same direction and NULL ordering.
 func (oc *OrderingChoice) MatchesAt(index int, col opt.OrderingColumn) bool {
 	if oc.Optional.Contains(col.ID()) {
 		return true
 	}
 	choice := &oc.Columns[index]
-	if choice.Descending != col.Descending() {
+	if choice.Descending != col.Descending() || choice.NullsReversed != col.NullsReversed() {
 		return false
 	}
 	if !choice.Group.Contains(col.ID()) {
@@ -672,7 +693,7 @@ func (oc OrderingChoice) PrefixIntersection(
 			return result, true
 		case prefix.Empty() && len(oc.Columns) > 0 && len(suffix) > 0 &&
 			oc.Columns[0].Group.Intersects(suffix[0].Group) &&
-			oc.Columns[0].Descending == suffix[0].Descending:
+			oc.Columns[0].sameDirection(&suffix[0]):
 			// <prefix> is empty, and <suffix> and <oc> agree on the first column, so
 			// emit that column, remove it from both, and loop.
 			newCol := oc.Columns[0]
@@ -709,7 +730,7 @@ func (oc *OrderingChoice) Equals(rhs *OrderingChoice) bool {
 		left := &oc.Columns[i]
 		y := &rhs.Columns[i]
 
-		if left.Descending != y.Descending {
+		if !left.sameDirection(y) {
 			return false
 		}
 		if !left.Group.Equals(y.Group) {
@@ -733,6 +754,7 @@ func (oc OrderingChoice) String() string {
 //   +(1|2)
 //   +(1|2),+3
 //   -(3|4),+5 opt(1,2)
+//   +1:nulls-last
 //
 func (oc OrderingChoice) Format(buf *bytes.Buffer) {
 	for g := range oc.Columns {
@@ -762,6 +784,14 @@ func (oc OrderingChoice) Format(buf *bytes.Buffer) {
 			buf.WriteByte(')')
 		}
 
+		if group.NullsReversed {
+			if group.Descending {
+				buf.WriteString(":nulls-first")
+			} else {
+				buf.WriteString(":nulls-last")
+			}
+		}
+
 		if g+1 != len(oc.Columns) {
 			buf.WriteByte(',')
 		}
@@ -785,3 +815,9 @@ func (oc *OrderingColumnChoice) AnyID() opt.ColumnID {
 	}
 	return id
 }
+
+// sameDirection returns true if the two ordering columns have the same
+// direction and NULL ordering.
+func (oc *OrderingColumnChoice) sameDirection(other *OrderingColumnChoice) bool {
+	return oc.Descending == other.Descending && oc.NullsReversed == other.NullsReversed
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// CreateFunction creates a test function from a parsed DDL statement and adds
+// it to the catalog. This is intended for testing, and is not a complete (and
+// probably not fully correct) implementation. It just has to be "good enough".
+func (tc *Catalog) CreateFunction(stmt *tree.CreateFunction) *Function {
+	fnName := stmt.FuncName.ToTableName()
+	tc.qualifyTableName(&fnName)
+
+	opts, err := stmt.Options.Values()
+	if err != nil {
+		panic(err)
+	}
+
+	fn := &Function{
+		FuncID:   tc.nextStableID(),
+		FuncName: fnName,
+		Params:   make([]FuncParam, len(stmt.Params)),
+		Vol:      opts.TreeVolatility(),
+		Strict:   opts.IsStrict(),
+		BodyText: opts.Body,
+	}
+	for i := range stmt.Params {
+		typ, err := tree.ResolveType(context.Background(), stmt.Params[i].Type, tc)
+		if err != nil {
+			panic(err)
+		}
+		fn.Params[i] = FuncParam{Name: stmt.Params[i].Name, Type: typ}
+	}
+	fn.RetType, err = tree.ResolveType(context.Background(), stmt.ReturnType, tc)
+	if err != nil {
+		panic(err)
+	}
+
+	tc.AddFunction(fn)
+	return fn
+}
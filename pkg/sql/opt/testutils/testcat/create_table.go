@@ -191,7 +191,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.WithoutIndex)
+				tab.addUniqueConstraint(def.Name, def.Columns, def.WithoutIndex, def.Deferrability)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -202,6 +202,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.ColumnTableDef:
 			if def.Unique.IsUnique {
 				if def.Unique.WithoutIndex {
@@ -209,6 +212,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						def.Unique.ConstraintName,
 						tree.IndexElemList{{Column: def.Name}},
 						def.Unique.WithoutIndex,
+						tree.NotDeferrableConstraint,
 					)
 				} else {
 					tab.addIndex(
@@ -477,13 +481,18 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrable:               d.Deferrability.Deferrable(),
+		initiallyDeferred:        d.Deferrability == tree.DeferrableInitiallyDeferred,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	cols := make([]int, len(columns))
 	for i, c := range columns {
@@ -505,10 +514,27 @@ func (tt *Table) addUniqueConstraint(
 		columnOrdinals: cols,
 		withoutIndex:   withoutIndex,
 		validated:      true,
+
+		deferrable:        deferrability.Deferrable(),
+		initiallyDeferred: deferrability == tree.DeferrableInitiallyDeferred,
 	}
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
 
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	e := ExclusionConstraint{
+		name:           string(def.Name),
+		tabID:          tt.TabID,
+		columnOrdinals: make([]int, len(def.Elems)),
+		operators:      make([]tree.ComparisonOperator, len(def.Elems)),
+	}
+	for i := range def.Elems {
+		e.columnOrdinals[i] = tt.FindOrdinal(string(def.Elems[i].Column))
+		e.operators[i] = def.Elems[i].Operator
+	}
+	tt.exclusionConstraints = append(tt.exclusionConstraints, e)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	ordinal := len(tt.Columns)
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
@@ -573,7 +599,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, false /* withoutIndex */, tree.NotDeferrableConstraint,
+		)
 	}
 
 	idx := &Index{
@@ -653,7 +681,7 @@ func (tt *Table) addIndexWithVersion(
 		// Add the rest of the columns in the table.
 		for i, col := range tt.Columns {
 			if !pkOrdinals.Contains(i) && col.Kind() != cat.VirtualInverted && !col.IsVirtualComputed() {
-				idx.addColumnByOrdinal(tt, i, tree.Ascending, nonKeyCol)
+				idx.addColumnByOrdinal(tt, i, tree.Ascending, false /* nullsReversed */, nonKeyCol)
 			}
 		}
 		if len(tt.Indexes) != 0 {
@@ -802,7 +830,9 @@ func (ti *Index) addColumn(
 			panic("expression-based inverted column not supported")
 		}
 		col := columnForIndexElemExpr(tt, elem.Expr)
-		return ti.addColumnByOrdinal(tt, col.Ordinal(), elem.Direction, colType)
+		return ti.addColumnByOrdinal(
+			tt, col.Ordinal(), elem.Direction, elem.NullsOrder.IsReversed(elem.Direction), colType,
+		)
 	}
 
 	ordinal := tt.FindOrdinal(string(elem.Column))
@@ -826,7 +856,9 @@ func (ti *Index) addColumn(
 		ordinal = col.Ordinal()
 	}
 
-	return ti.addColumnByOrdinal(tt, ordinal, elem.Direction, colType)
+	return ti.addColumnByOrdinal(
+		tt, ordinal, elem.Direction, elem.NullsOrder.IsReversed(elem.Direction), colType,
+	)
 }
 
 // columnForIndexElemExpr returns a VirtualComputed table column that can be
@@ -870,13 +902,13 @@ func columnForIndexElemExpr(tt *Table, expr tree.Expr) cat.Column {
 }
 
 func (ti *Index) addColumnByOrdinal(
-	tt *Table, ord int, direction tree.Direction, colType colType,
+	tt *Table, ord int, direction tree.Direction, nullsReversed bool, colType colType,
 ) *cat.Column {
 	col := tt.Column(ord)
 	if colType == keyCol || colType == strictKeyCol {
 		typ := col.DatumType()
 		if col.Kind() == cat.VirtualInverted {
-			if !colinfo.ColumnTypeIsInvertedIndexable(typ) {
+			if !colinfo.ColumnTypeIsInvertedIndexable(typ) && !colinfo.ColumnTypeIsTrigramIndexable(typ) {
 				panic(fmt.Errorf(
 					"column %s of type %s is not allowed as the last column of an inverted index",
 					col.ColName(), typ,
@@ -887,8 +919,9 @@ func (ti *Index) addColumnByOrdinal(
 		}
 	}
 	idxCol := cat.IndexColumn{
-		Column:     col,
-		Descending: direction == tree.Descending,
+		Column:        col,
+		Descending:    direction == tree.Descending,
+		NullsReversed: nullsReversed,
 	}
 	ti.Columns = append(ti.Columns, idxCol)
 
@@ -0,0 +1,41 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// CreateTrigger adds a trigger to a test table from a parsed DDL statement.
+// The trigger body is stored as it was written.
+func (tc *Catalog) CreateTrigger(stmt *tree.CreateTrigger) {
+	tn := stmt.Table.ToTableName()
+	// Update the table name to include catalog and schema if not provided.
+	tc.qualifyTableName(&tn)
+	tab := tc.Table(&tn)
+
+	trigger := cat.Trigger{
+		Name:       string(stmt.Name),
+		ActionTime: stmt.ActionTime,
+		Events:     stmt.Events,
+		Body:       stmt.Body,
+	}
+	if stmt.When != nil {
+		trigger.When = tree.Serialize(stmt.When)
+	}
+	tab.Triggers = append(tab.Triggers, trigger)
+	sort.Slice(tab.Triggers, func(i, j int) bool {
+		return tab.Triggers[i].Name < tab.Triggers[j].Name
+	})
+}
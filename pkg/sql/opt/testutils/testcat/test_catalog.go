@@ -45,6 +45,7 @@ const (
 type Catalog struct {
 	tree.TypeReferenceResolver
 	testSchema Schema
+	functions  map[string]*Function
 	counter    int
 }
 
@@ -178,6 +179,19 @@ func (tc *Catalog) ResolveTypeByOID(context.Context, oid.Oid) (*types.T, error)
 	return nil, errors.Newf("test catalog cannot handle user defined types")
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (tc *Catalog) ResolveFunction(
+	_ context.Context, _ cat.Flags, name *tree.UnresolvedObjectName,
+) (cat.Function, cat.SchemaName, error) {
+	fnName := name.ToTableName()
+	tc.qualifyTableName(&fnName)
+	if fn, ok := tc.functions[fnName.FQString()]; ok {
+		return fn, fnName.ObjectNamePrefix, nil
+	}
+	return nil, cat.SchemaName{}, pgerror.Newf(pgcode.UndefinedFunction,
+		"function %s does not exist", tree.ErrString(name))
+}
+
 // CheckPrivilege is part of the cat.Catalog interface.
 func (tc *Catalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	return tc.CheckAnyPrivilege(ctx, o)
@@ -202,6 +216,10 @@ func (tc *Catalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error {
 		if t.Revoked {
 			return pgerror.Newf(pgcode.InsufficientPrivilege, "user does not have privilege to access %v", t.SeqName)
 		}
+	case *Function:
+		if t.Revoked {
+			return pgerror.Newf(pgcode.InsufficientPrivilege, "user does not have privilege to access %v", t.FuncName)
+		}
 	default:
 		panic("invalid Object")
 	}
@@ -327,6 +345,19 @@ func (tc *Catalog) AddSequence(seq *Sequence) {
 	tc.testSchema.dataSources[fq] = seq
 }
 
+// AddFunction adds the given test function to the catalog.
+func (tc *Catalog) AddFunction(fn *Function) {
+	fq := fn.FuncName.FQString()
+	if _, ok := tc.functions[fq]; ok {
+		panic(pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q already exists", tree.ErrString(&fn.FuncName)))
+	}
+	if tc.functions == nil {
+		tc.functions = make(map[string]*Function)
+	}
+	tc.functions[fq] = fn
+}
+
 // ExecuteMultipleDDL parses the given semicolon-separated DDL SQL statements
 // and applies each of them to the test catalog.
 func (tc *Catalog) ExecuteMultipleDDL(sql string) error {
@@ -395,6 +426,14 @@ func (tc *Catalog) ExecuteDDLWithIndexVersion(
 		tc.CreateSequence(stmt)
 		return "", nil
 
+	case *tree.CreateFunction:
+		tc.CreateFunction(stmt)
+		return "", nil
+
+	case *tree.CreateTrigger:
+		tc.CreateTrigger(stmt)
+		return "", nil
+
 	case *tree.SetZoneConfig:
 		tc.SetZoneConfig(stmt)
 		return "", nil
@@ -570,6 +609,88 @@ func (tv *View) ColumnName(i int) tree.Name {
 	return tv.ColumnNames[i]
 }
 
+// FuncParam is a parameter of a test function.
+type FuncParam struct {
+	Name tree.Name
+	Type *types.T
+}
+
+// Function implements the cat.Function interface for testing purposes.
+type Function struct {
+	FuncID   cat.StableID
+	FuncName tree.TableName
+	Params   []FuncParam
+	RetType  *types.T
+	Vol      tree.Volatility
+	Strict   bool
+	BodyText string
+
+	// If Revoked is true, then the user has had privileges on the function
+	// revoked.
+	Revoked bool
+}
+
+var _ cat.Function = &Function{}
+
+// ID is part of the cat.Object interface.
+func (tf *Function) ID() cat.StableID {
+	return tf.FuncID
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (tf *Function) PostgresDescriptorID() cat.StableID {
+	return tf.FuncID
+}
+
+// Equals is part of the cat.Object interface.
+func (tf *Function) Equals(other cat.Object) bool {
+	otherFunc, ok := other.(*Function)
+	if !ok {
+		return false
+	}
+	return tf.FuncID == otherFunc.FuncID
+}
+
+// Name is part of the cat.Function interface.
+func (tf *Function) Name() tree.Name {
+	return tf.FuncName.ObjectName
+}
+
+// ParamCount is part of the cat.Function interface.
+func (tf *Function) ParamCount() int {
+	return len(tf.Params)
+}
+
+// ParamName is part of the cat.Function interface.
+func (tf *Function) ParamName(i int) tree.Name {
+	return tf.Params[i].Name
+}
+
+// ParamType is part of the cat.Function interface.
+func (tf *Function) ParamType(i int) *types.T {
+	return tf.Params[i].Type
+}
+
+// ReturnType is part of the cat.Function interface.
+func (tf *Function) ReturnType() *types.T {
+	return tf.RetType
+}
+
+// Volatility is part of the cat.Function interface.
+func (tf *Function) Volatility() tree.Volatility {
+	return tf.Vol
+}
+
+// IsStrict is part of the cat.Function interface.
+func (tf *Function) IsStrict() bool {
+	return tf.Strict
+}
+
+// Body is part of the cat.Function interface.
+func (tf *Function) Body() string {
+	return tf.BodyText
+}
+
 // Table implements the cat.Table interface for testing purposes.
 type Table struct {
 	TabID      cat.StableID
@@ -580,6 +701,7 @@ type Table struct {
 	Stats      TableStats
 	Checks     []cat.CheckConstraint
 	Families   []*Family
+	Triggers   []cat.Trigger
 	IsVirtual  bool
 	Catalog    cat.Catalog
 
@@ -597,6 +719,8 @@ type Table struct {
 	inboundFKs  []ForeignKeyConstraint
 
 	uniqueConstraints []UniqueConstraint
+
+	exclusionConstraints []ExclusionConstraint
 }
 
 var _ cat.Table = &Table{}
@@ -736,6 +860,26 @@ func (tt *Table) Unique(i int) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (tt *Table) ExclusionCount() int {
+	return len(tt.exclusionConstraints)
+}
+
+// Exclusion is part of the cat.Table interface.
+func (tt *Table) Exclusion(i int) cat.ExclusionConstraint {
+	return &tt.exclusionConstraints[i]
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return len(tt.Triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	return tt.Triggers[i]
+}
+
 // FindOrdinal returns the ordinal of the column with the given name.
 func (tt *Table) FindOrdinal(name string) int {
 	for i, col := range tt.Columns {
@@ -1075,6 +1219,9 @@ type ForeignKeyConstraint struct {
 	matchMethod  tree.CompositeKeyMatchMethod
 	deleteAction tree.ReferenceAction
 	updateAction tree.ReferenceAction
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1132,6 +1279,16 @@ func (fk *ForeignKeyConstraint) MatchMethod() tree.CompositeKeyMatchMethod {
 	return fk.matchMethod
 }
 
+// Deferrable is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrable() bool {
+	return fk.deferrable
+}
+
+// InitiallyDeferred is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) InitiallyDeferred() bool {
+	return fk.initiallyDeferred
+}
+
 // DeleteReferenceAction is part of the cat.ForeignKeyConstraint interface.
 func (fk *ForeignKeyConstraint) DeleteReferenceAction() tree.ReferenceAction {
 	return fk.deleteAction
@@ -1150,6 +1307,9 @@ type UniqueConstraint struct {
 	columnOrdinals []int
 	withoutIndex   bool
 	validated      bool
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.UniqueConstraint = &UniqueConstraint{}
@@ -1190,6 +1350,63 @@ func (u *UniqueConstraint) Validated() bool {
 	return u.validated
 }
 
+// Deferrable is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrable() bool {
+	return u.deferrable
+}
+
+// InitiallyDeferred is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) InitiallyDeferred() bool {
+	return u.initiallyDeferred
+}
+
+// ExclusionConstraint implements cat.ExclusionConstraint. See that interface
+// for more information on the fields.
+type ExclusionConstraint struct {
+	name           string
+	tabID          cat.StableID
+	columnOrdinals []int
+	operators      []tree.ComparisonOperator
+}
+
+var _ cat.ExclusionConstraint = &ExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Name() string {
+	return e.name
+}
+
+// TableID is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) TableID() cat.StableID {
+	return e.tabID
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) ColumnCount() int {
+	return len(e.columnOrdinals)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.tabID {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.tabID,
+		))
+	}
+	return e.columnOrdinals[i]
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Operator(i int) tree.ComparisonOperator {
+	return e.operators[i]
+}
+
+// Validated is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Validated() bool {
+	return true
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
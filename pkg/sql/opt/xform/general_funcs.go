@@ -144,7 +144,7 @@ func (c *CustomFuncs) initIdxConstraintForIndex(
 			nullable = col.IsNullable()
 		}
 		colID := tabID.ColumnID(ordinal)
-		columns[i] = opt.MakeOrderingColumn(colID, col.Descending)
+		columns[i] = opt.MakeOrderingColumnWithNulls(colID, col.Descending, col.NullsReversed)
 		if !nullable {
 			notNullCols.Add(colID)
 		}
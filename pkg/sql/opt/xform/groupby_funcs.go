@@ -80,7 +80,8 @@ func (c *CustomFuncs) GenerateStreamingGroupBy(
 
 			if intraIdx < len(intraOrd.Columns) &&
 				intraOrd.Columns[intraIdx].Group.Contains(oCol) &&
-				intraOrd.Columns[intraIdx].Descending == o[oIdx].Descending() {
+				intraOrd.Columns[intraIdx].Descending == o[oIdx].Descending() &&
+				intraOrd.Columns[intraIdx].NullsReversed == o[oIdx].NullsReversed() {
 				// Column matches the one in the ordering.
 				intraIdx++
 				continue
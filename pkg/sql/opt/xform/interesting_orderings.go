@@ -88,7 +88,9 @@ func interestingOrderingsForScan(scan *memo.ScanExpr) opt.OrderingSet {
 			if o == nil {
 				o = make(opt.Ordering, 0, numIndexCols)
 			}
-			o = append(o, opt.MakeOrderingColumn(colID, indexCol.Descending))
+			o = append(o, opt.MakeOrderingColumnWithNulls(
+				colID, indexCol.Descending, indexCol.NullsReversed,
+			))
 		}
 		if o != nil {
 			ord.Add(o)
@@ -613,7 +613,8 @@ func deriveInterestingOrderingPrefix(
 		for i, orderingCol := range ordering {
 			if i < len(requiredOrdering.Columns) &&
 				requiredOrdering.Columns[i].Group.Contains(orderingCol.ID()) &&
-				requiredOrdering.Columns[i].Descending == orderingCol.Descending() {
+				requiredOrdering.Columns[i].Descending == orderingCol.Descending() &&
+				requiredOrdering.Columns[i].NullsReversed == orderingCol.NullsReversed() {
 				commonPrefix = append(commonPrefix, orderingCol)
 			} else {
 				break
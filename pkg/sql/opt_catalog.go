@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -259,6 +260,34 @@ func (oc *optCatalog) ResolveType(
 	return oc.planner.ResolveType(ctx, name)
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunction(
+	ctx context.Context, flags cat.Flags, name *tree.UnresolvedObjectName,
+) (cat.Function, cat.SchemaName, error) {
+	if flags.AvoidDescriptorCaches {
+		defer func(prev bool) {
+			oc.planner.avoidCachedDescriptors = prev
+		}(oc.planner.avoidCachedDescriptors)
+		oc.planner.avoidCachedDescriptors = true
+	}
+
+	lflags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: true},
+		DesiredObjectKind: tree.FunctionObject,
+	}
+	desc, prefix, err := resolver.ResolveExistingObject(ctx, oc.planner, name, lflags)
+	if err != nil {
+		return nil, cat.SchemaName{}, err
+	}
+	fn := desc.(*funcdesc.Immutable)
+
+	// Ensure that the current user can access the target schema.
+	if err := oc.planner.canResolveDescUnderSchema(ctx, fn.GetParentSchemaID(), fn); err != nil {
+		return nil, cat.SchemaName{}, err
+	}
+	return newOptFunction(fn), prefix, nil
+}
+
 func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, error) {
 	switch t := o.(type) {
 	case *optSchema:
@@ -271,6 +300,8 @@ func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, e
 		return t.desc, nil
 	case *optSequence:
 		return t.desc, nil
+	case *optFunction:
+		return t.desc, nil
 	default:
 		return nil, errors.AssertionFailedf("invalid object type: %T", o)
 	}
@@ -516,6 +547,77 @@ func (ov *optView) ColumnName(i int) tree.Name {
 	return tree.Name(ov.desc.Columns[i].Name)
 }
 
+// optFunction is a wrapper around funcdesc.Immutable that implements the
+// cat.Object and cat.Function interfaces.
+type optFunction struct {
+	desc *funcdesc.Immutable
+}
+
+var _ cat.Function = &optFunction{}
+
+func newOptFunction(desc *funcdesc.Immutable) *optFunction {
+	return &optFunction{desc: desc}
+}
+
+// ID is part of the cat.Object interface.
+func (of *optFunction) ID() cat.StableID {
+	return cat.StableID(of.desc.ID)
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (of *optFunction) PostgresDescriptorID() cat.StableID {
+	return cat.StableID(of.desc.ID)
+}
+
+// Equals is part of the cat.Object interface.
+func (of *optFunction) Equals(other cat.Object) bool {
+	otherFunc, ok := other.(*optFunction)
+	if !ok {
+		return false
+	}
+	return of.desc.ID == otherFunc.desc.ID && of.desc.Version == otherFunc.desc.Version
+}
+
+// Name is part of the cat.Function interface.
+func (of *optFunction) Name() tree.Name {
+	return tree.Name(of.desc.Name)
+}
+
+// ParamCount is part of the cat.Function interface.
+func (of *optFunction) ParamCount() int {
+	return len(of.desc.Params)
+}
+
+// ParamName is part of the cat.Function interface.
+func (of *optFunction) ParamName(i int) tree.Name {
+	return tree.Name(of.desc.Params[i].Name)
+}
+
+// ParamType is part of the cat.Function interface.
+func (of *optFunction) ParamType(i int) *types.T {
+	return of.desc.Params[i].Type
+}
+
+// ReturnType is part of the cat.Function interface.
+func (of *optFunction) ReturnType() *types.T {
+	return of.desc.ReturnType
+}
+
+// Volatility is part of the cat.Function interface.
+func (of *optFunction) Volatility() tree.Volatility {
+	return of.desc.TreeVolatility()
+}
+
+// IsStrict is part of the cat.Function interface.
+func (of *optFunction) IsStrict() bool {
+	return of.desc.IsStrict()
+}
+
+// Body is part of the cat.Function interface.
+func (of *optFunction) Body() string {
+	return of.desc.FunctionBody
+}
+
 // optSequence is a wrapper around sqlbase.Immutable that
 // implements the cat.Object and cat.DataSource interfaces.
 type optSequence struct {
@@ -597,6 +699,8 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -605,6 +709,9 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []cat.CheckConstraint
 
+	// triggers is the set of enabled triggers on this table, ordered by name.
+	triggers []cat.Trigger
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -766,11 +873,24 @@ func newOptTable(
 	for i := range ot.desc.UniqueWithoutIndexConstraints {
 		u := &ot.desc.UniqueWithoutIndexConstraints[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:              u.Name,
+			table:             ot.ID(),
+			columns:           u.ColumnIDs,
+			withoutIndex:      true,
+			validity:          u.Validity,
+			deferrable:        u.Deferrable,
+			initiallyDeferred: u.InitiallyDeferred,
+		})
+	}
+
+	ot.exclusionConstraints = make([]optExclusionConstraint, 0, len(ot.desc.ExclusionConstraints))
+	for i := range ot.desc.ExclusionConstraints {
+		ec := &ot.desc.ExclusionConstraints[i]
+		ot.exclusionConstraints = append(ot.exclusionConstraints, optExclusionConstraint{
+			name:     ec.Name,
+			table:    ot.ID(),
+			desc:     ec,
+			validity: ec.Validity,
 		})
 	}
 
@@ -786,6 +906,8 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrable:        fk.Deferrable,
+			initiallyDeferred: fk.InitiallyDeferred,
 		})
 	}
 	for i := range ot.desc.InboundFKs {
@@ -800,6 +922,8 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrable:        fk.Deferrable,
+			initiallyDeferred: fk.InitiallyDeferred,
 		})
 	}
 
@@ -845,6 +969,25 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// Move all enabled triggers into the opt table. The triggers are kept
+	// sorted by name in the descriptor.
+	for i := range desc.Triggers {
+		t := &desc.Triggers[i]
+		if t.Disabled {
+			continue
+		}
+		trigger := cat.Trigger{
+			Name:       t.Name,
+			ActionTime: tree.TriggerActionTime(t.ActionTime),
+			When:       t.WhenExpr,
+			Body:       t.Body,
+		}
+		for _, e := range t.Events {
+			trigger.Events = append(trigger.Events, tree.TriggerEvent(e))
+		}
+		ot.triggers = append(ot.triggers, trigger)
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1072,6 +1215,26 @@ func (ot *optTable) Unique(i int) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optTable) Exclusion(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return ot.triggers[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1228,8 +1391,9 @@ func (oi *optIndex) Column(i int) cat.IndexColumn {
 			ord, _ = oi.tab.lookupColumnOrdinal(oi.desc.ColumnIDs[i])
 		}
 		return cat.IndexColumn{
-			Column:     oi.tab.Column(ord),
-			Descending: oi.desc.ColumnDirections[i] == descpb.IndexDescriptor_DESC,
+			Column:        oi.tab.Column(ord),
+			Descending:    oi.desc.ColumnDirections[i] == descpb.IndexDescriptor_DESC,
+			NullsReversed: oi.desc.ColumnNullsReversedAt(i),
 		}
 	}
 
@@ -1472,6 +1636,9 @@ type optUniqueConstraint struct {
 
 	withoutIndex bool
 	validity     descpb.ConstraintValidity
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
@@ -1514,6 +1681,66 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrable is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrable() bool {
+	return u.deferrable
+}
+
+// InitiallyDeferred is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) InitiallyDeferred() bool {
+	return u.initiallyDeferred
+}
+
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// exclusion constraint.
+type optExclusionConstraint struct {
+	name string
+
+	table    cat.StableID
+	desc     *descpb.ExclusionConstraint
+	validity descpb.ConstraintValidity
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.name
+}
+
+// TableID is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) TableID() cat.StableID {
+	return e.table
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return len(e.desc.ColumnIDs)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := tab.(*optTable)
+	ord, _ := optTab.lookupColumnOrdinal(e.desc.ColumnIDs[i])
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) tree.ComparisonOperator {
+	return e.desc.Operator(i)
+}
+
+// Validated is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Validated() bool {
+	return e.validity == descpb.ConstraintValidity_Validated
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1531,6 +1758,9 @@ type optForeignKeyConstraint struct {
 	match        descpb.ForeignKeyReference_Match
 	deleteAction descpb.ForeignKeyReference_Action
 	updateAction descpb.ForeignKeyReference_Action
+
+	deferrable        bool
+	initiallyDeferred bool
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1592,6 +1822,16 @@ func (fk *optForeignKeyConstraint) MatchMethod() tree.CompositeKeyMatchMethod {
 	return descpb.ForeignKeyReferenceMatchValue[fk.match]
 }
 
+// Deferrable is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrable() bool {
+	return fk.deferrable
+}
+
+// InitiallyDeferred is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) InitiallyDeferred() bool {
+	return fk.initiallyDeferred
+}
+
 // DeleteReferenceAction is part of the cat.ForeignKeyConstraint interface.
 func (fk *optForeignKeyConstraint) DeleteReferenceAction() tree.ReferenceAction {
 	return descpb.ForeignKeyReferenceActionType[fk.deleteAction]
@@ -1895,6 +2135,26 @@ func (ot *optVirtualTable) Unique(i int) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionCount() int {
+	return 0
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optVirtualTable) Exclusion(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the indexes
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
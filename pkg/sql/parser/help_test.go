@@ -105,6 +105,8 @@ func TestContextualHelp(t *testing.T) {
 		{`CANCEL SESSIONS IF ??`, `CANCEL SESSIONS`},
 		{`CANCEL SESSIONS IF EXISTS ??`, `CANCEL SESSIONS`},
 
+		{`CLOSE ??`, `CLOSE`},
+
 		{`CREATE UNIQUE ??`, `CREATE`},
 		{`CREATE UNIQUE INDEX ??`, `CREATE INDEX`},
 		{`CREATE INDEX IF NOT ??`, `CREATE INDEX`},
@@ -145,6 +147,15 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION f(??`, `CREATE FUNCTION`},
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER t BEFORE ??`, `CREATE TRIGGER`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+		{`DROP TRIGGER t ON ??`, `DROP TRIGGER`},
+
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA bli ??`, `CREATE SCHEMA`},
@@ -154,6 +165,9 @@ func TestContextualHelp(t *testing.T) {
 		{`DELETE FROM blah WHERE ??`, `DELETE`},
 		{`DELETE FROM blah WHERE x > 3 ??`, `DELETE`},
 
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE foo ??`, `DECLARE`},
+
 		{`DISCARD ALL ??`, `DISCARD`},
 		{`DISCARD ??`, `DISCARD`},
 
@@ -214,6 +228,10 @@ func TestContextualHelp(t *testing.T) {
 		{`DEALLOCATE ALL ??`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ??`, `DEALLOCATE`},
 
+		{`FETCH ??`, `FETCH`},
+		{`FETCH FORWARD ??`, `FETCH`},
+		{`MOVE ??`, `MOVE`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -390,6 +408,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SET SESSION blah TO ??`, `SET SESSION`},
 		{`SET SESSION blah TO 42 ??`, `SET SESSION`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
@@ -442,6 +463,8 @@ func TestContextualHelp(t *testing.T) {
 		{`EXPORT INTO CSV 'a' ??`, `EXPORT`},
 		{`EXPORT INTO CSV 'a' FROM SELECT a ??`, `SELECT`},
 		{`CREATE SCHEDULE FOR BACKUP ??`, `CREATE SCHEDULE FOR BACKUP`},
+		{`CREATE SCHEDULE FOR EXPORT ??`, `CREATE SCHEDULE FOR EXPORT`},
+		{`CREATE SCHEDULE FOR CHANGEFEED ??`, `CREATE SCHEDULE FOR CHANGEFEED`},
 	}
 
 	// The following checks that the test definition above exercises all
@@ -111,6 +111,7 @@ func TestParse(t *testing.T) {
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c.d (e)`},
 		{`CREATE INDEX ON a (b ASC, c DESC)`},
 		{`CREATE INDEX ON a (b NULLS FIRST, c ASC NULLS FIRST, d DESC NULLS LAST)`},
+		{`CREATE INDEX ON a (b NULLS LAST, c ASC NULLS LAST, d DESC NULLS FIRST)`},
 		{`CREATE INDEX IF NOT EXISTS i ON a (b) WHERE c > 3`},
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
@@ -124,6 +125,9 @@ func TestParse(t *testing.T) {
 		{`CREATE INVERTED INDEX a ON b (c) WHERE d > 3`},
 		{`CREATE INVERTED INDEX a ON b (c) INTERLEAVE IN PARENT d (e)`},
 		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b (c) WHERE d > 3`},
+		{`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
+		{`CREATE INVERTED INDEX a ON b (c, d gin_trgm_ops)`},
+		{`CREATE TABLE a (b STRING, INVERTED INDEX (b gin_trgm_ops))`},
 		{`CREATE INDEX a ON b (c) WITH (fillfactor = 100, y_bounds = 50)`},
 
 		{`CREATE INDEX ON a ((a + b))`},
@@ -233,10 +237,17 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT ON UPDATE SET NULL)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, c STRING, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8 REFERENCES other (x) MATCH FULL DEFERRABLE, c STRING)`},
+		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE WITHOUT INDEX (b, c) DEFERRABLE INITIALLY DEFERRED)`},
+		{`ALTER TABLE a ADD CONSTRAINT s FOREIGN KEY (b) REFERENCES other (x) DEFERRABLE INITIALLY DEFERRED`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE WITHOUT INDEX (b, c))`},
+		{`CREATE TABLE a (b INT8, c INT8[], CONSTRAINT d EXCLUDE USING gist (b WITH =, c WITH &&))`},
+		{`CREATE TABLE a (b INT8, c INT8, EXCLUDE USING gist (b WITH !=))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
@@ -374,6 +385,8 @@ func TestParse(t *testing.T) {
 		{`REFRESH MATERIALIZED VIEW CONCURRENTLY a.b`},
 		{`REFRESH MATERIALIZED VIEW a.b WITH DATA`},
 		{`REFRESH MATERIALIZED VIEW a.b WITH NO DATA`},
+		{`REFRESH MATERIALIZED VIEW a.b INCREMENTAL`},
+		{`REFRESH MATERIALIZED VIEW CONCURRENTLY a.b INCREMENTAL`},
 
 		{`CREATE SEQUENCE a`},
 		{`EXPLAIN CREATE SEQUENCE a`},
@@ -421,6 +434,35 @@ func TestParse(t *testing.T) {
 		{`CREATE TYPE a AS ENUM ('a', 'b', 'c')`},
 		{`CREATE TYPE a.b AS ENUM ('a', 'b', 'c')`},
 		{`CREATE TYPE a.b.c AS ENUM ('a', 'b', 'c')`},
+		{`CREATE TYPE a AS ()`},
+		{`CREATE TYPE IF NOT EXISTS a AS (b INT8)`},
+		{`CREATE TYPE a.b AS (c STRING, d DECIMAL(10,2))`},
+		{`CREATE TYPE a AS (b INT8[], c s.t)`},
+
+		{`CREATE FUNCTION f() RETURNS INT8 AS 'SELECT 1'`},
+		{`CREATE FUNCTION sc.f(a INT8, b STRING) RETURNS STRING LANGUAGE sql IMMUTABLE AS 'SELECT b || a::STRING'`},
+		{`CREATE OR REPLACE FUNCTION db.sc.f(INT8, INT8) RETURNS INT8 STABLE STRICT AS 'SELECT $1 + $2'`},
+		{`CREATE FUNCTION f(a INT8) RETURNS BOOL IMMUTABLE LEAKPROOF RETURNS NULL ON NULL INPUT AS 'SELECT a > 0'`},
+		{`CREATE FUNCTION f(a INT8[]) RETURNS INT8 VOLATILE NOT LEAKPROOF CALLED ON NULL INPUT AS 'SELECT a[1]'`},
+
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION IF EXISTS f(INT8), sc.g(a INT8, b STRING)`},
+		{`DROP FUNCTION db.sc.f CASCADE`},
+		{`DROP FUNCTION f, g RESTRICT`},
+
+		{`SHOW CREATE FUNCTION f`},
+		{`SHOW CREATE FUNCTION db.sc.f`},
+
+		{`CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW AS 'INSERT INTO b VALUES (new.x)'`},
+		{`CREATE TRIGGER t AFTER INSERT OR UPDATE OR DELETE ON db.sc.a FOR EACH ROW AS 'DELETE FROM b WHERE b.x = old.x'`},
+		{`CREATE TRIGGER t AFTER UPDATE ON a FOR EACH ROW WHEN (old.x IS DISTINCT FROM new.x) AS 'UPDATE b SET y = new.x'`},
+
+		{`DROP TRIGGER t ON a`},
+		{`DROP TRIGGER IF EXISTS t ON db.sc.a CASCADE`},
+
+		{`ALTER TABLE a ENABLE TRIGGER t`},
+		{`ALTER TABLE a DISABLE TRIGGER ALL`},
 
 		{`DROP SCHEMA a`},
 		{`DROP SCHEMA a, b`},
@@ -461,6 +503,9 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b RETURNING a + b`},
 		{`DELETE FROM a WHERE a = b RETURNING NOTHING`},
 		{`DELETE FROM a WHERE a = b ORDER BY c LIMIT d RETURNING e`},
+		{`DELETE FROM a USING b WHERE a.c = b.c`},
+		{`DELETE FROM a AS x USING b AS y, c WHERE x.d = y.d RETURNING x.e, y.f`},
+		{`DELETE FROM a USING (SELECT * FROM b) AS c WHERE a.d = c.d ORDER BY e LIMIT f RETURNING c.d`},
 
 		{`DISCARD ALL`},
 
@@ -555,6 +600,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SHOW PAUSED SCHEDULES FOR BACKUP`},
 		{`SHOW RUNNING SCHEDULES FOR BACKUP`},
 		{`EXPLAIN SHOW RUNNING SCHEDULES FOR BACKUP`},
+		{`SHOW SCHEDULES FOR EXPORT`},
+		{`SHOW PAUSED SCHEDULES FOR CHANGEFEED`},
 
 		{`EXPLAIN SELECT 1`},
 		{`EXPLAIN EXPLAIN SELECT 1`},
@@ -780,6 +827,10 @@ func TestParse(t *testing.T) {
 		{`GRANT USAGE, GRANT ON TYPE foo TO root`},
 		{`GRANT ALL ON TYPE foo TO root`},
 
+		// GRANT ON FUNCTION.
+		{`GRANT EXECUTE ON FUNCTION f TO foo`},
+		{`GRANT EXECUTE, GRANT ON FUNCTION f(INT8), sc.g TO foo, bar`},
+
 		// GRANT ON SCHEMA.
 		{`GRANT USAGE ON SCHEMA foo TO root`},
 		{`GRANT USAGE ON SCHEMA foo.bar TO root`},
@@ -804,6 +855,9 @@ func TestParse(t *testing.T) {
 		{`REVOKE USAGE, GRANT ON TYPE foo FROM root`},
 		{`REVOKE ALL ON TYPE foo FROM root`},
 
+		// REVOKE ON FUNCTION.
+		{`REVOKE EXECUTE ON FUNCTION f, g() FROM foo`},
+
 		// REVOKE ON SCHEMA.
 		{`REVOKE USAGE ON SCHEMA foo FROM root`},
 		{`REVOKE USAGE ON SCHEMA foo.bar FROM root`},
@@ -856,6 +910,10 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING 1, 2`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING a + b`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO UPDATE SET a = 1`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO UPDATE SET a = excluded.a WHERE b > 2`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO UPDATE SET a = 1 RETURNING a`},
 
 		{`SELECT 1 + 1`},
 		{`SELECT -1`},
@@ -1127,10 +1185,22 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t ORDER BY a NULLS FIRST`},
 		{`SELECT a FROM t ORDER BY a ASC NULLS FIRST`},
 		{`SELECT a FROM t ORDER BY a DESC NULLS LAST`},
+		{`SELECT a FROM t ORDER BY a NULLS LAST`},
+		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`},
+		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`},
+		{`SELECT a, rank() OVER (ORDER BY b DESC NULLS FIRST) FROM t`},
+		{`SELECT array_agg(a ORDER BY a NULLS LAST) FROM t`},
 
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
 		{`SELECT 1 FROM t GROUP BY ()`},
+		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`},
+		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b, (c, d))`},
+		{`SELECT 1 FROM t GROUP BY CUBE (b)`},
+		{`SELECT 1 FROM t GROUP BY CUBE (a, b), CUBE (c)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (a, (b, c), (), ROLLUP (d), GROUPING SETS (e))`},
+		{`SELECT GROUPING (a), GROUPING (a, b) FROM t GROUP BY CUBE (a, b)`},
 		{`SELECT sum(x ORDER BY y) FROM t`},
 		{`SELECT sum(x ORDER BY y, z) FROM t`},
 
@@ -1279,6 +1349,8 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET LOCAL a = 3`},
+		{`SET LOCAL a = DEFAULT`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
@@ -1290,6 +1362,11 @@ func TestParse(t *testing.T) {
 		{`SET TRANSACTION NOT DEFERRABLE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE, PRIORITY HIGH, AS OF SYSTEM TIME '-1s', NOT DEFERRABLE`},
 
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS a DEFERRED`},
+		{`SET CONSTRAINTS a, b IMMEDIATE`},
+
 		{`SET TRACING = off`},
 		{`EXPLAIN SET TRACING = off`},
 		{`SET TRACING = 'cluster', 'kv'`},
@@ -1420,6 +1497,8 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE IF EXISTS a ADD COLUMN b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN IF NOT EXISTS b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE a ADD COLUMN b INT8 UNIQUE WITHOUT INDEX, ADD CONSTRAINT a_no_idx UNIQUE WITHOUT INDEX (a)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =, baz WITH &&)`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =) NOT VALID`},
 		{`ALTER TABLE a ADD COLUMN IF NOT EXISTS b INT8, ADD CONSTRAINT a_idx UNIQUE (a) NOT VALID`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN IF NOT EXISTS b INT8, ADD CONSTRAINT a_idx UNIQUE (a)`},
@@ -1481,7 +1560,30 @@ func TestParse(t *testing.T) {
 		{`COPY t (a, b, c) FROM STDIN`},
 		{`COPY crdb_internal.file_upload FROM STDIN WITH destination = 'filename'`},
 		{`COPY t (a, b, c) FROM STDIN WITH BINARY`},
+		{`COPY t FROM STDIN WITH CSV HEADER DELIMITER '|' NULL 'x' QUOTE e'\'' ESCAPE e'\\'`},
+		{`COPY t (a, b) FROM STDIN WHERE a > 1`},
+		{`COPY t FROM STDIN WITH CSV WHERE (a = 1) AND (b IS NULL)`},
 		{`COPY crdb_internal.file_upload FROM STDIN WITH BINARY destination = 'filename'`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b) TO STDOUT WITH CSV HEADER DELIMITER '|' NULL 'x'`},
+		{`COPY (SELECT a FROM t WHERE b > 1) TO STDOUT WITH BINARY`},
+		{`COPY (INSERT INTO t VALUES (1) RETURNING a) TO STDOUT`},
+
+		{`DECLARE a CURSOR FOR SELECT 1`},
+		{`DECLARE a BINARY INSENSITIVE NO SCROLL CURSOR WITH HOLD FOR SELECT * FROM t`},
+		{`DECLARE a ASENSITIVE SCROLL CURSOR FOR SELECT a FROM t ORDER BY a`},
+		{`FETCH 1 a`},
+		{`FETCH -1 a`},
+		{`FETCH ALL a`},
+		{`FETCH BACKWARD ALL a`},
+		{`FETCH ABSOLUTE 3 a`},
+		{`FETCH RELATIVE -2 a`},
+		{`FETCH FIRST a`},
+		{`FETCH LAST a`},
+		{`MOVE 5 a`},
+		{`MOVE ALL a`},
+		{`CLOSE a`},
+		{`CLOSE ALL`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`EXPLAIN ALTER TABLE a SPLIT AT VALUES (1)`},
@@ -1605,6 +1707,12 @@ func TestParse(t *testing.T) {
 		{`ALTER TYPE t RENAME TO t2`},
 		{`ALTER TYPE t SET SCHEMA newschema`},
 		{`ALTER TYPE t OWNER TO foo`},
+		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar`},
+		{`ALTER TYPE t ADD ATTRIBUTE foo INT8 RESTRICT`},
+		{`ALTER TYPE t ADD ATTRIBUTE foo STRING CASCADE`},
+		{`ALTER TYPE db.s.t DROP ATTRIBUTE foo`},
+		{`ALTER TYPE t DROP ATTRIBUTE IF EXISTS foo RESTRICT`},
+		{`ALTER TYPE t DROP ATTRIBUTE foo CASCADE`},
 
 		{`REASSIGN OWNED BY foo TO bar`},
 		{`REASSIGN OWNED BY foo, bar TO third`},
@@ -1661,6 +1769,10 @@ func TestParse(t *testing.T) {
 		{`CREATE SCHEDULE FOR BACKUP TABLE foo, bar, buz INTO 'bar' RECURRING '@daily' FULL BACKUP '@weekly'`},
 		{`CREATE SCHEDULE FOR BACKUP TABLE foo, bar, buz INTO 'bar' WITH revision_history RECURRING '@daily' FULL BACKUP '@weekly'`},
 		{`CREATE SCHEDULE FOR BACKUP INTO 'bar' WITH revision_history RECURRING '@daily' FULL BACKUP '@weekly' WITH SCHEDULE OPTIONS foo = 'bar'`},
+		{`CREATE SCHEDULE FOR EXPORT INTO CSV 'bar' FROM (SELECT * FROM foo) RECURRING '@hourly'`},
+		{`CREATE SCHEDULE 'my schedule' FOR EXPORT INTO CSV 'bar' WITH delimiter = '|' FROM (SELECT a, b FROM foo) RECURRING '@daily' WITH SCHEDULE OPTIONS first_run = 'now'`},
+		{`CREATE SCHEDULE FOR CHANGEFEED TABLE foo INTO 'sink' RECURRING '@daily'`},
+		{`CREATE SCHEDULE 'my schedule' FOR CHANGEFEED TABLE foo, bar INTO 'sink' WITH format = 'csv' RECURRING '@daily' WITH SCHEDULE OPTIONS on_execution_failure = 'pause'`},
 		{`EXPLAIN BACKUP TABLE foo TO 'bar'`},
 		{`BACKUP TABLE foo.foo, baz.baz TO 'bar'`},
 
@@ -1738,6 +1850,7 @@ func TestParse(t *testing.T) {
 		{`EXPORT INTO CSV 'a' FROM TABLE a`}, // TODO(knz): Make this explainable.
 		{`EXPORT INTO CSV 'a' FROM SELECT * FROM a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM TABLE a`},
+		{`EXPORT INTO PARQUET 'a' WITH chunk_rows = '100', compression = 'gzip' FROM SELECT * FROM a`},
 		{`EXPORT INTO CSV 's3://my/path/%part%.csv' WITH delimiter = '|' FROM SELECT a, sum(b) FROM c WHERE d = 1 ORDER BY sum(b) DESC LIMIT 10`},
 
 		{`SET ROW (1, true, NULL)`},
@@ -1786,6 +1899,18 @@ func TestParse2(t *testing.T) {
 		sql      string
 		expected string
 	}{
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x) INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES c (x))`},
+		{`CREATE TABLE a (b INT8, EXCLUDE (b WITH =))`,
+			`CREATE TABLE a (b INT8, EXCLUDE USING gist (b WITH =))`},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING btree (bar WITH <>)`,
+			`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH !=)`},
+		{`CREATE TRIGGER t BEFORE DELETE ON a FOR ROW AS 'DELETE FROM b'`,
+			`CREATE TRIGGER t BEFORE DELETE ON a FOR EACH ROW AS 'DELETE FROM b'`},
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
@@ -1835,6 +1960,10 @@ func TestParse2(t *testing.T) {
 			`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b USING GIN (c)`,
 			`CREATE UNIQUE INVERTED INDEX a ON b (c)`},
+		{`CREATE INDEX a ON b USING GIN (c gin_trgm_ops)`,
+			`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
+		{`CREATE INDEX a ON b USING GIST (c gist_trgm_ops)`,
+			`CREATE INVERTED INDEX a ON b (c gist_trgm_ops)`},
 
 		{`CREATE INDEX ON a (a, (lower(b)))`,
 			`CREATE INDEX ON a (a, lower(b))`},
@@ -1933,6 +2062,10 @@ func TestParse2(t *testing.T) {
 
 		{`SELECT b <<= c`, `SELECT inet_contained_by_or_equals(b, c)`},
 		{`SELECT b >>= c`, `SELECT inet_contains_or_equals(b, c)`},
+		{`SELECT b -|- c`, `SELECT range_adjacent(b, c)`},
+		{`SELECT b &< c`, `SELECT range_overleft(b, c)`},
+		{`SELECT b &> c`, `SELECT range_overright(b, c)`},
+		{`SELECT a - -b`, `SELECT a - (-b)`},
 
 		{`SELECT NUMERIC 'foo'`, `SELECT DECIMAL 'foo'`},
 		{`SELECT REAL 'foo'`, `SELECT FLOAT4 'foo'`},
@@ -2029,6 +2162,19 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM t LIMIT 3`},
 		{`SELECT a FROM t FETCH FIRST ROW ONLY`,
 			`SELECT a FROM t LIMIT 1`},
+
+		{`DECLARE a ASENSITIVE CURSOR WITHOUT HOLD FOR SELECT 1`,
+			`DECLARE a ASENSITIVE CURSOR FOR SELECT 1`},
+		{`FETCH a`, `FETCH 1 a`},
+		{`FETCH FROM a`, `FETCH 1 a`},
+		{`FETCH NEXT FROM a`, `FETCH 1 a`},
+		{`FETCH PRIOR IN a`, `FETCH -1 a`},
+		{`FETCH FORWARD a`, `FETCH 1 a`},
+		{`FETCH BACKWARD 3 FROM a`, `FETCH -3 a`},
+		{`FETCH FORWARD ALL IN a`, `FETCH ALL a`},
+		{`FETCH ABSOLUTE 2 FROM a`, `FETCH ABSOLUTE 2 a`},
+		{`MOVE IN a`, `MOVE 1 a`},
+		{`MOVE FORWARD 10 a`, `MOVE 10 a`},
 		{`SELECT a FROM t FETCH FIRST (2 * a) ROWS ONLY`,
 			`SELECT a FROM t LIMIT (2 * a)`},
 		{`SELECT a FROM t OFFSET b FETCH FIRST (2 * a) ROWS ONLY`,
@@ -2128,6 +2274,10 @@ $function$`,
 			`SET search_path = 'public'`},
 		{`SET TIME ZONE 'pst8pdt'`,
 			`SET timezone = 'pst8pdt'`},
+		{`SET LOCAL TIME ZONE 'pst8pdt'`,
+			`SET LOCAL timezone = 'pst8pdt'`},
+		{`SET LOCAL a TO 3`,
+			`SET LOCAL a = 3`},
 		{`SET TIME ZONE 'Europe/Rome'`,
 			`SET timezone = 'Europe/Rome'`},
 		{`SET TIME ZONE -7`,
@@ -2154,6 +2304,10 @@ $function$`,
 			`SET a = "on"`},
 		{`SET a = default`,
 			`SET a = DEFAULT`},
+		{`SET pg_trgm.similarity_threshold = 0.5`,
+			`SET "pg_trgm.similarity_threshold" = 0.5`},
+		{`SHOW pg_trgm.similarity_threshold`,
+			`SHOW "pg_trgm.similarity_threshold"`},
 
 		// Special substring syntax
 		{`SELECT SUBSTRING('RoacH' from 2 for 3)`,
@@ -2243,6 +2397,10 @@ $function$`,
 			`SHOW CREATE t`},
 		{`SHOW CREATE SEQUENCE t`,
 			`SHOW CREATE t`},
+		{`CREATE FUNCTION f(a INT) RETURNS INT LANGUAGE SQL AS $$SELECT a$$`,
+			`CREATE FUNCTION f(a INT8) RETURNS INT8 LANGUAGE sql AS 'SELECT a'`},
+		{`CREATE FUNCTION f(a INT) RETURNS INT LANGUAGE 'sql' AS 'SELECT ''a'''`,
+			`CREATE FUNCTION f(a INT8) RETURNS INT8 LANGUAGE sql AS e'SELECT \'a\''`},
 		{`SHOW INDEX FROM t`,
 			`SHOW INDEXES FROM t`},
 		{`SHOW CONSTRAINT FROM t`,
@@ -2413,6 +2571,18 @@ $function$`,
 			`COPY t (a, b, c) FROM STDIN WITH BINARY`},
 		{`COPY t (a, b, c) FROM STDIN destination = 'filename' BINARY`,
 			`COPY t (a, b, c) FROM STDIN WITH BINARY destination = 'filename'`},
+		{`COPY t TO STDOUT NULL '' CSV`,
+			`COPY t TO STDOUT WITH CSV NULL ''`},
+		{`COPY t FROM STDIN WITH (FORMAT csv, HEADER, DELIMITER ';', QUOTE '"', ESCAPE '\', NULL 'NULL')`,
+			`COPY t FROM STDIN WITH CSV HEADER DELIMITER ';' NULL 'NULL' QUOTE '"' ESCAPE e'\\'`},
+		{`COPY t FROM STDIN (FORMAT binary)`,
+			`COPY t FROM STDIN WITH BINARY`},
+		{`COPY t FROM STDIN WITH (FORMAT 'text', HEADER false)`,
+			`COPY t FROM STDIN`},
+		{`COPY t FROM STDIN WITH (FORMAT csv, HEADER true) WHERE a > 1`,
+			`COPY t FROM STDIN WITH CSV HEADER WHERE a > 1`},
+		{`COPY t TO STDOUT WITH (FORMAT csv, HEADER on)`,
+			`COPY t TO STDOUT WITH CSV HEADER`},
 
 		// Identifier handling for zone configs.
 
@@ -3173,14 +3343,13 @@ func TestUnimplementedSyntax(t *testing.T) {
 		hint     string
 	}{
 		{`ALTER TABLE a ALTER CONSTRAINT foo`, 31632, `alter constraint`, ``},
-		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =)`, 46657, `add constraint exclude using`, ``},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING gist (bar WITH =) WHERE bar > 0`, 46657, `exclusion constraint with predicate`, ``},
+		{`ALTER TABLE a ADD CONSTRAINT foo EXCLUDE USING spgist (bar WITH =)`, 46657, `exclusion constraint using spgist`, ``},
 		{`ALTER TABLE a INHERITS b`, 22456, `alter table inherits`, ``},
 		{`ALTER TABLE a NO INHERITS b`, 22456, `alter table no inherits`, ``},
 
 		{`CREATE ACCESS METHOD a`, 0, `create access method`, ``},
 
-		{`COPY x FROM STDIN WHERE a = b`, 54580, ``, ``},
-
 		{`CREATE AGGREGATE a`, 0, `create aggregate`, ``},
 		{`CREATE CAST a`, 0, `create cast`, ``},
 		{`CREATE CONSTRAINT TRIGGER a`, 28296, `create constraint`, ``},
@@ -3188,8 +3357,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE DEFAULT CONVERSION a`, 0, `create def conv`, ``},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`, ``},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`, ``},
-		{`CREATE FUNCTION a`, 17511, `create`, ``},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`, ``},
 		{`CREATE LANGUAGE a`, 17511, `create language a`, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`, ``},
 		{`CREATE PUBLICATION a`, 0, `create publication`, ``},
@@ -3198,7 +3365,10 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`, ``},
 		{`CREATE TABLESPACE a`, 54113, `create tablespace`, ``},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`, ``},
-		{`CREATE TRIGGER a`, 28296, `create`, ``},
+		{`CREATE TRIGGER a BEFORE INSERT ON b FOR EACH STATEMENT AS 'SELECT 1'`, 28296, `for each statement`, ``},
+		{`CREATE TRIGGER a BEFORE TRUNCATE ON b FOR EACH ROW AS 'SELECT 1'`, 28296, `truncate`, ``},
+		{`CREATE TRIGGER a BEFORE UPDATE OF c ON b FOR EACH ROW AS 'SELECT 1'`, 28296, `update of`, ``},
+		{`CREATE TRIGGER a AFTER INSERT ON b FOR EACH ROW EXECUTE FUNCTION f()`, 28296, `execute function`, ``},
 
 		{`DROP ACCESS METHOD a`, 0, `drop access method`, ``},
 		{`DROP AGGREGATE a`, 0, `drop aggregate`, ``},
@@ -3209,7 +3379,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`, ``},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`, ``},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`, ``},
-		{`DROP FUNCTION a`, 17511, `drop `, ``},
 		{`DROP LANGUAGE a`, 17511, `drop language a`, ``},
 		{`DROP OPERATOR a`, 0, `drop operator`, ``},
 		{`DROP PUBLICATION a`, 0, `drop publication`, ``},
@@ -3217,15 +3386,13 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP SERVER a`, 0, `drop server`, ``},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`, ``},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`, ``},
-		{`DROP TRIGGER a`, 28296, `drop`, ``},
 
 		{`DISCARD PLANS`, 0, `discard plans`, ``},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`, ``},
 		{`DISCARD TEMP`, 0, `discard temp`, ``},
 		{`DISCARD TEMPORARY`, 0, `discard temp`, ``},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`, ``},
-		{`SET LOCAL foo = bar`, 32562, ``, ``},
+		{`SET LOCAL SESSION AUTHORIZATION DEFAULT`, 32562, ``, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`, ``},
 
 		{`CREATE TABLE a(x INT[][])`, 32552, ``, ``},
@@ -3239,14 +3406,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`, ``},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`, ``},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`, ``},
-
 		{`CREATE TABLE a (LIKE b INCLUDING COMMENTS)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING IDENTITY)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING STATISTICS)`, 47071, `like table`, ``},
@@ -3267,59 +3426,36 @@ func TestUnimplementedSyntax(t *testing.T) {
 
 		{`CREATE RECURSIVE VIEW a AS SELECT b`, 0, `create recursive view`, ``},
 
-		{`CREATE TYPE a AS (b)`, 27792, ``, ``},
 		{`CREATE TYPE a AS RANGE b`, 27791, ``, ``},
 		{`CREATE TYPE a (b)`, 27793, `base`, ``},
 		{`CREATE TYPE a`, 27793, `shell`, ``},
 		{`CREATE DOMAIN a`, 27796, `create`, ``},
 
 		{`ALTER TYPE db.t RENAME ATTRIBUTE foo TO bar`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar COLLATE hello`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar RESTRICT`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar CASCADE`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t DROP ATTRIBUTE foo`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t DROP ATTRIBUTE foo RESTRICT`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t DROP ATTRIBUTE foo CASCADE`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo TYPE typ`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo TYPE typ COLLATE en`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo TYPE typ COLLATE en CASCADE`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo SET DATA TYPE typ COLLATE en RESTRICT`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
-		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar RESTRICT, DROP ATTRIBUTE foo`, 48701, `ALTER TYPE ATTRIBUTE`, ``},
+		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar COLLATE hello`, 48701, `ALTER TYPE ATTRIBUTE COLLATE`, ``},
+		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo TYPE typ`, 48701, `ALTER TYPE ALTER ATTRIBUTE`, ``},
+		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo TYPE typ COLLATE en`, 48701, `ALTER TYPE ALTER ATTRIBUTE`, ``},
+		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo TYPE typ COLLATE en CASCADE`, 48701, `ALTER TYPE ALTER ATTRIBUTE`, ``},
+		{`ALTER TYPE db.s.t ALTER ATTRIBUTE foo SET DATA TYPE typ COLLATE en RESTRICT`, 48701, `ALTER TYPE ALTER ATTRIBUTE`, ``},
+		{`ALTER TYPE db.s.t ADD ATTRIBUTE foo bar RESTRICT, DROP ATTRIBUTE foo`, 48701, `ALTER TYPE ATTRIBUTE list`, ``},
 
 		{`CREATE INDEX a ON b USING HASH (c)`, 0, `index using hash`, ``},
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`, ``},
 		{`CREATE INDEX a ON b USING BRIN (c)`, 0, `index using brin`, ``},
 
-		{`CREATE INDEX a ON b(c gin_trgm_ops)`, 41285, `index using gin_trgm_ops`, ``},
-		{`CREATE INDEX a ON b(c gist_trgm_ops)`, 41285, `index using gist_trgm_ops`, ``},
 		{`CREATE INDEX a ON b(c bobby)`, 47420, ``, ``},
-		{`CREATE INDEX a ON b(a NULLS LAST)`, 6224, ``, ``},
-		{`CREATE INDEX a ON b(a ASC NULLS LAST)`, 6224, ``, ``},
-		{`CREATE INDEX a ON b(a DESC NULLS FIRST)`, 6224, ``, ``},
 
 		{`INSERT INTO foo(a, a.b) VALUES (1,2)`, 27792, ``, ``},
-		{`INSERT INTO foo VALUES (1,2) ON CONFLICT ON CONSTRAINT a DO NOTHING`, 28161, ``, ``},
 
 		{`SELECT * FROM ROWS FROM (a(b) AS (d))`, 0, `ROWS FROM with col_def_list`, ``},
 
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`, ``},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`, ``},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`, ``},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`, ``},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`, ``},
 
-		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY CUBE (b)`, 46280, `cube`, ``},
-		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`, 46280, `grouping sets`, ``},
-
-		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``, ``},
-		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``, ``},
-		{`SELECT a FROM t ORDER BY a DESC NULLS FIRST`, 6224, ``, ``},
-
 		{`CREATE TABLE a(b BOX)`, 21286, `box`, ``},
 		{`CREATE TABLE a(b CIDR)`, 18846, `cidr`, ``},
 		{`CREATE TABLE a(b CIRCLE)`, 21286, `circle`, ``},
@@ -348,6 +348,14 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = AND_AND
 			return
+		case '<': // &<
+			s.pos++
+			lval.id = RANGE_OVERLEFT
+			return
+		case '>': // &>
+			s.pos++
+			lval.id = RANGE_OVERRIGHT
+			return
 		}
 		return
 
@@ -363,6 +371,13 @@ func (s *scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = FETCHVAL
 			return
+		case '|': // -|
+			if s.peekN(1) == '-' {
+				// -|-
+				s.pos += 2
+				lval.id = RANGE_ADJACENT
+				return
+			}
 		}
 		return
 
@@ -63,6 +63,10 @@ func TestScanner(t *testing.T) {
 		{`$`, []int{'$'}},
 		{`&`, []int{'&'}},
 		{`&&`, []int{AND_AND}},
+		{`&<`, []int{RANGE_OVERLEFT}},
+		{`&>`, []int{RANGE_OVERRIGHT}},
+		{`-|-`, []int{RANGE_ADJACENT}},
+		{`-|`, []int{'-', '|'}},
 		{`|`, []int{'|'}},
 		{`||`, []int{CONCAT}},
 		{`|/`, []int{SQRT}},
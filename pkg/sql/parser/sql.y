@@ -33,6 +33,8 @@ import (
     "github.com/cockroachdb/cockroach/pkg/roachpb"
     "github.com/cockroachdb/cockroach/pkg/security"
     "github.com/cockroachdb/cockroach/pkg/sql/lex"
+    "github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+    "github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
     "github.com/cockroachdb/cockroach/pkg/sql/privilege"
     "github.com/cockroachdb/cockroach/pkg/sql/roleoption"
     "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -176,6 +178,33 @@ func (u *sqlSymUnion) unresolvedObjectName() *tree.UnresolvedObjectName {
 func (u *sqlSymUnion) unresolvedObjectNames() []*tree.UnresolvedObjectName {
     return u.val.([]*tree.UnresolvedObjectName)
 }
+func (u *sqlSymUnion) funcParam() tree.FuncParam {
+    return u.val.(tree.FuncParam)
+}
+func (u *sqlSymUnion) funcParams() tree.FuncParams {
+    return u.val.(tree.FuncParams)
+}
+func (u *sqlSymUnion) functionOption() tree.FunctionOption {
+    return u.val.(tree.FunctionOption)
+}
+func (u *sqlSymUnion) functionOptions() tree.FunctionOptions {
+    return u.val.(tree.FunctionOptions)
+}
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() tree.TriggerEvent {
+    return u.val.(tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() tree.TriggerEvents {
+    return u.val.(tree.TriggerEvents)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
 func (u *sqlSymUnion) functionReference() tree.FunctionReference {
     return u.val.(tree.FunctionReference)
 }
@@ -434,6 +463,12 @@ func (u *sqlSymUnion) idxElem() tree.IndexElem {
 func (u *sqlSymUnion) idxElems() tree.IndexElemList {
     return u.val.(tree.IndexElemList)
 }
+func (u *sqlSymUnion) exclusionElem() tree.ExclusionElem {
+    return u.val.(tree.ExclusionElem)
+}
+func (u *sqlSymUnion) exclusionElems() tree.ExclusionElemList {
+    return u.val.(tree.ExclusionElemList)
+}
 func (u *sqlSymUnion) dropBehavior() tree.DropBehavior {
     return u.val.(tree.DropBehavior)
 }
@@ -506,12 +541,24 @@ func (u *sqlSymUnion) backupOptions() *tree.BackupOptions {
 func (u *sqlSymUnion) copyOptions() *tree.CopyOptions {
   return u.val.(*tree.CopyOptions)
 }
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+  return u.val.(tree.CursorStmt)
+}
+func (u *sqlSymUnion) cursorSensitivity() tree.CursorSensitivity {
+  return u.val.(tree.CursorSensitivity)
+}
+func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
+  return u.val.(tree.CursorScrollOption)
+}
 func (u *sqlSymUnion) restoreOptions() *tree.RestoreOptions {
   return u.val.(*tree.RestoreOptions)
 }
 func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
     return u.val.(tree.TransactionModes)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
@@ -560,6 +607,18 @@ func (u *sqlSymUnion) typeReferences() []tree.ResolvableTypeReference {
 func (u *sqlSymUnion) alterTypeAddValuePlacement() *tree.AlterTypeAddValuePlacement {
     return u.val.(*tree.AlterTypeAddValuePlacement)
 }
+func (u *sqlSymUnion) alterTypeCmd() tree.AlterTypeCmd {
+    return u.val.(tree.AlterTypeCmd)
+}
+func (u *sqlSymUnion) alterTypeCmds() []tree.AlterTypeCmd {
+    return u.val.([]tree.AlterTypeCmd)
+}
+func (u *sqlSymUnion) compositeTypeElem() tree.CompositeTypeElem {
+    return u.val.(tree.CompositeTypeElem)
+}
+func (u *sqlSymUnion) compositeTypeElemList() []tree.CompositeTypeElem {
+    return u.val.([]tree.CompositeTypeElem)
+}
 func (u *sqlSymUnion) scheduleState() tree.ScheduleState {
   return u.val.(tree.ScheduleState)
 }
@@ -600,29 +659,29 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACCESS ACTION ADD ADMIN AFFINITY AFTER AGGREGATE
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFFINITY AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
-%token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC
+%token <str> ASENSITIVE ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC
 
-%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
-%token <str> CACHE CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
+%token <str> CACHE CALLED CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CLOSE
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str> CONFLICT CONNECTION CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
 %token <str> CONVERSION CONVERT COPY COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
-%token <str> CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
+%token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
-%token <str> CURRENT_USER CYCLE
+%token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT DEFAULTS
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DESC DESTINATION DETACHED
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
+%token <str> DISABLE DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENABLE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
@@ -630,19 +689,19 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FROM FULL FUNCTION
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FORWARD FROM FULL FUNCTION
 
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INTEGER
+%token <str> INNER INPUT INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -650,11 +709,11 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> KEY KEYS KMS KV
 
 %token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
+%token <str> LEADING LEAKPROOF LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
 %token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH
+%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
@@ -668,24 +727,24 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIORITY PRIVILEGES
+%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
-%token <str> QUERIES QUERY
+%token <str> QUERIES QUERY QUOTE
 
-%token <str> RANGE RANGES READ REAL REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
+%token <str> RANGE RANGE_ADJACENT RANGE_OVERLEFT RANGE_OVERRIGHT RANGES READ REAL REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
+%token <str> RELATIVE RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETURNS RETRY REVISION_HISTORY REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
-%token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
+%token <str> STABLE START STATISTICS STATUS STDIN STDOUT STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENT STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE
@@ -696,7 +755,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIRTUAL
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIRTUAL VOLATILE
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -808,15 +867,20 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt copy_to_query
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_extension_stmt
+%type <tree.Statement> create_func_stmt
+%type <tree.Statement> create_trigger_stmt
 %type <tree.Statement> create_index_stmt
 %type <tree.Statement> create_role_stmt
 %type <tree.Statement> create_schedule_for_backup_stmt
+%type <tree.Statement> create_schedule_for_export_stmt
+%type <tree.Statement> create_schedule_for_changefeed_stmt
 %type <tree.Statement> create_schema_stmt
 %type <tree.Statement> create_table_stmt
 %type <tree.Statement> create_table_as_stmt
@@ -835,6 +899,8 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> drop_stmt
 %type <tree.Statement> drop_ddl_stmt
 %type <tree.Statement> drop_database_stmt
+%type <tree.Statement> drop_func_stmt
+%type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
 %type <tree.Statement> drop_schema_stmt
@@ -876,6 +942,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -932,6 +999,12 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.CursorStmt> cursor_movement_specifier
+%type <bool> opt_hold opt_binary opt_or_replace
+%type <tree.CursorSensitivity> opt_sensitivity
+%type <tree.CursorScrollOption> opt_scroll
 %type <tree.Statement> reindex_stmt
 
 %type <[]string> opt_incremental
@@ -939,7 +1012,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <[]tree.KVOption> kv_option_list opt_with_options var_set_list opt_with_schedule_options
 %type <*tree.BackupOptions> opt_with_backup_options backup_options backup_options_list
 %type <*tree.RestoreOptions> opt_with_restore_options restore_options restore_options_list
-%type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list
+%type <*tree.CopyOptions> opt_with_copy_options copy_options copy_options_list copy_generic_options_list copy_generic_option
 %type <str> import_format
 %type <tree.StorageParam> storage_parameter
 %type <[]tree.StorageParam> storage_parameter_list opt_table_with opt_with_storage_parameter_list
@@ -991,6 +1064,16 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <str> db_object_name_component
 %type <*tree.UnresolvedObjectName> table_name standalone_index_name sequence_name type_name view_name db_object_name simple_db_object_name complex_db_object_name
 %type <[]*tree.UnresolvedObjectName> type_name_list
+%type <tree.FuncParam> func_param
+%type <tree.TriggerActionTime> trigger_action_time
+%type <tree.TriggerEvent> trigger_event
+%type <tree.TriggerEvents> trigger_event_list
+%type <tree.Expr> opt_trigger_when
+%type <tree.FuncParams> func_param_list opt_func_param_list
+%type <tree.FunctionOption> func_option
+%type <tree.FunctionOptions> func_option_list
+%type <tree.FuncObj> func_obj
+%type <tree.FuncObjs> func_obj_list
 %type <str> schema_name
 %type <tree.ObjectNamePrefix>  qualifiable_schema_name opt_schema_name
 %type <tree.ObjectNamePrefixList> schema_name_list
@@ -1001,7 +1084,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.TableIndexName> table_index_name
 %type <tree.TableIndexNames> table_index_name_list
 
-%type <tree.Operator> math_op
+%type <tree.Operator> math_op exclude_op
 
 %type <tree.IsolationLevel> iso_level
 %type <tree.UserPriority> user_priority
@@ -1031,7 +1114,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
 %type <tree.From> from_clause
-%type <tree.TableExprs> from_list rowsfrom_list opt_from_list
+%type <tree.TableExprs> from_list rowsfrom_list opt_from_list opt_using_clause
 %type <tree.TablePatterns> table_pattern_list single_table_pattern_list
 %type <tree.TableNames> table_name_list opt_locked_rels
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -1047,7 +1130,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
-%type <empty> opt_using_clause
+%type <empty> opt_exclude_access_method
 %type <tree.RefreshDataOption> opt_clear_data
 
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
@@ -1112,6 +1195,8 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <bool> opt_ordinality opt_compact
 %type <*tree.Order> sortby
 %type <tree.IndexElem> index_elem index_elem_options create_as_param
+%type <tree.ExclusionElemList> exclude_elem_list
+%type <tree.ExclusionElem> exclude_elem
 %type <tree.TableExpr> table_ref numeric_table_ref func_table
 %type <tree.Exprs> rowsfrom_list
 %type <tree.Expr> rowsfrom_item
@@ -1130,6 +1215,10 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.ResolvableTypeReference> typename simple_typename cast_target
 %type <*types.T> const_typename
 %type <*tree.AlterTypeAddValuePlacement> opt_add_val_placement
+%type <tree.AlterTypeCmd> alter_attribute_action
+%type <[]tree.AlterTypeCmd> alter_attribute_action_list
+%type <tree.CompositeTypeElem> composite_type_elem
+%type <[]tree.CompositeTypeElem> opt_composite_type_list composite_type_list
 %type <bool> opt_timezone
 %type <*types.T> numeric opt_numeric_modifiers
 %type <*types.T> opt_float
@@ -1146,6 +1235,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.NumVal> signed_fconst only_signed_fconst
 %type <int32> iconst32
 %type <int64> signed_iconst64
+%type <int64> next_prior opt_forward_backward forward_backward
 %type <int64> iconst64
 %type <tree.Expr> var_value
 %type <tree.Exprs> var_list
@@ -1159,7 +1249,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <security.SQLUsername> role_spec
 %type <[]security.SQLUsername> role_spec_list
 %type <tree.Expr> zone_value
-%type <tree.Expr> string_or_placeholder
+%type <tree.Expr> string_or_placeholder copy_generic_option_arg
 %type <tree.Expr> string_or_placeholder_list
 %type <str> region_or_regions
 
@@ -1176,6 +1266,9 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.NamedColumnQualification> col_qualification create_as_col_qualification
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
+%type <tree.ConstraintDeferrability> opt_deferrable
+%type <tree.NameList> constraints_set_list
+%type <bool> constraints_set_mode
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -1200,7 +1293,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %type <[]tree.ColumnID> opt_tableref_col_list tableref_col_list
 
-%type <tree.TargetList> targets targets_roles target_types changefeed_targets
+%type <tree.TargetList> targets targets_roles target_types target_functions changefeed_targets
 %type <*tree.TargetList> opt_on_targets_roles opt_backup_targets
 %type <tree.NameList> for_grantee_clause
 %type <privilege.List> privileges
@@ -1267,6 +1360,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %left      '#'
 %left      '&'
 %left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT
+%left      RANGE_ADJACENT RANGE_OVERLEFT RANGE_OVERRIGHT
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
 %left      '^'
@@ -1300,6 +1394,7 @@ stmt:
 | preparable_stmt           // help texts in sub-rule
 | analyze_stmt              // EXTEND WITH HELP: ANALYZE
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt              // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt           // EXTEND WITH HELP: DEALLOCATE
@@ -1314,8 +1409,10 @@ stmt:
 | refresh_stmt              // EXTEND WITH HELP: REFRESH
 | nonpreparable_set_stmt    // help texts in sub-rule
 | transaction_stmt          // help texts in sub-rule
-| close_cursor_stmt
-| declare_cursor_stmt
+| close_cursor_stmt         // EXTEND WITH HELP: CLOSE
+| declare_cursor_stmt       // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt         // EXTEND WITH HELP: FETCH
+| move_cursor_stmt          // EXTEND WITH HELP: MOVE
 | reindex_stmt
 | /* EMPTY */
   {
@@ -1370,6 +1467,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... {ENABLE | DISABLE} TRIGGER {<triggername> | ALL}
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE [WITHOUT INDEX] | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -1448,6 +1546,7 @@ alter_view_stmt:
 //   [MINVALUE <minvalue> | NO MINVALUE]
 //   [MAXVALUE <maxvalue> | NO MAXVALUE]
 //   [START <start>]
+//   [CACHE <cache>]
 //   [[NO] CYCLE]
 // ALTER SEQUENCE [IF EXISTS] <name> RENAME TO <newname>
 // ALTER SEQUENCE [IF EXISTS] <name> SET SCHEMA <newschemaname>
@@ -1997,6 +2096,24 @@ alter_table_cmd:
       DropBehavior: $4.dropBehavior(),
     }
   }
+  // ALTER TABLE <name> ENABLE TRIGGER {<name> | ALL}
+| ENABLE TRIGGER name
+  {
+    $$.val = &tree.AlterTableEnableTrigger{Trigger: tree.Name($3), Enable: true}
+  }
+| ENABLE TRIGGER ALL
+  {
+    $$.val = &tree.AlterTableEnableTrigger{All: true, Enable: true}
+  }
+  // ALTER TABLE <name> DISABLE TRIGGER {<name> | ALL}
+| DISABLE TRIGGER name
+  {
+    $$.val = &tree.AlterTableEnableTrigger{Trigger: tree.Name($3), Enable: false}
+  }
+| DISABLE TRIGGER ALL
+  {
+    $$.val = &tree.AlterTableEnableTrigger{All: true, Enable: false}
+  }
   // ALTER TABLE <name> EXPERIMENTAL_AUDIT SET <mode>
 | EXPERIMENTAL_AUDIT SET audit_mode
   {
@@ -2170,7 +2287,14 @@ alter_type_stmt:
   }
 | ALTER TYPE type_name alter_attribute_action_list
   {
-    return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE")
+    cmds := $4.alterTypeCmds()
+    if len(cmds) > 1 {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE list")
+    }
+    $$.val = &tree.AlterType{
+      Type: $3.unresolvedObjectName(),
+      Cmd: cmds[0],
+    }
   }
 | ALTER TYPE error // SHOW HELP: ALTER TYPE
 
@@ -2236,19 +2360,54 @@ username_or_sconst:
 
 alter_attribute_action_list:
   alter_attribute_action
+  {
+    $$.val = []tree.AlterTypeCmd{$1.alterTypeCmd()}
+  }
 | alter_attribute_action_list ',' alter_attribute_action
+  {
+    $$.val = append($1.alterTypeCmds(), $3.alterTypeCmd())
+  }
 
 alter_attribute_action:
-  ADD ATTRIBUTE column_name type_name opt_collate opt_drop_behavior
+  ADD ATTRIBUTE column_name typename opt_collate opt_drop_behavior
+  {
+    if $5 != "" {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE COLLATE")
+    }
+    $$.val = &tree.AlterTypeAddAttribute{
+      Name: tree.Name($3),
+      Type: $4.typeReference(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
 | DROP ATTRIBUTE column_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterTypeDropAttribute{
+      Name: tree.Name($3),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
 | DROP ATTRIBUTE IF EXISTS column_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterTypeDropAttribute{
+      Name: tree.Name($5),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
 | ALTER ATTRIBUTE column_name TYPE type_name opt_collate opt_drop_behavior
+  {
+    return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ALTER ATTRIBUTE")
+  }
 | ALTER ATTRIBUTE column_name SET DATA TYPE type_name opt_collate opt_drop_behavior
+  {
+    return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ALTER ATTRIBUTE")
+  }
 
 // %Help: REFRESH - recalculate a materialized view
 // %Category: Misc
 // %Text:
-// REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name [WITH [NO] DATA]
+// REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name [WITH [NO] DATA | INCREMENTAL]
 refresh_stmt:
   REFRESH MATERIALIZED VIEW opt_concurrently view_name opt_clear_data
   {
@@ -2269,6 +2428,10 @@ opt_clear_data:
   {
     $$.val = tree.RefreshDataClear
   }
+| INCREMENTAL
+  {
+    $$.val = tree.RefreshDataIncremental
+  }
 | /* EMPTY */
   {
     $$.val = tree.RefreshDataDefault
@@ -2489,6 +2652,88 @@ create_schedule_for_backup_stmt:
   }
 | CREATE SCHEDULE error  // SHOW HELP: CREATE SCHEDULE FOR BACKUP
 
+// %Help: CREATE SCHEDULE FOR EXPORT - export data periodically
+// %Category: CCL
+// %Text:
+// CREATE SCHEDULE [<description>]
+// FOR EXPORT INTO <format> <location> [WITH <option>[=<value>] [, ...]]
+// FROM (<query>)
+// RECURRING <crontab>
+// [WITH SCHEDULE OPTIONS <schedule_option>[= <value>] [, ...] ]
+//
+// All EXPORT formats and options are supported.
+//
+// Each execution of the schedule runs the EXPORT statement; a new file
+// is written into <location> on every run.
+//
+// RECURRING <crontab>:
+//   The RECURRING expression specifies when the export should run.
+//   It is specified as a crontab expression (or @daily, @hourly, etc).
+//
+// SCHEDULE OPTIONS:
+//   The same options as CREATE SCHEDULE FOR BACKUP are supported:
+//   first_run, on_execution_failure and on_previous_running.
+//
+// %SeeAlso: EXPORT, SHOW SCHEDULES
+create_schedule_for_export_stmt:
+  CREATE SCHEDULE /*$3=*/opt_description FOR EXPORT INTO /*$7=*/import_format
+  /*$8=*/string_or_placeholder /*$9=*/opt_with_options FROM /*$11=*/select_with_parens
+  /*$12=*/cron_expr /*$13=*/opt_with_schedule_options
+  {
+    $$.val = &tree.ScheduledExport{
+      ScheduleLabel: $3.expr(),
+      Recurrence:    $12.expr(),
+      Export:        &tree.Export{
+        Query:      &tree.Select{Select: $11.selectStmt()},
+        FileFormat: $7,
+        File:       $8.expr(),
+        Options:    $9.kvOptions(),
+      },
+      ScheduleOptions: $13.kvOptions(),
+    }
+  }
+| CREATE SCHEDULE opt_description FOR EXPORT error  // SHOW HELP: CREATE SCHEDULE FOR EXPORT
+
+// %Help: CREATE SCHEDULE FOR CHANGEFEED - run an initial scan changefeed periodically
+// %Category: CCL
+// %Text:
+// CREATE SCHEDULE [<description>]
+// FOR CHANGEFEED <targets> INTO <sink> [WITH <option>[=<value>] [, ...]]
+// RECURRING <crontab>
+// [WITH SCHEDULE OPTIONS <schedule_option>[= <value>] [, ...] ]
+//
+// Each execution of the schedule starts a changefeed which performs an
+// initial scan of the targets, emits the rows to the sink and then
+// completes; the initial_scan option defaults to 'only' and may not be
+// set to anything else.
+//
+// RECURRING <crontab>:
+//   The RECURRING expression specifies when the changefeed should run.
+//   It is specified as a crontab expression (or @daily, @hourly, etc).
+//
+// SCHEDULE OPTIONS:
+//   The same options as CREATE SCHEDULE FOR BACKUP are supported:
+//   first_run, on_execution_failure and on_previous_running.
+//
+// %SeeAlso: CREATE CHANGEFEED, SHOW SCHEDULES
+create_schedule_for_changefeed_stmt:
+  CREATE SCHEDULE /*$3=*/opt_description FOR CHANGEFEED /*$6=*/changefeed_targets
+  INTO /*$8=*/string_or_placeholder /*$9=*/opt_with_options
+  /*$10=*/cron_expr /*$11=*/opt_with_schedule_options
+  {
+    $$.val = &tree.ScheduledChangefeed{
+      ScheduleLabel:    $3.expr(),
+      Recurrence:       $10.expr(),
+      CreateChangefeed: &tree.CreateChangefeed{
+        Targets: $6.targetList(),
+        SinkURI: $8.expr(),
+        Options: $9.kvOptions(),
+      },
+      ScheduleOptions: $11.kvOptions(),
+    }
+  }
+| CREATE SCHEDULE opt_description FOR CHANGEFEED error  // SHOW HELP: CREATE SCHEDULE FOR CHANGEFEED
+
 opt_description:
   string_or_placeholder
 | /* EMPTY */
@@ -2722,6 +2967,8 @@ import_format:
 //    CSV
 //    DELIMITED
 //    MYSQLDUMP
+//    NDJSON
+//    PARQUET
 //    PGCOPY
 //    PGDUMP
 //
@@ -2732,6 +2979,7 @@ import_format:
 //    delimiter = '...'      [CSV, PGCOPY-specific]
 //    nullif = '...'         [CSV, PGCOPY-specific]
 //    comment = '...'        [CSV-specific]
+//    strict_validation      [AVRO, NDJSON, PARQUET-specific]
 //
 // %SeeAlso: CREATE TABLE
 import_stmt:
@@ -2784,9 +3032,14 @@ import_stmt:
 //
 // Formats:
 //    CSV
+//    PARQUET
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
+//    nullas = '...'      [CSV-specific]
+//    chunk_rows = '...'
+//    filename = '...'
+//    compression = 'gzip'
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -2874,29 +3127,61 @@ opt_with_options:
 // 1) The "really old" syntax from v7.2 and prior
 // 2) Pre 9.0 using hard-wired, space-separated options
 // 3) The current and preferred options using comma-separated generic identifiers instead of keywords.
-// We currently support only the #2 format.
+// We currently support the #2 and #3 formats.
 // See the comment for CopyStmt in https://github.com/postgres/postgres/blob/master/src/backend/parser/gram.y.
 copy_from_stmt:
   COPY table_name opt_column_list FROM STDIN opt_with_copy_options opt_where_clause
   {
     /* FORCE DOC */
     name := $2.unresolvedObjectName().ToTableName()
+    var where *tree.Where
     if $7.expr() != nil {
-      return unimplementedWithIssue(sqllex, 54580)
+      where = tree.NewWhere(tree.AstWhere, $7.expr())
     }
     $$.val = &tree.CopyFrom{
        Table: name,
        Columns: $3.nameList(),
        Stdin: true,
        Options: *$6.copyOptions(),
+       Where: where,
     }
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Table: $2.unresolvedObjectName().ToTableName(),
+       Columns: $3.nameList(),
+       Options: *$6.copyOptions(),
+    }
+  }
+| COPY '(' copy_to_query ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Statement: $3.stmt(),
+       Options: *$7.copyOptions(),
+    }
+  }
+
+copy_to_query:
+  select_stmt
+| insert_stmt
+| upsert_stmt
+| update_stmt
+| delete_stmt
+
 opt_with_copy_options:
   opt_with copy_options_list
   {
     $$.val = $2.copyOptions()
   }
+| opt_with '(' copy_generic_options_list ')'
+  {
+    $$.val = $3.copyOptions()
+  }
 | /* EMPTY */
   {
     $$.val = &tree.CopyOptions{}
@@ -2923,6 +3208,71 @@ copy_options:
   {
     $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatBinary}
   }
+| CSV
+  {
+    $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatCSV}
+  }
+| HEADER
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
+| DELIMITER string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Delimiter: $2.expr()}
+  }
+| NULL string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Null: $2.expr()}
+  }
+| QUOTE string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Quote: $2.expr()}
+  }
+| ESCAPE string_or_placeholder
+  {
+    $$.val = &tree.CopyOptions{Escape: $2.expr()}
+  }
+
+copy_generic_options_list:
+  copy_generic_option
+  {
+    $$.val = $1.copyOptions()
+  }
+| copy_generic_options_list ',' copy_generic_option
+  {
+    if err := $1.copyOptions().CombineWith($3.copyOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+  }
+
+copy_generic_option:
+  unrestricted_name copy_generic_option_arg
+  {
+    opts, err := tree.MakeCopyOption($1, $2.expr())
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = opts
+  }
+
+copy_generic_option_arg:
+  string_or_placeholder
+| TRUE
+  {
+    $$.val = tree.DBoolTrue
+  }
+| FALSE
+  {
+    $$.val = tree.DBoolFalse
+  }
+| ON
+  {
+    $$.val = tree.DBoolTrue
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
 
 // %Help: CANCEL
 // %Category: Group
@@ -3076,13 +3426,18 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE, CREATE TYPE, CREATE EXTENSION
+// CREATE ROLE, CREATE TYPE, CREATE EXTENSION, CREATE FUNCTION,
+// CREATE TRIGGER
 create_stmt:
   create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
 | create_stats_stmt    // EXTEND WITH HELP: CREATE STATISTICS
 | create_schedule_for_backup_stmt   // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
+| create_schedule_for_export_stmt   // EXTEND WITH HELP: CREATE SCHEDULE FOR EXPORT
+| create_schedule_for_changefeed_stmt   // EXTEND WITH HELP: CREATE SCHEDULE FOR CHANGEFEED
 | create_extension_stmt // EXTEND WITH HELP: CREATE EXTENSION
+| create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
 | create_unsupported   {}
 | CREATE error         // SHOW HELP: CREATE
 
@@ -3099,6 +3454,193 @@ create_extension_stmt:
   }
 | CREATE EXTENSION error // SHOW HELP: CREATE EXTENSION
 
+// %Help: CREATE FUNCTION - create a user-defined function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [ [<argname>] <argtype> [, ...] ] )
+//   RETURNS <rettype>
+//   [ LANGUAGE SQL ]
+//   [ IMMUTABLE | STABLE | VOLATILE ]
+//   [ [NOT] LEAKPROOF ]
+//   [ CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT ]
+//   AS '<body>'
+// %SeeAlso: DROP FUNCTION, SHOW CREATE FUNCTION
+create_func_stmt:
+  CREATE opt_or_replace FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS typename func_option_list
+  {
+    $$.val = &tree.CreateFunction{
+      FuncName: $4.unresolvedObjectName(),
+      Replace: $2.bool(),
+      Params: $6.funcParams(),
+      ReturnType: $9.typeReference(),
+      Options: $10.functionOptions(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_param_list:
+  func_param_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncParams{}
+  }
+
+func_param_list:
+  func_param
+  {
+    $$.val = tree.FuncParams{$1.funcParam()}
+  }
+| func_param_list ',' func_param
+  {
+    $$.val = append($1.funcParams(), $3.funcParam())
+  }
+
+func_param:
+  type_function_name typename
+  {
+    $$.val = tree.FuncParam{Name: tree.Name($1), Type: $2.typeReference()}
+  }
+| typename
+  {
+    $$.val = tree.FuncParam{Type: $1.typeReference()}
+  }
+
+func_option_list:
+  func_option
+  {
+    $$.val = tree.FunctionOptions{$1.functionOption()}
+  }
+| func_option_list func_option
+  {
+    $$.val = append($1.functionOptions(), $2.functionOption())
+  }
+
+func_option:
+  LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = tree.FunctionLanguage($2)
+  }
+| IMMUTABLE
+  {
+    $$.val = tree.FunctionImmutable
+  }
+| STABLE
+  {
+    $$.val = tree.FunctionStable
+  }
+| VOLATILE
+  {
+    $$.val = tree.FunctionVolatile
+  }
+| LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakProof(true)
+  }
+| NOT LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakProof(false)
+  }
+| CALLED ON NULL INPUT
+  {
+    $$.val = tree.FunctionCalledOnNullInput
+  }
+| RETURNS NULL ON NULL INPUT
+  {
+    $$.val = tree.FunctionReturnsNullOnNullInput
+  }
+| STRICT
+  {
+    $$.val = tree.FunctionStrict
+  }
+| AS SCONST
+  {
+    $$.val = tree.FunctionBody($2)
+  }
+
+// %Help: CREATE TRIGGER - create a row-level trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER <name> { BEFORE | AFTER } <event> [ OR ... ]
+//   ON <tablename> FOR EACH ROW
+//   [ WHEN ( <condition> ) ]
+//   AS '<statement>'
+//
+// <event> is one of INSERT, UPDATE or DELETE. The statement is an INSERT,
+// UPSERT, UPDATE or DELETE statement which refers to the values of the
+// modified row using NEW.<column> and OLD.<column>.
+// %SeeAlso: DROP TRIGGER, ALTER TABLE
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name trigger_for_each_row opt_trigger_when AS SCONST
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName(),
+      When: $9.expr(),
+      Body: $11,
+    }
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name trigger_for_each_row opt_trigger_when EXECUTE error
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "execute function")
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerAfter
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = tree.TriggerEvents{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = tree.TriggerInsert
+  }
+| UPDATE
+  {
+    $$.val = tree.TriggerUpdate
+  }
+| DELETE
+  {
+    $$.val = tree.TriggerDelete
+  }
+| UPDATE OF name_list { return unimplementedWithIssueDetail(sqllex, 28296, "update of") }
+| TRUNCATE { return unimplementedWithIssueDetail(sqllex, 28296, "truncate") }
+
+trigger_for_each_row:
+  FOR opt_each ROW {}
+| FOR opt_each STATEMENT { return unimplementedWithIssueDetail(sqllex, 28296, "for each statement") }
+
+opt_each:
+  EACH {}
+| /* EMPTY */ {}
+
+opt_trigger_when:
+  WHEN '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
 create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
 | CREATE AGGREGATE error { return unimplemented(sqllex, "create aggregate") }
@@ -3108,8 +3650,6 @@ create_unsupported:
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
@@ -3118,11 +3658,16 @@ create_unsupported:
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
 
 opt_trusted:
   TRUSTED {}
@@ -3143,7 +3688,6 @@ drop_unsupported:
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
@@ -3151,7 +3695,6 @@ drop_unsupported:
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_changefeed_stmt
@@ -3313,7 +3856,9 @@ opt_changefeed_sink:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [[AS] <name>]
+//               [USING <table_expr> [, ...]]
+//               [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
@@ -3324,6 +3869,7 @@ delete_stmt:
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
+      Using: $5.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $6.expr()),
       OrderBy: $7.orderBy(),
       Limit: $8.limit(),
@@ -3333,8 +3879,14 @@ delete_stmt:
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
 opt_using_clause:
-  USING from_list { return unimplementedWithIssueDetail(sqllex, 40963, "delete using") }
-| /* EMPTY */ { }
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 
 // %Help: DISCARD - reset the session to its initial state
@@ -3355,7 +3907,7 @@ discard_stmt:
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE, DROP TYPE
+// DROP USER, DROP ROLE, DROP TYPE, DROP FUNCTION, DROP TRIGGER
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
@@ -3371,6 +3923,8 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -3495,15 +4049,89 @@ drop_type_stmt:
       DropBehavior: $4.dropBehavior(),
     }
   }
-| DROP TYPE IF EXISTS type_name_list opt_drop_behavior
+| DROP TYPE IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TYPE error // SHOW HELP: DROP TYPE
+
+// %Help: DROP FUNCTION - remove a user-defined function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [ ( [ [<argname>] <argtype> [, ...] ] ) ] [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_func_stmt:
+  DROP FUNCTION func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.funcObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <tablename> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      IfExists: false,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName(),
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+func_obj_list:
+  func_obj
+  {
+    $$.val = tree.FuncObjs{$1.funcObj()}
+  }
+| func_obj_list ',' func_obj
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+func_obj:
+  db_object_name
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName()}
+  }
+| db_object_name '(' opt_func_param_list ')'
   {
-    $$.val = &tree.DropType{
-      Names: $5.unresolvedObjectNames(),
-      IfExists: true,
-      DropBehavior: $6.dropBehavior(),
-    }
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName(), Params: $3.funcParams()}
+  }
+
+target_functions:
+  func_obj_list
+  {
+    $$.val = tree.TargetList{Functions: $1.funcObjs()}
   }
-| DROP TYPE error // SHOW HELP: DROP TYPE
 
 target_types:
   type_name_list
@@ -3846,6 +4474,10 @@ grant_stmt:
   {
     $$.val = &tree.Grant{Privileges: $2.privilegeList(), Targets: $5.targetList(), Grantees: $7.nameList()}
   }
+| GRANT privileges ON FUNCTION target_functions TO name_list
+  {
+    $$.val = &tree.Grant{Privileges: $2.privilegeList(), Targets: $5.targetList(), Grantees: $7.nameList()}
+  }
 | GRANT privileges ON SCHEMA schema_name_list TO name_list
   {
     $$.val = &tree.Grant{
@@ -3893,6 +4525,10 @@ revoke_stmt:
   {
     $$.val = &tree.Revoke{Privileges: $2.privilegeList(), Targets: $5.targetList(), Grantees: $7.nameList()}
   }
+| REVOKE privileges ON FUNCTION target_functions FROM name_list
+  {
+    $$.val = &tree.Revoke{Privileges: $2.privilegeList(), Targets: $5.targetList(), Grantees: $7.nameList()}
+  }
 | REVOKE privileges ON SCHEMA schema_name_list FROM name_list
   {
     $$.val = &tree.Revoke{
@@ -3987,8 +4623,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
-| SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 
 // SET SESSION / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -4125,11 +4760,14 @@ set_exprs_internal:
 // %Help: SET SESSION - change a session variable
 // %Category: Cfg
 // %Text:
-// SET [SESSION] <var> { TO | = } <values...>
-// SET [SESSION] TIME ZONE <tz>
+// SET [SESSION | LOCAL] <var> { TO | = } <values...>
+// SET [SESSION | LOCAL] TIME ZONE <tz>
 // SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
 // SET [SESSION] TRACING { TO | = } { on | off | cluster | kv | results } [,...]
 //
+// SET LOCAL only changes the variable until the end of the current
+// transaction.
+//
 // %SeeAlso: SHOW SESSION, RESET, DISCARD, SHOW, SET CLUSTER SETTING, SET TRANSACTION,
 // WEBDOCS/set-vars.html
 set_session_stmt:
@@ -4141,6 +4779,15 @@ set_session_stmt:
   {
     $$.val = $2.stmt()
   }
+| SET LOCAL set_rest_more
+  {
+    setVar, ok := $3.stmt().(*tree.SetVar)
+    if !ok {
+      return unimplementedWithIssue(sqllex, 32562)
+    }
+    setVar.Local = true
+    $$.val = setVar
+  }
 // Special form for pg compatibility:
 | SET SESSION CHARACTERISTICS AS TRANSACTION transaction_mode_list
   {
@@ -4172,6 +4819,41 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Deferred constraints are checked when the current transaction commits.
+// Immediate constraints are checked at the end of each statement; making
+// constraints immediate checks them for the preceding statements of the
+// transaction.
+//
+// %SeeAlso: SET TRANSACTION, COMMIT, WEBDOCS/set-constraints.html
+set_constraints_stmt:
+  SET CONSTRAINTS constraints_set_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_list:
+  ALL
+  {
+    $$.val = tree.NameList(nil)
+  }
+| name_list
+
+constraints_set_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -4399,14 +5081,220 @@ show_stmt:
 | SHOW error                // SHOW HELP: SHOW
 | show_last_query_stats_stmt
 
-// Cursors are not yet supported by CockroachDB. CLOSE ALL is safe to no-op
-// since there will be no open cursors.
+// %Help: CLOSE - close SQL cursor
+// %Category: Misc
+// %Text: CLOSE [ ALL | <name> ]
+// %SeeAlso: DECLARE, FETCH, MOVE
 close_cursor_stmt:
-	CLOSE ALL { }
-| CLOSE cursor_name { return unimplementedWithIssue(sqllex, 41412) }
+  CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{
+      All: true,
+    }
+  }
+| CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{
+      Name: tree.Name($2),
+    }
+  }
+| CLOSE error // SHOW HELP: CLOSE
 
+// %Help: DECLARE - declare SQL cursor
+// %Category: Misc
+// %Text: DECLARE <name> [ BINARY ] [ INSENSITIVE | ASENSITIVE ] [ [ NO ] SCROLL ]
+//    CURSOR [ { WITH | WITHOUT } HOLD ] FOR <query>
+// %SeeAlso: CLOSE, FETCH, MOVE
 declare_cursor_stmt:
-	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
+  DECLARE cursor_name opt_binary opt_sensitivity opt_scroll CURSOR opt_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{
+      Name: tree.Name($2),
+      Binary: $3.bool(),
+      Sensitivity: $4.cursorSensitivity(),
+      Scroll: $5.cursorScrollOption(),
+      Hold: $7.bool(),
+      Select: $9.slct(),
+    }
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_binary:
+  BINARY
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+opt_sensitivity:
+  INSENSITIVE
+  {
+    $$.val = tree.Insensitive
+  }
+| ASENSITIVE
+  {
+    $$.val = tree.Asensitive
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedSensitivity
+  }
+
+opt_scroll:
+  SCROLL
+  {
+    $$.val = tree.Scroll
+  }
+| NO SCROLL
+  {
+    $$.val = tree.NoScroll
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedScroll
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - fetch rows from a SQL cursor
+// %Category: Misc
+// %Text: FETCH [ <direction> [ FROM | IN ] ] <name>
+// %SeeAlso: CLOSE, DECLARE, MOVE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - move a SQL cursor without fetching rows
+// %Category: Misc
+// %Text: MOVE [ <direction> [ FROM | IN ] ] <name>
+// %SeeAlso: CLOSE, DECLARE, FETCH
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($1),
+      Count: 1,
+    }
+  }
+| from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($2),
+      Count: 1,
+    }
+  }
+| next_prior opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| forward_backward opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| opt_forward_backward signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      Count: $2.int64() * $1.int64(),
+    }
+  }
+| opt_forward_backward ALL opt_from_or_in cursor_name
+  {
+    fetchType := tree.FetchAll
+    count := $1.int64()
+    if count < 0 {
+      fetchType = tree.FetchBackwardAll
+    }
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: fetchType,
+    }
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchAbsolute,
+      Count: $2.int64(),
+    }
+  }
+| RELATIVE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchRelative,
+      Count: $2.int64(),
+    }
+  }
+| FIRST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchFirst,
+    }
+  }
+| LAST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchLast,
+    }
+  }
+
+next_prior:
+  NEXT  { $$.val = int64(1) }
+| PRIOR { $$.val = int64(-1) }
+
+opt_forward_backward:
+  forward_backward { $$.val = $1.int64() }
+| /* EMPTY */ { $$.val = int64(1) }
+
+forward_backward:
+  FORWARD  { $$.val = int64(1) }
+| BACKWARD { $$.val = int64(-1) }
+
+opt_from_or_in:
+  from_or_in { }
+| /* EMPTY */ { }
+
+from_or_in:
+  FROM { }
+| IN { }
 
 reindex_stmt:
   REINDEX TABLE error
@@ -4446,6 +5334,9 @@ show_session_stmt:
 
 session_var:
   IDENT
+// Extension variables such as pg_trgm.similarity_threshold are qualified with
+// the name of their extension.
+| IDENT '.' IDENT { $$ = $1 + "." + $3 }
 // Although ALL, SESSION_USER and DATABASE are identifiers for the
 // purpose of SHOW, they lex as separate token types, so they need
 // separate rules.
@@ -4826,7 +5717,7 @@ show_jobs_stmt:
 // %Help: SHOW SCHEDULES - list periodic schedules
 // %Category: Misc
 // %Text:
-// SHOW [RUNNING | PAUSED] SCHEDULES [FOR BACKUP]
+// SHOW [RUNNING | PAUSED] SCHEDULES [FOR {BACKUP | EXPORT | CHANGEFEED}]
 // SHOW SCHEDULE <schedule_id>
 // %SeeAlso: PAUSE SCHEDULES, RESUME SCHEDULES, DROP SCHEDULES
 show_schedules_stmt:
@@ -4874,6 +5765,14 @@ opt_schedule_executor_type:
   {
     $$.val = tree.ScheduledBackupExecutor
   }
+| FOR EXPORT
+  {
+    $$.val = tree.ScheduledExportExecutor
+  }
+| FOR CHANGEFEED
+  {
+    $$.val = tree.ScheduledChangefeedExecutor
+  }
 
 // %Help: SHOW TRACE - display an execution trace
 // %Category: Misc
@@ -5046,9 +5945,11 @@ show_transaction_stmt:
   }
 | SHOW TRANSACTION error // SHOW HELP: SHOW TRANSACTION
 
-// %Help: SHOW CREATE - display the CREATE statement for a table, sequence or view
+// %Help: SHOW CREATE - display the CREATE statement for a table, sequence, view or function
 // %Category: DDL
-// %Text: SHOW CREATE [ TABLE | SEQUENCE | VIEW ] <tablename>
+// %Text:
+// SHOW CREATE [ TABLE | SEQUENCE | VIEW ] <tablename>
+// SHOW CREATE FUNCTION <funcname>
 // %SeeAlso: WEBDOCS/show-create-table.html
 show_create_stmt:
   SHOW CREATE table_name
@@ -5060,6 +5961,10 @@ show_create_stmt:
     /* SKIP DOC */
     $$.val = &tree.ShowCreate{Name: $4.unresolvedObjectName()}
   }
+| SHOW CREATE FUNCTION db_object_name
+  {
+    $$.val = &tree.ShowCreateFunction{Name: $4.unresolvedObjectName()}
+  }
 | SHOW CREATE error // SHOW HELP: SHOW CREATE
 
 create_kw:
@@ -6185,7 +7090,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
  {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -6193,6 +7098,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrability: $6.constraintDeferrability(),
     }
  }
 | generated_as '(' a_expr ')' STORED
@@ -6291,6 +7197,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability().Deferrable() {
+      return setErr(sqllex, pgerror.New(pgcode.FeatureNotSupported,
+        "CHECK constraints cannot be marked DEFERRABLE"))
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -6307,6 +7217,7 @@ constraint_elem:
         PartitionByIndex: $8.partitionByIndex(),
         Predicate: $10.expr(),
       },
+      Deferrability: $9.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_interleave
@@ -6330,13 +7241,58 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
+    }
+  }
+| EXCLUDE opt_exclude_access_method '(' exclude_elem_list ')' opt_where_clause
+  {
+    if $6.expr() != nil {
+      return unimplementedWithIssueDetail(sqllex, 46657, "exclusion constraint with predicate")
+    }
+    $$.val = &tree.ExclusionConstraintTableDef{
+      Elems: $4.exclusionElems(),
+    }
+  }
+
+opt_exclude_access_method:
+  USING name
+  {
+    switch $2 {
+      case "gist", "btree":
+      case "gin", "hash", "spgist", "brin":
+        return unimplementedWithIssueDetail(sqllex, 46657, "exclusion constraint using " + $2)
+      default:
+        sqllex.Error("unrecognized access method: " + $2)
+        return 1
     }
   }
-| EXCLUDE USING error
+| /* EMPTY */ {}
+
+exclude_elem_list:
+  exclude_elem
+  {
+    $$.val = tree.ExclusionElemList{$1.exclusionElem()}
+  }
+| exclude_elem_list ',' exclude_elem
+  {
+    $$.val = append($1.exclusionElems(), $3.exclusionElem())
+  }
+
+exclude_elem:
+  name WITH exclude_op
   {
-    return unimplementedWithIssueDetail(sqllex, 46657, "add constraint exclude using")
+    op, ok := $3.op().(tree.ComparisonOperator)
+    if !ok {
+      return setErr(sqllex, pgerror.Newf(pgcode.WrongObjectType,
+        "operator %s is not a comparison operator", $3.op()))
+    }
+    $$.val = tree.ExclusionElem{Column: tree.Name($1), Operator: op}
   }
 
+exclude_op:
+  math_op
+| AND_AND { $$.val = tree.Overlaps }
+
 
 create_as_opt_col_list:
   '(' create_as_table_defs ')'
@@ -6439,13 +7395,32 @@ create_as_col_qualification_elem:
     $$.val = tree.PrimaryKeyConstraint{}
   }
 
+// INITIALLY DEFERRED implies DEFERRABLE, like in Postgres.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.NotDeferrableConstraint
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.DeferrableInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.DeferrableInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.NotDeferrableConstraint
+  }
 
 storing:
   COVERING
@@ -6595,7 +7570,7 @@ reference_action:
 //   [MAXVALUE <maxvalue> | NO MAXVALUE]
 //   [START [WITH] <start>]
 //   [CACHE <cache>]
-//   [NO CYCLE]
+//   [[NO] CYCLE]
 //   [VIRTUAL]
 //
 // %SeeAlso: CREATE TABLE
@@ -6630,8 +7605,7 @@ sequence_option_list:
 
 sequence_option_elem:
   AS typename                  { return unimplementedWithIssueDetail(sqllex, 25110, $2.typeReference().SQLString()) }
-| CYCLE                        { /* SKIP DOC */
-                                 $$.val = tree.SequenceOption{Name: tree.SeqOptCycle} }
+| CYCLE                        { $$.val = tree.SequenceOption{Name: tree.SeqOptCycle} }
 | NO CYCLE                     { $$.val = tree.SequenceOption{Name: tree.SeqOptNoCycle} }
 | OWNED BY NONE                { $$.val = tree.SequenceOption{Name: tree.SeqOptOwnedBy, ColumnItemVal: nil} }
 | OWNED BY column_path         { varName, err := $3.unresolvedName().NormalizeVarName()
@@ -6644,8 +7618,7 @@ sequence_option_elem:
                                              return 1
                                      }
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptOwnedBy, ColumnItemVal: columnItem} }
-| CACHE signed_iconst64        { /* SKIP DOC */
-                                 x := $2.int64()
+| CACHE signed_iconst64        { x := $2.int64()
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptCache, IntVal: &x} }
 | INCREMENT signed_iconst64    { x := $2.int64()
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptIncrement, IntVal: &x} }
@@ -6911,7 +7884,9 @@ opt_view_recursive:
 
 // %Help: CREATE TYPE -- create a type
 // %Category: DDL
-// %Text: CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// %Text:
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS (<attribute_name> <type> [, ...])
 create_type_stmt:
   // Enum types.
   CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
@@ -6931,9 +7906,25 @@ create_type_stmt:
       IfNotExists: true,
     }
   }
-| CREATE TYPE error // SHOW HELP: CREATE TYPE
   // Record/Composite types.
-| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
+| CREATE TYPE type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $6.compositeTypeElemList(),
+    }
+  }
+| CREATE TYPE IF NOT EXISTS type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $6.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $9.compositeTypeElemList(),
+      IfNotExists: true,
+    }
+  }
+| CREATE TYPE error // SHOW HELP: CREATE TYPE
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
@@ -6943,6 +7934,35 @@ create_type_stmt:
   // Domain types.
 | CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
 
+opt_composite_type_list:
+  composite_type_list
+  {
+    $$.val = $1.compositeTypeElemList()
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.CompositeTypeElem(nil)
+  }
+
+composite_type_list:
+  composite_type_elem
+  {
+    $$.val = []tree.CompositeTypeElem{$1.compositeTypeElem()}
+  }
+| composite_type_list ',' composite_type_elem
+  {
+    $$.val = append($1.compositeTypeElemList(), $3.compositeTypeElem())
+  }
+
+composite_type_elem:
+  column_name typename
+  {
+    $$.val = tree.CompositeTypeElem{
+      Label: tree.Name($1),
+      Type: $2.typeReference(),
+    }
+  }
+
 opt_enum_val_list:
   enum_val_list
   {
@@ -7133,22 +8153,10 @@ index_elem_options:
     opClass := $1
     dir := $2.dir()
     nullsOrder := $3.nullsOrder()
-    if opClass != "" {
-      if opClass == "gin_trgm_ops" || opClass == "gist_trgm_ops" {
-        return unimplementedWithIssueDetail(sqllex, 41285, "index using " + opClass)
-      }
+    if opClass != "" && opClass != "gin_trgm_ops" && opClass != "gist_trgm_ops" {
       return unimplementedWithIssue(sqllex, 47420)
     }
-    // We currently only support the opposite of Postgres defaults.
-    if nullsOrder != tree.DefaultNullsOrder {
-      if dir == tree.Descending && nullsOrder == tree.NullsFirst {
-        return unimplementedWithIssue(sqllex, 6224)
-      }
-      if dir != tree.Descending && nullsOrder == tree.NullsLast {
-        return unimplementedWithIssue(sqllex, 6224)
-      }
-    }
-    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder}
+    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder, OpClass: tree.Name(opClass)}
   }
 
 opt_class:
@@ -8122,7 +9130,21 @@ on_conflict:
       Where: tree.NewWhere(tree.AstWhere, $11.expr()),
     }
   }
-| ON CONFLICT ON CONSTRAINT constraint_name { return unimplementedWithIssue(sqllex, 28161) }
+| ON CONFLICT ON CONSTRAINT constraint_name DO NOTHING
+  {
+    $$.val = &tree.OnConflict{
+      Constraint: tree.Name($5),
+      DoNothing: true,
+    }
+  }
+| ON CONFLICT ON CONSTRAINT constraint_name DO UPDATE SET set_clause_list opt_where_clause
+  {
+    $$.val = &tree.OnConflict{
+      Constraint: tree.Name($5),
+      Exprs: $9.updateExprs(),
+      Where: tree.NewWhere(tree.AstWhere, $10.expr()),
+    }
+  }
 
 returning_clause:
   RETURNING target_list
@@ -8679,22 +9701,11 @@ sortby:
   a_expr opt_asc_desc opt_nulls_order
   {
     /* FORCE DOC */
-    dir := $2.dir()
-    nullsOrder := $3.nullsOrder()
-    // We currently only support the opposite of Postgres defaults.
-    if nullsOrder != tree.DefaultNullsOrder {
-      if dir == tree.Descending && nullsOrder == tree.NullsFirst {
-        return unimplementedWithIssue(sqllex, 6224)
-      }
-      if dir != tree.Descending && nullsOrder == tree.NullsLast {
-        return unimplementedWithIssue(sqllex, 6224)
-      }
-    }
     $$.val = &tree.Order{
       OrderType:  tree.OrderByColumn,
       Expr:       $1.expr(),
-      Direction:  dir,
-      NullsOrder: nullsOrder,
+      Direction:  $2.dir(),
+      NullsOrder: $3.nullsOrder(),
     }
   }
 | PRIMARY KEY table_name opt_asc_desc
@@ -8859,9 +9870,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.ExplicitGroupingSet, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -10216,6 +11236,18 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.Overlaps, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr RANGE_ADJACENT a_expr
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("range_adjacent"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
+  }
+| a_expr RANGE_OVERLEFT a_expr
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("range_overleft"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
+  }
+| a_expr RANGE_OVERRIGHT a_expr
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("range_overright"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
+  }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_equals"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
@@ -10703,7 +11735,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingExpr{Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -12171,6 +13206,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ACCESS
 | ADD
@@ -12180,11 +13216,13 @@ unreserved_keyword:
 | AGGREGATE
 | ALTER
 | ALWAYS
+| ASENSITIVE
 | AT
 | ATTRIBUTE
 | AUTOMATIC
 | BACKUP
 | BACKUPS
+| BACKWARD
 | BEFORE
 | BEGIN
 | BINARY
@@ -12192,6 +13230,7 @@ unreserved_keyword:
 | BUNDLE
 | BY
 | CACHE
+| CALLED
 | CANCEL
 | CANCELQUERY
 | CASCADE
@@ -12220,8 +13259,10 @@ unreserved_keyword:
 | CREATEDB
 | CREATELOGIN
 | CREATEROLE
+| CSV
 | CUBE
 | CURRENT
+| CURSOR
 | CYCLE
 | DATA
 | DATABASE
@@ -12232,12 +13273,16 @@ unreserved_keyword:
 | DELETE
 | DEFAULTS
 | DEFERRED
+| DELIMITER
 | DESTINATION
 | DETACHED
+| DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
+| ENABLE
 | ENCODING
 | ENCRYPTION_PASSPHRASE
 | ENUM
@@ -12262,6 +13307,7 @@ unreserved_keyword:
 | FIRST
 | FOLLOWING
 | FORCE_INDEX
+| FORWARD
 | FUNCTION
 | GENERATED
 | GEOMETRYM
@@ -12276,11 +13322,14 @@ unreserved_keyword:
 | GRANTS
 | GROUPS
 | HASH
+| HEADER
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDING
@@ -12289,6 +13338,8 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INPUT
+| INSENSITIVE
 | INSERT
 | INTERLEAVE
 | INTO_DB
@@ -12306,6 +13357,7 @@ unreserved_keyword:
 | LATEST
 | LC_COLLATE
 | LC_CTYPE
+| LEAKPROOF
 | LEASE
 | LESS
 | LEVEL
@@ -12338,6 +13390,7 @@ unreserved_keyword:
 | MULTIPOLYGONZ
 | MULTIPOLYGONZM
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NEVER
@@ -12388,12 +13441,14 @@ unreserved_keyword:
 | PRECEDING
 | PREPARE
 | PRESERVE
+| PRIOR
 | PRIORITY
 | PRIVILEGES
 | PUBLIC
 | PUBLICATION
 | QUERIES
 | QUERY
+| QUOTE
 | RANGE
 | RANGES
 | READ
@@ -12406,6 +13461,7 @@ unreserved_keyword:
 | REGIONAL
 | REGIONS
 | REINDEX
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -12415,6 +13471,7 @@ unreserved_keyword:
 | RESTRICT
 | RESUME
 | RETRY
+| RETURNS
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -12433,6 +13490,7 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
@@ -12455,10 +13513,13 @@ unreserved_keyword:
 | SNAPSHOT
 | SPLIT
 | SQL
+| STABLE
 | START
+| STATEMENT
 | STATEMENTS
 | STATISTICS
 | STDIN
+| STDOUT
 | STORAGE
 | STORE
 | STORED
@@ -12503,6 +13564,7 @@ unreserved_keyword:
 | VARYING
 | VIEW
 | VIEWACTIVITY
+| VOLATILE
 | WITHIN
 | WITHOUT
 | WRITE
@@ -217,6 +217,7 @@ var pgCatalog = virtualSchema{
 		catconstants.PgCatalogCollationTableID:           pgCatalogCollationTable,
 		catconstants.PgCatalogConstraintTableID:          pgCatalogConstraintTable,
 		catconstants.PgCatalogConversionTableID:          pgCatalogConversionTable,
+		catconstants.PgCatalogCursorsTableID:             pgCatalogCursorsTable,
 		catconstants.PgCatalogDatabaseTableID:            pgCatalogDatabaseTable,
 		catconstants.PgCatalogDefaultACLTableID:          pgCatalogDefaultACLTable,
 		catconstants.PgCatalogDependTableID:              pgCatalogDependTable,
@@ -708,7 +709,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -764,6 +764,9 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		condeferrable := tree.DBoolFalse
+		condeferred := tree.DBoolFalse
+		conexclop := tree.DNull
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -823,6 +826,8 @@ func populateTableConstraints(
 				return err
 			}
 			condef = tree.NewDString(buf.String())
+			condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+			condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 
 		case descpb.ConstraintTypeUnique:
 			contype = conTypeUnique
@@ -855,6 +860,10 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				uc := con.UniqueWithoutIndexConstraint
+				f.FormatNode(constraintDeferrability(uc.Deferrable, uc.InitiallyDeferred))
+				condeferrable = tree.MakeDBool(tree.DBool(uc.Deferrable))
+				condeferred = tree.MakeDBool(tree.DBool(uc.InitiallyDeferred))
 			} else {
 				return errors.AssertionFailedf(
 					"Index or UniqueWithoutIndexConstraint must be non-nil for a unique constraint",
@@ -862,6 +871,27 @@ func populateTableConstraints(
 			}
 			condef = tree.NewDString(f.CloseAndGetString())
 
+		case descpb.ConstraintTypeExclusion:
+			ec := con.ExclusionConstraint
+			oid = h.ExclusionConstraintOid(db.GetID(), scName, table.GetID(), ec)
+			contype = conTypeExclusion
+			if conkey, err = colIDArrayToDatum(ec.ColumnIDs); err != nil {
+				return err
+			}
+			if conexclop, err = exclusionOperatorsToDatum(h, table, ec); err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			buf.WriteString("EXCLUDE USING gist (")
+			if err := formatExclusionElems(&buf, table, ec); err != nil {
+				return err
+			}
+			buf.WriteByte(')')
+			if ec.Validity != descpb.ConstraintValidity_Validated {
+				buf.WriteString(" NOT VALID")
+			}
+			condef = tree.NewDString(buf.String())
+
 		case descpb.ConstraintTypeCheck:
 			oid = h.CheckConstraintOid(db.GetID(), scName, table.GetID(), con.CheckConstraint)
 			contype = conTypeCheck
@@ -886,8 +916,8 @@ func populateTableConstraints(
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -904,7 +934,7 @@ func populateTableConstraints(
 			tree.DNull,     // conpfeqop
 			tree.DNull,     // conppeqop
 			tree.DNull,     // conffeqop
-			tree.DNull,     // conexclop
+			conexclop,      // conexclop
 			conbin,         // conbin
 			consrc,         // consrc
 			condef,         // condef
@@ -1051,6 +1081,36 @@ func colIDArrayToDatum(arr []descpb.ColumnID) (tree.Datum, error) {
 	return d, nil
 }
 
+// exclusionOperatorsToDatum returns an OID[] containing the pg_operator OIDs
+// of the operators used by the given exclusion constraint.
+func exclusionOperatorsToDatum(
+	h oidHasher, table catalog.TableDescriptor, ec *descpb.ExclusionConstraint,
+) (tree.Datum, error) {
+	d := tree.NewDArray(types.Oid)
+	for i, colID := range ec.ColumnIDs {
+		col, err := table.FindColumnByID(colID)
+		if err != nil {
+			return nil, err
+		}
+		op := ec.Operator(i)
+		// Inverse operators such as <> are listed in pg_operator using the
+		// parameters of the operator they invert.
+		foldedOp, _, _, _, _ := tree.FoldComparisonExpr(op, nil, nil)
+		overload, ok := tree.CmpOps[foldedOp].LookupImpl(col.Type, col.Type)
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"no overload of operator %s for type %s", op, col.Type.SQLString())
+		}
+		leftType := tree.NewDOid(tree.DInt(overload.LeftType.Oid()))
+		rightType := tree.NewDOid(tree.DInt(overload.RightType.Oid()))
+		returnType := tree.NewDOid(tree.DInt(types.Bool.Oid()))
+		if err := d.Append(h.OperatorOid(op.String(), leftType, rightType, returnType)); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
 // colIDArrayToVector returns an INT2VECTOR containing the ColumnIDs, or NULL if
 // there are no ColumnIDs.
 func colIDArrayToVector(arr []descpb.ColumnID) (tree.Datum, error) {
@@ -1472,14 +1532,17 @@ https://www.postgresql.org/docs/9.5/catalog-pg-index.html`,
 						if err := collationOids.Append(typColl(col.Type, h)); err != nil {
 							return err
 						}
-						// Currently, nulls always appear first if the order is ascending,
-						// and always appear last if the order is descending.
+						// By default, nulls appear first if the order is ascending, and
+						// last if the order is descending.
 						var thisIndOption tree.DInt
 						if index.GetColumnDirection(i) == descpb.IndexDescriptor_ASC {
 							thisIndOption = indoptionNullsFirst
 						} else {
 							thisIndOption = indoptionDesc
 						}
+						if index.GetColumnNullsReversed(i) {
+							thisIndOption ^= indoptionNullsFirst
+						}
 						if err := indoption.Append(tree.NewDInt(thisIndOption)); err != nil {
 							return err
 						}
@@ -1578,6 +1641,12 @@ func indexDefFromDescriptor(
 		if index.ColumnDirections[index.ExplicitColumnStartIdx()+i] == descpb.IndexDescriptor_DESC {
 			elem.Direction = tree.Descending
 		}
+		if index.ColumnNullsReversedAt(index.ExplicitColumnStartIdx() + i) {
+			elem.NullsOrder = tree.NullsLast
+			if elem.Direction == tree.Descending {
+				elem.NullsOrder = tree.NullsFirst
+			}
+		}
 		indexDef.Columns[i] = elem
 	}
 	for i, name := range index.StoreColumnNames {
@@ -1896,6 +1965,31 @@ https://www.postgresql.org/docs/9.6/view-pg-prepared-statements.html`,
 	},
 }
 
+var pgCatalogCursorsTable = virtualSchemaTable{
+	comment: `cursors
+https://www.postgresql.org/docs/14/view-pg-cursors.html`,
+	schema: vtable.PGCatalogCursors,
+	populate: func(ctx context.Context, p *planner, dbContext *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		for name, c := range p.sqlCursors.list() {
+			ts, err := tree.MakeDTimestampTZ(c.created, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.NewDString(string(name)), /* name */
+				tree.NewDString(c.statement),  /* statement */
+				tree.DBoolFalse,               /* is_holdable */
+				tree.DBoolFalse,               /* is_binary */
+				tree.DBoolFalse,               /* is_scrollable */
+				ts,                            /* creation_time */
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 var pgCatalogProcTable = virtualSchemaTable{
 	comment: `built-in functions (incomplete)
 https://www.postgresql.org/docs/9.5/catalog-pg-proc.html`,
@@ -2112,14 +2206,14 @@ https://www.postgresql.org/docs/9.5/catalog-pg-sequence.html`,
 				}
 				opts := table.GetSequenceOpts()
 				return addRow(
-					tableOid(table.GetID()),                 // seqrelid
-					tree.NewDOid(tree.DInt(oid.T_int8)),     // seqtypid
-					tree.NewDInt(tree.DInt(opts.Start)),     // seqstart
-					tree.NewDInt(tree.DInt(opts.Increment)), // seqincrement
-					tree.NewDInt(tree.DInt(opts.MaxValue)),  // seqmax
-					tree.NewDInt(tree.DInt(opts.MinValue)),  // seqmin
-					tree.NewDInt(1),                         // seqcache
-					tree.DBoolFalse,                         // seqcycle
+					tableOid(table.GetID()),                            // seqrelid
+					tree.NewDOid(tree.DInt(oid.T_int8)),                // seqtypid
+					tree.NewDInt(tree.DInt(opts.Start)),                // seqstart
+					tree.NewDInt(tree.DInt(opts.Increment)),            // seqincrement
+					tree.NewDInt(tree.DInt(opts.MaxValue)),             // seqmax
+					tree.NewDInt(tree.DInt(opts.MinValue)),             // seqmin
+					tree.NewDInt(tree.DInt(opts.EffectiveCacheSize())), // seqcache
+					tree.MakeDBool(tree.DBool(opts.Cycle)),             // seqcycle
 				)
 			})
 	},
@@ -2255,7 +2349,6 @@ var (
 	typTypeRange     = tree.NewDString("r")
 
 	// Avoid unused warning for constants.
-	_ = typTypeComposite
 	_ = typTypeDomain
 	_ = typTypePseudo
 	_ = typTypeRange
@@ -2278,7 +2371,6 @@ var (
 	typCategoryUnknown     = tree.NewDString("X")
 
 	// Avoid unused warning for constants.
-	_ = typCategoryComposite
 	_ = typCategoryEnum
 	_ = typCategoryGeometric
 	_ = typCategoryRange
@@ -2324,6 +2416,12 @@ func addPGTypeRow(
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	if typ.Family() == types.TupleFamily && typ.UserDefined() {
+		// User defined composite types share the I/O functions of record.
+		builtinPrefix = "record_"
+		typType = typTypeComposite
+		cat = typCategoryComposite
+	}
 	typname := typ.PGName()
 
 	return addRow(
@@ -2586,6 +2684,7 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.ArrayFamily:       typCategoryArray,
 	types.TupleFamily:       typCategoryPseudo,
 	types.OidFamily:         typCategoryNumeric,
+	types.RangeFamily:       typCategoryRange,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
 	types.UnknownFamily:     typCategoryUnknown,
@@ -2783,6 +2882,7 @@ const (
 	collationTypeTag
 	operatorTypeTag
 	enumEntryTypeTag
+	exclusionConstraintTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -2816,6 +2916,11 @@ func (h oidHasher) writeUniqueConstraint(uc *descpb.UniqueWithoutIndexConstraint
 	h.writeStr(uc.Name)
 }
 
+func (h oidHasher) writeExclusionConstraint(ec *descpb.ExclusionConstraint) {
+	h.writeUInt32(uint32(ec.TableID))
+	h.writeStr(ec.Name)
+}
+
 func (h oidHasher) writeCheckConstraint(check *descpb.TableDescriptor_CheckConstraint) {
 	h.writeStr(check.Name)
 	h.writeStr(check.Expr)
@@ -2891,6 +2996,17 @@ func (h oidHasher) UniqueWithoutIndexConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scName string, tableID descpb.ID, ec *descpb.ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scName)
+	h.writeTable(tableID)
+	h.writeExclusionConstraint(ec)
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	dbID descpb.ID, scName string, tableID descpb.ID, indexID descpb.IndexID,
 ) *tree.DOid {
@@ -89,6 +89,10 @@ type commandResult struct {
 	// statements.
 	bufferingDisabled bool
 
+	// copyOut is set for results which stream the rows of a COPY ... TO STDOUT
+	// statement using the CopyOut sub-protocol.
+	copyOut *copyOutState
+
 	// released is set when the command result has been released so that its
 	// memory can be reused. It is also used to assert against use-after-free
 	// errors.
@@ -105,6 +109,7 @@ type paramStatusUpdate struct {
 }
 
 var _ sql.CommandResult = &commandResult{}
+var _ sql.CopyOutResult = &commandResult{}
 
 // Close is part of the CommandResult interface.
 func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndicator) {
@@ -137,6 +142,10 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		}
 	}
 
+	if r.copyOut != nil && r.copyOut.started {
+		r.conn.bufferCopyOutDone(r.copyOut)
+	}
+
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
@@ -203,7 +212,11 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	}
 	r.rowsAffected++
 
-	r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
+	if r.copyOut != nil {
+		r.conn.bufferCopyOutRow(ctx, row, r)
+	} else {
+		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
+	}
 	var err error
 	if r.bufferingDisabled {
 		err = r.conn.Flush(r.pos)
@@ -236,13 +249,23 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	r.assertNotReleased()
 	r.conn.writerState.fi.registerCmd(r.pos)
-	if r.descOpt == sql.NeedRowDesc {
+	if r.copyOut == nil && r.descOpt == sql.NeedRowDesc {
 		_ /* err */ = r.conn.writeRowDescription(ctx, cols, r.formatCodes, &r.conn.writerState.buf)
 	}
 	r.types = make([]*types.T, len(cols))
 	for i, col := range cols {
 		r.types[i] = col.Typ
 	}
+	if r.copyOut != nil {
+		r.conn.bufferCopyOutResponse(cols, r.copyOut)
+		r.copyOut.started = true
+	}
+}
+
+// SetCopyOut is part of the sql.CopyOutResult interface.
+func (r *commandResult) SetCopyOut(opts sql.CopyFormatOptions) {
+	r.assertNotReleased()
+	r.copyOut = &copyOutState{opts: opts, scratch: newWriteBuffer(nil /* bytecount */)}
 }
 
 // SetInferredTypes is part of the DescribeResult interface.
@@ -402,6 +425,10 @@ func (r *limitedCommandResult) AddRow(ctx context.Context, row tree.Datums) erro
 	if err := r.commandResult.AddRow(ctx, row); err != nil {
 		return err
 	}
+	if r.copyOut != nil {
+		// Like in Postgres, the row limit is ignored for COPY.
+		return nil
+	}
 	r.seenTuples++
 
 	if r.seenTuples == r.limit {
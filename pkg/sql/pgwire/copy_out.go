@@ -0,0 +1,198 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// copyBinarySignature is the header of the binary COPY format, followed by
+// the flags field and the length of the header extension area.
+var copyBinarySignature = []byte("PGCOPY\n\377\r\n\000")
+
+// copyOutState holds the state of a result streaming the rows of a COPY ...
+// TO STDOUT statement.
+type copyOutState struct {
+	opts sql.CopyFormatOptions
+	// started is set once the CopyOutResponse message has been sent.
+	started bool
+	// scratch is used to produce the text representation of datums.
+	scratch *writeBuffer
+	// line accumulates the text or CSV representation of a row.
+	line bytes.Buffer
+}
+
+// copyFormatCode returns the format code of the columns of a COPY format.
+func copyFormatCode(format tree.CopyFormat) pgwirebase.FormatCode {
+	if format == tree.CopyFormatBinary {
+		return pgwirebase.FormatBinary
+	}
+	return pgwirebase.FormatText
+}
+
+// bufferCopyOutResponse starts a copy of rows with the given columns to the
+// client. In the binary format, it is followed by the file header, and in the
+// CSV format by the header line if requested.
+func (c *conn) bufferCopyOutResponse(cols colinfo.ResultColumns, s *copyOutState) {
+	format := copyFormatCode(s.opts.Format)
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(format))
+	c.msgBuilder.putInt16(int16(len(cols)))
+	for range cols {
+		c.msgBuilder.putInt16(int16(format))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+
+	switch {
+	case s.opts.Format == tree.CopyFormatBinary:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.write(copyBinarySignature)
+		c.msgBuilder.putInt32(0) // flags
+		c.msgBuilder.putInt32(0) // header extension length
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+
+	case s.opts.Header:
+		s.line.Reset()
+		for i := range cols {
+			if i > 0 {
+				s.line.WriteByte(s.opts.Delimiter)
+			}
+			writeCopyCSVField(&s.line, []byte(cols[i].Name), s.opts)
+		}
+		s.line.WriteByte('\n')
+		c.bufferCopyData(s.line.Bytes())
+	}
+}
+
+// bufferCopyOutRow serializes a row as a CopyData message.
+func (c *conn) bufferCopyOutRow(ctx context.Context, row tree.Datums, r *commandResult) {
+	s := r.copyOut
+	if s.opts.Format == tree.CopyFormatBinary {
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.putInt16(int16(len(row)))
+		for i, col := range row {
+			c.msgBuilder.writeBinaryDatum(ctx, col, r.location, r.types[i])
+		}
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+		return
+	}
+
+	s.line.Reset()
+	for i, col := range row {
+		if i > 0 {
+			s.line.WriteByte(s.opts.Delimiter)
+		}
+		if col == tree.DNull {
+			s.line.WriteString(s.opts.Null)
+			continue
+		}
+		// writeTextDatum prefixes the value with its length, which is skipped.
+		s.scratch.reset()
+		s.scratch.writeTextDatum(ctx, col, r.conv, r.location, r.types[i])
+		val := s.scratch.wrapped.Bytes()[4:]
+		if s.opts.Format == tree.CopyFormatCSV {
+			writeCopyCSVField(&s.line, val, s.opts)
+		} else {
+			writeCopyTextField(&s.line, val, s.opts.Delimiter)
+		}
+	}
+	s.line.WriteByte('\n')
+	c.bufferCopyData(s.line.Bytes())
+}
+
+// bufferCopyOutDone terminates a copy of rows to the client.
+func (c *conn) bufferCopyOutDone(s *copyOutState) {
+	if s.opts.Format == tree.CopyFormatBinary {
+		// The file trailer is a field count of -1.
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.putInt16(-1)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+func (c *conn) bufferCopyData(data []byte) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	c.msgBuilder.write(data)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// writeCopyTextField writes a value in the text COPY format, escaping
+// backslashes, control characters and the delimiter.
+func writeCopyTextField(buf *bytes.Buffer, val []byte, delim byte) {
+	for _, c := range val {
+		switch c {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\v':
+			buf.WriteString(`\v`)
+		default:
+			if c == delim {
+				buf.WriteByte('\\')
+			}
+			buf.WriteByte(c)
+		}
+	}
+}
+
+// writeCopyCSVField writes a value in the CSV COPY format. Values are quoted
+// if they contain the delimiter, a quote or a line break, or if they could
+// otherwise be mistaken for a NULL or the end-of-data marker. Inside quotes,
+// quote and escape characters are preceded by the escape character.
+func writeCopyCSVField(buf *bytes.Buffer, val []byte, opts sql.CopyFormatOptions) {
+	needsQuotes := bytes.IndexByte(val, opts.Delimiter) != -1 ||
+		bytes.IndexByte(val, opts.Quote) != -1 ||
+		bytes.ContainsAny(val, "\r\n") ||
+		string(val) == opts.Null ||
+		string(val) == `\.`
+	if !needsQuotes {
+		buf.Write(val)
+		return
+	}
+	buf.WriteByte(opts.Quote)
+	for _, c := range val {
+		if c == opts.Quote || c == opts.Escape {
+			buf.WriteByte(opts.Escape)
+		}
+		buf.WriteByte(c)
+	}
+	buf.WriteByte(opts.Quote)
+}
@@ -491,6 +491,20 @@ func DecodeDatum(
 			}
 			return tree.NewDString(string(b)), nil
 		}
+		if t.Family() == types.TupleFamily {
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDTupleFromString(evalCtx, string(b), t)
+			return d, err
+		}
+		if t.Family() == types.RangeFamily {
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDRangeFromString(evalCtx, string(b), t)
+			return d, err
+		}
 	case FormatBinary:
 		switch id {
 		case oid.T_bool:
@@ -759,6 +773,12 @@ func DecodeDatum(
 			if _, ok := types.ArrayOids[id]; ok {
 				return decodeBinaryArray(evalCtx, types.OidToType[id].ArrayContents(), b, code)
 			}
+			if t.Family() == types.TupleFamily {
+				return decodeBinaryTuple(evalCtx, t, b)
+			}
+			if t.Family() == types.RangeFamily {
+				return decodeBinaryRange(evalCtx, t, b)
+			}
 		}
 	default:
 		return nil, errors.AssertionFailedf(
@@ -798,6 +818,96 @@ func DecodeDatum(
 		"unsupported OID %v with format code %s", errors.Safe(id), errors.Safe(code))
 }
 
+// decodeBinaryTuple decodes the binary record format, which consists of the
+// number of fields followed by the OID, length and value of each field. A
+// length of -1 denotes NULL.
+func decodeBinaryTuple(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Datum, error) {
+	contents := t.TupleContents()
+	r := bytes.NewBuffer(b)
+	var numElems int32
+	if err := binary.Read(r, binary.BigEndian, &numElems); err != nil {
+		return nil, err
+	}
+	if int(numElems) != len(contents) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"wrong number of columns: %d, expected %d", numElems, len(contents))
+	}
+	tup := tree.NewDTupleWithLen(t, len(contents))
+	var elemOid, vlen int32
+	for i := range contents {
+		if err := binary.Read(r, binary.BigEndian, &elemOid); err != nil {
+			return nil, err
+		}
+		if oid.Oid(elemOid) != contents[i].Oid() {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"wrong data type: %d, expected %d", elemOid, contents[i].Oid())
+		}
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		if vlen < 0 {
+			tup.D[i] = tree.DNull
+			continue
+		}
+		buf := r.Next(int(vlen))
+		if len(buf) != int(vlen) {
+			return nil, NewProtocolViolationErrorf("insufficient data: %d", len(buf))
+		}
+		elem, err := DecodeDatum(evalCtx, contents[i], FormatBinary, buf)
+		if err != nil {
+			return nil, err
+		}
+		tup.D[i] = elem
+	}
+	if r.Len() > 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("improper binary format in record")
+	}
+	return tup, nil
+}
+
+// decodeBinaryRange decodes the binary range format, which consists of a flags
+// byte followed by the length and value of each finite bound.
+func decodeBinaryRange(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Datum, error) {
+	if len(b) == 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("insufficient data: %d", len(b))
+	}
+	flags := b[0]
+	r := bytes.NewBuffer(b[1:])
+	if flags&PGBinaryRangeEmpty != 0 {
+		if r.Len() > 0 {
+			return nil, NewInvalidBinaryRepresentationErrorf("improper binary format in range")
+		}
+		return tree.NewDEmptyRange(t), nil
+	}
+	bounds := [2]tree.Datum{tree.DNull, tree.DNull}
+	infinite := [2]bool{flags&PGBinaryRangeLowerInf != 0, flags&PGBinaryRangeUpperInf != 0}
+	var vlen int32
+	for i := range bounds {
+		if infinite[i] {
+			continue
+		}
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		buf := r.Next(int(vlen))
+		if vlen < 0 || len(buf) != int(vlen) {
+			return nil, NewProtocolViolationErrorf("insufficient data: %d", len(buf))
+		}
+		d, err := DecodeDatum(evalCtx, t.RangeContents(), FormatBinary, buf)
+		if err != nil {
+			return nil, err
+		}
+		bounds[i] = d
+	}
+	if r.Len() > 0 {
+		return nil, NewInvalidBinaryRepresentationErrorf("improper binary format in range")
+	}
+	return tree.NewDRange(
+		evalCtx, t, bounds[0], bounds[1],
+		flags&PGBinaryRangeLowerInc != 0, flags&PGBinaryRangeUpperInc != 0,
+	)
+}
+
 // Values which are going to be converted to strings (STRING and NAME) need to
 // be valid UTF-8 for us to accept them.
 func validateStringBytes(b []byte) error {
@@ -965,3 +1075,17 @@ const (
 	// AF_NET + 1.
 	PGBinaryIPv6family byte = 3
 )
+
+// The flags used in the binary format of ranges.
+const (
+	// PGBinaryRangeEmpty is set if the range is empty.
+	PGBinaryRangeEmpty byte = 0x01
+	// PGBinaryRangeLowerInc is set if the lower bound is inclusive.
+	PGBinaryRangeLowerInc byte = 0x02
+	// PGBinaryRangeUpperInc is set if the upper bound is inclusive.
+	PGBinaryRangeUpperInc byte = 0x04
+	// PGBinaryRangeLowerInf is set if the range has no lower bound.
+	PGBinaryRangeLowerInf byte = 0x08
+	// PGBinaryRangeUpperInf is set if the range has no upper bound.
+	PGBinaryRangeUpperInf byte = 0x10
+)
@@ -199,6 +199,10 @@ func (b *writeBuffer) writeTextDatum(
 		b.textFormatter.FormatNode(d)
 		b.writeFromFmtCtx(b.textFormatter)
 
+	case *tree.DRange:
+		b.textFormatter.FormatNode(v)
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DOid:
 		b.writeLengthPrefixedDatum(v)
 
@@ -459,8 +463,14 @@ func (b *writeBuffer) writeBinaryDatum(
 		subWriter := newWriteBuffer(nil /* bytecount */)
 		// Put the number of datums.
 		subWriter.putInt32(int32(len(v.D)))
-		for _, elem := range v.D {
+		contents := v.ResolvedType().TupleContents()
+		for i, elem := range v.D {
 			oid := elem.ResolvedType().Oid()
+			if elem == tree.DNull && i < len(contents) {
+				// Use the declared field type for NULLs so that clients can
+				// still determine the type of the field.
+				oid = contents[i].Oid()
+			}
 			subWriter.putInt32(int32(oid))
 			subWriter.writeBinaryDatum(ctx, elem, sessionLoc, elem.ResolvedType())
 		}
@@ -520,6 +530,35 @@ func (b *writeBuffer) writeBinaryDatum(
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
+	case *tree.DRange:
+		// TODO(andrei): We shouldn't be allocating a new buffer for every range.
+		subWriter := newWriteBuffer(nil /* bytecount */)
+		var flags byte
+		switch {
+		case v.Empty:
+			flags |= pgwirebase.PGBinaryRangeEmpty
+		default:
+			if v.LowerInc {
+				flags |= pgwirebase.PGBinaryRangeLowerInc
+			}
+			if v.UpperInc {
+				flags |= pgwirebase.PGBinaryRangeUpperInc
+			}
+			if v.Lower == tree.DNull {
+				flags |= pgwirebase.PGBinaryRangeLowerInf
+			}
+			if v.Upper == tree.DNull {
+				flags |= pgwirebase.PGBinaryRangeUpperInf
+			}
+		}
+		subWriter.writeByte(flags)
+		subtype := v.ResolvedType().RangeContents()
+		for _, bound := range []tree.Datum{v.Lower, v.Upper} {
+			if bound != tree.DNull {
+				subWriter.writeBinaryDatum(ctx, bound, sessionLoc, subtype)
+			}
+		}
+		b.writeLengthPrefixedBuffer(&subWriter.wrapped)
 	default:
 		b.setError(errors.AssertionFailedf("unsupported type %T", d))
 	}
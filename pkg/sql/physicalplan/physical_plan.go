@@ -526,6 +526,7 @@ func (p *PhysicalPlan) AddProjection(columns []uint32) {
 			}
 			newOrdering[i].ColIdx = uint32(found)
 			newOrdering[i].Direction = c.Direction
+			newOrdering[i].NullsReversed = c.NullsReversed
 		}
 		p.MergeOrdering.Columns = newOrdering
 	}
@@ -680,6 +681,7 @@ func (p *PhysicalPlan) AddRendering(
 			}
 			newOrdering[i].ColIdx = uint32(found)
 			newOrdering[i].Direction = c.Direction
+			newOrdering[i].NullsReversed = c.NullsReversed
 		}
 		p.MergeOrdering.Columns = newOrdering
 	}
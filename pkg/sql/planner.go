@@ -159,6 +159,17 @@ type planner struct {
 
 	preparedStatements preparedStatementsAccessor
 
+	// sqlCursors is used to access the cursors of the session.
+	sqlCursors sqlCursors
+
+	// deferredConstraints is used to access the state of the deferrable
+	// constraints in the current transaction.
+	deferredConstraints deferredConstraints
+
+	// localSessionVars is used to access the session variables changed by SET
+	// LOCAL in the current transaction.
+	localSessionVars localSessionVars
+
 	// avoidCachedDescriptors, when true, instructs all code that
 	// accesses table/view descriptors to force reading the descriptors
 	// within the transaction. This is necessary to read descriptors
@@ -308,6 +319,9 @@ func newInternalPlanner(
 	p.stmt = Statement{}
 	p.cancelChecker = cancelchecker.NewCancelChecker(ctx)
 	p.isInternalPlanner = true
+	p.sqlCursors = emptySQLCursors{}
+	p.deferredConstraints = emptyDeferredConstraints{}
+	p.localSessionVars = emptyLocalSessionVars{}
 
 	p.semaCtx = tree.MakeSemaContext()
 	p.semaCtx.SearchPath = sd.SearchPath
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
 )
 
 type refreshMaterializedViewNode struct {
@@ -66,6 +67,19 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 		)
 	}
 
+	// Try to apply the changes since the last refresh to the view, and fall
+	// back to a full refresh if that is not possible.
+	if n.n.RefreshDataOption == tree.RefreshDataIncremental {
+		err := n.refreshIncrementally(params)
+		if !errors.Is(err, errIncrementalRefreshUnsupported) {
+			return err
+		}
+		params.p.BufferClientNotice(
+			params.ctx,
+			pgnotice.Newf("cannot refresh %q incrementally, refreshing it in full: %v", n.desc.Name, err),
+		)
+	}
+
 	// Prepare the new set of indexes by cloning all existing indexes on the view.
 	newPrimaryIndex := n.desc.GetPrimaryIndex().IndexDescDeepCopy()
 	newIndexes := make([]descpb.IndexDescriptor, len(n.desc.PublicNonPrimaryIndexes()))
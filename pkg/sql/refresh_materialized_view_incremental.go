@@ -0,0 +1,853 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+)
+
+// incrementalRefreshMaxChangedRows is the maximum number of changed rows that
+// an incremental refresh of a materialized view applies. If more rows of the
+// tables the view depends on were changed, the view is refreshed in full.
+var incrementalRefreshMaxChangedRows = settings.RegisterIntSetting(
+	"sql.materialized_view.incremental_refresh.max_changed_rows",
+	"the maximum number of changed rows for which REFRESH MATERIALIZED VIEW ... INCREMENTAL "+
+		"applies the changes to the view instead of refreshing it in full",
+	10000,
+	settings.NonNegativeInt,
+)
+
+// errIncrementalRefreshUnsupported marks the errors which indicate that a
+// materialized view cannot be refreshed incrementally. The view is refreshed in
+// full instead.
+var errIncrementalRefreshUnsupported = errors.New("incremental refresh unsupported")
+
+func incrementalRefreshUnsupportedf(format string, args ...interface{}) error {
+	return errors.Mark(errors.Newf(format, args...), errIncrementalRefreshUnsupported)
+}
+
+// errRangefeedCaughtUp is returned by the consumer of the rangefeed events in
+// changedPrimaryKeys to stop the rangefeed once all changes have been seen.
+var errRangefeedCaughtUp = errors.New("rangefeed caught up")
+
+// refreshIncrementally refreshes the materialized view by applying the changes
+// made to the tables the view depends on since its last refresh, rather than
+// recomputing the view from scratch. The changes are found with a rangefeed
+// over the primary indexes of the tables, whose catch-up scan uses an MVCC
+// incremental iterator to read only the versions written since the last
+// refresh. The view rows derived from the changed rows are then recomputed as
+// of the time of the last refresh and as of now, and the difference is written
+// to the view.
+//
+// This is possible if the view query is a single SELECT clause over inner
+// joins of tables, optionally with aggregation. For an aggregating query, the
+// groups which contain changed rows are recomputed in full. Otherwise, the view
+// rows which the changed rows contribute to are replaced.
+//
+// An error marked with errIncrementalRefreshUnsupported is returned before
+// anything is written if the view cannot be refreshed incrementally.
+func (n *refreshMaterializedViewNode) refreshIncrementally(params runParams) error {
+	ctx, p := params.ctx, params.p
+	if n.desc.RefreshAsOfTime == nil {
+		return incrementalRefreshUnsupportedf("the time of the last refresh of the view is unknown")
+	}
+	from := *n.desc.RefreshAsOfTime
+	if !kvserver.RangefeedEnabled.Get(&p.ExecCfg().Settings.SV) {
+		return incrementalRefreshUnsupportedf("rangefeeds require the kv.rangefeed.enabled setting")
+	}
+	if len(n.desc.PartialIndexes()) > 0 {
+		return incrementalRefreshUnsupportedf("the view has partial indexes")
+	}
+	primaryIndex := n.desc.GetPrimaryIndex()
+	if primaryIndex.NumColumns() != 1 {
+		return incrementalRefreshUnsupportedf("the view does not have a hidden primary key")
+	}
+	rowIDCol, err := n.desc.FindColumnByID(primaryIndex.GetColumnID(0))
+	if err != nil {
+		return err
+	}
+	if !rowIDCol.Hidden {
+		return incrementalRefreshUnsupportedf("the view does not have a hidden primary key")
+	}
+
+	q, err := analyzeIncrementalViewQuery(ctx, p, n.desc.ViewQuery)
+	if err != nil {
+		return err
+	}
+	var visibleCols []int
+	for i := range n.desc.Columns {
+		if !n.desc.Columns[i].Hidden {
+			visibleCols = append(visibleCols, i)
+		}
+	}
+	if len(visibleCols) != len(q.sel.Exprs) {
+		return errors.AssertionFailedf(
+			"view has %d columns, but its query returns %d", len(visibleCols), len(q.sel.Exprs),
+		)
+	}
+	var tables []*tabledesc.Immutable
+	seen := make(map[descpb.ID]bool)
+	for _, src := range q.sources {
+		if from.Less(src.desc.GetModificationTime()) {
+			return incrementalRefreshUnsupportedf(
+				"the schema of table %q was changed since the last refresh", src.desc.GetName(),
+			)
+		}
+		if src.desc.IsInterleaved() {
+			return incrementalRefreshUnsupportedf("table %q is interleaved", src.desc.GetName())
+		}
+		if !seen[src.desc.GetID()] {
+			seen[src.desc.GetID()] = true
+			tables = append(tables, src.desc)
+		}
+	}
+
+	to := p.Txn().ReadTimestamp()
+	changes, err := p.changedPrimaryKeys(ctx, tables, from, to)
+	if err != nil {
+		return err
+	}
+
+	colIdxMap := n.desc.ColumnIdxMap()
+	r := incrementalViewRefresher{
+		p:           p,
+		view:        n.desc,
+		query:       q,
+		visibleCols: visibleCols,
+		rowIDCol:    colIdxMap.GetDefault(rowIDCol.ID),
+		from:        from,
+		to:          to,
+	}
+	if filter := q.changedRowsFilter(changes); filter != nil {
+		var oldRows, newRows []tree.Datums
+		if q.aggregate {
+			oldRows, newRows, err = r.aggregateChanges(ctx, filter)
+		} else {
+			oldRows, newRows, err = r.rowChanges(ctx, filter)
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.write(ctx, oldRows, newRows); err != nil {
+			return err
+		}
+	}
+
+	n.desc.RefreshAsOfTime = &to
+	return p.writeSchemaChange(
+		ctx, n.desc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// incrementalViewQuery is the analyzed query of a materialized view which can
+// be refreshed incrementally.
+type incrementalViewQuery struct {
+	sel *tree.SelectClause
+	// sources are the tables in the FROM clause of the query.
+	sources []incrementalViewSource
+	// aggregate is true if the query computes aggregates.
+	aggregate bool
+	// groupCols are the ordinals of the output columns of an aggregating query
+	// which hold its grouping expressions.
+	groupCols []int
+}
+
+// incrementalViewSource is a table in the FROM clause of a view query.
+type incrementalViewSource struct {
+	desc *tabledesc.Immutable
+	// name is the name that the query uses to refer to the table.
+	name tree.TableName
+}
+
+// analyzeIncrementalViewQuery parses the given view query and checks that the
+// view can be refreshed incrementally.
+func analyzeIncrementalViewQuery(
+	ctx context.Context, p *planner, query string,
+) (*incrementalViewQuery, error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected view query %q", query)
+	}
+	if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil, incrementalRefreshUnsupportedf(
+			"the view query has a WITH, ORDER BY, LIMIT or locking clause",
+		)
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.TableSelect {
+		return nil, incrementalRefreshUnsupportedf("the view query is not a simple SELECT")
+	}
+	if clause.Distinct || clause.DistinctOn != nil || clause.Window != nil {
+		return nil, incrementalRefreshUnsupportedf(
+			"the view query has a DISTINCT, DISTINCT ON or WINDOW clause",
+		)
+	}
+
+	q := &incrementalViewQuery{sel: clause}
+	// The grouping expressions are matched to the output columns before the
+	// function names in the query are resolved, since resolution changes how
+	// the expressions are formatted.
+	if len(clause.GroupBy) > 0 || clause.Having != nil {
+		q.aggregate = true
+	}
+	for _, g := range clause.GroupBy {
+		i := findSelectExpr(clause.Exprs, g)
+		if i < 0 {
+			return nil, incrementalRefreshUnsupportedf(
+				"the grouping expression %s is not a column of the view", tree.AsString(g),
+			)
+		}
+		q.groupCols = append(q.groupCols, i)
+	}
+
+	v := incrementalViewExprVisitor{searchPath: p.CurrentSearchPath()}
+	for _, t := range clause.From.Tables {
+		if err := q.addSources(ctx, p, &v, t); err != nil {
+			return nil, err
+		}
+	}
+	if len(q.sources) == 0 {
+		return nil, incrementalRefreshUnsupportedf("the view query does not read from any tables")
+	}
+	for _, e := range clause.Exprs {
+		v.walk(e.Expr)
+	}
+	if clause.Where != nil {
+		v.walk(clause.Where.Expr)
+	}
+	if clause.Having != nil {
+		v.walk(clause.Having.Expr)
+	}
+	if v.err != nil {
+		return nil, v.err
+	}
+	q.aggregate = q.aggregate || v.aggregate
+	return q, nil
+}
+
+// findSelectExpr returns the ordinal of the select expression which the given
+// grouping expression refers to, or -1 if there is none.
+func findSelectExpr(exprs tree.SelectExprs, g tree.Expr) int {
+	if num, ok := g.(*tree.NumVal); ok {
+		if i, err := num.AsInt64(); err == nil && i >= 1 && int(i) <= len(exprs) {
+			return int(i) - 1
+		}
+		return -1
+	}
+	s := tree.AsStringWithFlags(g, tree.FmtParsable)
+	for i := range exprs {
+		if tree.AsStringWithFlags(exprs[i].Expr, tree.FmtParsable) == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// addSources adds the tables in the given FROM clause expression to the
+// sources of the query.
+func (q *incrementalViewQuery) addSources(
+	ctx context.Context, p *planner, v *incrementalViewExprVisitor, expr tree.TableExpr,
+) error {
+	switch t := expr.(type) {
+	case *tree.AliasedTableExpr:
+		tn, ok := t.Expr.(*tree.TableName)
+		if !ok || t.Ordinality || t.Lateral || len(t.As.Cols) > 0 {
+			return incrementalRefreshUnsupportedf(
+				"the view query reads from %s, which is not a table", tree.AsString(t),
+			)
+		}
+		resolved := *tn
+		desc, err := p.ResolveUncachedTableDescriptor(
+			ctx, &resolved, true /* required */, tree.ResolveAnyTableKind,
+		)
+		if err != nil {
+			return err
+		}
+		if !desc.IsTable() || desc.IsVirtualTable() {
+			return incrementalRefreshUnsupportedf(
+				"the view query reads from %s, which is not a table", tree.AsString(tn),
+			)
+		}
+		name := *tn
+		if t.As.Alias != "" {
+			name = tree.MakeUnqualifiedTableName(t.As.Alias)
+		}
+		q.sources = append(q.sources, incrementalViewSource{desc: desc, name: name})
+		return nil
+
+	case *tree.ParenTableExpr:
+		return q.addSources(ctx, p, v, t.Expr)
+
+	case *tree.JoinTableExpr:
+		// The rows of an outer join which are extended with NULLs are not
+		// derived from any row of one side of the join, so changes to that side
+		// cannot be traced to them.
+		if t.JoinType != "" && t.JoinType != tree.AstInner && t.JoinType != tree.AstCross {
+			return incrementalRefreshUnsupportedf("the view query has an outer join")
+		}
+		if err := q.addSources(ctx, p, v, t.Left); err != nil {
+			return err
+		}
+		if err := q.addSources(ctx, p, v, t.Right); err != nil {
+			return err
+		}
+		if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+			v.walk(on.Expr)
+		}
+		return nil
+
+	default:
+		return incrementalRefreshUnsupportedf(
+			"the view query reads from %s, which is not a table", tree.AsString(t),
+		)
+	}
+}
+
+// changedRowsFilter returns an expression which filters the rows of the FROM
+// clause of the query to those which are derived from any of the given changed
+// rows, or nil if there are no changed rows.
+func (q *incrementalViewQuery) changedRowsFilter(changes map[descpb.ID][]tree.Datums) tree.Expr {
+	var filter tree.Expr
+	for i := range q.sources {
+		src := &q.sources[i]
+		pks := changes[src.desc.GetID()]
+		if len(pks) == 0 {
+			continue
+		}
+		index := src.desc.GetPrimaryIndex()
+		cols := make(tree.Exprs, index.NumColumns())
+		for j := range cols {
+			cols[j] = &tree.ColumnItem{
+				TableName:  src.name.ToUnresolvedObjectName(),
+				ColumnName: tree.Name(index.GetColumnName(j)),
+			}
+		}
+		filter = orExprs(filter, makeKeyFilter(cols, pks))
+	}
+	return filter
+}
+
+// selectAsOf returns the query which evaluates the given expressions over the
+// rows of the FROM clause of the view query which satisfy both its WHERE
+// clause and the given filter, as of the given timestamp. If group is true, the
+// grouping of the view query is applied to the rows.
+func (q *incrementalViewQuery) selectAsOf(
+	exprs tree.SelectExprs, filter tree.Expr, asOf hlc.Timestamp, distinct bool, group bool,
+) string {
+	sel := *q.sel
+	sel.Exprs = exprs
+	sel.Distinct = distinct
+	if !group {
+		sel.GroupBy = nil
+		sel.Having = nil
+	}
+	if filter != nil {
+		if q.sel.Where != nil {
+			filter = &tree.AndExpr{Left: &tree.ParenExpr{Expr: q.sel.Where.Expr}, Right: filter}
+		}
+		sel.Where = tree.NewWhere(tree.AstWhere, filter)
+	}
+	sel.From.AsOf = tree.AsOfClause{Expr: tree.NewStrVal(asOf.AsOfSystemTime())}
+	return tree.AsStringWithFlags(&sel, tree.FmtParsable)
+}
+
+// groupExprs returns the grouping expressions of an aggregating query.
+func (q *incrementalViewQuery) groupExprs() tree.SelectExprs {
+	exprs := make(tree.SelectExprs, len(q.groupCols))
+	for i, c := range q.groupCols {
+		exprs[i] = tree.SelectExpr{Expr: q.sel.Exprs[c].Expr}
+	}
+	return exprs
+}
+
+// incrementalViewExprVisitor checks that the expressions of a view query
+// produce the same results when they are evaluated over a subset of the rows
+// of the query, and at different times.
+type incrementalViewExprVisitor struct {
+	searchPath sessiondata.SearchPath
+	// aggregate is set if the expressions contain an aggregate function.
+	aggregate bool
+	err       error
+}
+
+var _ tree.Visitor = &incrementalViewExprVisitor{}
+
+func (v *incrementalViewExprVisitor) walk(expr tree.Expr) {
+	if v.err == nil {
+		tree.WalkExprConst(v, expr)
+	}
+}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *incrementalViewExprVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.err = incrementalRefreshUnsupportedf("the view query contains a subquery")
+		return false, expr
+
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.err = incrementalRefreshUnsupportedf("the view query contains a window function")
+			return false, expr
+		}
+		def, err := t.Func.Resolve(v.searchPath)
+		if err != nil {
+			v.err = incrementalRefreshUnsupportedf("the view query calls %s, which is not a builtin", t.Func.String())
+			return false, expr
+		}
+		switch def.Class {
+		case tree.NormalClass:
+		case tree.AggregateClass:
+			v.aggregate = true
+		default:
+			v.err = incrementalRefreshUnsupportedf(
+				"the view query calls %s, which is not a scalar or aggregate function", def.Name,
+			)
+			return false, expr
+		}
+		for _, o := range def.Definition {
+			if o, ok := o.(*tree.Overload); ok && o.Volatility > tree.VolatilityImmutable {
+				v.err = incrementalRefreshUnsupportedf(
+					"the view query calls %s, which is not immutable", def.Name,
+				)
+				return false, expr
+			}
+		}
+	}
+	return true, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *incrementalViewExprVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// makeKeyFilter returns an expression which is true for the rows whose values
+// of the given columns match any of the given keys. NULL values match NULL.
+func makeKeyFilter(cols tree.Exprs, keys []tree.Datums) tree.Expr {
+	var filter tree.Expr
+	var tuples tree.Exprs
+	for _, key := range keys {
+		hasNull := false
+		for _, d := range key {
+			hasNull = hasNull || d == tree.DNull
+		}
+		if !hasNull {
+			tuple := make(tree.Exprs, len(key))
+			for i, d := range key {
+				tuple[i] = d
+			}
+			tuples = append(tuples, &tree.Tuple{Exprs: tuple})
+			continue
+		}
+		var match tree.Expr
+		for i, d := range key {
+			match = andExprs(match, &tree.ComparisonExpr{
+				Operator: tree.IsNotDistinctFrom, Left: cols[i], Right: d,
+			})
+		}
+		filter = orExprs(filter, match)
+	}
+	if len(tuples) > 0 {
+		filter = orExprs(filter, &tree.ComparisonExpr{
+			Operator: tree.In, Left: &tree.Tuple{Exprs: cols}, Right: &tree.Tuple{Exprs: tuples},
+		})
+	}
+	return filter
+}
+
+func andExprs(left, right tree.Expr) tree.Expr {
+	if left == nil {
+		return right
+	}
+	return &tree.AndExpr{Left: left, Right: right}
+}
+
+func orExprs(left, right tree.Expr) tree.Expr {
+	if left == nil {
+		return right
+	}
+	return &tree.OrExpr{Left: left, Right: right}
+}
+
+// changedPrimaryKeys returns the primary keys of the rows of the given tables
+// which were changed after from and at or before to, keyed by table ID.
+func (p *planner) changedPrimaryKeys(
+	ctx context.Context, tables []*tabledesc.Immutable, from, to hlc.Timestamp,
+) (map[descpb.ID][]tree.Datums, error) {
+	codec := p.ExecCfg().Codec
+	maxRows := int(incrementalRefreshMaxChangedRows.Get(&p.ExecCfg().Settings.SV))
+	spans := make([]roachpb.Span, len(tables))
+	for i, desc := range tables {
+		spans[i] = desc.PrimaryIndexSpan(codec)
+	}
+	frontier := span.MakeFrontier(spans...)
+	// changed contains the keys of the changed rows, without their column
+	// family suffix, so that every row is only included once.
+	changed := make(map[string]roachpb.Key)
+
+	eventCh := make(chan *roachpb.RangeFeedEvent, 128)
+	g := ctxgroup.WithContext(ctx)
+	for _, sp := range spans {
+		sp := sp
+		g.GoCtx(func(ctx context.Context) error {
+			return p.ExecCfg().DistSender.RangeFeed(ctx, sp, from, false /* withDiff */, eventCh)
+		})
+	}
+	g.GoCtx(func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ev := <-eventCh:
+				switch {
+				case ev.Val != nil:
+					if ts := ev.Val.Value.Timestamp; ts.LessEq(from) || to.Less(ts) {
+						continue
+					}
+					rowKey, err := keys.EnsureSafeSplitKey(ev.Val.Key)
+					if err != nil {
+						return err
+					}
+					changed[string(rowKey)] = rowKey
+					if len(changed) > maxRows {
+						return incrementalRefreshUnsupportedf(
+							"more than %d rows were changed since the last refresh", maxRows,
+						)
+					}
+				case ev.Checkpoint != nil:
+					frontier.Forward(ev.Checkpoint.Span, ev.Checkpoint.ResolvedTS)
+					if to.LessEq(frontier.Frontier()) {
+						return errRangefeedCaughtUp
+					}
+				case ev.Error != nil:
+					return ev.Error.Error.GoError()
+				}
+			}
+		}
+	})
+	if err := g.Wait(); !errors.Is(err, errRangefeedCaughtUp) {
+		if errors.HasType(err, (*roachpb.BatchTimestampBeforeGCError)(nil)) {
+			return nil, incrementalRefreshUnsupportedf(
+				"the changes since the last refresh have been garbage collected",
+			)
+		}
+		return nil, err
+	}
+
+	res := make(map[descpb.ID][]tree.Datums)
+	var alloc rowenc.DatumAlloc
+	for _, key := range changed {
+		for _, desc := range tables {
+			pk, ok, err := decodePrimaryKey(codec, desc, key, &alloc)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				res[desc.GetID()] = append(res[desc.GetID()], pk)
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// decodePrimaryKey decodes the primary key of the row of the given table with
+// the given key. It returns false if the key does not belong to the primary
+// index of the table.
+func decodePrimaryKey(
+	codec keys.SQLCodec, desc *tabledesc.Immutable, key roachpb.Key, alloc *rowenc.DatumAlloc,
+) (tree.Datums, bool, error) {
+	if !desc.PrimaryIndexSpan(codec).ContainsKey(key) {
+		return nil, false, nil
+	}
+	index := desc.GetPrimaryIndex()
+	colTypes := make([]*types.T, index.NumColumns())
+	for i := range colTypes {
+		col, err := desc.FindColumnByID(index.GetColumnID(i))
+		if err != nil {
+			return nil, false, err
+		}
+		colTypes[i] = col.Type
+	}
+	vals := make([]rowenc.EncDatum, len(colTypes))
+	if _, _, _, err := rowenc.DecodeIndexKey(
+		codec, desc, index.IndexDesc(), colTypes, vals, index.IndexDesc().ColumnDirections, key,
+	); err != nil {
+		return nil, false, err
+	}
+	pk := make(tree.Datums, len(vals))
+	for i := range vals {
+		if err := vals[i].EnsureDecoded(colTypes[i], alloc); err != nil {
+			return nil, false, err
+		}
+		pk[i] = vals[i].Datum
+	}
+	return pk, true, nil
+}
+
+// incrementalViewRefresher computes and writes the changes to the rows of a
+// materialized view during an incremental refresh.
+type incrementalViewRefresher struct {
+	p     *planner
+	view  *tabledesc.Mutable
+	query *incrementalViewQuery
+	// visibleCols are the ordinals of the view columns which hold the results
+	// of the view query, in the order of the query's select expressions.
+	visibleCols []int
+	// rowIDCol is the ordinal of the hidden primary key column of the view.
+	rowIDCol int
+	// from is the timestamp of the last refresh, and to is the timestamp which
+	// the view is refreshed to.
+	from, to hlc.Timestamp
+}
+
+// aggregateChanges returns the view rows to delete and the rows to insert for
+// an aggregating view query. The groups which contain any rows matching the
+// given filter, either as of the last refresh or now, are recomputed in full.
+func (r *incrementalViewRefresher) aggregateChanges(
+	ctx context.Context, filter tree.Expr,
+) (oldRows, newRows []tree.Datums, _ error) {
+	var viewFilter, groupFilter tree.Expr
+	if len(r.query.groupCols) > 0 {
+		groups := make(map[string]tree.Datums)
+		for _, ts := range []hlc.Timestamp{r.from, r.to} {
+			rows, err := r.queryAsOf(ctx, r.query.selectAsOf(
+				r.query.groupExprs(), filter, ts, true /* distinct */, false, /* group */
+			))
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, row := range rows {
+				groups[datumsKey(row)] = row
+			}
+		}
+		if len(groups) == 0 {
+			return nil, nil, nil
+		}
+		keys := make([]tree.Datums, 0, len(groups))
+		for _, g := range groups {
+			keys = append(keys, g)
+		}
+		viewCols := make(tree.Exprs, len(r.query.groupCols))
+		sourceCols := make(tree.Exprs, len(r.query.groupCols))
+		for i, c := range r.query.groupCols {
+			viewCols[i] = &tree.ColumnItem{ColumnName: tree.Name(r.view.Columns[r.visibleCols[c]].Name)}
+			sourceCols[i] = r.query.sel.Exprs[c].Expr
+		}
+		viewFilter = makeKeyFilter(viewCols, keys)
+		groupFilter = makeKeyFilter(sourceCols, keys)
+	}
+	// A query without grouping expressions produces a single row, which is
+	// recomputed from all of the rows of the query.
+	oldRows, err := r.viewRows(ctx, viewFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+	newRows, err = r.queryAsOf(ctx, r.query.selectAsOf(
+		r.query.sel.Exprs, groupFilter, r.to, false /* distinct */, true, /* group */
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+	return oldRows, newRows, nil
+}
+
+// rowChanges returns the view rows to delete and the rows to insert for a
+// view query without aggregation. The view rows derived from the rows matching
+// the given filter as of the last refresh are replaced by the ones derived from
+// the matching rows now. Since a view row is not linked to the rows it was
+// derived from, any view row with the same values can be deleted.
+func (r *incrementalViewRefresher) rowChanges(
+	ctx context.Context, filter tree.Expr,
+) (oldRows, newRows []tree.Datums, _ error) {
+	before, err := r.queryAsOf(ctx, r.query.selectAsOf(
+		r.query.sel.Exprs, filter, r.from, false /* distinct */, false, /* group */
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := r.queryAsOf(ctx, r.query.selectAsOf(
+		r.query.sel.Exprs, filter, r.to, false /* distinct */, false, /* group */
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Rows which are both in before and in after don't need to be changed.
+	counts := make(map[string]int)
+	rows := make(map[string]tree.Datums)
+	for _, row := range before {
+		k := datumsKey(row)
+		counts[k]--
+		rows[k] = row
+	}
+	for _, row := range after {
+		k := datumsKey(row)
+		counts[k]++
+		rows[k] = row
+	}
+	var deleted []tree.Datums
+	for k, c := range counts {
+		if c < 0 {
+			deleted = append(deleted, rows[k])
+		}
+		for ; c > 0; c-- {
+			newRows = append(newRows, rows[k])
+		}
+	}
+	if len(deleted) == 0 {
+		return nil, newRows, nil
+	}
+
+	viewCols := make(tree.Exprs, len(r.visibleCols))
+	for i, c := range r.visibleCols {
+		viewCols[i] = &tree.ColumnItem{ColumnName: tree.Name(r.view.Columns[c].Name)}
+	}
+	candidates, err := r.viewRows(ctx, makeKeyFilter(viewCols, deleted))
+	if err != nil {
+		return nil, nil, err
+	}
+	visible := make(tree.Datums, len(r.visibleCols))
+	for _, row := range candidates {
+		for i, c := range r.visibleCols {
+			visible[i] = row[c]
+		}
+		if k := datumsKey(visible); counts[k] < 0 {
+			counts[k]++
+			oldRows = append(oldRows, row)
+		}
+	}
+	for _, c := range counts {
+		if c < 0 {
+			return nil, nil, incrementalRefreshUnsupportedf(
+				"the view does not contain the results of its query as of the last refresh",
+			)
+		}
+	}
+	return oldRows, newRows, nil
+}
+
+// queryAsOf runs the given historical query outside of the transaction of the
+// refresh.
+func (r *incrementalViewRefresher) queryAsOf(ctx context.Context, query string) ([]tree.Datums, error) {
+	return r.p.ExecCfg().InternalExecutor.QueryEx(
+		ctx, "refresh-view-incremental", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		query,
+	)
+}
+
+// viewRows returns all of the columns of the view rows which match the given
+// filter, which may be nil.
+func (r *incrementalViewRefresher) viewRows(
+	ctx context.Context, filter tree.Expr,
+) ([]tree.Datums, error) {
+	sel := tree.SelectClause{
+		From: tree.From{Tables: tree.TableExprs{&tree.TableRef{
+			TableID: int64(r.view.GetID()),
+			As:      tree.AliasClause{Alias: "v"},
+		}}},
+	}
+	for i := range r.view.Columns {
+		sel.Exprs = append(sel.Exprs, tree.SelectExpr{
+			Expr: &tree.ColumnItem{ColumnName: tree.Name(r.view.Columns[i].Name)},
+		})
+	}
+	if filter != nil {
+		sel.Where = tree.NewWhere(tree.AstWhere, filter)
+	}
+	return r.p.ExecCfg().InternalExecutor.QueryEx(
+		ctx, "refresh-view-incremental", r.p.Txn(),
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		tree.AsStringWithFlags(&sel, tree.FmtParsable),
+	)
+}
+
+// write deletes the given view rows, which contain all of the view columns,
+// and inserts the given results of the view query.
+func (r *incrementalViewRefresher) write(
+	ctx context.Context, oldRows, newRows []tree.Datums,
+) error {
+	p := r.p
+	codec := p.ExecCfg().Codec
+	desc := r.view.ImmutableCopy().(*tabledesc.Immutable)
+	traceKV := p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	// Neither deletes nor inserts need to update partial indexes, since views
+	// with partial indexes are not refreshed incrementally.
+	var pm row.PartialIndexUpdateHelper
+
+	// The old rows are deleted in a separate batch, so that the new rows can
+	// reuse their values in unique indexes.
+	if len(oldRows) > 0 {
+		rd := row.MakeDeleter(codec, desc, r.view.Columns)
+		b := p.Txn().NewBatch()
+		for _, values := range oldRows {
+			if err := rd.DeleteRow(ctx, b, values, pm, traceKV); err != nil {
+				return err
+			}
+		}
+		if err := p.Txn().Run(ctx, b); err != nil {
+			return row.ConvertBatchError(ctx, desc, b)
+		}
+	}
+
+	if len(newRows) > 0 {
+		ri, err := row.MakeInserter(ctx, p.Txn(), codec, desc, r.view.Columns, p.alloc)
+		if err != nil {
+			return err
+		}
+		b := p.Txn().NewBatch()
+		values := make(tree.Datums, len(r.view.Columns))
+		for _, newRow := range newRows {
+			for i, c := range r.visibleCols {
+				values[c] = newRow[i]
+			}
+			values[r.rowIDCol] = tree.NewDInt(builtins.GenerateUniqueInt(p.EvalContext().NodeID.SQLInstanceID()))
+			if err := ri.InsertRow(ctx, b, values, pm, false /* overwrite */, traceKV); err != nil {
+				return err
+			}
+		}
+		if err := p.Txn().Run(ctx, b); err != nil {
+			return row.ConvertBatchError(ctx, desc, b)
+		}
+	}
+	return nil
+}
+
+// datumsKey returns a string which identifies the given values.
+func datumsKey(row tree.Datums) string {
+	return tree.AsStringWithFlags(&row, tree.FmtParsable)
+}
@@ -123,6 +123,15 @@ func (p *planner) renameColumn(
 			)
 		}
 	}
+	for _, ref := range tableDesc.DependedOnByFunctions {
+		for _, colID := range ref.ColumnIDs {
+			if colID == col.ID {
+				return false, p.dependentFunctionErrorByID(
+					ctx, "column", oldName.String(), tableDesc.ParentID, ref.ID, "rename",
+				)
+			}
+		}
+	}
 	if *oldName == *newName {
 		// Noop.
 		return false, nil
@@ -235,6 +235,14 @@ func (n *renameDatabaseNode) startExec(params runParams) error {
 			}); err != nil {
 				return err
 			}
+
+			// Function bodies always name the database of the relations they
+			// refer to.
+			if refs := tbDesc.GetDependedOnByFunctions(); len(refs) > 0 {
+				return p.dependentFunctionErrorByID(
+					ctx, "database", dbDesc.GetName(), dbDesc.GetID(), refs[0].ID, "rename",
+				)
+			}
 		}
 	}
 
@@ -90,6 +90,13 @@ func (p *planner) RenameTable(ctx context.Context, n *tree.RenameTable) (planNod
 			tableDesc.ParentID, tableDesc.DependedOnBy[0].ID, "rename",
 		)
 	}
+	// The same goes for the bodies of user-defined functions.
+	if len(tableDesc.DependedOnByFunctions) > 0 {
+		return nil, p.dependentFunctionErrorByID(
+			ctx, tableDesc.TypeName(), oldTn.String(),
+			tableDesc.ParentID, tableDesc.DependedOnByFunctions[0].ID, "rename",
+		)
+	}
 
 	return &renameTableNode{n: n, oldTn: &oldTn, newTn: &newTn, tableDesc: tableDesc}, nil
 }
@@ -238,8 +238,27 @@ func (n *reparentDatabaseNode) startExec(params runParams) error {
 			if err != nil {
 				return err
 			}
-			// If we couldn't find the object at all, then continue.
 			if !found {
+				// Function bodies refer to relations by their fully qualified names,
+				// which change when the database becomes a schema, and function
+				// bodies are not rewritten. Refuse to convert a database which
+				// contains functions.
+				found, _, err = p.LookupObject(
+					ctx,
+					tree.ObjectLookupFlags{DesiredObjectKind: tree.FunctionObject},
+					objName.Catalog(),
+					objName.Schema(),
+					objName.Object(),
+				)
+				if err != nil {
+					return err
+				}
+				if found {
+					return pgerror.Newf(pgcode.FeatureNotSupported,
+						"cannot convert database %q into schema because it contains function %q",
+						n.db.Name, objName.Object())
+				}
+				// If we couldn't find the object at all, then continue.
 				continue
 			}
 			// Remap the ID's on the type.
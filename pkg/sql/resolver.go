@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -322,6 +323,32 @@ func getDescriptorsFromTargetListForPrivilegeChange(
 		return descs, nil
 	}
 
+	if targets.Functions != nil {
+		if len(targets.Functions) == 0 {
+			return nil, errNoFunction
+		}
+		descs := make([]catalog.Descriptor, 0, len(targets.Functions))
+		for i := range targets.Functions {
+			fo := &targets.Functions[i]
+			_, descriptor, err := p.ResolveMutableFunctionDescriptor(ctx, fo.FuncName, true /* required */)
+			if err != nil {
+				return nil, err
+			}
+			if fo.Params != nil {
+				matches, err := p.functionParamsMatch(ctx, descriptor, fo.Params)
+				if err != nil {
+					return nil, err
+				}
+				if !matches {
+					return nil, pgerror.Newf(pgcode.UndefinedFunction,
+						"function %s does not exist", tree.AsString(fo))
+				}
+			}
+			descs = append(descs, descriptor)
+		}
+		return descs, nil
+	}
+
 	if targets.Schemas != nil {
 		if len(targets.Schemas) == 0 {
 			return nil, errNoSchema
@@ -398,11 +425,11 @@ func getDescriptorsFromTargetListForPrivilegeChange(
 	return descs, nil
 }
 
-// getQualifiedTableName returns the database-qualified name of the table
-// or view represented by the provided descriptor. It is a sort of
+// getQualifiedTableName returns the database-qualified name of the table,
+// view or function represented by the provided descriptor. It is a sort of
 // reverse of the Resolve() functions.
 func (p *planner) getQualifiedTableName(
-	ctx context.Context, desc catalog.TableDescriptor,
+	ctx context.Context, desc catalog.Descriptor,
 ) (*tree.TableName, error) {
 	dbDesc, err := p.Descriptors().GetImmutableDatabaseByID(ctx, p.txn, desc.GetParentID(),
 		tree.DatabaseLookupFlags{})
@@ -925,6 +952,23 @@ func (p *planner) ResolveMutableTypeDescriptor(
 	return desc, nil
 }
 
+// ResolveMutableFunctionDescriptor resolves a function descriptor for mutable
+// access. It also returns the fully qualified name of the function.
+func (p *planner) ResolveMutableFunctionDescriptor(
+	ctx context.Context, name *tree.UnresolvedObjectName, required bool,
+) (*tree.TableName, *funcdesc.Mutable, error) {
+	prefix, desc, err := resolver.ResolveMutableFunction(ctx, p, name, required)
+	if err != nil || desc == nil {
+		return nil, nil, err
+	}
+	// Ensure that the user can access the target schema.
+	if err := p.canResolveDescUnderSchema(ctx, desc.GetParentSchemaID(), desc); err != nil {
+		return nil, nil, err
+	}
+	fn := tree.MakeTableNameFromPrefix(prefix, tree.Name(name.Object()))
+	return &fn, desc, nil
+}
+
 // The versions below are part of the work for #34240.
 // TODO(radu): clean these up when everything is switched over.
 
@@ -1525,7 +1525,12 @@ func (rf *Fetcher) checkKeyOrdering(ctx context.Context) error {
 	// ordering.
 	for i, id := range rf.rowReadyTable.index.ColumnIDs {
 		idx := rf.rowReadyTable.colIdxMap.GetDefault(id)
-		result := rf.rowReadyTable.decodedRow[idx].Compare(&evalCtx, rf.rowReadyTable.lastDatums[idx])
+		cur, last := rf.rowReadyTable.decodedRow[idx], rf.rowReadyTable.lastDatums[idx]
+		result := cur.Compare(&evalCtx, last)
+		if rf.rowReadyTable.index.ColumnNullsReversedAt(i) && (cur == tree.DNull) != (last == tree.DNull) {
+			// NULLs sort opposite to the default in this column.
+			result = -result
+		}
 		expectedDirection := rf.rowReadyTable.index.ColumnDirections[i]
 		if rf.reverse && expectedDirection == descpb.IndexDescriptor_ASC {
 			expectedDirection = descpb.IndexDescriptor_DESC
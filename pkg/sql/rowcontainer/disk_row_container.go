@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -281,6 +282,13 @@ func (d *DiskRowContainer) encodeRow(ctx context.Context, row rowenc.EncDatumRow
 
 	for i, orderInfo := range d.ordering {
 		col := orderInfo.ColIdx
+		if orderInfo.NullsReversed && row[col].IsNull() {
+			// NULLs are encoded with the marker that sorts at the opposite end.
+			d.scratchKey = rowenc.EncodeNullTableKey(
+				d.scratchKey, orderInfo.Direction, true, /* nullsReversed */
+			)
+			continue
+		}
 		var err error
 		d.scratchKey, err = row[col].Encode(d.types[col], d.datumAlloc, d.encodings[i], d.scratchKey)
 		if err != nil {
@@ -414,6 +422,11 @@ func (d *DiskRowContainer) keyValToRow(k []byte, v []byte) (rowenc.EncDatumRow,
 			return nil, errors.NewAssertionErrorWithWrappedErrf(err,
 				"unable to decode row, column idx %d", errors.Safe(col))
 		}
+		if orderInfo.NullsReversed && d.scratchEncRow[col].IsNull() {
+			// Don't leak the reversed NULL marker into the encoded datum, since
+			// it doesn't match the column's key encoding.
+			d.scratchEncRow[col] = rowenc.DatumToEncDatum(d.types[col], tree.DNull)
+		}
 	}
 	for _, i := range d.valueIdxs {
 		var err error
@@ -52,10 +52,7 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 	}
 
 	if val == tree.DNull {
-		if dir == encoding.Ascending {
-			return encoding.EncodeNullAscending(b), nil
-		}
-		return encoding.EncodeNullDescending(b), nil
+		return EncodeNullTableKey(b, dir, false /* nullsReversed */), nil
 	}
 
 	switch t := tree.UnwrapDatum(nil, val).(type) {
@@ -184,6 +181,15 @@ func EncodeTableKey(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, e
 			return encoding.EncodeBytesAscending(b, t.PhysicalRep), nil
 		}
 		return encoding.EncodeBytesDescending(b, t.PhysicalRep), nil
+	case *tree.DRange:
+		data, err := encodeRangeContents(nil /* appendTo */, t)
+		if err != nil {
+			return nil, err
+		}
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, data), nil
+		}
+		return encoding.EncodeBytesDescending(b, data), nil
 	case *tree.DJSON:
 		return nil, unimplemented.NewWithIssue(35706, "unable to encode JSON as a table key")
 	}
@@ -200,6 +206,17 @@ func SkipTableKey(key []byte) ([]byte, error) {
 	return key[skipLen:], nil
 }
 
+// EncodeNullTableKey encodes a NULL key value for a column with the given
+// direction. NULLs sort before all other values in an ascending column and
+// after them in a descending one, unless nullsReversed is true, in which case
+// it is the opposite. DecodeTableKey recognizes either encoding.
+func EncodeNullTableKey(b []byte, dir encoding.Direction, nullsReversed bool) []byte {
+	if (dir == encoding.Ascending) != nullsReversed {
+		return encoding.EncodeNullAscending(b)
+	}
+	return encoding.EncodeNullDescending(b)
+}
+
 // DecodeTableKey decodes a value encoded by EncodeTableKey.
 func DecodeTableKey(
 	a *DatumAlloc, valType *types.T, key []byte, dir encoding.Direction,
@@ -423,6 +440,18 @@ func DecodeTableKey(
 			return nil, nil, err
 		}
 		return a.NewDEnum(tree.DEnum{EnumTyp: valType, PhysicalRep: phys, LogicalRep: log}), rkey, nil
+	case types.RangeFamily:
+		var r []byte
+		if dir == encoding.Ascending {
+			rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+		} else {
+			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := decodeRangeContents(a, valType, r)
+		return d, rkey, err
 	default:
 		return nil, nil, errors.Errorf("unable to decode table key: %s", valType)
 	}
@@ -502,6 +531,12 @@ func EncodeTableValue(
 		return encoding.EncodeIntValue(appendTo, uint32(colID), int64(t.DInt)), nil
 	case *tree.DEnum:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), t.PhysicalRep), nil
+	case *tree.DRange:
+		data, err := encodeRangeContents(scratch[:0], t)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), data), nil
 	default:
 		return nil, errors.Errorf("unable to encode table value: %T", t)
 	}
@@ -676,6 +711,13 @@ func DecodeUntaggedDatum(a *DatumAlloc, t *types.T, buf []byte) (tree.Datum, []b
 			return nil, nil, err
 		}
 		return a.NewDEnum(tree.DEnum{EnumTyp: t, PhysicalRep: phys, LogicalRep: log}), b, nil
+	case types.RangeFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := decodeRangeContents(a, t, data)
+		return d, b, err
 	default:
 		return nil, buf, errors.Errorf("couldn't decode type %s", t)
 	}
@@ -832,6 +874,29 @@ func MarshalColumnValue(col *descpb.ColumnDescriptor, val tree.Datum) (roachpb.V
 			r.SetBytes(v.PhysicalRep)
 			return r, nil
 		}
+	case types.RangeFamily:
+		if v, ok := tree.AsDRange(val); ok {
+			data, err := encodeRangeContents(nil /* appendTo */, v)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(data)
+			return r, nil
+		}
+	case types.TupleFamily:
+		if v, ok := val.(*tree.DTuple); ok {
+			b, err := encodeTuple(v, nil /* appendTo */, encoding.NoColumnID, nil /* scratch */)
+			if err != nil {
+				return r, err
+			}
+			// Strip the value tag, which is added back by SetTuple.
+			_, dataOffset, _, _, err := encoding.DecodeValueTag(b)
+			if err != nil {
+				return r, err
+			}
+			r.SetTuple(b[dataOffset:])
+			return r, nil
+		}
 	default:
 		return r, errors.AssertionFailedf("unsupported column type: %s", col.Type.Family())
 	}
@@ -1011,19 +1076,48 @@ func UnmarshalColumnValue(a *DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return a.NewDEnum(tree.DEnum{EnumTyp: typ, PhysicalRep: phys, LogicalRep: log}), nil
+	case types.RangeFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return decodeRangeContents(a, typ, v)
+	case types.TupleFamily:
+		v, err := value.GetTuple()
+		if err != nil {
+			return nil, err
+		}
+		datum, _, err := decodeTuple(a, typ, v)
+		return datum, err
 	default:
 		return nil, errors.Errorf("unsupported column type: %s", typ.Family())
 	}
 }
 
 // encodeTuple produces the value encoding for a tuple.
+//
+// The elements of a composite type are tagged with the delta of their stable
+// element IDs, in the same way as the columns of a column family, so that
+// values remain decodable after attributes are added to or dropped from the
+// type. The elements of other tuples are not tagged.
 func encodeTuple(t *tree.DTuple, appendTo []byte, colID uint32, scratch []byte) ([]byte, error) {
 	appendTo = encoding.EncodeValueTag(appendTo, colID, encoding.Tuple)
 	appendTo = encoding.EncodeNonsortingUvarint(appendTo, uint64(len(t.D)))
 
+	var elemIDs []uint32
+	if compositeData := t.ResolvedType().TypeMeta.CompositeData; compositeData != nil &&
+		len(compositeData.ElementIDs) == len(t.D) {
+		elemIDs = compositeData.ElementIDs
+	}
 	var err error
-	for _, dd := range t.D {
-		appendTo, err = EncodeTableValue(appendTo, descpb.ColumnID(encoding.NoColumnID), dd, scratch)
+	var lastID uint32
+	for i, dd := range t.D {
+		elemColID := uint32(encoding.NoColumnID)
+		if elemIDs != nil {
+			elemColID = elemIDs[i] - lastID
+			lastID = elemIDs[i]
+		}
+		appendTo, err = EncodeTableValue(appendTo, descpb.ColumnID(elemColID), dd, scratch)
 		if err != nil {
 			return nil, err
 		}
@@ -1034,13 +1128,27 @@ func encodeTuple(t *tree.DTuple, appendTo []byte, colID uint32, scratch []byte)
 // decodeTuple decodes a tuple from its value encoding. It is the
 // counterpart of encodeTuple().
 func decodeTuple(a *DatumAlloc, tupTyp *types.T, b []byte) (tree.Datum, []byte, error) {
-	b, _, _, err := encoding.DecodeNonsortingUvarint(b)
+	b, _, numElems, err := encoding.DecodeNonsortingUvarint(b)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	result := tree.DTuple{
-		D: a.NewDatums(len(tupTyp.TupleContents())),
+	result := *tree.NewDTuple(tupTyp, a.NewDatums(len(tupTyp.TupleContents()))...)
+
+	if compositeData := tupTyp.TypeMeta.CompositeData; compositeData != nil && numElems > 0 {
+		_, _, firstID, _, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return nil, b, err
+		}
+		// Values of composite types encoded without element IDs are decoded
+		// positionally below.
+		if firstID != encoding.NoColumnID {
+			b, err = decodeCompositeElements(a, tupTyp, compositeData.ElementIDs, int(numElems), b, result.D)
+			if err != nil {
+				return nil, b, err
+			}
+			return a.NewDTuple(result), b, nil
+		}
 	}
 
 	var datum tree.Datum
@@ -1054,6 +1162,143 @@ func decodeTuple(a *DatumAlloc, tupTyp *types.T, b []byte) (tree.Datum, []byte,
 	return a.NewDTuple(result), b, nil
 }
 
+// decodeCompositeElements decodes the numElems elements of a composite type
+// value that are tagged with the deltas of their element IDs into result.
+// Elements of attributes that were dropped from the type are skipped, and the
+// attributes that were added to the type after the value was encoded are NULL.
+func decodeCompositeElements(
+	a *DatumAlloc, tupTyp *types.T, elemIDs []uint32, numElems int, b []byte, result tree.Datums,
+) ([]byte, error) {
+	for i := range result {
+		result[i] = tree.DNull
+	}
+	var lastID uint32
+	for n := 0; n < numElems; n++ {
+		_, dataOffset, idDelta, typ, err := encoding.DecodeValueTag(b)
+		if err != nil {
+			return b, err
+		}
+		lastID += idDelta
+		idx := -1
+		for i, id := range elemIDs {
+			if id == lastID {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			// The attribute was dropped from the type.
+			l, err := encoding.PeekValueLengthWithOffsetsAndType(b, dataOffset, typ)
+			if err != nil {
+				return b, err
+			}
+			b = b[l:]
+			continue
+		}
+		result[idx], b, err = DecodeTableValue(a, tupTyp.TupleContents()[idx], b)
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// Markers used by encodeRangeContents. They are chosen so that the encoding of
+// ranges sorts in the same order as tree.DRange.Compare.
+const (
+	rangeEmptyMarker    = 0x00
+	rangeNonEmptyMarker = 0x01
+
+	rangeBoundLowerInfiniteMarker = 0x00
+	rangeBoundFiniteMarker        = 0x01
+	rangeBoundUpperInfiniteMarker = 0x02
+
+	rangeLowerBoundInclusive = 0x00
+	rangeLowerBoundExclusive = 0x01
+	rangeUpperBoundExclusive = 0x00
+	rangeUpperBoundInclusive = 0x01
+)
+
+// encodeRangeContents appends the encoding of a range to appendTo. The
+// encoding is used both in keys and in values, so it sorts in the same order
+// as tree.DRange.Compare: the empty range is first, and all other ranges are
+// sorted by their lower bound and then by their upper bound. Each finite
+// bound is encoded as an ascending key followed by a byte recording whether
+// it is inclusive.
+func encodeRangeContents(appendTo []byte, r *tree.DRange) ([]byte, error) {
+	if r.Empty {
+		return append(appendTo, rangeEmptyMarker), nil
+	}
+	b := append(appendTo, rangeNonEmptyMarker)
+	var err error
+	if r.Lower == tree.DNull {
+		b = append(b, rangeBoundLowerInfiniteMarker)
+	} else {
+		b = append(b, rangeBoundFiniteMarker)
+		if b, err = EncodeTableKey(b, r.Lower, encoding.Ascending); err != nil {
+			return nil, err
+		}
+		if r.LowerInc {
+			b = append(b, rangeLowerBoundInclusive)
+		} else {
+			b = append(b, rangeLowerBoundExclusive)
+		}
+	}
+	if r.Upper == tree.DNull {
+		b = append(b, rangeBoundUpperInfiniteMarker)
+	} else {
+		b = append(b, rangeBoundFiniteMarker)
+		if b, err = EncodeTableKey(b, r.Upper, encoding.Ascending); err != nil {
+			return nil, err
+		}
+		if r.UpperInc {
+			b = append(b, rangeUpperBoundInclusive)
+		} else {
+			b = append(b, rangeUpperBoundExclusive)
+		}
+	}
+	return b, nil
+}
+
+// decodeRangeContents decodes a range of type t encoded by
+// encodeRangeContents.
+func decodeRangeContents(a *DatumAlloc, t *types.T, b []byte) (tree.Datum, error) {
+	if len(b) == 0 {
+		return nil, errors.AssertionFailedf("invalid range encoding (empty)")
+	}
+	if b[0] == rangeEmptyMarker {
+		return tree.NewDEmptyRange(t), nil
+	}
+	b = b[1:]
+	var bounds [2]tree.Datum
+	var inclusive [2]bool
+	for i := range bounds {
+		if len(b) == 0 {
+			return nil, errors.AssertionFailedf("invalid range encoding (missing bound)")
+		}
+		if b[0] != rangeBoundFiniteMarker {
+			bounds[i] = tree.DNull
+			b = b[1:]
+			continue
+		}
+		var err error
+		bounds[i], b, err = DecodeTableKey(a, t.RangeContents(), b[1:], encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) == 0 {
+			return nil, errors.AssertionFailedf("invalid range encoding (missing inclusivity)")
+		}
+		if i == 0 {
+			inclusive[i] = b[0] == rangeLowerBoundInclusive
+		} else {
+			inclusive[i] = b[0] == rangeUpperBoundInclusive
+		}
+		b = b[1:]
+	}
+	return tree.NewDRange(nil /* ctx */, t, bounds[0], bounds[1], inclusive[0], inclusive[1])
+}
+
 // encodeArrayKey generates an ordered key encoding of an array.
 // The encoding format for an array [a, b] is as follows:
 // [arrayMarker, enc(a), enc(b), terminator].
@@ -1314,7 +1559,8 @@ func datumTypeToArrayElementEncodingType(t *types.T) (encoding.Type, error) {
 		return encoding.Geo, nil
 	case types.DecimalFamily:
 		return encoding.Decimal, nil
-	case types.BytesFamily, types.StringFamily, types.CollatedStringFamily, types.EnumFamily:
+	case types.BytesFamily, types.StringFamily, types.CollatedStringFamily, types.EnumFamily,
+		types.RangeFamily:
 		return encoding.Bytes, nil
 	case types.TimestampFamily, types.TimestampTZFamily:
 		return encoding.Time, nil
@@ -1406,6 +1652,12 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 		return encodeArrayElement(b, t.Wrapped)
 	case *tree.DEnum:
 		return encoding.EncodeUntaggedBytesValue(b, t.PhysicalRep), nil
+	case *tree.DRange:
+		data, err := encodeRangeContents(nil /* appendTo */, t)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeUntaggedBytesValue(b, data), nil
 	default:
 		return nil, errors.Errorf("don't know how to encode %s (%T)", d, d)
 	}
@@ -500,10 +500,7 @@ func (r EncDatumRow) Compare(
 			return 0, err
 		}
 		if cmp != 0 {
-			if c.Direction == encoding.Descending {
-				cmp = -cmp
-			}
-			return cmp, nil
+			return c.CompareWithNulls(cmp, r[c.ColIdx].IsNull(), rhs[c.ColIdx].IsNull()), nil
 		}
 	}
 	return 0, nil
@@ -523,10 +520,7 @@ func (r EncDatumRow) CompareToDatums(
 		}
 		cmp := r[c.ColIdx].Datum.Compare(evalCtx, rhs[c.ColIdx])
 		if cmp != 0 {
-			if c.Direction == encoding.Descending {
-				cmp = -cmp
-			}
-			return cmp, nil
+			return c.CompareWithNulls(cmp, r[c.ColIdx].Datum == tree.DNull, rhs[c.ColIdx] == tree.DNull), nil
 		}
 	}
 	return 0, nil
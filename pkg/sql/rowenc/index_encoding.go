@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/unique"
 	"github.com/cockroachdb/errors"
 )
@@ -136,6 +137,7 @@ func EncodePartialIndexKey(
 	key = growKey(keyPrefix, len(keyPrefix)+3*len(index.Interleave.Ancestors)+2*len(values))
 
 	dirs := directions(index.ColumnDirections)
+	nulls := nullsOrders(index.ColumnNullsReversed)
 
 	if len(index.Interleave.Ancestors) > 0 {
 		for i, ancestor := range index.Interleave.Ancestors {
@@ -151,7 +153,7 @@ func EncodePartialIndexKey(
 				partial = true
 			}
 			var n bool
-			key, n, err = EncodeColumns(colIDs[:length], dirs[:length], colMap, values, key)
+			key, n, err = EncodeColumns(colIDs[:length], dirs[:length], nulls, colMap, values, key)
 			if err != nil {
 				return nil, false, err
 			}
@@ -162,7 +164,7 @@ func EncodePartialIndexKey(
 				// that results in a more specific key.
 				return key, containsNull, nil
 			}
-			colIDs, dirs = colIDs[length:], dirs[length:]
+			colIDs, dirs, nulls = colIDs[length:], dirs[length:], nulls.skip(length)
 			// Each ancestor is separated by an interleaved
 			// sentinel (0xfe).
 			key = encoding.EncodeInterleavedSentinel(key)
@@ -172,13 +174,15 @@ func EncodePartialIndexKey(
 	}
 
 	var n bool
-	key, n, err = EncodeColumns(colIDs, dirs, colMap, values, key)
+	key, n, err = EncodeColumns(colIDs, dirs, nulls, colMap, values, key)
 	if err != nil {
 		return nil, false, err
 	}
 	containsNull = containsNull || n
 
-	key, n, err = EncodeColumns(extraColIDs, nil /* directions */, colMap, values, key)
+	key, n, err = EncodeColumns(
+		extraColIDs, nil /* directions */, nil /* nulls */, colMap, values, key,
+	)
 	if err != nil {
 		return nil, false, err
 	}
@@ -195,6 +199,22 @@ func (d directions) get(i int) (encoding.Direction, error) {
 	return encoding.Ascending, nil
 }
 
+// nullsOrders indicates, for each column, whether NULLs sort opposite to the
+// default for the column's direction. It is either empty or parallels the
+// columns being encoded.
+type nullsOrders []bool
+
+func (n nullsOrders) reversed(i int) bool {
+	return i < len(n) && n[i]
+}
+
+func (n nullsOrders) skip(i int) nullsOrders {
+	if i >= len(n) {
+		return nil
+	}
+	return n[i:]
+}
+
 // MakeSpanFromEncDatums creates a minimal index key span on the input
 // values. A minimal index key span is a span that includes the fewest possible
 // keys after the start key generated by the input values.
@@ -430,6 +450,7 @@ func makeKeyFromEncDatums(
 	// so make it bigger from the get-go.
 	key := make(roachpb.Key, len(keyPrefix), len(keyPrefix)*2)
 	copy(key, keyPrefix)
+	nulls := nullsOrders(index.ColumnNullsReversed)
 
 	if len(index.Interleave.Ancestors) > 0 {
 		for i, ancestor := range index.Interleave.Ancestors {
@@ -448,7 +469,9 @@ func makeKeyFromEncDatums(
 				err error
 				n   bool
 			)
-			key, n, err = appendEncDatumsToKey(key, types[:length], values[:length], dirs[:length], alloc)
+			key, n, err = appendEncDatumsToKey(
+				key, types[:length], values[:length], dirs[:length], nulls, alloc,
+			)
 			if err != nil {
 				return nil, false, false, err
 			}
@@ -458,7 +481,7 @@ func makeKeyFromEncDatums(
 				// left in the current interleave.
 				return key, false, false, nil
 			}
-			types, values, dirs = types[length:], values[length:], dirs[length:]
+			types, values, dirs, nulls = types[length:], values[length:], dirs[length:], nulls.skip(length)
 
 			// Each ancestor is separated by an interleaved
 			// sentinel (0xfe).
@@ -471,7 +494,7 @@ func makeKeyFromEncDatums(
 		err error
 		n   bool
 	)
-	key, n, err = appendEncDatumsToKey(key, types, values, dirs, alloc)
+	key, n, err = appendEncDatumsToKey(key, types, values, dirs, nulls, alloc)
 	if err != nil {
 		return key, false, false, err
 	}
@@ -499,18 +522,23 @@ func appendEncDatumsToKey(
 	types []*types.T,
 	values EncDatumRow,
 	dirs []descpb.IndexDescriptor_Direction,
+	nulls nullsOrders,
 	alloc *DatumAlloc,
 ) (_ roachpb.Key, containsNull bool, _ error) {
 	for i, val := range values {
-		encoding := descpb.DatumEncoding_ASCENDING_KEY
+		enc, dir := descpb.DatumEncoding_ASCENDING_KEY, encoding.Ascending
 		if dirs[i] == descpb.IndexDescriptor_DESC {
-			encoding = descpb.DatumEncoding_DESCENDING_KEY
+			enc, dir = descpb.DatumEncoding_DESCENDING_KEY, encoding.Descending
 		}
 		if val.IsNull() {
 			containsNull = true
+			if nulls.reversed(i) {
+				key = EncodeNullTableKey(key, dir, true /* nullsReversed */)
+				continue
+			}
 		}
 		var err error
-		key, err = val.Encode(types[i], alloc, encoding, key)
+		key, err = val.Encode(types[i], alloc, enc, key)
 		if err != nil {
 			return nil, false, err
 		}
@@ -721,6 +749,10 @@ func DecodeKeyVals(
 		}
 		if vals[j].IsNull() {
 			foundNull = true
+			// The NULL marker depends on the NULLs order of the column, so we
+			// don't keep the encoded NULL around; otherwise, it could compare
+			// or hash differently from NULLs encoded elsewhere.
+			vals[j] = DatumToEncDatum(types[j], tree.DNull)
 		}
 	}
 	return key, foundNull, nil
@@ -764,12 +796,13 @@ func EncodeInvertedIndexKeys(
 		// is encoded below this block.
 		colIDs := index.ColumnIDs[:numColumns-1]
 		dirs := directions(index.ColumnDirections)
+		nulls := nullsOrders(index.ColumnNullsReversed)
 
 		// Double the size of the key to make the imminent appends more
 		// efficient.
 		keyPrefix = growKey(keyPrefix, len(keyPrefix))
 
-		keyPrefix, _, err = EncodeColumns(colIDs, dirs, colMap, values, keyPrefix)
+		keyPrefix, _, err = EncodeColumns(colIDs, dirs, nulls, colMap, values, keyPrefix)
 		if err != nil {
 			return nil, err
 		}
@@ -784,6 +817,9 @@ func EncodeInvertedIndexKeys(
 	if !geoindex.IsEmptyConfig(&index.GeoConfig) {
 		return EncodeGeoInvertedIndexTableKeys(val, keyPrefix, index)
 	}
+	if index.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+		return EncodeTrigramInvertedIndexTableKeys(val, keyPrefix), nil
+	}
 	return EncodeInvertedIndexTableKeys(val, keyPrefix, index.Version)
 }
 
@@ -914,6 +950,26 @@ func encodeArrayInvertedIndexTableKeys(
 	return outKeys, nil
 }
 
+// EncodeTrigramInvertedIndexTableKeys is the equivalent of
+// EncodeInvertedIndexTableKeys for trigram indexes. It returns one inverted
+// index key per distinct trigram of the input string, each prefixed by inKey.
+// Strings without any trigrams (for example, the empty string) produce no
+// keys.
+func EncodeTrigramInvertedIndexTableKeys(val tree.Datum, inKey []byte) [][]byte {
+	if val == tree.DNull {
+		return nil
+	}
+	s := string(tree.MustBeDString(tree.UnwrapDatum(nil, val)))
+	trigrams := trigram.MakeTrigrams(s, true /* pad */)
+	outKeys := make([][]byte, len(trigrams))
+	for i := range trigrams {
+		outKey := make([]byte, len(inKey))
+		copy(outKey, inKey)
+		outKeys[i] = encoding.EncodeStringAscending(outKey, trigrams[i])
+	}
+	return outKeys
+}
+
 // encodeContainingArrayInvertedIndexSpans returns the spans that must be
 // scanned in the inverted index to evaluate a contains (@>) predicate with
 // the given array, one slice of spans per entry in the array. The input
@@ -1127,7 +1183,7 @@ func EncodeSecondaryIndex(
 
 	// Add the extra columns - they are encoded in ascending order which is done
 	// by passing nil for the encoding directions.
-	extraKey, _, err := EncodeColumns(secondaryIndex.ExtraColumnIDs, nil,
+	extraKey, _, err := EncodeColumns(secondaryIndex.ExtraColumnIDs, nil, nil,
 		colMap, values, nil)
 	if err != nil {
 		return []IndexEntry{}, err
@@ -1802,12 +1858,13 @@ func AdjustEndKeyForInterleave(
 	return end[:firstNTokenLen+1], nil
 }
 
-// EncodeColumns is a version of EncodePartialIndexKey that takes ColumnIDs and
-// directions explicitly. WARNING: unlike EncodePartialIndexKey, EncodeColumns
-// appends directly to keyPrefix.
+// EncodeColumns is a version of EncodePartialIndexKey that takes ColumnIDs,
+// directions and NULLs orders explicitly. WARNING: unlike
+// EncodePartialIndexKey, EncodeColumns appends directly to keyPrefix.
 func EncodeColumns(
 	columnIDs []descpb.ColumnID,
 	directions directions,
+	nulls nullsOrders,
 	colMap catalog.TableColMap,
 	values []tree.Datum,
 	keyPrefix []byte,
@@ -1815,15 +1872,20 @@ func EncodeColumns(
 	key = keyPrefix
 	for colIdx, id := range columnIDs {
 		val := findColumnValue(id, colMap, values)
-		if val == tree.DNull {
-			containsNull = true
-		}
 
 		dir, err := directions.get(colIdx)
 		if err != nil {
 			return nil, containsNull, err
 		}
 
+		if val == tree.DNull {
+			containsNull = true
+			if nulls.reversed(colIdx) {
+				key = EncodeNullTableKey(key, dir, true /* nullsReversed */)
+				continue
+			}
+		}
+
 		if key, err = EncodeTableKey(key, val, dir); err != nil {
 			return nil, containsNull, err
 		}
@@ -254,6 +254,24 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			panic(err)
 		}
 		return d
+	case types.RangeFamily:
+		if rng.Intn(10) == 0 {
+			return tree.NewDEmptyRange(typ)
+		}
+		// A NULL bound makes the range unbounded on that side.
+		lower := RandDatumWithNullChance(rng, typ.RangeContents(), 5)
+		upper := RandDatumWithNullChance(rng, typ.RangeContents(), 5)
+		lowerInc, upperInc := rng.Intn(2) == 0, rng.Intn(2) == 0
+		d, err := tree.NewDRange(nil /* ctx */, typ, lower, upper, lowerInc, upperInc)
+		if err != nil {
+			// The bounds may be out of order, or the upper bound may not have
+			// a successor in the canonical form.
+			d, err = tree.NewDRange(nil /* ctx */, typ, upper, lower, lowerInc, false /* upperInc */)
+			if err != nil {
+				return tree.NewDEmptyRange(typ)
+			}
+		}
+		return d
 	default:
 		panic(errors.AssertionFailedf("invalid type %v", typ.DebugString()))
 	}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/axiomhq/hyperloglog"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -340,9 +341,11 @@ func (s *samplerProcessor) mainLoop(ctx context.Context) (earlyExit bool, err er
 				// index entries.
 				continue
 			}
-			switch s.outTypes[col].Family() {
-			case types.GeographyFamily, types.GeometryFamily:
+			switch family := s.outTypes[col].Family(); {
+			case family == types.GeographyFamily || family == types.GeometryFamily:
 				invKeys, err = rowenc.EncodeGeoInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index)
+			case index.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM:
+				invKeys = rowenc.EncodeTrigramInvertedIndexTableKeys(row[col].Datum, nil /* inKey */)
 			default:
 				invKeys, err = rowenc.EncodeInvertedIndexTableKeys(row[col].Datum, nil /* inKey */, index.Version)
 			}
@@ -595,6 +595,7 @@ func (z *zigzagJoiner) produceInvertedIndexKey(
 	keyBytes, _, err := rowenc.EncodeColumns(
 		info.index.ExtraColumnIDs[:len(datums)-1],
 		info.indexDirs[1:],
+		nil, /* nulls */
 		colMap,
 		decodedDatums,
 		keys[0],
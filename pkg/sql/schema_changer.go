@@ -613,7 +613,7 @@ func (sc *SchemaChanger) exec(ctx context.Context) error {
 		}
 		// Some descriptors should be deleted if they are in the DROP state.
 		switch desc.(type) {
-		case catalog.SchemaDescriptor, catalog.DatabaseDescriptor:
+		case catalog.SchemaDescriptor, catalog.DatabaseDescriptor, catalog.FunctionDescriptor:
 			if desc.Dropped() {
 				if err := sc.execCfg.DB.Del(ctx, catalogkeys.MakeDescMetadataKey(sc.execCfg.Codec, desc.GetID())); err != nil {
 					return err
@@ -1732,6 +1732,23 @@ func (sc *SchemaChanger) maybeDropValidatingConstraint(
 				constraint.ForeignKey.Name,
 			)
 		}
+	case descpb.ConstraintToUpdate_EXCLUSION:
+		if constraint.ExclusionConstraint.Validity == descpb.ConstraintValidity_Unvalidated {
+			return nil
+		}
+		for i := range desc.ExclusionConstraints {
+			if desc.ExclusionConstraints[i].Name == constraint.ExclusionConstraint.Name {
+				desc.ExclusionConstraints = append(desc.ExclusionConstraints[:i], desc.ExclusionConstraints[i+1:]...)
+				return nil
+			}
+		}
+		if log.V(2) {
+			log.Infof(
+				ctx,
+				"attempted to drop constraint %s, but it hadn't been added to the table descriptor yet",
+				constraint.ExclusionConstraint.Name,
+			)
+		}
 	default:
 		return errors.AssertionFailedf("unsupported constraint type: %d", errors.Safe(constraint.ConstraintType))
 	}
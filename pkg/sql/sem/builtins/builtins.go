@@ -67,6 +67,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/unaccent"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -107,6 +108,7 @@ const (
 	categoryJSON                = "JSONB"
 	categoryMultiRegion         = "Multi-region"
 	categoryMultiTenancy        = "Multi-tenancy"
+	categoryRange               = "Range"
 	categorySequences           = "Sequence"
 	categorySpatial             = "Spatial"
 	categoryString              = "String and byte"
@@ -3008,11 +3010,62 @@ may increase either contention or retry errors, or both.`,
 	"dmetaphone_alt":         makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 56820, Category: categoryFuzzyStringMatching}),
 
 	// Trigram functions.
-	"similarity":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_trgm":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"word_similarity":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
+	// See https://www.postgresql.org/docs/current/pgtrgm.html.
+	"similarity": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				l, r := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+				return tree.NewDFloat(tree.DFloat(trigram.Similarity(l, r))), nil
+			},
+			Info: "Returns a number that indicates how similar the two arguments are, " +
+				"from 0 (completely dissimilar) to 1 (identical), based on the trigrams they share.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"show_trgm": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigram.MakeTrigrams(string(tree.MustBeDString(args[0])), true /* pad */) {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info:       "Returns an array of all the trigrams in the given string.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"word_similarity": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				l, r := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+				return tree.NewDFloat(tree.DFloat(trigram.WordSimilarity(l, r))), nil
+			},
+			Info: "Returns the greatest similarity between the set of trigrams in the first " +
+				"string and any continuous extent of an ordered set of trigrams in the second string.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
 	"strict_word_similarity": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_limit":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
+	"show_limit": makeBuiltin(tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(evalCtx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return tree.NewDFloat(tree.DFloat(evalCtx.SessionData.TrigramSimilarityThreshold)), nil
+			},
+			Info:       "Returns the current similarity threshold used by the % operator.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
 	"set_limit":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 
 	// JSON functions.
@@ -4218,6 +4271,15 @@ may increase either contention or retry errors, or both.`,
 			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
 			Volatility: tree.VolatilityStable,
 		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"val", types.String}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return stringNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
+		},
 		tree.Overload{
 			Types: tree.ArgTypes{
 				{"val", types.Jsonb},
@@ -4246,6 +4308,20 @@ may increase either contention or retry errors, or both.`,
 			},
 			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
 			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.String},
+				{"version", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// The version argument is ignored for trigram inverted indexes, since
+				// all versions of them include the same entries.
+				return stringNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
 		}),
 
 	// Returns true iff the current user has admin role.
@@ -6888,6 +6964,14 @@ func jsonNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datu
 	return tree.NewDInt(tree.DInt(n)), nil
 }
 
+func stringNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
+	if val == tree.DNull {
+		return tree.DZero, nil
+	}
+	n := len(trigram.MakeTrigrams(string(tree.MustBeDString(val)), true /* pad */))
+	return tree.NewDInt(tree.DInt(n)), nil
+}
+
 func arrayNumInvertedIndexEntries(
 	ctx *tree.EvalContext, val, version tree.Datum,
 ) (tree.Datum, error) {
@@ -0,0 +1,238 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+func initRangeBuiltins() {
+	// Add all rangeBuiltins to the Builtins map after a sanity check.
+	for k, v := range rangeBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		builtins[k] = v
+	}
+	// lower and upper are also string functions, so the range overloads are
+	// added to the existing definitions instead.
+	for k, overloads := range rangeBoundOverloads {
+		b, exists := builtins[k]
+		if !exists {
+			panic("missing builtin: " + k)
+		}
+		b.overloads = append(b.overloads, overloads...)
+		builtins[k] = b
+	}
+}
+
+// rangeBoundOverloads contains the range overloads of builtins which are
+// defined elsewhere, indexed by name.
+var rangeBoundOverloads = map[string][]tree.Overload{
+	"lower": makeRangeOverloads(
+		func(t *types.T) *types.T { return t.RangeContents() },
+		func(r *tree.DRange) tree.Datum { return r.Lower },
+		"Returns the lower bound of `range`, or NULL if the range is empty or has no lower bound.",
+	),
+	"upper": makeRangeOverloads(
+		func(t *types.T) *types.T { return t.RangeContents() },
+		func(r *tree.DRange) tree.Datum { return r.Upper },
+		"Returns the upper bound of `range`, or NULL if the range is empty or has no upper bound.",
+	),
+}
+
+// rangeBuiltins contains the built-in functions operating on the built-in
+// range types, indexed by name.
+var rangeBuiltins = map[string]builtinDefinition{
+	"isempty": makeRangeBuiltin(
+		func(*types.T) *types.T { return types.Bool },
+		func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.Empty)) },
+		"Returns whether `range` is empty.",
+	),
+	"lower_inc": makeRangeBuiltin(
+		func(*types.T) *types.T { return types.Bool },
+		func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.LowerInc)) },
+		"Returns whether the lower bound of `range` is inclusive.",
+	),
+	"upper_inc": makeRangeBuiltin(
+		func(*types.T) *types.T { return types.Bool },
+		func(r *tree.DRange) tree.Datum { return tree.MakeDBool(tree.DBool(r.UpperInc)) },
+		"Returns whether the upper bound of `range` is inclusive.",
+	),
+	"lower_inf": makeRangeBuiltin(
+		func(*types.T) *types.T { return types.Bool },
+		func(r *tree.DRange) tree.Datum {
+			return tree.MakeDBool(tree.DBool(!r.Empty && r.Lower == tree.DNull))
+		},
+		"Returns whether `range` has no lower bound.",
+	),
+	"upper_inf": makeRangeBuiltin(
+		func(*types.T) *types.T { return types.Bool },
+		func(r *tree.DRange) tree.Datum {
+			return tree.MakeDBool(tree.DBool(!r.Empty && r.Upper == tree.DNull))
+		},
+		"Returns whether `range` has no upper bound.",
+	),
+	"range_merge": makeRangeMergeBuiltin(),
+	"int4range":   makeRangeConstructorBuiltin(types.Int4Range),
+	"int8range":   makeRangeConstructorBuiltin(types.Int8Range),
+	"tsrange":     makeRangeConstructorBuiltin(types.TSRange),
+	"tstzrange":   makeRangeConstructorBuiltin(types.TSTZRange),
+	"daterange":   makeRangeConstructorBuiltin(types.DateRange),
+	"range_adjacent": makeRangeComparisonBuiltin(
+		(*tree.DRange).Adjacent,
+		"Returns whether `left` and `right` are adjacent. This is the `-|-` operator.",
+	),
+	"range_overleft": makeRangeComparisonBuiltin(
+		(*tree.DRange).DoesNotExtendRightOf,
+		"Returns whether `left` does not extend to the right of `right`. This is the `&<` operator.",
+	),
+	"range_overright": makeRangeComparisonBuiltin(
+		(*tree.DRange).DoesNotExtendLeftOf,
+		"Returns whether `left` does not extend to the left of `right`. This is the `&>` operator.",
+	),
+}
+
+// makeRangeBuiltin returns a builtin with one overload for each of the
+// built-in range types, which computes a property of its argument.
+func makeRangeBuiltin(
+	retType func(*types.T) *types.T, fn func(r *tree.DRange) tree.Datum, info string,
+) builtinDefinition {
+	return makeBuiltin(
+		tree.FunctionProperties{Category: categoryRange},
+		makeRangeOverloads(retType, fn, info)...,
+	)
+}
+
+// makeRangeOverloads returns one overload for each of the built-in range
+// types, which computes a property of its argument. The return type of each
+// overload is determined by retType.
+func makeRangeOverloads(
+	retType func(*types.T) *types.T, fn func(r *tree.DRange) tree.Datum, info string,
+) []tree.Overload {
+	overloads := make([]tree.Overload, 0, len(types.RangeTypes))
+	for _, typ := range types.RangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ArgTypes{{"range", typ}},
+			ReturnType: tree.FixedReturnType(retType(typ)),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(tree.MustBeDRange(args[0])), nil
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		})
+	}
+	return overloads
+}
+
+// makeRangeComparisonBuiltin returns a builtin with one overload for each of
+// the built-in range types, which compares two ranges of that type.
+func makeRangeComparisonBuiltin(
+	fn func(lhs *tree.DRange, ctx *tree.EvalContext, rhs *tree.DRange) bool, info string,
+) builtinDefinition {
+	overloads := make([]tree.Overload, 0, len(types.RangeTypes))
+	for _, typ := range types.RangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ArgTypes{{"left", typ}, {"right", typ}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				ret := fn(tree.MustBeDRange(args[0]), ctx, tree.MustBeDRange(args[1]))
+				return tree.MakeDBool(tree.DBool(ret)), nil
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		})
+	}
+	return makeBuiltin(tree.FunctionProperties{Category: categoryRange}, overloads...)
+}
+
+func makeRangeMergeBuiltin() builtinDefinition {
+	overloads := make([]tree.Overload, 0, len(types.RangeTypes))
+	for _, typ := range types.RangeTypes {
+		overloads = append(overloads, tree.Overload{
+			Types:      tree.ArgTypes{{"left", typ}, {"right", typ}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.MustBeDRange(args[0]).Merge(ctx, tree.MustBeDRange(args[1]))
+			},
+			Info:       "Returns the smallest range which includes both `left` and `right`.",
+			Volatility: tree.VolatilityImmutable,
+		})
+	}
+	return makeBuiltin(tree.FunctionProperties{Category: categoryRange}, overloads...)
+}
+
+// makeRangeConstructorBuiltin returns the constructor function of the given
+// range type. A NULL bound makes the range unbounded on that side.
+func makeRangeConstructorBuiltin(typ *types.T) builtinDefinition {
+	subtype := typ.RangeContents()
+	construct := func(ctx *tree.EvalContext, lower, upper tree.Datum, flags string) (tree.Datum, error) {
+		if typ.Oid() == oid.T_int4range {
+			for _, d := range []tree.Datum{lower, upper} {
+				if v, ok := tree.AsDInt(d); ok && (v < math.MinInt32 || v > math.MaxInt32) {
+					return nil, pgerror.New(pgcode.NumericValueOutOfRange, "integer out of range")
+				}
+			}
+		}
+		lowerInc, upperInc, err := parseRangeBoundFlags(flags)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDRange(ctx, typ, lower, upper, lowerInc, upperInc)
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{Category: categoryRange, NullableArgs: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lower", subtype}, {"upper", subtype}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return construct(ctx, args[0], args[1], "[)")
+			},
+			Info: "Constructs a range with the given bounds. The lower bound is inclusive " +
+				"and the upper bound is exclusive. A NULL bound makes the range unbounded on that side.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lower", subtype}, {"upper", subtype}, {"bounds", types.String}},
+			ReturnType: tree.FixedReturnType(typ),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[2] == tree.DNull {
+					return nil, pgerror.New(pgcode.DataException,
+						"range constructor flags argument must not be null")
+				}
+				return construct(ctx, args[0], args[1], string(tree.MustBeDString(args[2])))
+			},
+			Info: "Constructs a range with the given bounds. `bounds` is one of `[]`, `[)`, " +
+				"`(]` or `()`, and determines whether each bound is inclusive. A NULL bound makes " +
+				"the range unbounded on that side.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// parseRangeBoundFlags parses the bounds argument of a range constructor.
+func parseRangeBoundFlags(flags string) (lowerInc, upperInc bool, _ error) {
+	if len(flags) != 2 || (flags[0] != '[' && flags[0] != '(') ||
+		(flags[1] != ']' && flags[1] != ')') {
+		return false, false, errors.WithHint(
+			pgerror.New(pgcode.Syntax, "invalid range bound flags"),
+			`Valid values are "[]", "[)", "(]", and "()".`,
+		)
+	}
+	return flags[0] == '[', flags[1] == ']', nil
+}
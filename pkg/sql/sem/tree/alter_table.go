@@ -68,6 +68,7 @@ func (*AlterTableDropColumn) alterTableCmd()         {}
 func (*AlterTableDropConstraint) alterTableCmd()     {}
 func (*AlterTableDropNotNull) alterTableCmd()        {}
 func (*AlterTableDropStored) alterTableCmd()         {}
+func (*AlterTableEnableTrigger) alterTableCmd()      {}
 func (*AlterTableSetNotNull) alterTableCmd()         {}
 func (*AlterTableRenameColumn) alterTableCmd()       {}
 func (*AlterTableRenameConstraint) alterTableCmd()   {}
@@ -84,6 +85,7 @@ var _ AlterTableCmd = &AlterTableDropColumn{}
 var _ AlterTableCmd = &AlterTableDropConstraint{}
 var _ AlterTableCmd = &AlterTableDropNotNull{}
 var _ AlterTableCmd = &AlterTableDropStored{}
+var _ AlterTableCmd = &AlterTableEnableTrigger{}
 var _ AlterTableCmd = &AlterTableSetNotNull{}
 var _ AlterTableCmd = &AlterTableRenameColumn{}
 var _ AlterTableCmd = &AlterTableRenameConstraint{}
@@ -340,6 +342,37 @@ func (node *AlterTableValidateConstraint) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Constraint)
 }
 
+// AlterTableEnableTrigger represents an ENABLE TRIGGER or DISABLE TRIGGER
+// command.
+type AlterTableEnableTrigger struct {
+	// Trigger is the name of the trigger, or empty if All is set.
+	Trigger Name
+	All     bool
+	Enable  bool
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableEnableTrigger) TelemetryCounter() telemetry.Counter {
+	if node.Enable {
+		return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "enable_trigger")
+	}
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "disable_trigger")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableEnableTrigger) Format(ctx *FmtCtx) {
+	if node.Enable {
+		ctx.WriteString(" ENABLE TRIGGER ")
+	} else {
+		ctx.WriteString(" DISABLE TRIGGER ")
+	}
+	if node.All {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Trigger)
+	}
+}
+
 // AlterTableRenameColumn represents an ALTER TABLE RENAME [COLUMN] command.
 type AlterTableRenameColumn struct {
 	Column  Name
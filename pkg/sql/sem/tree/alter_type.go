@@ -38,17 +38,21 @@ type AlterTypeCmd interface {
 	TelemetryCounter() telemetry.Counter
 }
 
-func (*AlterTypeAddValue) alterTypeCmd()    {}
-func (*AlterTypeRenameValue) alterTypeCmd() {}
-func (*AlterTypeRename) alterTypeCmd()      {}
-func (*AlterTypeSetSchema) alterTypeCmd()   {}
-func (*AlterTypeOwner) alterTypeCmd()       {}
+func (*AlterTypeAddValue) alterTypeCmd()      {}
+func (*AlterTypeRenameValue) alterTypeCmd()   {}
+func (*AlterTypeRename) alterTypeCmd()        {}
+func (*AlterTypeSetSchema) alterTypeCmd()     {}
+func (*AlterTypeOwner) alterTypeCmd()         {}
+func (*AlterTypeAddAttribute) alterTypeCmd()  {}
+func (*AlterTypeDropAttribute) alterTypeCmd() {}
 
 var _ AlterTypeCmd = &AlterTypeAddValue{}
 var _ AlterTypeCmd = &AlterTypeRenameValue{}
 var _ AlterTypeCmd = &AlterTypeRename{}
 var _ AlterTypeCmd = &AlterTypeSetSchema{}
 var _ AlterTypeCmd = &AlterTypeOwner{}
+var _ AlterTypeCmd = &AlterTypeAddAttribute{}
+var _ AlterTypeCmd = &AlterTypeDropAttribute{}
 
 // AlterTypeAddValue represents an ALTER TYPE ADD VALUE command.
 type AlterTypeAddValue struct {
@@ -154,3 +158,50 @@ func (node *AlterTypeOwner) Format(ctx *FmtCtx) {
 func (node *AlterTypeOwner) TelemetryCounter() telemetry.Counter {
 	return sqltelemetry.SchemaChangeAlterCounterWithExtra("type", "owner")
 }
+
+// AlterTypeAddAttribute represents an ALTER TYPE ADD ATTRIBUTE command.
+type AlterTypeAddAttribute struct {
+	Name         Name
+	Type         ResolvableTypeReference
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAddAttribute) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ADD ATTRIBUTE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.FormatTypeReference(node.Type)
+	if node.DropBehavior != DropDefault {
+		ctx.Printf(" %s", node.DropBehavior)
+	}
+}
+
+// TelemetryCounter implements the AlterTypeCmd interface.
+func (node *AlterTypeAddAttribute) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("type", "add_attribute")
+}
+
+// AlterTypeDropAttribute represents an ALTER TYPE DROP ATTRIBUTE command.
+type AlterTypeDropAttribute struct {
+	Name         Name
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeDropAttribute) Format(ctx *FmtCtx) {
+	ctx.WriteString(" DROP ATTRIBUTE ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	if node.DropBehavior != DropDefault {
+		ctx.Printf(" %s", node.DropBehavior)
+	}
+}
+
+// TelemetryCounter implements the AlterTypeCmd interface.
+func (node *AlterTypeDropAttribute) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("type", "drop_attribute")
+}
@@ -177,6 +177,7 @@ var validCasts = []castInfo{
 	{from: types.INetFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.RangeFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 
 	// Casts to CollatedStringFamily.
 	{from: types.UnknownFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
@@ -204,6 +205,7 @@ var validCasts = []castInfo{
 	{from: types.INetFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.RangeFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 
 	// Casts to BytesFamily.
 	{from: types.UnknownFamily, to: types.BytesFamily, volatility: VolatilityImmutable},
@@ -308,6 +310,13 @@ var validCasts = []castInfo{
 
 	// Casts to TupleFamily.
 	{from: types.UnknownFamily, to: types.TupleFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TupleFamily, volatility: VolatilityStable},
+
+	// Casts to RangeFamily.
+	{from: types.UnknownFamily, to: types.RangeFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.RangeFamily, volatility: VolatilityStable},
+	{from: types.CollatedStringFamily, to: types.RangeFamily, volatility: VolatilityStable},
+	{from: types.RangeFamily, to: types.RangeFamily, volatility: VolatilityImmutable},
 }
 
 type castsMapKey struct {
@@ -367,6 +376,10 @@ func LookupCastVolatility(from, to *types.T) (_ Volatility, ok bool) {
 		}
 		return maxVolatility, true
 	}
+	// Ranges can only be cast to ranges over the same subtype.
+	if fromFamily == types.RangeFamily && toFamily == types.RangeFamily && from.Oid() != to.Oid() {
+		return 0, false
+	}
 	cast := lookupCast(fromFamily, toFamily)
 	if cast == nil {
 		return 0, false
@@ -510,6 +523,21 @@ func AdjustValueToType(typ *types.T, inVal Datum) (outVal Datum, err error) {
 				return outArr, nil
 			}
 		}
+	case types.TupleFamily:
+		// Values of composite types must carry the composite type, since its
+		// metadata is needed to encode them.
+		if inTup, ok := inVal.(*DTuple); ok && typ.UserDefined() &&
+			len(inTup.D) == len(typ.TupleContents()) {
+			outTup := NewDTupleWithLen(typ, len(inTup.D))
+			for i, inElem := range inTup.D {
+				outElem, err := AdjustValueToType(typ.TupleContents()[i], inElem)
+				if err != nil {
+					return nil, err
+				}
+				outTup.D[i] = outElem
+			}
+			return outTup, nil
+		}
 	case types.TimeFamily:
 		if in, ok := inVal.(*DTime); ok {
 			return in.Round(TimeFamilyPrecisionToRoundDuration(typ.Precision())), nil
@@ -822,7 +850,7 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 				ctx.SessionData.DataConversionConfig.GetFloatPrec(), 64)
 		case *DBool, *DInt, *DDecimal:
 			s = d.String()
-		case *DTimestamp, *DDate, *DTime, *DTimeTZ, *DGeography, *DGeometry, *DBox2D:
+		case *DTimestamp, *DDate, *DTime, *DTimeTZ, *DGeography, *DGeometry, *DBox2D, *DRange:
 			s = AsStringWithFlags(d, FmtBareStrings)
 		case *DTimestampTZ:
 			// Convert to context timezone for correct display.
@@ -1244,6 +1272,42 @@ func performCastWithoutPrecisionTruncation(ctx *EvalContext, d Datum, t *types.T
 			}
 			return dcast, nil
 		}
+	case types.TupleFamily:
+		switch v := d.(type) {
+		case *DString:
+			res, _, err := ParseDTupleFromString(ctx, string(*v), t)
+			return res, err
+		case *DTuple:
+			if types.IsWildcardTupleType(t) || len(v.D) != len(t.TupleContents()) {
+				break
+			}
+			dcast := NewDTupleWithLen(t, len(v.D))
+			for i, e := range v.D {
+				ecast := DNull
+				if e != DNull {
+					var err error
+					ecast, err = PerformCast(ctx, e, t.TupleContents()[i])
+					if err != nil {
+						return nil, err
+					}
+				}
+				dcast.D[i] = ecast
+			}
+			return dcast, nil
+		}
+	case types.RangeFamily:
+		switch v := d.(type) {
+		case *DString:
+			res, _, err := ParseDRangeFromString(ctx, string(*v), t)
+			return res, err
+		case *DCollatedString:
+			res, _, err := ParseDRangeFromString(ctx, v.Contents, t)
+			return res, err
+		case *DRange:
+			if v.ResolvedType().Oid() == t.Oid() {
+				return v, nil
+			}
+		}
 	case types.OidFamily:
 		switch v := d.(type) {
 		case *DOid:
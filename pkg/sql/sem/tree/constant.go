@@ -73,6 +73,13 @@ func typeCheckConstant(
 				return c.ResolveAsType(ctx, semaCtx, desired)
 			}
 		}
+		// String literals can also be typed as user-defined composite types.
+		// These aren't part of the available types since the input of anonymous
+		// records is not supported.
+		if s, ok := c.(*StrVal); ok && !s.scannedAsBytes &&
+			desired.Family() == types.TupleFamily && desired.UserDefined() {
+			return c.ResolveAsType(ctx, semaCtx, desired)
+		}
 	}
 
 	// If a numeric constant will be promoted to a DECIMAL because it was out
@@ -494,6 +501,11 @@ var (
 		types.AnyEnum,
 		types.INetArray,
 		types.VarBitArray,
+		types.Int4Range,
+		types.Int8Range,
+		types.TSRange,
+		types.TSTZRange,
+		types.DateRange,
 	}
 	// StrValAvailBytes is the set of types convertible to byte array.
 	StrValAvailBytes = []*types.T{types.Bytes, types.Uuid, types.String, types.AnyEnum}
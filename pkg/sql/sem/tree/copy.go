@@ -10,7 +10,13 @@
 
 package tree
 
-import "github.com/cockroachdb/errors"
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
 
 // CopyFrom represents a COPY FROM statement.
 type CopyFrom struct {
@@ -18,12 +24,29 @@ type CopyFrom struct {
 	Columns NameList
 	Stdin   bool
 	Options CopyOptions
+	// Where, if set, filters the copied rows before they are inserted.
+	Where *Where
+}
+
+// CopyTo represents a COPY TO statement. Either Table or Statement is set:
+// COPY t (cols) TO STDOUT copies the contents of a table and COPY (query) TO
+// STDOUT copies the results of a query.
+type CopyTo struct {
+	Table     TableName
+	Columns   NameList
+	Statement Statement
+	Options   CopyOptions
 }
 
 // CopyOptions describes options for COPY execution.
 type CopyOptions struct {
 	Destination Expr
 	CopyFormat  CopyFormat
+	Delimiter   Expr
+	Null        Expr
+	Quote       Expr
+	Escape      Expr
+	Header      bool
 }
 
 var _ NodeFormatter = &CopyOptions{}
@@ -45,6 +68,52 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
 	}
+	if node.Where != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.Where)
+	}
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(node.Statement)
+		ctx.WriteString(")")
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
+// Query returns the statement whose results are copied. For COPY t (cols) TO
+// STDOUT, this is SELECT cols FROM t.
+func (node *CopyTo) Query() Statement {
+	if node.Statement != nil {
+		return node.Statement
+	}
+	exprs := SelectExprs{StarSelectExpr()}
+	if len(node.Columns) > 0 {
+		exprs = make(SelectExprs, len(node.Columns))
+		for i, col := range node.Columns {
+			exprs[i] = SelectExpr{Expr: NewUnresolvedName(string(col))}
+		}
+	}
+	table := node.Table
+	return &Select{Select: &SelectClause{
+		Exprs: exprs,
+		From:  From{Tables: TableExprs{&table}},
+	}}
 }
 
 // Format implements the NodeFormatter interface
@@ -61,8 +130,35 @@ func (o *CopyOptions) Format(ctx *FmtCtx) {
 		case CopyFormatBinary:
 			ctx.WriteString("BINARY")
 			addSep = true
+		case CopyFormatCSV:
+			ctx.WriteString("CSV")
+			addSep = true
 		}
 	}
+	if o.Header {
+		maybeAddSep()
+		ctx.WriteString("HEADER")
+	}
+	if o.Delimiter != nil {
+		maybeAddSep()
+		ctx.WriteString("DELIMITER ")
+		ctx.FormatNode(o.Delimiter)
+	}
+	if o.Null != nil {
+		maybeAddSep()
+		ctx.WriteString("NULL ")
+		ctx.FormatNode(o.Null)
+	}
+	if o.Quote != nil {
+		maybeAddSep()
+		ctx.WriteString("QUOTE ")
+		ctx.FormatNode(o.Quote)
+	}
+	if o.Escape != nil {
+		maybeAddSep()
+		ctx.WriteString("ESCAPE ")
+		ctx.FormatNode(o.Escape)
+	}
 	if o.Destination != nil {
 		maybeAddSep()
 		// Lowercase because that's what has historically been produced
@@ -93,9 +189,93 @@ func (o *CopyOptions) CombineWith(other *CopyOptions) error {
 		}
 		o.CopyFormat = other.CopyFormat
 	}
+	if other.Delimiter != nil {
+		if o.Delimiter != nil {
+			return errors.New("delimiter option specified multiple times")
+		}
+		o.Delimiter = other.Delimiter
+	}
+	if other.Null != nil {
+		if o.Null != nil {
+			return errors.New("null option specified multiple times")
+		}
+		o.Null = other.Null
+	}
+	if other.Quote != nil {
+		if o.Quote != nil {
+			return errors.New("quote option specified multiple times")
+		}
+		o.Quote = other.Quote
+	}
+	if other.Escape != nil {
+		if o.Escape != nil {
+			return errors.New("escape option specified multiple times")
+		}
+		o.Escape = other.Escape
+	}
+	if other.Header {
+		if o.Header {
+			return errors.New("header option specified multiple times")
+		}
+		o.Header = true
+	}
 	return nil
 }
 
+// MakeCopyOption returns the options described by a single element of the
+// parenthesized option list of COPY, e.g. the FORMAT csv in
+// COPY t FROM STDIN WITH (FORMAT csv). The argument is nil if the option was
+// given without one.
+func MakeCopyOption(name string, arg Expr) (*CopyOptions, error) {
+	requireArg := func() error {
+		if arg == nil {
+			return pgerror.Newf(pgcode.Syntax, "%s requires a parameter", name)
+		}
+		return nil
+	}
+	switch name {
+	case "format":
+		s, ok := arg.(*StrVal)
+		if !ok {
+			return nil, pgerror.Newf(pgcode.Syntax, "%s requires a parameter", name)
+		}
+		switch strings.ToLower(s.RawString()) {
+		case "text":
+			return &CopyOptions{CopyFormat: CopyFormatText}, nil
+		case "binary":
+			return &CopyOptions{CopyFormat: CopyFormatBinary}, nil
+		case "csv":
+			return &CopyOptions{CopyFormat: CopyFormatCSV}, nil
+		}
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"COPY format \"%s\" not recognized", s.RawString())
+	case "header":
+		switch t := arg.(type) {
+		case nil:
+			return &CopyOptions{Header: true}, nil
+		case *DBool:
+			return &CopyOptions{Header: bool(*t)}, nil
+		case *StrVal:
+			switch strings.ToLower(t.RawString()) {
+			case "true", "on":
+				return &CopyOptions{Header: true}, nil
+			case "false", "off":
+				return &CopyOptions{}, nil
+			}
+		}
+		return nil, pgerror.Newf(pgcode.Syntax, "%s requires a Boolean value", name)
+	case "delimiter":
+		return &CopyOptions{Delimiter: arg}, requireArg()
+	case "null":
+		return &CopyOptions{Null: arg}, requireArg()
+	case "quote":
+		return &CopyOptions{Quote: arg}, requireArg()
+	case "escape":
+		return &CopyOptions{Escape: arg}, requireArg()
+	}
+	return nil, pgerror.Newf(pgcode.Syntax, "option \"%s\" not recognized", name)
+}
+
 // CopyFormat identifies a COPY data format.
 type CopyFormat int
 
@@ -103,4 +283,5 @@ type CopyFormat int
 const (
 	CopyFormatText CopyFormat = iota
 	CopyFormatBinary
+	CopyFormatCSV
 )
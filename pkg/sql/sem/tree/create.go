@@ -99,6 +99,9 @@ type IndexElem struct {
 	Expr       Expr
 	Direction  Direction
 	NullsOrder NullsOrder
+	// OpClass is set if an operator class was specified for the element, as
+	// in gin_trgm_ops for trigram indexes.
+	OpClass Name
 }
 
 // Format implements the NodeFormatter interface.
@@ -117,6 +120,10 @@ func (node *IndexElem) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 	}
+	if node.OpClass != "" {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.OpClass)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -139,6 +146,9 @@ func (node *IndexElem) doc(p *PrettyCfg) pretty.Doc {
 			d = p.bracket("(", d, ")")
 		}
 	}
+	if node.OpClass != "" {
+		d = pretty.ConcatSpace(d, p.Doc(&node.OpClass))
+	}
 	if node.Direction != DefaultDirection {
 		d = pretty.ConcatSpace(d, pretty.Keyword(node.Direction.String()))
 	}
@@ -303,12 +313,22 @@ func (l *EnumValueList) Format(ctx *FmtCtx) {
 	}
 }
 
+// CompositeTypeElem is a single attribute in a CREATE TYPE ... AS (...)
+// statement.
+type CompositeTypeElem struct {
+	Label Name
+	Type  ResolvableTypeReference
+}
+
 // CreateType represents a CREATE TYPE statement.
 type CreateType struct {
 	TypeName *UnresolvedObjectName
 	Variety  CreateTypeVariety
 	// EnumLabels is set when this represents a CREATE TYPE ... AS ENUM statement.
 	EnumLabels EnumValueList
+	// CompositeTypeList is set when this represents a CREATE TYPE ... AS (...)
+	// statement.
+	CompositeTypeList []CompositeTypeElem
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -328,6 +348,18 @@ func (node *CreateType) Format(ctx *FmtCtx) {
 		ctx.WriteString("AS ENUM (")
 		ctx.FormatNode(&node.EnumLabels)
 		ctx.WriteString(")")
+	case Composite:
+		ctx.WriteString("AS (")
+		for i := range node.CompositeTypeList {
+			elem := &node.CompositeTypeList[i]
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&elem.Label)
+			ctx.WriteByte(' ')
+			ctx.FormatTypeReference(elem.Type)
+		}
+		ctx.WriteString(")")
 	}
 }
 
@@ -349,6 +381,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExclusionConstraintTableDef) tableDef()  {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -406,6 +439,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -535,6 +569,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
@@ -652,6 +687,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -756,10 +792,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -842,13 +879,15 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -888,6 +927,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -961,14 +1001,56 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability specifies whether the checking of a constraint can
+// be deferred until the end of the transaction, using SET CONSTRAINTS.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	// NotDeferrableConstraint constraints are checked at the end of each statement.
+	NotDeferrableConstraint ConstraintDeferrability = iota
+	// DeferrableInitiallyImmediate constraints are checked at the end of each
+	// statement, unless they are deferred using SET CONSTRAINTS.
+	DeferrableInitiallyImmediate
+	// DeferrableInitiallyDeferred constraints are checked at the end of the
+	// transaction, unless they are made immediate using SET CONSTRAINTS.
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrableConstraint:      "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE INITIALLY IMMEDIATE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Deferrable returns true if the constraint can be deferred.
+func (d ConstraintDeferrability) Deferrable() bool {
+	return d != NotDeferrableConstraint
+}
+
+// Format implements the NodeFormatter interface.
+func (d ConstraintDeferrability) Format(ctx *FmtCtx) {
+	switch d {
+	case DeferrableInitiallyImmediate:
+		ctx.WriteString(" DEFERRABLE")
+	case DeferrableInitiallyDeferred:
+		ctx.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -996,6 +1078,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1028,6 +1111,57 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an exclusion constraint within a
+// CREATE TABLE statement.
+type ExclusionConstraintTableDef struct {
+	Name  Name
+	Elems ExclusionElemList
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE USING gist (")
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+}
+
+// ExclusionElem represents a single column of an exclusion constraint along
+// with the operator used to compare its values.
+type ExclusionElem struct {
+	Column   Name
+	Operator ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExclusionElemList is a list of exclusion constraint elements.
+type ExclusionElemList []ExclusionElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExclusionElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1387,12 +1521,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -1800,6 +1935,9 @@ const (
 	// RefreshDataClear refers to the WITH NO DATA option provided to the REFRESH
 	// MATERIALIZED VIEW statement.
 	RefreshDataClear
+	// RefreshDataIncremental refers to the INCREMENTAL option provided to the
+	// REFRESH MATERIALIZED VIEW statement.
+	RefreshDataIncremental
 )
 
 // Format implements the NodeFormatter interface.
@@ -1814,6 +1952,8 @@ func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH DATA")
 	case RefreshDataClear:
 		ctx.WriteString(" WITH NO DATA")
+	case RefreshDataIncremental:
+		ctx.WriteString(" INCREMENTAL")
 	}
 }
 
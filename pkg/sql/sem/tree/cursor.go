@@ -0,0 +1,208 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "strconv"
+
+// DeclareCursor represents a DECLARE statement.
+type DeclareCursor struct {
+	Name        Name
+	Select      *Select
+	Binary      bool
+	Scroll      CursorScrollOption
+	Sensitivity CursorSensitivity
+	Hold        bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DeclareCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("DECLARE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteRune(' ')
+	if node.Binary {
+		ctx.WriteString("BINARY ")
+	}
+	if node.Sensitivity != UnspecifiedSensitivity {
+		ctx.WriteString(node.Sensitivity.String())
+		ctx.WriteRune(' ')
+	}
+	if node.Scroll != UnspecifiedScroll {
+		ctx.WriteString(node.Scroll.String())
+		ctx.WriteRune(' ')
+	}
+	ctx.WriteString("CURSOR ")
+	if node.Hold {
+		ctx.WriteString("WITH HOLD ")
+	}
+	ctx.WriteString("FOR ")
+	ctx.FormatNode(node.Select)
+}
+
+// CursorScrollOption represents the scroll option, if one was given, for a
+// DECLARE statement.
+type CursorScrollOption int8
+
+const (
+	// UnspecifiedScroll represents no SCROLL option having been given. In
+	// Postgres, this is like NO SCROLL, but the returned cursor also supports
+	// some trivial cases of moving backwards.
+	UnspecifiedScroll CursorScrollOption = iota
+	// Scroll represents SCROLL.
+	Scroll
+	// NoScroll represents NO SCROLL.
+	NoScroll
+)
+
+func (o CursorScrollOption) String() string {
+	switch o {
+	case Scroll:
+		return "SCROLL"
+	case NoScroll:
+		return "NO SCROLL"
+	}
+	return ""
+}
+
+// CursorSensitivity represents the "sensitivity" of a cursor, which describes
+// whether it sees writes that occur within the transaction after it was
+// declared.
+type CursorSensitivity int
+
+const (
+	// UnspecifiedSensitivity indicates that no sensitivity was specified.
+	UnspecifiedSensitivity CursorSensitivity = iota
+	// Insensitive indicates that the cursor is required to not see writes that
+	// occur after it was declared.
+	Insensitive
+	// Asensitive leaves the sensitivity of the cursor up to the
+	// implementation.
+	Asensitive
+)
+
+func (o CursorSensitivity) String() string {
+	switch o {
+	case Insensitive:
+		return "INSENSITIVE"
+	case Asensitive:
+		return "ASENSITIVE"
+	}
+	return ""
+}
+
+// CursorStmt represents the common elements of a FETCH or MOVE statement.
+type CursorStmt struct {
+	Name      Name
+	FetchType FetchType
+	Count     int64
+}
+
+// FetchCursor represents a FETCH statement.
+type FetchCursor struct {
+	CursorStmt
+}
+
+// MoveCursor represents a MOVE statement.
+type MoveCursor struct {
+	CursorStmt
+}
+
+// FetchType represents the type of a FETCH (or MOVE) statement.
+type FetchType int
+
+const (
+	// FetchNormal represents a FETCH statement that doesn't have a special
+	// qualifier. It's used for FORWARD, BACKWARD, NEXT, and PRIOR.
+	FetchNormal FetchType = iota
+	// FetchRelative represents a FETCH RELATIVE statement.
+	FetchRelative
+	// FetchAbsolute represents a FETCH ABSOLUTE statement.
+	FetchAbsolute
+	// FetchFirst represents a FETCH FIRST statement.
+	FetchFirst
+	// FetchLast represents a FETCH LAST statement.
+	FetchLast
+	// FetchAll represents a FETCH ALL statement.
+	FetchAll
+	// FetchBackwardAll represents a FETCH BACKWARD ALL statement.
+	FetchBackwardAll
+)
+
+func (o FetchType) String() string {
+	switch o {
+	case FetchNormal:
+		return ""
+	case FetchRelative:
+		return "RELATIVE"
+	case FetchAbsolute:
+		return "ABSOLUTE"
+	case FetchFirst:
+		return "FIRST"
+	case FetchLast:
+		return "LAST"
+	case FetchAll:
+		return "ALL"
+	case FetchBackwardAll:
+		return "BACKWARD ALL"
+	}
+	return ""
+}
+
+// HasCount returns true if the given fetch type should be printed with an
+// associated count.
+func (o FetchType) HasCount() bool {
+	switch o {
+	case FetchNormal, FetchRelative, FetchAbsolute:
+		return true
+	}
+	return false
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CursorStmt) Format(ctx *FmtCtx) {
+	fetchType := node.FetchType.String()
+	if fetchType != "" {
+		ctx.WriteString(fetchType)
+		ctx.WriteString(" ")
+	}
+	if node.FetchType.HasCount() {
+		ctx.WriteString(strconv.FormatInt(node.Count, 10))
+		ctx.WriteString(" ")
+	}
+	ctx.FormatNode(&node.Name)
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FetchCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("FETCH ")
+	node.CursorStmt.Format(ctx)
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MoveCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("MOVE ")
+	node.CursorStmt.Format(ctx)
+}
+
+// CloseCursor represents a CLOSE statement.
+type CloseCursor struct {
+	Name Name
+	All  bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CloseCursor) Format(ctx *FmtCtx) {
+	ctx.WriteString("CLOSE ")
+	if node.All {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Name)
+	}
+}
@@ -3064,6 +3064,247 @@ func (d *DBox2D) Size() uintptr {
 	return unsafe.Sizeof(*d) + unsafe.Sizeof(d.CartesianBoundingBox)
 }
 
+// DRange is the Datum representation of the built-in range types. A range is
+// either empty or contains the values between its lower and upper bound. An
+// unbounded side of the range is represented by a DNull bound, which is never
+// inclusive.
+//
+// DRanges are always kept in canonical form: a range that contains no values
+// is represented by the empty range, and ranges over discrete subtypes (INT4,
+// INT8 and DATE) always have an inclusive lower bound and an exclusive upper
+// bound.
+type DRange struct {
+	typ *types.T
+
+	Lower, Upper       Datum
+	LowerInc, UpperInc bool
+	Empty              bool
+}
+
+// NewDEmptyRange returns an empty range of the given range type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{typ: typ, Lower: DNull, Upper: DNull, Empty: true}
+}
+
+// NewDRange returns a range of the given range type with the given bounds,
+// converted to canonical form. A DNull bound makes the range unbounded on
+// that side. An error is returned if the lower bound is greater than the
+// upper bound.
+//
+// ctx may be nil, since both bounds have the same type and so their order
+// does not depend on the session.
+func NewDRange(
+	ctx *EvalContext, typ *types.T, lower, upper Datum, lowerInc, upperInc bool,
+) (*DRange, error) {
+	if ctx == nil {
+		ctx = &EvalContext{}
+	}
+	if lower == DNull {
+		lowerInc = false
+	}
+	if upper == DNull {
+		upperInc = false
+	}
+	if lower != DNull && upper != DNull {
+		c := lower.Compare(ctx, upper)
+		if c > 0 {
+			return nil, pgerror.New(pgcode.DataException,
+				"range lower bound must be less than or equal to range upper bound")
+		}
+		if c == 0 && !(lowerInc && upperInc) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	r := &DRange{typ: typ, Lower: lower, Upper: upper, LowerInc: lowerInc, UpperInc: upperInc}
+	if err := r.canonicalize(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// canonicalize converts the bounds of a range over a discrete subtype to the
+// canonical [) form, and turns the range into the empty range if it does not
+// contain any values as a result.
+func (d *DRange) canonicalize(ctx *EvalContext) error {
+	if d.Lower != DNull && !d.LowerInc {
+		next, err := d.discreteNext(d.Lower)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			d.Lower, d.LowerInc = next, true
+		}
+	}
+	if d.Upper != DNull && d.UpperInc {
+		next, err := d.discreteNext(d.Upper)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			d.Upper, d.UpperInc = next, false
+		}
+	}
+	if d.Lower != DNull && d.Upper != DNull && d.Lower.Compare(ctx, d.Upper) >= 0 &&
+		!(d.LowerInc && d.UpperInc) {
+		*d = *NewDEmptyRange(d.typ)
+	}
+	return nil
+}
+
+// discreteNext returns the value following the given bound for ranges over
+// discrete subtypes. It returns nil if the bound cannot be converted to the
+// canonical form, which is the case for continuous subtypes and for infinite
+// dates.
+func (d *DRange) discreteNext(bound Datum) (Datum, error) {
+	switch d.typ.Oid() {
+	case oid.T_int4range:
+		v := MustBeDInt(bound)
+		if v >= math.MaxInt32 {
+			return nil, pgerror.New(pgcode.NumericValueOutOfRange, "integer out of range")
+		}
+		return NewDInt(v + 1), nil
+	case oid.T_int8range:
+		v := MustBeDInt(bound)
+		if v == math.MaxInt64 {
+			return nil, pgerror.New(pgcode.NumericValueOutOfRange, "bigint out of range")
+		}
+		return NewDInt(v + 1), nil
+	case oid.T_daterange:
+		v := bound.(*DDate)
+		if !v.IsFinite() {
+			return nil, nil
+		}
+		next, err := v.AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(next), nil
+	}
+	return nil, nil
+}
+
+// AsDRange attempts to retrieve a *DRange from an Expr, returning a *DRange
+// and a flag signifying whether the assertion was successful.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a *DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	r, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return r
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DRange) ResolvedType() *types.T {
+	return d.typ
+}
+
+// Compare implements the Datum interface. Empty ranges sort before all other
+// ranges, which are ordered by their lower bound and then by their upper
+// bound.
+func (d *DRange) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	o, ok := AsDRange(other)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	switch {
+	case d.Empty && o.Empty:
+		return 0
+	case d.Empty:
+		return -1
+	case o.Empty:
+		return 1
+	}
+	if c := compareRangeBounds(ctx, d.lowerBound(), o.lowerBound()); c != 0 {
+		return c
+	}
+	return compareRangeBounds(ctx, d.upperBound(), o.upperBound())
+}
+
+// Prev implements the Datum interface.
+func (d *DRange) Prev(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DRange) Next(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DRange) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DRange) IsMin(_ *EvalContext) bool {
+	return d.Empty
+}
+
+// Max implements the Datum interface.
+func (d *DRange) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DRange) Min(_ *EvalContext) (Datum, bool) {
+	return NewDEmptyRange(d.typ), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DRange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DRange) Format(ctx *FmtCtx) {
+	var buf bytes.Buffer
+	if d.Empty {
+		buf.WriteString("empty")
+	} else {
+		if d.LowerInc {
+			buf.WriteByte('[')
+		} else {
+			buf.WriteByte('(')
+		}
+		if d.Lower != DNull {
+			formatStringInRange(&buf, AsStringWithFlags(d.Lower, FmtBareStrings))
+		}
+		buf.WriteByte(',')
+		if d.Upper != DNull {
+			formatStringInRange(&buf, AsStringWithFlags(d.Upper, FmtBareStrings))
+		}
+		if d.UpperInc {
+			buf.WriteByte(']')
+		} else {
+			buf.WriteByte(')')
+		}
+	}
+	if ctx.flags.HasAnyFlags(fmtRawStrings | FmtFlags(lexbase.EncBareStrings)) {
+		ctx.Write(buf.Bytes())
+	} else {
+		lex.EncodeSQLStringWithFlags(&ctx.Buffer, buf.String(), ctx.flags.EncodeFlags())
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DRange) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.Lower.Size() + d.Upper.Size()
+}
+
 // DJSON is the JSON Datum.
 type DJSON struct{ json.JSON }
 
@@ -3183,7 +3424,7 @@ func AsJSON(d Datum, loc *time.Location) (json.JSON, error) {
 	case *DTimestamp:
 		// This is RFC3339Nano, but without the TZ fields.
 		return json.FromString(t.UTC().Format("2006-01-02T15:04:05.999999999")), nil
-	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D:
+	case *DDate, *DUuid, *DOid, *DInterval, *DBytes, *DIPAddr, *DTime, *DTimeTZ, *DBitArray, *DBox2D, *DRange:
 		return json.FromString(AsStringWithFlags(t, FmtBareStrings)), nil
 	case *DGeometry:
 		return json.FromSpatialObject(t.Geometry.SpatialObject(), geo.DefaultGeoJSONDecimalDigits)
@@ -4953,6 +5194,7 @@ var baseDatumTypeSizes = map[types.Family]struct {
 	types.UuidFamily:           {unsafe.Sizeof(DUuid{}), fixedSize},
 	types.INetFamily:           {unsafe.Sizeof(DIPAddr{}), fixedSize},
 	types.OidFamily:            {unsafe.Sizeof(DInt(0)), fixedSize},
+	types.RangeFamily:          {unsafe.Sizeof(DRange{}), variableSize},
 	types.EnumFamily:           {unsafe.Sizeof(DEnum{}), variableSize},
 
 	// TODO(jordan,justin): This seems suspicious.
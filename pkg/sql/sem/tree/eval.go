@@ -47,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -457,10 +458,50 @@ func initNonArrayToNonArrayConcatenation() {
 	}
 }
 
+// initRangeOperators initializes the union (+), intersection (*) and
+// difference (-) operators, as well as the strictly left of (<<) and strictly
+// right of (>>) operators, for each of the built-in range types.
+func initRangeOperators() {
+	addRangeOp := func(
+		op BinaryOperator, typ *types.T, fn func(lhs *DRange, ctx *EvalContext, rhs *DRange) (*DRange, error),
+	) {
+		BinOps[op] = append(BinOps[op], &BinOp{
+			LeftType:   typ,
+			RightType:  typ,
+			ReturnType: typ,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return fn(MustBeDRange(left), ctx, MustBeDRange(right))
+			},
+			Volatility: VolatilityImmutable,
+		})
+	}
+	addRangeBoolOp := func(
+		op BinaryOperator, typ *types.T, fn func(lhs *DRange, ctx *EvalContext, rhs *DRange) bool,
+	) {
+		BinOps[op] = append(BinOps[op], &BinOp{
+			LeftType:   typ,
+			RightType:  typ,
+			ReturnType: types.Bool,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(fn(MustBeDRange(left), ctx, MustBeDRange(right)))), nil
+			},
+			Volatility: VolatilityImmutable,
+		})
+	}
+	for _, typ := range types.RangeTypes {
+		addRangeOp(Plus, typ, (*DRange).Union)
+		addRangeOp(Mult, typ, (*DRange).Intersect)
+		addRangeOp(Minus, typ, (*DRange).Minus)
+		addRangeBoolOp(LShift, typ, (*DRange).StrictlyLeftOf)
+		addRangeBoolOp(RShift, typ, (*DRange).StrictlyRightOf)
+	}
+}
+
 func init() {
 	initArrayElementConcatenation()
 	initArrayToArrayConcatenation()
 	initNonArrayToNonArrayConcatenation()
+	initRangeOperators()
 }
 
 func init() {
@@ -1638,6 +1679,20 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			},
 			Volatility: VolatilityImmutable,
 		},
+		&BinOp{
+			// The trigram similarity operator of pg_trgm.
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				// Strings without any shared trigrams are never similar, even if
+				// the threshold is zero. This allows the operator to be evaluated
+				// with a trigram index.
+				sim := trigram.Similarity(string(MustBeDString(left)), string(MustBeDString(right)))
+				return MakeDBool(DBool(sim > 0 && sim >= ctx.SessionData.TrigramSimilarityThreshold)), nil
+			},
+			Volatility: VolatilityStable,
+		},
 	},
 
 	Concat: {
@@ -2112,6 +2167,11 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEqFn(types.Interval, types.Interval, VolatilityLeakProof),
 		makeEqFn(types.Jsonb, types.Jsonb, VolatilityImmutable),
 		makeEqFn(types.Oid, types.Oid, VolatilityLeakProof),
+		makeEqFn(types.DateRange, types.DateRange, VolatilityLeakProof),
+		makeEqFn(types.Int4Range, types.Int4Range, VolatilityLeakProof),
+		makeEqFn(types.Int8Range, types.Int8Range, VolatilityLeakProof),
+		makeEqFn(types.TSRange, types.TSRange, VolatilityLeakProof),
+		makeEqFn(types.TSTZRange, types.TSTZRange, VolatilityLeakProof),
 		makeEqFn(types.String, types.String, VolatilityLeakProof),
 		makeEqFn(types.Time, types.Time, VolatilityLeakProof),
 		makeEqFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
@@ -2168,6 +2228,11 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLtFn(types.Int, types.Int, VolatilityLeakProof),
 		makeLtFn(types.Interval, types.Interval, VolatilityLeakProof),
 		makeLtFn(types.Oid, types.Oid, VolatilityLeakProof),
+		makeLtFn(types.DateRange, types.DateRange, VolatilityLeakProof),
+		makeLtFn(types.Int4Range, types.Int4Range, VolatilityLeakProof),
+		makeLtFn(types.Int8Range, types.Int8Range, VolatilityLeakProof),
+		makeLtFn(types.TSRange, types.TSRange, VolatilityLeakProof),
+		makeLtFn(types.TSTZRange, types.TSTZRange, VolatilityLeakProof),
 		makeLtFn(types.String, types.String, VolatilityLeakProof),
 		makeLtFn(types.Time, types.Time, VolatilityLeakProof),
 		makeLtFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
@@ -2224,6 +2289,11 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeLeFn(types.Int, types.Int, VolatilityLeakProof),
 		makeLeFn(types.Interval, types.Interval, VolatilityLeakProof),
 		makeLeFn(types.Oid, types.Oid, VolatilityLeakProof),
+		makeLeFn(types.DateRange, types.DateRange, VolatilityLeakProof),
+		makeLeFn(types.Int4Range, types.Int4Range, VolatilityLeakProof),
+		makeLeFn(types.Int8Range, types.Int8Range, VolatilityLeakProof),
+		makeLeFn(types.TSRange, types.TSRange, VolatilityLeakProof),
+		makeLeFn(types.TSTZRange, types.TSTZRange, VolatilityLeakProof),
 		makeLeFn(types.String, types.String, VolatilityLeakProof),
 		makeLeFn(types.Time, types.Time, VolatilityLeakProof),
 		makeLeFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
@@ -2290,6 +2360,11 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeIsFn(types.Interval, types.Interval, VolatilityLeakProof),
 		makeIsFn(types.Jsonb, types.Jsonb, VolatilityImmutable),
 		makeIsFn(types.Oid, types.Oid, VolatilityLeakProof),
+		makeIsFn(types.DateRange, types.DateRange, VolatilityLeakProof),
+		makeIsFn(types.Int4Range, types.Int4Range, VolatilityLeakProof),
+		makeIsFn(types.Int8Range, types.Int8Range, VolatilityLeakProof),
+		makeIsFn(types.TSRange, types.TSRange, VolatilityLeakProof),
+		makeIsFn(types.TSTZRange, types.TSTZRange, VolatilityLeakProof),
 		makeIsFn(types.String, types.String, VolatilityLeakProof),
 		makeIsFn(types.Time, types.Time, VolatilityLeakProof),
 		makeIsFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
@@ -2348,6 +2423,11 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(types.Interval, VolatilityLeakProof),
 		makeEvalTupleIn(types.Jsonb, VolatilityLeakProof),
 		makeEvalTupleIn(types.Oid, VolatilityLeakProof),
+		makeEvalTupleIn(types.DateRange, VolatilityLeakProof),
+		makeEvalTupleIn(types.Int4Range, VolatilityLeakProof),
+		makeEvalTupleIn(types.Int8Range, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSRange, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSTZRange, VolatilityLeakProof),
 		makeEvalTupleIn(types.String, VolatilityLeakProof),
 		makeEvalTupleIn(types.Time, VolatilityLeakProof),
 		makeEvalTupleIn(types.TimeTZ, VolatilityLeakProof),
@@ -2475,56 +2555,62 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 		},
 	},
 
-	Contains: {
-		&CmpOp{
-			LeftType:  types.AnyArray,
-			RightType: types.AnyArray,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				haystack := MustBeDArray(left)
-				needles := MustBeDArray(right)
-				return ArrayContains(ctx, haystack, needles)
+	Contains: append(
+		cmpOpOverload{
+			&CmpOp{
+				LeftType:  types.AnyArray,
+				RightType: types.AnyArray,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					haystack := MustBeDArray(left)
+					needles := MustBeDArray(right)
+					return ArrayContains(ctx, haystack, needles)
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
-		},
-		&CmpOp{
-			LeftType:  types.Jsonb,
-			RightType: types.Jsonb,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				c, err := json.Contains(left.(*DJSON).JSON, right.(*DJSON).JSON)
-				if err != nil {
-					return nil, err
-				}
-				return MakeDBool(DBool(c)), nil
+			&CmpOp{
+				LeftType:  types.Jsonb,
+				RightType: types.Jsonb,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					c, err := json.Contains(left.(*DJSON).JSON, right.(*DJSON).JSON)
+					if err != nil {
+						return nil, err
+					}
+					return MakeDBool(DBool(c)), nil
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
 		},
-	},
+		makeRangeContainmentOperators(false /* containedBy */)...,
+	),
 
-	ContainedBy: {
-		&CmpOp{
-			LeftType:  types.AnyArray,
-			RightType: types.AnyArray,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				needles := MustBeDArray(left)
-				haystack := MustBeDArray(right)
-				return ArrayContains(ctx, haystack, needles)
+	ContainedBy: append(
+		cmpOpOverload{
+			&CmpOp{
+				LeftType:  types.AnyArray,
+				RightType: types.AnyArray,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					needles := MustBeDArray(left)
+					haystack := MustBeDArray(right)
+					return ArrayContains(ctx, haystack, needles)
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
-		},
-		&CmpOp{
-			LeftType:  types.Jsonb,
-			RightType: types.Jsonb,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				c, err := json.Contains(right.(*DJSON).JSON, left.(*DJSON).JSON)
-				if err != nil {
-					return nil, err
-				}
-				return MakeDBool(DBool(c)), nil
+			&CmpOp{
+				LeftType:  types.Jsonb,
+				RightType: types.Jsonb,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					c, err := json.Contains(right.(*DJSON).JSON, left.(*DJSON).JSON)
+					if err != nil {
+						return nil, err
+					}
+					return MakeDBool(DBool(c)), nil
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
 		},
-	},
-	Overlaps: append(
+		makeRangeContainmentOperators(true /* containedBy */)...,
+	),
+	Overlaps: append(append(
 		cmpOpOverload{
 			&CmpOp{
 				LeftType:  types.AnyArray,
@@ -2565,6 +2651,11 @@ var CmpOps = cmpOpFixups(map[ComparisonOperator]cmpOpOverload{
 			func(lhs, rhs *geo.CartesianBoundingBox) bool {
 				return lhs.Intersects(rhs)
 			},
+		)...),
+		makeRangeComparisonOperators(
+			func(ctx *EvalContext, lhs, rhs *DRange) bool {
+				return lhs.Overlaps(ctx, rhs)
+			},
 		)...,
 	),
 })
@@ -2656,6 +2747,54 @@ func makeBox2DComparisonOperators(op func(lhs, rhs *geo.CartesianBoundingBox) bo
 	}
 }
 
+// makeRangeComparisonOperators returns an overload of a comparison operator
+// between two ranges for each of the built-in range types.
+func makeRangeComparisonOperators(op func(ctx *EvalContext, lhs, rhs *DRange) bool) cmpOpOverload {
+	ret := make(cmpOpOverload, 0, len(types.RangeTypes))
+	for _, typ := range types.RangeTypes {
+		ret = append(ret, &CmpOp{
+			LeftType:  typ,
+			RightType: typ,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(op(ctx, MustBeDRange(left), MustBeDRange(right)))), nil
+			},
+			Volatility: VolatilityImmutable,
+		})
+	}
+	return ret
+}
+
+// makeRangeContainmentOperators returns the overloads of @> (or <@, if
+// containedBy is set) for each of the built-in range types. Each range type
+// can contain either another range of the same type or a value of its
+// subtype.
+func makeRangeContainmentOperators(containedBy bool) cmpOpOverload {
+	ret := makeRangeComparisonOperators(func(ctx *EvalContext, lhs, rhs *DRange) bool {
+		if containedBy {
+			return rhs.Contains(ctx, lhs)
+		}
+		return lhs.Contains(ctx, rhs)
+	})
+	for _, typ := range types.RangeTypes {
+		op := &CmpOp{
+			LeftType:  typ,
+			RightType: typ.RangeContents(),
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(MustBeDRange(left).ContainsElem(ctx, right))), nil
+			},
+			Volatility: VolatilityImmutable,
+		}
+		if containedBy {
+			op.LeftType, op.RightType = op.RightType, op.LeftType
+			op.Fn = func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(MustBeDRange(right).ContainsElem(ctx, left))), nil
+			}
+		}
+		ret = append(ret, op)
+	}
+	return ret
+}
+
 // This map contains the inverses for operators in the CmpOps map that have
 // inverses.
 var cmpOpsInverse map[ComparisonOperator]ComparisonOperator
@@ -3085,11 +3224,12 @@ type EvalPlanner interface {
 
 // EvalSessionAccessor is a limited interface to access session variables.
 type EvalSessionAccessor interface {
-	// SetConfig sets a session variable to a new value.
+	// SetConfig sets a session variable to a new value. If isLocal is set, the
+	// variable is only changed for the rest of the current transaction.
 	//
 	// This interface only supports strings as this is sufficient for
 	// pg_catalog.set_config().
-	SetSessionVar(ctx context.Context, settingName, newValue string) error
+	SetSessionVar(ctx context.Context, settingName, newValue string, isLocal bool) error
 
 	// GetSessionVar retrieves the current value of a session variable.
 	GetSessionVar(ctx context.Context, settingName string, missingOk bool) (bool, string, error)
@@ -3848,6 +3988,10 @@ func (expr *ColumnAccessExpr) Eval(ctx *EvalContext) (Datum, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Accessing a field of a NULL composite value yields NULL.
+	if d == DNull {
+		return d, nil
+	}
 	return d.(*DTuple).D[expr.ColIndex], nil
 }
 
@@ -4358,6 +4502,11 @@ func (t *DBox2D) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DRange) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DGeography) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -1769,6 +1769,7 @@ func (node *DDecimal) String() string         { return AsString(node) }
 func (node *DFloat) String() string           { return AsString(node) }
 func (node *DBox2D) String() string           { return AsString(node) }
 func (node *DGeography) String() string       { return AsString(node) }
+func (node *DRange) String() string           { return AsString(node) }
 func (node *DGeometry) String() string        { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
@@ -1810,6 +1811,8 @@ func (node *UnaryExpr) String() string        { return AsString(node) }
 func (node DefaultVal) String() string        { return AsString(node) }
 func (node PartitionMaxVal) String() string   { return AsString(node) }
 func (node PartitionMinVal) String() string   { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
+func (node *GroupingExpr) String() string     { return AsString(node) }
 func (node *Placeholder) String() string      { return AsString(node) }
 func (node dNull) String() string             { return AsString(node) }
 func (list *NameList) String() string         { return AsString(list) }
@@ -94,6 +94,12 @@ type FunctionProperties struct {
 	// should take RegClass as the arg type for the sequence name instead of
 	// string, we will add a dependency on all RegClass types used in a view.
 	HasSequenceArguments bool
+
+	// UserDefined is set to true for the definitions which the optimizer
+	// synthesizes for calls to user-defined functions. Such definitions are
+	// not registered in FunDefs, and their overloads evaluate the body of the
+	// function using the internal executor.
+	UserDefined bool
 }
 
 // ShouldDocument returns whether the built-in function should be included in
@@ -155,6 +161,22 @@ func NewFunctionDefinition(
 	}
 }
 
+// NewUserDefinedFunctionDefinition creates a FunctionDefinition with a single
+// overload for a call to a user-defined function. Unlike NewFunctionDefinition,
+// it does not register telemetry counters, since the name of the function is
+// chosen by the user.
+func NewUserDefinedFunctionDefinition(
+	name string, props *FunctionProperties, def *Overload,
+) *FunctionDefinition {
+	fd := &FunctionDefinition{
+		Name:               name,
+		Definition:         []overloadImpl{def},
+		FunctionProperties: *props,
+	}
+	fd.UserDefined = true
+	return fd
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
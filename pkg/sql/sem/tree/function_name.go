@@ -62,6 +62,14 @@ func (fn *ResolvableFunctionReference) Resolve(
 	}
 }
 
+// WrapUserDefinedFunction creates a new ResolvableFunctionReference holding a
+// user-defined function with the given name and properties. User-defined
+// functions are not registered in FunDefs, so they cannot be wrapped using
+// WrapFunction.
+func WrapUserDefinedFunction(n string, props *FunctionProperties) ResolvableFunctionReference {
+	return ResolvableFunctionReference{&FunctionDefinition{Name: n, FunctionProperties: *props}}
+}
+
 // WrapFunction creates a new ResolvableFunctionReference
 // holding a pre-resolved function. Helper for grammar rules.
 func WrapFunction(n string) ResolvableFunctionReference {
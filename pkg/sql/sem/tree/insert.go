@@ -52,6 +52,10 @@ func (node *Insert) Format(ctx *FmtCtx) {
 	}
 	if node.OnConflict != nil && !node.OnConflict.IsUpsertAlias() {
 		ctx.WriteString(" ON CONFLICT")
+		if node.OnConflict.Constraint != "" {
+			ctx.WriteString(" ON CONSTRAINT ")
+			ctx.FormatNode(&node.OnConflict.Constraint)
+		}
 		if len(node.OnConflict.Columns) > 0 {
 			ctx.WriteString(" (")
 			ctx.FormatNode(&node.OnConflict.Columns)
@@ -84,12 +88,14 @@ func (node *Insert) DefaultValues() bool {
 }
 
 // OnConflict represents an `ON CONFLICT (columns) WHERE arbiter DO UPDATE SET
-// exprs WHERE where` clause.
+// exprs WHERE where` clause. When the `ON CONFLICT ON CONSTRAINT name` form is
+// used, Constraint is set instead of Columns and ArbiterPredicate.
 //
 // The zero value for OnConflict is used to signal the UPSERT short form, which
 // uses the primary key for as the conflict index and the values being inserted
 // for Exprs.
 type OnConflict struct {
+	Constraint       Name
 	Columns          NameList
 	ArbiterPredicate Expr
 	Exprs            UpdateExprs
@@ -99,5 +105,5 @@ type OnConflict struct {
 
 // IsUpsertAlias returns true if the UPSERT syntactic sugar was used.
 func (oc *OnConflict) IsUpsertAlias() bool {
-	return oc != nil && oc.Columns == nil && oc.ArbiterPredicate == nil && oc.Exprs == nil && oc.Where == nil && !oc.DoNothing
+	return oc != nil && oc.Constraint == "" && oc.Columns == nil && oc.ArbiterPredicate == nil && oc.Exprs == nil && oc.Where == nil && !oc.DoNothing
 }
@@ -0,0 +1,145 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// ParseDRangeFromString parses the string-form of a range, such as
+// `'[1,10)'::int4range` or `'empty'::daterange`. An empty unquoted bound
+// makes the range unbounded on that side.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseTimeContext (either for the time or the local timezone).
+func ParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	ret, dependsOnContext, err := doParseDRangeFromString(ctx, s, t)
+	if err != nil {
+		return nil, false, makeParseError(s, t, err)
+	}
+	return ret, dependsOnContext, nil
+}
+
+// doParseDRangeFromString does most of the work of ParseDRangeFromString,
+// except the error it returns isn't prettified as a parsing error.
+func doParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	s = strings.TrimFunc(s, unicode.IsSpace)
+	if strings.EqualFold(s, "empty") {
+		return NewDEmptyRange(t), false, nil
+	}
+
+	if len(s) == 0 || (s[0] != '[' && s[0] != '(') {
+		return nil, false, errors.New("missing left parenthesis or bracket")
+	}
+	lowerInc := s[0] == '['
+	i := 1
+
+	var bounds [2]Datum
+	for idx := range bounds {
+		var bound string
+		var infinite bool
+		var err error
+		bound, infinite, i, err = parseRangeBound(s, i)
+		if err != nil {
+			return nil, false, err
+		}
+		if infinite {
+			bounds[idx] = DNull
+		} else {
+			d, dep, err := ParseAndRequireString(t.RangeContents(), bound, ctx)
+			if err != nil {
+				return nil, false, err
+			}
+			if t.Oid() == oid.T_int4range {
+				if v := MustBeDInt(d); v < math.MinInt32 || v > math.MaxInt32 {
+					return nil, false, pgerror.Newf(pgcode.NumericValueOutOfRange,
+						"value %q is out of range for type integer", bound)
+				}
+			}
+			dependsOnContext = dependsOnContext || dep
+			bounds[idx] = d
+		}
+		if idx == 0 {
+			if i >= len(s) || s[i] != ',' {
+				return nil, false, errors.New("missing comma after lower bound")
+			}
+			i++
+		}
+	}
+	if i >= len(s) || (s[i] != ']' && s[i] != ')') {
+		return nil, false, errors.New("too many commas")
+	}
+	upperInc := s[i] == ']'
+	if i != len(s)-1 {
+		return nil, false, errors.New("junk after right parenthesis or bracket")
+	}
+
+	ret, err := NewDRange(nil /* ctx */, t, bounds[0], bounds[1], lowerInc, upperInc)
+	if err != nil {
+		return nil, false, err
+	}
+	return ret, dependsOnContext, nil
+}
+
+// parseRangeBound parses the range bound starting at position i in s. It
+// returns the unescaped text of the bound, whether the bound is infinite, and
+// the position following the bound.
+func parseRangeBound(s string, i int) (bound string, infinite bool, next int, _ error) {
+	// An empty unquoted bound is infinite. Note that an empty quoted bound is
+	// an empty string instead.
+	if i < len(s) && (s[i] == ',' || s[i] == ')' || s[i] == ']') {
+		return "", true, i, nil
+	}
+	var buf bytes.Buffer
+	inQuote := false
+	for ; i < len(s); i++ {
+		ch := s[i]
+		if !inQuote && (ch == ',' || ch == ')' || ch == ']' || ch == '(' || ch == '[') {
+			break
+		}
+		switch {
+		case ch == '\\':
+			i++
+			if i >= len(s) {
+				return "", false, 0, errors.New("unexpected end of input")
+			}
+			buf.WriteByte(s[i])
+		case ch == '"' && inQuote && i+1 < len(s) && s[i+1] == '"':
+			// A doubled quote inside a quoted section is a literal quote.
+			buf.WriteByte('"')
+			i++
+		case ch == '"':
+			inQuote = !inQuote
+		default:
+			buf.WriteByte(ch)
+		}
+	}
+	if i >= len(s) {
+		return "", false, 0, errors.New("unexpected end of input")
+	}
+	if s[i] == '(' || s[i] == '[' {
+		return "", false, 0, errors.Newf("unexpected %q", s[i])
+	}
+	return buf.String(), false, i, nil
+}
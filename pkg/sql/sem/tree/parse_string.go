@@ -67,6 +67,8 @@ func ParseAndRequireString(
 			return nil, false, err
 		}
 		d = NewDOid(*i)
+	case types.RangeFamily:
+		return ParseDRangeFromString(ctx, s, t)
 	case types.StringFamily:
 		// If the string type specifies a limit we truncate to that limit:
 		//   'hello'::CHAR(2) -> 'he'
@@ -87,6 +89,8 @@ func ParseAndRequireString(
 		d, err = ParseDUuidFromString(s)
 	case types.EnumFamily:
 		d, err = MakeDEnumFromLogicalRepresentation(t, s)
+	case types.TupleFamily:
+		d, dependsOnContext, err = ParseDTupleFromString(ctx, s, t)
 	default:
 		return nil, false, errors.AssertionFailedf("unknown type %s (%T)", t, t)
 	}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"bytes"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+var anonymousRecordInputError = pgerror.New(pgcode.FeatureNotSupported,
+	"input of anonymous composite types is not implemented")
+
+// ParseDTupleFromString parses the string-form of a record, handling cases
+// such as `'(1,"a b",)'::t`, where t is a composite type. An empty unquoted
+// field is parsed as NULL.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseTimeContext (either for the time or the local timezone).
+func ParseDTupleFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DTuple, dependsOnContext bool, _ error) {
+	if !t.UserDefined() {
+		return nil, false, anonymousRecordInputError
+	}
+	ret, dependsOnContext, err := doParseDTupleFromString(ctx, s, t)
+	if err != nil {
+		return nil, false, pgerror.Wrapf(err, pgcode.InvalidTextRepresentation,
+			"malformed record literal: %q", s)
+	}
+	return ret, dependsOnContext, nil
+}
+
+// doParseDTupleFromString does most of the work of ParseDTupleFromString,
+// except the error it returns isn't prettified as a parsing error.
+func doParseDTupleFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DTuple, dependsOnContext bool, _ error) {
+	contents := t.TupleContents()
+	result := NewDTupleWithLen(t, len(contents))
+
+	i := 0
+	for i < len(s) && unicode.IsSpace(rune(s[i])) {
+		i++
+	}
+	if i >= len(s) || s[i] != '(' {
+		return nil, false, errors.New("missing left parenthesis")
+	}
+	i++
+
+	var buf bytes.Buffer
+	for idx := range contents {
+		// An empty unquoted field is NULL. Note that an empty quoted field is
+		// an empty string instead.
+		if i < len(s) && (s[i] == ',' || s[i] == ')') {
+			result.D[idx] = DNull
+		} else {
+			buf.Reset()
+			inQuote := false
+			for ; i < len(s); i++ {
+				ch := s[i]
+				if !inQuote && (ch == ',' || ch == ')') {
+					break
+				}
+				switch {
+				case ch == '\\':
+					i++
+					if i >= len(s) {
+						return nil, false, errors.New("unexpected end of input")
+					}
+					buf.WriteByte(s[i])
+				case ch == '"' && inQuote && i+1 < len(s) && s[i+1] == '"':
+					// A doubled quote inside a quoted section is a literal quote.
+					buf.WriteByte('"')
+					i++
+				case ch == '"':
+					inQuote = !inQuote
+				default:
+					buf.WriteByte(ch)
+				}
+			}
+			if i >= len(s) {
+				return nil, false, errors.New("unexpected end of input")
+			}
+			d, dep, err := ParseAndRequireString(contents[idx], buf.String(), ctx)
+			if err != nil {
+				return nil, false, err
+			}
+			dependsOnContext = dependsOnContext || dep
+			result.D[idx] = d
+		}
+		if idx < len(contents)-1 {
+			if s[i] != ',' {
+				return nil, false, errors.New("too few columns")
+			}
+			i++
+		}
+	}
+	if i >= len(s) {
+		return nil, false, errors.New("unexpected end of input")
+	}
+	if s[i] != ')' {
+		return nil, false, errors.New("too many columns")
+	}
+	i++
+	for ; i < len(s); i++ {
+		if !unicode.IsSpace(rune(s[i])) {
+			return nil, false, errors.New("junk after right parenthesis")
+		}
+	}
+	return result, dependsOnContext, nil
+}
@@ -132,7 +132,7 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	}
 }
 
-var tupleQuoteSet, arrayQuoteSet asciiSet
+var tupleQuoteSet, arrayQuoteSet, rangeQuoteSet asciiSet
 
 func init() {
 	var ok bool
@@ -144,6 +144,10 @@ func init() {
 	if !ok {
 		panic("array asciiset")
 	}
+	rangeQuoteSet, ok = makeASCIISet(" \t\v\f\r\n()[],\"\\")
+	if !ok {
+		panic("range asciiset")
+	}
 }
 
 func pgwireQuoteStringInTuple(in string) bool {
@@ -188,6 +192,25 @@ func pgwireFormatStringInArray(ctx *FmtCtx, in string) {
 	}
 }
 
+// formatStringInRange writes the text of a range bound, quoting it if
+// necessary. As in tuples, the special double quote and backslash characters
+// are doubled inside of the quotes.
+func formatStringInRange(buf *bytes.Buffer, in string) {
+	quote := in == "" || rangeQuoteSet.in(in)
+	if quote {
+		buf.WriteByte('"')
+	}
+	for _, r := range in {
+		if r == '"' || r == '\\' {
+			buf.WriteByte(byte(r))
+		}
+		buf.WriteRune(r)
+	}
+	if quote {
+		buf.WriteByte('"')
+	}
+}
+
 // From: https://github.com/golang/go/blob/master/src/strings/strings.go
 
 // asciiSet is a 32-byte value, where each bit represents the presence of a
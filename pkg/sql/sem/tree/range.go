@@ -0,0 +1,287 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// rangeBound is one of the two bounds of a non-empty DRange. A DNull val
+// denotes an infinite bound.
+type rangeBound struct {
+	val       Datum
+	inclusive bool
+	lower     bool
+}
+
+func (b rangeBound) infinite() bool {
+	return b.val == DNull
+}
+
+func (d *DRange) lowerBound() rangeBound {
+	return rangeBound{val: d.Lower, inclusive: d.LowerInc, lower: true}
+}
+
+func (d *DRange) upperBound() rangeBound {
+	return rangeBound{val: d.Upper, inclusive: d.UpperInc, lower: false}
+}
+
+// compareRangeBounds compares two range bounds, taking into account whether
+// each of them is a lower or an upper bound and whether it is inclusive. For
+// example, an exclusive lower bound at 5 sorts after an inclusive lower bound
+// at 5 and after an exclusive upper bound at 5.
+func compareRangeBounds(ctx *EvalContext, b1, b2 rangeBound) int {
+	if c, ok := compareInfiniteRangeBounds(b1, b2); ok {
+		return c
+	}
+	c := b1.val.Compare(ctx, b2.val)
+	if c != 0 {
+		return c
+	}
+	switch {
+	case !b1.inclusive && !b2.inclusive:
+		if b1.lower == b2.lower {
+			return 0
+		}
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case !b1.inclusive:
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case !b2.inclusive:
+		if b2.lower {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// compareRangeBoundValues is like compareRangeBounds, but ignores whether the
+// bounds are inclusive.
+func compareRangeBoundValues(ctx *EvalContext, b1, b2 rangeBound) int {
+	if c, ok := compareInfiniteRangeBounds(b1, b2); ok {
+		return c
+	}
+	return b1.val.Compare(ctx, b2.val)
+}
+
+// compareInfiniteRangeBounds compares two range bounds if at least one of
+// them is infinite. ok is false if both bounds are finite.
+func compareInfiniteRangeBounds(b1, b2 rangeBound) (c int, ok bool) {
+	switch {
+	case b1.infinite() && b2.infinite():
+		if b1.lower == b2.lower {
+			return 0, true
+		}
+		if b1.lower {
+			return -1, true
+		}
+		return 1, true
+	case b1.infinite():
+		if b1.lower {
+			return -1, true
+		}
+		return 1, true
+	case b2.infinite():
+		if b2.lower {
+			return 1, true
+		}
+		return -1, true
+	}
+	return 0, false
+}
+
+// rangeBoundsAdjacent returns whether the given upper bound and lower bound
+// touch without overlapping, e.g. the upper bound of [1,5) and the lower
+// bound of [5,10).
+func rangeBoundsAdjacent(ctx *EvalContext, upper, lower rangeBound) bool {
+	if upper.infinite() || lower.infinite() {
+		return false
+	}
+	return compareRangeBoundValues(ctx, upper, lower) == 0 && upper.inclusive != lower.inclusive
+}
+
+// makeRangeFromBounds returns a range of the same type as d with the given
+// bounds.
+func (d *DRange) makeRangeFromBounds(ctx *EvalContext, lower, upper rangeBound) (*DRange, error) {
+	return NewDRange(ctx, d.typ, lower.val, upper.val, lower.inclusive, upper.inclusive)
+}
+
+// Contains returns whether every value in o is also contained in d. The
+// empty range is contained in every range.
+func (d *DRange) Contains(ctx *EvalContext, o *DRange) bool {
+	if o.Empty {
+		return true
+	}
+	if d.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.lowerBound(), o.lowerBound()) <= 0 &&
+		compareRangeBounds(ctx, d.upperBound(), o.upperBound()) >= 0
+}
+
+// ContainsElem returns whether the range contains the given value of its
+// subtype.
+func (d *DRange) ContainsElem(ctx *EvalContext, elem Datum) bool {
+	if d.Empty {
+		return false
+	}
+	if d.Lower != DNull {
+		c := d.Lower.Compare(ctx, elem)
+		if c > 0 || (c == 0 && !d.LowerInc) {
+			return false
+		}
+	}
+	if d.Upper != DNull {
+		c := d.Upper.Compare(ctx, elem)
+		if c < 0 || (c == 0 && !d.UpperInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps returns whether the two ranges have any value in common.
+func (d *DRange) Overlaps(ctx *EvalContext, o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	if compareRangeBounds(ctx, d.lowerBound(), o.lowerBound()) >= 0 {
+		return compareRangeBounds(ctx, d.lowerBound(), o.upperBound()) <= 0
+	}
+	return compareRangeBounds(ctx, o.lowerBound(), d.upperBound()) <= 0
+}
+
+// StrictlyLeftOf returns whether every value in d is less than every value
+// in o.
+func (d *DRange) StrictlyLeftOf(ctx *EvalContext, o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.upperBound(), o.lowerBound()) < 0
+}
+
+// StrictlyRightOf returns whether every value in d is greater than every
+// value in o.
+func (d *DRange) StrictlyRightOf(ctx *EvalContext, o *DRange) bool {
+	return o.StrictlyLeftOf(ctx, d)
+}
+
+// DoesNotExtendRightOf returns whether no value in d is greater than every
+// value in o.
+func (d *DRange) DoesNotExtendRightOf(ctx *EvalContext, o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.upperBound(), o.upperBound()) <= 0
+}
+
+// DoesNotExtendLeftOf returns whether no value in d is less than every value
+// in o.
+func (d *DRange) DoesNotExtendLeftOf(ctx *EvalContext, o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.lowerBound(), o.lowerBound()) >= 0
+}
+
+// Adjacent returns whether the two ranges touch without overlapping.
+func (d *DRange) Adjacent(ctx *EvalContext, o *DRange) bool {
+	if d.Empty || o.Empty {
+		return false
+	}
+	return rangeBoundsAdjacent(ctx, d.upperBound(), o.lowerBound()) ||
+		rangeBoundsAdjacent(ctx, o.upperBound(), d.lowerBound())
+}
+
+// Union returns the union of the two ranges. An error is returned if the
+// union is not a contiguous range.
+func (d *DRange) Union(ctx *EvalContext, o *DRange) (*DRange, error) {
+	if !d.Empty && !o.Empty && !d.Overlaps(ctx, o) && !d.Adjacent(ctx, o) {
+		return nil, pgerror.New(pgcode.DataException,
+			"result of range union would not be contiguous")
+	}
+	return d.Merge(ctx, o)
+}
+
+// Merge returns the smallest range that contains both ranges.
+func (d *DRange) Merge(ctx *EvalContext, o *DRange) (*DRange, error) {
+	if d.Empty {
+		return o, nil
+	}
+	if o.Empty {
+		return d, nil
+	}
+	lower, upper := d.lowerBound(), d.upperBound()
+	if compareRangeBounds(ctx, o.lowerBound(), lower) < 0 {
+		lower = o.lowerBound()
+	}
+	if compareRangeBounds(ctx, o.upperBound(), upper) > 0 {
+		upper = o.upperBound()
+	}
+	return d.makeRangeFromBounds(ctx, lower, upper)
+}
+
+// Intersect returns the range of values contained in both ranges.
+func (d *DRange) Intersect(ctx *EvalContext, o *DRange) (*DRange, error) {
+	if !d.Overlaps(ctx, o) {
+		return NewDEmptyRange(d.typ), nil
+	}
+	lower, upper := d.lowerBound(), d.upperBound()
+	if compareRangeBounds(ctx, o.lowerBound(), lower) > 0 {
+		lower = o.lowerBound()
+	}
+	if compareRangeBounds(ctx, o.upperBound(), upper) < 0 {
+		upper = o.upperBound()
+	}
+	return d.makeRangeFromBounds(ctx, lower, upper)
+}
+
+// Minus returns the range of values contained in d but not in o. An error is
+// returned if the result is not a contiguous range.
+func (d *DRange) Minus(ctx *EvalContext, o *DRange) (*DRange, error) {
+	if d.Empty || o.Empty {
+		return d, nil
+	}
+	cmpL1L2 := compareRangeBounds(ctx, d.lowerBound(), o.lowerBound())
+	cmpL1U2 := compareRangeBounds(ctx, d.lowerBound(), o.upperBound())
+	cmpU1L2 := compareRangeBounds(ctx, d.upperBound(), o.lowerBound())
+	cmpU1U2 := compareRangeBounds(ctx, d.upperBound(), o.upperBound())
+
+	switch {
+	case cmpL1L2 < 0 && cmpU1U2 > 0:
+		// o is strictly inside of d, which would split d in two.
+		return nil, pgerror.New(pgcode.DataException,
+			"result of range difference would not be contiguous")
+	case cmpL1U2 > 0 || cmpU1L2 < 0:
+		// The ranges do not overlap.
+		return d, nil
+	case cmpL1L2 >= 0 && cmpU1U2 <= 0:
+		// d is entirely contained in o.
+		return NewDEmptyRange(d.typ), nil
+	case cmpL1L2 <= 0 && cmpU1L2 >= 0 && cmpU1U2 <= 0:
+		// o covers the upper part of d.
+		upper := o.lowerBound()
+		upper.inclusive, upper.lower = !upper.inclusive, false
+		return d.makeRangeFromBounds(ctx, d.lowerBound(), upper)
+	default:
+		// o covers the lower part of d.
+		lower := o.upperBound()
+		lower.inclusive, lower.lower = !lower.inclusive, true
+		return d.makeRangeFromBounds(ctx, lower, d.upperBound())
+	}
+}
@@ -74,3 +74,81 @@ func (node *ScheduledBackup) Format(ctx *FmtCtx) {
 		node.ScheduleOptions.Format(ctx)
 	}
 }
+
+// ScheduledExport represents scheduled export job.
+type ScheduledExport struct {
+	ScheduleLabel   Expr
+	Recurrence      Expr
+	Export          *Export
+	ScheduleOptions KVOptions
+}
+
+var _ Statement = &ScheduledExport{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledExport) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE")
+
+	if node.ScheduleLabel != nil {
+		ctx.WriteString(" ")
+		node.ScheduleLabel.Format(ctx)
+	}
+
+	ctx.WriteString(" FOR ")
+	ctx.FormatNode(node.Export)
+
+	ctx.WriteString(" RECURRING ")
+	if node.Recurrence == nil {
+		ctx.WriteString("NEVER")
+	} else {
+		node.Recurrence.Format(ctx)
+	}
+
+	if node.ScheduleOptions != nil {
+		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
+		node.ScheduleOptions.Format(ctx)
+	}
+}
+
+// ScheduledChangefeed represents scheduled changefeed job.
+type ScheduledChangefeed struct {
+	ScheduleLabel    Expr
+	Recurrence       Expr
+	CreateChangefeed *CreateChangefeed
+	ScheduleOptions  KVOptions
+}
+
+var _ Statement = &ScheduledChangefeed{}
+
+// Format implements the NodeFormatter interface.
+func (node *ScheduledChangefeed) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SCHEDULE")
+
+	if node.ScheduleLabel != nil {
+		ctx.WriteString(" ")
+		node.ScheduleLabel.Format(ctx)
+	}
+
+	ctx.WriteString(" FOR CHANGEFEED ")
+	ctx.FormatNode(&node.CreateChangefeed.Targets)
+
+	ctx.WriteString(" INTO ")
+	ctx.FormatNode(node.CreateChangefeed.SinkURI)
+
+	if node.CreateChangefeed.Options != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.CreateChangefeed.Options)
+	}
+
+	ctx.WriteString(" RECURRING ")
+	if node.Recurrence == nil {
+		ctx.WriteString("NEVER")
+	} else {
+		node.Recurrence.Format(ctx)
+	}
+
+	if node.ScheduleOptions != nil {
+		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
+		node.ScheduleOptions.Format(ctx)
+	}
+}
@@ -570,6 +570,57 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType is the type of a GroupingSet.
+type GroupingSetType int
+
+const (
+	// RollupGroupingSet represents ROLLUP (a, b, ...), which is equivalent to
+	// GROUPING SETS ((a, b, ...), ..., (a), ()).
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet represents CUBE (a, b, ...), which is equivalent to
+	// GROUPING SETS over all the subsets of its arguments.
+	CubeGroupingSet
+	// ExplicitGroupingSet represents GROUPING SETS (...).
+	ExplicitGroupingSet
+)
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. A tuple among the Exprs is a composite element which is grouped on
+// as a unit; for GROUPING SETS, the Exprs can also contain nested grouping
+// sets.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	switch node.Type {
+	case RollupGroupingSet:
+		ctx.WriteString("ROLLUP (")
+	case CubeGroupingSet:
+		ctx.WriteString("CUBE (")
+	case ExplicitGroupingSet:
+		ctx.WriteString("GROUPING SETS (")
+	}
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// GroupingExpr represents a GROUPING (a, b, ...) operation, which returns a
+// bit mask indicating which of its arguments are not grouped on in the current
+// grouping set.
+type GroupingExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -639,6 +690,16 @@ func (n NullsOrder) String() string {
 	return nullsOrderName[n]
 }
 
+// IsReversed returns true if the NULLs order is the opposite of the default
+// for the given direction. By default, NULLs sort as the smallest values: first
+// in ascending orderings and last in descending orderings.
+func (n NullsOrder) IsReversed(dir Direction) bool {
+	if dir == Descending {
+		return n == NullsFirst
+	}
+	return n == NullsLast
+}
+
 // OrderType indicates which type of expression is used in ORDER BY.
 type OrderType int
 
@@ -422,6 +422,32 @@ func isCastDeepValid(castFrom, castTo *types.T) (bool, telemetry.Counter, Volati
 	case toFamily == types.EnumFamily && fromFamily == types.EnumFamily:
 		// Casts from ENUM to ENUM type can only succeed if the two enums
 		return castFrom.Equivalent(castTo), sqltelemetry.EnumCastCounter, VolatilityImmutable
+	case toFamily == types.TupleFamily && fromFamily == types.TupleFamily && castTo.UserDefined():
+		// Casts to a composite type are valid if each element can be cast to
+		// the corresponding attribute type.
+		fromContents, toContents := castFrom.TupleContents(), castTo.TupleContents()
+		if types.IsWildcardTupleType(castFrom) || len(fromContents) != len(toContents) {
+			return false, nil, 0
+		}
+		volatility := VolatilityLeakProof
+		for i := range fromContents {
+			if fromContents[i].Family() == types.UnknownFamily {
+				continue
+			}
+			ok, _, v := isCastDeepValid(fromContents[i], toContents[i])
+			if !ok {
+				return false, nil, 0
+			}
+			if v > volatility {
+				volatility = v
+			}
+		}
+		return true, sqltelemetry.CastOpCounter("tuple", "composite"), volatility
+	case toFamily == types.RangeFamily && fromFamily == types.RangeFamily:
+		// Ranges can only be cast to ranges over the same subtype.
+		if castFrom.Oid() != castTo.Oid() {
+			return false, nil, 0
+		}
 	}
 
 	cast := lookupCast(fromFamily, toFamily)
@@ -466,6 +492,13 @@ func (expr *CastExpr) TypeCheck(
 		// the child of a cast, or was the child of a cast to a different type.
 		// In this case, we default to inferring a STRING for the placeholder.
 		desired = types.String
+	case exprType.Family() == types.TupleFamily && exprType.UserDefined():
+		// A tuple cast to a composite type is typed with the composite type's
+		// attribute types as desired types, like an INSERT into a column of the
+		// composite type would.
+		if _, ok := expr.Expr.(*Tuple); ok {
+			desired = exprType
+		}
 	case isEmptyArray(expr.Expr):
 		// An empty array can't be type-checked with a desired parameter of
 		// types.Any. If we're going to cast to another array type, which is a
@@ -755,6 +788,8 @@ var (
 	errInvalidDefaultUsage = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
 	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
 	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
+	errInvalidGroupingSet  = pgerror.New(pgcode.Syntax, "ROLLUP, CUBE and GROUPING SETS can only appear in a GROUP BY clause")
+	errInvalidGroupingExpr = pgerror.New(pgcode.Grouping, "GROUPING can only appear in a query with a GROUP BY clause")
 	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
 )
 
@@ -1384,6 +1419,20 @@ func (expr PartitionMaxVal) TypeCheck(
 	return nil, errInvalidMaxUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSet
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *GroupingExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingExpr
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *NumVal) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
@@ -1432,6 +1481,13 @@ func (expr *Tuple) TypeCheck(
 		}
 	}
 	expr.typ = types.MakeLabeledTuple(contents, labels)
+	// An unlabeled tuple with the shape of a desired composite type is typed
+	// as the composite type, so that it can be used wherever a value of the
+	// composite type is expected.
+	if desired.Family() == types.TupleFamily && desired.UserDefined() && labels == nil &&
+		expr.typ.EquivalentOrNull(desired) {
+		expr.typ = desired
+	}
 	return expr, nil
 }
 
@@ -1641,6 +1697,12 @@ func (d *DBox2D) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (Typed
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DRange) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DGeography) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
@@ -0,0 +1,327 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	FuncName   *UnresolvedObjectName
+	Replace    bool
+	Params     FuncParams
+	ReturnType ResolvableTypeReference
+	Options    FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(") RETURNS ")
+	ctx.FormatTypeReference(node.ReturnType)
+	if len(node.Options) > 0 {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.Options)
+	}
+}
+
+// FuncParam represents a parameter in a CREATE FUNCTION or DROP FUNCTION
+// statement. The name is optional.
+type FuncParam struct {
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParam) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncParams is a list of FuncParam.
+type FuncParams []FuncParam
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParams) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOption is an option of a CREATE FUNCTION statement. The options
+// may be given in any order; conflicting or repeated options are rejected
+// when the statement is planned.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionLanguage) functionOption()          {}
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakProof) functionOption()         {}
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionBody) functionOption()              {}
+
+// FunctionOptions is a list of FunctionOption.
+type FunctionOptions []FunctionOption
+
+// Format implements the NodeFormatter interface.
+func (node *FunctionOptions) Format(ctx *FmtCtx) {
+	for i, opt := range *node {
+		if i > 0 {
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatNode(opt)
+	}
+}
+
+// FunctionOptionValues holds the effective options of a CREATE FUNCTION
+// statement.
+type FunctionOptionValues struct {
+	Volatility        FunctionVolatility
+	LeakProof         bool
+	NullInputBehavior FunctionNullInputBehavior
+	Body              string
+}
+
+// Values returns the effective values of the options, using the defaults for
+// the options that were not specified. It returns an error if an option is
+// specified more than once, if the options contradict each other, or if the
+// function body is missing.
+func (node FunctionOptions) Values() (FunctionOptionValues, error) {
+	var res FunctionOptionValues
+	var seenLang, seenVolatility, seenLeakProof, seenNullInput, seenBody bool
+	conflict := func(seen *bool) error {
+		if *seen {
+			return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		*seen = true
+		return nil
+	}
+	for _, opt := range node {
+		var err error
+		switch t := opt.(type) {
+		case FunctionLanguage:
+			if err = conflict(&seenLang); err == nil && t != FunctionLanguageSQL {
+				if t == "plpgsql" {
+					return res, unimplemented.NewWithIssue(17511, "PL/pgSQL functions are not supported")
+				}
+				return res, pgerror.Newf(pgcode.UndefinedObject, "language %q does not exist", string(t))
+			}
+		case FunctionVolatility:
+			err = conflict(&seenVolatility)
+			res.Volatility = t
+		case FunctionLeakProof:
+			err = conflict(&seenLeakProof)
+			res.LeakProof = bool(t)
+		case FunctionNullInputBehavior:
+			err = conflict(&seenNullInput)
+			res.NullInputBehavior = t
+		case FunctionBody:
+			err = conflict(&seenBody)
+			res.Body = string(t)
+		}
+		if err != nil {
+			return res, err
+		}
+	}
+	if !seenBody {
+		return res, pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	if res.LeakProof && res.Volatility != FunctionImmutable {
+		return res, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"cannot create leakproof function with non-immutable volatility: %s", res.Volatility)
+	}
+	return res, nil
+}
+
+// TreeVolatility returns the declared volatility of the function as a
+// Volatility.
+func (v FunctionOptionValues) TreeVolatility() Volatility {
+	switch v.Volatility {
+	case FunctionImmutable:
+		if v.LeakProof {
+			return VolatilityLeakProof
+		}
+		return VolatilityImmutable
+	case FunctionStable:
+		return VolatilityStable
+	default:
+		return VolatilityVolatile
+	}
+}
+
+// IsStrict returns true if the function returns NULL without being evaluated
+// when any of its arguments is NULL.
+func (v FunctionOptionValues) IsStrict() bool {
+	return v.NullInputBehavior != FunctionCalledOnNullInput
+}
+
+// FunctionLanguage is the LANGUAGE option of a function.
+type FunctionLanguage string
+
+// FunctionLanguageSQL is the only language supported for user-defined
+// functions.
+const FunctionLanguageSQL FunctionLanguage = "sql"
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	ctx.WriteString("LANGUAGE ")
+	name := Name(node)
+	ctx.FormatNode(&name)
+}
+
+// FunctionVolatility is the IMMUTABLE, STABLE or VOLATILE option of a
+// function.
+type FunctionVolatility int
+
+// FunctionVolatility values.
+const (
+	FunctionVolatile FunctionVolatility = iota
+	FunctionStable
+	FunctionImmutable
+)
+
+var functionVolatilityName = [...]string{
+	FunctionVolatile:  "VOLATILE",
+	FunctionStable:    "STABLE",
+	FunctionImmutable: "IMMUTABLE",
+}
+
+func (node FunctionVolatility) String() string {
+	return functionVolatilityName[node]
+}
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.String())
+}
+
+// FunctionLeakProof is the [NOT] LEAKPROOF option of a function.
+type FunctionLeakProof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakProof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionNullInputBehavior is the option of a function that determines
+// whether it is called when some of its arguments are NULL.
+type FunctionNullInputBehavior int
+
+// FunctionNullInputBehavior values.
+const (
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	FunctionReturnsNullOnNullInput
+	// FunctionStrict is a synonym of FunctionReturnsNullOnNullInput. It is
+	// kept separate so that the statement can be formatted the way it was
+	// written.
+	FunctionStrict
+)
+
+var functionNullInputBehaviorName = [...]string{
+	FunctionCalledOnNullInput:      "CALLED ON NULL INPUT",
+	FunctionReturnsNullOnNullInput: "RETURNS NULL ON NULL INPUT",
+	FunctionStrict:                 "STRICT",
+}
+
+func (node FunctionNullInputBehavior) String() string {
+	return functionNullInputBehaviorName[node]
+}
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.String())
+}
+
+// FunctionBody is the AS option of a function, which holds the SQL text of
+// the function body.
+type FunctionBody string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBody) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	lex.EncodeSQLStringWithFlags(&ctx.Buffer, string(node), ctx.flags.EncodeFlags())
+}
+
+// FuncObj identifies a function in a DROP FUNCTION statement. Params is nil
+// if the statement did not include a parameter list.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	Params   FuncParams
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.Params != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(&node.Params)
+		ctx.WriteByte(')')
+	}
+}
+
+// FuncObjs is a list of FuncObj.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Functions    FuncObjs
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
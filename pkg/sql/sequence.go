@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -29,7 +30,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/sequence"
 	"github.com/cockroachdb/errors"
@@ -92,18 +92,10 @@ func (p *planner) IncrementSequence(ctx context.Context, seqName *tree.TableName
 		rowid := builtins.GenerateUniqueInt(p.EvalContext().NodeID.SQLInstanceID())
 		val = int64(rowid)
 	} else {
-		seqValueKey := p.ExecCfg().Codec.SequenceKey(uint32(descriptor.ID))
-		val, err = kv.IncrementValRetryable(
-			ctx, p.txn.DB(), seqValueKey, seqOpts.Increment)
+		val, err = p.incrementSequenceUsingCache(ctx, descriptor)
 		if err != nil {
-			if errors.HasType(err, (*roachpb.IntegerOverflowError)(nil)) {
-				return 0, boundsExceededError(descriptor)
-			}
 			return 0, err
 		}
-		if val > seqOpts.MaxValue || val < seqOpts.MinValue {
-			return 0, boundsExceededError(descriptor)
-		}
 	}
 
 	p.ExtendedEvalContext().SessionMutator.RecordLatestSequenceVal(uint32(descriptor.ID), val)
@@ -111,6 +103,175 @@ func (p *planner) IncrementSequence(ctx context.Context, seqName *tree.TableName
 	return val, nil
 }
 
+// incrementSequenceUsingCache returns the next value of a non-virtual
+// sequence. If the cache size of the sequence is greater than 1, the value is
+// taken from the values cached by the session, and a block of cache size
+// values is fetched from KV when the cache runs out.
+func (p *planner) incrementSequenceUsingCache(
+	ctx context.Context, descriptor *tabledesc.Immutable,
+) (int64, error) {
+	seqOpts := descriptor.SequenceOpts
+	cacheSize := seqOpts.EffectiveCacheSize()
+	fetchNextValues := func() (start, increment, count int64, err error) {
+		if seqOpts.Cycle {
+			start, count, err = p.fetchCyclicSequenceValues(ctx, descriptor, cacheSize)
+		} else {
+			start, count, err = p.fetchSequenceValues(ctx, descriptor, cacheSize)
+		}
+		return start, seqOpts.Increment, count, err
+	}
+
+	if cacheSize == 1 {
+		val, _, _, err := fetchNextValues()
+		return val, err
+	}
+	return p.SessionData().SequenceState.NextCachedValue(
+		uint32(descriptor.ID), uint32(descriptor.Version), fetchNextValues,
+	)
+}
+
+// fetchSequenceValues reserves up to cacheSize consecutive values of a
+// sequence that does not cycle by incrementing its value in KV. It returns the
+// first of the values and the number of values that were reserved, which is
+// less than cacheSize if the sequence reached its bound.
+func (p *planner) fetchSequenceValues(
+	ctx context.Context, descriptor *tabledesc.Immutable, cacheSize int64,
+) (start, count int64, _ error) {
+	seqOpts := descriptor.SequenceOpts
+	// Reserve fewer values if incrementing by the whole block would overflow.
+	count = cacheSize
+	if maxCount := math.MaxInt64 / absInt64(seqOpts.Increment); count > maxCount {
+		count = maxCount
+	}
+	if count < 1 {
+		count = 1
+	}
+	seqValueKey := p.ExecCfg().Codec.SequenceKey(uint32(descriptor.ID))
+	var end int64
+	for {
+		var err error
+		end, err = kv.IncrementValRetryable(
+			ctx, p.txn.DB(), seqValueKey, seqOpts.Increment*count)
+		if err == nil {
+			break
+		}
+		if !errors.HasType(err, (*roachpb.IntegerOverflowError)(nil)) {
+			return 0, 0, err
+		}
+		// Fewer than count values are left before the value overflows. Reserve
+		// only the values that are left, if any.
+		res, err := p.txn.DB().Get(ctx, seqValueKey)
+		if err != nil {
+			return 0, 0, err
+		}
+		overflowLimit := int64(math.MaxInt64)
+		if seqOpts.Increment < 0 {
+			overflowLimit = math.MinInt64
+		}
+		left := numSequenceValues(res.ValueInt(), overflowLimit, seqOpts.Increment) - 1
+		if left == 0 {
+			return 0, 0, boundsExceededError(descriptor)
+		}
+		if left < count {
+			count = left
+		}
+	}
+	start = end - seqOpts.Increment*(count-1)
+	if start > seqOpts.MaxValue || start < seqOpts.MinValue {
+		return 0, 0, boundsExceededError(descriptor)
+	}
+	if end > seqOpts.MaxValue || end < seqOpts.MinValue {
+		// Only the values up to the bound of the sequence can be handed out.
+		count = numSequenceValues(start, sequenceLimit(seqOpts), seqOpts.Increment)
+	}
+	return start, count, nil
+}
+
+// fetchCyclicSequenceValues reserves up to cacheSize consecutive values of a
+// sequence that cycles. If the sequence has reached its bound, it wraps around
+// to the other bound. It returns the first of the values and the number of
+// values that were reserved, which is less than cacheSize if the sequence
+// reached its bound.
+//
+// Unlike fetchSequenceValues, the value of the sequence cannot be incremented
+// blindly, since it has to be reset when it wraps around. It is read and
+// written in a separate transaction instead.
+func (p *planner) fetchCyclicSequenceValues(
+	ctx context.Context, descriptor *tabledesc.Immutable, cacheSize int64,
+) (start, count int64, _ error) {
+	seqOpts := descriptor.SequenceOpts
+	limit := sequenceLimit(seqOpts)
+	restart := seqOpts.MinValue
+	if seqOpts.Increment < 0 {
+		restart = seqOpts.MaxValue
+	}
+	seqValueKey := p.ExecCfg().Codec.SequenceKey(uint32(descriptor.ID))
+	err := p.txn.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		res, err := txn.Get(ctx, seqValueKey)
+		if err != nil {
+			return err
+		}
+		cur := res.ValueInt()
+		// remaining is the number of values after cur that do not go past the
+		// bound.
+		var remaining int64
+		if (seqOpts.Increment > 0 && cur < limit) || (seqOpts.Increment < 0 && cur > limit) {
+			remaining = numSequenceValues(cur, limit, seqOpts.Increment) - 1
+		}
+		start = cur + seqOpts.Increment
+		// Wrap around if there are no values left, or if the next value is out
+		// of the range of the sequence because its bounds were altered.
+		if remaining == 0 || start < seqOpts.MinValue || start > seqOpts.MaxValue {
+			start = restart
+			remaining = numSequenceValues(restart, limit, seqOpts.Increment)
+		}
+		count = cacheSize
+		if count > remaining {
+			count = remaining
+		}
+		return txn.Put(ctx, seqValueKey, start+seqOpts.Increment*(count-1))
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, count, nil
+}
+
+// sequenceLimit returns the bound of the sequence in the direction of its
+// increment.
+func sequenceLimit(seqOpts *descpb.TableDescriptor_SequenceOpts) int64 {
+	if seqOpts.Increment < 0 {
+		return seqOpts.MinValue
+	}
+	return seqOpts.MaxValue
+}
+
+// numSequenceValues returns the number of values of a sequence, starting at
+// start and increment apart, that do not go past limit. start must not be past
+// limit. The result saturates at math.MaxInt64.
+func numSequenceValues(start, limit, increment int64) int64 {
+	// The differences are computed as unsigned integers, since they can
+	// overflow an int64.
+	var n uint64
+	if increment > 0 {
+		n = uint64(limit-start) / uint64(increment)
+	} else {
+		n = uint64(start-limit) / uint64(-increment)
+	}
+	if n >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(n) + 1
+}
+
+// absInt64 returns the absolute value of x.
+func absInt64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 func boundsExceededError(descriptor *tabledesc.Immutable) error {
 	seqOpts := descriptor.SequenceOpts
 	isAscending := seqOpts.Increment > 0
@@ -182,6 +343,11 @@ func (p *planner) SetSequenceValue(
 		return err
 	}
 
+	// The values cached by this session were reserved before the new value was
+	// set, so they must not be handed out anymore. Other sessions keep using
+	// their cached values, like in Postgres.
+	p.SessionData().SequenceState.InvalidateCachedValues(uint32(descriptor.ID))
+
 	// TODO(vilterp): not supposed to mix usage of Inc and Put on a key,
 	// according to comments on Inc operation. Switch to Inc if `desired-current`
 	// overflows correctly.
@@ -228,6 +394,21 @@ func readOnlyError(s string) error {
 		"cannot execute %s in a read-only transaction", s)
 }
 
+// checkSequenceOptionVersion returns an error if the cluster version is too
+// old for the nodes to honor the given sequence option. Sequences created by
+// IMPORT have no params and are not checked.
+func checkSequenceOptionVersion(params *runParams, option string) error {
+	if params == nil {
+		return nil
+	}
+	if !params.p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.SequenceCacheAndCycle) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use sequence option %s",
+			clusterversion.SequenceCacheAndCycle, option)
+	}
+	return nil
+}
+
 // assignSequenceOptions moves options from the AST node to the sequence options descriptor,
 // starting with defaults and overriding them with user-provided options.
 func assignSequenceOptions(
@@ -262,6 +443,7 @@ func assignSequenceOptions(
 			opts.MaxValue = -1
 			opts.Start = opts.MaxValue
 		}
+		opts.CacheSize = 1
 	}
 
 	// Fill in all other options.
@@ -276,22 +458,22 @@ func assignSequenceOptions(
 
 		switch option.Name {
 		case tree.SeqOptCycle:
-			return unimplemented.NewWithIssue(20961,
-				"CYCLE option is not supported")
+			if err := checkSequenceOptionVersion(params, "CYCLE"); err != nil {
+				return err
+			}
+			opts.Cycle = true
 		case tree.SeqOptNoCycle:
-			// Do nothing; this is the default.
+			opts.Cycle = false
 		case tree.SeqOptCache:
-			v := *option.IntVal
-			switch {
-			case v < 1:
+			if v := *option.IntVal; v < 1 {
 				return pgerror.Newf(pgcode.InvalidParameterValue,
 					"CACHE (%d) must be greater than zero", v)
-			case v == 1:
-				// Do nothing; this is the default.
-			case v > 1:
-				return unimplemented.NewWithIssuef(32567,
-					"CACHE values larger than 1 are not supported, found %d", v)
+			} else if v > 1 {
+				if err := checkSequenceOptionVersion(params, "CACHE"); err != nil {
+					return err
+				}
 			}
+			opts.CacheSize = *option.IntVal
 		case tree.SeqOptIncrement:
 			// Do nothing; this has already been set.
 		case tree.SeqOptMinValue:
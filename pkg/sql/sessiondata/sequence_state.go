@@ -30,9 +30,28 @@ type SequenceState struct {
 		// lastSequenceIncremented records the descriptor id of the last sequence
 		// nextval() was called on in this session.
 		lastSequenceIncremented uint32
+
+		// cache stores the values of sequences with a cache size greater than 1
+		// that were fetched by this session but not handed out yet, by
+		// descriptor id.
+		cache map[uint32]*sequenceCacheEntry
 	}
 }
 
+// sequenceCacheEntry stores the cached values of a sequence.
+type sequenceCacheEntry struct {
+	// version is the descriptor version of the sequence that the values were
+	// fetched with. The values are discarded when a different version of the
+	// descriptor is used, since the options of the sequence may have changed.
+	version uint32
+	// next is the next value to hand out.
+	next int64
+	// increment is the difference between consecutive values.
+	increment int64
+	// remaining is the number of values left in the cache, including next.
+	remaining int64
+}
+
 // NewSequenceState creates a SequenceState.
 func NewSequenceState() *SequenceState {
 	ss := SequenceState{}
@@ -104,3 +123,49 @@ func (ss *SequenceState) Export() (map[uint32]int64, uint32) {
 	}
 	return res, ss.mu.lastSequenceIncremented
 }
+
+// NextCachedValue returns the next value of a sequence from the values cached
+// by this session. If no values are cached for the sequence, or they were
+// cached for a different descriptor version, fetch is called to obtain a new
+// block of values: count values starting at start, increment apart.
+func (ss *SequenceState) NextCachedValue(
+	seqID uint32, version uint32, fetch func() (start, increment, count int64, err error),
+) (int64, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.mu.cache == nil {
+		ss.mu.cache = make(map[uint32]*sequenceCacheEntry)
+	}
+	entry, ok := ss.mu.cache[seqID]
+	if !ok {
+		entry = &sequenceCacheEntry{}
+		ss.mu.cache[seqID] = entry
+	}
+	if entry.remaining == 0 || entry.version != version {
+		start, increment, count, err := fetch()
+		if err != nil {
+			return 0, err
+		}
+		*entry = sequenceCacheEntry{
+			version:   version,
+			next:      start,
+			increment: increment,
+			remaining: count,
+		}
+	}
+	val := entry.next
+	entry.remaining--
+	if entry.remaining > 0 {
+		entry.next += entry.increment
+	}
+	return val, nil
+}
+
+// InvalidateCachedValues discards the values of the given sequence cached by
+// this session.
+func (ss *SequenceState) InvalidateCachedValues(seqID uint32) {
+	ss.mu.Lock()
+	delete(ss.mu.cache, seqID)
+	ss.mu.Unlock()
+}
@@ -34,6 +34,9 @@ type setVarNode struct {
 	v    sessionVar
 	// typedValues == nil means RESET.
 	typedValues []tree.TypedExpr
+	// local is set for SET LOCAL, which only changes the variable for the rest
+	// of the current transaction.
+	local bool
 }
 
 // SetVar sets session variables.
@@ -94,7 +97,7 @@ func (p *planner) SetVar(ctx context.Context, n *tree.SetVar) (planNode, error)
 		}
 	}
 
-	return &setVarNode{name: name, v: v, typedValues: typedValues}, nil
+	return &setVarNode{name: name, v: v, typedValues: typedValues, local: n.Local}, nil
 }
 
 func (n *setVarNode) startExec(params runParams) error {
@@ -130,10 +133,39 @@ func (n *setVarNode) startExec(params runParams) error {
 		_, strVal = getSessionVarDefaultString(n.name, n.v, params.p.sessionDataMutator)
 	}
 
-	if n.v.RuntimeSet != nil {
-		return n.v.RuntimeSet(params.ctx, params.extendedEvalCtx, strVal)
+	return params.p.setSessionVarValue(params.ctx, n.name, n.v, strVal, n.local)
+}
+
+// setSessionVarValue changes the value of a session variable. If local is set,
+// the previous value of the variable is restored when the current transaction
+// ends.
+func (p *planner) setSessionVarValue(
+	ctx context.Context, name string, v sessionVar, val string, local bool,
+) error {
+	if v.RuntimeSet != nil {
+		// Variables that can only be set at runtime, like
+		// transaction_isolation, are already scoped to the transaction.
+		return v.RuntimeSet(ctx, &p.extendedEvalCtx, val)
+	}
+	if !local {
+		p.localSessionVars.overrideLocal(name)
+		return v.Set(ctx, p.sessionDataMutator, val)
+	}
+
+	if p.EvalContext().TxnImplicit {
+		// The variable is restored when the implicit transaction of the
+		// statement ends, so SET LOCAL has no visible effect.
+		p.BufferClientNotice(
+			ctx,
+			pgnotice.NewWithSeverityf("WARNING", "SET LOCAL can only be used in transaction blocks"),
+		)
+	}
+	prevVal := v.Get(&p.extendedEvalCtx)
+	if err := v.Set(ctx, p.sessionDataMutator, val); err != nil {
+		return err
 	}
-	return n.v.Set(params.ctx, params.p.sessionDataMutator, strVal)
+	p.localSessionVars.recordLocal(name, prevVal)
+	return nil
 }
 
 // getSessionVarDefaultString retrieves a string suitable to pass to a
@@ -359,3 +391,81 @@ func newCannotChangeParameterError(varName string) error {
 	return pgerror.Newf(pgcode.CantChangeRuntimeParam,
 		"parameter %q cannot be changed", varName)
 }
+
+// localSessionVars gives a planner access to the session variables changed by
+// SET LOCAL in the current transaction.
+type localSessionVars interface {
+	// recordLocal records the value that a session variable had before SET
+	// LOCAL changed it, so that the value can be restored when the
+	// transaction ends.
+	recordLocal(name, prevVal string)
+	// overrideLocal is called when a session variable is changed by SET, which
+	// makes the new value outlive the transaction even if the variable was
+	// changed by SET LOCAL before.
+	overrideLocal(name string)
+}
+
+// localSessionVarChange is a change of a session variable made by SET LOCAL.
+type localSessionVarChange struct {
+	name string
+	// prevVal is the value of the variable before the change.
+	prevVal string
+	// overridden is set if the variable was changed by SET afterwards, in
+	// which case prevVal must not be restored.
+	overridden bool
+}
+
+// localSessionVarStack is the localSessionVars implementation of a
+// connExecutor. It holds the changes made by SET LOCAL in the current
+// transaction, in the order in which they were made.
+type localSessionVarStack struct {
+	changes []localSessionVarChange
+}
+
+var _ localSessionVars = &localSessionVarStack{}
+
+func (s *localSessionVarStack) recordLocal(name, prevVal string) {
+	s.changes = append(s.changes, localSessionVarChange{name: name, prevVal: prevVal})
+}
+
+func (s *localSessionVarStack) overrideLocal(name string) {
+	for i := range s.changes {
+		if s.changes[i].name == name {
+			s.changes[i].overridden = true
+		}
+	}
+}
+
+// len returns the number of changes made so far. It is recorded by savepoints
+// so that the changes made after them can be undone.
+func (s *localSessionVarStack) len() int {
+	return len(s.changes)
+}
+
+// restore undoes the changes made after the first n changes, in reverse order,
+// and forgets them.
+func (s *localSessionVarStack) restore(ctx context.Context, m *sessionDataMutator, n int) error {
+	for i := len(s.changes) - 1; i >= n; i-- {
+		c := &s.changes[i]
+		if c.overridden {
+			continue
+		}
+		if err := varGen[c.name].Set(ctx, m, c.prevVal); err != nil {
+			return errors.NewAssertionErrorWithWrappedErrf(err,
+				"restoring session variable %q", c.name)
+		}
+	}
+	s.changes = s.changes[:n]
+	return nil
+}
+
+// emptyLocalSessionVars is the localSessionVars implementation of planners
+// that are not bound to a session. Their session data does not outlive them,
+// so there is nothing to restore.
+type emptyLocalSessionVars struct{}
+
+var _ localSessionVars = emptyLocalSessionVars{}
+
+func (emptyLocalSessionVars) recordLocal(string, string) {}
+
+func (emptyLocalSessionVars) overrideLocal(string) {}
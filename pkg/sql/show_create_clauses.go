@@ -215,12 +215,26 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	buf.WriteString(tree.AsString(constraintDeferrability(fk.Deferrable, fk.InitiallyDeferred)))
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
 	return nil
 }
 
+// constraintDeferrability returns the deferrability clause of a constraint
+// with the given attributes.
+func constraintDeferrability(deferrable, initiallyDeferred bool) tree.ConstraintDeferrability {
+	switch {
+	case initiallyDeferred:
+		return tree.DeferrableInitiallyDeferred
+	case deferrable:
+		return tree.DeferrableInitiallyImmediate
+	default:
+		return tree.NotDeferrableConstraint
+	}
+}
+
 // ShowCreateSequence returns a valid SQL representation of the
 // CREATE SEQUENCE statement used to create the given sequence.
 func ShowCreateSequence(
@@ -238,6 +252,12 @@ func ShowCreateSequence(
 	f.Printf(" MAXVALUE %d", opts.MaxValue)
 	f.Printf(" INCREMENT %d", opts.Increment)
 	f.Printf(" START %d", opts.Start)
+	if opts.CacheSize > 1 {
+		f.Printf(" CACHE %d", opts.CacheSize)
+	}
+	if opts.Cycle {
+		f.Printf(" CYCLE")
+	}
 	if opts.Virtual {
 		f.Printf(" VIRTUAL")
 	}
@@ -472,6 +492,23 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		f.FormatNode(constraintDeferrability(c.Deferrable, c.InitiallyDeferred))
+		if c.Validity != descpb.ConstraintValidity_Validated {
+			f.WriteString(" NOT VALID")
+		}
+	}
+	for _, c := range desc.AllActiveAndInactiveExclusionConstraints() {
+		f.WriteString(",\n\t")
+		if len(c.Name) > 0 {
+			f.WriteString("CONSTRAINT ")
+			formatQuoteNames(&f.Buffer, c.Name)
+			f.WriteString(" ")
+		}
+		f.WriteString("EXCLUDE USING gist (")
+		if err := formatExclusionElems(&f.Buffer, desc, c); err != nil {
+			return err
+		}
+		f.WriteString(")")
 		if c.Validity != descpb.ConstraintValidity_Validated {
 			f.WriteString(" NOT VALID")
 		}
@@ -479,3 +516,23 @@ func showConstraintClause(
 	f.WriteString("\n)")
 	return nil
 }
+
+// formatExclusionElems writes the column and operator pairs of the given
+// exclusion constraint to buf, in the form "a WITH =, b WITH &&".
+func formatExclusionElems(
+	buf *bytes.Buffer, desc catalog.TableDescriptor, c *descpb.ExclusionConstraint,
+) error {
+	colNames, err := desc.NamesForColumnIDs(c.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	for i := range colNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		formatQuoteNames(buf, colNames[i])
+		buf.WriteString(" WITH ")
+		buf.WriteString(c.Operators[i])
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+var showCreateFunctionColumns = colinfo.ResultColumns{
+	{Name: "function_name", Typ: types.String},
+	{Name: "create_statement", Typ: types.String},
+}
+
+// ShowCreateFunction returns a SHOW CREATE FUNCTION statement.
+// Privileges: Any privilege on the function.
+func (p *planner) ShowCreateFunction(
+	ctx context.Context, n *tree.ShowCreateFunction,
+) (planNode, error) {
+	return &delayedNode{
+		name:    "SHOW CREATE FUNCTION " + tree.AsString(n.Name),
+		columns: showCreateFunctionColumns,
+
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			flags := tree.ObjectLookupFlags{
+				CommonLookupFlags: tree.CommonLookupFlags{Required: true},
+				DesiredObjectKind: tree.FunctionObject,
+			}
+			desc, prefix, err := resolver.ResolveExistingObject(ctx, p, n.Name, flags)
+			if err != nil {
+				return nil, err
+			}
+			fn := desc.(catalog.FunctionDescriptor)
+			if err := p.CheckAnyPrivilege(ctx, fn); err != nil {
+				return nil, err
+			}
+			name := tree.MakeTableNameFromPrefix(prefix, tree.Name(n.Name.Object()))
+			stmt, err := showCreateFunction(fn.FuncDesc(), &name)
+			if err != nil {
+				return nil, err
+			}
+
+			v := p.newContainerValuesNode(showCreateFunctionColumns, 0)
+			row := tree.Datums{
+				tree.NewDString(name.FQString()),
+				tree.NewDString(stmt),
+			}
+			if _, err := v.rows.AddRow(ctx, row); err != nil {
+				v.Close(ctx)
+				return nil, err
+			}
+			return v, nil
+		},
+	}, nil
+}
+
+// showCreateFunction returns a CREATE FUNCTION statement which recreates the
+// given function.
+func showCreateFunction(desc *descpb.FunctionDescriptor, name *tree.TableName) (string, error) {
+	funcName, err := tree.NewUnresolvedObjectName(
+		3, [3]string{name.Object(), name.Schema(), name.Catalog()}, 0, /* annotationIdx */
+	)
+	if err != nil {
+		return "", err
+	}
+	cf := tree.CreateFunction{
+		FuncName:   funcName,
+		Params:     make(tree.FuncParams, len(desc.Params)),
+		ReturnType: desc.ReturnType,
+	}
+	for i := range desc.Params {
+		cf.Params[i] = tree.FuncParam{Name: tree.Name(desc.Params[i].Name), Type: desc.Params[i].Type}
+	}
+
+	var volatility tree.FunctionVolatility
+	switch desc.Volatility {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		volatility = tree.FunctionImmutable
+	case descpb.FunctionDescriptor_STABLE:
+		volatility = tree.FunctionStable
+	default:
+		volatility = tree.FunctionVolatile
+	}
+	nullInput := tree.FunctionCalledOnNullInput
+	if desc.NullInputBehavior == descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT {
+		nullInput = tree.FunctionReturnsNullOnNullInput
+	}
+	cf.Options = tree.FunctionOptions{
+		tree.FunctionLanguageSQL,
+		volatility,
+		tree.FunctionLeakProof(desc.LeakProof),
+		nullInput,
+		tree.FunctionBody(desc.FunctionBody),
+	}
+
+	f := tree.NewFmtCtx(tree.FmtParsable)
+	f.FormatNode(&cf)
+	return f.CloseAndGetString(), nil
+}
@@ -327,6 +327,8 @@ func (s *Builder) encodeConstraintKey(
 				return nil, false, err
 			}
 			key = keys[0]
+		} else if val == tree.DNull && s.index.ColumnNullsReversedAt(i) {
+			key = rowenc.EncodeNullTableKey(key, dir, true /* nullsReversed */)
 		} else {
 			key, err = rowenc.EncodeTableKey(key, val, dir)
 			if err != nil {
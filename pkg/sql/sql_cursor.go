@@ -0,0 +1,452 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// DeclareCursor implements the DECLARE statement.
+// See https://www.postgresql.org/docs/current/sql-declare.html for details.
+func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (planNode, error) {
+	if s.Hold {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE CURSOR WITH HOLD")
+	}
+	if s.Binary {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE BINARY CURSOR")
+	}
+	if s.Scroll == tree.Scroll {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE SCROLL CURSOR")
+	}
+	if s.Select.With != nil {
+		for _, cte := range s.Select.With.CTEList {
+			if _, ok := cte.Stmt.(*tree.Select); !ok {
+				return nil, pgerror.New(pgcode.FeatureNotSupported,
+					"DECLARE CURSOR must not contain data-modifying statements in WITH")
+			}
+		}
+	}
+	return &declareCursorNode{n: s}, nil
+}
+
+type declareCursorNode struct {
+	n *tree.DeclareCursor
+}
+
+func (n *declareCursorNode) startExec(params runParams) error {
+	p := params.p
+	if p.extendedEvalCtx.TxnImplicit {
+		return pgerror.New(pgcode.NoActiveSQLTransaction,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	if _, err := p.sqlCursors.getCursor(n.n.Name); err == nil {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", n.n.Name)
+	}
+
+	// The cursor outlives the statement that declares it, so its query runs
+	// under a context that is not tied to this statement's span or
+	// cancellation.
+	ctx := logtags.WithTags(context.Background(), logtags.FromContext(params.ctx))
+	ie := p.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+	stmt := tree.AsStringWithFlags(n.n.Select, tree.FmtParsable)
+	rows, err := ie.QueryIteratorEx(
+		ctx, "sql-cursor", p.txn, sessiondata.InternalExecutorOverride{}, stmt,
+	)
+	if err != nil {
+		return err
+	}
+	cursor := &sqlCursor{
+		rowsIterator: rows,
+		readSeqNum:   p.txn.GetReadSeqNum(),
+		txn:          p.txn,
+		statement:    tree.AsString(n.n),
+		created:      timeutil.Now(),
+	}
+	return p.sqlCursors.addCursor(n.n.Name, cursor)
+}
+
+func (n *declareCursorNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *declareCursorNode) Values() tree.Datums                 { return nil }
+func (n *declareCursorNode) Close(ctx context.Context)           {}
+
+var errBackwardScan = pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+	"cursor can only scan forward")
+
+// FetchCursor implements the FETCH statement.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(ctx context.Context, s *tree.FetchCursor) (planNode, error) {
+	cursor, err := p.sqlCursors.getCursor(s.Name)
+	if err != nil {
+		return nil, err
+	}
+	n, err := newFetchNode(cursor, &s.CursorStmt)
+	if err != nil {
+		return nil, err
+	}
+	n.columns = cursor.Types()
+	return n, nil
+}
+
+// MoveCursor implements the MOVE statement.
+// See https://www.postgresql.org/docs/current/sql-move.html for details.
+func (p *planner) MoveCursor(ctx context.Context, s *tree.MoveCursor) (planNode, error) {
+	cursor, err := p.sqlCursors.getCursor(s.Name)
+	if err != nil {
+		return nil, err
+	}
+	n, err := newFetchNode(cursor, &s.CursorStmt)
+	if err != nil {
+		return nil, err
+	}
+	n.isMove = true
+	return n, nil
+}
+
+// fetchNode returns or, for MOVE, skips over rows of a cursor. Only forward
+// movement is supported.
+type fetchNode struct {
+	cursor *sqlCursor
+	isMove bool
+	// columns is empty for MOVE.
+	columns colinfo.ResultColumns
+
+	// current is set if the row the cursor is positioned on is to be returned
+	// without moving.
+	current bool
+	// skip is the number of rows to move over before returning rows.
+	skip int64
+	// count is the number of rows to return after skipping, or -1 if all the
+	// remaining rows are to be returned.
+	count int64
+	// last is set if only the last row of the cursor is to be returned.
+	last bool
+
+	row      tree.Datums
+	numMoved int
+	// origReadSeqNum is the read sequence number of the txn before the
+	// statement started, which is restored when it finishes.
+	origReadSeqNum enginepb.TxnSeq
+	seqNumSet      bool
+}
+
+func newFetchNode(cursor *sqlCursor, s *tree.CursorStmt) (*fetchNode, error) {
+	n := &fetchNode{cursor: cursor}
+	// moveTo positions the cursor on the given row, counted from 1.
+	moveTo := func(pos int64) error {
+		switch {
+		case pos < cursor.curRow:
+			return errBackwardScan
+		case pos == cursor.curRow:
+			n.current = true
+		default:
+			n.skip, n.count = pos-cursor.curRow-1, 1
+		}
+		return nil
+	}
+	switch s.FetchType {
+	case tree.FetchNormal:
+		if s.Count < 0 {
+			return nil, errBackwardScan
+		}
+		if s.Count == 0 {
+			n.current = true
+		}
+		n.count = s.Count
+	case tree.FetchRelative:
+		if s.Count < 0 {
+			return nil, errBackwardScan
+		}
+		if s.Count == 0 {
+			n.current = true
+		} else {
+			n.skip, n.count = s.Count-1, 1
+		}
+	case tree.FetchAbsolute:
+		if s.Count <= 0 {
+			return nil, errBackwardScan
+		}
+		if err := moveTo(s.Count); err != nil {
+			return nil, err
+		}
+	case tree.FetchFirst:
+		if err := moveTo(1); err != nil {
+			return nil, err
+		}
+	case tree.FetchLast:
+		n.last = true
+	case tree.FetchAll:
+		n.count = -1
+	case tree.FetchBackwardAll:
+		return nil, errBackwardScan
+	}
+	return n, nil
+}
+
+func (n *fetchNode) startExec(params runParams) error {
+	// The cursor reads at the sequence number of the statement that declared
+	// it, so that it does not observe writes performed by later statements in
+	// the transaction.
+	n.origReadSeqNum = n.cursor.txn.GetReadSeqNum()
+	if err := n.cursor.txn.SetReadSeqNum(n.cursor.readSeqNum); err != nil {
+		return err
+	}
+	n.seqNumSet = true
+
+	if !n.isMove {
+		return nil
+	}
+	for {
+		ok, err := n.Next(params)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		n.numMoved++
+	}
+}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *fetchNode) FastPathResults() (int, bool) {
+	return n.numMoved, n.isMove
+}
+
+func (n *fetchNode) Next(params runParams) (bool, error) {
+	c := n.cursor
+	if n.current {
+		n.current = false
+		if c.curRow == 0 || c.exhausted {
+			return false, nil
+		}
+		n.row = c.Cur()
+		return true, nil
+	}
+	for ; n.skip > 0; n.skip-- {
+		if ok, err := c.next(params.ctx); !ok || err != nil {
+			return false, err
+		}
+	}
+	if n.last {
+		n.last = false
+		var last tree.Datums
+		for {
+			ok, err := c.next(params.ctx)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				break
+			}
+			last = c.Cur()
+		}
+		n.row = last
+		return last != nil, nil
+	}
+	if n.count == 0 {
+		return false, nil
+	}
+	ok, err := c.next(params.ctx)
+	if !ok || err != nil {
+		return false, err
+	}
+	if n.count > 0 {
+		n.count--
+	}
+	n.row = c.Cur()
+	return true, nil
+}
+
+func (n *fetchNode) Values() tree.Datums {
+	return n.row
+}
+
+func (n *fetchNode) Close(ctx context.Context) {
+	if n.seqNumSet {
+		// Restoring a previously valid sequence number cannot fail.
+		_ = n.cursor.txn.SetReadSeqNum(n.origReadSeqNum)
+		n.seqNumSet = false
+	}
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, s *tree.CloseCursor) (planNode, error) {
+	return &closeCursorNode{n: s}, nil
+}
+
+type closeCursorNode struct {
+	n *tree.CloseCursor
+}
+
+func (n *closeCursorNode) startExec(params runParams) error {
+	if n.n.All {
+		params.p.sqlCursors.closeAll()
+		return nil
+	}
+	return params.p.sqlCursors.closeCursor(n.n.Name)
+}
+
+func (n *closeCursorNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *closeCursorNode) Values() tree.Datums                 { return nil }
+func (n *closeCursorNode) Close(ctx context.Context)           {}
+
+// sqlCursor is an open cursor, which lazily produces the rows of its query
+// within the transaction that declared it.
+type sqlCursor struct {
+	*rowsIterator
+	// readSeqNum is the read sequence number of the txn at the time the cursor
+	// was declared.
+	readSeqNum enginepb.TxnSeq
+	txn        *kv.Txn
+	statement  string
+	created    time.Time
+	// curRow is the position of the cursor, counted from 1. It is 0 before the
+	// first row has been fetched.
+	curRow int64
+	// exhausted is set once the cursor moved past its last row.
+	exhausted bool
+	// declIdx orders the cursors of a session by declaration.
+	declIdx int
+}
+
+// next moves the cursor to the next row, returning false if there are no more
+// rows.
+func (c *sqlCursor) next(ctx context.Context) (bool, error) {
+	if c.exhausted {
+		return false, nil
+	}
+	ok, err := c.Next(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		c.exhausted = true
+		return false, nil
+	}
+	c.curRow++
+	return true, nil
+}
+
+// sqlCursors gives a planner access to the cursors of a session.
+type sqlCursors interface {
+	// closeAll closes all the cursors.
+	closeAll()
+	// closeCursor closes the cursor with the given name.
+	closeCursor(tree.Name) error
+	// getCursor returns the cursor with the given name.
+	getCursor(tree.Name) (*sqlCursor, error)
+	// addCursor adds a cursor with the given name, which must not exist yet.
+	addCursor(tree.Name, *sqlCursor) error
+	// list returns all the cursors, keyed by name.
+	list() map[tree.Name]*sqlCursor
+}
+
+// cursorMap is the sqlCursors implementation of a connExecutor. The cursors
+// it holds are scoped to the current transaction.
+type cursorMap struct {
+	cursors map[tree.Name]*sqlCursor
+	// numDeclared is the number of cursors declared by the session.
+	numDeclared int
+}
+
+var _ sqlCursors = &cursorMap{}
+
+func (c *cursorMap) closeAll() {
+	for name, cursor := range c.cursors {
+		cursor.Close()
+		delete(c.cursors, name)
+	}
+}
+
+func (c *cursorMap) closeCursor(name tree.Name) error {
+	cursor, ok := c.cursors[name]
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	cursor.Close()
+	delete(c.cursors, name)
+	return nil
+}
+
+func (c *cursorMap) getCursor(name tree.Name) (*sqlCursor, error) {
+	cursor, ok := c.cursors[name]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	return cursor, nil
+}
+
+func (c *cursorMap) addCursor(name tree.Name, cursor *sqlCursor) error {
+	if _, ok := c.cursors[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+	}
+	if c.cursors == nil {
+		c.cursors = make(map[tree.Name]*sqlCursor)
+	}
+	cursor.declIdx = c.numDeclared
+	c.numDeclared++
+	c.cursors[name] = cursor
+	return nil
+}
+
+// closeDeclaredAfter closes the cursors declared after the first numDeclared
+// cursors of the session. It is used to close the cursors declared after a
+// savepoint when rolling back to it.
+func (c *cursorMap) closeDeclaredAfter(numDeclared int) {
+	for name, cursor := range c.cursors {
+		if cursor.declIdx >= numDeclared {
+			cursor.Close()
+			delete(c.cursors, name)
+		}
+	}
+}
+
+func (c *cursorMap) list() map[tree.Name]*sqlCursor {
+	return c.cursors
+}
+
+// emptySQLCursors is the sqlCursors implementation of planners that are not
+// bound to a session, which cannot have cursors.
+type emptySQLCursors struct{}
+
+var _ sqlCursors = emptySQLCursors{}
+
+func (emptySQLCursors) closeAll() {}
+
+func (emptySQLCursors) closeCursor(tree.Name) error {
+	return errors.AssertionFailedf("closeCursor not supported in emptySQLCursors")
+}
+
+func (emptySQLCursors) getCursor(tree.Name) (*sqlCursor, error) {
+	return nil, errors.AssertionFailedf("getCursor not supported in emptySQLCursors")
+}
+
+func (emptySQLCursors) addCursor(tree.Name, *sqlCursor) error {
+	return errors.AssertionFailedf("addCursor not supported in emptySQLCursors")
+}
+
+func (emptySQLCursors) list() map[tree.Name]*sqlCursor {
+	return nil
+}
@@ -62,12 +62,15 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_bytea:        Bytes,
 	oid.T_char:         typeQChar,
 	oid.T_date:         Date,
+	oid.T_daterange:    DateRange,
 	oid.T_float4:       Float4,
 	oid.T_float8:       Float,
 	oid.T_int2:         Int2,
 	oid.T_int2vector:   Int2Vector,
 	oid.T_int4:         Int4,
+	oid.T_int4range:    Int4Range,
 	oid.T_int8:         Int,
+	oid.T_int8range:    Int8Range,
 	oid.T_inet:         INet,
 	oid.T_interval:     Interval,
 	oid.T_jsonb:        Jsonb,
@@ -86,6 +89,8 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
+	oid.T_tsrange:      TSRange,
+	oid.T_tstzrange:    TSTZRange,
 	oid.T_unknown:      Unknown,
 	oid.T_uuid:         Uuid,
 	oid.T_varbit:       VarBit,
@@ -105,13 +110,16 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_bytea:        oid.T__bytea,
 	oid.T_char:         oid.T__char,
 	oid.T_date:         oid.T__date,
+	oid.T_daterange:    oid.T__daterange,
 	oid.T_float4:       oid.T__float4,
 	oid.T_float8:       oid.T__float8,
 	oid.T_inet:         oid.T__inet,
 	oid.T_int2:         oid.T__int2,
 	oid.T_int2vector:   oid.T__int2vector,
 	oid.T_int4:         oid.T__int4,
+	oid.T_int4range:    oid.T__int4range,
 	oid.T_int8:         oid.T__int8,
+	oid.T_int8range:    oid.T__int8range,
 	oid.T_interval:     oid.T__interval,
 	oid.T_jsonb:        oid.T__jsonb,
 	oid.T_name:         oid.T__name,
@@ -129,6 +137,8 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oid.T_timetz:       oid.T__timetz,
 	oid.T_timestamp:    oid.T__timestamp,
 	oid.T_timestamptz:  oid.T__timestamptz,
+	oid.T_tsrange:      oid.T__tsrange,
+	oid.T_tstzrange:    oid.T__tstzrange,
 	oid.T_uuid:         oid.T__uuid,
 	oid.T_varbit:       oid.T__varbit,
 	oid.T_varchar:      oid.T__varchar,
@@ -205,6 +215,11 @@ func CalcArrayOid(elemTyp *T) oid.Oid {
 
 	case EnumFamily:
 		return elemTyp.UserDefinedArrayOID()
+
+	case TupleFamily:
+		if elemTyp.UserDefined() {
+			return elemTyp.UserDefinedArrayOID()
+		}
 	}
 
 	// Map the OID of the array element type to the corresponding array OID.
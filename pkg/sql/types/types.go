@@ -194,6 +194,9 @@ type UserDefinedTypeMetadata struct {
 
 	// enumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
+
+	// CompositeData is non-nil iff the metadata is for a composite type.
+	CompositeData *CompositeMetadata
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -212,6 +215,16 @@ type EnumMetadata struct {
 	//  should occur, if at all.
 }
 
+// CompositeMetadata is metadata about a composite type needed for encoding
+// and decoding its values.
+type CompositeMetadata struct {
+	// ElementIDs is a slice of the stable IDs of the attributes of the type,
+	// in the same order as the tuple contents. The IDs are used in the value
+	// encoding of the type instead of the positions of the attributes, so that
+	// attributes can be added and dropped without rewriting existing values.
+	ElementIDs []uint32
+}
+
 func (e *EnumMetadata) debugString() string {
 	return fmt.Sprintf(
 		"PhysicalReps: %v; LogicalReps: %s",
@@ -443,6 +456,26 @@ var (
 		},
 	}
 
+	// Int4Range is the type of a range of INT4 values.
+	Int4Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int4range, Locale: &emptyLocale}}
+
+	// Int8Range is the type of a range of INT8 values.
+	Int8Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int8range, Locale: &emptyLocale}}
+
+	// TSRange is the type of a range of TIMESTAMP values.
+	TSRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tsrange, Locale: &emptyLocale}}
+
+	// TSTZRange is the type of a range of TIMESTAMPTZ values.
+	TSTZRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tstzrange, Locale: &emptyLocale}}
+
+	// DateRange is the type of a range of DATE values.
+	DateRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_daterange, Locale: &emptyLocale}}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -1053,6 +1086,18 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeComposite constructs a new instance of a TupleFamily type that
+// represents a user defined composite type with the given stable type ID. Note
+// that it does not hydrate cached fields on the type.
+func MakeComposite(typeOID, arrayTypeOID oid.Oid, contents []*T, labels []string) *T {
+	t := MakeLabeledTuple(contents, labels)
+	t.InternalType.Oid = typeOID
+	t.InternalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID: arrayTypeOID,
+	}
+	return t
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1224,6 +1269,27 @@ func (t *T) ArrayContents() *T {
 	return t.InternalType.ArrayContents
 }
 
+// RangeContents returns the type of the bounds of a range type. It is nil
+// for all types other than ranges.
+func (t *T) RangeContents() *T {
+	if t.Family() != RangeFamily {
+		return nil
+	}
+	return rangeContents[t.Oid()]
+}
+
+// rangeContents maps the Oid of each range type to its subtype.
+var rangeContents = map[oid.Oid]*T{
+	oid.T_int4range: Int4,
+	oid.T_int8range: Int,
+	oid.T_tsrange:   Timestamp,
+	oid.T_tstzrange: TimestampTZ,
+	oid.T_daterange: Date,
+}
+
+// RangeTypes contains all of the built-in range types.
+var RangeTypes = []*T{Int4Range, Int8Range, TSRange, TSTZRange, DateRange}
+
 // TupleContents returns a slice containing the type of each tuple field. This
 // is nil for non-TupleFamily types.
 func (t *T) TupleContents() []*T {
@@ -1262,6 +1328,16 @@ func RemapUserDefinedTypeOIDs(t *T, newOID, newArrayOID oid.Oid) {
 	}
 }
 
+// HydrateComposite installs the attributes of a composite type into t, which
+// must be a TupleFamily type. The ids are the stable IDs of the attributes
+// used in the value encoding of the type. It mutates the input types.T and
+// should only be used when hydrating the type from its type descriptor.
+func HydrateComposite(t *T, contents []*T, labels []string, ids []uint32) {
+	t.InternalType.TupleContents = contents
+	t.InternalType.TupleLabels = labels
+	t.TypeMeta.CompositeData = &CompositeMetadata{ElementIDs: ids}
+}
+
 // UserDefined returns whether or not t is a user defined type.
 func (t *T) UserDefined() bool {
 	return IsOIDUserDefinedType(t.Oid())
@@ -1293,6 +1369,7 @@ var familyNames = map[Family]string{
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
 	OidFamily:            "oid",
+	RangeFamily:          "range",
 	StringFamily:         "string",
 	TimeFamily:           "time",
 	TimestampFamily:      "timestamp",
@@ -1363,7 +1440,7 @@ func (t *T) Name() string {
 			panic(errors.AssertionFailedf("programming error: unknown int width: %d", t.Width()))
 		}
 
-	case OidFamily:
+	case OidFamily, RangeFamily:
 		return t.SQLStandardName()
 
 	case StringFamily, CollatedStringFamily:
@@ -1383,7 +1460,14 @@ func (t *T) Name() string {
 		panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
 
 	case TupleFamily:
-		// Tuple types are currently anonymous, with no name.
+		if t.UserDefined() {
+			// This can be nil during unit testing.
+			if t.TypeMeta.Name == nil {
+				return "unknown_composite"
+			}
+			return t.TypeMeta.Name.Basename()
+		}
+		// Other tuple types are anonymous, with no name.
 		return ""
 
 	case EnumFamily:
@@ -1557,6 +1641,12 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		default:
 			panic(errors.AssertionFailedf("unexpected Oid: %v", errors.Safe(t.Oid())))
 		}
+	case RangeFamily:
+		name, ok := oid.TypeName[t.Oid()]
+		if !ok || t.RangeContents() == nil {
+			panic(errors.AssertionFailedf("unexpected Oid: %v", errors.Safe(t.Oid())))
+		}
+		return strings.ToLower(name)
 	case StringFamily, CollatedStringFamily:
 		switch t.Oid() {
 		case oid.T_text:
@@ -1615,6 +1705,9 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		}
 		return fmt.Sprintf("timestamp(%d) with time zone", typmod)
 	case TupleFamily:
+		if t.UserDefined() {
+			return t.TypeMeta.Name.Basename()
+		}
 		return "record"
 	case UnknownFamily:
 		return "unknown"
@@ -1738,6 +1831,10 @@ func (t *T) SQLString() string {
 			return "anyenum"
 		}
 		return t.TypeMeta.Name.FQName()
+	case TupleFamily:
+		if t.UserDefined() {
+			return t.TypeMeta.Name.FQName()
+		}
 	}
 	return strings.ToUpper(t.Name())
 }
@@ -1778,6 +1875,11 @@ func (t *T) Equivalent(other *T) bool {
 		if IsWildcardTupleType(t) || IsWildcardTupleType(other) {
 			return true
 		}
+		// Distinct composite types are never equivalent, even if they have
+		// the same attributes.
+		if t.UserDefined() && other.UserDefined() && t.Oid() != other.Oid() {
+			return false
+		}
 		if len(t.TupleContents()) != len(other.TupleContents()) {
 			return false
 		}
@@ -1801,6 +1903,12 @@ func (t *T) Equivalent(other *T) bool {
 		if t.Oid() != other.Oid() {
 			return false
 		}
+
+	case RangeFamily:
+		// Ranges over different subtypes are never equivalent.
+		if t.Oid() != other.Oid() {
+			return false
+		}
 	}
 
 	return true
@@ -2293,6 +2401,9 @@ func (t *T) String() string {
 		return t.ArrayContents().String() + "[]"
 
 	case TupleFamily:
+		if t.UserDefined() {
+			return t.Name()
+		}
 		var buf bytes.Buffer
 		buf.WriteString("tuple")
 		if len(t.TupleContents()) != 0 && !IsWildcardTupleType(t) {
@@ -826,6 +826,28 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// See https://www.postgresql.org/docs/current/pgtrgm.html#PGTRGM-GUC
+	`pg_trgm.similarity_threshold`: {
+		GetStringVal: makeFloatGetStringValFn(`pg_trgm.similarity_threshold`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return wrapSetVarError("pg_trgm.similarity_threshold", s, "%v", err)
+			}
+			// Note: this is the range allowed by PostgreSQL.
+			if f < 0 || f > 1 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%g is outside the valid range for parameter "pg_trgm.similarity_threshold" (0 .. 1)`, f)
+			}
+			m.SetTrigramSimilarityThreshold(f)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return strconv.FormatFloat(evalCtx.SessionData.TrigramSimilarityThreshold, 'g', -1, 64)
+		},
+		GlobalDefault: func(sv *settings.Values) string { return "0.3" },
+	},
+
 	// See https://www.postgresql.org/docs/10/static/ddl-schemas.html#DDL-SCHEMAS-PATH
 	// https://www.postgresql.org/docs/9.6/static/runtime-config-client.html
 	`search_path`: {
@@ -1373,6 +1395,19 @@ func makeIntGetStringValFn(name string) getStringValFn {
 	}
 }
 
+func makeFloatGetStringValFn(name string) getStringValFn {
+	return func(ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr) (string, error) {
+		if len(values) != 1 {
+			return "", newSingleArgVarError(name)
+		}
+		f, err := paramparse.DatumAsFloat(&evalCtx.EvalContext, name, values[0])
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	}
+}
+
 // IsSessionVariableConfigurable returns true iff there is a session
 // variable with the given name and it is settable by a client
 // (e.g. in pgwire).
@@ -1413,7 +1448,9 @@ func (p *planner) GetSessionVar(
 }
 
 // SetSessionVar implements the EvalSessionAccessor interface.
-func (p *planner) SetSessionVar(ctx context.Context, varName, newVal string) error {
+func (p *planner) SetSessionVar(
+	ctx context.Context, varName, newVal string, isLocal bool,
+) error {
 	name := strings.ToLower(varName)
 	_, v, err := getSessionVar(name, false /* missingOk */)
 	if err != nil {
@@ -1423,8 +1460,5 @@ func (p *planner) SetSessionVar(ctx context.Context, varName, newVal string) err
 	if v.Set == nil && v.RuntimeSet == nil {
 		return newCannotChangeParameterError(name)
 	}
-	if v.RuntimeSet != nil {
-		return v.RuntimeSet(ctx, &p.extendedEvalCtx, newVal)
-	}
-	return v.Set(ctx, p.sessionDataMutator, newVal)
+	return p.setSessionVarValue(ctx, name, v, newVal, isLocal)
 }
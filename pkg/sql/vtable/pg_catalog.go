@@ -246,6 +246,18 @@ CREATE TABLE pg_catalog.pg_conversion (
 	condefault BOOL
 )`
 
+// PGCatalogCursors describes the schema of the pg_catalog.pg_cursors table.
+// https://www.postgresql.org/docs/14/view-pg-cursors.html,
+const PGCatalogCursors = `
+CREATE TABLE pg_catalog.pg_cursors (
+	name TEXT,
+	statement TEXT,
+	is_holdable BOOL,
+	is_binary BOOL,
+	is_scrollable BOOL,
+	creation_time TIMESTAMPTZ
+)`
+
 // PGCatalogDatabase describes the schema of the pg_catalog.pg_database table.
 // https://www.postgresql.org/docs/9.5/catalog-pg-database.html,
 const PGCatalogDatabase = `
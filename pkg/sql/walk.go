@@ -340,6 +340,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&cancelQueriesNode{}):              "cancel queries",
 	reflect.TypeOf(&cancelSessionsNode{}):             "cancel sessions",
 	reflect.TypeOf(&changePrivilegesNode{}):           "change privileges",
+	reflect.TypeOf(&closeCursorNode{}):                "close cursor",
 	reflect.TypeOf(&commentOnColumnNode{}):            "comment on column",
 	reflect.TypeOf(&commentOnDatabaseNode{}):          "comment on database",
 	reflect.TypeOf(&commentOnIndexNode{}):             "comment on index",
@@ -348,6 +349,8 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&controlSchedulesNode{}):           "control schedules",
 	reflect.TypeOf(&createDatabaseNode{}):             "create database",
 	reflect.TypeOf(&createExtensionNode{}):            "create extension",
+	reflect.TypeOf(&createFunctionNode{}):             "create function",
+	reflect.TypeOf(&createTriggerNode{}):              "create trigger",
 	reflect.TypeOf(&createIndexNode{}):                "create index",
 	reflect.TypeOf(&createSequenceNode{}):             "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):               "create schema",
@@ -356,11 +359,14 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&createTypeNode{}):                 "create type",
 	reflect.TypeOf(&CreateRoleNode{}):                 "create user/role",
 	reflect.TypeOf(&createViewNode{}):                 "create view",
+	reflect.TypeOf(&declareCursorNode{}):              "declare cursor",
 	reflect.TypeOf(&delayedNode{}):                    "virtual table",
 	reflect.TypeOf(&deleteNode{}):                     "delete",
 	reflect.TypeOf(&deleteRangeNode{}):                "delete range",
 	reflect.TypeOf(&distinctNode{}):                   "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):               "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):               "drop function",
+	reflect.TypeOf(&dropTriggerNode{}):                "drop trigger",
 	reflect.TypeOf(&dropIndexNode{}):                  "drop index",
 	reflect.TypeOf(&dropSequenceNode{}):               "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                 "drop schema",
@@ -372,6 +378,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainPlanNode{}):                "explain plan",
 	reflect.TypeOf(&explainVecNode{}):                 "explain vectorized",
 	reflect.TypeOf(&exportNode{}):                     "export",
+	reflect.TypeOf(&fetchNode{}):                      "fetch",
 	reflect.TypeOf(&filterNode{}):                     "filter",
 	reflect.TypeOf(&GrantRoleNode{}):                  "grant role",
 	reflect.TypeOf(&groupNode{}):                      "group",
@@ -409,6 +416,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&sequenceSelectNode{}):             "sequence select",
 	reflect.TypeOf(&serializeNode{}):                  "run",
 	reflect.TypeOf(&setClusterSettingNode{}):          "set cluster setting",
+	reflect.TypeOf(&setConstraintsNode{}):             "set constraints",
 	reflect.TypeOf(&setVarNode{}):                     "set",
 	reflect.TypeOf(&setZoneConfigNode{}):              "configure zone",
 	reflect.TypeOf(&showFingerprintsNode{}):           "show fingerprints",
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
 	"github.com/cockroachdb/datadriven"
@@ -56,7 +57,9 @@ func WalkWithNewServer(
 // can be used to specify types to ignore. ErrorResponse messages are
 // immediately returned as errors unless they are the expected type, in which
 // case they will marshal to an empty ErrorResponse message since our error
-// detail specifics differ from Postgres.
+// detail specifics differ from Postgres. The data of CopyData messages is
+// output as a string, unless it isn't valid UTF-8 in which case it is
+// hex-encoded.
 //
 // "receive": Like "until", but only output matching messages instead of all
 // messages.
@@ -216,6 +219,16 @@ func msgsToJSONWithIgnore(msgs []pgproto3.BackendMessage, args *datadriven.TestD
 			}); err != nil {
 				panic(err)
 			}
+		} else if m, ok := msg.(*pgproto3.CopyData); ok && utf8.Valid(m.Data) {
+			if err := enc.Encode(struct {
+				Type string
+				Data string
+			}{
+				Type: "CopyData",
+				Data: string(m.Data),
+			}); err != nil {
+				panic(err)
+			}
 		} else if err := enc.Encode(msg); err != nil {
 			panic(err)
 		}
@@ -237,6 +250,8 @@ func toMessage(typ string) interface{} {
 		return &pgproto3.CopyDone{}
 	case "CopyInResponse":
 		return &pgproto3.CopyInResponse{}
+	case "CopyOutResponse":
+		return &pgproto3.CopyOutResponse{}
 	case "DataRow":
 		return &pgproto3.DataRow{}
 	case "Describe":
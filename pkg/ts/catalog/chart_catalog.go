@@ -2276,6 +2276,32 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Export"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.EXPORT.started",
+					"schedules.EXPORT.succeeded",
+					"schedules.EXPORT.failed",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Changefeed"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.CHANGEFEED.started",
+					"schedules.CHANGEFEED.succeeded",
+					"schedules.CHANGEFEED.failed",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Jobs, "Execution"}},
 		Charts: []chartDescription{
@@ -129,6 +129,16 @@ type Reader struct {
 	// It is set to comma (',') by NewReader.
 	Comma rune
 
+	// Quote is the character enclosing quoted fields.
+	// It is set to double quote ('"') by NewReader.
+	Quote rune
+
+	// Escape, if not 0, is the character which, inside a quoted field,
+	// precedes a quote or escape character that is part of the field. If it
+	// is 0 or equal to Quote, a quote character inside a quoted field is
+	// written as two quote characters.
+	Escape rune
+
 	// Comment, if not 0, is the comment character. Lines beginning with the
 	// Comment character without preceding whitespace are ignored.
 	// With leading whitespace the Comment character becomes part of the
@@ -174,6 +184,10 @@ type Reader struct {
 	// The i'th field ends at offset fieldIndexes[i] in recordBuffer.
 	fieldIndexes []int
 
+	// fieldQuoted records, for each field of the last record, whether the
+	// field was quoted.
+	fieldQuoted []bool
+
 	// lastRecord is a record cache and only used when ReuseRecord == true.
 	lastRecord []string
 }
@@ -182,6 +196,7 @@ type Reader struct {
 func NewReader(r io.Reader) *Reader {
 	return &Reader{
 		Comma: ',',
+		Quote: '"',
 		r:     bufio.NewReader(r),
 	}
 }
@@ -204,6 +219,13 @@ func (r *Reader) Read() (record []string, err error) {
 	return record, err
 }
 
+// FieldQuoted returns whether the i'th field of the last record returned by
+// Read was a quoted field. This allows callers to distinguish a quoted empty
+// string from an empty unquoted field.
+func (r *Reader) FieldQuoted(i int) bool {
+	return i < len(r.fieldQuoted) && r.fieldQuoted[i]
+}
+
 // ReadAll reads all the remaining records from r.
 // Each record is a slice of fields.
 // A successful call returns err == nil, not err == io.EOF. Because ReadAll is
@@ -265,9 +287,20 @@ func nextRune(b []byte) rune {
 }
 
 func (r *Reader) readRecord(dst []string) ([]string, error) {
+	quote := r.Quote
+	if quote == 0 {
+		quote = '"'
+	}
+	escape := r.Escape
+	if escape == 0 {
+		escape = quote
+	}
 	if r.Comma == r.Comment || !validDelim(r.Comma) || (r.Comment != 0 && !validDelim(r.Comment)) {
 		return nil, errInvalidDelim
 	}
+	if quote == r.Comma || !validDelim(quote) || !validDelim(escape) {
+		return nil, errInvalidDelim
+	}
 
 	// Read line (automatically skipping past empty lines and any comments).
 	var line, fullLine []byte
@@ -291,18 +324,21 @@ func (r *Reader) readRecord(dst []string) ([]string, error) {
 
 	// Parse each field in the record.
 	var err error
-	const quoteLen = len(`"`)
+	quoteLen := utf8.RuneLen(quote)
+	escapeLen := utf8.RuneLen(escape)
 	commaLen := utf8.RuneLen(r.Comma)
 	recLine := r.numLine // Starting line for record
 	r.recordBuffer = r.recordBuffer[:0]
 	r.fieldIndexes = r.fieldIndexes[:0]
+	r.fieldQuoted = r.fieldQuoted[:0]
 parseField:
 	for {
 		if r.TrimLeadingSpace {
 			line = bytes.TrimLeftFunc(line, unicode.IsSpace)
 		}
-		if len(line) == 0 || line[0] != '"' {
+		if len(line) == 0 || nextRune(line) != quote {
 			// Non-quoted string field
+			r.fieldQuoted = append(r.fieldQuoted, false)
 			i := bytes.IndexRune(line, r.Comma)
 			field := line
 			if i >= 0 {
@@ -312,7 +348,7 @@ parseField:
 			}
 			// Check to make sure a quote does not appear in field.
 			if !r.LazyQuotes {
-				if j := bytes.IndexByte(field, '"'); j >= 0 {
+				if j := bytes.IndexRune(field, quote); j >= 0 {
 					col := utf8.RuneCount(fullLine[:len(fullLine)-len(line[j:])])
 					err = &ParseError{StartLine: recLine, Line: r.numLine, Column: col, Err: ErrBareQuote}
 					break parseField
@@ -327,17 +363,38 @@ parseField:
 			break parseField
 		} else {
 			// Quoted string field
+			r.fieldQuoted = append(r.fieldQuoted, true)
 			line = line[quoteLen:]
 			for {
-				i := bytes.IndexByte(line, '"')
+				i := bytes.IndexRune(line, quote)
+				if escape != quote {
+					if j := bytes.IndexRune(line, escape); j >= 0 && (i < 0 || j < i) {
+						// Hit an escape character. It escapes the next character
+						// if that is a quote or escape character, and is part of
+						// the field otherwise.
+						r.recordBuffer = append(r.recordBuffer, line[:j]...)
+						line = line[j+escapeLen:]
+						switch nextRune(line) {
+						case quote:
+							r.recordBuffer = append(r.recordBuffer, line[:quoteLen]...)
+							line = line[quoteLen:]
+						case escape:
+							r.recordBuffer = append(r.recordBuffer, line[:escapeLen]...)
+							line = line[escapeLen:]
+						default:
+							r.recordBuffer = append(r.recordBuffer, string(escape)...)
+						}
+						continue
+					}
+				}
 				if i >= 0 {
 					// Hit next quote.
 					r.recordBuffer = append(r.recordBuffer, line[:i]...)
 					line = line[i+quoteLen:]
 					switch rn := nextRune(line); {
-					case rn == '"':
+					case rn == quote && escape == quote:
 						// `""` sequence (append quote).
-						r.recordBuffer = append(r.recordBuffer, '"')
+						r.recordBuffer = append(r.recordBuffer, line[:quoteLen]...)
 						line = line[quoteLen:]
 					case rn == r.Comma:
 						// `",` sequence (end of field).
@@ -350,7 +407,7 @@ parseField:
 						break parseField
 					case r.LazyQuotes:
 						// `"` sequence (bare quote).
-						r.recordBuffer = append(r.recordBuffer, '"')
+						r.recordBuffer = append(r.recordBuffer, string(quote)...)
 					default:
 						// `"*` sequence (invalid non-escaped quote).
 						col := utf8.RuneCount(fullLine[:len(fullLine)-len(line)-quoteLen])
@@ -31,6 +31,8 @@ func TestRead(t *testing.T) {
 
 		// These fields are copied into the Reader
 		Comma              rune
+		Quote              rune
+		Escape             rune
 		Comment            rune
 		UseFieldsPerRecord bool // false (default) means FieldsPerRecord is -1
 		FieldsPerRecord    int
@@ -359,6 +361,36 @@ x,,,
 		Input:      `"""""""`,
 		Output:     [][]string{{`"""`}},
 		LazyQuotes: true,
+	}, {
+		Name:   "SingleQuote",
+		Input:  "'a,b',c\n'it''s',d\n",
+		Output: [][]string{{"a,b", "c"}, {"it's", "d"}},
+		Quote:  '\'',
+	}, {
+		Name:   "Escape",
+		Input:  `"a\"b","c\\d","e\f",g` + "\n",
+		Output: [][]string{{`a"b`, `c\d`, `e\f`, "g"}},
+		Escape: '\\',
+	}, {
+		Name:   "EscapeDoesNotDoubleQuotes",
+		Input:  `"a""b"` + "\n",
+		Error:  &ParseError{StartLine: 1, Line: 1, Column: 2, Err: ErrQuote},
+		Escape: '\\',
+	}, {
+		Name:   "EscapeOutsideQuotes",
+		Input:  `a\b,"c"` + "\n",
+		Output: [][]string{{`a\b`, "c"}},
+		Escape: '\\',
+	}, {
+		Name:   "EscapeMultiline",
+		Input:  "\"a\\\"\nb\",c\n",
+		Output: [][]string{{"a\"\nb", "c"}},
+		Escape: '\\',
+	}, {
+		Name:  "BadQuote",
+		Comma: '|',
+		Quote: '|',
+		Error: errInvalidDelim,
 	}, {
 		Name:  "BadComma1",
 		Comma: '\n',
@@ -397,6 +429,10 @@ x,,,
 			if tt.Comma != 0 {
 				r.Comma = tt.Comma
 			}
+			if tt.Quote != 0 {
+				r.Quote = tt.Quote
+			}
+			r.Escape = tt.Escape
 			r.Comment = tt.Comment
 			if tt.UseFieldsPerRecord {
 				r.FieldsPerRecord = tt.FieldsPerRecord
@@ -422,6 +458,22 @@ x,,,
 	}
 }
 
+func TestFieldQuoted(t *testing.T) {
+	r := NewReader(strings.NewReader("a,\"\",,\"b\"\n"))
+	record, err := r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a", "", "", "b"}; !reflect.DeepEqual(record, expected) {
+		t.Fatalf("expected %q, got %q", expected, record)
+	}
+	for i, expected := range []bool{false, true, false, true} {
+		if quoted := r.FieldQuoted(i); quoted != expected {
+			t.Errorf("field %d: expected quoted=%t, got %t", i, expected, quoted)
+		}
+	}
+}
+
 // nTimes is an io.Reader which yields the string s n times.
 type nTimes struct {
 	s   string
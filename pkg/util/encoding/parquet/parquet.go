@@ -0,0 +1,168 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package parquet implements a reader and a writer for the Apache Parquet file
+// format.
+//
+// The writer supports a deliberately small subset of the format: every file
+// contains a single row group, every column chunk contains a single PLAIN
+// encoded data page, and all columns are nullable. Columns may hold lists of
+// values, which are written using the standard three-level LIST structure.
+//
+// The reader supports the files produced by common writers such as Spark and
+// Arrow, as long as their columns are either primitive values or lists of
+// primitive values. It reads PLAIN and dictionary encoded pages, compressed
+// using gzip or snappy.
+//
+// See https://github.com/apache/parquet-format for the specification.
+package parquet
+
+import "github.com/cockroachdb/errors"
+
+// magic is written at the start and at the end of every Parquet file.
+const magic = "PAR1"
+
+// PhysicalType is the type used to store values on disk.
+type PhysicalType int32
+
+// The physical types, numbered as in parquet.thrift.
+const (
+	Boolean PhysicalType = 0
+	Int32   PhysicalType = 1
+	Int64   PhysicalType = 2
+	// Int96 is deprecated, but is still used by Spark to store timestamps as
+	// the number of nanoseconds since midnight, in the low 8 bytes, followed
+	// by the Julian day number. Int96 columns can only be read.
+	Int96             PhysicalType = 3
+	Float             PhysicalType = 4
+	Double            PhysicalType = 5
+	ByteArray         PhysicalType = 6
+	FixedLenByteArray PhysicalType = 7
+)
+
+// Annotation describes how the values of a physical type should be
+// interpreted. Annotations are written as both converted types and logical
+// types, so that older and newer readers understand them.
+type Annotation int
+
+const (
+	// NoAnnotation leaves the physical values uninterpreted.
+	NoAnnotation Annotation = iota
+	// String annotates UTF-8 encoded ByteArray values.
+	String
+	// Enum annotates ByteArray values holding the labels of an enum.
+	Enum
+	// JSON annotates ByteArray values holding UTF-8 encoded JSON documents.
+	JSON
+	// Decimal annotates the unscaled value of a decimal. Int32 and Int64
+	// values hold it directly, while ByteArray and FixedLenByteArray values
+	// hold it as a big-endian two's complement integer. Column.Precision and
+	// Column.Scale must be set.
+	Decimal
+	// Date annotates Int32 values holding the number of days since the Unix
+	// epoch.
+	Date
+	// TimeMicros annotates Int64 values holding the number of microseconds
+	// since midnight.
+	TimeMicros
+	// TimestampMicros annotates Int64 values holding the number of
+	// microseconds since the Unix epoch, in an unspecified time zone.
+	TimestampMicros
+	// TimestampMicrosUTC annotates Int64 values holding the number of
+	// microseconds since the Unix epoch, in UTC.
+	TimestampMicrosUTC
+	// TimestampMillis annotates Int64 values holding the number of
+	// milliseconds since the Unix epoch, in an unspecified time zone.
+	TimestampMillis
+	// TimestampMillisUTC annotates Int64 values holding the number of
+	// milliseconds since the Unix epoch, in UTC.
+	TimestampMillisUTC
+	// Int16 annotates Int32 values which fit in 16 bits.
+	Int16
+	// UUID annotates FixedLenByteArray values of length 16 holding a UUID.
+	UUID
+)
+
+// CompressionCodec is the codec used to compress data pages.
+type CompressionCodec int32
+
+// The compression codecs, numbered as in parquet.thrift. Snappy is only
+// supported by the reader.
+const (
+	Uncompressed CompressionCodec = 0
+	Snappy       CompressionCodec = 1
+	Gzip         CompressionCodec = 2
+)
+
+// Column describes a column of a Parquet file. All columns are nullable.
+type Column struct {
+	Name string
+	// Type is the physical type of the column's values, or of the elements of
+	// the column's lists if List is set.
+	Type PhysicalType
+	// TypeLength is the length of FixedLenByteArray values.
+	TypeLength int32
+	// Annotation describes how the physical values should be interpreted.
+	Annotation Annotation
+	// Precision and Scale are set for Decimal columns.
+	Precision, Scale int32
+	// List is set if the column's values are lists of nullable elements.
+	List bool
+}
+
+// Validate checks that the column is well formed.
+func (c *Column) Validate() error {
+	if c.Name == "" {
+		return errors.New("parquet: column names must not be empty")
+	}
+	if c.Type == FixedLenByteArray && c.TypeLength <= 0 {
+		return errors.Newf("parquet: column %q requires a positive type length", c.Name)
+	}
+	var ok bool
+	switch c.Annotation {
+	case NoAnnotation:
+		ok = true
+	case String, Enum, JSON:
+		ok = c.Type == ByteArray
+	case Decimal:
+		switch c.Type {
+		case Int32, Int64, ByteArray, FixedLenByteArray:
+			ok = c.Precision > 0 && c.Scale >= 0 && c.Scale <= c.Precision
+		}
+	case Date, Int16:
+		ok = c.Type == Int32
+	case TimeMicros, TimestampMicros, TimestampMicrosUTC, TimestampMillis, TimestampMillisUTC:
+		ok = c.Type == Int64
+	case UUID:
+		ok = c.Type == FixedLenByteArray && c.TypeLength == 16
+	}
+	if !ok {
+		return errors.Newf("parquet: invalid annotation %d for column %q", c.Annotation, c.Name)
+	}
+	return nil
+}
+
+// maxLevels returns the maximum definition and repetition levels of the
+// column's values.
+func (c *Column) maxLevels() (maxDef, maxRep uint8) {
+	if c.List {
+		// The optional list, the repeated group, and the optional element.
+		return 3, 1
+	}
+	return 1, 0
+}
+
+// path returns the path of the column's leaf in the schema.
+func (c *Column) path() []string {
+	if c.List {
+		return []string{c.Name, "list", "element"}
+	}
+	return []string{c.Name}
+}
@@ -0,0 +1,794 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// Reader reads the rows of a Parquet file. Only the footer of the file and
+// the row group being read are held in memory.
+type Reader struct {
+	file    io.ReaderAt
+	opts    ReaderOptions
+	meta    fileMetaData
+	columns []Column
+	readers []columnReader
+
+	// footerStart is the offset of the footer, which ends the data of the row
+	// groups, and footerLen its length.
+	footerStart, footerLen int64
+
+	// rowGroup is the index of the next row group to load, and rowsLeft is
+	// the number of rows left in the current one.
+	rowGroup int
+	rowsLeft int64
+}
+
+// ReaderOptions are the options of a Reader.
+type ReaderOptions struct {
+	// Reserve, if set, is called before the Reader reads a part of the file
+	// into memory, with the total number of bytes the Reader holds once the
+	// part is read. The read fails if Reserve returns an error.
+	Reserve func(bytes int64) error
+}
+
+// NewReader returns a Reader of the Parquet file contained in data.
+func NewReader(data []byte) (*Reader, error) {
+	return NewFileReader(bytes.NewReader(data), int64(len(data)), ReaderOptions{})
+}
+
+// NewFileReader returns a Reader of the Parquet file of the specified size
+// read from file. The footer of the file is read immediately, and each row
+// group when its first row is read.
+func NewFileReader(file io.ReaderAt, size int64, opts ReaderOptions) (*Reader, error) {
+	const trailerLen = 4 + len(magic)
+	if size < int64(len(magic)+trailerLen) {
+		return nil, errors.New("parquet: not a Parquet file")
+	}
+	header := make([]byte, len(magic))
+	trailer := make([]byte, trailerLen)
+	if err := readFull(file, header, 0); err != nil {
+		return nil, err
+	}
+	if err := readFull(file, trailer, size-int64(trailerLen)); err != nil {
+		return nil, err
+	}
+	if string(header) != magic || string(trailer[4:]) != magic {
+		return nil, errors.New("parquet: not a Parquet file")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(trailer))
+	footerStart := size - int64(trailerLen) - footerLen
+	if footerStart < int64(len(magic)) {
+		return nil, errors.New("parquet: invalid footer length")
+	}
+	r := &Reader{file: file, opts: opts, footerStart: footerStart, footerLen: footerLen}
+	footer, err := r.read(footerStart, footerLen, 0 /* held */)
+	if err != nil {
+		return nil, err
+	}
+	r.meta, err = decodeFileMetaData(footer)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.readSchema(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// read reads n bytes of the file at offset into memory, after reserving them
+// in addition to the held bytes the reader keeps using.
+func (r *Reader) read(offset, n, held int64) ([]byte, error) {
+	if r.opts.Reserve != nil {
+		if err := r.opts.Reserve(held + n); err != nil {
+			return nil, err
+		}
+	}
+	data := make([]byte, n)
+	if err := readFull(r.file, data, offset); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readFull reads len(data) bytes of file at offset.
+func readFull(file io.ReaderAt, data []byte, offset int64) error {
+	// ReadAt may return io.EOF along with the last bytes of the file.
+	if n, err := file.ReadAt(data, offset); n < len(data) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return errors.Wrap(err, "parquet: reading file")
+	}
+	return nil
+}
+
+// Columns returns the columns of the file.
+func (r *Reader) Columns() []Column {
+	return r.columns
+}
+
+// NumRows returns the number of rows in the file.
+func (r *Reader) NumRows() int64 {
+	return r.meta.numRows
+}
+
+// Next returns the next row of the file, or io.EOF once all the rows have
+// been read. The values of the row have the types accepted by Writer.AddRow,
+// and Int96 values are returned as 12 byte slices. Byte slices may alias the
+// buffered row group, and must not be modified.
+func (r *Reader) Next() ([]interface{}, error) {
+	for r.rowsLeft == 0 {
+		if r.rowGroup == len(r.meta.rowGroups) {
+			return nil, io.EOF
+		}
+		if err := r.loadRowGroup(&r.meta.rowGroups[r.rowGroup]); err != nil {
+			return nil, err
+		}
+		r.rowsLeft = r.meta.rowGroups[r.rowGroup].numRows
+		r.rowGroup++
+	}
+	row := make([]interface{}, len(r.readers))
+	for i := range r.readers {
+		v, err := r.readers[i].next()
+		if err != nil {
+			return nil, err
+		}
+		row[i] = v
+	}
+	r.rowsLeft--
+	return row, nil
+}
+
+// readSchema flattens the schema of the file into columns. Every top-level
+// field must either be a primitive, optionally repeated, or a list of
+// primitives using one of the LIST structures described in
+// LogicalTypes.md.
+func (r *Reader) readSchema() error {
+	schema := r.meta.schema
+	if len(schema) == 0 || schema[0].numChildren == nil {
+		return errors.New("parquet: missing schema")
+	}
+	idx := 1
+	// next returns the next element of the schema, in depth-first order.
+	next := func() (*schemaElement, error) {
+		if idx == len(schema) {
+			return nil, errors.New("parquet: truncated schema")
+		}
+		idx++
+		return &schema[idx-1], nil
+	}
+
+	for i := int32(0); i < *schema[0].numChildren; i++ {
+		top, err := next()
+		if err != nil {
+			return err
+		}
+		var rd columnReader
+		leaf := top
+		def := uint8(0)
+		if repetition(top) != repetitionRequired {
+			def++
+		}
+		switch {
+		case top.typ != nil && repetition(top) == repetitionRepeated:
+			// A repeated primitive is a list of non-null elements.
+			rd.list = true
+			rd.listDef, rd.elemDef = 0, 1
+		case top.typ != nil:
+		case isList(top) && numChildren(top) == 1:
+			rd.list = true
+			rd.listDef = def
+			repeated, err := next()
+			if err != nil {
+				return err
+			}
+			if repetition(repeated) != repetitionRepeated {
+				return errors.Newf("parquet: column %q: invalid list structure", top.name)
+			}
+			def++
+			rd.elemDef = def
+			leaf = repeated
+			if repeated.typ == nil {
+				// The three-level structure, in which the repeated group holds
+				// the element.
+				if numChildren(repeated) != 1 {
+					return errors.Newf("parquet: column %q: lists of groups are not supported", top.name)
+				}
+				if leaf, err = next(); err != nil {
+					return err
+				}
+				if leaf.typ == nil || repetition(leaf) == repetitionRepeated {
+					return errors.Newf("parquet: column %q: nested lists and groups are not supported", top.name)
+				}
+				if repetition(leaf) == repetitionOptional {
+					def++
+				}
+			}
+		default:
+			return errors.Newf("parquet: column %q: groups and maps are not supported", top.name)
+		}
+		rd.maxDef = def
+		if rd.list {
+			rd.maxRep = 1
+		}
+
+		col, err := leafColumn(top.name, leaf)
+		if err != nil {
+			return err
+		}
+		col.List = rd.list
+		rd.col = col
+		r.columns = append(r.columns, col)
+		r.readers = append(r.readers, rd)
+	}
+	if idx != len(schema) {
+		return errors.New("parquet: unexpected schema elements")
+	}
+	for i := range r.meta.rowGroups {
+		if len(r.meta.rowGroups[i].columns) != len(r.columns) {
+			return errors.Newf("parquet: expected %d column chunks in row group %d, found %d",
+				len(r.columns), i, len(r.meta.rowGroups[i].columns))
+		}
+	}
+	return nil
+}
+
+func repetition(s *schemaElement) int32 {
+	if s.repetition == nil {
+		return repetitionRequired
+	}
+	return *s.repetition
+}
+
+func numChildren(s *schemaElement) int32 {
+	if s.numChildren == nil {
+		return 0
+	}
+	return *s.numChildren
+}
+
+func isList(s *schemaElement) bool {
+	if s.logicalType != nil {
+		return s.logicalType.kind == logicalList
+	}
+	return s.convertedType != nil && *s.convertedType == convertedList
+}
+
+// leafColumn returns the column named name whose values are described by
+// leaf. Annotations which the package does not know about are ignored.
+func leafColumn(name string, leaf *schemaElement) (Column, error) {
+	col := Column{Name: name, Type: PhysicalType(*leaf.typ)}
+	if leaf.typeLength != nil {
+		col.TypeLength = *leaf.typeLength
+	}
+	if t := leaf.logicalType; t != nil {
+		switch t.kind {
+		case logicalString:
+			col.Annotation = String
+		case logicalEnum:
+			col.Annotation = Enum
+		case logicalJSON:
+			col.Annotation = JSON
+		case logicalDecimal:
+			col.Annotation = Decimal
+			col.Precision, col.Scale = t.precision, t.scale
+		case logicalDate:
+			col.Annotation = Date
+		case logicalTime:
+			if t.unit == timeUnitMicros {
+				col.Annotation = TimeMicros
+			}
+		case logicalTimestamp:
+			switch {
+			case t.unit == timeUnitMicros && t.adjustedToUTC:
+				col.Annotation = TimestampMicrosUTC
+			case t.unit == timeUnitMicros:
+				col.Annotation = TimestampMicros
+			case t.unit == timeUnitMillis && t.adjustedToUTC:
+				col.Annotation = TimestampMillisUTC
+			case t.unit == timeUnitMillis:
+				col.Annotation = TimestampMillis
+			}
+		case logicalInteger:
+			if t.bitWidth == 16 && t.signed {
+				col.Annotation = Int16
+			}
+		case logicalUUID:
+			col.Annotation = UUID
+		}
+	} else if leaf.convertedType != nil {
+		switch *leaf.convertedType {
+		case convertedUTF8:
+			col.Annotation = String
+		case convertedEnum:
+			col.Annotation = Enum
+		case convertedJSON:
+			col.Annotation = JSON
+		case convertedDecimal:
+			col.Annotation = Decimal
+			if leaf.precision != nil && leaf.scale != nil {
+				col.Precision, col.Scale = *leaf.precision, *leaf.scale
+			}
+		case convertedDate:
+			col.Annotation = Date
+		case convertedTimeMicros:
+			col.Annotation = TimeMicros
+		case convertedTimestampMillis:
+			col.Annotation = TimestampMillisUTC
+		case convertedTimestampMicros:
+			col.Annotation = TimestampMicrosUTC
+		case convertedInt16:
+			col.Annotation = Int16
+		case convertedMap, convertedMapKeyValue:
+			return Column{}, errors.Newf("parquet: column %q: maps are not supported", name)
+		}
+	}
+	if err := col.Validate(); err != nil {
+		return Column{}, err
+	}
+	return col, nil
+}
+
+// loadRowGroup reads a row group into memory and decodes its column chunks.
+func (r *Reader) loadRowGroup(rg *rowGroup) error {
+	if len(rg.columns) != len(r.readers) {
+		return errors.Newf("parquet: expected %d column chunks, found %d", len(r.readers), len(rg.columns))
+	}
+	if len(rg.columns) == 0 {
+		return nil
+	}
+	// The column chunks of a row group are contiguous, so the whole group is
+	// read at once.
+	start, end := r.footerStart, int64(len(magic))
+	for i := range rg.columns {
+		chunkStart, chunkEnd := rg.columns[i].byteRange()
+		if chunkStart < start {
+			start = chunkStart
+		}
+		if chunkEnd > end {
+			end = chunkEnd
+		}
+	}
+	if start < int64(len(magic)) || end > r.footerStart || start > end {
+		return errors.New("parquet: invalid row group offsets")
+	}
+	data, err := r.read(start, end-start, r.footerLen)
+	if err != nil {
+		return err
+	}
+	for i := range r.readers {
+		if err := r.readers[i].load(data, start, &rg.columns[i]); err != nil {
+			return errors.Wrapf(err, "parquet: column %q", r.columns[i].Name)
+		}
+	}
+	return nil
+}
+
+// columnReader holds the decoded levels and values of a column chunk.
+type columnReader struct {
+	col            Column
+	maxDef, maxRep uint8
+	// list is set if the column holds lists, in which case listDef is the
+	// definition level at which the list is defined, and elemDef the level
+	// at which its element is present, which may be NULL if it is less than
+	// maxDef.
+	list             bool
+	listDef, elemDef uint8
+
+	defLevels, repLevels []uint8
+	// values holds the non-null values of the chunk.
+	values      []interface{}
+	dictionary  []interface{}
+	levelPos    int
+	valuePos    int
+	totalLevels int
+}
+
+// next returns the value of the column in the next row.
+func (c *columnReader) next() (interface{}, error) {
+	if c.levelPos >= c.totalLevels {
+		return nil, errors.Newf("parquet: column %q has too few values", c.col.Name)
+	}
+	if !c.list {
+		return c.nextValue(c.level(c.defLevels, c.maxDef)), nil
+	}
+	if c.level(c.repLevels, 0) != 0 {
+		return nil, errors.Newf("parquet: column %q: unexpected repetition level", c.col.Name)
+	}
+	def := c.level(c.defLevels, c.maxDef)
+	if def < c.listDef {
+		c.levelPos++
+		return nil, nil
+	}
+	list := []interface{}{}
+	if def < c.elemDef {
+		c.levelPos++
+		return list, nil
+	}
+	for {
+		list = append(list, c.nextValue(c.level(c.defLevels, c.maxDef)))
+		if c.levelPos == c.totalLevels || c.level(c.repLevels, 0) == 0 {
+			return list, nil
+		}
+	}
+}
+
+// level returns the current level in levels, or def if levels were omitted.
+func (c *columnReader) level(levels []uint8, def uint8) uint8 {
+	if levels == nil {
+		return def
+	}
+	return levels[c.levelPos]
+}
+
+// nextValue consumes the current level, returning the value it defines.
+func (c *columnReader) nextValue(def uint8) interface{} {
+	c.levelPos++
+	if def < c.maxDef {
+		return nil
+	}
+	v := c.values[c.valuePos]
+	c.valuePos++
+	return v
+}
+
+// load decodes all the pages of a column chunk, reading them from data, which
+// holds the part of the file starting at base.
+func (c *columnReader) load(data []byte, base int64, chunk *columnChunk) error {
+	if chunk.filePath != "" {
+		return errors.New("column chunks stored in other files are not supported")
+	}
+	m := &chunk.metaData
+	if m.typ != int32(c.col.Type) {
+		return errors.Newf("expected physical type %d, found %d", c.col.Type, m.typ)
+	}
+	c.defLevels, c.repLevels, c.values, c.dictionary = nil, nil, nil, nil
+	c.levelPos, c.valuePos, c.totalLevels = 0, 0, 0
+	if c.maxDef > 0 {
+		c.defLevels = []uint8{}
+	}
+	if c.maxRep > 0 {
+		c.repLevels = []uint8{}
+	}
+
+	offset, _ := chunk.byteRange()
+	offset -= base
+	for int64(c.totalLevels) < m.numValues {
+		if offset < 0 || offset >= int64(len(data)) {
+			return errors.New("invalid page offset")
+		}
+		h, n, err := decodePageHeader(data[offset:])
+		if err != nil {
+			return err
+		}
+		offset += int64(n)
+		end := offset + int64(h.compressedPageSize)
+		if h.compressedPageSize < 0 || end > int64(len(data)) {
+			return errors.New("invalid page size")
+		}
+		page := data[offset:end]
+		offset = end
+
+		switch h.typ {
+		case pageTypeDictionary:
+			err = c.readDictionaryPage(&h, page, CompressionCodec(m.codec))
+		case pageTypeData:
+			err = c.readDataPage(&h, page, CompressionCodec(m.codec))
+		case pageTypeDataV2:
+			err = c.readDataPageV2(&h, page, CompressionCodec(m.codec))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *columnReader) readDictionaryPage(
+	h *pageHeader, page []byte, codec CompressionCodec,
+) (err error) {
+	switch h.dictionaryPageHeader.encoding {
+	case encodingPlain, encodingPlainDictionary:
+	default:
+		return errors.Newf("unsupported dictionary encoding %d", h.dictionaryPageHeader.encoding)
+	}
+	if page, err = decompress(codec, page, h.uncompressedPageSize); err != nil {
+		return err
+	}
+	c.dictionary, err = decodePlain(&c.col, page, int(h.dictionaryPageHeader.numValues))
+	return err
+}
+
+func (c *columnReader) readDataPage(h *pageHeader, page []byte, codec CompressionCodec) error {
+	page, err := decompress(codec, page, h.uncompressedPageSize)
+	if err != nil {
+		return err
+	}
+	dh := &h.dataPageHeader
+	numLevels := int(dh.numValues)
+	if c.maxRep > 0 {
+		if dh.repetitionLevelEncoding != encodingRLE {
+			return errors.Newf("unsupported level encoding %d", dh.repetitionLevelEncoding)
+		}
+		if page, err = c.readLevels(&c.repLevels, page, c.maxRep, numLevels, -1); err != nil {
+			return err
+		}
+	}
+	if c.maxDef > 0 {
+		if dh.definitionLevelEncoding != encodingRLE {
+			return errors.Newf("unsupported level encoding %d", dh.definitionLevelEncoding)
+		}
+		if page, err = c.readLevels(&c.defLevels, page, c.maxDef, numLevels, -1); err != nil {
+			return err
+		}
+	}
+	return c.readValues(page, dh.encoding, numLevels)
+}
+
+func (c *columnReader) readDataPageV2(h *pageHeader, page []byte, codec CompressionCodec) error {
+	dh := &h.dataPageHeaderV2
+	numLevels := int(dh.numValues)
+	levelsLen := int(dh.repetitionLevelsByteLength) + int(dh.definitionLevelsByteLength)
+	if dh.repetitionLevelsByteLength < 0 || dh.definitionLevelsByteLength < 0 || levelsLen > len(page) {
+		return errors.New("invalid level lengths")
+	}
+	levels, values := page[:levelsLen], page[levelsLen:]
+	var err error
+	if c.maxRep > 0 {
+		if _, err = c.readLevels(&c.repLevels, levels, c.maxRep, numLevels,
+			int(dh.repetitionLevelsByteLength)); err != nil {
+			return err
+		}
+	}
+	if c.maxDef > 0 {
+		if _, err = c.readLevels(&c.defLevels, levels[dh.repetitionLevelsByteLength:], c.maxDef,
+			numLevels, int(dh.definitionLevelsByteLength)); err != nil {
+			return err
+		}
+	}
+	if dh.isCompressed {
+		if values, err = decompress(codec, values, h.uncompressedPageSize-int32(levelsLen)); err != nil {
+			return err
+		}
+	}
+	return c.readValues(values, dh.encoding, numLevels)
+}
+
+// readLevels decodes n levels at the start of data, and appends them to
+// levels. If length is negative, the levels are preceded by their encoded
+// length. It returns the remainder of data.
+func (c *columnReader) readLevels(
+	levels *[]uint8, data []byte, maxLevel uint8, n int, length int,
+) ([]byte, error) {
+	if length < 0 {
+		if len(data) < 4 {
+			return nil, errors.New("truncated levels")
+		}
+		length = int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+	}
+	if length > len(data) {
+		return nil, errors.New("truncated levels")
+	}
+	decoded, err := decodeHybrid(data[:length], bits.Len8(maxLevel), n)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range decoded {
+		if l > uint32(maxLevel) {
+			return nil, errors.Newf("invalid level %d", l)
+		}
+		*levels = append(*levels, uint8(l))
+	}
+	return data[length:], nil
+}
+
+// readValues decodes the non-null values of a page with numLevels levels,
+// whose levels have already been read.
+func (c *columnReader) readValues(data []byte, encoding int32, numLevels int) error {
+	n := numLevels
+	if c.maxDef > 0 {
+		n = 0
+		for _, l := range c.defLevels[len(c.defLevels)-numLevels:] {
+			if l == c.maxDef {
+				n++
+			}
+		}
+	}
+	c.totalLevels += numLevels
+
+	switch encoding {
+	case encodingPlain:
+		values, err := decodePlain(&c.col, data, n)
+		if err != nil {
+			return err
+		}
+		c.values = append(c.values, values...)
+
+	case encodingPlainDictionary, encodingRLEDictionary:
+		if c.dictionary == nil {
+			return errors.New("missing dictionary page")
+		}
+		if n == 0 {
+			return nil
+		}
+		if len(data) == 0 {
+			return errors.New("truncated page")
+		}
+		indexes, err := decodeHybrid(data[1:], int(data[0]), n)
+		if err != nil {
+			return err
+		}
+		for _, idx := range indexes {
+			if int(idx) >= len(c.dictionary) {
+				return errors.Newf("invalid dictionary index %d", idx)
+			}
+			c.values = append(c.values, c.dictionary[idx])
+		}
+
+	case encodingRLE:
+		// Booleans may be encoded using the RLE/bit-packing hybrid encoding,
+		// preceded by their encoded length.
+		if c.col.Type != Boolean {
+			return errors.Newf("unsupported encoding %d for physical type %d", encoding, c.col.Type)
+		}
+		var bools []uint8
+		if _, err := c.readLevels(&bools, data, 1, n, -1); err != nil {
+			return err
+		}
+		for _, b := range bools {
+			c.values = append(c.values, b == 1)
+		}
+
+	default:
+		return errors.Newf("unsupported encoding %d", encoding)
+	}
+	return nil
+}
+
+// decompress returns the decompressed contents of a page.
+func decompress(codec CompressionCodec, data []byte, uncompressedSize int32) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		return snappy.Decode(nil, data)
+	case Gzip:
+		if uncompressedSize < 0 {
+			return nil, errors.New("invalid page size")
+		}
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		buf := bytes.NewBuffer(make([]byte, 0, uncompressedSize))
+		if _, err := buf.ReadFrom(gr); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Newf("unsupported compression codec %d", codec)
+	}
+}
+
+// decodePlain decodes n PLAIN encoded values of the column's physical type.
+func decodePlain(col *Column, data []byte, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	if col.Type == Boolean {
+		if len(data)*8 < n {
+			return nil, errors.New("truncated values")
+		}
+		for i := range values {
+			values[i] = data[i/8]&(1<<(i%8)) != 0
+		}
+		return values, nil
+	}
+
+	for i := range values {
+		size := 0
+		switch col.Type {
+		case Int32, Float:
+			size = 4
+		case Int64, Double:
+			size = 8
+		case Int96:
+			size = 12
+		case FixedLenByteArray:
+			size = int(col.TypeLength)
+		case ByteArray:
+			if len(data) < 4 {
+				return nil, errors.New("truncated values")
+			}
+			size = int(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return nil, errors.Newf("unsupported physical type %d", col.Type)
+		}
+		if size < 0 || size > len(data) {
+			return nil, errors.New("truncated values")
+		}
+		switch col.Type {
+		case Int32:
+			values[i] = int32(binary.LittleEndian.Uint32(data))
+		case Int64:
+			values[i] = int64(binary.LittleEndian.Uint64(data))
+		case Float:
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(data))
+		case Double:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data))
+		default:
+			values[i] = data[:size:size]
+		}
+		data = data[size:]
+	}
+	return values, nil
+}
+
+// decodeHybrid decodes n values encoded using the RLE/bit-packing hybrid
+// encoding with the specified bit width.
+func decodeHybrid(data []byte, bitWidth int, n int) ([]uint32, error) {
+	if bitWidth > 32 {
+		return nil, errors.Newf("invalid bit width %d", bitWidth)
+	}
+	byteWidth := (bitWidth + 7) / 8
+	values := make([]uint32, 0, n)
+	for len(values) < n {
+		header, k := binary.Uvarint(data)
+		if k <= 0 {
+			return nil, errors.New("truncated RLE data")
+		}
+		data = data[k:]
+		if header&1 == 0 {
+			// An RLE run of a single value.
+			count := header >> 1
+			if len(data) < byteWidth {
+				return nil, errors.New("truncated RLE data")
+			}
+			var v uint32
+			for i := 0; i < byteWidth; i++ {
+				v |= uint32(data[i]) << (8 * i)
+			}
+			data = data[byteWidth:]
+			for ; count > 0 && len(values) < n; count-- {
+				values = append(values, v)
+			}
+			continue
+		}
+		// A run of groups of 8 bit-packed values, least significant bit first.
+		groups := header >> 1
+		size := groups * uint64(bitWidth)
+		if size > uint64(len(data)) {
+			return nil, errors.New("truncated RLE data")
+		}
+		packed := data[:size]
+		data = data[size:]
+		for i := uint64(0); i < groups*8 && len(values) < n; i++ {
+			var v uint32
+			for b := 0; b < bitWidth; b++ {
+				bit := i*uint64(bitWidth) + uint64(b)
+				v |= uint32(packed[bit/8]>>(bit%8)&1) << b
+			}
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,355 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, data []byte) ([]Column, [][]interface{}) {
+	r, err := NewReader(data)
+	require.NoError(t, err)
+	var rows [][]interface{}
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+	require.Equal(t, r.NumRows(), int64(len(rows)))
+	return r.Columns(), rows
+}
+
+func TestDecodeHybrid(t *testing.T) {
+	for _, tc := range []struct {
+		data     []byte
+		bitWidth int
+		n        int
+		expected []uint32
+	}{
+		// The bit-packed example from Encodings.md.
+		{[]byte{3, 0x88, 0xc6, 0xfa}, 3, 8, []uint32{0, 1, 2, 3, 4, 5, 6, 7}},
+		// Trailing bit-packed values are ignored.
+		{[]byte{3, 0x88, 0xc6, 0xfa}, 3, 5, []uint32{0, 1, 2, 3, 4}},
+		{[]byte{3 << 1, 1, 1 << 1, 0}, 1, 4, []uint32{1, 1, 1, 0}},
+		{[]byte{2 << 1, 0x34, 0x12}, 13, 2, []uint32{0x1234, 0x1234}},
+		{[]byte{5 << 1}, 0, 5, []uint32{0, 0, 0, 0, 0}},
+	} {
+		values, err := decodeHybrid(tc.data, tc.bitWidth, tc.n)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, values)
+	}
+
+	_, err := decodeHybrid([]byte{3 << 1, 1}, 1, 4)
+	require.Error(t, err)
+	_, err = decodeHybrid([]byte{3, 0x88}, 3, 8)
+	require.Error(t, err)
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "b", Type: Boolean},
+		{Name: "i", Type: Int32, Annotation: Int16},
+		{Name: "f", Type: Float},
+		{Name: "s", Type: ByteArray, Annotation: String},
+		{Name: "d", Type: ByteArray, Annotation: Decimal, Precision: 10, Scale: 2},
+		{Name: "ts", Type: Int64, Annotation: TimestampMillis},
+		{Name: "u", Type: FixedLenByteArray, TypeLength: 16, Annotation: UUID},
+		{Name: "l", Type: Double, List: true},
+	}
+	rows := [][]interface{}{
+		{true, int32(1), float32(1.5), []byte("a"), []byte{0x04, 0xd2}, int64(1000),
+			bytes.Repeat([]byte{1}, 16), []interface{}{1.5, nil, 2.5}},
+		{nil, nil, nil, nil, nil, nil, nil, nil},
+		{false, int32(-1), float32(0), []byte(""), []byte{0xff}, int64(0),
+			make([]byte, 16), []interface{}{}},
+		{true, int32(3), float32(-2), []byte("xyz"), []byte{0x01}, int64(-1),
+			bytes.Repeat([]byte{2}, 16), []interface{}{nil}},
+	}
+	for _, codec := range []CompressionCodec{Uncompressed, Gzip} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, columns, WriterOptions{Compression: codec})
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, w.AddRow(row))
+		}
+		require.NoError(t, w.Close())
+
+		readColumns, readRows := readAll(t, buf.Bytes())
+		require.Equal(t, columns, readColumns)
+		require.Equal(t, rows, readRows)
+	}
+
+	// A file without rows has no row groups.
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, columns, WriterOptions{})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	readColumns, readRows := readAll(t, buf.Bytes())
+	require.Equal(t, columns, readColumns)
+	require.Empty(t, readRows)
+}
+
+func TestReaderReserve(t *testing.T) {
+	columns := []Column{{Name: "s", Type: ByteArray, Annotation: String}}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, columns, WriterOptions{})
+	require.NoError(t, err)
+	require.NoError(t, w.AddRow([]interface{}{[]byte("abc")}))
+	require.NoError(t, w.Close())
+	data := buf.Bytes()
+
+	// The footer is held while each row group is read.
+	var reserved []int64
+	r, err := NewFileReader(bytes.NewReader(data), int64(len(data)), ReaderOptions{
+		Reserve: func(bytes int64) error {
+			reserved = append(reserved, bytes)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	row, err := r.Next()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{[]byte("abc")}, row)
+	_, err = r.Next()
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 2, len(reserved))
+	footerLen := int64(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	require.Equal(t, footerLen, reserved[0])
+	require.Less(t, reserved[0], reserved[1])
+	require.Less(t, reserved[1], int64(len(data)))
+
+	// Errors reserving memory are returned.
+	r, err = NewFileReader(bytes.NewReader(data), int64(len(data)), ReaderOptions{
+		Reserve: func(bytes int64) error {
+			if bytes > footerLen {
+				return errors.New("out of memory")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	_, err = r.Next()
+	require.EqualError(t, err, "out of memory")
+
+	// Files are not read past their size.
+	_, err = NewFileReader(bytes.NewReader(data[:len(data)-1]), int64(len(data)), ReaderOptions{})
+	require.Error(t, err)
+}
+
+// TestReaderEncodings reads a file using features the writer does not, like
+// required and repeated fields, dictionary encoding, version 2 data pages,
+// snappy compression and multiple row groups.
+func TestReaderEncodings(t *testing.T) {
+	var file bytes.Buffer
+	file.WriteString(magic)
+	writePage := func(h pageHeader, data []byte) int64 {
+		offset := int64(file.Len())
+		h.compressedPageSize = int32(len(data))
+		h.dataPageHeader.definitionLevelEncoding = encodingRLE
+		h.dataPageHeader.repetitionLevelEncoding = encodingRLE
+		if h.uncompressedPageSize == 0 {
+			h.uncompressedPageSize = int32(len(data))
+		}
+		header, err := encodePageHeader(&h)
+		require.NoError(t, err)
+		file.Write(header)
+		file.Write(data)
+		return offset
+	}
+	levels := func(maxLevel uint8, l ...uint8) []byte {
+		return encodeLevels(l, maxLevel)
+	}
+	cat := func(b ...[]byte) []byte {
+		return bytes.Join(b, nil)
+	}
+	plainInt64s := func(vals ...int64) []byte {
+		b := make([]byte, 8*len(vals))
+		for i, v := range vals {
+			binary.LittleEndian.PutUint64(b[8*i:], uint64(v))
+		}
+		return b
+	}
+	plainStrings := func(vals ...string) []byte {
+		var b bytes.Buffer
+		for _, v := range vals {
+			_ = binary.Write(&b, binary.LittleEndian, uint32(len(v)))
+			b.WriteString(v)
+		}
+		return b.Bytes()
+	}
+
+	var rowGroups []rowGroup
+	chunk := func(typ PhysicalType, path []string, codec CompressionCodec, numValues int64,
+		dataPageOffset int64, dictionaryPageOffset *int64) columnChunk {
+		return columnChunk{
+			fileOffset: dataPageOffset,
+			metaData: columnMetaData{
+				typ:                  int32(typ),
+				encodings:            []int32{encodingPlain},
+				pathInSchema:         path,
+				codec:                int32(codec),
+				numValues:            numValues,
+				dataPageOffset:       dataPageOffset,
+				dictionaryPageOffset: dictionaryPageOffset,
+			},
+		}
+	}
+
+	// contiguous sets the sizes of the chunks of a row group, which were
+	// written in order and end with the file.
+	contiguous := func(chunks ...columnChunk) []columnChunk {
+		for i := range chunks {
+			start, _ := chunks[i].byteRange()
+			end := int64(file.Len())
+			if i+1 < len(chunks) {
+				end, _ = chunks[i+1].byteRange()
+			}
+			chunks[i].metaData.totalCompressedSize = end - start
+		}
+		return chunks
+	}
+
+	// The first row group holds two rows, split into two pages.
+	{
+		// A required INT64 column, in PLAIN encoded pages.
+		id := writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 1, encoding: encodingPlain,
+		}}, plainInt64s(1))
+		writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 1, encoding: encodingPlain,
+		}}, plainInt64s(2))
+		// An optional string column with a snappy compressed dictionary.
+		dict := snappy.Encode(nil, plainStrings("x", "y"))
+		dictOffset := writePage(pageHeader{
+			typ:                  pageTypeDictionary,
+			uncompressedPageSize: int32(len(plainStrings("x", "y"))),
+			dictionaryPageHeader: dictionaryPageHeader{numValues: 2, encoding: encodingPlainDictionary},
+		}, dict)
+		dataPage := cat(levels(1, 1, 1), []byte{1, 1<<1 | 1, 0x02})
+		s := writePage(pageHeader{
+			typ:                  pageTypeData,
+			uncompressedPageSize: int32(len(dataPage)),
+			dataPageHeader: dataPageHeader{
+				numValues: 2, encoding: encodingRLEDictionary,
+			},
+		}, snappy.Encode(nil, dataPage))
+		// A two-level list of required INT64s, in a version 2 data page.
+		repLevels, defLevels := levels(1, 0, 1, 0)[4:], levels(2, 2, 2, 1)[4:]
+		l := writePage(pageHeader{typ: pageTypeDataV2, dataPageHeaderV2: dataPageHeaderV2{
+			numValues: 3, numRows: 2, encoding: encodingPlain,
+			repetitionLevelsByteLength: int32(len(repLevels)),
+			definitionLevelsByteLength: int32(len(defLevels)),
+		}}, cat(repLevels, defLevels, plainInt64s(7, 8)))
+		// A repeated boolean, RLE encoded.
+		b := writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 3, encoding: encodingRLE,
+		}}, cat(levels(1, 0, 0, 1), levels(1, 1, 1, 1), levels(1, 1, 0, 1)))
+
+		rowGroups = append(rowGroups, rowGroup{numRows: 2, columns: contiguous(
+			chunk(Int64, []string{"id"}, Uncompressed, 2, id, nil),
+			chunk(ByteArray, []string{"s"}, Snappy, 2, s, &dictOffset),
+			chunk(Int64, []string{"l", "array"}, Uncompressed, 3, l, nil),
+			chunk(Boolean, []string{"b"}, Uncompressed, 3, b, nil),
+		)})
+	}
+	// The second row group holds a single row.
+	{
+		id := writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 1, encoding: encodingPlain,
+		}}, plainInt64s(3))
+		s := writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 1, encoding: encodingPlain,
+		}}, levels(1, 0))
+		l := writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 1, encoding: encodingPlain,
+		}}, cat(levels(1, 0), levels(2, 0)))
+		b := writePage(pageHeader{typ: pageTypeData, dataPageHeader: dataPageHeader{
+			numValues: 1, encoding: encodingPlain,
+		}}, cat(levels(1, 0), levels(1, 0)))
+		rowGroups = append(rowGroups, rowGroup{numRows: 1, columns: contiguous(
+			chunk(Int64, []string{"id"}, Uncompressed, 1, id, nil),
+			chunk(ByteArray, []string{"s"}, Uncompressed, 1, s, nil),
+			chunk(Int64, []string{"l", "array"}, Uncompressed, 1, l, nil),
+			chunk(Boolean, []string{"b"}, Uncompressed, 1, b, nil),
+		)})
+	}
+
+	required, optional, repeated := repetitionRequired, repetitionOptional, repetitionRepeated
+	typ := func(t PhysicalType) *int32 { return i32(int32(t)) }
+	footer, err := encodeFileMetaData(&fileMetaData{
+		version: 1,
+		schema: []schemaElement{
+			{name: "spark_schema", numChildren: i32(4)},
+			{name: "id", typ: typ(Int64), repetition: &required},
+			{name: "s", typ: typ(ByteArray), repetition: &optional, convertedType: i32(convertedUTF8)},
+			{name: "l", repetition: &optional, numChildren: i32(1), convertedType: i32(convertedList)},
+			{name: "array", typ: typ(Int64), repetition: &repeated},
+			{name: "b", typ: typ(Boolean), repetition: &repeated},
+		},
+		numRows:   3,
+		rowGroups: rowGroups,
+	})
+	require.NoError(t, err)
+	file.Write(footer)
+	_ = binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(magic)
+
+	columns, rows := readAll(t, file.Bytes())
+	require.Equal(t, []Column{
+		{Name: "id", Type: Int64},
+		{Name: "s", Type: ByteArray, Annotation: String},
+		{Name: "l", Type: Int64, List: true},
+		{Name: "b", Type: Boolean, List: true},
+	}, columns)
+	require.Equal(t, [][]interface{}{
+		{int64(1), []byte("x"), []interface{}{int64(7), int64(8)}, []interface{}{true}},
+		{int64(2), []byte("y"), []interface{}{}, []interface{}{false, true}},
+		{int64(3), nil, nil, []interface{}{}},
+	}, rows)
+}
+
+func TestReaderErrors(t *testing.T) {
+	_, err := NewReader([]byte("PAR1"))
+	require.Error(t, err)
+	_, err = NewReader([]byte("PAR1\x00\x00\x00\x00PAR2"))
+	require.Error(t, err)
+	_, err = NewReader([]byte("PAR1\xff\x00\x00\x00PAR1"))
+	require.Error(t, err)
+
+	// Groups are not supported.
+	optional := repetitionOptional
+	footer, err := encodeFileMetaData(&fileMetaData{
+		version: 1,
+		schema: []schemaElement{
+			{name: "schema", numChildren: i32(1)},
+			{name: "g", repetition: &optional, numChildren: i32(1)},
+			{name: "x", typ: i32(int32(Int32)), repetition: &optional},
+		},
+	})
+	require.NoError(t, err)
+	var file bytes.Buffer
+	file.WriteString(magic)
+	file.Write(footer)
+	_ = binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(magic)
+	_, err = NewReader(file.Bytes())
+	require.EqualError(t, err, `parquet: column "g": groups and maps are not supported`)
+}
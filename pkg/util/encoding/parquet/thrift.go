@@ -0,0 +1,780 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/cockroachdb/errors"
+)
+
+// This file contains the subset of the structs defined in parquet.thrift that
+// is needed to read and write files, along with their Thrift compact protocol
+// encoding. Field IDs match parquet.thrift. Fields we do not understand are
+// skipped when decoding.
+
+// Values of the enums defined in parquet.thrift.
+const (
+	repetitionRequired int32 = 0
+	repetitionOptional int32 = 1
+	repetitionRepeated int32 = 2
+
+	encodingPlain           int32 = 0
+	encodingPlainDictionary int32 = 2
+	encodingRLE             int32 = 3
+	encodingRLEDictionary   int32 = 8
+
+	pageTypeData       int32 = 0
+	pageTypeDictionary int32 = 2
+	pageTypeDataV2     int32 = 3
+
+	convertedUTF8            int32 = 0
+	convertedMap             int32 = 1
+	convertedMapKeyValue     int32 = 2
+	convertedList            int32 = 3
+	convertedEnum            int32 = 4
+	convertedDecimal         int32 = 5
+	convertedDate            int32 = 6
+	convertedTimeMicros      int32 = 8
+	convertedTimestampMillis int32 = 9
+	convertedTimestampMicros int32 = 10
+	convertedInt16           int32 = 16
+	convertedJSON            int32 = 19
+)
+
+// Field IDs of the members of the LogicalType union.
+const (
+	logicalString    int16 = 1
+	logicalMap       int16 = 2
+	logicalList      int16 = 3
+	logicalEnum      int16 = 4
+	logicalDecimal   int16 = 5
+	logicalDate      int16 = 6
+	logicalTime      int16 = 7
+	logicalTimestamp int16 = 8
+	logicalInteger   int16 = 10
+	logicalJSON      int16 = 12
+	logicalUUID      int16 = 14
+)
+
+// Field IDs of the members of the TimeUnit union.
+const (
+	timeUnitMillis int16 = 1
+	timeUnitMicros int16 = 2
+)
+
+// logicalType mirrors the LogicalType union.
+type logicalType struct {
+	kind int16
+	// Set for logicalDecimal.
+	scale, precision int32
+	// Set for logicalTime and logicalTimestamp.
+	adjustedToUTC bool
+	unit          int16
+	// Set for logicalInteger.
+	bitWidth int8
+	signed   bool
+}
+
+// schemaElement mirrors the SchemaElement struct. Optional fields are nil when
+// unset.
+type schemaElement struct {
+	typ           *int32
+	typeLength    *int32
+	repetition    *int32
+	name          string
+	numChildren   *int32
+	convertedType *int32
+	scale         *int32
+	precision     *int32
+	logicalType   *logicalType
+}
+
+// dataPageHeader mirrors the DataPageHeader struct.
+type dataPageHeader struct {
+	numValues               int32
+	encoding                int32
+	definitionLevelEncoding int32
+	repetitionLevelEncoding int32
+}
+
+// dictionaryPageHeader mirrors the DictionaryPageHeader struct.
+type dictionaryPageHeader struct {
+	numValues int32
+	encoding  int32
+}
+
+// dataPageHeaderV2 mirrors the DataPageHeaderV2 struct.
+type dataPageHeaderV2 struct {
+	numValues                  int32
+	numNulls                   int32
+	numRows                    int32
+	encoding                   int32
+	definitionLevelsByteLength int32
+	repetitionLevelsByteLength int32
+	isCompressed               bool
+}
+
+// pageHeader mirrors the PageHeader struct. Only the header matching typ is
+// set.
+type pageHeader struct {
+	typ                  int32
+	uncompressedPageSize int32
+	compressedPageSize   int32
+	dataPageHeader       dataPageHeader
+	dictionaryPageHeader dictionaryPageHeader
+	dataPageHeaderV2     dataPageHeaderV2
+}
+
+// columnMetaData mirrors the ColumnMetaData struct.
+type columnMetaData struct {
+	typ                   int32
+	encodings             []int32
+	pathInSchema          []string
+	codec                 int32
+	numValues             int64
+	totalUncompressedSize int64
+	totalCompressedSize   int64
+	dataPageOffset        int64
+	// dictionaryPageOffset is nil if the chunk has no dictionary page.
+	dictionaryPageOffset *int64
+}
+
+// columnChunk mirrors the ColumnChunk struct.
+type columnChunk struct {
+	// filePath is set if the chunk is stored in another file.
+	filePath   string
+	fileOffset int64
+	metaData   columnMetaData
+}
+
+// byteRange returns the offsets at which the pages of the chunk start and end.
+func (c *columnChunk) byteRange() (start, end int64) {
+	m := &c.metaData
+	start = m.dataPageOffset
+	if m.dictionaryPageOffset != nil && *m.dictionaryPageOffset > 0 && *m.dictionaryPageOffset < start {
+		start = *m.dictionaryPageOffset
+	}
+	return start, start + m.totalCompressedSize
+}
+
+// rowGroup mirrors the RowGroup struct.
+type rowGroup struct {
+	columns       []columnChunk
+	totalByteSize int64
+	numRows       int64
+}
+
+// fileMetaData mirrors the FileMetaData struct.
+type fileMetaData struct {
+	version   int32
+	schema    []schemaElement
+	numRows   int64
+	rowGroups []rowGroup
+	createdBy string
+}
+
+// thriftEncoder encodes structs using the Thrift compact protocol. Errors are
+// sticky and returned by finish.
+type thriftEncoder struct {
+	buf *thrift.TMemoryBuffer
+	p   *thrift.TCompactProtocol
+	err error
+}
+
+func newThriftEncoder() *thriftEncoder {
+	buf := thrift.NewTMemoryBuffer()
+	return &thriftEncoder{buf: buf, p: thrift.NewTCompactProtocol(buf)}
+}
+
+func (e *thriftEncoder) check(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *thriftEncoder) finish() ([]byte, error) {
+	e.check(e.p.Flush(context.Background()))
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// writeStruct writes a struct whose fields are written by fn.
+func (e *thriftEncoder) writeStruct(fn func()) {
+	e.check(e.p.WriteStructBegin(""))
+	fn()
+	e.check(e.p.WriteFieldStop())
+	e.check(e.p.WriteStructEnd())
+}
+
+func (e *thriftEncoder) structField(id int16, fn func()) {
+	e.check(e.p.WriteFieldBegin("", thrift.STRUCT, id))
+	e.writeStruct(fn)
+	e.check(e.p.WriteFieldEnd())
+}
+
+func (e *thriftEncoder) emptyStructField(id int16) {
+	e.structField(id, func() {})
+}
+
+func (e *thriftEncoder) boolField(id int16, v bool) {
+	e.check(e.p.WriteFieldBegin("", thrift.BOOL, id))
+	e.check(e.p.WriteBool(v))
+	e.check(e.p.WriteFieldEnd())
+}
+
+func (e *thriftEncoder) byteField(id int16, v int8) {
+	e.check(e.p.WriteFieldBegin("", thrift.BYTE, id))
+	e.check(e.p.WriteByte(v))
+	e.check(e.p.WriteFieldEnd())
+}
+
+func (e *thriftEncoder) i32Field(id int16, v int32) {
+	e.check(e.p.WriteFieldBegin("", thrift.I32, id))
+	e.check(e.p.WriteI32(v))
+	e.check(e.p.WriteFieldEnd())
+}
+
+func (e *thriftEncoder) optionalI32Field(id int16, v *int32) {
+	if v != nil {
+		e.i32Field(id, *v)
+	}
+}
+
+func (e *thriftEncoder) i64Field(id int16, v int64) {
+	e.check(e.p.WriteFieldBegin("", thrift.I64, id))
+	e.check(e.p.WriteI64(v))
+	e.check(e.p.WriteFieldEnd())
+}
+
+func (e *thriftEncoder) stringField(id int16, v string) {
+	e.check(e.p.WriteFieldBegin("", thrift.STRING, id))
+	e.check(e.p.WriteString(v))
+	e.check(e.p.WriteFieldEnd())
+}
+
+// listField writes a list of n elements, each of which is written by fn.
+func (e *thriftEncoder) listField(id int16, elemType thrift.TType, n int, fn func(i int)) {
+	e.check(e.p.WriteFieldBegin("", thrift.LIST, id))
+	e.check(e.p.WriteListBegin(elemType, n))
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+	e.check(e.p.WriteListEnd())
+	e.check(e.p.WriteFieldEnd())
+}
+
+func (e *thriftEncoder) logicalType(t *logicalType) {
+	e.writeStruct(func() {
+		switch t.kind {
+		case logicalDecimal:
+			e.structField(t.kind, func() {
+				e.i32Field(1, t.scale)
+				e.i32Field(2, t.precision)
+			})
+		case logicalTime, logicalTimestamp:
+			e.structField(t.kind, func() {
+				e.boolField(1, t.adjustedToUTC)
+				e.structField(2, func() {
+					e.emptyStructField(t.unit)
+				})
+			})
+		case logicalInteger:
+			e.structField(t.kind, func() {
+				e.byteField(1, t.bitWidth)
+				e.boolField(2, t.signed)
+			})
+		default:
+			e.emptyStructField(t.kind)
+		}
+	})
+}
+
+func (e *thriftEncoder) schemaElement(s *schemaElement) {
+	e.writeStruct(func() {
+		e.optionalI32Field(1, s.typ)
+		e.optionalI32Field(2, s.typeLength)
+		e.optionalI32Field(3, s.repetition)
+		e.stringField(4, s.name)
+		e.optionalI32Field(5, s.numChildren)
+		e.optionalI32Field(6, s.convertedType)
+		e.optionalI32Field(7, s.scale)
+		e.optionalI32Field(8, s.precision)
+		if s.logicalType != nil {
+			e.check(e.p.WriteFieldBegin("", thrift.STRUCT, 10))
+			e.logicalType(s.logicalType)
+			e.check(e.p.WriteFieldEnd())
+		}
+	})
+}
+
+func (e *thriftEncoder) pageHeader(h *pageHeader) {
+	e.writeStruct(func() {
+		e.i32Field(1, h.typ)
+		e.i32Field(2, h.uncompressedPageSize)
+		e.i32Field(3, h.compressedPageSize)
+		switch h.typ {
+		case pageTypeData:
+			e.structField(5, func() {
+				d := &h.dataPageHeader
+				e.i32Field(1, d.numValues)
+				e.i32Field(2, d.encoding)
+				e.i32Field(3, d.definitionLevelEncoding)
+				e.i32Field(4, d.repetitionLevelEncoding)
+			})
+		case pageTypeDictionary:
+			e.structField(7, func() {
+				d := &h.dictionaryPageHeader
+				e.i32Field(1, d.numValues)
+				e.i32Field(2, d.encoding)
+			})
+		case pageTypeDataV2:
+			e.structField(8, func() {
+				d := &h.dataPageHeaderV2
+				e.i32Field(1, d.numValues)
+				e.i32Field(2, d.numNulls)
+				e.i32Field(3, d.numRows)
+				e.i32Field(4, d.encoding)
+				e.i32Field(5, d.definitionLevelsByteLength)
+				e.i32Field(6, d.repetitionLevelsByteLength)
+				e.boolField(7, d.isCompressed)
+			})
+		}
+	})
+}
+
+func (e *thriftEncoder) columnChunk(c *columnChunk) {
+	e.writeStruct(func() {
+		e.i64Field(2, c.fileOffset)
+		e.structField(3, func() {
+			m := &c.metaData
+			e.i32Field(1, m.typ)
+			e.listField(2, thrift.I32, len(m.encodings), func(i int) {
+				e.check(e.p.WriteI32(m.encodings[i]))
+			})
+			e.listField(3, thrift.STRING, len(m.pathInSchema), func(i int) {
+				e.check(e.p.WriteString(m.pathInSchema[i]))
+			})
+			e.i32Field(4, m.codec)
+			e.i64Field(5, m.numValues)
+			e.i64Field(6, m.totalUncompressedSize)
+			e.i64Field(7, m.totalCompressedSize)
+			e.i64Field(9, m.dataPageOffset)
+			if m.dictionaryPageOffset != nil {
+				e.i64Field(11, *m.dictionaryPageOffset)
+			}
+		})
+	})
+}
+
+func (e *thriftEncoder) fileMetaData(m *fileMetaData) {
+	e.writeStruct(func() {
+		e.i32Field(1, m.version)
+		e.listField(2, thrift.STRUCT, len(m.schema), func(i int) {
+			e.schemaElement(&m.schema[i])
+		})
+		e.i64Field(3, m.numRows)
+		e.listField(4, thrift.STRUCT, len(m.rowGroups), func(i int) {
+			rg := &m.rowGroups[i]
+			e.writeStruct(func() {
+				e.listField(1, thrift.STRUCT, len(rg.columns), func(j int) {
+					e.columnChunk(&rg.columns[j])
+				})
+				e.i64Field(2, rg.totalByteSize)
+				e.i64Field(3, rg.numRows)
+			})
+		})
+		e.stringField(6, m.createdBy)
+	})
+}
+
+// encodePageHeader returns the encoding of a page header.
+func encodePageHeader(h *pageHeader) ([]byte, error) {
+	e := newThriftEncoder()
+	e.pageHeader(h)
+	return e.finish()
+}
+
+// encodeFileMetaData returns the encoding of a file's metadata.
+func encodeFileMetaData(m *fileMetaData) ([]byte, error) {
+	e := newThriftEncoder()
+	e.fileMetaData(m)
+	return e.finish()
+}
+
+// errUnexpectedType is returned when decoding a field of an unexpected type.
+var errUnexpectedType = errors.New("parquet: unexpected thrift field type")
+
+// thriftDecoder decodes structs encoded using the Thrift compact protocol.
+type thriftDecoder struct {
+	buf *thrift.TMemoryBuffer
+	p   *thrift.TCompactProtocol
+}
+
+func newThriftDecoder(data []byte) *thriftDecoder {
+	buf := &thrift.TMemoryBuffer{Buffer: bytes.NewBuffer(data)}
+	return &thriftDecoder{buf: buf, p: thrift.NewTCompactProtocol(buf)}
+}
+
+// readStruct reads a struct, calling fn for each of its fields. fn reads the
+// field's value and returns true, or returns false if the field should be
+// skipped.
+func (d *thriftDecoder) readStruct(fn func(id int16, typ thrift.TType) (bool, error)) error {
+	if _, err := d.p.ReadStructBegin(); err != nil {
+		return err
+	}
+	for {
+		_, typ, id, err := d.p.ReadFieldBegin()
+		if err != nil {
+			return err
+		}
+		if typ == thrift.STOP {
+			break
+		}
+		ok, err := fn(id, typ)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := d.p.Skip(typ); err != nil {
+				return err
+			}
+		}
+		if err := d.p.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	return d.p.ReadStructEnd()
+}
+
+// structField reads a field of type struct, calling fn for each of the
+// struct's fields.
+func (d *thriftDecoder) structField(
+	typ thrift.TType, fn func(id int16, typ thrift.TType) (bool, error),
+) (bool, error) {
+	if typ != thrift.STRUCT {
+		return false, errUnexpectedType
+	}
+	return true, d.readStruct(fn)
+}
+
+// readList reads a list of elements of type elemType, calling fn to read each
+// of them.
+func (d *thriftDecoder) readList(typ, elemType thrift.TType, fn func(n int) error) error {
+	if typ != thrift.LIST {
+		return errUnexpectedType
+	}
+	t, n, err := d.p.ReadListBegin()
+	if err != nil {
+		return err
+	}
+	if t != elemType {
+		return errUnexpectedType
+	}
+	for i := 0; i < n; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return d.p.ReadListEnd()
+}
+
+func (d *thriftDecoder) i32(typ thrift.TType, v *int32) (bool, error) {
+	if typ != thrift.I32 {
+		return false, errUnexpectedType
+	}
+	var err error
+	*v, err = d.p.ReadI32()
+	return true, err
+}
+
+func (d *thriftDecoder) optionalI32(typ thrift.TType, v **int32) (bool, error) {
+	*v = new(int32)
+	return d.i32(typ, *v)
+}
+
+func (d *thriftDecoder) i64(typ thrift.TType, v *int64) (bool, error) {
+	if typ != thrift.I64 {
+		return false, errUnexpectedType
+	}
+	var err error
+	*v, err = d.p.ReadI64()
+	return true, err
+}
+
+func (d *thriftDecoder) boolean(typ thrift.TType, v *bool) (bool, error) {
+	if typ != thrift.BOOL {
+		return false, errUnexpectedType
+	}
+	var err error
+	*v, err = d.p.ReadBool()
+	return true, err
+}
+
+func (d *thriftDecoder) string(typ thrift.TType, v *string) (bool, error) {
+	if typ != thrift.STRING {
+		return false, errUnexpectedType
+	}
+	var err error
+	*v, err = d.p.ReadString()
+	return true, err
+}
+
+func (d *thriftDecoder) logicalType(t *logicalType) error {
+	return d.readStruct(func(id int16, typ thrift.TType) (bool, error) {
+		if typ != thrift.STRUCT {
+			return false, errUnexpectedType
+		}
+		t.kind = id
+		switch id {
+		case logicalDecimal:
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					return d.i32(typ, &t.scale)
+				case 2:
+					return d.i32(typ, &t.precision)
+				}
+				return false, nil
+			})
+		case logicalTime, logicalTimestamp:
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					return d.boolean(typ, &t.adjustedToUTC)
+				case 2:
+					return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+						t.unit = id
+						return false, nil
+					})
+				}
+				return false, nil
+			})
+		case logicalInteger:
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					if typ != thrift.BYTE {
+						return false, errUnexpectedType
+					}
+					var err error
+					t.bitWidth, err = d.p.ReadByte()
+					return true, err
+				case 2:
+					return d.boolean(typ, &t.signed)
+				}
+				return false, nil
+			})
+		}
+		return false, nil
+	})
+}
+
+func (d *thriftDecoder) schemaElement(s *schemaElement) error {
+	return d.readStruct(func(id int16, typ thrift.TType) (bool, error) {
+		switch id {
+		case 1:
+			return d.optionalI32(typ, &s.typ)
+		case 2:
+			return d.optionalI32(typ, &s.typeLength)
+		case 3:
+			return d.optionalI32(typ, &s.repetition)
+		case 4:
+			return d.string(typ, &s.name)
+		case 5:
+			return d.optionalI32(typ, &s.numChildren)
+		case 6:
+			return d.optionalI32(typ, &s.convertedType)
+		case 7:
+			return d.optionalI32(typ, &s.scale)
+		case 8:
+			return d.optionalI32(typ, &s.precision)
+		case 10:
+			if typ != thrift.STRUCT {
+				return false, errUnexpectedType
+			}
+			s.logicalType = &logicalType{}
+			return true, d.logicalType(s.logicalType)
+		}
+		return false, nil
+	})
+}
+
+func (d *thriftDecoder) pageHeader(h *pageHeader) error {
+	return d.readStruct(func(id int16, typ thrift.TType) (bool, error) {
+		switch id {
+		case 1:
+			return d.i32(typ, &h.typ)
+		case 2:
+			return d.i32(typ, &h.uncompressedPageSize)
+		case 3:
+			return d.i32(typ, &h.compressedPageSize)
+		case 5:
+			dh := &h.dataPageHeader
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					return d.i32(typ, &dh.numValues)
+				case 2:
+					return d.i32(typ, &dh.encoding)
+				case 3:
+					return d.i32(typ, &dh.definitionLevelEncoding)
+				case 4:
+					return d.i32(typ, &dh.repetitionLevelEncoding)
+				}
+				return false, nil
+			})
+		case 7:
+			dh := &h.dictionaryPageHeader
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					return d.i32(typ, &dh.numValues)
+				case 2:
+					return d.i32(typ, &dh.encoding)
+				}
+				return false, nil
+			})
+		case 8:
+			dh := &h.dataPageHeaderV2
+			// is_compressed defaults to true.
+			dh.isCompressed = true
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					return d.i32(typ, &dh.numValues)
+				case 2:
+					return d.i32(typ, &dh.numNulls)
+				case 3:
+					return d.i32(typ, &dh.numRows)
+				case 4:
+					return d.i32(typ, &dh.encoding)
+				case 5:
+					return d.i32(typ, &dh.definitionLevelsByteLength)
+				case 6:
+					return d.i32(typ, &dh.repetitionLevelsByteLength)
+				case 7:
+					return d.boolean(typ, &dh.isCompressed)
+				}
+				return false, nil
+			})
+		}
+		return false, nil
+	})
+}
+
+func (d *thriftDecoder) columnChunk(c *columnChunk) error {
+	return d.readStruct(func(id int16, typ thrift.TType) (bool, error) {
+		switch id {
+		case 1:
+			return d.string(typ, &c.filePath)
+		case 2:
+			return d.i64(typ, &c.fileOffset)
+		case 3:
+			m := &c.metaData
+			return d.structField(typ, func(id int16, typ thrift.TType) (bool, error) {
+				switch id {
+				case 1:
+					return d.i32(typ, &m.typ)
+				case 2:
+					return true, d.readList(typ, thrift.I32, func(int) error {
+						v, err := d.p.ReadI32()
+						m.encodings = append(m.encodings, v)
+						return err
+					})
+				case 3:
+					return true, d.readList(typ, thrift.STRING, func(int) error {
+						v, err := d.p.ReadString()
+						m.pathInSchema = append(m.pathInSchema, v)
+						return err
+					})
+				case 4:
+					return d.i32(typ, &m.codec)
+				case 5:
+					return d.i64(typ, &m.numValues)
+				case 6:
+					return d.i64(typ, &m.totalUncompressedSize)
+				case 7:
+					return d.i64(typ, &m.totalCompressedSize)
+				case 9:
+					return d.i64(typ, &m.dataPageOffset)
+				case 11:
+					m.dictionaryPageOffset = new(int64)
+					return d.i64(typ, m.dictionaryPageOffset)
+				}
+				return false, nil
+			})
+		}
+		return false, nil
+	})
+}
+
+func (d *thriftDecoder) fileMetaData(m *fileMetaData) error {
+	return d.readStruct(func(id int16, typ thrift.TType) (bool, error) {
+		switch id {
+		case 1:
+			return d.i32(typ, &m.version)
+		case 2:
+			return true, d.readList(typ, thrift.STRUCT, func(int) error {
+				m.schema = append(m.schema, schemaElement{})
+				return d.schemaElement(&m.schema[len(m.schema)-1])
+			})
+		case 3:
+			return d.i64(typ, &m.numRows)
+		case 4:
+			return true, d.readList(typ, thrift.STRUCT, func(int) error {
+				m.rowGroups = append(m.rowGroups, rowGroup{})
+				rg := &m.rowGroups[len(m.rowGroups)-1]
+				return d.readStruct(func(id int16, typ thrift.TType) (bool, error) {
+					switch id {
+					case 1:
+						return true, d.readList(typ, thrift.STRUCT, func(int) error {
+							rg.columns = append(rg.columns, columnChunk{})
+							return d.columnChunk(&rg.columns[len(rg.columns)-1])
+						})
+					case 2:
+						return d.i64(typ, &rg.totalByteSize)
+					case 3:
+						return d.i64(typ, &rg.numRows)
+					}
+					return false, nil
+				})
+			})
+		case 6:
+			return d.string(typ, &m.createdBy)
+		}
+		return false, nil
+	})
+}
+
+// decodePageHeader decodes the page header at the start of data, and returns
+// it along with its encoded length.
+func decodePageHeader(data []byte) (pageHeader, int, error) {
+	var h pageHeader
+	d := newThriftDecoder(data)
+	if err := d.pageHeader(&h); err != nil {
+		return pageHeader{}, 0, errors.Wrap(err, "parquet: decoding page header")
+	}
+	return h, len(data) - d.buf.Len(), nil
+}
+
+// decodeFileMetaData decodes a file's metadata.
+func decodeFileMetaData(data []byte) (fileMetaData, error) {
+	var m fileMetaData
+	if err := newThriftDecoder(data).fileMetaData(&m); err != nil {
+		return fileMetaData{}, errors.Wrap(err, "parquet: decoding file metadata")
+	}
+	return m, nil
+}
@@ -0,0 +1,452 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+
+	"github.com/cockroachdb/errors"
+)
+
+// createdBy is recorded in the metadata of the files we write.
+const createdBy = "cockroachdb"
+
+// WriterOptions configures a Writer.
+type WriterOptions struct {
+	// Compression is the codec used to compress data pages.
+	Compression CompressionCodec
+}
+
+// Writer buffers rows in memory and writes them as a Parquet file when
+// closed.
+type Writer struct {
+	w       io.Writer
+	opts    WriterOptions
+	columns []columnWriter
+	numRows int64
+}
+
+// NewWriter returns a Writer of files with the specified columns to w.
+func NewWriter(w io.Writer, columns []Column, opts WriterOptions) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("parquet: at least one column is required")
+	}
+	switch opts.Compression {
+	case Uncompressed, Gzip:
+	default:
+		return nil, errors.Newf("parquet: unsupported compression codec %d", opts.Compression)
+	}
+	pw := &Writer{w: w, opts: opts, columns: make([]columnWriter, len(columns))}
+	for i := range columns {
+		if err := columns[i].Validate(); err != nil {
+			return nil, err
+		}
+		if columns[i].Type == Int96 {
+			return nil, errors.Newf("parquet: column %q: INT96 values cannot be written", columns[i].Name)
+		}
+		pw.columns[i].col = columns[i]
+		pw.columns[i].maxDef, pw.columns[i].maxRep = columns[i].maxLevels()
+	}
+	return pw, nil
+}
+
+// AddRow buffers a row. The row must have a value for every column: nil for
+// NULL, a []interface{} for List columns, and otherwise a value of the Go
+// type corresponding to the column's physical type: bool, int32, int64,
+// float32, float64, or []byte for ByteArray and FixedLenByteArray.
+//
+// If AddRow returns an error, the Writer must be Reset before it is used again.
+func (w *Writer) AddRow(row []interface{}) error {
+	if len(row) != len(w.columns) {
+		return errors.Newf("parquet: expected %d values, got %d", len(w.columns), len(row))
+	}
+	for i := range w.columns {
+		if err := w.columns[i].add(row[i]); err != nil {
+			return err
+		}
+	}
+	w.numRows++
+	return nil
+}
+
+// Reset discards the buffered rows, so that the Writer can be reused to write
+// another file with the same columns to w.
+func (w *Writer) Reset(out io.Writer) {
+	w.w = out
+	w.numRows = 0
+	for i := range w.columns {
+		c := &w.columns[i]
+		c.defLevels = c.defLevels[:0]
+		c.repLevels = c.repLevels[:0]
+		c.values.Reset()
+		c.bools = c.bools[:0]
+	}
+}
+
+// NumRows returns the number of rows added to the writer.
+func (w *Writer) NumRows() int64 {
+	return w.numRows
+}
+
+// Close writes the file. It does not close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if _, err := io.WriteString(w.w, magic); err != nil {
+		return err
+	}
+	offset := int64(len(magic))
+
+	meta := fileMetaData{
+		version:   1,
+		schema:    w.schema(),
+		numRows:   w.numRows,
+		createdBy: createdBy,
+	}
+	if w.numRows > 0 {
+		rg := rowGroup{numRows: w.numRows, columns: make([]columnChunk, len(w.columns))}
+		for i := range w.columns {
+			chunk, n, err := w.columns[i].writeChunk(w.w, offset, w.opts.Compression)
+			if err != nil {
+				return err
+			}
+			rg.columns[i] = chunk
+			rg.totalByteSize += chunk.metaData.totalUncompressedSize
+			offset += n
+		}
+		meta.rowGroups = []rowGroup{rg}
+	}
+
+	footer, err := encodeFileMetaData(&meta)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(footer); err != nil {
+		return err
+	}
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(footer)))
+	if _, err := w.w.Write(footerLen[:]); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w.w, magic)
+	return err
+}
+
+// schema returns the flattened schema of the file, starting with the root.
+func (w *Writer) schema() []schemaElement {
+	schema := []schemaElement{{name: "schema", numChildren: i32(int32(len(w.columns)))}}
+	for i := range w.columns {
+		col := &w.columns[i].col
+		leaf := schemaElement{
+			typ:        i32(int32(col.Type)),
+			repetition: i32(repetitionOptional),
+			name:       col.Name,
+		}
+		if col.Type == FixedLenByteArray {
+			leaf.typeLength = i32(col.TypeLength)
+		}
+		annotate(&leaf, col)
+		if col.List {
+			leaf.name = "element"
+			schema = append(schema,
+				schemaElement{
+					repetition:    i32(repetitionOptional),
+					name:          col.Name,
+					numChildren:   i32(1),
+					convertedType: i32(convertedList),
+					logicalType:   &logicalType{kind: logicalList},
+				},
+				schemaElement{
+					repetition:  i32(repetitionRepeated),
+					name:        "list",
+					numChildren: i32(1),
+				},
+			)
+		}
+		schema = append(schema, leaf)
+	}
+	return schema
+}
+
+// annotate sets the converted and logical types of a leaf.
+func annotate(leaf *schemaElement, col *Column) {
+	switch col.Annotation {
+	case String:
+		leaf.convertedType = i32(convertedUTF8)
+		leaf.logicalType = &logicalType{kind: logicalString}
+	case Enum:
+		leaf.convertedType = i32(convertedEnum)
+		leaf.logicalType = &logicalType{kind: logicalEnum}
+	case JSON:
+		leaf.convertedType = i32(convertedJSON)
+		leaf.logicalType = &logicalType{kind: logicalJSON}
+	case Decimal:
+		leaf.convertedType = i32(convertedDecimal)
+		leaf.scale, leaf.precision = i32(col.Scale), i32(col.Precision)
+		leaf.logicalType = &logicalType{kind: logicalDecimal, scale: col.Scale, precision: col.Precision}
+	case Date:
+		leaf.convertedType = i32(convertedDate)
+		leaf.logicalType = &logicalType{kind: logicalDate}
+	case TimeMicros:
+		// The TIME_MICROS converted type implies adjustment to UTC, so it is
+		// not set.
+		leaf.logicalType = &logicalType{kind: logicalTime, unit: timeUnitMicros}
+	case TimestampMicros:
+		// Likewise, TIMESTAMP_MICROS implies adjustment to UTC.
+		leaf.logicalType = &logicalType{kind: logicalTimestamp, unit: timeUnitMicros}
+	case TimestampMicrosUTC:
+		leaf.convertedType = i32(convertedTimestampMicros)
+		leaf.logicalType = &logicalType{
+			kind: logicalTimestamp, unit: timeUnitMicros, adjustedToUTC: true,
+		}
+	case TimestampMillis:
+		leaf.logicalType = &logicalType{kind: logicalTimestamp, unit: timeUnitMillis}
+	case TimestampMillisUTC:
+		leaf.convertedType = i32(convertedTimestampMillis)
+		leaf.logicalType = &logicalType{
+			kind: logicalTimestamp, unit: timeUnitMillis, adjustedToUTC: true,
+		}
+	case Int16:
+		leaf.convertedType = i32(convertedInt16)
+		leaf.logicalType = &logicalType{kind: logicalInteger, bitWidth: 16, signed: true}
+	case UUID:
+		leaf.logicalType = &logicalType{kind: logicalUUID}
+	}
+}
+
+func i32(v int32) *int32 {
+	return &v
+}
+
+// columnWriter buffers the levels and values of a column.
+type columnWriter struct {
+	col            Column
+	maxDef, maxRep uint8
+
+	defLevels []uint8
+	repLevels []uint8
+	// values holds the PLAIN encoding of the non-null values, except for
+	// booleans, which are bit-packed when the chunk is written.
+	values bytes.Buffer
+	bools  []bool
+}
+
+func (c *columnWriter) add(v interface{}) error {
+	if !c.col.List {
+		if v == nil {
+			c.defLevels = append(c.defLevels, 0)
+			return nil
+		}
+		c.defLevels = append(c.defLevels, 1)
+		return c.addValue(v)
+	}
+
+	if v == nil {
+		c.defLevels = append(c.defLevels, 0)
+		c.repLevels = append(c.repLevels, 0)
+		return nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return errors.Newf("parquet: expected list for column %q, got %T", c.col.Name, v)
+	}
+	if len(list) == 0 {
+		c.defLevels = append(c.defLevels, 1)
+		c.repLevels = append(c.repLevels, 0)
+		return nil
+	}
+	for i, elem := range list {
+		rep := uint8(1)
+		if i == 0 {
+			rep = 0
+		}
+		c.repLevels = append(c.repLevels, rep)
+		if elem == nil {
+			c.defLevels = append(c.defLevels, 2)
+			continue
+		}
+		c.defLevels = append(c.defLevels, 3)
+		if err := c.addValue(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *columnWriter) addValue(v interface{}) error {
+	var scratch [8]byte
+	var ok bool
+	switch c.col.Type {
+	case Boolean:
+		var b bool
+		if b, ok = v.(bool); ok {
+			c.bools = append(c.bools, b)
+		}
+	case Int32:
+		var i int32
+		if i, ok = v.(int32); ok {
+			binary.LittleEndian.PutUint32(scratch[:4], uint32(i))
+			c.values.Write(scratch[:4])
+		}
+	case Int64:
+		var i int64
+		if i, ok = v.(int64); ok {
+			binary.LittleEndian.PutUint64(scratch[:], uint64(i))
+			c.values.Write(scratch[:])
+		}
+	case Float:
+		var f float32
+		if f, ok = v.(float32); ok {
+			binary.LittleEndian.PutUint32(scratch[:4], math.Float32bits(f))
+			c.values.Write(scratch[:4])
+		}
+	case Double:
+		var f float64
+		if f, ok = v.(float64); ok {
+			binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(f))
+			c.values.Write(scratch[:])
+		}
+	case ByteArray:
+		var b []byte
+		if b, ok = v.([]byte); ok {
+			binary.LittleEndian.PutUint32(scratch[:4], uint32(len(b)))
+			c.values.Write(scratch[:4])
+			c.values.Write(b)
+		}
+	case FixedLenByteArray:
+		var b []byte
+		if b, ok = v.([]byte); ok {
+			if len(b) != int(c.col.TypeLength) {
+				return errors.Newf("parquet: expected %d bytes for column %q, got %d",
+					c.col.TypeLength, c.col.Name, len(b))
+			}
+			c.values.Write(b)
+		}
+	}
+	if !ok {
+		return errors.Newf("parquet: unexpected value of type %T for column %q", v, c.col.Name)
+	}
+	return nil
+}
+
+// writeChunk writes the column chunk, made up of a single data page, at the
+// specified offset of the file. It returns the chunk's metadata and the
+// number of bytes written.
+func (c *columnWriter) writeChunk(
+	w io.Writer, offset int64, codec CompressionCodec,
+) (columnChunk, int64, error) {
+	var page bytes.Buffer
+	if c.maxRep > 0 {
+		page.Write(encodeLevels(c.repLevels, c.maxRep))
+	}
+	if c.maxDef > 0 {
+		page.Write(encodeLevels(c.defLevels, c.maxDef))
+	}
+	if c.col.Type == Boolean {
+		page.Write(packBools(c.bools))
+	} else {
+		page.Write(c.values.Bytes())
+	}
+	uncompressedSize := page.Len()
+
+	data := page.Bytes()
+	if codec == Gzip {
+		var compressed bytes.Buffer
+		gw := gzip.NewWriter(&compressed)
+		if _, err := gw.Write(data); err != nil {
+			return columnChunk{}, 0, err
+		}
+		if err := gw.Close(); err != nil {
+			return columnChunk{}, 0, err
+		}
+		data = compressed.Bytes()
+	}
+	if uncompressedSize > math.MaxInt32 || len(data) > math.MaxInt32 {
+		return columnChunk{}, 0, errors.Newf("parquet: column %q is too large", c.col.Name)
+	}
+
+	numValues := len(c.defLevels)
+	header, err := encodePageHeader(&pageHeader{
+		typ:                  pageTypeData,
+		uncompressedPageSize: int32(uncompressedSize),
+		compressedPageSize:   int32(len(data)),
+		dataPageHeader: dataPageHeader{
+			numValues:               int32(numValues),
+			encoding:                encodingPlain,
+			definitionLevelEncoding: encodingRLE,
+			repetitionLevelEncoding: encodingRLE,
+		},
+	})
+	if err != nil {
+		return columnChunk{}, 0, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return columnChunk{}, 0, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return columnChunk{}, 0, err
+	}
+
+	written := int64(len(header) + len(data))
+	return columnChunk{
+		fileOffset: offset,
+		metaData: columnMetaData{
+			typ:                   int32(c.col.Type),
+			encodings:             []int32{encodingPlain, encodingRLE},
+			pathInSchema:          c.col.path(),
+			codec:                 int32(codec),
+			numValues:             int64(numValues),
+			totalUncompressedSize: int64(len(header) + uncompressedSize),
+			totalCompressedSize:   written,
+			dataPageOffset:        offset,
+		},
+	}, written, nil
+}
+
+// encodeLevels encodes levels using the RLE/bit-packing hybrid encoding,
+// preceded by the length of the encoded data. Only RLE runs are used.
+func encodeLevels(levels []uint8, maxLevel uint8) []byte {
+	byteWidth := (bits.Len8(maxLevel) + 7) / 8
+	buf := make([]byte, 4, 4+len(levels))
+	var scratch [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		n := binary.PutUvarint(scratch[:], uint64(j-i)<<1)
+		buf = append(buf, scratch[:n]...)
+		// Levels always fit in a byte, so any additional bytes of the value
+		// are zero.
+		buf = append(buf, levels[i])
+		for k := 1; k < byteWidth; k++ {
+			buf = append(buf, 0)
+		}
+		i = j
+	}
+	binary.LittleEndian.PutUint32(buf[:4], uint32(len(buf)-4))
+	return buf
+}
+
+// packBools encodes booleans one bit each, least significant bit first.
+func packBools(vals []bool) []byte {
+	buf := make([]byte, (len(vals)+7)/8)
+	for i, v := range vals {
+		if v {
+			buf[i/8] |= 1 << (i % 8)
+		}
+	}
+	return buf
+}
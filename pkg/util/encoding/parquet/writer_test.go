@@ -0,0 +1,86 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeLevels(t *testing.T) {
+	for _, tc := range []struct {
+		levels   []uint8
+		maxLevel uint8
+		expected []byte
+	}{
+		{levels: nil, maxLevel: 1, expected: []byte{0, 0, 0, 0}},
+		{levels: []uint8{1, 1, 1}, maxLevel: 1, expected: []byte{2, 0, 0, 0, 3 << 1, 1}},
+		{levels: []uint8{1, 0, 0, 3}, maxLevel: 3,
+			expected: []byte{6, 0, 0, 0, 1 << 1, 1, 2 << 1, 0, 1 << 1, 3}},
+		{levels: bytes.Repeat([]uint8{1}, 100), maxLevel: 1,
+			expected: []byte{3, 0, 0, 0, 0xc8, 0x01, 1}},
+	} {
+		require.Equal(t, tc.expected, encodeLevels(tc.levels, tc.maxLevel))
+	}
+}
+
+func TestPackBools(t *testing.T) {
+	require.Equal(t, []byte{}, packBools(nil))
+	require.Equal(t, []byte{0x05}, packBools([]bool{true, false, true}))
+	require.Equal(t, []byte{0x81, 0x01},
+		packBools([]bool{true, false, false, false, false, false, false, true, true}))
+}
+
+func TestWriter(t *testing.T) {
+	columns := []Column{
+		{Name: "b", Type: Boolean},
+		{Name: "i", Type: Int64},
+		{Name: "s", Type: ByteArray, Annotation: String},
+		{Name: "d", Type: ByteArray, Annotation: Decimal, Precision: 10, Scale: 2},
+		{Name: "ts", Type: Int64, Annotation: TimestampMicrosUTC},
+		{Name: "u", Type: FixedLenByteArray, TypeLength: 16, Annotation: UUID},
+		{Name: "l", Type: Int32, List: true},
+	}
+	for _, codec := range []CompressionCodec{Uncompressed, Gzip} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, columns, WriterOptions{Compression: codec})
+		require.NoError(t, err)
+		require.NoError(t, w.AddRow([]interface{}{
+			true, int64(1), []byte("a"), []byte{0x04, 0xd2}, int64(1e6),
+			make([]byte, 16), []interface{}{int32(1), nil, int32(2)},
+		}))
+		require.NoError(t, w.AddRow([]interface{}{nil, nil, nil, nil, nil, nil, nil}))
+		require.NoError(t, w.AddRow([]interface{}{
+			false, int64(-1), []byte(""), []byte{0xff}, int64(0), make([]byte, 16), []interface{}{},
+		}))
+		require.Equal(t, int64(3), w.NumRows())
+		require.NoError(t, w.Close())
+
+		data := buf.Bytes()
+		require.Equal(t, magic, string(data[:4]))
+		require.Equal(t, magic, string(data[len(data)-4:]))
+		footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+		require.Less(t, footerLen, len(data)-12)
+	}
+
+	_, err := NewWriter(&bytes.Buffer{}, nil, WriterOptions{})
+	require.Error(t, err)
+	_, err = NewWriter(&bytes.Buffer{}, []Column{{Name: "x", Type: Int64, Annotation: String}}, WriterOptions{})
+	require.Error(t, err)
+
+	w, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "x", Type: Int64}}, WriterOptions{})
+	require.NoError(t, err)
+	require.Error(t, w.AddRow([]interface{}{"not an int"}))
+	require.Error(t, w.AddRow([]interface{}{int64(1), int64(2)}))
+}
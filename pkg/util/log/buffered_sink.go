@@ -0,0 +1,226 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// bufferedSinkInitialRetryDelay and bufferedSinkMaxRetryDelay bound
+// the delay between attempts to deliver entries to an unavailable
+// child sink.
+const (
+	bufferedSinkInitialRetryDelay = 100 * time.Millisecond
+	bufferedSinkMaxRetryDelay     = 10 * time.Second
+)
+
+// bufferedSink wraps a child sink, typically a network sink, so that
+// log entries are delivered to it asynchronously by a separate
+// goroutine. This ensures that logging calls do not block on the
+// network.
+//
+// The amount of memory used by entries not yet delivered is bounded.
+// When the child sink is unavailable for long enough that the limit
+// is reached, the oldest entries are dropped.
+//
+// Errors encountered while delivering entries, including the loss of
+// entries, are reported by the next call to output(). This way, the
+// logger reports them using the mechanisms it already uses for
+// synchronous sinks, including process termination for sinks
+// configured with exit-on-error.
+type bufferedSink struct {
+	child logSink
+	// maxBufferSize is the maximum total size of the entries
+	// not yet delivered.
+	maxBufferSize uint64
+
+	// flushC is used to wake up the flusher goroutine.
+	flushC chan struct{}
+
+	// flushMu serializes the calls to the child sink.
+	flushMu syncutil.Mutex
+
+	mu struct {
+		syncutil.Mutex
+		// entries are the formatted entries not yet delivered, oldest
+		// first.
+		entries [][]byte
+		// size is the total size of entries.
+		size uint64
+		// dropped is the number of entries dropped since the
+		// last call to output().
+		dropped uint64
+		// err is the last delivery error not yet reported by output().
+		err error
+	}
+}
+
+func newBufferedSink(child logSink, maxBufferSize uint64) *bufferedSink {
+	return &bufferedSink{
+		child:         child,
+		maxBufferSize: maxBufferSize,
+		flushC:        make(chan struct{}, 1),
+	}
+}
+
+// active implements the logSink interface.
+func (bs *bufferedSink) active() bool { return bs.child.active() }
+
+// attachHints implements the logSink interface.
+func (bs *bufferedSink) attachHints(stacks []byte) []byte {
+	return bs.child.attachHints(stacks)
+}
+
+// exitCode implements the logSink interface.
+func (bs *bufferedSink) exitCode() exit.Code { return bs.child.exitCode() }
+
+// output implements the logSink interface.
+//
+// The entry is only queued for delivery, unless extraSync is set, in
+// which case all the queued entries are delivered before output()
+// returns.
+func (bs *bufferedSink) output(extraSync bool, b []byte) error {
+	bs.mu.Lock()
+	bs.appendLocked(b)
+	err := bs.mu.err
+	bs.mu.err = nil
+	if bs.mu.dropped > 0 {
+		if err == nil {
+			err = errors.Newf("%d log entries dropped: buffer full", bs.mu.dropped)
+		} else {
+			err = errors.Wrapf(err, "%d log entries dropped: buffer full", bs.mu.dropped)
+		}
+		bs.mu.dropped = 0
+	}
+	bs.mu.Unlock()
+
+	if extraSync {
+		return errors.CombineErrors(err, bs.flush())
+	}
+	bs.signalFlush()
+	return err
+}
+
+// emergencyOutput implements the logSink interface.
+func (bs *bufferedSink) emergencyOutput(b []byte) {
+	bs.mu.Lock()
+	bs.appendLocked(b)
+	bs.mu.Unlock()
+	bs.signalFlush()
+}
+
+// appendLocked queues a copy of the given entry, dropping the oldest
+// entries if the buffer is full.
+func (bs *bufferedSink) appendLocked(b []byte) {
+	if uint64(len(b)) > bs.maxBufferSize {
+		// The entry would not fit even in an empty buffer.
+		bs.mu.dropped++
+		return
+	}
+	// The caller reuses the buffer after we return, so we need a copy.
+	entry := make([]byte, len(b))
+	copy(entry, b)
+	bs.mu.entries = append(bs.mu.entries, entry)
+	bs.mu.size += uint64(len(entry))
+	bs.trimLocked()
+}
+
+// trimLocked drops the oldest entries until the buffer size is within
+// bounds.
+func (bs *bufferedSink) trimLocked() {
+	i := 0
+	for ; bs.mu.size > bs.maxBufferSize; i++ {
+		bs.mu.size -= uint64(len(bs.mu.entries[i]))
+		bs.mu.entries[i] = nil
+		bs.mu.dropped++
+	}
+	bs.mu.entries = bs.mu.entries[i:]
+}
+
+// signalFlush wakes up the flusher goroutine, if it is not already
+// awake.
+func (bs *bufferedSink) signalFlush() {
+	select {
+	case bs.flushC <- struct{}{}:
+	default:
+	}
+}
+
+// flush delivers all the queued entries to the child sink in a single
+// call. If the delivery fails, the entries are put back in the queue.
+func (bs *bufferedSink) flush() error {
+	bs.flushMu.Lock()
+	defer bs.flushMu.Unlock()
+
+	bs.mu.Lock()
+	entries := bs.mu.entries
+	bs.mu.entries = nil
+	bs.mu.size = 0
+	bs.mu.Unlock()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := bs.child.output(false, bytes.Join(entries, nil))
+	if err != nil {
+		// Put the entries back in front of the ones queued in the
+		// meantime. If this overflows the buffer, the oldest entries
+		// are dropped.
+		bs.mu.Lock()
+		entries = append(entries, bs.mu.entries...)
+		bs.mu.entries = entries
+		bs.mu.size = 0
+		for _, e := range entries {
+			bs.mu.size += uint64(len(e))
+		}
+		bs.trimLocked()
+		bs.mu.err = err
+		bs.mu.Unlock()
+	}
+	return err
+}
+
+// runFlusher delivers the queued entries to the child sink until the
+// context is canceled, after which a last attempt is made to deliver
+// the remaining entries. When the child sink is unavailable, the
+// delivery is retried with exponential backoff.
+func (bs *bufferedSink) runFlusher(ctx context.Context) {
+	retryDelay := bufferedSinkInitialRetryDelay
+	for {
+		select {
+		case <-ctx.Done():
+			_ = bs.flush()
+			return
+		case <-bs.flushC:
+		}
+		if err := bs.flush(); err == nil {
+			retryDelay = bufferedSinkInitialRetryDelay
+			continue
+		}
+		// The child sink is unavailable. Wait before trying again; the
+		// entries logged in the meantime accumulate in the buffer.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+		bs.signalFlush()
+		if retryDelay *= 2; retryDelay > bufferedSinkMaxRetryDelay {
+			retryDelay = bufferedSinkMaxRetryDelay
+		}
+	}
+}
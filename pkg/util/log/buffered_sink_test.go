@@ -0,0 +1,146 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testSink is a logSink which records the data it receives, and can
+// be made unavailable.
+type testSink struct {
+	mu struct {
+		syncutil.Mutex
+		unavailable bool
+		received    []string
+	}
+	// calls is signaled on every call to output().
+	calls chan struct{}
+}
+
+func newTestSink() *testSink {
+	return &testSink{calls: make(chan struct{}, 100)}
+}
+
+func (s *testSink) active() bool                { return true }
+func (s *testSink) attachHints(b []byte) []byte { return b }
+func (s *testSink) exitCode() exit.Code         { return exit.UnspecifiedError() }
+func (s *testSink) emergencyOutput(b []byte)    { _ = s.output(false, b) }
+
+func (s *testSink) setUnavailable(unavailable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.unavailable = unavailable
+}
+
+func (s *testSink) received() (res []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(res, s.mu.received...)
+}
+
+func (s *testSink) output(_ bool, b []byte) error {
+	defer func() {
+		select {
+		case s.calls <- struct{}{}:
+		default:
+		}
+	}()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.unavailable {
+		return errors.New("unavailable")
+	}
+	s.mu.received = append(s.mu.received, string(b))
+	return nil
+}
+
+func TestBufferedSinkDelivery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	child := newTestSink()
+	bs := newBufferedSink(child, 1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bs.runFlusher(ctx)
+	}()
+
+	require.NoError(t, bs.output(false, []byte("a\n")))
+	<-child.calls
+	require.Equal(t, []string{"a\n"}, child.received())
+
+	// Entries logged while the child is unavailable are kept in the
+	// buffer, and delivered in order when it becomes available again.
+	child.setUnavailable(true)
+	require.NoError(t, bs.output(false, []byte("b\n")))
+	<-child.calls
+	_ = bs.output(false, []byte("c\n"))
+	child.setUnavailable(false)
+	deadline := time.After(10 * time.Second)
+	for strings.Join(child.received(), "") != "a\nb\nc\n" {
+		select {
+		case <-child.calls:
+		case <-deadline:
+			t.Fatalf("entries not delivered: %q", child.received())
+		}
+	}
+
+	// An output with extraSync delivers the entries synchronously.
+	_ = bs.output(true /* extraSync */, []byte("d\n"))
+	r := child.received()
+	require.Equal(t, "d\n", r[len(r)-1])
+
+	cancel()
+	<-done
+}
+
+func TestBufferedSinkBoundedMemory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	child := newTestSink()
+	child.setUnavailable(true)
+	bs := newBufferedSink(child, 10)
+
+	// No flusher is running: the entries accumulate, and the oldest ones
+	// are dropped when the buffer is full. The loss is reported by the
+	// output that caused it.
+	require.NoError(t, bs.output(false, []byte("1234\n")))
+	require.NoError(t, bs.output(false, []byte("5678\n")))
+	require.EqualError(t, bs.output(false, []byte("9abc\n")),
+		"1 log entries dropped: buffer full")
+	// An entry larger than the buffer is dropped immediately.
+	require.EqualError(t, bs.output(false, []byte("0123456789abcdef\n")),
+		"1 log entries dropped: buffer full")
+	bs.mu.Lock()
+	require.Equal(t, uint64(10), bs.mu.size)
+	bs.mu.Unlock()
+
+	// A failed flush keeps the entries in the buffer, and the failure is
+	// reported by the next output.
+	require.EqualError(t, bs.flush(), "unavailable")
+	require.EqualError(t, bs.output(false, []byte("x\n")),
+		"1 log entries dropped: buffer full: unavailable")
+
+	child.setUnavailable(false)
+	require.NoError(t, bs.flush())
+	require.Equal(t, []string{"9abc\nx\n"}, child.received())
+}
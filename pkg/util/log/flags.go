@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
@@ -117,6 +118,12 @@ func ApplyConfig(config logconfig.Config) (cleanupFn func(), err error) {
 	// fd2CaptureCleanupFn is the cleanup function for the fd2 capture,
 	// which is populated if fd2 capture is enabled, below.
 	fd2CaptureCleanupFn := func() {}
+	// netSinkFlushers tracks the goroutines that deliver the entries of
+	// the buffered network sinks.
+	var netSinkFlushers sync.WaitGroup
+	// netSinkClosers collects the functions that release the
+	// connections of the network sinks.
+	var netSinkClosers []func()
 
 	// cleanupFn is the returned cleanup function, whose purpose
 	// is to tear down the work we are doing here.
@@ -126,6 +133,12 @@ func ApplyConfig(config logconfig.Config) (cleanupFn func(), err error) {
 		logging.setChannelLoggers(make(map[Channel]*loggerT), &si)
 		fd2CaptureCleanupFn()
 		secLoggersCancel()
+		// Wait for the buffered network sinks to deliver their remaining
+		// entries before closing their connections.
+		netSinkFlushers.Wait()
+		for _, closeFn := range netSinkClosers {
+			closeFn()
+		}
 		for _, l := range secLoggers {
 			allLoggers.del(l)
 		}
@@ -279,6 +292,65 @@ func ApplyConfig(config logconfig.Config) (cleanupFn func(), err error) {
 		}
 	}
 
+	// connectNetSink connects a network sink to its channels. If
+	// buffering is requested, the sink is wrapped in a bufferedSink
+	// whose flusher runs until cleanupFn is called.
+	connectNetSink := func(
+		sink logSink, closeFn func(), buffered bool, maxBufferSize logconfig.ByteSize,
+		cc logconfig.CommonSinkConfig, channels logconfig.ChannelList,
+	) error {
+		info := &sinkInfo{}
+		if err := info.applyConfig(cc); err != nil {
+			return err
+		}
+		info.sink = sink
+		if buffered {
+			bs := newBufferedSink(sink, uint64(maxBufferSize))
+			info.sink = bs
+			netSinkFlushers.Add(1)
+			go func() {
+				defer netSinkFlushers.Done()
+				bs.runFlusher(secLoggersCtx)
+			}()
+		}
+		netSinkClosers = append(netSinkClosers, closeFn)
+		sinkInfos = append(sinkInfos, info)
+		allSinkInfos.put(info)
+
+		// Connect the channels for this sink.
+		for _, ch := range channels.Channels {
+			l := chans[ch]
+			l.sinkInfos = append(l.sinkInfos, info)
+		}
+		return nil
+	}
+
+	// Create the fluent sinks.
+	for name, fc := range config.Sinks.FluentServers {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		fs := newFluentSink(name, fc.Net, fc.Address, fc.Mode)
+		if err := connectNetSink(fs, fs.close, *fc.BufferedWrites, *fc.MaxBufferSize,
+			fc.CommonSinkConfig, fc.Channels); err != nil {
+			cleanupFn()
+			return nil, err
+		}
+	}
+
+	// Create the HTTP sinks.
+	for name, hc := range config.Sinks.HTTPServers {
+		if hc.Filter == severity.NONE {
+			continue
+		}
+		hs := newHTTPSink(name, hc.Address, *hc.Format, *hc.Timeout, *hc.UnsafeTLS, *hc.DisableKeepAlives)
+		if err := connectNetSink(hs, hs.close, *hc.BufferedWrites, *hc.MaxBufferSize,
+			hc.CommonSinkConfig, hc.Channels); err != nil {
+			cleanupFn()
+			return nil, err
+		}
+	}
+
 	logging.setChannelLoggers(chans, &stderrSinkInfo)
 	setActive()
 
@@ -436,6 +508,61 @@ func DescribeAppliedConfig() string {
 		return nil
 	})
 
+	// Describe the network sinks.
+	_ = allSinkInfos.iter(func(l *sinkInfo) error {
+		sink := l.sink
+		var bs *bufferedSink
+		if b, ok := sink.(*bufferedSink); ok {
+			bs = b
+			sink = b.child
+		}
+		buffered := bs != nil
+		var maxBufferSize *logconfig.ByteSize
+		if buffered {
+			m := logconfig.ByteSize(bs.maxBufferSize)
+			maxBufferSize = &m
+		}
+
+		switch s := sink.(type) {
+		case *fluentSink:
+			fc := &logconfig.FluentConfig{
+				Net:            s.network,
+				Address:        s.addr,
+				Mode:           s.mode,
+				BufferedWrites: &buffered,
+				MaxBufferSize:  maxBufferSize,
+			}
+			fc.CommonSinkConfig = l.describeAppliedConfig()
+			for ch, logger := range chans {
+				describeConnections(logger, ch, l, &fc.Channels)
+			}
+			if config.Sinks.FluentServers == nil {
+				config.Sinks.FluentServers = make(map[string]*logconfig.FluentConfig)
+			}
+			config.Sinks.FluentServers[s.name] = fc
+
+		case *httpSink:
+			timeout := s.client.Timeout
+			hc := &logconfig.HTTPConfig{
+				Address:           s.address,
+				Timeout:           &timeout,
+				UnsafeTLS:         &s.unsafeTLS,
+				DisableKeepAlives: &s.disableKeepAlives,
+				BufferedWrites:    &buffered,
+				MaxBufferSize:     maxBufferSize,
+			}
+			hc.CommonSinkConfig = l.describeAppliedConfig()
+			for ch, logger := range chans {
+				describeConnections(logger, ch, l, &hc.Channels)
+			}
+			if config.Sinks.HTTPServers == nil {
+				config.Sinks.HTTPServers = make(map[string]*logconfig.HTTPConfig)
+			}
+			config.Sinks.HTTPServers[s.name] = hc
+		}
+		return nil
+	})
+
 	// Note: we cannot return 'config' directly, because this captures
 	// certain variables from the loggers by reference and thus could be
 	// invalidated by concurrent uses of ApplyConfig().
@@ -0,0 +1,186 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// fluentDialTimeout is the maximum amount of time spent establishing
+// a connection to a fluent collector.
+const fluentDialTimeout = 5 * time.Second
+
+// fluentWriteTimeout is the maximum amount of time spent writing to
+// a fluent collector. This ensures that a stalled collector does not
+// block logging indefinitely.
+const fluentWriteTimeout = 5 * time.Second
+
+// fluentSink represents a sink that sends log entries to a
+// Fluentd-compatible collector over the network.
+type fluentSink struct {
+	// name is the name of the sink in the configuration.
+	name string
+	// network and addr are the network protocol and address of the
+	// collector.
+	network string
+	addr    string
+	// mode is the framing of entries on the connection, either
+	// logconfig.FluentModeJSONLines or logconfig.FluentModeForward.
+	mode string
+
+	mu struct {
+		syncutil.Mutex
+		// conn is the connection to the collector, or nil if there is
+		// no connection currently.
+		conn net.Conn
+	}
+}
+
+func newFluentSink(name, network, addr, mode string) *fluentSink {
+	return &fluentSink{
+		name:    name,
+		network: network,
+		addr:    addr,
+		mode:    mode,
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (l *fluentSink) String() string {
+	return fmt.Sprintf("fluent:%s://%s", l.network, l.addr)
+}
+
+// active implements the logSink interface.
+func (l *fluentSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *fluentSink) attachHints(stacks []byte) []byte { return stacks }
+
+// exitCode implements the logSink interface.
+func (l *fluentSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *fluentSink) output(_ bool, b []byte) error {
+	if l.mode == logconfig.FluentModeForward {
+		b = frameFluentForward(b, timeutil.Now())
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.tryWriteLocked(b); err != nil {
+		// The connection may have been closed by the collector since the
+		// last write. Try again once with a new connection.
+		if err := l.tryWriteLocked(b); err != nil {
+			return errors.Wrapf(err, "writing to %s", l)
+		}
+	}
+	return nil
+}
+
+// emergencyOutput implements the logSink interface.
+func (l *fluentSink) emergencyOutput(b []byte) {
+	_ = l.output(false, b)
+}
+
+// close closes the connection to the collector, if any.
+func (l *fluentSink) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closeLocked()
+}
+
+func (l *fluentSink) closeLocked() {
+	if l.mu.conn != nil {
+		_ = l.mu.conn.Close()
+		l.mu.conn = nil
+	}
+}
+
+// tryWriteLocked writes the given bytes to the collector, connecting
+// first if there is no connection yet. If an error occurs, the
+// connection is closed so that the next write reconnects.
+func (l *fluentSink) tryWriteLocked(b []byte) error {
+	if l.mu.conn == nil {
+		conn, err := net.DialTimeout(l.network, l.addr, fluentDialTimeout)
+		if err != nil {
+			return err
+		}
+		l.mu.conn = conn
+	}
+	if err := l.mu.conn.SetWriteDeadline(timeutil.Now().Add(fluentWriteTimeout)); err != nil {
+		l.closeLocked()
+		return err
+	}
+	n, err := l.mu.conn.Write(b)
+	if err == nil && n < len(b) {
+		err = errors.Newf("%d bytes written, expected %d", n, len(b))
+	}
+	if err != nil {
+		l.closeLocked()
+	}
+	return err
+}
+
+// fluentTagPrefix is the beginning of the entries produced by the
+// json-fluent formats.
+const fluentTagPrefix = `{"tag":"`
+
+// frameFluentForward wraps every newline-terminated JSON entry in the
+// given bytes into a message of the Fluent Forward protocol, using
+// the JSON encoding accepted by the Fluentd in_forward input:
+//
+//     ["<tag>",<time>,<entry>]
+//
+// The tag is taken from the entry, which is produced by one of the
+// json-fluent formats. The time is the time at which the message is
+// sent; the precise time of the event remains available in the
+// entry.
+func frameFluentForward(b []byte, now time.Time) []byte {
+	var buf bytes.Buffer
+	ts := strconv.AppendInt(nil, now.Unix(), 10)
+	for len(b) > 0 {
+		var entry []byte
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			entry, b = b[:i], b[i+1:]
+		} else {
+			entry, b = b, nil
+		}
+		if len(entry) == 0 {
+			continue
+		}
+		tag := []byte(programEscaped)
+		if bytes.HasPrefix(entry, []byte(fluentTagPrefix)) {
+			rest := entry[len(fluentTagPrefix):]
+			if i := bytes.IndexByte(rest, '"'); i >= 0 {
+				tag = rest[:i]
+			}
+		}
+		buf.WriteString(`["`)
+		buf.Write(tag)
+		buf.WriteString(`",`)
+		buf.Write(ts)
+		buf.WriteByte(',')
+		buf.Write(entry)
+		buf.WriteString("]\n")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFluentClient(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		mode     string
+		buffered bool
+	}{
+		{logconfig.FluentModeJSONLines, false},
+		{logconfig.FluentModeJSONLines, true},
+		{logconfig.FluentModeForward, false},
+		{logconfig.FluentModeForward, true},
+	}
+	for _, tc := range testCases {
+		name := tc.mode
+		if tc.buffered {
+			name += "/buffered"
+		}
+		t.Run(name, func(t *testing.T) {
+			sc := ScopeWithoutShowLogs(t)
+			defer sc.Close(t)
+
+			serverAddr, lines, stop := startFluentTestServer(t)
+			defer stop()
+
+			cfg := logconfig.DefaultConfig()
+			cfg.Sinks.FluentServers = map[string]*logconfig.FluentConfig{
+				"ops": {
+					Channels:       logconfig.ChannelList{Channels: []Channel{channel.OPS}},
+					Address:        serverAddr,
+					Mode:           tc.mode,
+					BufferedWrites: &tc.buffered,
+				},
+			}
+			require.NoError(t, cfg.Validate(&sc.logDir))
+			TestingResetActive()
+			cleanup, err := ApplyConfig(cfg)
+			require.NoError(t, err)
+			defer cleanup()
+
+			Ops.Infof(context.Background(), "hello %s", "world")
+
+			var line string
+			select {
+			case line = <-lines:
+			case <-time.After(10 * time.Second):
+				t.Fatal("no log entry received")
+			}
+
+			var entry map[string]interface{}
+			if tc.mode == logconfig.FluentModeForward {
+				// The entry is wrapped in a [tag, time, record] message.
+				var msg []json.RawMessage
+				require.NoError(t, json.Unmarshal([]byte(line), &msg))
+				require.Len(t, msg, 3)
+				var tag string
+				require.NoError(t, json.Unmarshal(msg[0], &tag))
+				require.Equal(t, programEscaped+".ops", tag)
+				var ts int64
+				require.NoError(t, json.Unmarshal(msg[1], &ts))
+				require.NotZero(t, ts)
+				require.NoError(t, json.Unmarshal(msg[2], &entry))
+			} else {
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			}
+			require.Equal(t, programEscaped+".ops", entry["tag"])
+			require.Equal(t, "hello ‹world›", entry["message"])
+		})
+	}
+}
+
+// startFluentTestServer starts a TCP server which reports every line
+// it receives on the returned channel.
+func startFluentTestServer(t *testing.T) (addr string, lines <-chan string, stop func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	linesC := make(chan string, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				linesC <- strings.TrimSpace(scanner.Text())
+			}
+			_ = conn.Close()
+		}
+	}()
+	return l.Addr().String(), linesC, func() {
+		_ = l.Close()
+		<-done
+	}
+}
+
+func TestFrameFluentForward(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	now := time.Unix(1234, 0)
+	input := `{"tag":"cockroach.dev","c":0,"message":"a"}` + "\n" +
+		`{"tag":"cockroach.ops","c":1,"message":"b"}` + "\n"
+	expected := `["cockroach.dev",1234,{"tag":"cockroach.dev","c":0,"message":"a"}]` + "\n" +
+		`["cockroach.ops",1234,{"tag":"cockroach.ops","c":1,"message":"b"}]` + "\n"
+	require.Equal(t, expected, string(frameFluentForward([]byte(input), now)))
+}
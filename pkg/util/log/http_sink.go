@@ -0,0 +1,113 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/errors"
+)
+
+// httpSink represents a sink that sends log entries to a collector
+// using HTTP POST requests. Every request contains one or more
+// newline-terminated log entries.
+type httpSink struct {
+	// name is the name of the sink in the configuration.
+	name string
+	// address is the URL of the collector.
+	address string
+	// contentType is the value of the Content-Type header of the
+	// requests, derived from the entry format.
+	contentType string
+	// unsafeTLS and disableKeepAlives memorize the input configuration
+	// used to create the client below.
+	unsafeTLS, disableKeepAlives bool
+	client                       *http.Client
+}
+
+func newHTTPSink(
+	name, address, format string, timeout time.Duration, unsafeTLS, disableKeepAlives bool,
+) *httpSink {
+	transport := &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		DialContext:       (&net.Dialer{Timeout: timeout}).DialContext,
+		DisableKeepAlives: disableKeepAlives,
+	}
+	if unsafeTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	contentType := "text/plain"
+	if strings.HasPrefix(format, "json") {
+		// The entries are JSON objects separated by newlines.
+		contentType = "application/x-ndjson"
+	}
+	return &httpSink{
+		name:              name,
+		address:           address,
+		contentType:       contentType,
+		unsafeTLS:         unsafeTLS,
+		disableKeepAlives: disableKeepAlives,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (l *httpSink) String() string {
+	return fmt.Sprintf("http:%s", l.address)
+}
+
+// active implements the logSink interface.
+func (l *httpSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *httpSink) attachHints(stacks []byte) []byte { return stacks }
+
+// exitCode implements the logSink interface.
+func (l *httpSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *httpSink) output(_ bool, b []byte) error {
+	resp, err := l.client.Post(l.address, l.contentType, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrapf(err, "sending to %s", l)
+	}
+	// Consume the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Newf("sending to %s: %s", l, resp.Status)
+	}
+	return nil
+}
+
+// emergencyOutput implements the logSink interface.
+func (l *httpSink) emergencyOutput(b []byte) {
+	_ = l.output(false, b)
+}
+
+// close releases the idle connections to the collector.
+func (l *httpSink) close() {
+	l.client.CloseIdleConnections()
+}
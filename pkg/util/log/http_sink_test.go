@@ -0,0 +1,98 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, buffered := range []bool{false, true} {
+		buffered := buffered
+		name := "unbuffered"
+		if buffered {
+			name = "buffered"
+		}
+		t.Run(name, func(t *testing.T) {
+			sc := ScopeWithoutShowLogs(t)
+			defer sc.Close(t)
+
+			bodies := make(chan string, 10)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				require.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+				b, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				bodies <- string(b)
+			}))
+			defer server.Close()
+
+			cfg := logconfig.DefaultConfig()
+			cfg.Sinks.HTTPServers = map[string]*logconfig.HTTPConfig{
+				"ops": {
+					Channels:       logconfig.ChannelList{Channels: []Channel{channel.OPS}},
+					Address:        server.URL,
+					BufferedWrites: &buffered,
+				},
+			}
+			require.NoError(t, cfg.Validate(&sc.logDir))
+			TestingResetActive()
+			cleanup, err := ApplyConfig(cfg)
+			require.NoError(t, err)
+			defer cleanup()
+
+			Ops.Infof(context.Background(), "hello %s", "world")
+
+			var body string
+			select {
+			case body = <-bodies:
+			case <-time.After(10 * time.Second):
+				t.Fatal("no log entry received")
+			}
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(body)), &entry))
+			require.Equal(t, "hello ‹world›", entry["message"])
+		})
+	}
+}
+
+func TestHTTPSinkError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hs := newHTTPSink("test", server.URL, logconfig.DefaultHTTPFormat,
+		time.Second, false /* unsafeTLS */, false /* disableKeepAlives */)
+	defer hs.close()
+	err := hs.output(false, []byte("{}\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "503 Service Unavailable")
+}
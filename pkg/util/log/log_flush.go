@@ -26,7 +26,8 @@ type flushSyncWriter interface {
 	io.Writer
 }
 
-// Flush explicitly flushes all pending log file I/O.
+// Flush explicitly flushes all pending log file I/O, and attempts to
+// deliver the entries held by buffered network sinks.
 // See also flushDaemon() that manages background (asynchronous)
 // flushes, and signalFlusher() that manages flushes in reaction to a
 // user signal.
@@ -35,6 +36,14 @@ func Flush() {
 		l.lockAndFlushAndMaybeSync(true /*doSync*/)
 		return nil
 	})
+	_ = allSinkInfos.iter(func(l *sinkInfo) error {
+		if bs, ok := l.sink.(*bufferedSink); ok {
+			// Delivery errors are reported by the next log call on this
+			// sink.
+			_ = bs.flush()
+		}
+		return nil
+	})
 }
 
 func init() {
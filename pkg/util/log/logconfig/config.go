@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/errors"
@@ -30,6 +31,14 @@ const DefaultFileFormat = `crdb-v1`
 // when not specified in a configuration.
 const DefaultStderrFormat = `crdb-v1-tty`
 
+// DefaultFluentFormat is the entry format for fluent sinks
+// when not specified in a configuration.
+const DefaultFluentFormat = `json-fluent-compact`
+
+// DefaultHTTPFormat is the entry format for HTTP sinks
+// when not specified in a configuration.
+const DefaultHTTPFormat = `json-compact`
+
 // DefaultConfig returns a suitable default configuration when logging
 // is meant to primarily go to files.
 func DefaultConfig() (c Config) {
@@ -87,6 +96,16 @@ type Config struct {
 	// configuration value.
 	FileDefaults FileDefaults `yaml:"file-defaults,omitempty"`
 
+	// FluentDefaults represents the default configuration for fluent sinks,
+	// inherited when a specific fluent sink config does not provide a
+	// configuration value.
+	FluentDefaults FluentDefaults `yaml:"fluent-defaults,omitempty"`
+
+	// HTTPDefaults represents the default configuration for HTTP sinks,
+	// inherited when a specific HTTP sink config does not provide a
+	// configuration value.
+	HTTPDefaults HTTPDefaults `yaml:"http-defaults,omitempty"`
+
 	// Sinks represents the sink configurations.
 	Sinks SinkConfig `yaml:",omitempty"`
 
@@ -116,12 +135,19 @@ type CaptureFd2Config struct {
 type SinkConfig struct {
 	// FileGroups represents the list of configured file sinks.
 	FileGroups map[string]*FileConfig `yaml:"file-groups,omitempty"`
+	// FluentServers represents the list of configured fluent sinks.
+	FluentServers map[string]*FluentConfig `yaml:"fluent-servers,omitempty"`
+	// HTTPServers represents the list of configured HTTP sinks.
+	HTTPServers map[string]*HTTPConfig `yaml:"http-servers,omitempty"`
 	// Stderr represents the configuration for the stderr sink.
 	Stderr StderrConfig `yaml:",omitempty"`
 
-	// sortedFileGroupNames is used internally to
-	// make the Export() function deterministic.
-	sortedFileGroupNames []string
+	// sortedFileGroupNames, sortedFluentServerNames and
+	// sortedHTTPServerNames are used internally to make the Export()
+	// function deterministic.
+	sortedFileGroupNames    []string
+	sortedFluentServerNames []string
+	sortedHTTPServerNames   []string
 }
 
 // StderrConfig represents the configuration for the stderr sink.
@@ -236,6 +262,150 @@ type FileConfig struct {
 	prefix string
 }
 
+// FluentDefaults represent configuration defaults for fluent sinks.
+type FluentDefaults struct {
+	// BufferedWrites stores the default setting for buffered-writes on
+	// fluent sinks. When set, log entries are accumulated in memory
+	// and sent to the collector asynchronously.
+	BufferedWrites *bool `yaml:"buffered-writes,omitempty"`
+
+	// MaxBufferSize stores the default maximum amount of memory used
+	// to hold log entries that have not been sent to the collector
+	// yet. When the limit is reached, the oldest entries are dropped.
+	MaxBufferSize ByteSize `yaml:"max-buffer-size,omitempty"`
+
+	// CommonSinkConfig is the configuration common to all sinks. Note
+	// that although the idiom in Go is to place embedded fields at the
+	// beginning of a struct, we purposefully deviate from the idiom
+	// here to ensure that "general" options appear after the
+	// sink-specific options in YAML config dumps.
+	CommonSinkConfig `yaml:",inline"`
+}
+
+// FluentConfig represents the configuration for one fluent sink.
+type FluentConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelList `yaml:",omitempty"`
+
+	// Net is the network protocol used to reach the collector: tcp,
+	// tcp4, tcp6, udp, udp4, udp6 or unix. Defaults to tcp.
+	Net string `yaml:",omitempty"`
+
+	// Address is the network address of the collector.
+	Address string `yaml:",omitempty"`
+
+	// Mode indicates how log entries are framed on the connection.
+	// With "json-lines" (the default), every entry is sent as a JSON
+	// object followed by a newline, as expected by the Fluentd in_tcp
+	// and in_udp inputs with a json parser. With "forward", every entry
+	// is wrapped in a message for the Fluent Forward protocol, as
+	// expected by the Fluentd in_forward input.
+	Mode string `yaml:",omitempty"`
+
+	// BufferedWrites specifies whether log entries are sent to the
+	// collector asynchronously. Inherited from
+	// FluentDefaults.BufferedWrites if not specified.
+	BufferedWrites *bool `yaml:"buffered-writes,omitempty"`
+
+	// MaxBufferSize indicates the maximum amount of memory used to hold
+	// log entries that have not been sent to the collector yet.
+	// Inherited from FluentDefaults.MaxBufferSize if not specified.
+	MaxBufferSize *ByteSize `yaml:"max-buffer-size,omitempty"`
+
+	// CommonSinkConfig is the configuration common to all sinks. Note
+	// that although the idiom in Go is to place embedded fields at the
+	// beginning of a struct, we purposefully deviate from the idiom
+	// here to ensure that "general" options appear after the
+	// sink-specific options in YAML config dumps.
+	CommonSinkConfig `yaml:",inline"`
+
+	// serverName is populated during validation.
+	serverName string
+}
+
+// FluentModeJSONLines and FluentModeForward are the supported values
+// for FluentConfig.Mode.
+const (
+	FluentModeJSONLines = "json-lines"
+	FluentModeForward   = "forward"
+)
+
+// HTTPDefaults represent configuration defaults for HTTP sinks.
+type HTTPDefaults struct {
+	// Timeout stores the default timeout for requests to HTTP
+	// collectors.
+	Timeout *time.Duration `yaml:",omitempty"`
+
+	// UnsafeTLS stores the default setting for the verification of the
+	// server certificates of HTTP collectors. When set, the
+	// certificates are not checked.
+	UnsafeTLS *bool `yaml:"unsafe-tls,omitempty"`
+
+	// DisableKeepAlives stores the default setting for the reuse of
+	// connections across requests to HTTP collectors.
+	DisableKeepAlives *bool `yaml:"disable-keep-alives,omitempty"`
+
+	// BufferedWrites stores the default setting for buffered-writes on
+	// HTTP sinks. When set, log entries are accumulated in memory and
+	// sent to the collector in batches.
+	BufferedWrites *bool `yaml:"buffered-writes,omitempty"`
+
+	// MaxBufferSize stores the default maximum amount of memory used
+	// to hold log entries that have not been sent to the collector
+	// yet. When the limit is reached, the oldest entries are dropped.
+	MaxBufferSize ByteSize `yaml:"max-buffer-size,omitempty"`
+
+	// CommonSinkConfig is the configuration common to all sinks. Note
+	// that although the idiom in Go is to place embedded fields at the
+	// beginning of a struct, we purposefully deviate from the idiom
+	// here to ensure that "general" options appear after the
+	// sink-specific options in YAML config dumps.
+	CommonSinkConfig `yaml:",inline"`
+}
+
+// HTTPConfig represents the configuration for one HTTP sink.
+type HTTPConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelList `yaml:",omitempty"`
+
+	// Address is the URL of the collector. Log entries are sent to it
+	// with POST requests.
+	Address string `yaml:",omitempty"`
+
+	// Timeout is the timeout for requests to the collector.
+	// Inherited from HTTPDefaults.Timeout if not specified.
+	Timeout *time.Duration `yaml:",omitempty"`
+
+	// UnsafeTLS disables the verification of the server certificate.
+	// Inherited from HTTPDefaults.UnsafeTLS if not specified.
+	UnsafeTLS *bool `yaml:"unsafe-tls,omitempty"`
+
+	// DisableKeepAlives disables the reuse of connections across
+	// requests. Inherited from HTTPDefaults.DisableKeepAlives if not
+	// specified.
+	DisableKeepAlives *bool `yaml:"disable-keep-alives,omitempty"`
+
+	// BufferedWrites specifies whether log entries are sent to the
+	// collector asynchronously. Inherited from
+	// HTTPDefaults.BufferedWrites if not specified.
+	BufferedWrites *bool `yaml:"buffered-writes,omitempty"`
+
+	// MaxBufferSize indicates the maximum amount of memory used to hold
+	// log entries that have not been sent to the collector yet.
+	// Inherited from HTTPDefaults.MaxBufferSize if not specified.
+	MaxBufferSize *ByteSize `yaml:"max-buffer-size,omitempty"`
+
+	// CommonSinkConfig is the configuration common to all sinks. Note
+	// that although the idiom in Go is to place embedded fields at the
+	// beginning of a struct, we purposefully deviate from the idiom
+	// here to ensure that "general" options appear after the
+	// sink-specific options in YAML config dumps.
+	CommonSinkConfig `yaml:",inline"`
+
+	// serverName is populated during validation.
+	serverName string
+}
+
 // IterateDirectories calls the provided fn on every directory linked to
 // by the configuration.
 func (c *Config) IterateDirectories(fn func(d string) error) error {
@@ -24,6 +24,19 @@
 //       sync-writes: <bool>   # whether to sync each write, default false
 //       <common sink parameters>
 //
+//     fluent-defaults: #optional
+//       buffered-writes: <bool>  # whether to send entries asynchronously, default true
+//       max-buffer-size: <sz>    # max memory for unsent entries, default 10MiB
+//       <common sink parameters> # if not specified, inherit from file-defaults
+//
+//     http-defaults: #optional
+//       timeout: <duration>         # timeout for requests, default 2s
+//       unsafe-tls: <bool>          # whether to skip server certificate checks, default false
+//       disable-keep-alives: <bool> # whether to use a new connection per request, default false
+//       buffered-writes: <bool>     # whether to send entries asynchronously, default true
+//       max-buffer-size: <sz>       # max memory for unsent entries, default 10MiB
+//       <common sink parameters>    # if not specified, inherit from file-defaults
+//
 //     sinks: #optional
 //      stderr: #optional
 //       channels: <chans>        # channel selection for stderr output, default ALL
@@ -39,6 +52,31 @@
 //
 //        ... repeat ...
 //
+//      fluent-servers: #optional
+//        <sinkname>:
+//          channels: <chans>        # channel selection for this sink, mandatory
+//          net: <protocol>          # tcp, udp or unix (and variants), default tcp
+//          address: <addr>          # network address of the collector, mandatory
+//          mode: <mode>             # json-lines or forward, default json-lines
+//          buffered-writes: <bool>  # defaults to fluent-defaults.buffered-writes
+//          max-buffer-size: <sz>    # defaults to fluent-defaults.max-buffer-size
+//          <common sink parameters> # if not specified, inherit from fluent-defaults
+//
+//        ... repeat ...
+//
+//      http-servers: #optional
+//        <sinkname>:
+//          channels: <chans>           # channel selection for this sink, mandatory
+//          address: <url>              # URL of the collector, mandatory
+//          timeout: <duration>         # defaults to http-defaults.timeout
+//          unsafe-tls: <bool>          # defaults to http-defaults.unsafe-tls
+//          disable-keep-alives: <bool> # defaults to http-defaults.disable-keep-alives
+//          buffered-writes: <bool>     # defaults to http-defaults.buffered-writes
+//          max-buffer-size: <sz>       # defaults to http-defaults.max-buffer-size
+//          <common sink parameters>    # if not specified, inherit from http-defaults
+//
+//        ... repeat ...
+//
 //     capture-stray-errors: #optional
 //       enable: <bool>       # whether to enable internal fd2 capture
 //       dir: <optional>      # output directory, defaults to file-defaults.dir
@@ -51,7 +89,8 @@
 //       format: <fmt>         # format to use for log enries, default
 //                             # crdb-v1 for files, crdb-v1-tty for stderr
 //       exit-on-error: <bool> # whether to terminate upon a write error
-//                             # default true for file+stderr sinks
+//                             # default true for file+stderr sinks,
+//                             # false for network sinks
 //       auditable: <bool>     # if true, activates sink-specific features
 //                             # that enhance non-repudiability.
 //                             # also implies exit-on-error: true.
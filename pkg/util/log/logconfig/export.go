@@ -189,6 +189,49 @@ func (c *Config) Export(onlyChans ChannelList) (string, string) {
 		}
 	}
 
+	// Export the network sinks.
+	//
+	// servers collects the declarations of the network collectors.
+	servers := []string{}
+	exportNetSink := func(key, label string, buffered bool, chans ChannelList, cc CommonSinkConfig) {
+		target := key
+		var bufproc, buflink []string
+		if buffered {
+			bkey := fmt.Sprintf("%s_buffer", key)
+			bufproc = append(bufproc, fmt.Sprintf("card %s as \"buffer\"", bkey))
+			buflink = append(buflink, fmt.Sprintf("%s --> %s", bkey, target))
+			target = bkey
+		}
+		target, thisprocs, thislinks := process(target, cc)
+		hasLink := false
+		for _, ch := range chans.Channels {
+			if !chanSel.HasChannel(ch) {
+				continue
+			}
+			hasLink = true
+			links = append(links, fmt.Sprintf("%s --> %s", ch, target))
+		}
+		if hasLink {
+			processing = append(processing, bufproc...)
+			processing = append(processing, thisprocs...)
+			links = append(links, thislinks...)
+			links = append(links, buflink...)
+			servers = append(servers, fmt.Sprintf("queue %s as \"%s\"", key, label))
+		}
+	}
+	for i, sn := range c.Sinks.sortedFluentServerNames {
+		fc := c.Sinks.FluentServers[sn]
+		exportNetSink(fmt.Sprintf("fluent%d", i+1),
+			fmt.Sprintf("fluent: %s://%s", fc.Net, fc.Address),
+			*fc.BufferedWrites, fc.Channels, fc.CommonSinkConfig)
+	}
+	for i, sn := range c.Sinks.sortedHTTPServerNames {
+		hc := c.Sinks.HTTPServers[sn]
+		exportNetSink(fmt.Sprintf("http%d", i+1),
+			fmt.Sprintf("http: %s", hc.Address),
+			*hc.BufferedWrites, hc.Channels, hc.CommonSinkConfig)
+	}
+
 	// Represent the processing stages, if any.
 	if len(processing) > 0 {
 		for _, p := range processing {
@@ -210,6 +253,15 @@ func (c *Config) Export(onlyChans ChannelList) (string, string) {
 		buf.WriteString("}\n")
 	}
 
+	// Represent the network collectors, if any.
+	if len(servers) > 0 {
+		buf.WriteString("cloud network {\n")
+		for _, sdecl := range servers {
+			fmt.Fprintf(&buf, " %s\n", sdecl)
+		}
+		buf.WriteString("}\n")
+	}
+
 	// Export the relationships.
 	for _, l := range links {
 		fmt.Fprintf(&buf, "%s\n", l)
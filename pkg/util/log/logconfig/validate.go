@@ -13,14 +13,24 @@ package logconfig
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/errors"
 )
 
+// defaultMaxBufferSize is the default amount of memory used by a
+// network sink to hold log entries not yet sent to its collector.
+const defaultMaxBufferSize = ByteSize(10 << 20)
+
+// defaultHTTPTimeout is the default timeout for requests to HTTP
+// collectors.
+const defaultHTTPTimeout = 2 * time.Second
+
 // Validate checks the configuration and propagates defaults.
 func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 	var errBuf bytes.Buffer
@@ -72,6 +82,51 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		c.FileDefaults.Criticality = &bt
 	}
 
+	// Defaults for fluent sinks. The common parameters not specified
+	// otherwise are inherited from the file defaults.
+	if c.FluentDefaults.Format == nil {
+		s := DefaultFluentFormat
+		c.FluentDefaults.Format = &s
+	}
+	// No criticality -> default false for network sinks: we do not
+	// want to stop the process if the collector is unavailable.
+	if c.FluentDefaults.Criticality == nil {
+		c.FluentDefaults.Criticality = &bf
+	}
+	if c.FluentDefaults.BufferedWrites == nil {
+		c.FluentDefaults.BufferedWrites = &bt
+	}
+	if c.FluentDefaults.MaxBufferSize == 0 {
+		c.FluentDefaults.MaxBufferSize = defaultMaxBufferSize
+	}
+	c.inheritCommonDefaults(&c.FluentDefaults.CommonSinkConfig, &c.FileDefaults.CommonSinkConfig)
+
+	// Defaults for HTTP sinks.
+	if c.HTTPDefaults.Format == nil {
+		s := DefaultHTTPFormat
+		c.HTTPDefaults.Format = &s
+	}
+	if c.HTTPDefaults.Criticality == nil {
+		c.HTTPDefaults.Criticality = &bf
+	}
+	if c.HTTPDefaults.Timeout == nil {
+		t := defaultHTTPTimeout
+		c.HTTPDefaults.Timeout = &t
+	}
+	if c.HTTPDefaults.UnsafeTLS == nil {
+		c.HTTPDefaults.UnsafeTLS = &bf
+	}
+	if c.HTTPDefaults.DisableKeepAlives == nil {
+		c.HTTPDefaults.DisableKeepAlives = &bf
+	}
+	if c.HTTPDefaults.BufferedWrites == nil {
+		c.HTTPDefaults.BufferedWrites = &bt
+	}
+	if c.HTTPDefaults.MaxBufferSize == 0 {
+		c.HTTPDefaults.MaxBufferSize = defaultMaxBufferSize
+	}
+	c.inheritCommonDefaults(&c.HTTPDefaults.CommonSinkConfig, &c.FileDefaults.CommonSinkConfig)
+
 	// Validate and fill in defaults for file sinks.
 	for prefix, fc := range c.Sinks.FileGroups {
 		if fc == nil {
@@ -84,6 +139,43 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		}
 	}
 
+	// Validate and fill in defaults for fluent sinks. We iterate in
+	// sorted order so that the errors are reported deterministically.
+	fluentServerNames := make([]string, 0, len(c.Sinks.FluentServers))
+	for serverName := range c.Sinks.FluentServers {
+		fluentServerNames = append(fluentServerNames, serverName)
+	}
+	sort.Strings(fluentServerNames)
+	for _, serverName := range fluentServerNames {
+		fc := c.Sinks.FluentServers[serverName]
+		if fc == nil {
+			fc = &FluentConfig{}
+			c.Sinks.FluentServers[serverName] = fc
+		}
+		fc.serverName = serverName
+		if err := c.validateFluentConfig(fc); err != nil {
+			fmt.Fprintf(&errBuf, "fluent server %q: %v\n", serverName, err)
+		}
+	}
+
+	// Validate and fill in defaults for HTTP sinks.
+	httpServerNames := make([]string, 0, len(c.Sinks.HTTPServers))
+	for serverName := range c.Sinks.HTTPServers {
+		httpServerNames = append(httpServerNames, serverName)
+	}
+	sort.Strings(httpServerNames)
+	for _, serverName := range httpServerNames {
+		hc := c.Sinks.HTTPServers[serverName]
+		if hc == nil {
+			hc = &HTTPConfig{}
+			c.Sinks.HTTPServers[serverName] = hc
+		}
+		hc.serverName = serverName
+		if err := c.validateHTTPConfig(hc); err != nil {
+			fmt.Fprintf(&errBuf, "http server %q: %v\n", serverName, err)
+		}
+	}
+
 	// Defaults for stderr.
 	c.inheritCommonDefaults(&c.Sinks.Stderr.CommonSinkConfig, &c.FileDefaults.CommonSinkConfig)
 	if c.Sinks.Stderr.Filter == logpb.Severity_UNKNOWN {
@@ -199,6 +291,25 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 	sort.Strings(fileGroupNames)
 	c.Sinks.sortedFileGroupNames = fileGroupNames
 
+	// Elide the network sinks with severity set to NONE. Unlike file
+	// sinks, the same channel can be sent to multiple network sinks.
+	c.Sinks.sortedFluentServerNames = nil
+	for _, serverName := range fluentServerNames {
+		if c.Sinks.FluentServers[serverName].Filter == logpb.Severity_NONE {
+			delete(c.Sinks.FluentServers, serverName)
+		} else {
+			c.Sinks.sortedFluentServerNames = append(c.Sinks.sortedFluentServerNames, serverName)
+		}
+	}
+	c.Sinks.sortedHTTPServerNames = nil
+	for _, serverName := range httpServerNames {
+		if c.Sinks.HTTPServers[serverName].Filter == logpb.Severity_NONE {
+			delete(c.Sinks.HTTPServers, serverName)
+		} else {
+			c.Sinks.sortedHTTPServerNames = append(c.Sinks.sortedHTTPServerNames, serverName)
+		}
+	}
+
 	return nil
 }
 
@@ -270,6 +381,121 @@ func (c *Config) validateFileConfig(fc *FileConfig, defaultLogDir *string) error
 	return nil
 }
 
+func (c *Config) validateFluentConfig(fc *FluentConfig) error {
+	c.inheritCommonDefaults(&fc.CommonSinkConfig, &c.FluentDefaults.CommonSinkConfig)
+
+	// Inherit fluent-specific defaults.
+	if fc.BufferedWrites == nil {
+		fc.BufferedWrites = c.FluentDefaults.BufferedWrites
+	}
+	if fc.MaxBufferSize == nil {
+		fc.MaxBufferSize = &c.FluentDefaults.MaxBufferSize
+	}
+
+	if len(fc.Channels.Channels) == 0 {
+		return errors.New("no channel selected")
+	}
+	fc.Channels.Sort()
+
+	fc.Net = strings.ToLower(strings.TrimSpace(fc.Net))
+	switch fc.Net {
+	case "":
+		fc.Net = "tcp"
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix":
+	default:
+		return errors.Newf("unknown protocol: %q", fc.Net)
+	}
+	fc.Address = strings.TrimSpace(fc.Address)
+	if fc.Address == "" {
+		return errors.New("address cannot be empty")
+	}
+
+	switch fc.Mode {
+	case "":
+		fc.Mode = FluentModeJSONLines
+	case FluentModeJSONLines:
+	case FluentModeForward:
+		// The Fluent Forward protocol is only defined over stream
+		// transports, and identifies events using the tag produced by
+		// the json-fluent formats.
+		if strings.HasPrefix(fc.Net, "udp") {
+			return errors.Newf("mode %q cannot be used with protocol %q", fc.Mode, fc.Net)
+		}
+		if !strings.HasPrefix(*fc.Format, "json-fluent") {
+			return errors.Newf("mode %q requires a json-fluent format, found %q", fc.Mode, *fc.Format)
+		}
+	default:
+		return errors.Newf("unknown mode: %q", fc.Mode)
+	}
+
+	// Apply the auditable flag if set.
+	if *fc.Auditable {
+		bf, bt := false, true
+		fc.BufferedWrites = &bf
+		fc.Criticality = &bt
+	}
+	fc.Auditable = nil
+
+	if *fc.BufferedWrites && *fc.MaxBufferSize == 0 {
+		return errors.New("max-buffer-size cannot be zero with buffered-writes")
+	}
+	return nil
+}
+
+func (c *Config) validateHTTPConfig(hc *HTTPConfig) error {
+	c.inheritCommonDefaults(&hc.CommonSinkConfig, &c.HTTPDefaults.CommonSinkConfig)
+
+	// Inherit HTTP-specific defaults.
+	if hc.Timeout == nil {
+		hc.Timeout = c.HTTPDefaults.Timeout
+	}
+	if hc.UnsafeTLS == nil {
+		hc.UnsafeTLS = c.HTTPDefaults.UnsafeTLS
+	}
+	if hc.DisableKeepAlives == nil {
+		hc.DisableKeepAlives = c.HTTPDefaults.DisableKeepAlives
+	}
+	if hc.BufferedWrites == nil {
+		hc.BufferedWrites = c.HTTPDefaults.BufferedWrites
+	}
+	if hc.MaxBufferSize == nil {
+		hc.MaxBufferSize = &c.HTTPDefaults.MaxBufferSize
+	}
+
+	if len(hc.Channels.Channels) == 0 {
+		return errors.New("no channel selected")
+	}
+	hc.Channels.Sort()
+
+	hc.Address = strings.TrimSpace(hc.Address)
+	if hc.Address == "" {
+		return errors.New("address cannot be empty")
+	}
+	u, err := url.Parse(hc.Address)
+	if err != nil {
+		return errors.Wrap(err, "invalid address")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Newf("address must use the http or https scheme: %q", hc.Address)
+	}
+	if *hc.Timeout < 0 {
+		return errors.Newf("timeout cannot be negative: %s", *hc.Timeout)
+	}
+
+	// Apply the auditable flag if set.
+	if *hc.Auditable {
+		bf, bt := false, true
+		hc.BufferedWrites = &bf
+		hc.Criticality = &bt
+	}
+	hc.Auditable = nil
+
+	if *hc.BufferedWrites && *hc.MaxBufferSize == 0 {
+		return errors.New("max-buffer-size cannot be zero with buffered-writes")
+	}
+	return nil
+}
+
 func normalizeDir(dir **string) error {
 	if *dir == nil {
 		return nil
@@ -49,3 +49,6 @@ type logSink interface {
 
 var _ logSink = (*stderrSink)(nil)
 var _ logSink = (*fileSink)(nil)
+var _ logSink = (*fluentSink)(nil)
+var _ logSink = (*httpSink)(nil)
+var _ logSink = (*bufferedSink)(nil)
@@ -0,0 +1,135 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package trigram implements the trigram operations of the pg_trgm Postgres
+// extension.
+//
+// A string is split into words, which are the maximal sequences of letters and
+// digits in the string. Each word is lowercased and padded with two spaces at
+// the front and one space at the back, and the trigrams of the string are the
+// sequences of three consecutive characters of all of its padded words.
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MakeTrigrams returns the sorted and de-duplicated trigrams of the input
+// string. If pad is false, the words of the string are not padded, which
+// yields only the trigrams that are contained in each word. Unpadded trigrams
+// are useful to search for strings that contain a fragment of a word.
+func MakeTrigrams(s string, pad bool) []string {
+	trigrams := appendTrigrams(nil /* trigrams */, s, pad)
+	if len(trigrams) == 0 {
+		return nil
+	}
+	sort.Strings(trigrams)
+	n := 1
+	for i := 1; i < len(trigrams); i++ {
+		if trigrams[i] != trigrams[n-1] {
+			trigrams[n] = trigrams[i]
+			n++
+		}
+	}
+	return trigrams[:n]
+}
+
+// appendTrigrams appends the trigrams of all words of s to trigrams, in the
+// order in which they appear in s.
+func appendTrigrams(trigrams []string, s string, pad bool) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune(word)
+		if pad {
+			runes = append([]rune{' ', ' '}, runes...)
+			runes = append(runes, ' ')
+		}
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams = append(trigrams, string(runes[i:i+3]))
+		}
+	}
+	return trigrams
+}
+
+// Similarity returns the similarity of the two strings, which is the number of
+// trigrams that they share divided by the number of distinct trigrams of both
+// strings. The similarity ranges from 0 (no shared trigrams) to 1 (the same
+// set of trigrams).
+func Similarity(l, r string) float64 {
+	lTrigrams, rTrigrams := MakeTrigrams(l, true /* pad */), MakeTrigrams(r, true /* pad */)
+	if len(lTrigrams) == 0 || len(rTrigrams) == 0 {
+		return 0
+	}
+	// Both slices are sorted, so the shared trigrams can be counted with a
+	// merge.
+	shared := 0
+	for i, j := 0, 0; i < len(lTrigrams) && j < len(rTrigrams); {
+		switch {
+		case lTrigrams[i] < rTrigrams[j]:
+			i++
+		case lTrigrams[i] > rTrigrams[j]:
+			j++
+		default:
+			shared++
+			i++
+			j++
+		}
+	}
+	return float64(shared) / float64(len(lTrigrams)+len(rTrigrams)-shared)
+}
+
+// WordSimilarity returns the greatest similarity between the set of trigrams
+// of l and any continuous extent of the ordered trigrams of r. It is high if l
+// is similar to a word or a sequence of words within r, regardless of the
+// length of r.
+func WordSimilarity(l, r string) float64 {
+	lTrigrams := MakeTrigrams(l, true /* pad */)
+	rTrigrams := appendTrigrams(nil /* trigrams */, r, true /* pad */)
+	if len(lTrigrams) == 0 || len(rTrigrams) == 0 {
+		return 0
+	}
+	lSet := make(map[string]struct{}, len(lTrigrams))
+	for _, t := range lTrigrams {
+		lSet[t] = struct{}{}
+	}
+	best := 0.0
+	extent := make(map[string]struct{}, len(rTrigrams))
+	for i := range rTrigrams {
+		// The best extents start and end with a trigram of l, since any other
+		// trigram at their ends only lowers the similarity.
+		if _, ok := lSet[rTrigrams[i]]; !ok {
+			continue
+		}
+		for k := range extent {
+			delete(extent, k)
+		}
+		shared := 0
+		for j := i; j < len(rTrigrams); j++ {
+			t := rTrigrams[j]
+			if _, ok := extent[t]; ok {
+				continue
+			}
+			extent[t] = struct{}{}
+			if _, ok := lSet[t]; !ok {
+				continue
+			}
+			shared++
+			sim := float64(shared) / float64(len(lTrigrams)+len(extent)-shared)
+			if sim > best {
+				best = sim
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	tt := []struct {
+		Source   string
+		Pad      bool
+		Expected []string
+	}{
+		{
+			Source:   "",
+			Pad:      true,
+			Expected: nil,
+		},
+		{
+			Source:   "a",
+			Pad:      true,
+			Expected: []string{"  a", " a "},
+		},
+		{
+			Source:   "a",
+			Pad:      false,
+			Expected: nil,
+		},
+		{
+			Source:   "Cat",
+			Pad:      true,
+			Expected: []string{"  c", " ca", "at ", "cat"},
+		},
+		{
+			Source:   "foo|bar",
+			Pad:      false,
+			Expected: []string{"bar", "foo"},
+		},
+		{
+			Source:   "banana",
+			Pad:      false,
+			Expected: []string{"ana", "ban", "nan"},
+		},
+		{
+			Source:   "Größe 42",
+			Pad:      true,
+			Expected: []string{"  4", "  g", " 42", " gr", "42 ", "grö", "röß", "ße ", "öße"},
+		},
+	}
+
+	for _, tc := range tt {
+		got := MakeTrigrams(tc.Source, tc.Pad)
+		if !reflect.DeepEqual(tc.Expected, got) {
+			t.Errorf("MakeTrigrams(%q, %t): expected %q, got %q", tc.Source, tc.Pad, tc.Expected, got)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tt := []struct {
+		Left     string
+		Right    string
+		Expected float64
+	}{
+		{Left: "", Right: "", Expected: 0},
+		{Left: "word", Right: "", Expected: 0},
+		{Left: "word", Right: "WORD", Expected: 1},
+		{Left: "word", Right: "two words", Expected: 0.363636},
+		{Left: "abc", Right: "xyz", Expected: 0},
+	}
+
+	for _, tc := range tt {
+		got := Similarity(tc.Left, tc.Right)
+		if math.Abs(tc.Expected-got) > 1e-6 {
+			t.Errorf("Similarity(%q, %q): expected %f, got %f", tc.Left, tc.Right, tc.Expected, got)
+		}
+	}
+}
+
+func TestWordSimilarity(t *testing.T) {
+	tt := []struct {
+		Left     string
+		Right    string
+		Expected float64
+	}{
+		{Left: "", Right: "word", Expected: 0},
+		{Left: "word", Right: "two words", Expected: 0.8},
+		{Left: "word", Right: "word", Expected: 1},
+		{Left: "abc", Right: "xyz", Expected: 0},
+	}
+
+	for _, tc := range tt {
+		got := WordSimilarity(tc.Left, tc.Right)
+		if math.Abs(tc.Expected-got) > 1e-6 {
+			t.Errorf("WordSimilarity(%q, %q): expected %f, got %f", tc.Left, tc.Right, tc.Expected, got)
+		}
+	}
+}